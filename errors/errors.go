@@ -101,6 +101,18 @@ var (
 	ErrRegistryNoContent                = errors.New("could not find a Content that matches localRepo")
 	ErrSyncReferrerNotFound             = errors.New("couldn't find upstream referrer")
 	ErrImageLintAnnotations             = errors.New("lint checks failed")
+	ErrImageLintMaxLayers               = errors.New("image has more layers than allowed")
+	ErrImageLintMaxSize                 = errors.New("image is bigger than allowed")
+	ErrImageLintArchitectures           = errors.New("image index is missing required architectures")
+	ErrImageLintRootUser                = errors.New("image runs as root")
+	ErrImageLintMediaType               = errors.New("image uses a banned media type")
+	ErrImageLintLabels                  = errors.New("image config is missing required labels")
+	ErrImageLintBaseImage               = errors.New("image is built from a forbidden base image")
+	ErrQuotaExceeded                    = errors.New("storage quota exceeded")
+	ErrImmutableTag                     = errors.New("tag is immutable")
+	ErrAdmissionDenied                  = errors.New("manifest push denied by admission webhook")
+	ErrImageNotTrusted                  = errors.New("manifest is not signed by a trusted key or certificate")
+	ErrAdmissionWebhookFailed           = errors.New("admission webhook did not return a valid review")
 	ErrParsingAuthHeader                = errors.New("failed parsing authorization header")
 	ErrBadType                          = errors.New("invalid type")
 	ErrParsingHTTPHeader                = errors.New("invalid HTTP header")
@@ -180,10 +192,41 @@ var (
 	ErrInvalidBearerToken               = errors.New("invalid bearer token given")
 	ErrInsufficientScope                = errors.New("bearer token does not have sufficient scope")
 	ErrCouldNotLoadPublicKey            = errors.New("failed to load public key")
+	ErrCouldNotLoadPrivateKey           = errors.New("failed to load private key")
+	ErrUnsupportedSigningKey            = errors.New("unsupported token signing key")
+	ErrInvalidTokenScope                = errors.New("invalid token scope")
+	ErrUnexpectedRateLimitReply         = errors.New("unexpected rate limit reply from redis")
 	ErrEventTypeEmpty                   = errors.New("event type empty")
 	ErrEventSinkIsNil                   = errors.New("event sink is nil")
 	ErrUnsupportedEventSink             = errors.New("event sink is not supported")
 	ErrInvalidEventSinkType             = errors.New("invalid sink type")
 	ErrEventSinkAddressEmpty            = errors.New("address field cannot be empty")
+	ErrEventSinkChannelEmpty            = errors.New("channel field cannot be empty")
 	ErrCouldNotCreateHTTPEventTransport = errors.New("default transport is not *http.Transport")
+	ErrUnsupportedEventDelivery         = errors.New("event delivery guarantee is not supported")
+	ErrInvalidEventSinkFilter           = errors.New("invalid event type or repository pattern in event sink filter")
+	ErrEventNotAcknowledged             = errors.New("event was not acknowledged by the sink")
+	ErrClusterMemberPush                = errors.New("failed to push content to cluster member")
+	ErrRepoChangedDuringRebalance       = errors.New("repository changed while being moved to its cluster owner")
+	ErrBadJobPriority                   = errors.New("invalid job priority")
+	ErrAdminJobFailed                   = errors.New("admin job failed")
+	ErrSchedulerQueueFull               = errors.New("scheduler task queue is full")
+	ErrSchedulerShutdown                = errors.New("scheduler is shutting down")
+	ErrBadTaskPriority                  = errors.New("invalid task priority")
+	ErrEventOutboxDisabled              = errors.New("event outbox is not enabled")
+	ErrEventNotDeadLettered             = errors.New("event is not dead-lettered")
+	ErrEventNotFound                    = errors.New("event not found in the outbox")
+	ErrInvalidSbom                      = errors.New("invalid spdx or cyclonedx document")
+	ErrSbomNotFound                     = errors.New("no sbom found for image")
+	ErrSbomPackagesNotFound             = errors.New("sbom packages not found")
+	ErrSbomPackagesTableNotSet          = errors.New("sbom packages table name is not configured")
+	ErrCVEScanResultNotFound            = errors.New("cve scan result not found")
+	ErrCVEScanResultsTableNotSet        = errors.New("cve scan results table name is not configured")
+	ErrRobotAccountNotFound             = errors.New("robot account not found")
+	ErrRobotAccountAlreadyExists        = errors.New("robot account already exists")
+	ErrRobotAccountsTableNotSet         = errors.New("robot accounts table name is not configured")
+	ErrAccessControlPoliciesNotFound    = errors.New("access control policies version not found")
+	ErrAccessControlPoliciesConflict    = errors.New("access control policies version already exists")
+	ErrAccessControlPoliciesTableNotSet = errors.New("access control policies table name is not configured")
+	ErrBadExpirationDate                = errors.New("expiration date is in the past")
 )
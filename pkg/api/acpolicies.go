@@ -0,0 +1,524 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	"github.com/gorilla/mux"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+	"zotregistry.dev/zot/pkg/scheduler"
+)
+
+const defaultAccessControlReloadInterval = 10 * time.Second
+
+// AccessControlPolicies are the access control policies managed through the api, they are written the same way
+// as the repositories, adminPolicy and groups of the accessControl section of the config file.
+type AccessControlPolicies struct {
+	Repositories config.Repositories `json:"repositories"`
+	AdminPolicy  config.Policy       `json:"adminPolicy"`
+	Groups       config.Groups       `json:"groups"`
+}
+
+type AccessControlPoliciesPayload struct {
+	Policies AccessControlPolicies `json:"policies"`
+	Comment  string                `json:"comment,omitempty"`
+	// if set, the policies are only updated if it's still the current version, protecting from concurrent updates
+	BaseVersion int `json:"baseVersion,omitempty"`
+}
+
+type AccessControlPoliciesInfo struct {
+	Version   int                   `json:"version"`
+	Policies  AccessControlPolicies `json:"policies"`
+	CreatedBy string                `json:"createdBy"`
+	CreatedAt time.Time             `json:"createdAt"`
+	Comment   string                `json:"comment"`
+}
+
+type AccessControlPoliciesHistory struct {
+	// oldest version first
+	Versions []AccessControlPoliciesInfo `json:"versions"`
+}
+
+// accessControlPolicies tracks the version of the policies stored in MetaDB which is applied
+// to the access control config.
+type accessControlPolicies struct {
+	lock     sync.Mutex
+	version  int
+	policies AccessControlPolicies
+}
+
+func newAccessControlPoliciesInfo(stored mTypes.AccessControlPolicies) (AccessControlPoliciesInfo, error) {
+	info := AccessControlPoliciesInfo{
+		Version:   stored.Version,
+		CreatedBy: stored.CreatedBy,
+		CreatedAt: stored.CreatedAt,
+		Comment:   stored.Comment,
+	}
+
+	err := json.Unmarshal(stored.Policies, &info.Policies)
+
+	return info, err
+}
+
+// validateAccessControlPolicies rejects invalid glob patterns, unknown actions
+// and policies which would lock the admins out.
+func validateAccessControlPolicies(policies AccessControlPolicies) error {
+	for pattern, policyGroup := range policies.Repositories {
+		if !glob.ValidatePattern(pattern) {
+			return fmt.Errorf("%w: %s", glob.ErrBadPattern, pattern)
+		}
+
+		actionLists := [][]string{policyGroup.DefaultPolicy, policyGroup.AnonymousPolicy}
+		for _, policy := range policyGroup.Policies {
+			actionLists = append(actionLists, policy.Actions)
+		}
+
+		for _, actions := range actionLists {
+			if err := validateActions(actions); err != nil {
+				return fmt.Errorf("%w in the policies of %s", err, pattern)
+			}
+		}
+	}
+
+	if err := validateActions(policies.AdminPolicy.Actions); err != nil {
+		return fmt.Errorf("%w in the admin policy", err)
+	}
+
+	if len(policies.AdminPolicy.Users) == 0 && len(policies.AdminPolicy.Groups) == 0 {
+		return fmt.Errorf("%w: the admin policy has no users nor groups", zerr.ErrBadConfig)
+	}
+
+	return nil
+}
+
+func validateActions(actions []string) error {
+	for _, action := range actions {
+		if !slices.Contains(methodActions, action) && action != constants.DetectManifestCollisionPermission &&
+			action != constants.OverrideImmutableTagsPermission {
+			return fmt.Errorf("%w: unknown action %q", zerr.ErrBadConfig, action)
+		}
+	}
+
+	return nil
+}
+
+// initAccessControlPolicies applies the latest policies stored in MetaDB,
+// MetaDB is seeded with the policies of the config file the first time.
+func (c *Controller) initAccessControlPolicies() error {
+	if !c.Config.IsAccessControlInMetaDB() {
+		return nil
+	}
+
+	c.acPolicies = &accessControlPolicies{}
+
+	stored, err := c.MetaDB.GetAccessControlPolicies(0)
+	if errors.Is(err, zerr.ErrAccessControlPoliciesNotFound) {
+		stored, err = c.seedAccessControlPolicies()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.applyAccessControlPolicies(stored)
+}
+
+func (c *Controller) seedAccessControlPolicies() (mTypes.AccessControlPolicies, error) {
+	accessControl := c.Config.HTTP.AccessControl
+
+	policies, err := json.Marshal(AccessControlPolicies{
+		Repositories: accessControl.Repositories,
+		AdminPolicy:  accessControl.AdminPolicy,
+		Groups:       accessControl.Groups,
+	})
+	if err != nil {
+		return mTypes.AccessControlPolicies{}, err
+	}
+
+	stored := mTypes.AccessControlPolicies{
+		Version:   1,
+		Policies:  policies,
+		CreatedAt: time.Now(),
+		Comment:   "seeded from the config file",
+	}
+
+	err = c.MetaDB.AddAccessControlPolicies(stored)
+	if errors.Is(err, zerr.ErrAccessControlPoliciesConflict) {
+		// another instance sharing MetaDB seeded it first
+		return c.MetaDB.GetAccessControlPolicies(0)
+	}
+
+	if err != nil {
+		return mTypes.AccessControlPolicies{}, err
+	}
+
+	c.Log.Info().Msg("seeded the access control policies stored in metaDB from the config file")
+
+	return stored, nil
+}
+
+// applyAccessControlPolicies replaces the policies of the access control config, unless a newer version
+// is already applied. The requests which are being authorized keep using the previous config.
+func (c *Controller) applyAccessControlPolicies(stored mTypes.AccessControlPolicies) error {
+	c.acPolicies.lock.Lock()
+	defer c.acPolicies.lock.Unlock()
+
+	if stored.Version <= c.acPolicies.version {
+		return nil
+	}
+
+	var policies AccessControlPolicies
+
+	if err := json.Unmarshal(stored.Policies, &policies); err != nil {
+		return err
+	}
+
+	c.acPolicies.version = stored.Version
+	c.acPolicies.policies = policies
+
+	c.setAccessControlConfig(c.AccessControlConfig())
+
+	c.Log.Info().Int("version", stored.Version).Str("createdBy", stored.CreatedBy).
+		Msg("applied access control policies")
+
+	return nil
+}
+
+// setAccessControlConfig replaces the access control config with a copy of accessControl which uses the applied
+// policies, and keeps the metaDB settings, changing them needs a restart. The caller holds the lock.
+func (c *Controller) setAccessControlConfig(accessControl *config.AccessControlConfig) {
+	newAccessControl := config.AccessControlConfig{}
+
+	if accessControl != nil {
+		newAccessControl = *accessControl
+	}
+
+	newAccessControl.Repositories = c.acPolicies.policies.Repositories
+	newAccessControl.AdminPolicy = c.acPolicies.policies.AdminPolicy
+	newAccessControl.Groups = c.acPolicies.policies.Groups
+	newAccessControl.MetaDB = c.Config.HTTP.AccessControl.MetaDB
+
+	c.accessControlConfig.Store(&newAccessControl)
+}
+
+// AccessControlPoliciesVersion returns the version of the access control policies stored in MetaDB
+// which is applied, 0 if the policies are not stored in MetaDB.
+func (c *Controller) AccessControlPoliciesVersion() int {
+	if c.acPolicies == nil {
+		return 0
+	}
+
+	c.acPolicies.lock.Lock()
+	defer c.acPolicies.lock.Unlock()
+
+	return c.acPolicies.version
+}
+
+// RunAccessControlPoliciesReload periodically applies the latest access control policies stored in MetaDB,
+// which may have been changed through another instance sharing MetaDB.
+func (c *Controller) RunAccessControlPoliciesReload(sch *scheduler.Scheduler) {
+	if c.acPolicies == nil {
+		return
+	}
+
+	interval := c.Config.HTTP.AccessControl.MetaDB.ReloadInterval
+	if interval == 0 {
+		interval = defaultAccessControlReloadInterval
+	}
+
+	sch.SubmitGenerator(&AccessControlReloadGenerator{ctlr: c}, interval, scheduler.MediumPriority)
+}
+
+// AccessControlReloadGenerator generates a single task reloading the policies each time it runs.
+type AccessControlReloadGenerator struct {
+	ctlr      *Controller
+	generated bool
+	done      bool
+}
+
+func (gen *AccessControlReloadGenerator) Name() string {
+	return "AccessControlReloadGenerator"
+}
+
+func (gen *AccessControlReloadGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil //nolint:nilnil
+	}
+
+	gen.generated = true
+
+	return &accessControlReloadTask{ctlr: gen.ctlr}, nil
+}
+
+func (gen *AccessControlReloadGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *AccessControlReloadGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *AccessControlReloadGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type accessControlReloadTask struct {
+	ctlr *Controller
+}
+
+func (task *accessControlReloadTask) DoWork(ctx context.Context) error {
+	stored, err := task.ctlr.MetaDB.GetAccessControlPolicies(0)
+	if err != nil {
+		return err
+	}
+
+	return task.ctlr.applyAccessControlPolicies(stored)
+}
+
+func (task *accessControlReloadTask) String() string {
+	return fmt.Sprintf("{Name: %s}", task.Name())
+}
+
+func (task *accessControlReloadTask) Name() string {
+	return "AccessControlReloadTask"
+}
+
+// addAccessControlPolicies stores the policies as the version following base and applies them.
+func (rh *RouteHandler) addAccessControlPolicies(response http.ResponseWriter, request *http.Request,
+	base mTypes.AccessControlPolicies, policies AccessControlPolicies, comment string,
+) {
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	policiesBlob, err := json.Marshal(policies)
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	stored := mTypes.AccessControlPolicies{
+		Version:   base.Version + 1,
+		Policies:  policiesBlob,
+		CreatedBy: userAc.GetUsername(),
+		CreatedAt: time.Now(),
+		Comment:   comment,
+	}
+
+	if err := rh.c.MetaDB.AddAccessControlPolicies(stored); err != nil {
+		if errors.Is(err, zerr.ErrAccessControlPoliciesConflict) {
+			response.WriteHeader(http.StatusConflict)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Int("version", stored.Version).Msg("failed to store access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.c.Log.Info().Int("version", stored.Version).Str("createdBy", stored.CreatedBy).
+		Msg("access control policies updated")
+
+	// the other instances apply them when they reload the policies
+	if err := rh.c.applyAccessControlPolicies(stored); err != nil {
+		rh.c.Log.Error().Err(err).Int("version", stored.Version).Msg("failed to apply access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, AccessControlPoliciesInfo{
+		Version:   stored.Version,
+		Policies:  policies,
+		CreatedBy: stored.CreatedBy,
+		CreatedAt: stored.CreatedAt,
+		Comment:   stored.Comment,
+	})
+}
+
+// GetAccessControlPolicies godoc
+// @Summary Get the access control policies
+// @Description Get the current version of the access control policies stored in MetaDB.
+// @Produce json
+// @Success 200 {object} api.AccessControlPoliciesInfo
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/policies  [get].
+func (rh *RouteHandler) GetAccessControlPolicies(response http.ResponseWriter, request *http.Request) {
+	stored, err := rh.c.MetaDB.GetAccessControlPolicies(0)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to get access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	info, err := newAccessControlPoliciesInfo(stored)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Int("version", stored.Version).Msg("failed to read access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, info)
+}
+
+// UpdateAccessControlPolicies godoc
+// @Summary Update the access control policies
+// @Description Store a new version of the access control policies and apply it.
+// @Description The other instances sharing MetaDB apply it when they reload the policies.
+// @Accept  json
+// @Produce json
+// @Param   policies  body  AccessControlPoliciesPayload  true  "repositories, adminPolicy and groups policies"
+// @Success 200 {object} api.AccessControlPoliciesInfo
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "conflict"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/policies  [put].
+func (rh *RouteHandler) UpdateAccessControlPolicies(response http.ResponseWriter, request *http.Request) {
+	var payload AccessControlPoliciesPayload
+
+	if !rh.readJSONPayload(response, request, &payload) {
+		return
+	}
+
+	if err := validateAccessControlPolicies(payload.Policies); err != nil {
+		rh.c.Log.Info().Err(err).Msg("rejected invalid access control policies")
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	base, err := rh.c.MetaDB.GetAccessControlPolicies(0)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to get access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if payload.BaseVersion != 0 && payload.BaseVersion != base.Version {
+		response.WriteHeader(http.StatusConflict)
+
+		return
+	}
+
+	rh.addAccessControlPolicies(response, request, base, payload.Policies, payload.Comment)
+}
+
+// GetAccessControlPoliciesHistory godoc
+// @Summary Get the history of the access control policies
+// @Description Get all the versions of the access control policies stored in MetaDB, oldest first.
+// @Produce json
+// @Success 200 {object} api.AccessControlPoliciesHistory
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/policies/history  [get].
+func (rh *RouteHandler) GetAccessControlPoliciesHistory(response http.ResponseWriter, request *http.Request) {
+	storedHistory, err := rh.c.MetaDB.GetAccessControlPoliciesHistory()
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to get access control policies history")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	history := AccessControlPoliciesHistory{Versions: make([]AccessControlPoliciesInfo, 0, len(storedHistory))}
+
+	for _, stored := range storedHistory {
+		info, err := newAccessControlPoliciesInfo(stored)
+		if err != nil {
+			rh.c.Log.Error().Err(err).Int("version", stored.Version).Msg("failed to read access control policies")
+			response.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		history.Versions = append(history.Versions, info)
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, history)
+}
+
+// RollbackAccessControlPolicies godoc
+// @Summary Roll back the access control policies
+// @Description Store a copy of a previous version of the access control policies as a new version and apply it.
+// @Produce json
+// @Param   version  path  int  true  "version to roll back to"
+// @Success 200 {object} api.AccessControlPoliciesInfo
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "conflict"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/policies/{version}/rollback  [post].
+func (rh *RouteHandler) RollbackAccessControlPolicies(response http.ResponseWriter, request *http.Request) {
+	version, err := strconv.Atoi(mux.Vars(request)["version"])
+	if err != nil || version <= 0 {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	target, err := rh.c.MetaDB.GetAccessControlPolicies(version)
+	if err != nil {
+		if errors.Is(err, zerr.ErrAccessControlPoliciesNotFound) {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Int("version", version).Msg("failed to get access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	targetInfo, err := newAccessControlPoliciesInfo(target)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Int("version", version).Msg("failed to read access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	base, err := rh.c.MetaDB.GetAccessControlPolicies(0)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to get access control policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.addAccessControlPolicies(response, request, base, targetInfo.Policies,
+		fmt.Sprintf("rollback to version %d", version))
+}
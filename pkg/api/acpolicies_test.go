@@ -0,0 +1,391 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	test "zotregistry.dev/zot/pkg/test/common"
+)
+
+func makeAccessControlPoliciesConfig(port, htpasswdPath, adminUser, user string) *config.Config {
+	conf := config.New()
+	conf.HTTP.Port = port
+	conf.HTTP.Auth = &config.AuthConfig{
+		HTPasswd: config.AuthHTPasswd{Path: htpasswdPath},
+	}
+	conf.HTTP.AccessControl = &config.AccessControlConfig{
+		Repositories: config.Repositories{
+			test.AuthorizationAllRepos: config.PolicyGroup{
+				Policies: []config.Policy{
+					{
+						Users:   []string{user},
+						Actions: []string{constants.ReadPermission},
+					},
+				},
+			},
+		},
+		AdminPolicy: config.Policy{
+			Users: []string{adminUser},
+			Actions: []string{
+				constants.ReadPermission, constants.CreatePermission,
+				constants.UpdatePermission, constants.DeletePermission,
+			},
+		},
+		MetaDB: &config.AccessControlMetaDBConfig{Enable: true},
+	}
+
+	return conf
+}
+
+// userCanPushPolicies gives user the create permission on all repositories, on top of read.
+func userCanPushPolicies(adminUser, user string) api.AccessControlPolicies {
+	return api.AccessControlPolicies{
+		Repositories: config.Repositories{
+			test.AuthorizationAllRepos: config.PolicyGroup{
+				Policies: []config.Policy{
+					{
+						Users:   []string{user},
+						Actions: []string{constants.ReadPermission, constants.CreatePermission},
+					},
+				},
+			},
+		},
+		AdminPolicy: config.Policy{
+			Users: []string{adminUser},
+			Actions: []string{
+				constants.ReadPermission, constants.CreatePermission,
+				constants.UpdatePermission, constants.DeletePermission,
+			},
+		},
+	}
+}
+
+func TestAccessControlPoliciesInMetaDB(t *testing.T) {
+	adminUser, adminPassword := "admin", "admin"
+	user, password := "user", "user"
+
+	htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n" +
+		test.GetCredString(user, password))
+	defer os.Remove(htpasswdPath)
+
+	Convey("Make a new controller with access control policies stored in MetaDB", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := makeAccessControlPoliciesConfig(port, htpasswdPath, adminUser, user)
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		policiesURL := baseURL + constants.AccessControlPoliciesPath
+		uploadURL := baseURL + "/v2/repo/blobs/uploads/"
+
+		getPolicies := func() api.AccessControlPoliciesInfo {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(policiesURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var info api.AccessControlPoliciesInfo
+
+			err = json.Unmarshal(resp.Body(), &info)
+			So(err, ShouldBeNil)
+
+			return info
+		}
+
+		getHistory := func() api.AccessControlPoliciesHistory {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(policiesURL + "/history")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var history api.AccessControlPoliciesHistory
+
+			err = json.Unmarshal(resp.Body(), &history)
+			So(err, ShouldBeNil)
+
+			return history
+		}
+
+		updatePolicies := func(payload any) *resty.Response {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).SetBody(payload).Put(policiesURL)
+			So(err, ShouldBeNil)
+
+			return resp
+		}
+
+		userUploadStatus := func() int {
+			resp, err := resty.R().SetBasicAuth(user, password).Post(uploadURL)
+			So(err, ShouldBeNil)
+
+			return resp.StatusCode()
+		}
+
+		// the policies of the config file are the first version
+		info := getPolicies()
+		So(info.Version, ShouldEqual, 1)
+		So(info.Comment, ShouldEqual, "seeded from the config file")
+		So(info.Policies.AdminPolicy.Users, ShouldResemble, []string{adminUser})
+		So(info.Policies.Repositories[test.AuthorizationAllRepos].Policies[0].Users, ShouldResemble, []string{user})
+		So(ctlr.AccessControlPoliciesVersion(), ShouldEqual, 1)
+
+		So(userUploadStatus(), ShouldEqual, http.StatusForbidden)
+
+		Convey("Only admins can manage the policies", func() {
+			resp, err := resty.R().SetBasicAuth(user, password).Get(policiesURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().SetBasicAuth(user, password).
+				SetBody(api.AccessControlPoliciesPayload{Policies: userCanPushPolicies(adminUser, user)}).
+				Put(policiesURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().Get(policiesURL + "/history")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(user, password).Post(policiesURL + "/1/rollback")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Invalid policies are rejected", func() {
+			resp := updatePolicies("not json")
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			policies := userCanPushPolicies(adminUser, user)
+			policies.Repositories["[a-"] = config.PolicyGroup{DefaultPolicy: []string{constants.ReadPermission}}
+
+			resp = updatePolicies(api.AccessControlPoliciesPayload{Policies: policies})
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			// the admins would be locked out
+			policies = userCanPushPolicies(adminUser, user)
+			policies.AdminPolicy = config.Policy{}
+
+			resp = updatePolicies(api.AccessControlPoliciesPayload{Policies: policies})
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			// misspelled actions would silently grant nothing
+			policies = userCanPushPolicies(adminUser, user)
+			policies.Repositories[test.AuthorizationAllRepos].Policies[0].Actions = []string{"reed"}
+
+			resp = updatePolicies(api.AccessControlPoliciesPayload{Policies: policies})
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			policies = userCanPushPolicies(adminUser, user)
+			policies.Repositories["public/**"] = config.PolicyGroup{AnonymousPolicy: []string{"pull"}}
+
+			resp = updatePolicies(api.AccessControlPoliciesPayload{Policies: policies})
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			policies = userCanPushPolicies(adminUser, user)
+			policies.AdminPolicy.Actions = append(policies.AdminPolicy.Actions, "admin")
+
+			resp = updatePolicies(api.AccessControlPoliciesPayload{Policies: policies})
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			So(ctlr.AccessControlPoliciesVersion(), ShouldEqual, 1)
+
+			// based on another version
+			resp = updatePolicies(api.AccessControlPoliciesPayload{
+				Policies:    userCanPushPolicies(adminUser, user),
+				BaseVersion: 5,
+			})
+			So(resp.StatusCode(), ShouldEqual, http.StatusConflict)
+
+			So(len(getHistory().Versions), ShouldEqual, 1)
+			So(ctlr.AccessControlPoliciesVersion(), ShouldEqual, 1)
+		})
+
+		Convey("Update and roll back the policies", func() {
+			resp := updatePolicies(api.AccessControlPoliciesPayload{
+				Policies:    userCanPushPolicies(adminUser, user),
+				Comment:     "user pushes images",
+				BaseVersion: 1,
+			})
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err := json.Unmarshal(resp.Body(), &info)
+			So(err, ShouldBeNil)
+			So(info.Version, ShouldEqual, 2)
+			So(info.CreatedBy, ShouldEqual, adminUser)
+			So(info.Comment, ShouldEqual, "user pushes images")
+
+			// applied right away
+			So(ctlr.AccessControlPoliciesVersion(), ShouldEqual, 2)
+			So(userUploadStatus(), ShouldEqual, http.StatusAccepted)
+
+			info = getPolicies()
+			So(info.Version, ShouldEqual, 2)
+			So(info.Policies.Repositories[test.AuthorizationAllRepos].Policies[0].Actions,
+				ShouldContain, constants.CreatePermission)
+
+			// the update is based on an outdated version
+			resp = updatePolicies(api.AccessControlPoliciesPayload{
+				Policies:    userCanPushPolicies(adminUser, user),
+				BaseVersion: 1,
+			})
+			So(resp.StatusCode(), ShouldEqual, http.StatusConflict)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(policiesURL + "/1/rollback")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &info)
+			So(err, ShouldBeNil)
+			So(info.Version, ShouldEqual, 3)
+			So(info.Comment, ShouldEqual, "rollback to version 1")
+
+			So(ctlr.AccessControlPoliciesVersion(), ShouldEqual, 3)
+			So(userUploadStatus(), ShouldEqual, http.StatusForbidden)
+
+			history := getHistory()
+			So(len(history.Versions), ShouldEqual, 3)
+
+			for i, version := range history.Versions {
+				So(version.Version, ShouldEqual, i+1)
+			}
+
+			So(history.Versions[2].Policies, ShouldResemble, history.Versions[0].Policies)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(policiesURL + "/9/rollback")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(policiesURL + "/latest/rollback")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+	})
+
+	Convey("Reloading the config file keeps the policies stored in MetaDB", t, func() {
+		conf := makeAccessControlPoliciesConfig(test.GetFreePort(), htpasswdPath, adminUser, user)
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		err := ctlr.Init()
+		So(err, ShouldBeNil)
+
+		newConf := makeAccessControlPoliciesConfig(conf.HTTP.Port, htpasswdPath, adminUser, user)
+		newConf.HTTP.AccessControl.Repositories = config.Repositories{}
+		newConf.HTTP.AccessControl.Metrics = config.Metrics{Users: []string{adminUser}}
+		newConf.HTTP.AccessControl.MetaDB = nil
+		newConf.Storage.RootDirectory = ctlr.Config.Storage.RootDirectory
+
+		ctlr.LoadNewConfig(newConf)
+
+		accessControl := ctlr.AccessControlConfig()
+		So(accessControl.Repositories, ShouldContainKey, test.AuthorizationAllRepos)
+		So(accessControl.Metrics.Users, ShouldResemble, []string{adminUser})
+		So(accessControl.MetaDB, ShouldNotBeNil)
+		So(ctlr.Config.IsAccessControlInMetaDB(), ShouldBeTrue)
+	})
+
+	Convey("Instances sharing MetaDB apply the policies updated through the others", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		makeController := func() (*api.Controller, string) {
+			port := test.GetFreePort()
+
+			conf := makeAccessControlPoliciesConfig(port, htpasswdPath, adminUser, user)
+			conf.HTTP.AccessControl.MetaDB.ReloadInterval = time.Second
+			conf.Storage.RemoteCache = true
+			conf.Storage.CacheDriver = map[string]interface{}{
+				"name": "redis",
+				"url":  "redis://" + miniRedis.Addr(),
+			}
+
+			ctlr := api.NewController(conf)
+			ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+			return ctlr, port
+		}
+
+		ctlr1, port1 := makeController()
+
+		cm1 := test.NewControllerManager(ctlr1)
+		cm1.StartAndWait(port1)
+
+		defer cm1.StopServer()
+
+		ctlr2, port2 := makeController()
+
+		cm2 := test.NewControllerManager(ctlr2)
+		cm2.StartAndWait(port2)
+
+		defer cm2.StopServer()
+
+		// only the first instance seeds MetaDB
+		So(ctlr1.AccessControlPoliciesVersion(), ShouldEqual, 1)
+		So(ctlr2.AccessControlPoliciesVersion(), ShouldEqual, 1)
+
+		history, err := ctlr2.MetaDB.GetAccessControlPoliciesHistory()
+		So(err, ShouldBeNil)
+		So(len(history), ShouldEqual, 1)
+
+		uploadURL := test.GetBaseURL(port2) + "/v2/repo/blobs/uploads/"
+
+		resp, err := resty.R().SetBasicAuth(user, password).Post(uploadURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).
+			SetBody(api.AccessControlPoliciesPayload{Policies: userCanPushPolicies(adminUser, user)}).
+			Put(test.GetBaseURL(port1) + constants.AccessControlPoliciesPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		for range 30 {
+			if ctlr2.AccessControlPoliciesVersion() == 2 {
+				break
+			}
+
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		So(ctlr2.AccessControlPoliciesVersion(), ShouldEqual, 2)
+
+		resp, err = resty.R().SetBasicAuth(user, password).Post(uploadURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		// the rollback is applied too
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).
+			Post(fmt.Sprintf("%s%s/1/rollback", test.GetBaseURL(port1), constants.AccessControlPoliciesPath))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		for range 30 {
+			if ctlr2.AccessControlPoliciesVersion() == 3 {
+				break
+			}
+
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		So(ctlr2.AccessControlPoliciesVersion(), ShouldEqual, 3)
+
+		resp, err = resty.R().SetBasicAuth(user, password).Post(uploadURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
@@ -35,6 +36,7 @@ import (
 	apiErr "zotregistry.dev/zot/pkg/api/errors"
 	zcommon "zotregistry.dev/zot/pkg/common"
 	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
 )
 
@@ -44,9 +46,10 @@ const (
 )
 
 type AuthnMiddleware struct {
-	htpasswd   *HTPasswd
-	ldapClient *LDAPClient
-	log        log.Logger
+	htpasswd     *HTPasswd
+	ldapClient   *LDAPClient
+	tokenService *TokenService
+	log          log.Logger
 }
 
 func AuthHandler(ctlr *Controller) mux.MiddlewareFunc {
@@ -98,6 +101,60 @@ func (amw *AuthnMiddleware) sessionAuthn(ctlr *Controller, userAc *reqCtx.UserAc
 	return true, nil
 }
 
+func (amw *AuthnMiddleware) tokenAuthn(ctlr *Controller, userAc *reqCtx.UserAccessControl,
+	request *http.Request,
+) error {
+	claims, err := amw.tokenService.Verify(request)
+	if err != nil {
+		return err
+	}
+
+	// tokens without subject are issued to anonymous users
+	if claims.Subject == "" {
+		return nil
+	}
+
+	if ctlr.Config.IsRobotAccountsEnabled() && strings.HasPrefix(claims.Subject, constants.RobotAccountPrefix) {
+		robot, err := ctlr.MetaDB.GetRobotAccount(strings.TrimPrefix(claims.Subject, constants.RobotAccountPrefix))
+		if err != nil {
+			if errors.Is(err, zerr.ErrRobotAccountNotFound) {
+				// the robot account was deleted after the token was issued
+				return fmt.Errorf("%w: %w", zerr.ErrInvalidBearerToken, err)
+			}
+
+			ctlr.Log.Err(err).Str("identity", claims.Subject).Msg("failed to get robot account in DB")
+
+			return err
+		}
+
+		setRobotAccessControl(userAc, claims.Subject, robot)
+		userAc.SaveOnRequest(request)
+
+		return nil
+	}
+
+	userAc.SetUsername(claims.Subject)
+
+	// tokens issued to scoped api keys are restricted to the scope of the key
+	if claims.Scope != nil {
+		userAc.SetScope(claims.Scope.Repositories, claims.Scope.Actions)
+	}
+
+	userAc.SaveOnRequest(request)
+
+	groups, err := ctlr.MetaDB.GetUserGroups(request.Context())
+	if err != nil {
+		ctlr.Log.Err(err).Str("identity", claims.Subject).Msg("failed to get user's groups in DB")
+
+		return err
+	}
+
+	userAc.AddGroups(groups)
+	userAc.SaveOnRequest(request)
+
+	return nil
+}
+
 func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAccessControl,
 	response http.ResponseWriter, request *http.Request,
 ) (bool, error) {
@@ -110,14 +167,19 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 		return false, nil
 	}
 
+	// robot accounts are only authenticated by their secret
+	if ctlr.Config.IsRobotAccountsEnabled() && strings.HasPrefix(identity, constants.RobotAccountPrefix) {
+		return amw.robotAuthn(ctlr, userAc, request, identity, passphrase)
+	}
+
 	// first, HTTPPassword authN (which is local)
 	htOk, _ := amw.htpasswd.Authenticate(identity, passphrase)
 	if htOk {
 		// Process request
 		var groups []string
 
-		if ctlr.Config.HTTP.AccessControl != nil {
-			ac := NewAccessController(ctlr.Config)
+		if accessControl := ctlr.AccessControlConfig(); accessControl != nil {
+			ac := NewAccessController(accessControl, ctlr.Log)
 			groups = ac.getUserGroups(identity)
 		}
 
@@ -151,8 +213,8 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 			// Process request
 			var groups []string
 
-			if ctlr.Config.HTTP.AccessControl != nil {
-				ac := NewAccessController(ctlr.Config)
+			if accessControl := ctlr.AccessControlConfig(); accessControl != nil {
+				ac := NewAccessController(accessControl, ctlr.Log)
 				groups = ac.getUserGroups(identity)
 			}
 
@@ -238,6 +300,17 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 			}
 
 			userAc.AddGroups(groups)
+
+			// the key may be restricted to a subset of the permissions of its owner
+			userData, err := ctlr.MetaDB.GetUserData(request.Context())
+			if err != nil {
+				ctlr.Log.Err(err).Str("identity", identity).Msg("failed to get user's api keys in DB")
+
+				return false, err
+			}
+
+			apiKeyDetails := userData.APIKeys[hashedKey]
+			userAc.SetScope(apiKeyDetails.Repositories, apiKeyDetails.Actions)
 			userAc.SaveOnRequest(request)
 
 			return true, nil
@@ -247,6 +320,52 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 	return false, nil
 }
 
+func (amw *AuthnMiddleware) robotAuthn(ctlr *Controller, userAc *reqCtx.UserAccessControl,
+	request *http.Request, identity, passphrase string,
+) (bool, error) {
+	if !strings.HasPrefix(passphrase, constants.RobotSecretsPrefix) {
+		ctlr.Log.Error().Str("identity", identity).Msg("invalid robot account secret format")
+
+		return false, nil
+	}
+
+	robot, err := ctlr.MetaDB.GetRobotAccount(strings.TrimPrefix(identity, constants.RobotAccountPrefix))
+	if err != nil {
+		if errors.Is(err, zerr.ErrRobotAccountNotFound) {
+			ctlr.Log.Info().Err(err).Str("identity", identity).Msg("failed to find robot account in DB")
+
+			return false, nil
+		}
+
+		ctlr.Log.Error().Err(err).Str("identity", identity).Msg("failed to get robot account in DB")
+
+		return false, err
+	}
+
+	hashedSecret := hashUUID(strings.TrimPrefix(passphrase, constants.RobotSecretsPrefix))
+
+	if subtle.ConstantTimeCompare([]byte(hashedSecret), []byte(robot.HashedSecret)) != 1 {
+		return false, nil
+	}
+
+	if robot.IsSecretExpired() {
+		ctlr.Log.Info().Str("identity", identity).Msg("robot account secret expired")
+
+		return false, nil
+	}
+
+	setRobotAccessControl(userAc, identity, robot)
+	userAc.SaveOnRequest(request)
+
+	return true, nil
+}
+
+func setRobotAccessControl(userAc *reqCtx.UserAccessControl, identity string, robot mTypes.RobotAccount) {
+	userAc.SetUsername(identity)
+	userAc.SetIsRobot(true)
+	userAc.SetScope(robot.Repositories, robot.Actions)
+}
+
 func (amw *AuthnMiddleware) tryAuthnHandlers(ctlr *Controller) mux.MiddlewareFunc { //nolint: gocyclo
 	// no password based authN, if neither LDAP nor HTTP BASIC is enabled
 	if !ctlr.Config.IsBasicAuthnEnabled() {
@@ -313,6 +432,18 @@ func (amw *AuthnMiddleware) tryAuthnHandlers(ctlr *Controller) mux.MiddlewareFun
 		}
 	}
 
+	// zot issued bearer tokens
+	if ctlr.Config.IsTokenServiceEnabled() {
+		tokenService, err := NewTokenService(ctlr.Config.HTTP.Auth.TokenService)
+		if err != nil {
+			amw.log.Panic().Err(err).Str("key", ctlr.Config.HTTP.Auth.TokenService.Key).
+				Msg("failed to create token service")
+		}
+
+		ctlr.TokenService = tokenService
+		amw.tokenService = tokenService
+	}
+
 	// openid based authN
 	if ctlr.Config.IsOpenIDAuthEnabled() {
 		ctlr.RelyingParties = make(map[string]rp.RelyingParty)
@@ -340,13 +471,40 @@ func (amw *AuthnMiddleware) tryAuthnHandlers(ctlr *Controller) mux.MiddlewareFun
 			}
 
 			isMgmtRequested := request.RequestURI == constants.FullMgmt
-			allowAnonymous := ctlr.Config.HTTP.AccessControl.AnonymousPolicyExists()
+			allowAnonymous := ctlr.AccessControlConfig().AnonymousPolicyExists()
 
 			// build user access control info
 			userAc := reqCtx.NewUserAccessControl()
 			// if it will not be populated by authn handlers, this represents an anonymous user
 			userAc.SaveOnRequest(request)
 
+			// try zot issued tokens if a bearer token is given, the token endpoint itself needs other credentials
+			isTokenRequested := request.URL.Path == constants.TokenPath
+			if amw.tokenService != nil && hasBearerToken(request) && !isTokenRequested {
+				//nolint: contextcheck
+				err := amw.tokenAuthn(ctlr, userAc, request)
+				if err == nil {
+					next.ServeHTTP(response, request)
+
+					return
+				}
+
+				var challenge *AuthChallengeError
+
+				switch {
+				case errors.As(err, &challenge):
+					// valid token without the needed scope, let the client ask for a new one
+					tokenAuthFail(response, request, challenge, 0)
+				case errors.Is(err, zerr.ErrInvalidBearerToken):
+					ctlr.Log.Debug().Err(err).Msg("bearer token authorization failed")
+					tokenAuthFail(response, request, amw.tokenService.Challenge(request, err), delay)
+				default:
+					response.WriteHeader(http.StatusInternalServerError)
+				}
+
+				return
+			}
+
 			// try basic auth if authorization header is given
 			if !isAuthorizationHeaderEmpty(request) { //nolint: gocritic
 				//nolint: contextcheck
@@ -397,6 +555,13 @@ func (amw *AuthnMiddleware) tryAuthnHandlers(ctlr *Controller) mux.MiddlewareFun
 				return
 			}
 
+			// clients which only speak the token flow are sent to the token endpoint
+			if amw.tokenService != nil && !isTokenRequested && !hasSessionHeader(request) {
+				tokenAuthFail(response, request, amw.tokenService.Challenge(request, zerr.ErrNoBearerToken), delay)
+
+				return
+			}
+
 			authFail(response, request, ctlr.Config.HTTP.Realm, delay)
 		})
 	}
@@ -425,7 +590,7 @@ func bearerAuthHandler(ctlr *Controller) mux.MiddlewareFunc {
 				return
 			}
 
-			acCtrlr := NewAccessController(ctlr.Config)
+			acCtrlr := NewAccessController(ctlr.AccessControlConfig(), ctlr.Log)
 
 			// we want to bypass auth for mgmt route
 			isMgmtRequested := request.RequestURI == constants.FullMgmt
@@ -684,6 +849,20 @@ func authFail(w http.ResponseWriter, r *http.Request, realm string, delay int) {
 	zcommon.WriteJSON(w, http.StatusUnauthorized, apiErr.NewError(apiErr.UNAUTHORIZED))
 }
 
+func tokenAuthFail(w http.ResponseWriter, r *http.Request, challenge *AuthChallengeError, delay int) {
+	if !isAuthorizationHeaderEmpty(r) {
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge.Header())
+	w.Header().Set("Content-Type", "application/json")
+	zcommon.WriteJSON(w, http.StatusUnauthorized, apiErr.NewError(apiErr.UNAUTHORIZED))
+}
+
+func hasBearerToken(request *http.Request) bool {
+	return strings.HasPrefix(strings.ToLower(request.Header.Get("Authorization")), "bearer ")
+}
+
 func isAuthorizationHeaderEmpty(request *http.Request) bool {
 	header := request.Header.Get("Authorization")
 
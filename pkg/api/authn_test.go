@@ -5,8 +5,12 @@ package api_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"net/http/httptest"
@@ -34,6 +38,7 @@ import (
 	"zotregistry.dev/zot/pkg/storage/local"
 	authutils "zotregistry.dev/zot/pkg/test/auth"
 	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
 	"zotregistry.dev/zot/pkg/test/mocks"
 )
 
@@ -930,6 +935,287 @@ func TestAPIKeysGeneratorErrors(t *testing.T) {
 	})
 }
 
+func TestScopedAPIKeys(t *testing.T) {
+	createAPIKey := func(baseURL string, payload api.APIKeyPayload, user, password string) *resty.Response {
+		reqBody, err := json.Marshal(payload)
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetBody(reqBody).SetBasicAuth(user, password).Post(baseURL + constants.APIKeyPath)
+		So(err, ShouldBeNil)
+
+		return resp
+	}
+
+	getAPIKey := func(baseURL string, payload api.APIKeyPayload, user, password string) string {
+		resp := createAPIKey(baseURL, payload, user, password)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var apiKeyResponse apiKeyResponse
+		err := json.Unmarshal(resp.Body(), &apiKeyResponse)
+		So(err, ShouldBeNil)
+		So(apiKeyResponse.Repositories, ShouldResemble, payload.Repositories)
+		So(apiKeyResponse.Actions, ShouldResemble, payload.Actions)
+
+		return apiKeyResponse.APIKey
+	}
+
+	for _, withAccessControl := range []bool{true, false} {
+		Convey(fmt.Sprintf("Scoped api keys, access control enabled: %t", withAccessControl), t, func() {
+			port := test.GetFreePort()
+			baseURL := test.GetBaseURL(port)
+
+			htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("alice", "alicepass") +
+				test.GetCredString("bob", "bobpass"))
+			defer os.Remove(htpasswdPath)
+
+			conf := config.New()
+			conf.HTTP.Port = port
+			conf.HTTP.Auth = &config.AuthConfig{
+				HTPasswd: config.AuthHTPasswd{Path: htpasswdPath},
+				APIKey:   true,
+			}
+
+			if withAccessControl {
+				conf.HTTP.AccessControl = &config.AccessControlConfig{
+					Repositories: config.Repositories{
+						"**": config.PolicyGroup{
+							Policies: []config.Policy{
+								{
+									Users: []string{"alice"},
+									Actions: []string{
+										constants.ReadPermission, constants.CreatePermission,
+										constants.UpdatePermission, constants.DeletePermission,
+									},
+								},
+							},
+						},
+						"private/**": config.PolicyGroup{
+							Policies: []config.Policy{
+								{
+									Users: []string{"alice"},
+									Actions: []string{
+										constants.ReadPermission, constants.CreatePermission,
+										constants.UpdatePermission, constants.DeletePermission,
+									},
+								},
+								{
+									Users:   []string{"bob"},
+									Actions: []string{constants.ReadPermission},
+								},
+							},
+						},
+					},
+					AdminPolicy: config.Policy{
+						Users:   []string{"bob"},
+						Actions: []string{constants.ReadPermission},
+					},
+				}
+			}
+
+			ctlr := api.NewController(conf)
+			ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+			cm := test.NewControllerManager(ctlr)
+			cm.StartAndWait(port)
+
+			defer cm.StopServer()
+
+			img := CreateRandomImage()
+
+			for _, repo := range []string{"ci/app", "ci/other", "private/repo"} {
+				err := UploadImageWithBasicAuth(img, baseURL, repo, "tag", "alice", "alicepass")
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Invalid scopes are rejected", func() {
+				resp := createAPIKey(baseURL, api.APIKeyPayload{Actions: []string{"push"}}, "alice", "alicepass")
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+				resp = createAPIKey(baseURL, api.APIKeyPayload{Repositories: []string{"ci/[app"}}, "alice", "alicepass")
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Push only key for a single repository", func() {
+				apiKey := getAPIKey(baseURL, api.APIKeyPayload{
+					Label:        "ci",
+					Repositories: []string{"ci/app"},
+					Actions:      []string{constants.CreatePermission, constants.UpdatePermission},
+				}, "alice", "alicepass")
+
+				err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "ci/app", "new", "alice", apiKey)
+				So(err, ShouldBeNil)
+
+				// overwriting tags needs update
+				err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "ci/app", "new", "alice", apiKey)
+				So(err, ShouldBeNil)
+
+				err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "ci/other", "new", "alice", apiKey)
+				So(err, ShouldNotBeNil)
+
+				resp, err := resty.R().SetBasicAuth("alice", apiKey).Get(baseURL + "/v2/ci/app/manifests/tag")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+				resp, err = resty.R().SetBasicAuth("alice", apiKey).Delete(baseURL + "/v2/ci/app/manifests/tag")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+				// the owner keeps all its permissions
+				resp, err = resty.R().SetBasicAuth("alice", "alicepass").Get(baseURL + "/v2/ci/other/manifests/tag")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+				// scoped keys can't manage api keys
+				resp = createAPIKey(baseURL, api.APIKeyPayload{Label: "escalate"}, "alice", apiKey)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+				resp, err = resty.R().SetBasicAuth("alice", apiKey).Get(baseURL + constants.APIKeyPath)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+			})
+
+			Convey("Read only key for a set of repositories", func() {
+				apiKey := getAPIKey(baseURL, api.APIKeyPayload{
+					Repositories: []string{"ci/**"},
+					Actions:      []string{constants.ReadPermission},
+				}, "alice", "alicepass")
+
+				for _, repo := range []string{"ci/app", "ci/other"} {
+					resp, err := resty.R().SetBasicAuth("alice", apiKey).Get(baseURL + "/v2/" + repo + "/manifests/tag")
+					So(err, ShouldBeNil)
+					So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+				}
+
+				resp, err := resty.R().SetBasicAuth("alice", apiKey).Get(baseURL + "/v2/private/repo/manifests/tag")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+				err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "ci/app", "new", "alice", apiKey)
+				So(err, ShouldNotBeNil)
+
+				// the catalog only lists the repositories of the key
+				resp, err = resty.R().SetBasicAuth("alice", apiKey).Get(baseURL + "/v2/_catalog")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+				var catalog struct {
+					Repositories []string `json:"repositories"`
+				}
+
+				err = json.Unmarshal(resp.Body(), &catalog)
+				So(err, ShouldBeNil)
+				So(catalog.Repositories, ShouldResemble, []string{"ci/app", "ci/other"})
+			})
+
+			if withAccessControl {
+				Convey("Scopes are intersected with the permissions of the owner", func() {
+					apiKey := getAPIKey(baseURL, api.APIKeyPayload{
+						Repositories: []string{"**"},
+						Actions:      []string{constants.ReadPermission, constants.DeletePermission},
+					}, "bob", "bobpass")
+
+					resp, err := resty.R().SetBasicAuth("bob", apiKey).Get(baseURL + "/v2/private/repo/manifests/tag")
+					So(err, ShouldBeNil)
+					So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+					// bob can't delete images, whatever the key allows
+					resp, err = resty.R().SetBasicAuth("bob", apiKey).Delete(baseURL + "/v2/private/repo/manifests/tag")
+					So(err, ShouldBeNil)
+					So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+					// scoped keys of admins can't be used for admin operations
+					resp, err = resty.R().SetBasicAuth("bob", apiKey).Get(baseURL + constants.AdminJobsPath)
+					So(err, ShouldBeNil)
+					So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+					resp, err = resty.R().SetBasicAuth("bob", "bobpass").Get(baseURL + constants.AdminJobsPath)
+					So(err, ShouldBeNil)
+					So(resp.StatusCode(), ShouldNotEqual, http.StatusForbidden)
+				})
+			}
+		})
+	}
+}
+
+func TestScopedAPIKeyTokens(t *testing.T) {
+	Convey("Bearer tokens issued to scoped api keys are restricted to the scope of the key", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("admin", "admin"))
+		defer os.Remove(htpasswdPath)
+
+		signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{Path: htpasswdPath},
+			TokenService: &config.TokenServiceConfig{
+				Key:     writeTokenSigningKey(t, signingKey),
+				Service: "zot-registry",
+			},
+			APIKey: true,
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			AdminPolicy: config.Policy{
+				Users:   []string{"admin"},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		adminToken := getBearerToken(baseURL, "admin", "admin")
+
+		resp, err := resty.R().SetAuthToken(adminToken).
+			SetBody(`{"label": "ci", "repositories": ["ci/**"], "actions": ["read"]}`).
+			Post(baseURL + constants.APIKeyPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var apiKeyResponse apiKeyResponse
+
+		err = json.Unmarshal(resp.Body(), &apiKeyResponse)
+		So(err, ShouldBeNil)
+
+		token := getBearerToken(baseURL, "admin", apiKeyResponse.APIKey, "repository:ci/app:pull")
+
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/v2/ci/app/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		// the token doesn't grant the permissions of the owner which the key doesn't have
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + constants.AdminJobsPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + constants.APIKeyPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetAuthToken(token).SetBody(`{"label": "escalate"}`).Post(baseURL + constants.APIKeyPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		// the tokens of the owner keep all its permissions
+		resp, err = resty.R().SetAuthToken(adminToken).Get(baseURL + constants.AdminJobsPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetAuthToken(adminToken).Get(baseURL + constants.APIKeyPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+}
+
 func TestCookiestoreCleanup(t *testing.T) {
 	log := log.Logger{}
 	metrics := monitoring.NewMetricsServer(true, log)
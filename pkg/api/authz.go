@@ -7,6 +7,7 @@ import (
 	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/gorilla/mux"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	"zotregistry.dev/zot/pkg/common"
@@ -35,23 +36,25 @@ func AuthzFilterFunc(userAc *reqCtx.UserAccessControl) storageTypes.FilterRepoFu
 	}
 }
 
+//nolint:gochecknoglobals
+var methodActions = []string{
+	constants.ReadPermission, constants.CreatePermission, constants.UpdatePermission, constants.DeletePermission,
+}
+
 // AccessController authorizes users to act on resources.
 type AccessController struct {
 	Config *config.AccessControlConfig
 	Log    log.Logger
 }
 
-func NewAccessController(conf *config.Config) *AccessController {
-	if conf.HTTP.AccessControl == nil {
-		return &AccessController{
-			Config: &config.AccessControlConfig{},
-			Log:    log.NewLogger(conf.Log.Level, conf.Log.Output),
-		}
+func NewAccessController(accessControl *config.AccessControlConfig, logger log.Logger) *AccessController {
+	if accessControl == nil {
+		accessControl = &config.AccessControlConfig{}
 	}
 
 	return &AccessController{
-		Config: conf.HTTP.AccessControl,
-		Log:    log.NewLogger(conf.Log.Level, conf.Log.Output),
+		Config: accessControl,
+		Log:    logger,
 	}
 }
 
@@ -100,6 +103,16 @@ func (ac *AccessController) getGlobPatterns(username string, groups []string, ac
 
 // can verifies if a user can do action on repository.
 func (ac *AccessController) can(userAc *reqCtx.UserAccessControl, action, repository string) bool {
+	// robot accounts are granted the method actions of their scope, the policies don't apply to them
+	if userAc.IsRobot() {
+		return common.Contains(methodActions, action) && userAc.IsInScope(action, repository)
+	}
+
+	// the permissions of scoped api keys are intersected with the permissions of their owner
+	if !userAc.IsInScope(action, repository) {
+		return false
+	}
+
 	can := false
 
 	var longestMatchedPattern string
@@ -168,6 +181,12 @@ func (ac *AccessController) getUserGroups(username string) []string {
 
 // getContext updates an UserAccessControl with admin status and specific permissions on repos.
 func (ac *AccessController) updateUserAccessControl(userAc *reqCtx.UserAccessControl) {
+	if userAc.IsRobot() {
+		ac.updateRobotAccessControl(userAc)
+
+		return
+	}
+
 	identity := userAc.GetUsername()
 	groups := userAc.GetGroups()
 
@@ -176,6 +195,7 @@ func (ac *AccessController) updateUserAccessControl(userAc *reqCtx.UserAccessCon
 	updateGlobPatterns := ac.getGlobPatterns(identity, groups, constants.UpdatePermission)
 	deleteGlobPatterns := ac.getGlobPatterns(identity, groups, constants.DeletePermission)
 	dmcGlobPatterns := ac.getGlobPatterns(identity, groups, constants.DetectManifestCollisionPermission)
+	oitGlobPatterns := ac.getGlobPatterns(identity, groups, constants.OverrideImmutableTagsPermission)
 
 	userAc.SetGlobPatterns(constants.ReadPermission, readGlobPatterns)
 	userAc.SetGlobPatterns(constants.CreatePermission, createGlobPatterns)
@@ -185,9 +205,37 @@ func (ac *AccessController) updateUserAccessControl(userAc *reqCtx.UserAccessCon
 
 	if ac.isAdmin(userAc.GetUsername(), userAc.GetGroups()) {
 		userAc.SetIsAdmin(true)
+
+		// admins can override immutable tags on all repos if the admin policy allows it
+		if common.Contains(ac.Config.AdminPolicy.Actions, constants.OverrideImmutableTagsPermission) {
+			for pattern := range oitGlobPatterns {
+				oitGlobPatterns[pattern] = true
+			}
+
+			oitGlobPatterns["**"] = true
+		}
 	} else {
 		userAc.SetIsAdmin(false)
 	}
+
+	userAc.SetGlobPatterns(constants.OverrideImmutableTagsPermission, oitGlobPatterns)
+}
+
+// updateRobotAccessControl sets the permissions of a robot account, which only depend on its scope.
+func (ac *AccessController) updateRobotAccessControl(userAc *reqCtx.UserAccessControl) {
+	for _, action := range append(methodActions,
+		constants.DetectManifestCollisionPermission, constants.OverrideImmutableTagsPermission) {
+		globPatterns := make(map[string]bool)
+
+		// the scope is checked on top of the glob patterns
+		if common.Contains(methodActions, action) {
+			globPatterns["**"] = true
+		}
+
+		userAc.SetGlobPatterns(action, globPatterns)
+	}
+
+	userAc.SetIsAdmin(false)
 }
 
 // getAuthnMiddlewareContext builds ac context(allowed to read repos and if user is admin) and returns it.
@@ -266,7 +314,7 @@ func BaseAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 				return
 			}
 
-			aCtlr := NewAccessController(ctlr.Config)
+			aCtlr := NewAccessController(ctlr.AccessControlConfig(), ctlr.Log)
 
 			// get access control context made in authn.go
 			userAc, err := reqCtx.UserAcFromContext(request.Context())
@@ -301,11 +349,7 @@ func DistSpecAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 				return
 			}
 
-			vars := mux.Vars(request)
-			resource := vars["name"]
-			reference, ok := vars["reference"]
-
-			acCtrlr := NewAccessController(ctlr.Config)
+			acCtrlr := NewAccessController(ctlr.AccessControlConfig(), ctlr.Log)
 
 			// get userAc built in authn and previous authz middlewares
 			userAc, err := reqCtx.UserAcFromContext(request.Context())
@@ -315,51 +359,133 @@ func DistSpecAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 				return
 			}
 
-			var action string
-			if request.Method == http.MethodGet || request.Method == http.MethodHead {
-				action = constants.ReadPermission
+			resource, action := getRequestedPermission(ctlr, request)
+
+			can := acCtrlr.can(userAc, action, resource) //nolint:contextcheck
+			if !can && ctlr.TokenService != nil && userAc.IsAnonymous() && !hasSessionHeader(request) {
+				// anonymous clients may get a token with more access than the anonymous policy gives
+				tokenAuthFail(response, request, ctlr.TokenService.Challenge(request, zerr.ErrNoBearerToken),
+					ctlr.Config.HTTP.Auth.FailDelay)
+			} else if !can {
+				common.AuthzFail(response, request, userAc.GetUsername(), ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
+			} else {
+				next.ServeHTTP(response, request) //nolint:contextcheck
 			}
+		})
+	}
+}
 
-			if request.Method == http.MethodPut || request.Method == http.MethodPatch || request.Method == http.MethodPost {
-				// assume user wants to create
-				action = constants.CreatePermission
-				// if we get a reference (tag)
-				if ok {
-					is := ctlr.StoreController.GetImageStore(resource)
-
-					tags, err := is.GetImageTags(resource)
-					if err == nil && common.Contains(tags, reference) && reference != "latest" {
-						// if repo exists and request's tag exists then action is UPDATE
-						action = constants.UpdatePermission
-					}
-				}
+// ScopeAuthzHandler restricts scoped api keys and robot accounts to their repositories and actions
+// when no access control is configured, otherwise DistSpecAuthzHandler takes care of it.
+func ScopeAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			if request.Method == http.MethodOptions {
+				next.ServeHTTP(response, request)
+
+				return
 			}
 
-			if request.Method == http.MethodDelete {
-				action = constants.DeletePermission
+			userAc, err := reqCtx.UserAcFromContext(request.Context())
+			if err != nil { // should never happen
+				authFail(response, request, ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
+
+				return
 			}
 
-			can := acCtrlr.can(userAc, action, resource) //nolint:contextcheck
-			if !can {
+			if !userAc.IsScoped() {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			resource, action := getRequestedPermission(ctlr, request)
+
+			if !userAc.IsInScope(action, resource) {
 				common.AuthzFail(response, request, userAc.GetUsername(), ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
-			} else {
-				next.ServeHTTP(response, request) //nolint:contextcheck
+
+				return
+			}
+
+			next.ServeHTTP(response, request) //nolint:contextcheck
+		})
+	}
+}
+
+// DenyScopedUsersHandler rejects the requests authenticated with scoped api keys or robot accounts,
+// so they can't be used to create api keys with all the permissions of their owner.
+func DenyScopedUsersHandler(ctlr *Controller) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			if request.Method == http.MethodOptions {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			userAc, err := reqCtx.UserAcFromContext(request.Context())
+			if err != nil { // should never happen
+				authFail(response, request, ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
+
+				return
 			}
+
+			if userAc.IsScoped() {
+				common.AuthzFail(response, request, userAc.GetUsername(), ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
+
+				return
+			}
+
+			next.ServeHTTP(response, request)
 		})
 	}
 }
 
+// getRequestedPermission returns the repository targeted by a dist-spec request and the permission it needs.
+func getRequestedPermission(ctlr *Controller, request *http.Request) (string, string) {
+	vars := mux.Vars(request)
+	resource := vars["name"]
+	reference, ok := vars["reference"]
+
+	var action string
+	if request.Method == http.MethodGet || request.Method == http.MethodHead {
+		action = constants.ReadPermission
+	}
+
+	if request.Method == http.MethodPut || request.Method == http.MethodPatch || request.Method == http.MethodPost {
+		// assume user wants to create
+		action = constants.CreatePermission
+		// if we get a reference (tag)
+		if ok {
+			is := ctlr.StoreController.GetImageStore(resource)
+
+			tags, err := is.GetImageTags(resource)
+			if err == nil && common.Contains(tags, reference) && reference != "latest" {
+				// if repo exists and request's tag exists then action is UPDATE
+				action = constants.UpdatePermission
+			}
+		}
+	}
+
+	if request.Method == http.MethodDelete {
+		action = constants.DeletePermission
+	}
+
+	return resource, action
+}
+
 func MetricsAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
-			if ctlr.Config.HTTP.AccessControl == nil {
+			accessControl := ctlr.AccessControlConfig()
+			if accessControl == nil {
 				// allow access to authenticated user as anonymous policy does not exist
 				next.ServeHTTP(response, request)
 
 				return
 			}
 
-			if len(ctlr.Config.HTTP.AccessControl.Metrics.Users) == 0 {
+			if len(accessControl.Metrics.Users) == 0 {
 				log := ctlr.Log
 				log.Warn().Msg("auth is enabled but no metrics users in accessControl: /metrics is unaccesible")
 				common.AuthzFail(response, request, "", ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
@@ -376,7 +502,7 @@ func MetricsAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 			}
 
 			username := userAc.GetUsername()
-			if !common.Contains(ctlr.Config.HTTP.AccessControl.Metrics.Users, username) {
+			if !common.Contains(accessControl.Metrics.Users, username) {
 				common.AuthzFail(response, request, username, ctlr.Config.HTTP.Realm, ctlr.Config.HTTP.Auth.FailDelay)
 
 				return
@@ -32,6 +32,8 @@ type ResourceAction struct {
 // https://distribution.github.io/distribution/spec/auth/jwt/
 type ClaimsWithAccess struct {
 	Access []ResourceAccess `json:"access"`
+	// set by the built-in token service when the token is issued to a scoped api key
+	Scope *TokenScope `json:"zot_scope,omitempty"` //nolint:tagliatelle // private claim
 	jwt.RegisteredClaims
 }
 
@@ -76,6 +78,15 @@ func NewBearerAuthorizer(realm string, service string, key crypto.PublicKey) Bea
 // scope for the requested resource action. If an authorization error occurs (e.g. no token is given or the token has
 // insufficient scope), an AuthChallengeError is returned as the error.
 func (a *BearerAuthorizer) Authorize(header string, requested *ResourceAction) error {
+	_, err := a.authorize(header, requested)
+
+	return err
+}
+
+// authorize is Authorize returning the claims of the valid tokens, the given parser options are used
+// in addition to the default ones.
+func (a *BearerAuthorizer) authorize(header string, requested *ResourceAction, options ...jwt.ParserOption,
+) (*ClaimsWithAccess, error) {
 	challenge := &AuthChallengeError{
 		realm:          a.realm,
 		service:        a.service,
@@ -86,26 +97,29 @@ func (a *BearerAuthorizer) Authorize(header string, requested *ResourceAction) e
 		// if no bearer token is set in the authorization header, return the authentication challenge
 		challenge.err = zerr.ErrNoBearerToken
 
-		return challenge
+		return nil, challenge
 	}
 
 	signedString := bearerTokenMatch.ReplaceAllString(header, "$1")
 
+	options = append([]jwt.ParserOption{jwt.WithValidMethods(a.allowedSigningAlgorithms()), jwt.WithIssuedAt()},
+		options...)
+
 	token, err := jwt.ParseWithClaims(signedString, &ClaimsWithAccess{}, func(token *jwt.Token) (interface{}, error) {
 		return a.key, nil
-	}, jwt.WithValidMethods(a.allowedSigningAlgorithms()), jwt.WithIssuedAt())
+	}, options...)
 	if err != nil {
-		return fmt.Errorf("%w: %w", zerr.ErrInvalidBearerToken, err)
-	}
-
-	if requested == nil {
-		// the token is valid and no access is requested, so we do not have to validate the access claim
-		return nil
+		return nil, fmt.Errorf("%w: %w", zerr.ErrInvalidBearerToken, err)
 	}
 
 	claims, ok := token.Claims.(*ClaimsWithAccess)
 	if !ok {
-		return fmt.Errorf("%w: invalid claims type", zerr.ErrInvalidBearerToken)
+		return nil, fmt.Errorf("%w: invalid claims type", zerr.ErrInvalidBearerToken)
+	}
+
+	if requested == nil {
+		// the token is valid and no access is requested, so we do not have to validate the access claim
+		return claims, nil
 	}
 
 	// check whether the requested access is allowed by the scope of the token
@@ -123,12 +137,12 @@ func (a *BearerAuthorizer) Authorize(header string, requested *ResourceAction) e
 		}
 
 		// requested action is allowed, so don't return an error
-		return nil
+		return claims, nil
 	}
 
 	challenge.err = zerr.ErrInsufficientScope
 
-	return challenge
+	return nil, challenge
 }
 
 func (a *BearerAuthorizer) allowedSigningAlgorithms() []string {
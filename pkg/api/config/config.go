@@ -2,9 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"math"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	distspec "github.com/opencontainers/distribution-spec/specs-go"
 
 	"zotregistry.dev/zot/pkg/compat"
@@ -32,14 +36,25 @@ type StorageConfig struct {
 	GCDelay       time.Duration // applied for blobs
 	GCInterval    time.Duration
 	Retention     ImageRetention
+	Quota         StorageQuota
 	StorageDriver map[string]interface{} `mapstructure:",omitempty"`
 	CacheDriver   map[string]interface{} `mapstructure:",omitempty"`
+	// enforced whether authorization is enabled or not, those of the root storage on all the repositories
+	// and those of a subPath on its repositories
+	ImmutableTags []ImmutableTagsPolicy
 }
 
 type ImageRetention struct {
 	DryRun   bool
 	Delay    time.Duration // applied for referrers and untagged
 	Policies []RetentionPolicy
+	// applied instead of the keepTags policies to repos populated by the sync proxy cache
+	ProxyCache *ProxyCacheRetention
+}
+
+type ProxyCacheRetention struct {
+	// cached tags not pulled (or synced) within this duration are evicted
+	EvictNotPulledWithin time.Duration
 }
 
 type RetentionPolicy struct {
@@ -55,6 +70,32 @@ type KeepTagsPolicy struct {
 	PushedWithin            *time.Duration
 	MostRecentlyPushedCount int
 	MostRecentlyPulledCount int
+	// images whose annotations match all the selectors are kept
+	Annotations []AnnotationSelector
+	// images used as a base image by other kept images, or by the images of other repos, are kept
+	KeepBaseImages bool
+	// images having a trusted signature are kept
+	Signed bool
+	// of the images kept by the rules above, the most recently pushed ones are kept while the total size
+	// of their blobs (in bytes) is within MaxSize
+	MaxSize int64
+}
+
+type AnnotationSelector struct {
+	Key string
+	// regex matched against the annotation value, if empty the annotation only needs to be present
+	Value string
+}
+
+type StorageQuota struct {
+	Policies []QuotaPolicy
+}
+
+type QuotaPolicy struct {
+	Repositories  []string
+	SoftLimit     int64 // bytes, exceeding it is only logged
+	HardLimit     int64 // bytes, pushes exceeding it are denied
+	PerRepository bool  // limits apply to each matching repository instead of all of them together
 }
 
 type TLSConfig struct {
@@ -72,8 +113,10 @@ type AuthConfig struct {
 	HTPasswd          AuthHTPasswd
 	LDAP              *LDAPConfig
 	Bearer            *BearerConfig
+	TokenService      *TokenServiceConfig
 	OpenID            *OpenIDConfig
 	APIKey            bool
+	RobotAccounts     bool
 	SessionKeysFile   string
 	SessionHashKey    []byte `json:"-"`
 	SessionEncryptKey []byte `json:"-"`
@@ -85,6 +128,22 @@ type BearerConfig struct {
 	Cert    string
 }
 
+// TokenServiceConfig makes zot its own token realm, issuing bearer tokens to the users
+// authenticated by htpasswd, LDAP or API keys.
+type TokenServiceConfig struct {
+	// Realm is the URL of the token endpoint advertised to clients,
+	// by default it is built from the URL of the request.
+	Realm string
+	// Service is the audience of the issued tokens, clients pass it back when requesting tokens.
+	Service string
+	// Issuer of the tokens, defaults to Service.
+	Issuer string
+	// Key is the path of the PEM encoded RSA, ECDSA or ED25519 private key used to sign the tokens.
+	Key string
+	// Expiration is the lifetime of the tokens, defaults to 5 minutes.
+	Expiration time.Duration
+}
+
 type SessionKeys struct {
 	HashKey    string
 	EncryptKey string `mapstructure:",omitempty"`
@@ -115,8 +174,35 @@ type MethodRatelimitConfig struct {
 }
 
 type RatelimitConfig struct {
-	Rate    *int                    // requests per second
-	Methods []MethodRatelimitConfig `mapstructure:",omitempty"`
+	Rate     *int                    // requests per second
+	Methods  []MethodRatelimitConfig `mapstructure:",omitempty"`
+	Policies []RatelimitPolicy       `mapstructure:",omitempty"`
+	// Redis shares the counters of the policies between the replicas of a cluster,
+	// it takes the same parameters as the redis cache driver.
+	Redis map[string]interface{} `mapstructure:",omitempty"`
+}
+
+const (
+	RatelimitKeyUsername   = "username"
+	RatelimitKeyAPIKey     = "apikey"
+	RatelimitKeyIP         = "ip"
+	RatelimitKeyRepository = "repository"
+)
+
+// RatelimitPolicy limits the pulls of the repositories matching Repositories with token buckets
+// counted separately for each value of Key (username, apikey, ip or repository).
+type RatelimitPolicy struct {
+	Key           string
+	Repositories  []string         `mapstructure:",omitempty"` // glob patterns, all repositories if empty
+	ManifestPulls *RatelimitBudget `mapstructure:",omitempty"`
+	BlobBytes     *RatelimitBudget `mapstructure:",omitempty"`
+}
+
+// RatelimitBudget refills Limit tokens every Interval, up to Burst tokens (by default Limit).
+type RatelimitBudget struct {
+	Limit    int64
+	Interval time.Duration
+	Burst    int64 `mapstructure:",omitempty"`
 }
 
 //nolint:maligned
@@ -143,11 +229,20 @@ type ClusterConfig struct {
 	// in the cluster.
 	Members []string `json:"members" mapstructure:"members"`
 
+	// contains the members of the cluster before a membership change.
+	// while set, the cluster is in transition: repositories are rebalanced to their new
+	// owners and reads missing on the new owner fall back to the previous owner.
+	PreviousMembers []string `json:"previousMembers,omitempty" mapstructure:"previousMembers,omitempty"`
+
 	// contains the hash key that is required for siphash.
 	// must be a 128-bit (16-byte) key
 	// https://github.com/dchest/siphash?tab=readme-ov-file#func-newkey-byte-hashhash64
 	HashKey string `json:"hashKey" mapstructure:"hashKey"`
 
+	// the algorithm computing the member owning a repository, "modulo" (default) or "rendezvous".
+	// with rendezvous hashing, a membership change only moves the repositories owned by the added or removed members.
+	Hashing string `json:"hashing,omitempty" mapstructure:"hashing,omitempty"`
+
 	// contains client TLS config.
 	TLS *TLSConfig `json:"tls" mapstructure:"tls"`
 
@@ -159,10 +254,20 @@ type ClusterRequestProxyConfig struct {
 	// holds the cluster socket (IP:port) derived from the host's
 	// interface configuration and the listening port of the HTTP server.
 	LocalMemberClusterSocket string
-	// index of the local member cluster socket in the members array.
+	// index of the local member cluster socket in the members array,
+	// RemovedMemberIndex if the local member was removed from the cluster.
 	LocalMemberClusterSocketIndex uint64
 }
 
+// RemovedMemberIndex is the local member index of a member which was removed from the cluster
+// by a configuration reload, such a member proxies all requests until it is shut down.
+const RemovedMemberIndex = math.MaxUint64
+
+// IsInTransition returns true while repositories are being rebalanced after a membership change.
+func (c *ClusterConfig) IsInTransition() bool {
+	return len(c.PreviousMembers) > 0
+}
+
 type LDAPCredentials struct {
 	BindDN       string
 	BindPassword string
@@ -217,10 +322,28 @@ type GlobalStorageConfig struct {
 }
 
 type AccessControlConfig struct {
-	Repositories Repositories `json:"repositories" mapstructure:"repositories"`
-	AdminPolicy  Policy
-	Groups       Groups
-	Metrics      Metrics
+	Repositories  Repositories `json:"repositories" mapstructure:"repositories"`
+	AdminPolicy   Policy
+	Groups        Groups
+	Metrics       Metrics
+	ImmutableTags []ImmutableTagsPolicy      `json:"immutableTags" mapstructure:"immutableTags"`
+	MetaDB        *AccessControlMetaDBConfig `json:"metaDB"        mapstructure:"metaDB"`
+}
+
+// AccessControlMetaDBConfig keeps the repositories, adminPolicy and groups policies in MetaDB, where admins
+// manage them through the api. The policies of the config file are only used to seed an empty MetaDB.
+type AccessControlMetaDBConfig struct {
+	Enable bool
+	// ReloadInterval is how often the changes made through other instances sharing MetaDB are applied,
+	// defaults to 10 seconds.
+	ReloadInterval time.Duration
+}
+
+// ImmutableTagsPolicy protects the tags matching Patterns (regular expressions) in the repositories
+// matching Repositories (glob patterns) from being overwritten or deleted once pushed.
+type ImmutableTagsPolicy struct {
+	Repositories []string
+	Patterns     []string
 }
 
 func (config *AccessControlConfig) AnonymousPolicyExists() bool {
@@ -237,6 +360,42 @@ func (config *AccessControlConfig) AnonymousPolicyExists() bool {
 	return false
 }
 
+// IsImmutableTag returns true if tag is protected by an immutable tags policy in repo.
+func (config *AccessControlConfig) IsImmutableTag(repo, tag string) bool {
+	if config == nil {
+		return false
+	}
+
+	return isImmutableTag(config.ImmutableTags, repo, tag)
+}
+
+func isImmutableTag(policies []ImmutableTagsPolicy, repo, tag string) bool {
+	for _, policy := range policies {
+		if !matchesAnyGlob(policy.Repositories, repo) {
+			continue
+		}
+
+		for _, pattern := range policy.Patterns {
+			// patterns are validated at startup
+			if matched, err := regexp.MatchString(pattern, tag); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := glob.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
 type (
 	Repositories map[string]PolicyGroup
 	Groups       map[string]Group
@@ -398,6 +557,24 @@ func (c *Config) IsAuthzEnabled() bool {
 	return c.HTTP.AccessControl != nil
 }
 
+// IsImmutableTag returns true if tag is protected in repo by an immutable tags policy of the root storage,
+// of the subPath storing repo or of accessControl, the access control config in use which may have been reloaded.
+func (c *Config) IsImmutableTag(accessControl *AccessControlConfig, repo, tag string) bool {
+	if isImmutableTag(c.Storage.ImmutableTags, repo, tag) || accessControl.IsImmutableTag(repo, tag) {
+		return true
+	}
+
+	// the repo is stored in the subPath named after its first path component
+	route, _, found := strings.Cut(repo, "/")
+	if !found {
+		return false
+	}
+
+	subPathConfig, ok := c.Storage.SubPaths["/"+route]
+
+	return ok && isImmutableTag(subPathConfig.ImmutableTags, repo, tag)
+}
+
 func (c *Config) IsMTLSAuthEnabled() bool {
 	if c.HTTP.TLS != nil &&
 		c.HTTP.TLS.Key != "" &&
@@ -431,6 +608,17 @@ func (c *Config) IsBearerAuthEnabled() bool {
 	return false
 }
 
+func (c *Config) IsTokenServiceEnabled() bool {
+	if c.HTTP.Auth != nil &&
+		c.HTTP.Auth.TokenService != nil &&
+		c.HTTP.Auth.TokenService.Key != "" &&
+		c.HTTP.Auth.TokenService.Service != "" {
+		return true
+	}
+
+	return false
+}
+
 func (c *Config) IsOpenIDAuthEnabled() bool {
 	if c.HTTP.Auth != nil &&
 		c.HTTP.Auth.OpenID != nil {
@@ -452,6 +640,24 @@ func (c *Config) IsAPIKeyEnabled() bool {
 	return false
 }
 
+// IsRobotAccountsEnabled returns whether admins can manage robot accounts, which are stored in MetaDB.
+func (c *Config) IsRobotAccountsEnabled() bool {
+	return c.HTTP.Auth != nil && c.HTTP.Auth.RobotAccounts
+}
+
+// IsAdminPolicyEnforced returns whether the admins can be told apart from the other users, which needs
+// basic authentication and an admin policy.
+func (c *Config) IsAdminPolicyEnforced() bool {
+	return c.IsBasicAuthnEnabled() && c.HTTP.AccessControl != nil &&
+		(len(c.HTTP.AccessControl.AdminPolicy.Users) > 0 || len(c.HTTP.AccessControl.AdminPolicy.Groups) > 0)
+}
+
+// IsAccessControlInMetaDB returns whether the access control policies are stored in MetaDB and managed
+// through the api instead of the config file.
+func (c *Config) IsAccessControlInMetaDB() bool {
+	return c.HTTP.AccessControl != nil && c.HTTP.AccessControl.MetaDB != nil && c.HTTP.AccessControl.MetaDB.Enable
+}
+
 func (c *Config) IsBasicAuthnEnabled() bool {
 	if c.IsHtpasswdAuthEnabled() || c.IsLdapAuthEnabled() ||
 		c.IsOpenIDAuthEnabled() || c.IsAPIKeyEnabled() {
@@ -482,6 +688,10 @@ func (c *Config) IsMetricsEnabled() bool {
 	return c.Extensions != nil && c.Extensions.Metrics != nil && *c.Extensions.Metrics.Enable
 }
 
+func (c *Config) IsTracingEnabled() bool {
+	return c.Extensions != nil && c.Extensions.Tracing != nil && *c.Extensions.Tracing.Enable
+}
+
 func (c *Config) IsSearchEnabled() bool {
 	return c.Extensions != nil && c.Extensions.Search != nil && *c.Extensions.Search.Enable
 }
@@ -510,6 +720,11 @@ func (c *Config) IsImageTrustEnabled() bool {
 func (c *Config) IsRetentionEnabled() bool {
 	var needsMetaDB bool
 
+	// cached repos are only known from metaDB
+	if c.Storage.Retention.ProxyCache != nil {
+		needsMetaDB = true
+	}
+
 	for _, retentionPolicy := range c.Storage.Retention.Policies {
 		for _, tagRetentionPolicy := range retentionPolicy.KeepTags {
 			if c.isTagsRetentionEnabled(tagRetentionPolicy) {
@@ -519,6 +734,10 @@ func (c *Config) IsRetentionEnabled() bool {
 	}
 
 	for _, subpath := range c.Storage.SubPaths {
+		if subpath.Retention.ProxyCache != nil {
+			needsMetaDB = true
+		}
+
 		for _, retentionPolicy := range subpath.Retention.Policies {
 			for _, tagRetentionPolicy := range retentionPolicy.KeepTags {
 				if c.isTagsRetentionEnabled(tagRetentionPolicy) {
@@ -542,6 +761,24 @@ func (c *Config) isTagsRetentionEnabled(tagRetentionPolicy KeepTagsPolicy) bool
 	return false
 }
 
+func (c *Config) IsQuotaEnabled() bool {
+	if len(c.Storage.Quota.Policies) > 0 {
+		return true
+	}
+
+	for _, subpath := range c.Storage.SubPaths {
+		if len(subpath.Quota.Policies) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) IsRatelimitPolicyEnabled() bool {
+	return c.HTTP.Ratelimit != nil && len(c.HTTP.Ratelimit.Policies) > 0
+}
+
 func (c *Config) IsCosignEnabled() bool {
 	return c.IsImageTrustEnabled() && c.Extensions.Trust.Cosign
 }
@@ -562,6 +799,11 @@ func (c *Config) IsEventRecorderEnabled() bool {
 	return c.Extensions != nil && c.Extensions.Events != nil && *c.Extensions.Events.Enable
 }
 
+func (c *Config) IsEventOutboxEnabled() bool {
+	return c.IsEventRecorderEnabled() && c.Extensions.Events.Outbox != nil &&
+		c.Extensions.Events.Outbox.Enable != nil && *c.Extensions.Events.Outbox.Enable
+}
+
 func IsOpenIDSupported(provider string) bool {
 	for _, supportedProvider := range openIDSupportedProviders {
 		if supportedProvider == provider {
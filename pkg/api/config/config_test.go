@@ -130,6 +130,48 @@ func TestConfig(t *testing.T) {
 		conf.Storage.SubPaths = subPaths
 
 		So(conf.IsRetentionEnabled(), ShouldBeTrue)
+
+		// proxy cache eviction needs metaDB to find cached repos
+		conf = config.New()
+		conf.Storage.Retention.ProxyCache = &config.ProxyCacheRetention{EvictNotPulledWithin: time.Hour}
+
+		So(conf.IsRetentionEnabled(), ShouldBeTrue)
+
+		conf = config.New()
+		conf.Storage.SubPaths = map[string]config.StorageConfig{
+			"/a": {
+				Retention: config.ImageRetention{
+					ProxyCache: &config.ProxyCacheRetention{EvictNotPulledWithin: time.Hour},
+				},
+			},
+		}
+
+		So(conf.IsRetentionEnabled(), ShouldBeTrue)
+	})
+
+	Convey("Test IsQuotaEnabled()", t, func() {
+		conf := config.New()
+		So(conf.IsQuotaEnabled(), ShouldBeFalse)
+
+		quota := config.StorageQuota{
+			Policies: []config.QuotaPolicy{
+				{
+					Repositories: []string{"team/**"},
+					HardLimit:    1024,
+				},
+			},
+		}
+
+		conf.Storage.SubPaths = map[string]config.StorageConfig{
+			"/a": {Quota: quota},
+		}
+
+		So(conf.IsQuotaEnabled(), ShouldBeTrue)
+
+		conf.Storage.SubPaths = nil
+		conf.Storage.Quota = quota
+
+		So(conf.IsQuotaEnabled(), ShouldBeTrue)
 	})
 
 	Convey("Test IsEventRecorderEnabled()", t, func() {
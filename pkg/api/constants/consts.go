@@ -18,9 +18,17 @@ const (
 	LoginPath                    = AppNamespacePath + "/auth/login"
 	LogoutPath                   = AppNamespacePath + "/auth/logout"
 	APIKeyPath                   = AppNamespacePath + "/auth/apikey"
+	TokenPath                    = AppNamespacePath + "/auth/token"
+	AdminPath                    = AppNamespacePath + "/admin"
+	AdminJobsPath                = AdminPath + "/jobs"
+	AdminEventsPath              = AdminPath + "/events"
+	RobotAccountsPath            = AdminPath + "/robots"
+	AccessControlPoliciesPath    = AdminPath + "/policies"
 	SessionClientHeaderName      = "X-ZOT-API-CLIENT"
 	SessionClientHeaderValue     = "zot-ui"
 	APIKeysPrefix                = "zak_"
+	RobotAccountPrefix           = "robot$"
+	RobotSecretsPrefix           = "zrs_"
 	CallbackUIQueryParam         = "callback_ui"
 	APIKeyTimeFormat             = time.RFC3339
 	// authz permissions.
@@ -31,8 +39,11 @@ const (
 	DeletePermission = "delete"
 	// behaviour actions.
 	DetectManifestCollisionPermission = "detectManifestCollision"
+	OverrideImmutableTagsPermission   = "overrideImmutableTags"
 	// zot scale-out hop count header.
 	ScaleOutHopCountHeader = "X-Zot-Cluster-Hop-Count"
+	// signature of the requests sent by a zot scale-out cluster member to another one.
+	ScaleOutSignatureHeader = "X-Zot-Cluster-Signature"
 	// log string keys.
 	// these can be used together with the logger to add context to a log message.
 	RepositoryLogKey = "repository"
@@ -9,8 +9,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -23,7 +25,9 @@ import (
 	ext "zotregistry.dev/zot/pkg/extensions"
 	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/extensions/lint"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
 	"zotregistry.dev/zot/pkg/log"
 	"zotregistry.dev/zot/pkg/meta"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
@@ -48,14 +52,27 @@ type Controller struct {
 	Metrics         monitoring.MetricServer
 	EventRecorder   events.Recorder
 	CveScanner      ext.CveScanner
+	Linter          *lint.Linter
 	SyncOnDemand    SyncOnDemand
 	RelyingParties  map[string]rp.RelyingParty
 	CookieStore     *CookieStore
 	HTPasswd        *HTPasswd
 	HTPasswdWatcher *HTPasswdWatcher
 	LDAPClient      *LDAPClient
+	TokenService    *TokenService
 	taskScheduler   *scheduler.Scheduler
-	Healthz         *common.Healthz
+	// admin jobs submitted through the admin api
+	jobs    *jobManager
+	Healthz *common.Healthz
+	// set if the access control policies are stored in MetaDB
+	acPolicies *accessControlPolicies
+	// flushes pending spans, set if tracing is enabled
+	shutdownTracing func(context.Context) error
+	// replaced as a whole when the cluster members are reloaded, read it with ClusterConfig()
+	clusterConfig atomic.Pointer[config.ClusterConfig]
+	// replaced as a whole when the config or the policies stored in MetaDB are reloaded,
+	// read it with AccessControlConfig()
+	accessControlConfig atomic.Pointer[config.AccessControlConfig]
 	// runtime params
 	chosenPort int // kernel-chosen port
 }
@@ -111,9 +128,11 @@ func NewController(appConfig *config.Config) *Controller {
 	}
 
 	controller.Config = appConfig
+	controller.clusterConfig.Store(appConfig.Cluster)
 	controller.Log = logger
 	controller.HTPasswd = htp
 	controller.HTPasswdWatcher = htw
+	controller.jobs = newJobManager()
 
 	if appConfig.Log.Audit != "" {
 		audit := log.NewAuditLogger(appConfig.Log.Level, appConfig.Log.Audit)
@@ -137,6 +156,11 @@ func (c *Controller) Run() error {
 	// setup HTTP API router
 	engine := mux.NewRouter()
 
+	// trace HTTP requests if enabled, rate-limited requests included
+	if c.shutdownTracing != nil {
+		engine.Use(tracing.Middleware())
+	}
+
 	// rate-limit HTTP requests if enabled
 	if c.Config.HTTP.Ratelimit != nil {
 		if c.Config.HTTP.Ratelimit.Rate != nil {
@@ -270,6 +294,13 @@ func (c *Controller) Init() error {
 
 	c.Metrics = monitoring.NewMetricsServer(enabled, c.Log)
 
+	// reloads replace the access control config in use from now on, Config.HTTP.AccessControl is left unchanged
+	c.accessControlConfig.Store(c.Config.HTTP.AccessControl)
+
+	if err := c.InitTracing(); err != nil {
+		return err
+	}
+
 	if err := c.InitEventRecorder(); err != nil {
 		return err
 	}
@@ -282,6 +313,10 @@ func (c *Controller) Init() error {
 		return err
 	}
 
+	if err := c.initAccessControlPolicies(); err != nil {
+		return err
+	}
+
 	c.InitCVEInfo()
 	c.Healthz.Started()
 
@@ -303,7 +338,10 @@ func (c *Controller) InitCVEInfo() {
 }
 
 func (c *Controller) InitImageStore() error {
-	linter := ext.GetLinter(c.Config, c.Log)
+	linter, err := ext.GetLinter(c.Config, c.Log)
+	if err != nil {
+		return err
+	}
 
 	storeController, err := storage.New(c.Config, linter, c.Metrics, c.Log, c.EventRecorder)
 	if err != nil {
@@ -311,6 +349,7 @@ func (c *Controller) InitImageStore() error {
 	}
 
 	c.StoreController = storeController
+	c.Linter = linter
 
 	return nil
 }
@@ -345,6 +384,10 @@ func (c *Controller) InitMetaDB() error {
 			return err
 		}
 
+		if c.shutdownTracing != nil {
+			driver = meta.NewTracedMetaDB(driver)
+		}
+
 		err = ext.SetupExtensions(c.Config, driver, c.Log) //nolint:contextcheck
 		if err != nil {
 			return err
@@ -361,13 +404,28 @@ func (c *Controller) InitMetaDB() error {
 		}
 
 		c.MetaDB = driver
+
+		if c.Linter != nil {
+			c.Linter.SetMetaDB(driver)
+		}
 	}
 
 	return nil
 }
 
+func (c *Controller) InitTracing() error {
+	shutdownTracing, err := ext.EnableTracingExtension(c.Config, c.Log)
+	if err != nil && !goerrors.Is(err, errors.ErrExtensionNotEnabled) {
+		return err
+	}
+
+	c.shutdownTracing = shutdownTracing
+
+	return nil
+}
+
 func (c *Controller) InitEventRecorder() error {
-	eventRecorder, err := ext.NewEventRecorder(c.Config, c.Log)
+	eventRecorder, err := ext.NewEventRecorder(c.Config, c.Metrics, c.Log)
 	if err != nil && !goerrors.Is(err, errors.ErrExtensionNotEnabled) {
 		return err
 	}
@@ -378,8 +436,14 @@ func (c *Controller) InitEventRecorder() error {
 }
 
 func (c *Controller) LoadNewConfig(newConfig *config.Config) {
-	// reload access control config
-	c.Config.HTTP.AccessControl = newConfig.HTTP.AccessControl
+	// reload access control config, the policies stored in MetaDB are kept
+	if c.acPolicies != nil {
+		c.acPolicies.lock.Lock()
+		c.setAccessControlConfig(newConfig.HTTP.AccessControl)
+		c.acPolicies.lock.Unlock()
+	} else {
+		c.accessControlConfig.Store(newConfig.HTTP.AccessControl)
+	}
 
 	if c.Config.HTTP.Auth != nil {
 		c.Config.HTTP.Auth.HTPasswd = newConfig.HTTP.Auth.HTPasswd
@@ -426,6 +490,12 @@ func (c *Controller) LoadNewConfig(newConfig *config.Config) {
 		}
 	}
 
+	// reload cluster membership, repositories which changed owner are moved
+	// by the rebalance task started together with the other background tasks
+	if c.ClusterConfig() != nil && newConfig.Cluster != nil {
+		c.reloadClusterMembers(newConfig.Cluster)
+	}
+
 	// reload background tasks
 	if newConfig.Extensions != nil {
 		if c.Config.Extensions == nil {
@@ -450,10 +520,80 @@ func (c *Controller) LoadNewConfig(newConfig *config.Config) {
 
 	c.InitCVEInfo()
 
-	c.Log.Info().Interface("reloaded params", c.Config.Sanitize()).
+	reloadedConfig := c.Config.Sanitize()
+	reloadedConfig.HTTP.AccessControl = c.AccessControlConfig()
+
+	c.Log.Info().Interface("reloaded params", reloadedConfig).
 		Msg("loaded new configuration settings")
 }
 
+// ClusterConfig returns the scale-out cluster config currently in use, nil if the cluster is not enabled.
+// the requests being served keep using the config they read even if the cluster members are reloaded meanwhile.
+func (c *Controller) ClusterConfig() *config.ClusterConfig {
+	return c.clusterConfig.Load()
+}
+
+// AccessControlConfig returns the access control config currently in use, nil if authorization is not enabled.
+// Config.HTTP.AccessControl keeps the config loaded at startup, this one follows the reloads.
+func (c *Controller) AccessControlConfig() *config.AccessControlConfig {
+	return c.accessControlConfig.Load()
+}
+
+func (c *Controller) reloadClusterMembers(newClusterConfig *config.ClusterConfig) {
+	clusterConfig := c.ClusterConfig()
+
+	// unless set explicitly, reads fall back to the owners computed from the members before the reload
+	previousMembers := newClusterConfig.PreviousMembers
+	if len(previousMembers) == 0 && !slices.Equal(clusterConfig.Members, newClusterConfig.Members) {
+		previousMembers = clusterConfig.Members
+	}
+
+	localSockets, err := common.GetLocalSockets(c.Config.HTTP.Port)
+	if err != nil {
+		c.Log.Error().Err(err).Msg("failed to get local sockets, cluster members not reloaded")
+
+		return
+	}
+
+	memberSocketIdx, memberSocket, err := GetLocalMemberClusterSocket(newClusterConfig.Members, localSockets)
+	if err != nil {
+		c.Log.Error().Err(err).Msg("failed to get member socket, cluster members not reloaded")
+
+		return
+	}
+
+	proxyConfig := &config.ClusterRequestProxyConfig{
+		LocalMemberClusterSocket:      memberSocket,
+		LocalMemberClusterSocketIndex: uint64(memberSocketIdx),
+	}
+
+	if memberSocketIdx < 0 || memberSocket == "" {
+		// a removed member proxies all requests and moves its repositories to the remaining members
+		c.Log.Warn().Str("members", strings.Join(newClusterConfig.Members, ",")).
+			Msg("local member was removed from the cluster")
+
+		proxyConfig = &config.ClusterRequestProxyConfig{
+			LocalMemberClusterSocket:      clusterConfig.Proxy.LocalMemberClusterSocket,
+			LocalMemberClusterSocketIndex: config.RemovedMemberIndex,
+		}
+	}
+
+	if newClusterConfig.Hashing != clusterConfig.Hashing {
+		c.Log.Warn().Str("hashing", clusterConfig.Hashing).
+			Msg("cluster hashing can't be changed by a reload, it would change the owner of most repositories")
+	}
+
+	// the config is swapped instead of being updated in place, it is read concurrently by the requests being served
+	reloadedConfig := *clusterConfig
+	reloadedConfig.Members = newClusterConfig.Members
+	reloadedConfig.PreviousMembers = previousMembers
+	reloadedConfig.Proxy = proxyConfig
+
+	c.clusterConfig.Store(&reloadedConfig)
+	// used when the background tasks are restarted after the reload
+	c.Config.Cluster = &reloadedConfig
+}
+
 func (c *Controller) Shutdown() {
 	c.StopBackgroundTasks()
 
@@ -461,6 +601,17 @@ func (c *Controller) Shutdown() {
 		ctx := context.Background()
 		_ = c.Server.Shutdown(ctx)
 	}
+
+	if c.shutdownTracing != nil {
+		if err := c.shutdownTracing(context.Background()); err != nil {
+			c.Log.Error().Err(err).Msg("failed to flush pending spans")
+		}
+	}
+
+	// stops delivering events, the undelivered ones are kept in the outbox if enabled
+	if c.EventRecorder != nil {
+		c.EventRecorder.Close()
+	}
 }
 
 // Will stop scheduler and wait for all tasks to finish their work.
@@ -479,6 +630,7 @@ func (c *Controller) StartBackgroundTasks() {
 		gc := gc.NewGarbageCollect(c.StoreController.DefaultStore, c.MetaDB, gc.Options{
 			Delay:          c.Config.Storage.GCDelay,
 			ImageRetention: c.Config.Storage.Retention,
+			Events:         c.EventRecorder,
 		}, c.Audit, c.Log)
 
 		gc.CleanImageStorePeriodically(c.Config.Storage.GCInterval, c.taskScheduler)
@@ -490,7 +642,8 @@ func (c *Controller) StartBackgroundTasks() {
 	// Enable extensions if extension config is provided for DefaultStore
 	if c.Config != nil && c.Config.Extensions != nil {
 		ext.EnableMetricsExtension(c.Config, c.Log, c.Config.Storage.RootDirectory)
-		ext.EnableSearchExtension(c.Config, c.StoreController, c.MetaDB, c.taskScheduler, c.CveScanner, c.Log)
+		ext.EnableSearchExtension(c.Config, c.StoreController, c.MetaDB, c.taskScheduler, c.CveScanner,
+			c.EventRecorder, c.Log)
 	}
 	// runs once if metrics are enabled & imagestore is local
 	if c.Config.IsMetricsEnabled() && c.Config.Storage.StorageDriver == nil {
@@ -505,6 +658,7 @@ func (c *Controller) StartBackgroundTasks() {
 					gc.Options{
 						Delay:          storageConfig.GCDelay,
 						ImageRetention: storageConfig.Retention,
+						Events:         c.EventRecorder,
 					}, c.Audit, c.Log)
 
 				gc.CleanImageStorePeriodically(storageConfig.GCInterval, c.taskScheduler)
@@ -530,7 +684,8 @@ func (c *Controller) StartBackgroundTasks() {
 	if c.Config.Extensions != nil {
 		ext.EnableScrubExtension(c.Config, c.Log, c.StoreController, c.taskScheduler)
 		//nolint: contextcheck
-		syncOnDemand, err := ext.EnableSyncExtension(c.Config, c.MetaDB, c.StoreController, c.taskScheduler, c.Log)
+		syncOnDemand, err := ext.EnableSyncExtension(c.Config, c.MetaDB, c.StoreController, c.taskScheduler,
+			c.EventRecorder, c.Log)
 		if err != nil {
 			c.Log.Error().Err(err).Msg("failed to start sync extension")
 		}
@@ -542,6 +697,10 @@ func (c *Controller) StartBackgroundTasks() {
 		c.CookieStore.RunSessionCleaner(c.taskScheduler)
 	}
 
+	c.RunClusterRebalance(c.taskScheduler)
+
+	c.RunAccessControlPoliciesReload(c.taskScheduler)
+
 	// we can later move enabling the other scheduled tasks inside the call below
 	ext.EnableScheduledTasks(c.Config, c.taskScheduler, c.MetaDB, c.Log) //nolint: contextcheck
 }
@@ -56,6 +56,7 @@ import (
 	"zotregistry.dev/zot/pkg/api/constants"
 	apiErr "zotregistry.dev/zot/pkg/api/errors"
 	"zotregistry.dev/zot/pkg/cli/server"
+	"zotregistry.dev/zot/pkg/cluster"
 	"zotregistry.dev/zot/pkg/common"
 	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/log"
@@ -1255,6 +1256,21 @@ func TestScaleOutRequestProxy(t *testing.T) {
 			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
 		})
 
+		Convey("Should proxy client requests claiming to be proxied by another member", func() {
+			// alpine is owned by an offline member, the request would be handled locally if the header was trusted
+			for _, signature := range []string{"", "1:invalid", fmt.Sprintf("%d:invalid", time.Now().Unix())} {
+				request := resty.R().SetHeader(constants.ScaleOutHopCountHeader, "1")
+				if signature != "" {
+					request.SetHeader(constants.ScaleOutSignatureHeader, signature)
+				}
+
+				resp, err := request.Get(fmt.Sprintf("%s/v2/%s/tags/list", test.GetBaseURL(port), "alpine"))
+				So(err, ShouldBeNil)
+				So(resp, ShouldNotBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusInternalServerError)
+			}
+		})
+
 		Convey("Should fail to upload an image that is proxied to another instance", func() {
 			repoName := "alpine"
 			img := CreateRandomImage()
@@ -1630,6 +1646,124 @@ func TestScaleOutRequestProxy(t *testing.T) {
 	})
 }
 
+func TestScaleOutMembershipChange(t *testing.T) {
+	Convey("Given a single member cluster extended with a second member", t, func() {
+		const hashKey = "loremipsumdolors"
+
+		ports := []string{test.GetFreePort(), test.GetFreePort()}
+		members := []string{"127.0.0.1:" + ports[0], "127.0.0.1:" + ports[1]}
+		rootDirs := []string{t.TempDir(), t.TempDir()}
+
+		// find a repository moving to the new member and one staying on the first member
+		clusterConfig := &config.ClusterConfig{Members: members, HashKey: hashKey, Hashing: cluster.RendezvousHashing}
+		movedRepo := getRepoOwnedBy(clusterConfig, 1)
+		keptRepo := getRepoOwnedBy(clusterConfig, 0)
+
+		conf := config.New()
+		conf.HTTP.Port = ports[0]
+		conf.Cluster = &config.ClusterConfig{
+			Members: members[:1],
+			HashKey: hashKey,
+			Hashing: cluster.RendezvousHashing,
+		}
+
+		ctlr := makeController(conf, rootDirs[0])
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(ports[0])
+
+		defer cm.StopServer()
+
+		img := CreateRandomImage()
+
+		for _, repo := range []string{movedRepo, keptRepo} {
+			err := UploadImage(img, test.GetBaseURL(ports[0]), repo, "1.0")
+			So(err, ShouldBeNil)
+		}
+
+		// the new member reads the content it doesn't have yet from the previous members
+		newConf := config.New()
+		newConf.HTTP.Port = ports[1]
+		newConf.Cluster = &config.ClusterConfig{
+			Members:         members,
+			PreviousMembers: members[:1],
+			HashKey:         hashKey,
+			Hashing:         cluster.RendezvousHashing,
+		}
+
+		newCtlr := makeController(newConf, rootDirs[1])
+		newCm := test.NewControllerManager(newCtlr)
+		newCm.StartAndWait(ports[1])
+
+		defer newCm.StopServer()
+
+		// reload the members of the first member the same way the config reloader does
+		reloadedConf := config.New()
+		reloadedConf.HTTP.Port = ports[0]
+		reloadedConf.Storage.RootDirectory = rootDirs[0]
+		reloadedConf.Cluster = &config.ClusterConfig{
+			Members: members,
+			HashKey: hashKey,
+			Hashing: cluster.RendezvousHashing,
+		}
+
+		ctlr.StopBackgroundTasks()
+		ctlr.LoadNewConfig(reloadedConf)
+
+		So(ctlr.ClusterConfig().Members, ShouldResemble, members)
+		So(ctlr.ClusterConfig().PreviousMembers, ShouldResemble, members[:1])
+
+		checkImage := func(port, repo string) {
+			resp, err := resty.R().Get(fmt.Sprintf("%s/v2/%s/manifests/1.0", test.GetBaseURL(port), repo))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get(constants.DistContentDigestKey), ShouldEqual, img.DigestStr())
+
+			resp, err = resty.R().Head(fmt.Sprintf("%s/v2/%s/blobs/%s", test.GetBaseURL(port), repo,
+				img.ConfigDescriptor.Digest))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		}
+
+		// until the repository is moved, reads fall back to the previous owner
+		for _, port := range ports {
+			checkImage(port, movedRepo)
+			checkImage(port, keptRepo)
+		}
+
+		resp, err := resty.R().Get(fmt.Sprintf("%s/v2/%s/manifests/2.0", test.GetBaseURL(ports[1]), movedRepo))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		ctlr.StartBackgroundTasks()
+
+		// the repository is moved to the new member by the rebalance task
+		movedRepoDirs := []string{path.Join(rootDirs[0], movedRepo), path.Join(rootDirs[1], movedRepo)}
+
+		for range 60 {
+			_, oldErr := os.Stat(movedRepoDirs[0])
+			_, newErr := os.Stat(movedRepoDirs[1])
+
+			if os.IsNotExist(oldErr) && newErr == nil {
+				break
+			}
+
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		_, err = os.Stat(movedRepoDirs[0])
+		So(os.IsNotExist(err), ShouldBeTrue)
+		_, err = os.Stat(path.Join(rootDirs[1], movedRepo, "index.json"))
+		So(err, ShouldBeNil)
+		_, err = os.Stat(path.Join(rootDirs[0], keptRepo, "index.json"))
+		So(err, ShouldBeNil)
+
+		for _, port := range ports {
+			checkImage(port, movedRepo)
+			checkImage(port, keptRepo)
+		}
+	})
+}
+
 func TestPrintTracebackOnPanic(t *testing.T) {
 	Convey("Run server on unavailable port", t, func() {
 		port := test.GetFreePort()
@@ -5658,7 +5792,7 @@ func TestAuthorization(t *testing.T) {
 			client.SetCookies(resp.Cookies())
 			client.SetHeader(constants.SessionClientHeaderName, constants.SessionClientHeaderValue)
 
-			RunAuthorizationTests(t, client, baseURL, username, conf)
+			RunAuthorizationTests(t, client, baseURL, username, ctlr)
 		})
 
 		Convey("with basic auth", func() {
@@ -5677,7 +5811,7 @@ func TestAuthorization(t *testing.T) {
 			client := resty.New()
 			client.SetBasicAuth(username, password)
 
-			RunAuthorizationTests(t, client, baseURL, username, conf)
+			RunAuthorizationTests(t, client, baseURL, username, ctlr)
 		})
 	})
 }
@@ -6686,40 +6820,50 @@ func TestCrossRepoMount(t *testing.T) {
 			baseURL, constants.RoutePrefix, constants.Blobs, constants.Uploads))
 
 		// Use correct request
-		// This is correct request but it will return 202 because blob is not present in cache.
+		// dedupe is disabled, so the blob is copied from the source repository.
 		params["mount"] = string(manifestDigest)
 		postResponse, err = client.R().
 			SetBasicAuth(username, password).SetQueryParams(params).
 			Post(baseURL + "/v2/zot-c-test/blobs/uploads/")
 		So(err, ShouldBeNil)
-		So(postResponse.StatusCode(), ShouldEqual, http.StatusAccepted)
-		So(test.Location(baseURL, postResponse), ShouldStartWith, fmt.Sprintf("%s%s/zot-c-test/%s/%s",
-			baseURL, constants.RoutePrefix, constants.Blobs, constants.Uploads))
+		So(postResponse.StatusCode(), ShouldEqual, http.StatusCreated)
+		So(test.Location(baseURL, postResponse), ShouldEqual, fmt.Sprintf("%s%s/zot-c-test/%s/%s",
+			baseURL, constants.RoutePrefix, constants.Blobs, manifestDigest))
+		So(postResponse.Header().Get(constants.DistContentDigestKey), ShouldEqual, manifestDigest.String())
+
+		headResponse, err = client.R().SetBasicAuth(username, password).
+			Head(fmt.Sprintf("%s/v2/zot-c-test/blobs/%s", baseURL, manifestDigest))
+		So(err, ShouldBeNil)
+		So(headResponse.StatusCode(), ShouldEqual, http.StatusOK)
 
 		// Send same request again
 		postResponse, err = client.R().
 			SetBasicAuth(username, password).SetQueryParams(params).
 			Post(baseURL + "/v2/zot-c-test/blobs/uploads/")
 		So(err, ShouldBeNil)
-		So(postResponse.StatusCode(), ShouldEqual, http.StatusAccepted)
+		So(postResponse.StatusCode(), ShouldEqual, http.StatusCreated)
 
 		// Valid requests
 		postResponse, err = client.R().
 			SetBasicAuth(username, password).SetQueryParams(params).
 			Post(baseURL + "/v2/zot-d-test/blobs/uploads/")
 		So(err, ShouldBeNil)
-		So(postResponse.StatusCode(), ShouldEqual, http.StatusAccepted)
+		So(postResponse.StatusCode(), ShouldEqual, http.StatusCreated)
 
 		headResponse, err = client.R().SetBasicAuth(username, password).
 			Head(fmt.Sprintf("%s/v2/zot-cv-test/blobs/%s", baseURL, manifestDigest))
 		So(err, ShouldBeNil)
 		So(headResponse.StatusCode(), ShouldEqual, http.StatusNotFound)
 
+		// blob missing from the source repository
+		params["from"] = "zot-cv-test"
 		postResponse, err = client.R().
-			SetBasicAuth(username, password).SetQueryParams(params).Post(baseURL + "/v2/zot-c-test/blobs/uploads/")
+			SetBasicAuth(username, password).SetQueryParams(params).Post(baseURL + "/v2/zot-e-test/blobs/uploads/")
 		So(err, ShouldBeNil)
 		So(postResponse.StatusCode(), ShouldEqual, http.StatusAccepted)
 
+		params["from"] = name
+
 		postResponse, err = client.R().
 			SetBasicAuth(username, password).SetQueryParams(params).
 			Post(baseURL + "/v2/ /blobs/uploads/")
@@ -6825,6 +6969,88 @@ func TestCrossRepoMount(t *testing.T) {
 		So(postResponse.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
 	})
 
+	Convey("Cross Repo Mount requires read access on the source repository", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		username, _ := test.GenerateRandomString()
+		password, _ := test.GenerateRandomString()
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(username, password))
+
+		defer os.Remove(htpasswdPath)
+
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"public/**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{username},
+							Actions: []string{"read"},
+						},
+					},
+				},
+				"mine/**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{username},
+							Actions: []string{"read", "create"},
+						},
+					},
+				},
+			},
+		}
+
+		dir := t.TempDir()
+		ctlr := makeController(conf, dir)
+
+		image := CreateDefaultImage()
+		storeController := ociutils.GetDefaultStoreController(dir, ctlr.Log)
+
+		err := WriteImageToFileSystem(image, "public/repo", "test", storeController)
+		So(err, ShouldBeNil)
+
+		err = WriteImageToFileSystem(image, "private/repo", "test", storeController)
+		So(err, ShouldBeNil)
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		client := resty.New()
+
+		params := map[string]string{
+			"mount": image.ConfigDescriptor.Digest.String(),
+			"from":  "private/repo",
+		}
+
+		postResponse, err := client.R().
+			SetBasicAuth(username, password).SetQueryParams(params).
+			Post(baseURL + "/v2/mine/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(postResponse.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		params["from"] = "public/repo"
+
+		postResponse, err = client.R().
+			SetBasicAuth(username, password).SetQueryParams(params).
+			Post(baseURL + "/v2/mine/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(postResponse.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		headResponse, err := client.R().SetBasicAuth(username, password).
+			Head(fmt.Sprintf("%s/v2/mine/repo/blobs/%s", baseURL, image.ConfigDescriptor.Digest))
+		So(err, ShouldBeNil)
+		So(headResponse.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+
 	Convey("Disable dedupe and cache", t, func() {
 		port := test.GetFreePort()
 		baseURL := test.GetBaseURL(port)
@@ -10948,6 +11174,215 @@ func TestManifestCollision(t *testing.T) {
 	})
 }
 
+func TestImmutableTags(t *testing.T) {
+	Convey("Make a new controller", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		dir := t.TempDir()
+		ctlr := makeController(conf, dir)
+
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				test.AuthorizationAllRepos: config.PolicyGroup{
+					AnonymousPolicy: []string{
+						constants.ReadPermission,
+						constants.CreatePermission,
+						constants.UpdatePermission,
+						constants.DeletePermission,
+					},
+				},
+			},
+			ImmutableTags: []config.ImmutableTagsPolicy{
+				{
+					Repositories: []string{"releases/**"},
+					Patterns:     []string{`^v\d+\.\d+\.\d+$`},
+				},
+			},
+		}
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		img1 := CreateRandomImage()
+		img2 := CreateRandomImage()
+
+		err := UploadImage(img1, baseURL, "releases/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		// idempotent re-push of the same manifest
+		err = UploadImage(img1, baseURL, "releases/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		// tags not matching the patterns can be moved
+		err = UploadImage(img2, baseURL, "releases/app", "latest")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(img1, baseURL, "releases/app", "latest")
+		So(err, ShouldBeNil)
+
+		// same tag in a repository not matching the policy
+		err = UploadImage(img1, baseURL, "dev/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(img2, baseURL, "dev/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		manifestBlob, err := json.Marshal(img2.Manifest)
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetHeader("Content-Type", ispec.MediaTypeImageManifest).
+			SetBody(manifestBlob).Put(baseURL + "/v2/releases/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		resp, err = resty.R().Delete(baseURL + "/v2/releases/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		// deleting by digest would remove the immutable tag too
+		resp, err = resty.R().Delete(baseURL + "/v2/releases/app/manifests/" + img1.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(baseURL + "/v2/releases/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(resp.Header().Get(constants.DistContentDigestKey), ShouldEqual, img1.DigestStr())
+
+		// tags not matching the patterns can be deleted
+		resp, err = resty.R().Delete(baseURL + "/v2/releases/app/manifests/latest")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		Convey("Users with the override permission can change immutable tags", func() {
+			repoPolicy := conf.HTTP.AccessControl.Repositories[test.AuthorizationAllRepos]
+			repoPolicy.AnonymousPolicy = append(repoPolicy.AnonymousPolicy, constants.OverrideImmutableTagsPermission)
+			conf.HTTP.AccessControl.Repositories[test.AuthorizationAllRepos] = repoPolicy
+
+			err = UploadImage(img2, baseURL, "releases/app", "v1.0.0")
+			So(err, ShouldBeNil)
+
+			resp, err = resty.R().Delete(baseURL + "/v2/releases/app/manifests/v1.0.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+		})
+	})
+
+	Convey("Immutable tags of the storage config are enforced without authorization", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.ImmutableTags = []config.ImmutableTagsPolicy{
+			{
+				Repositories: []string{"releases/**"},
+				Patterns:     []string{`^v\d+\.\d+\.\d+$`},
+			},
+		}
+
+		dir := t.TempDir()
+		ctlr := makeController(conf, dir)
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		img1 := CreateRandomImage()
+		img2 := CreateRandomImage()
+
+		err := UploadImage(img1, baseURL, "releases/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(img1, baseURL, "releases/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		manifestBlob, err := json.Marshal(img2.Manifest)
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetHeader("Content-Type", ispec.MediaTypeImageManifest).
+			SetBody(manifestBlob).Put(baseURL + "/v2/releases/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		resp, err = resty.R().Delete(baseURL + "/v2/releases/app/manifests/" + img1.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		err = UploadImage(img2, baseURL, "dev/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().Delete(baseURL + "/v2/dev/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+	})
+
+	Convey("Immutable tags of a subPath are enforced on its repositories", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		dir := t.TempDir()
+		ctlr := makeController(conf, dir)
+
+		ctlr.Config.Storage.SubPaths = map[string]config.StorageConfig{
+			"/releases": {
+				RootDirectory: t.TempDir(),
+				ImmutableTags: []config.ImmutableTagsPolicy{
+					{
+						Repositories: []string{"**"},
+						Patterns:     []string{`^v\d+\.\d+\.\d+$`},
+					},
+				},
+			},
+			"/dev": {
+				RootDirectory: t.TempDir(),
+			},
+		}
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		img1 := CreateRandomImage()
+		img2 := CreateRandomImage()
+
+		err := UploadImage(img1, baseURL, "releases/app", "v1.0.0")
+		So(err, ShouldBeNil)
+
+		manifestBlob, err := json.Marshal(img2.Manifest)
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetHeader("Content-Type", ispec.MediaTypeImageManifest).
+			SetBody(manifestBlob).Put(baseURL + "/v2/releases/app/manifests/v1.0.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		// the policies of a subPath don't apply to the repositories of the other stores
+		for _, repo := range []string{"dev/app", "app"} {
+			err = UploadImage(img1, baseURL, repo, "v1.0.0")
+			So(err, ShouldBeNil)
+
+			err = UploadImage(img2, baseURL, repo, "v1.0.0")
+			So(err, ShouldBeNil)
+
+			resp, err = resty.R().Delete(baseURL + "/v2/" + repo + "/manifests/v1.0.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+		}
+	})
+}
+
 func TestPullRange(t *testing.T) {
 	Convey("Make a new controller", t, func() {
 		port := test.GetFreePort()
@@ -12873,6 +13308,19 @@ func getAllManifests(imagePath string) []string {
 	return manifestList
 }
 
+// returns the name of a repository owned by the cluster member at the given index.
+func getRepoOwnedBy(clusterConfig *config.ClusterConfig, memberIdx uint64) string {
+	for idx := 0; ; idx++ {
+		repoName := fmt.Sprintf("repo%d", idx)
+
+		targetIdx, _ := cluster.ComputeTargetMemberWithHashing(clusterConfig.Hashing, clusterConfig.HashKey,
+			clusterConfig.Members, repoName)
+		if targetIdx == memberIdx {
+			return repoName
+		}
+	}
+}
+
 func makeController(conf *config.Config, dir string) *api.Controller {
 	ctlr := api.NewController(conf)
 
@@ -13045,9 +13493,11 @@ func RunAuthorizationWithMultiplePoliciesTests(t *testing.T, userClient *resty.C
 	So(len(catalog.Repositories), ShouldEqual, 0)
 }
 
-func RunAuthorizationTests(t *testing.T, client *resty.Client, baseURL, user string, conf *config.Config) {
+func RunAuthorizationTests(t *testing.T, client *resty.Client, baseURL, user string, ctlr *api.Controller) {
 	t.Helper()
 
+	conf := ctlr.Config
+
 	Convey("run authorization tests", func() {
 		blob := []byte("hello, blob!")
 		digest := godigest.FromBytes(blob).String()
@@ -13534,7 +13984,14 @@ func RunAuthorizationTests(t *testing.T, client *resty.Client, baseURL, user str
 		So(resp, ShouldNotBeNil)
 		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
 
-		conf.HTTP.AccessControl = &config.AccessControlConfig{}
+		// reload the config with an access control section granting nothing
+		newConf := config.New()
+		newConf.HTTP = conf.HTTP
+		newConf.HTTP.AccessControl = &config.AccessControlConfig{}
+		newConf.Storage = conf.Storage
+		newConf.Extensions = conf.Extensions
+
+		ctlr.LoadNewConfig(newConf)
 
 		resp, err = client.R().
 			SetHeader("Content-type", "application/vnd.oci.image.manifest.v1+json").
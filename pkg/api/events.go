@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zcommon "zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/storage"
+)
+
+// recordManifestPushed records the events of a pushed signature or referrer,
+// the image itself is recorded by the image store.
+func (rh *RouteHandler) recordManifestPushed(name, reference, mediaType string, digest,
+	subjectDigest godigest.Digest, body []byte,
+) {
+	if rh.c.EventRecorder == nil || zcommon.IsReferrersTag(reference) {
+		return
+	}
+
+	isSignature, signatureType, signedDigest, err := storage.CheckIsImageSignature(name, body, reference)
+	if err != nil {
+		return
+	}
+
+	if isSignature {
+		rh.c.EventRecorder.SignatureAdded(name, signedDigest.String(), digest.String(), signatureType)
+
+		return
+	}
+
+	if subjectDigest == "" {
+		return
+	}
+
+	rh.c.EventRecorder.ReferrerAdded(name, subjectDigest.String(), digest.String(), getArtifactType(mediaType, body))
+}
+
+// recordManifestDeleted records the events of a deleted signature,
+// the image itself is recorded by the image store.
+func (rh *RouteHandler) recordManifestDeleted(name, reference string, digest godigest.Digest, body []byte) {
+	if rh.c.EventRecorder == nil || zcommon.IsReferrersTag(reference) {
+		return
+	}
+
+	isSignature, signatureType, signedDigest, err := storage.CheckIsImageSignature(name, body, reference)
+	if err != nil || !isSignature {
+		return
+	}
+
+	rh.c.EventRecorder.SignatureDeleted(name, signedDigest.String(), digest.String(), signatureType)
+}
+
+func getArtifactType(mediaType string, body []byte) string {
+	switch mediaType {
+	case ispec.MediaTypeImageManifest:
+		var manifest ispec.Manifest
+
+		if err := json.Unmarshal(body, &manifest); err == nil {
+			return zcommon.GetManifestArtifactType(manifest)
+		}
+	case ispec.MediaTypeImageIndex:
+		var index ispec.Index
+
+		if err := json.Unmarshal(body, &index); err == nil {
+			return zcommon.GetIndexArtifactType(index)
+		}
+	}
+
+	return ""
+}
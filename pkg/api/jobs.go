@@ -0,0 +1,477 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	apiErr "zotregistry.dev/zot/pkg/api/errors"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/scheduler"
+	"zotregistry.dev/zot/pkg/storage"
+	"zotregistry.dev/zot/pkg/storage/gc"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+const (
+	// job types, there is no retention job as the retention policies are applied by gc jobs,
+	// the same way the periodic gc applies them.
+	GCJob     = "gc"
+	ScrubJob  = "scrub"
+	DedupeJob = "dedupe"
+	// job statuses.
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+
+	// finished jobs are dropped, oldest first, once there are more of them.
+	maxFinishedJobs = 100
+)
+
+type JobPayload struct {
+	Type string `json:"type"`
+	// if empty the job runs on all the repositories of all the image stores
+	Repository string `json:"repository,omitempty"`
+	// low (default), medium or high
+	Priority string `json:"priority,omitempty"`
+}
+
+type Job struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Repository string    `json:"repository,omitempty"`
+	Priority   string    `json:"priority"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// unset until the job starts
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// unset until the job finishes
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// repositories processed by gc and scrub jobs
+	Repositories []string `json:"repositories,omitempty"`
+	// number of digests processed by dedupe jobs
+	Digests      int                        `json:"digests,omitempty"`
+	ScrubResults []storage.ScrubImageResult `json:"scrubResults,omitempty"`
+}
+
+type JobList struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// jobManager keeps track of the admin jobs, which are run by the scheduler as a single task each.
+type jobManager struct {
+	lock sync.RWMutex
+	jobs map[string]*Job
+}
+
+func newJobManager() *jobManager {
+	return &jobManager{jobs: make(map[string]*Job)}
+}
+
+func (jm *jobManager) add(job *Job) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+
+	jm.jobs[job.ID] = job
+
+	finished := make([]*Job, 0)
+
+	for _, job := range jm.jobs {
+		if job.Status == JobSucceeded || job.Status == JobFailed {
+			finished = append(finished, job)
+		}
+	}
+
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(*finished[j].FinishedAt)
+	})
+
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(jm.jobs, job.ID)
+	}
+}
+
+// returns a copy of the job, so it can be read while the job is running.
+func (jm *jobManager) get(id string) (Job, bool) {
+	jm.lock.RLock()
+	defer jm.lock.RUnlock()
+
+	job, ok := jm.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	return *job, true
+}
+
+func (jm *jobManager) list() []Job {
+	jm.lock.RLock()
+	defer jm.lock.RUnlock()
+
+	jobs := make([]Job, 0, len(jm.jobs))
+
+	for _, job := range jm.jobs {
+		jobs = append(jobs, *job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+func (jm *jobManager) remove(id string) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+
+	delete(jm.jobs, id)
+}
+
+func (jm *jobManager) update(id string, updateFn func(job *Job)) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+
+	if job, ok := jm.jobs[id]; ok {
+		updateFn(job)
+	}
+}
+
+func parsePriority(priority string) (scheduler.Priority, error) {
+	for _, schPriority := range []scheduler.Priority{
+		scheduler.LowPriority, scheduler.MediumPriority, scheduler.HighPriority,
+	} {
+		if priority == schPriority.String() {
+			return schPriority, nil
+		}
+	}
+
+	return scheduler.LowPriority, fmt.Errorf("%w: %s", zerr.ErrBadJobPriority, priority)
+}
+
+// CreateJob godoc
+// @Summary Run gc, scrub or dedupe on demand
+// @Description Enqueue a gc, scrub or dedupe job for a repository, or for all the repositories if none is given.
+// @Accept  json
+// @Produce json
+// @Param   job  body  JobPayload  true  "job type, repository and priority"
+// @Success 202 {object} api.Job
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 503 {string} string "service unavailable"
+// @Router  /zot/admin/jobs  [post].
+func (rh *RouteHandler) CreateJob(response http.ResponseWriter, request *http.Request) {
+	var payload JobPayload
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to read request body")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if payload.Type != GCJob && payload.Type != ScrubJob && payload.Type != DedupeJob {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if payload.Priority == "" {
+		payload.Priority = scheduler.LowPriority.String()
+	}
+
+	priority, err := parsePriority(payload.Priority)
+	if err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if payload.Repository != "" {
+		imgStore := rh.c.StoreController.GetImageStore(payload.Repository)
+
+		if ok, err := imgStore.ValidateRepo(payload.Repository); err != nil || !ok {
+			zcommon.WriteJSON(response, http.StatusNotFound,
+				apiErr.NewErrorList(apiErr.NewError(apiErr.NAME_UNKNOWN).AddDetail(map[string]string{
+					"name": payload.Repository,
+				})))
+
+			return
+		}
+	}
+
+	if rh.c.taskScheduler == nil {
+		response.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	job := &Job{
+		ID:         uuid.New().String(),
+		Type:       payload.Type,
+		Repository: payload.Repository,
+		Priority:   priority.String(),
+		Status:     JobQueued,
+		CreatedAt:  time.Now(),
+	}
+
+	rh.c.jobs.add(job)
+
+	err = rh.c.taskScheduler.TrySubmitTask(&jobTask{ctrlr: rh.c, jobID: job.ID, jobType: job.Type,
+		repo: job.Repository}, priority)
+	if err != nil {
+		// the task was dropped, so the job would stay queued forever
+		rh.c.jobs.remove(job.ID)
+
+		rh.c.Log.Error().Err(err).Str("type", job.Type).Str(constants.RepositoryLogKey, job.Repository).
+			Str("priority", job.Priority).Msg("failed to enqueue admin job")
+
+		response.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	rh.c.Log.Info().Str("id", job.ID).Str("type", job.Type).Str(constants.RepositoryLogKey, job.Repository).
+		Str("priority", job.Priority).Msg("admin job enqueued")
+
+	createdJob, _ := rh.c.jobs.get(job.ID)
+
+	zcommon.WriteJSON(response, http.StatusAccepted, createdJob)
+}
+
+// GetJob godoc
+// @Summary Get the status of a job
+// @Description Get the status and the results of a gc, scrub or dedupe job.
+// @Produce json
+// @Param   id  path  string  true  "job id"
+// @Success 200 {object} api.Job
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Router  /zot/admin/jobs/{id}  [get].
+func (rh *RouteHandler) GetJob(response http.ResponseWriter, request *http.Request) {
+	job, ok := rh.c.jobs.get(mux.Vars(request)["id"])
+	if !ok {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, job)
+}
+
+// ListJobs godoc
+// @Summary List jobs
+// @Description List the queued, running and recently finished jobs.
+// @Produce json
+// @Success 200 {object} api.JobList
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Router  /zot/admin/jobs  [get].
+func (rh *RouteHandler) ListJobs(response http.ResponseWriter, request *http.Request) {
+	zcommon.WriteJSON(response, http.StatusOK, JobList{Jobs: rh.c.jobs.list()})
+}
+
+// jobTask runs an admin job on a repository, or on all the repositories of all the image stores.
+type jobTask struct {
+	ctrlr   *Controller
+	jobID   string
+	jobType string
+	repo    string
+}
+
+func (task *jobTask) DoWork(ctx context.Context) error {
+	task.ctrlr.jobs.update(task.jobID, func(job *Job) {
+		job.Status = JobRunning
+		startedAt := time.Now()
+		job.StartedAt = &startedAt
+	})
+
+	err := task.run(ctx)
+
+	task.ctrlr.jobs.update(task.jobID, func(job *Job) {
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = JobSucceeded
+		}
+	})
+
+	if err != nil {
+		task.ctrlr.Log.Error().Err(err).Str("id", task.jobID).Str("type", task.jobType).
+			Str(constants.RepositoryLogKey, task.repo).Msg("admin job failed")
+	} else {
+		task.ctrlr.Log.Info().Str("id", task.jobID).Str("type", task.jobType).
+			Str(constants.RepositoryLogKey, task.repo).Msg("admin job finished")
+	}
+
+	return err
+}
+
+// runs the job on each image store, along with the storage config applying to it.
+func (task *jobTask) run(ctx context.Context) error {
+	storeConfig := task.ctrlr.Config.Storage
+
+	if task.repo != "" {
+		route := task.ctrlr.StoreController.GetStorePath(task.repo)
+		if route != storage.DefaultStorePath {
+			return task.runOnStore(ctx, task.ctrlr.StoreController.SubStore[route], storeConfig.SubPaths[route],
+				[]string{task.repo})
+		}
+
+		return task.runOnStore(ctx, task.ctrlr.StoreController.DefaultStore, storeConfig.StorageConfig,
+			[]string{task.repo})
+	}
+
+	if err := task.runOnStore(ctx, task.ctrlr.StoreController.DefaultStore, storeConfig.StorageConfig,
+		nil); err != nil {
+		return err
+	}
+
+	routes := make([]string, 0, len(task.ctrlr.StoreController.SubStore))
+	for route := range task.ctrlr.StoreController.SubStore {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		if err := task.runOnStore(ctx, task.ctrlr.StoreController.SubStore[route], storeConfig.SubPaths[route],
+			nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (task *jobTask) runOnStore(ctx context.Context, imgStore storageTypes.ImageStore,
+	storeConfig config.StorageConfig, repos []string,
+) error {
+	if imgStore == nil {
+		return nil
+	}
+
+	var err error
+
+	if repos == nil {
+		repos, err = imgStore.GetRepositories()
+		if err != nil {
+			return err
+		}
+	}
+
+	if task.jobType == DedupeJob {
+		return task.dedupe(ctx, imgStore, storeConfig.Dedupe, repos)
+	}
+
+	garbageCollect := gc.NewGarbageCollect(imgStore, task.ctrlr.MetaDB, gc.Options{
+		Delay:          storeConfig.GCDelay,
+		ImageRetention: storeConfig.Retention,
+		Events:         task.ctrlr.EventRecorder,
+	}, task.ctrlr.Audit, task.ctrlr.Log)
+
+	for _, repo := range repos {
+		if zcommon.IsContextDone(ctx) {
+			return ctx.Err()
+		}
+
+		var results []storage.ScrubImageResult
+
+		switch task.jobType {
+		case GCJob:
+			err = garbageCollect.CleanRepo(ctx, repo)
+		case ScrubJob:
+			results, err = storage.CheckRepo(ctx, repo, imgStore)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path.Join(imgStore.RootDir(), repo), err)
+		}
+
+		task.ctrlr.jobs.update(task.jobID, func(job *Job) {
+			job.Repositories = append(job.Repositories, repo)
+			job.ScrubResults = append(job.ScrubResults, results...)
+		})
+	}
+
+	return nil
+}
+
+// dedupes (or restores, depending on the storage config) the blobs of the repos, one digest at a time.
+func (task *jobTask) dedupe(ctx context.Context, imgStore storageTypes.ImageStore, dedupe bool,
+	repos []string,
+) error {
+	if len(repos) == 0 {
+		return nil
+	}
+
+	processedDigests := []godigest.Digest{}
+
+	for {
+		if zcommon.IsContextDone(ctx) {
+			return ctx.Err()
+		}
+
+		digest, duplicateBlobs, err := imgStore.GetNextDigestWithBlobPaths(repos, processedDigests)
+		if err != nil {
+			return err
+		}
+
+		if digest == "" {
+			return nil
+		}
+
+		processedDigests = append(processedDigests, digest)
+
+		if err := imgStore.RunDedupeForDigest(ctx, digest, dedupe, duplicateBlobs); err != nil &&
+			!errors.Is(err, context.Canceled) {
+			return fmt.Errorf("%s: %w", digest, err)
+		}
+
+		task.ctrlr.jobs.update(task.jobID, func(job *Job) {
+			job.Digests++
+		})
+	}
+}
+
+func (task *jobTask) String() string {
+	return fmt.Sprintf("{Name: %s, id: %s, type: %s, repository: %s}", task.Name(), task.jobID, task.jobType, task.repo)
+}
+
+func (task *jobTask) Name() string {
+	return "AdminJobTask"
+}
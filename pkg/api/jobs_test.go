@@ -0,0 +1,248 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+func TestAdminJobs(t *testing.T) {
+	Convey("Run gc, scrub and dedupe jobs on demand", t, func() {
+		adminUser, adminPassword := "admin", "admin"
+		user, password := "user", "user"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n" +
+			test.GetCredString(user, password))
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth.HTPasswd.Path = htpasswdPath
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				test.AuthorizationAllRepos: config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{user},
+							Actions: []string{constants.ReadPermission},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.GC = false
+		conf.Storage.SubPaths = map[string]config.StorageConfig{
+			"/a": {RootDirectory: t.TempDir(), Dedupe: true},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		for _, repo := range []string{"alpine", "a/alpine"} {
+			err := UploadImageWithBasicAuth(image, baseURL, repo, "latest", adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		jobsURL := baseURL + constants.AdminJobsPath
+
+		waitForJob := func(jobID string) api.Job {
+			job := api.Job{}
+
+			for range 100 {
+				resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(jobsURL + "/" + jobID)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+				err = json.Unmarshal(resp.Body(), &job)
+				So(err, ShouldBeNil)
+
+				if job.Status == api.JobSucceeded || job.Status == api.JobFailed {
+					break
+				}
+
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			return job
+		}
+
+		submitJob := func(payload string) (*resty.Response, api.Job) {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).SetBody(payload).Post(jobsURL)
+			So(err, ShouldBeNil)
+
+			job := api.Job{}
+
+			if resp.StatusCode() == http.StatusAccepted {
+				err = json.Unmarshal(resp.Body(), &job)
+				So(err, ShouldBeNil)
+			}
+
+			return resp, job
+		}
+
+		Convey("Only admins can run jobs", func() {
+			resp, err := resty.R().SetBasicAuth(user, password).SetBody(`{"type": "gc"}`).Post(jobsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().SetBasicAuth(user, password).Get(jobsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().Get(jobsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Invalid jobs", func() {
+			resp, _ := submitJob(`{"type": "unknown"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, _ = submitJob(`{"type": "gc", "priority": "urgent"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, _ = submitJob(`not json`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, _ = submitJob(`{"type": "gc", "repository": "missing"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(jobsURL + "/missing")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Scrub a single repository", func() {
+			resp, job := submitJob(`{"type": "scrub", "repository": "a/alpine", "priority": "high"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+			So(job.ID, ShouldNotBeEmpty)
+			So(job.Priority, ShouldEqual, "high")
+
+			job = waitForJob(job.ID)
+			So(job.Status, ShouldEqual, api.JobSucceeded)
+			So(job.StartedAt, ShouldNotBeNil)
+			So(job.FinishedAt, ShouldNotBeNil)
+			So(job.FinishedAt.Before(*job.StartedAt), ShouldBeFalse)
+			So(job.Repositories, ShouldResemble, []string{"a/alpine"})
+			So(len(job.ScrubResults), ShouldEqual, 1)
+			So(job.ScrubResults[0].ImageName, ShouldEqual, "a/alpine")
+			So(job.ScrubResults[0].Tag, ShouldEqual, "latest")
+			So(job.ScrubResults[0].Status, ShouldEqual, "ok")
+		})
+
+		Convey("Run gc and dedupe on all the repositories", func() {
+			resp, gcJob := submitJob(`{"type": "gc"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+			So(gcJob.Priority, ShouldEqual, "low")
+
+			resp, dedupeJob := submitJob(`{"type": "dedupe", "priority": "medium"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+			gcJob = waitForJob(gcJob.ID)
+			So(gcJob.Status, ShouldEqual, api.JobSucceeded)
+			So(gcJob.Repositories, ShouldResemble, []string{"alpine", "a/alpine"})
+
+			dedupeJob = waitForJob(dedupeJob.ID)
+			So(dedupeJob.Status, ShouldEqual, api.JobSucceeded)
+			So(dedupeJob.Digests, ShouldBeGreaterThan, 0)
+
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(jobsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			jobs := api.JobList{}
+			err = json.Unmarshal(resp.Body(), &jobs)
+			So(err, ShouldBeNil)
+			So(len(jobs.Jobs), ShouldEqual, 2)
+			So(jobs.Jobs[0].ID, ShouldEqual, gcJob.ID)
+			So(jobs.Jobs[1].ID, ShouldEqual, dedupeJob.ID)
+		})
+
+		Convey("Jobs which can't be enqueued are not created", func() {
+			ctlr.StopBackgroundTasks()
+
+			resp, _ := submitJob(`{"type": "gc"}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusServiceUnavailable)
+
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(jobsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			jobs := api.JobList{}
+			err = json.Unmarshal(resp.Body(), &jobs)
+			So(err, ShouldBeNil)
+			So(jobs.Jobs, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestAdminJobsWithoutAdminPolicy(t *testing.T) {
+	Convey("The jobs api is not available if admins can't be told apart from the other users", t, func() {
+		username, password := "user", "user"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(username, password))
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+
+		Convey("Without authentication", func() {
+			ctlr := api.NewController(conf)
+
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			resp, err := resty.R().SetBody(`{"type": "gc"}`).Post(baseURL + constants.AdminJobsPath)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Without an admin policy", func() {
+			conf.HTTP.Auth.HTPasswd.Path = htpasswdPath
+
+			ctlr := api.NewController(conf)
+
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			resp, err := resty.R().SetBasicAuth(username, password).SetBody(`{"type": "gc"}`).
+				Post(baseURL + constants.AdminJobsPath)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().SetBasicAuth(username, password).Get(baseURL + constants.AdminJobsPath)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
@@ -3,18 +3,31 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
+	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	"zotregistry.dev/zot/pkg/cluster"
 	"zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
 )
 
+// clock skew tolerated between the members checking the signature of the requests they send to each other.
+const clusterRequestMaxAge = 5 * time.Minute
+
 // ClusterProxy wraps an http.HandlerFunc which requires proxying between zot instances to ensure
 // that a given repository only has a single writer and reader for dist-spec operations in a scale-out cluster.
 // based on the hash value of the repository name, the request will either be handled locally
@@ -22,16 +35,23 @@ import (
 func ClusterProxy(ctrlr *Controller) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
-			config := ctrlr.Config
+			clusterConfig := ctrlr.ClusterConfig()
 			logger := ctrlr.Log
 
 			// if no cluster or single-node cluster, handle locally.
-			if config.Cluster == nil || len(config.Cluster.Members) == 1 {
+			if clusterConfig == nil || len(clusterConfig.Members) == 1 {
 				next.ServeHTTP(response, request)
 
 				return
 			}
 
+			// requests claiming to be proxied by another member without a valid signature are sent by clients,
+			// they are proxied to the owner of the repository as any other request
+			if !isClusterRequest(clusterConfig, request) {
+				request.Header.Del(constants.ScaleOutHopCountHeader)
+				request.Header.Del(constants.ScaleOutSignatureHeader)
+			}
+
 			// since the handler has been wrapped, it should be possible to get the name
 			// of the repository from the mux.
 			vars := mux.Vars(request)
@@ -45,14 +65,25 @@ func ClusterProxy(ctrlr *Controller) func(http.HandlerFunc) http.HandlerFunc {
 
 			// the target member is the only one which should do read/write for the dist-spec APIs
 			// for the given repository.
-			targetMemberIndex, targetMember := cluster.ComputeTargetMember(config.Cluster.HashKey, config.Cluster.Members, name)
+			targetMemberIndex, targetMember := cluster.ComputeTargetMemberWithHashing(clusterConfig.Hashing,
+				clusterConfig.HashKey, clusterConfig.Members, name)
 			logger.Debug().Str(constants.RepositoryLogKey, name).
 				Msg(fmt.Sprintf("target member socket: %s index: %d", targetMember, targetMemberIndex))
 
 			// if the target member is the same as the local member, the current member should handle the request.
 			// since the instances have the same config, a quick index lookup is sufficient
-			if targetMemberIndex == config.Cluster.Proxy.LocalMemberClusterSocketIndex {
+			if targetMemberIndex == clusterConfig.Proxy.LocalMemberClusterSocketIndex {
 				logger.Debug().Str(constants.RepositoryLogKey, name).Msg("handling the request locally")
+
+				// until the repository is moved here, reads from clients fall back to its previous owner
+				previousMember := getPreviousTargetMember(ctrlr, clusterConfig, name)
+				if isFallbackAllowed(request) && previousMember != "" &&
+					previousMember != clusterConfig.Proxy.LocalMemberClusterSocket {
+					serveWithFallback(ctrlr, clusterConfig, next, response, request, previousMember)
+
+					return
+				}
+
 				next.ServeHTTP(response, request)
 
 				return
@@ -60,15 +91,25 @@ func ClusterProxy(ctrlr *Controller) func(http.HandlerFunc) http.HandlerFunc {
 
 			// if the header contains a hop-count, return an error response as there should be no multi-hop
 			if request.Header.Get(constants.ScaleOutHopCountHeader) != "" {
-				logger.Fatal().Str("url", request.URL.String()).
+				// the request is a read falling back to the local member as the previous owner of the repository
+				if getPreviousTargetMember(ctrlr, clusterConfig, name) == clusterConfig.Proxy.LocalMemberClusterSocket {
+					logger.Debug().Str(constants.RepositoryLogKey, name).Msg("handling the request as previous owner")
+					next.ServeHTTP(response, request)
+
+					return
+				}
+
+				// members may briefly disagree on the owner of a repository while a membership change is reloaded
+				logger.Error().Str("url", request.URL.String()).
 					Msg("failed to process request - cannot proxy an already proxied request")
+				http.Error(response, "cannot proxy an already proxied request", http.StatusMisdirectedRequest)
 
 				return
 			}
 
 			logger.Debug().Str(constants.RepositoryLogKey, name).Msg("proxying the request")
 
-			proxyResponse, err := proxyHTTPRequest(request.Context(), request, targetMember, ctrlr)
+			proxyResponse, err := proxyHTTPRequest(request.Context(), request, targetMember, ctrlr, clusterConfig)
 			if err != nil {
 				logger.Error().Err(err).Str(constants.RepositoryLogKey, name).Msg("failed to proxy the request")
 				http.Error(response, err.Error(), http.StatusInternalServerError)
@@ -77,13 +118,149 @@ func ClusterProxy(ctrlr *Controller) func(http.HandlerFunc) http.HandlerFunc {
 			}
 			defer proxyResponse.Body.Close()
 
-			copyHeader(response.Header(), proxyResponse.Header)
-			response.WriteHeader(proxyResponse.StatusCode)
-			_, _ = io.Copy(response, proxyResponse.Body)
+			// until the repository is moved to the target member, reads from clients fall back to its previous owner
+			if proxyResponse.StatusCode == http.StatusNotFound && isFallbackAllowed(request) {
+				previousMember := getPreviousTargetMember(ctrlr, clusterConfig, name)
+
+				if previousMember == clusterConfig.Proxy.LocalMemberClusterSocket {
+					logger.Debug().Str(constants.RepositoryLogKey, name).
+						Msg("content not found on the target member, handling the request as previous owner")
+					next.ServeHTTP(response, request)
+
+					return
+				}
+
+				if previousMember != "" && previousMember != targetMember {
+					fallbackResponse := proxyToPreviousOwner(ctrlr, clusterConfig, request, previousMember)
+					if fallbackResponse != nil {
+						defer fallbackResponse.Body.Close()
+
+						proxyResponse = fallbackResponse
+					}
+				}
+			}
+
+			writeProxyResponse(response, proxyResponse)
 		})
 	}
 }
 
+// returns the member which owned the repository before the last membership change.
+// returns an empty string if the cluster is not in transition or the members share the storage,
+// in which case there is nothing to fall back to.
+func getPreviousTargetMember(ctrlr *Controller, clusterConfig *config.ClusterConfig, repoName string) string {
+	if !clusterConfig.IsInTransition() || ctrlr.Config.Storage.StorageDriver != nil {
+		return ""
+	}
+
+	_, previousMember := cluster.ComputeTargetMemberWithHashing(clusterConfig.Hashing, clusterConfig.HashKey,
+		clusterConfig.PreviousMembers, repoName)
+
+	return previousMember
+}
+
+// only reads sent by clients fall back to the previous owner of a repository, the requests sent by other members,
+// e.g. to check for content while moving a repository, are answered based on the local content only.
+func isFallbackAllowed(request *http.Request) bool {
+	return (request.Method == http.MethodGet || request.Method == http.MethodHead) &&
+		request.Header.Get(constants.ScaleOutHopCountHeader) == ""
+}
+
+// proxies a read to the previous owner of the repository, which handles it locally.
+// returns nil if the previous owner can't be reached or doesn't have the content either.
+func proxyToPreviousOwner(ctrlr *Controller, clusterConfig *config.ClusterConfig, request *http.Request,
+	previousMember string,
+) *http.Response {
+	ctrlr.Log.Debug().Str(constants.RepositoryLogKey, mux.Vars(request)["name"]).Str("previousMember", previousMember).
+		Msg("content not found, falling back to the previous owner")
+
+	proxyResponse, err := proxyHTTPRequest(request.Context(), request, previousMember, ctrlr, clusterConfig)
+	if err != nil {
+		ctrlr.Log.Error().Err(err).Str("previousMember", previousMember).
+			Msg("failed to proxy the request to the previous owner")
+
+		return nil
+	}
+
+	if proxyResponse.StatusCode == http.StatusNotFound {
+		proxyResponse.Body.Close()
+
+		return nil
+	}
+
+	return proxyResponse
+}
+
+// handles a read locally and, if the repository content is not found, retries it on the previous owner
+// of the repository which may still hold content not yet moved to the local member.
+func serveWithFallback(ctrlr *Controller, clusterConfig *config.ClusterConfig, next http.HandlerFunc,
+	response http.ResponseWriter, request *http.Request, previousMember string,
+) {
+	recorder := &notFoundRecorder{ResponseWriter: response, header: http.Header{}}
+
+	next.ServeHTTP(recorder, request)
+
+	if recorder.statusCode != http.StatusNotFound {
+		return
+	}
+
+	if fallbackResponse := proxyToPreviousOwner(ctrlr, clusterConfig, request, previousMember); fallbackResponse != nil {
+		defer fallbackResponse.Body.Close()
+
+		writeProxyResponse(response, fallbackResponse)
+
+		return
+	}
+
+	copyHeader(response.Header(), recorder.header)
+	response.WriteHeader(recorder.statusCode)
+	_, _ = response.Write(recorder.body.Bytes())
+}
+
+// notFoundRecorder passes a response through to the client unless it is a not found response,
+// which is kept aside so that the request can be retried on another member first.
+type notFoundRecorder struct {
+	http.ResponseWriter
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (recorder *notFoundRecorder) Header() http.Header {
+	return recorder.header
+}
+
+func (recorder *notFoundRecorder) WriteHeader(statusCode int) {
+	if recorder.statusCode != 0 {
+		return
+	}
+
+	recorder.statusCode = statusCode
+
+	if statusCode != http.StatusNotFound {
+		copyHeader(recorder.ResponseWriter.Header(), recorder.header)
+		recorder.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (recorder *notFoundRecorder) Write(content []byte) (int, error) {
+	if recorder.statusCode == 0 {
+		recorder.WriteHeader(http.StatusOK)
+	}
+
+	if recorder.statusCode == http.StatusNotFound {
+		return recorder.body.Write(content)
+	}
+
+	return recorder.ResponseWriter.Write(content)
+}
+
+func writeProxyResponse(response http.ResponseWriter, proxyResponse *http.Response) {
+	copyHeader(response.Header(), proxyResponse.Header)
+	response.WriteHeader(proxyResponse.StatusCode)
+	_, _ = io.Copy(response, proxyResponse.Body)
+}
+
 // gets all the server sockets of a target member - IP:Port.
 // for IPv6, the socket is [IPv6]:Port.
 // if the input is an IP address, returns the same targetMember in an array.
@@ -115,18 +292,16 @@ func getTargetMemberServerSockets(targetMemberSocket string) ([]string, error) {
 
 // proxy the request to the target member and return a pointer to the response or an error.
 func proxyHTTPRequest(ctx context.Context, req *http.Request,
-	targetMember string, ctrlr *Controller,
+	targetMember string, ctrlr *Controller, clusterConfig *config.ClusterConfig,
 ) (*http.Response, error) {
 	cloneURL := *req.URL
 
-	proxyQueryScheme := "http"
-	if ctrlr.Config.HTTP.TLS != nil {
-		proxyQueryScheme = "https"
-	}
-
-	cloneURL.Scheme = proxyQueryScheme
+	cloneURL.Scheme = getClusterScheme(ctrlr)
 	cloneURL.Host = targetMember
 
+	ctx, span := tracing.StartChildSpan(ctx, "cluster.proxy", attribute.String("zot.cluster.member", targetMember))
+	defer span.End()
+
 	clonedBody := cloneRequestBody(req)
 
 	fwdRequest, err := http.NewRequestWithContext(ctx, req.Method, cloneURL.String(), clonedBody)
@@ -139,31 +314,25 @@ func proxyHTTPRequest(ctx context.Context, req *http.Request,
 	// always set hop count to 1 for now.
 	// the handler wrapper above will terminate the process if it sees a request that
 	// already has a hop count but is due for proxying.
-	fwdRequest.Header.Set(constants.ScaleOutHopCountHeader, "1")
+	signClusterRequest(clusterConfig, fwdRequest)
 
-	clientOpts := common.HTTPClientOptions{
-		TLSEnabled: ctrlr.Config.HTTP.TLS != nil,
-		VerifyTLS:  ctrlr.Config.HTTP.TLS != nil, // for now, always verify TLS when TLS mode is enabled
-		Host:       targetMember,
-	}
+	// the target member continues the trace of the request
+	tracing.InjectHeaders(ctx, fwdRequest.Header)
 
-	tlsConfig := ctrlr.Config.Cluster.TLS
-	if tlsConfig != nil {
-		clientOpts.CertOptions.ClientCertFile = tlsConfig.Cert
-		clientOpts.CertOptions.ClientKeyFile = tlsConfig.Key
-		clientOpts.CertOptions.RootCaCertFile = tlsConfig.CACert
-	}
-
-	httpClient, err := common.CreateHTTPClient(&clientOpts)
+	httpClient, err := newClusterHTTPClient(ctrlr, clusterConfig, targetMember)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := httpClient.Do(fwdRequest)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		return nil, err
 	}
 
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
 	var clonedRespBody bytes.Buffer
 
 	// copy out the contents into a new buffer as the response body
@@ -179,6 +348,75 @@ func proxyHTTPRequest(ctx context.Context, req *http.Request,
 	return resp, nil
 }
 
+// returns the scheme used by the cluster members to reach each other.
+func getClusterScheme(ctrlr *Controller) string {
+	if ctrlr.Config.HTTP.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// returns a client for requests sent to the target member, authenticated with the cluster client certificate.
+func newClusterHTTPClient(ctrlr *Controller, clusterConfig *config.ClusterConfig, targetMember string,
+) (*http.Client, error) {
+	clientOpts := common.HTTPClientOptions{
+		TLSEnabled: ctrlr.Config.HTTP.TLS != nil,
+		VerifyTLS:  ctrlr.Config.HTTP.TLS != nil, // for now, always verify TLS when TLS mode is enabled
+		Host:       targetMember,
+	}
+
+	tlsConfig := clusterConfig.TLS
+	if tlsConfig != nil {
+		clientOpts.CertOptions.ClientCertFile = tlsConfig.Cert
+		clientOpts.CertOptions.ClientKeyFile = tlsConfig.Key
+		clientOpts.CertOptions.RootCaCertFile = tlsConfig.CACert
+	}
+
+	return common.CreateHTTPClient(&clientOpts)
+}
+
+// marks a request sent to another member as proxied, with a signature keyed by the cluster hash key
+// so that the other member can tell it apart from a client request carrying the same headers.
+func signClusterRequest(clusterConfig *config.ClusterConfig, request *http.Request) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request.Header.Set(constants.ScaleOutHopCountHeader, "1")
+	request.Header.Set(constants.ScaleOutSignatureHeader,
+		timestamp+":"+clusterRequestSignature(clusterConfig, request, timestamp))
+}
+
+// returns true if the request was sent by another member of the cluster, i.e. it has a hop count
+// and a recent signature computed with the cluster hash key.
+func isClusterRequest(clusterConfig *config.ClusterConfig, request *http.Request) bool {
+	if clusterConfig == nil || request.Header.Get(constants.ScaleOutHopCountHeader) == "" {
+		return false
+	}
+
+	timestamp, signature, found := strings.Cut(request.Header.Get(constants.ScaleOutSignatureHeader), ":")
+	if !found {
+		return false
+	}
+
+	unixTime, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if age := time.Since(time.Unix(unixTime, 0)); age > clusterRequestMaxAge || age < -clusterRequestMaxAge {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(clusterRequestSignature(clusterConfig, request, timestamp)))
+}
+
+func clusterRequestSignature(clusterConfig *config.ClusterConfig, request *http.Request, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(clusterConfig.HashKey))
+	mac.Write([]byte(request.Method + " " + request.URL.RequestURI() + " " + timestamp))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func cloneRequestBody(src *http.Request) io.Reader {
 	var bCloneForOriginal, bCloneForCopy bytes.Buffer
 	multiWriter := io.MultiWriter(&bCloneForOriginal, &bCloneForCopy)
@@ -0,0 +1,461 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	rediscfg "zotregistry.dev/zot/pkg/api/config/redis"
+	"zotregistry.dev/zot/pkg/api/constants"
+	apiErr "zotregistry.dev/zot/pkg/api/errors"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+)
+
+const (
+	rateLimitKeyPrefix = "zot:ratelimit"
+	// full buckets are dropped from memory, they are the same as missing ones.
+	rateLimitSweepInterval = time.Minute
+
+	manifestPullsBudget = "manifestPulls"
+	blobBytesBudget     = "blobBytes"
+
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	retryAfterHeader         = "Retry-After"
+)
+
+// rateLimitBudget is a token bucket holding up to capacity tokens and refilled with rate tokens per second.
+type rateLimitBudget struct {
+	name     string
+	limit    int64
+	interval time.Duration
+	capacity float64
+	rate     float64
+}
+
+func newRateLimitBudget(name string, conf *config.RatelimitBudget) *rateLimitBudget {
+	if conf == nil {
+		return nil
+	}
+
+	capacity := conf.Burst
+	if capacity <= 0 {
+		capacity = conf.Limit
+	}
+
+	return &rateLimitBudget{
+		name:     name,
+		limit:    conf.Limit,
+		interval: conf.Interval,
+		capacity: float64(capacity),
+		rate:     float64(conf.Limit) / conf.Interval.Seconds(),
+	}
+}
+
+type rateLimitResult struct {
+	allowed    bool
+	remaining  float64
+	retryAfter time.Duration
+}
+
+// takeTokens refills a bucket holding tokens for elapsed time and removes cost tokens from it. The tokens are
+// only taken if the bucket holds at least cost tokens and isn't empty, unless force is set, in which case the
+// bucket may go below zero (eg. blob bytes are counted once sent, and the next downloads wait for the refill).
+func takeTokens(budget *rateLimitBudget, tokens float64, elapsed time.Duration, cost float64, force bool,
+) (float64, rateLimitResult) {
+	tokens = math.Min(budget.capacity, tokens+math.Max(0, elapsed.Seconds())*budget.rate)
+
+	if !force && (tokens <= 0 || tokens < cost) {
+		return tokens, getDeniedResult(budget, tokens, cost)
+	}
+
+	tokens -= cost
+
+	return tokens, rateLimitResult{allowed: true, remaining: tokens}
+}
+
+// getDeniedResult computes how long the client has to wait until the bucket holds enough tokens.
+func getDeniedResult(budget *rateLimitBudget, tokens, cost float64) rateLimitResult {
+	missing := math.Max(cost, 1) - tokens
+
+	return rateLimitResult{
+		allowed:    false,
+		remaining:  tokens,
+		retryAfter: time.Duration(missing / budget.rate * float64(time.Second)),
+	}
+}
+
+// rateLimitStore holds the token buckets of the rate limit policies.
+type rateLimitStore interface {
+	take(ctx context.Context, key string, budget *rateLimitBudget, cost float64, force bool) (rateLimitResult, error)
+}
+
+type rateLimitBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// localRateLimitStore keeps the buckets in memory, they are not shared with other zot replicas.
+type localRateLimitStore struct {
+	lock      sync.Mutex
+	buckets   map[string]*rateLimitBucket
+	budgets   map[string]*rateLimitBudget
+	lastSweep time.Time
+}
+
+func newLocalRateLimitStore() *localRateLimitStore {
+	return &localRateLimitStore{
+		buckets:   map[string]*rateLimitBucket{},
+		budgets:   map[string]*rateLimitBudget{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (store *localRateLimitStore) take(_ context.Context, key string, budget *rateLimitBudget, cost float64,
+	force bool,
+) (rateLimitResult, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	now := time.Now()
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &rateLimitBucket{tokens: budget.capacity, updatedAt: now}
+		store.buckets[key] = bucket
+		store.budgets[key] = budget
+	}
+
+	tokens, result := takeTokens(budget, bucket.tokens, now.Sub(bucket.updatedAt), cost, force)
+	bucket.tokens = tokens
+	bucket.updatedAt = now
+
+	if now.Sub(store.lastSweep) > rateLimitSweepInterval {
+		store.sweep(now)
+	}
+
+	return result, nil
+}
+
+func (store *localRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range store.buckets {
+		budget := store.budgets[key]
+
+		if bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*budget.rate >= budget.capacity {
+			delete(store.buckets, key)
+			delete(store.budgets, key)
+		}
+	}
+
+	store.lastSweep = now
+}
+
+// the same computation as takeTokens, run atomically by redis.
+// the buckets expire once they would be full again.
+var redisTakeTokensScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local force = ARGV[5] == "1"
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updatedAt")
+local tokens = tonumber(state[1]) or capacity
+local updatedAt = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - updatedAt) / 1000 * rate)
+
+local allowed = 1
+if not force and (tokens <= 0 or tokens < cost) then
+	allowed = 0
+else
+	tokens = tokens - cost
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updatedAt", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((capacity - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// redisRateLimitStore shares the buckets between the replicas of a cluster.
+type redisRateLimitStore struct {
+	client redis.UniversalClient
+}
+
+func (store *redisRateLimitStore) take(ctx context.Context, key string, budget *rateLimitBudget, cost float64,
+	force bool,
+) (rateLimitResult, error) {
+	forceArg := "0"
+	if force {
+		forceArg = "1"
+	}
+
+	reply, err := redisTakeTokensScript.Run(ctx, store.client, []string{key},
+		budget.capacity, budget.rate, time.Now().UnixMilli(), cost, forceArg).Slice()
+	if err != nil {
+		return rateLimitResult{allowed: true}, err
+	}
+
+	//nolint:mnd
+	if len(reply) != 2 {
+		return rateLimitResult{allowed: true}, fmt.Errorf("%w: %v", zerr.ErrUnexpectedRateLimitReply, reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	tokensStr, _ := reply[1].(string)
+
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return rateLimitResult{allowed: true}, err
+	}
+
+	if allowed == 1 {
+		return rateLimitResult{allowed: true, remaining: tokens}, nil
+	}
+
+	return getDeniedResult(budget, tokens, cost), nil
+}
+
+type rateLimitPolicy struct {
+	index         int
+	key           string
+	repositories  []string
+	manifestPulls *rateLimitBudget
+	blobBytes     *rateLimitBudget
+}
+
+func (policy *rateLimitPolicy) matches(repo string) bool {
+	if len(policy.repositories) == 0 {
+		return true
+	}
+
+	for _, pattern := range policy.repositories {
+		if matched, err := glob.Match(pattern, repo); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getBucketKey returns what the request is counted on by the policy, false if the policy doesn't apply to it.
+func (policy *rateLimitPolicy) getBucketKey(request *http.Request, repo string) (string, bool) {
+	switch policy.key {
+	case config.RatelimitKeyUsername:
+		userAc, err := reqCtx.UserAcFromContext(request.Context())
+		if err == nil && userAc != nil && !userAc.IsAnonymous() {
+			return "user:" + userAc.GetUsername(), true
+		}
+
+		// anonymous users are told apart by their address
+		return "ip:" + getClientIP(request), true
+	case config.RatelimitKeyAPIKey:
+		_, passphrase, err := getUsernamePasswordBasicAuth(request)
+		if err != nil || !strings.HasPrefix(passphrase, constants.APIKeysPrefix) {
+			return "", false
+		}
+
+		return "apikey:" + hashUUID(strings.TrimPrefix(passphrase, constants.APIKeysPrefix)), true
+	case config.RatelimitKeyIP:
+		return "ip:" + getClientIP(request), true
+	case config.RatelimitKeyRepository:
+		return "repo:" + repo, true
+	default:
+		return "", false
+	}
+}
+
+type rateLimitCheck struct {
+	key    string
+	budget *rateLimitBudget
+	result rateLimitResult
+}
+
+// RepositoryRateLimiter applies the rate limit policies to manifest pulls and blob downloads.
+func RepositoryRateLimiter(ctlr *Controller) mux.MiddlewareFunc {
+	ratelimitConfig := ctlr.Config.HTTP.Ratelimit
+
+	var store rateLimitStore = newLocalRateLimitStore()
+
+	if len(ratelimitConfig.Redis) > 0 {
+		client, err := rediscfg.GetRedisClient(ratelimitConfig.Redis, ctlr.Log)
+		if err != nil {
+			ctlr.Log.Panic().Err(err).Msg("failed to create redis client for rate limits")
+		}
+
+		store = &redisRateLimitStore{client: client}
+	}
+
+	policies := make([]*rateLimitPolicy, 0, len(ratelimitConfig.Policies))
+
+	for index, policyConfig := range ratelimitConfig.Policies {
+		policies = append(policies, &rateLimitPolicy{
+			index:         index,
+			key:           policyConfig.Key,
+			repositories:  policyConfig.Repositories,
+			manifestPulls: newRateLimitBudget(manifestPullsBudget, policyConfig.ManifestPulls),
+			blobBytes:     newRateLimitBudget(blobBytesBudget, policyConfig.BlobBytes),
+		})
+	}
+
+	ctlr.Log.Info().Int("policies", len(policies)).Bool("shared", len(ratelimitConfig.Redis) > 0).
+		Msg("repository ratelimiter enabled")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			if request.Method != http.MethodGet {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			// requests forwarded by cluster members were already counted by the member which received them,
+			// clients can't skip the limits by sending the same headers as they can't sign them
+			if isClusterRequest(ctlr.ClusterConfig(), request) {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			isBlobDownload := false
+
+			pathTemplate, _ := mux.CurrentRoute(request).GetPathTemplate()
+
+			switch {
+			case strings.HasSuffix(pathTemplate, "/manifests/{reference}"):
+			case strings.HasSuffix(pathTemplate, "/blobs/{digest}"):
+				isBlobDownload = true
+			default:
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			repo := mux.Vars(request)["name"]
+			checks := []*rateLimitCheck{}
+
+			for _, policy := range policies {
+				budget := policy.manifestPulls
+				if isBlobDownload {
+					budget = policy.blobBytes
+				}
+
+				if budget == nil || !policy.matches(repo) {
+					continue
+				}
+
+				bucketKey, ok := policy.getBucketKey(request, repo)
+				if !ok {
+					continue
+				}
+
+				check := &rateLimitCheck{
+					key:    fmt.Sprintf("%s:%d:%s:%s", rateLimitKeyPrefix, policy.index, budget.name, bucketKey),
+					budget: budget,
+				}
+
+				// manifest pulls take one token, blob downloads only need a non empty bucket
+				// and take the bytes once they are sent
+				cost := 1.0
+				if isBlobDownload {
+					cost = 0
+				}
+
+				result, err := store.take(request.Context(), check.key, budget, cost, false)
+				if err != nil {
+					// don't fail requests because the counters are not reachable
+					ctlr.Log.Warn().Err(err).Str("key", check.key).Msg("failed to check rate limit")
+
+					continue
+				}
+
+				check.result = result
+				checks = append(checks, check)
+
+				if !result.allowed {
+					setRateLimitHeaders(response, checks)
+					response.Header().Set(retryAfterHeader,
+						strconv.FormatInt(int64(math.Ceil(result.retryAfter.Seconds())), 10))
+
+					ctlr.Log.Info().Str(constants.RepositoryLogKey, repo).Str("key", check.key).
+						Msg("rate limit exceeded")
+
+					zcommon.WriteJSON(response, http.StatusTooManyRequests,
+						apiErr.NewErrorList(apiErr.NewError(apiErr.TOOMANYREQUESTS)))
+
+					return
+				}
+			}
+
+			setRateLimitHeaders(response, checks)
+
+			if !isBlobDownload {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			stwr := statusWriter{ResponseWriter: response}
+
+			next.ServeHTTP(&stwr, request)
+
+			if stwr.length == 0 {
+				return
+			}
+
+			for _, check := range checks {
+				// the request context may be canceled by the client once the download is done
+				_, err := store.take(context.WithoutCancel(request.Context()), check.key, check.budget,
+					float64(stwr.length), true)
+				if err != nil {
+					ctlr.Log.Warn().Err(err).Str("key", check.key).Msg("failed to count downloaded bytes")
+				}
+			}
+		})
+	}
+}
+
+// setRateLimitHeaders describes the most restrictive of the limits applied to the request.
+func setRateLimitHeaders(response http.ResponseWriter, checks []*rateLimitCheck) {
+	var restrictive *rateLimitCheck
+
+	for _, check := range checks {
+		if restrictive == nil ||
+			check.result.remaining/check.budget.capacity < restrictive.result.remaining/restrictive.budget.capacity {
+			restrictive = check
+		}
+	}
+
+	if restrictive == nil {
+		return
+	}
+
+	window := int64(restrictive.budget.interval.Seconds())
+	remaining := int64(math.Max(0, math.Floor(restrictive.result.remaining)))
+
+	response.Header().Set(rateLimitLimitHeader, fmt.Sprintf("%d;w=%d", restrictive.budget.limit, window))
+	response.Header().Set(rateLimitRemainingHeader, fmt.Sprintf("%d;w=%d", remaining, window))
+}
+
+func getClientIP(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
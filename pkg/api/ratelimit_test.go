@@ -0,0 +1,259 @@
+package api_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+func TestRatelimitPolicies(t *testing.T) {
+	Convey("Make a new controller with rate limit policies", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("alice", "alicepass") +
+			test.GetCredString("bob", "bobpass"))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.Ratelimit = &config.RatelimitConfig{
+			Policies: []config.RatelimitPolicy{
+				{
+					Key:           config.RatelimitKeyUsername,
+					Repositories:  []string{"limited/**"},
+					ManifestPulls: &config.RatelimitBudget{Limit: 2, Interval: time.Hour},
+				},
+				{
+					Key:           config.RatelimitKeyRepository,
+					Repositories:  []string{"shared"},
+					ManifestPulls: &config.RatelimitBudget{Limit: 3, Interval: time.Hour},
+				},
+				{
+					Key:          config.RatelimitKeyIP,
+					Repositories: []string{"bytes/**"},
+					BlobBytes:    &config.RatelimitBudget{Limit: 10, Interval: time.Hour},
+				},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		img := CreateRandomImage()
+
+		for _, repo := range []string{"limited/repo", "shared", "bytes/repo", "unlimited"} {
+			err := UploadImageWithBasicAuth(img, baseURL, repo, "tag", "alice", "alicepass")
+			So(err, ShouldBeNil)
+		}
+
+		pull := func(user, password, repo string) *resty.Response {
+			resp, err := resty.R().SetBasicAuth(user, password).Get(baseURL + "/v2/" + repo + "/manifests/tag")
+			So(err, ShouldBeNil)
+
+			return resp
+		}
+
+		Convey("Manifest pulls are limited per user", func() {
+			resp := pull("alice", "alicepass", "limited/repo")
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("RateLimit-Limit"), ShouldEqual, "2;w=3600")
+			So(resp.Header().Get("RateLimit-Remaining"), ShouldEqual, "1;w=3600")
+
+			// HEAD requests are not counted
+			resp, err := resty.R().SetBasicAuth("alice", "alicepass").Head(baseURL + "/v2/limited/repo/manifests/tag")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			resp = pull("alice", "alicepass", "limited/repo")
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("RateLimit-Remaining"), ShouldEqual, "0;w=3600")
+
+			resp = pull("alice", "alicepass", "limited/repo")
+			So(resp.StatusCode(), ShouldEqual, http.StatusTooManyRequests)
+			So(string(resp.Body()), ShouldContainSubstring, "TOOMANYREQUESTS")
+
+			// one pull is refilled every 30 minutes
+			retryAfter, err := strconv.Atoi(resp.Header().Get("Retry-After"))
+			So(err, ShouldBeNil)
+			So(retryAfter, ShouldBeBetweenOrEqual, 1790, 1800)
+
+			// other users have their own budget
+			resp = pull("bob", "bobpass", "limited/repo")
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("RateLimit-Remaining"), ShouldEqual, "1;w=3600")
+
+			// repositories not matched by any policy are not limited
+			for range 5 {
+				resp = pull("alice", "alicepass", "unlimited")
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+				So(resp.Header().Get("RateLimit-Limit"), ShouldBeEmpty)
+			}
+		})
+
+		Convey("Manifest pulls are limited per repository", func() {
+			So(pull("alice", "alicepass", "shared").StatusCode(), ShouldEqual, http.StatusOK)
+			So(pull("bob", "bobpass", "shared").StatusCode(), ShouldEqual, http.StatusOK)
+			So(pull("alice", "alicepass", "shared").StatusCode(), ShouldEqual, http.StatusOK)
+			So(pull("bob", "bobpass", "shared").StatusCode(), ShouldEqual, http.StatusTooManyRequests)
+		})
+
+		Convey("Blob downloads are limited by the downloaded bytes", func() {
+			getBlob := func() *resty.Response {
+				resp, err := resty.R().SetBasicAuth("alice", "alicepass").
+					Get(fmt.Sprintf("%s/v2/bytes/repo/blobs/%s", baseURL, img.ConfigDescriptor.Digest))
+				So(err, ShouldBeNil)
+
+				return resp
+			}
+
+			// the bucket isn't empty, the download goes beyond the budget
+			resp := getBlob()
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("RateLimit-Limit"), ShouldEqual, "10;w=3600")
+
+			resp = getBlob()
+			So(resp.StatusCode(), ShouldEqual, http.StatusTooManyRequests)
+			So(resp.Header().Get("RateLimit-Remaining"), ShouldEqual, "0;w=3600")
+			So(resp.Header().Get("Retry-After"), ShouldNotBeEmpty)
+
+			// manifest pulls have a separate budget
+			So(pull("alice", "alicepass", "bytes/repo").StatusCode(), ShouldEqual, http.StatusOK)
+		})
+	})
+
+	Convey("Rate limit counters are shared through redis", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		ratelimit := &config.RatelimitConfig{
+			Policies: []config.RatelimitPolicy{
+				{
+					Key:           config.RatelimitKeyIP,
+					ManifestPulls: &config.RatelimitBudget{Limit: 1, Interval: time.Hour},
+				},
+			},
+			Redis: map[string]interface{}{"url": "redis://" + miniRedis.Addr()},
+		}
+
+		img := CreateRandomImage()
+		baseURLs := []string{}
+
+		for range 2 {
+			port := test.GetFreePort()
+			baseURL := test.GetBaseURL(port)
+
+			conf := config.New()
+			conf.HTTP.Port = port
+			conf.HTTP.Ratelimit = ratelimit
+
+			ctlr := api.NewController(conf)
+			ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+			cm := test.NewControllerManager(ctlr)
+			cm.StartAndWait(port)
+
+			defer cm.StopServer()
+
+			err := UploadImage(img, baseURL, "repo", "tag")
+			So(err, ShouldBeNil)
+
+			baseURLs = append(baseURLs, baseURL)
+		}
+
+		resp, err := resty.R().Get(baseURLs[0] + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		So(miniRedis.Exists("zot:ratelimit:0:manifestPulls:ip:127.0.0.1"), ShouldBeTrue)
+
+		// the other replica sees the budget is spent
+		resp, err = resty.R().Get(baseURLs[1] + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusTooManyRequests)
+
+		// requests are not failed when redis is not reachable
+		miniRedis.Close()
+
+		resp, err = resty.R().Get(baseURLs[1] + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+
+	Convey("Only requests signed by cluster members skip the limits", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		hashKey := "loremipsumdolors"
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Ratelimit = &config.RatelimitConfig{
+			Policies: []config.RatelimitPolicy{
+				{
+					Key:           config.RatelimitKeyIP,
+					ManifestPulls: &config.RatelimitBudget{Limit: 1, Interval: time.Hour},
+				},
+			},
+		}
+		conf.Cluster = &config.ClusterConfig{
+			Members: []string{"127.0.0.1:" + port},
+			HashKey: hashKey,
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		img := CreateRandomImage()
+
+		err := UploadImage(img, baseURL, "repo", "tag")
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().Get(baseURL + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// a client claiming to be a cluster member is limited
+		resp, err = resty.R().SetHeader(constants.ScaleOutHopCountHeader, "1").
+			SetHeader(constants.ScaleOutSignatureHeader, fmt.Sprintf("%d:invalid", time.Now().Unix())).
+			Get(baseURL + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusTooManyRequests)
+
+		// a request signed with the cluster hash key was already counted by the member which forwarded it
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(hashKey))
+		mac.Write([]byte(http.MethodGet + " /v2/repo/manifests/tag " + timestamp))
+
+		resp, err = resty.R().SetHeader(constants.ScaleOutHopCountHeader, "1").
+			SetHeader(constants.ScaleOutSignatureHeader, timestamp+":"+hex.EncodeToString(mac.Sum(nil))).
+			Get(baseURL + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+}
@@ -0,0 +1,396 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	"zotregistry.dev/zot/pkg/cluster"
+	"zotregistry.dev/zot/pkg/compat"
+	"zotregistry.dev/zot/pkg/scheduler"
+	storageCommon "zotregistry.dev/zot/pkg/storage/common"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+// interval between two passes over the local repositories looking for repositories owned by other members.
+const clusterRebalanceInterval = 10 * time.Minute
+
+// RunClusterRebalance periodically moves the repositories stored by the local member but owned by other
+// members of the cluster, e.g. after a membership change, to their owner.
+// members sharing their storage see the same repositories, so only the local image stores are rebalanced.
+func (c *Controller) RunClusterRebalance(sch *scheduler.Scheduler) {
+	if c.ClusterConfig() == nil {
+		return
+	}
+
+	if c.Config.Storage.StorageDriver == nil {
+		sch.SubmitGenerator(c.newRebalanceTaskGenerator(c.StoreController.DefaultStore),
+			clusterRebalanceInterval, scheduler.LowPriority)
+	}
+
+	for route, storageConfig := range c.Config.Storage.SubPaths {
+		if storageConfig.StorageDriver == nil && c.StoreController.SubStore[route] != nil {
+			sch.SubmitGenerator(c.newRebalanceTaskGenerator(c.StoreController.SubStore[route]),
+				clusterRebalanceInterval, scheduler.LowPriority)
+		}
+	}
+}
+
+func (c *Controller) newRebalanceTaskGenerator(imgStore storageTypes.ImageStore) *RebalanceTaskGenerator {
+	return &RebalanceTaskGenerator{
+		ctrlr:          c,
+		imgStore:       imgStore,
+		processedRepos: make(map[string]struct{}),
+	}
+}
+
+type RebalanceTaskGenerator struct {
+	ctrlr          *Controller
+	imgStore       storageTypes.ImageStore
+	processedRepos map[string]struct{}
+	movedRepos     int
+	done           bool
+}
+
+func (gen *RebalanceTaskGenerator) Name() string {
+	return "RebalanceTaskGenerator"
+}
+
+func (gen *RebalanceTaskGenerator) Next() (scheduler.Task, error) {
+	clusterConfig := gen.ctrlr.ClusterConfig()
+
+	for {
+		repo, err := gen.imgStore.GetNextRepository(gen.processedRepos)
+		if err != nil {
+			return nil, err
+		}
+
+		if repo == "" {
+			if gen.movedRepos > 0 {
+				gen.ctrlr.Log.Info().Str("rootDir", gen.imgStore.RootDir()).Int("repositories", gen.movedRepos).
+					Msg("scheduled moving repositories to their cluster owner")
+			}
+
+			gen.done = true
+
+			return nil, nil //nolint:nilnil
+		}
+
+		gen.processedRepos[repo] = struct{}{}
+
+		targetMemberIndex, targetMember := cluster.ComputeTargetMemberWithHashing(clusterConfig.Hashing,
+			clusterConfig.HashKey, clusterConfig.Members, repo)
+		if targetMemberIndex == clusterConfig.Proxy.LocalMemberClusterSocketIndex {
+			continue
+		}
+
+		gen.movedRepos++
+
+		return &rebalanceTask{
+			ctrlr:         gen.ctrlr,
+			clusterConfig: clusterConfig,
+			imgStore:      gen.imgStore,
+			repo:          repo,
+			targetMember:  targetMember,
+		}, nil
+	}
+}
+
+func (gen *RebalanceTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *RebalanceTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *RebalanceTaskGenerator) Reset() {
+	gen.processedRepos = make(map[string]struct{})
+	gen.movedRepos = 0
+	gen.done = false
+}
+
+// rebalanceTask pushes a repository to the member owning it, then removes the local copy.
+type rebalanceTask struct {
+	ctrlr         *Controller
+	clusterConfig *config.ClusterConfig
+	imgStore      storageTypes.ImageStore
+	repo          string
+	targetMember  string
+}
+
+func (task *rebalanceTask) DoWork(ctx context.Context) error {
+	log := task.ctrlr.Log
+
+	index, err := storageCommon.GetIndex(task.imgStore, task.repo, log)
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := newClusterHTTPClient(task.ctrlr, task.clusterConfig, task.targetMember)
+	if err != nil {
+		return err
+	}
+
+	pusher := &repoPusher{
+		task:       task,
+		httpClient: httpClient,
+		baseURL:    fmt.Sprintf("%s://%s/v2/%s", getClusterScheme(task.ctrlr), task.targetMember, task.repo),
+		pushed:     make(map[godigest.Digest]struct{}),
+	}
+
+	for _, desc := range index.Manifests {
+		reference := desc.Digest.String()
+		if tag, ok := desc.Annotations[ispec.AnnotationRefName]; ok {
+			reference = tag
+		}
+
+		if err := pusher.pushManifest(ctx, desc, reference); err != nil {
+			log.Error().Err(err).Str(constants.RepositoryLogKey, task.repo).Str("reference", reference).
+				Str("targetMember", task.targetMember).Msg("failed to move repository to its cluster owner")
+
+			return err
+		}
+	}
+
+	// the local copy is only removed once the owner serves every manifest of the repository
+	for _, desc := range index.Manifests {
+		reference := desc.Digest.String()
+		if tag, ok := desc.Annotations[ispec.AnnotationRefName]; ok {
+			reference = tag
+		}
+
+		if err := pusher.verifyManifest(ctx, desc, reference); err != nil {
+			log.Error().Err(err).Str(constants.RepositoryLogKey, task.repo).Str("reference", reference).
+				Str("targetMember", task.targetMember).Msg("failed to verify repository moved to its cluster owner")
+
+			return err
+		}
+	}
+
+	if err := task.removeLocalRepo(index); err != nil {
+		log.Error().Err(err).Str(constants.RepositoryLogKey, task.repo).
+			Msg("failed to remove repository moved to its cluster owner")
+
+		return err
+	}
+
+	log.Info().Str(constants.RepositoryLogKey, task.repo).Str("targetMember", task.targetMember).
+		Msg("moved repository to its cluster owner")
+
+	return nil
+}
+
+// removes the local copy of the repository, unless it changed since it was pushed to its owner.
+func (task *rebalanceTask) removeLocalRepo(pushedIndex ispec.Index) error {
+	var lockLatency time.Time
+
+	task.imgStore.Lock(&lockLatency)
+	defer task.imgStore.Unlock(&lockLatency)
+
+	index, err := storageCommon.GetIndex(task.imgStore, task.repo, task.ctrlr.Log)
+	if err != nil {
+		return err
+	}
+
+	if !slices.EqualFunc(index.Manifests, pushedIndex.Manifests, func(desc, pushedDesc ispec.Descriptor) bool {
+		return desc.Digest == pushedDesc.Digest &&
+			desc.Annotations[ispec.AnnotationRefName] == pushedDesc.Annotations[ispec.AnnotationRefName]
+	}) {
+		return zerr.ErrRepoChangedDuringRebalance
+	}
+
+	// with no manifests left in the index, all the blobs of the repository can be removed
+	index.Manifests = []ispec.Descriptor{}
+
+	if err := task.imgStore.PutIndexContent(task.repo, index); err != nil {
+		return err
+	}
+
+	blobs, err := task.imgStore.GetAllBlobs(task.repo)
+	if err != nil {
+		return err
+	}
+
+	if _, err := task.imgStore.CleanupRepo(task.repo, blobs, true); err != nil {
+		return err
+	}
+
+	// a metaDB shared by the members already holds the repository pushed to its owner
+	if task.ctrlr.MetaDB != nil && !task.ctrlr.Config.Storage.RemoteCache {
+		return task.ctrlr.MetaDB.DeleteRepoMeta(task.repo)
+	}
+
+	return nil
+}
+
+func (task *rebalanceTask) String() string {
+	return fmt.Sprintf("{Name: %s, repository: %s, targetMember: %s}", task.Name(), task.repo, task.targetMember)
+}
+
+func (task *rebalanceTask) Name() string {
+	return "RebalanceTask"
+}
+
+// repoPusher pushes the content of a local repository to another member using the dist-spec APIs.
+type repoPusher struct {
+	task       *rebalanceTask
+	httpClient *http.Client
+	baseURL    string
+	pushed     map[godigest.Digest]struct{}
+}
+
+func (pusher *repoPusher) pushManifest(ctx context.Context, desc ispec.Descriptor, reference string) error {
+	content, err := pusher.task.imgStore.GetBlobContent(pusher.task.repo, desc.Digest)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case desc.MediaType == ispec.MediaTypeImageManifest || compat.IsCompatibleManifestMediaType(desc.MediaType):
+		var manifest ispec.Manifest
+
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return err
+		}
+
+		for _, blob := range append([]ispec.Descriptor{manifest.Config}, manifest.Layers...) {
+			if err := pusher.pushBlob(ctx, blob); err != nil {
+				return err
+			}
+		}
+	case desc.MediaType == ispec.MediaTypeImageIndex || compat.IsCompatibleManifestListMediaType(desc.MediaType):
+		var index ispec.Index
+
+		if err := json.Unmarshal(content, &index); err != nil {
+			return err
+		}
+
+		// the manifests of an index are pushed before the index
+		for _, manifest := range index.Manifests {
+			if _, ok := pusher.pushed[manifest.Digest]; ok {
+				continue
+			}
+
+			if err := pusher.pushManifest(ctx, manifest, manifest.Digest.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = pusher.do(ctx, http.MethodPut, pusher.baseURL+"/manifests/"+reference, desc.MediaType,
+		bytes.NewReader(content), int64(len(content)), http.StatusCreated)
+	if err != nil {
+		return err
+	}
+
+	pusher.pushed[desc.Digest] = struct{}{}
+
+	return nil
+}
+
+// checks that the target member serves the manifest pushed for the reference, the blobs it references
+// were already checked by the target member before storing the manifest.
+func (pusher *repoPusher) verifyManifest(ctx context.Context, desc ispec.Descriptor, reference string) error {
+	url := pusher.baseURL + "/manifests/" + reference
+
+	response, err := pusher.do(ctx, http.MethodHead, url, "", nil, 0, http.StatusOK)
+	if err != nil {
+		return err
+	}
+
+	if digest := response.Header.Get(constants.DistContentDigestKey); digest != desc.Digest.String() {
+		return fmt.Errorf("%w: %s %s returned digest %s instead of %s", zerr.ErrClusterMemberPush,
+			http.MethodHead, url, digest, desc.Digest)
+	}
+
+	return nil
+}
+
+func (pusher *repoPusher) pushBlob(ctx context.Context, desc ispec.Descriptor) error {
+	// the blob may have already been pushed by another manifest or a previous attempt
+	if _, err := pusher.do(ctx, http.MethodHead, pusher.baseURL+"/blobs/"+desc.Digest.String(), "",
+		nil, 0, http.StatusOK); err == nil {
+		return nil
+	}
+
+	response, err := pusher.do(ctx, http.MethodPost, pusher.baseURL+"/blobs/uploads/", "",
+		nil, 0, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+
+	location, err := url.Parse(pusher.baseURL)
+	if err != nil {
+		return err
+	}
+
+	location, err = location.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+
+	query := location.Query()
+	query.Set("digest", desc.Digest.String())
+	location.RawQuery = query.Encode()
+
+	blob, size, err := pusher.task.imgStore.GetBlob(pusher.task.repo, desc.Digest, desc.MediaType)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	_, err = pusher.do(ctx, http.MethodPut, location.String(), constants.BinaryMediaType,
+		blob, size, http.StatusCreated)
+
+	return err
+}
+
+// sends a request to the target member and returns an error if the response doesn't have the expected status code.
+func (pusher *repoPusher) do(ctx context.Context, method, url, contentType string,
+	body io.Reader, contentLength int64, expectedStatus int,
+) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.ContentLength = contentLength
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	// the target member owns the repository, the request must not be proxied any further
+	signClusterRequest(pusher.task.clusterConfig, request)
+
+	response, err := pusher.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+
+	if response.StatusCode != expectedStatus {
+		return nil, fmt.Errorf("%w: %s %s returned status %d", zerr.ErrClusterMemberPush, method, url, response.StatusCode)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	guuid "github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/constants"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+)
+
+//nolint:gochecknoglobals
+var robotNameRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+type RobotAccountPayload struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// glob patterns of the repositories the robot account can act on
+	Repositories []string `json:"repositories"`
+	// subset of read, create, update and delete
+	Actions []string `json:"actions"`
+	// if empty the secret never expires
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+type RobotSecretPayload struct {
+	// if empty the new secret never expires
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+type RobotAccountInfo struct {
+	Name string `json:"name"`
+	// the username used to authenticate, eg. robot$name
+	Username             string    `json:"username"`
+	Description          string    `json:"description"`
+	Repositories         []string  `json:"repositories"`
+	Actions              []string  `json:"actions"`
+	CreatedBy            string    `json:"createdBy"`
+	CreatedAt            time.Time `json:"createdAt"`
+	SecretCreatedAt      time.Time `json:"secretCreatedAt"`
+	SecretExpirationDate time.Time `json:"secretExpirationDate"`
+	IsExpired            bool      `json:"isExpired"`
+	// only returned when the robot account is created or its secret is rotated
+	Secret string `json:"secret,omitempty"`
+}
+
+type RobotAccountList struct {
+	Robots []RobotAccountInfo `json:"robots"`
+}
+
+func newRobotAccountInfo(robot mTypes.RobotAccount) RobotAccountInfo {
+	return RobotAccountInfo{
+		Name:                 robot.Name,
+		Username:             constants.RobotAccountPrefix + robot.Name,
+		Description:          robot.Description,
+		Repositories:         robot.Repositories,
+		Actions:              robot.Actions,
+		CreatedBy:            robot.CreatedBy,
+		CreatedAt:            robot.CreatedAt,
+		SecretCreatedAt:      robot.SecretCreatedAt,
+		SecretExpirationDate: robot.SecretExpirationDate,
+		IsExpired:            robot.IsSecretExpired(),
+	}
+}
+
+// parseSecretExpirationDate returns the zero time if no expiration date is given, the secret won't expire.
+func parseSecretExpirationDate(expirationDate string, createdAt time.Time) (time.Time, error) {
+	if expirationDate == "" {
+		return time.Time{}, nil
+	}
+
+	//nolint: gosmopolitan
+	date, err := time.ParseInLocation(constants.APIKeyTimeFormat, expirationDate, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if createdAt.After(date) {
+		return time.Time{}, fmt.Errorf("%w: %s", zerr.ErrBadExpirationDate, expirationDate)
+	}
+
+	return date, nil
+}
+
+// generateRobotSecret returns a new secret along with its hash, which is the only thing stored in MetaDB.
+func (rh *RouteHandler) generateRobotSecret() (string, string, error) {
+	secret, _, err := GenerateAPIKey(guuid.DefaultGenerator, rh.c.Log)
+	if err != nil {
+		return "", "", err
+	}
+
+	return constants.RobotSecretsPrefix + secret, hashUUID(secret), nil
+}
+
+func (rh *RouteHandler) readJSONPayload(response http.ResponseWriter, request *http.Request, payload any) bool {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to read request body")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return false
+	}
+
+	if err := json.Unmarshal(body, payload); err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return false
+	}
+
+	return true
+}
+
+// CreateRobotAccount godoc
+// @Summary Create a robot account
+// @Description Create a robot account allowed the given actions on the given repositories.
+// @Description The secret is only returned once, it's used as password along with the robot$<name> username.
+// @Accept  json
+// @Produce json
+// @Param   robot  body  RobotAccountPayload  true  "robot account name, description and scope"
+// @Success 201 {object} api.RobotAccountInfo
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "conflict"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/robots  [post].
+func (rh *RouteHandler) CreateRobotAccount(response http.ResponseWriter, request *http.Request) {
+	var payload RobotAccountPayload
+
+	if !rh.readJSONPayload(response, request, &payload) {
+		return
+	}
+
+	// robot accounts without scope can't do anything, so they are rejected
+	if !robotNameRegexp.MatchString(payload.Name) || len(payload.Repositories) == 0 || len(payload.Actions) == 0 ||
+		!isValidAPIKeyScope(payload.Repositories, payload.Actions) {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	createdAt := time.Now()
+
+	expirationDate, err := parseSecretExpirationDate(payload.ExpirationDate, createdAt)
+	if err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	secret, hashedSecret, err := rh.generateRobotSecret()
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	robot := mTypes.RobotAccount{
+		Name:                 payload.Name,
+		Description:          payload.Description,
+		HashedSecret:         hashedSecret,
+		Repositories:         payload.Repositories,
+		Actions:              payload.Actions,
+		CreatedBy:            userAc.GetUsername(),
+		CreatedAt:            createdAt,
+		SecretCreatedAt:      createdAt,
+		SecretExpirationDate: expirationDate,
+	}
+
+	if err := rh.c.MetaDB.AddRobotAccount(robot); err != nil {
+		if errors.Is(err, zerr.ErrRobotAccountAlreadyExists) {
+			response.WriteHeader(http.StatusConflict)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Str("robot", robot.Name).Msg("failed to store robot account")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.c.Log.Info().Str("robot", robot.Name).Str("createdBy", robot.CreatedBy).Msg("robot account created")
+
+	robotInfo := newRobotAccountInfo(robot)
+	robotInfo.Secret = secret
+
+	zcommon.WriteJSON(response, http.StatusCreated, robotInfo)
+}
+
+// ListRobotAccounts godoc
+// @Summary List robot accounts
+// @Description List the robot accounts, their scope and the expiration date of their secret.
+// @Produce json
+// @Success 200 {object} api.RobotAccountList
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/robots  [get].
+func (rh *RouteHandler) ListRobotAccounts(response http.ResponseWriter, request *http.Request) {
+	robots, err := rh.c.MetaDB.GetRobotAccounts()
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to get robot accounts")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	robotList := RobotAccountList{Robots: make([]RobotAccountInfo, 0, len(robots))}
+
+	for _, robot := range robots {
+		robotList.Robots = append(robotList.Robots, newRobotAccountInfo(robot))
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, robotList)
+}
+
+// DeleteRobotAccount godoc
+// @Summary Delete a robot account
+// @Description Delete a robot account, its secret and the tokens issued to it can't be used anymore.
+// @Param   robot  path  string  true  "robot account name"
+// @Success 200 {string} string "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/robots/{robot}  [delete].
+func (rh *RouteHandler) DeleteRobotAccount(response http.ResponseWriter, request *http.Request) {
+	name := mux.Vars(request)["robot"]
+
+	if _, err := rh.c.MetaDB.GetRobotAccount(name); err != nil {
+		if errors.Is(err, zerr.ErrRobotAccountNotFound) {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Str("robot", name).Msg("failed to get robot account")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := rh.c.MetaDB.DeleteRobotAccount(name); err != nil {
+		rh.c.Log.Error().Err(err).Str("robot", name).Msg("failed to delete robot account")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.c.Log.Info().Str("robot", name).Msg("robot account deleted")
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// RotateRobotSecret godoc
+// @Summary Rotate the secret of a robot account
+// @Description Replace the secret of a robot account, the previous secret can't be used anymore.
+// @Accept  json
+// @Produce json
+// @Param   robot   path  string              true   "robot account name"
+// @Param   secret  body  RobotSecretPayload  false  "expiration date of the new secret"
+// @Success 200 {object} api.RobotAccountInfo
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/robots/{robot}/rotate  [post].
+func (rh *RouteHandler) RotateRobotSecret(response http.ResponseWriter, request *http.Request) {
+	var payload RobotSecretPayload
+
+	// the payload is optional, the new secret never expires without it
+	if request.ContentLength != 0 && !rh.readJSONPayload(response, request, &payload) {
+		return
+	}
+
+	secretCreatedAt := time.Now()
+
+	expirationDate, err := parseSecretExpirationDate(payload.ExpirationDate, secretCreatedAt)
+	if err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	name := mux.Vars(request)["robot"]
+
+	robot, err := rh.c.MetaDB.GetRobotAccount(name)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRobotAccountNotFound) {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Str("robot", name).Msg("failed to get robot account")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	secret, hashedSecret, err := rh.generateRobotSecret()
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	robot.HashedSecret = hashedSecret
+	robot.SecretCreatedAt = secretCreatedAt
+	robot.SecretExpirationDate = expirationDate
+
+	if err := rh.c.MetaDB.UpdateRobotAccount(robot); err != nil {
+		if errors.Is(err, zerr.ErrRobotAccountNotFound) {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		rh.c.Log.Error().Err(err).Str("robot", name).Msg("failed to update robot account")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.c.Log.Info().Str("robot", name).Msg("robot account secret rotated")
+
+	robotInfo := newRobotAccountInfo(robot)
+	robotInfo.Secret = secret
+
+	zcommon.WriteJSON(response, http.StatusOK, robotInfo)
+}
@@ -0,0 +1,369 @@
+package api_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+func TestRobotAccounts(t *testing.T) {
+	Convey("Make a new controller with robot accounts", t, func() {
+		adminUser, adminPassword := "admin", "admin"
+		user, password := "user", "user"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n" +
+			test.GetCredString(user, password))
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		auditPath := path.Join(t.TempDir(), "audit.log")
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd:      config.AuthHTPasswd{Path: htpasswdPath},
+			RobotAccounts: true,
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				test.AuthorizationAllRepos: config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{user},
+							Actions: []string{constants.ReadPermission},
+						},
+					},
+					DefaultPolicy: []string{constants.ReadPermission},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users: []string{adminUser},
+				Actions: []string{
+					constants.ReadPermission, constants.CreatePermission,
+					constants.UpdatePermission, constants.DeletePermission,
+				},
+			},
+		}
+		conf.Log.Audit = auditPath
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		robotsURL := baseURL + constants.RobotAccountsPath
+
+		createRobot := func(payload string) (*resty.Response, api.RobotAccountInfo) {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).SetBody(payload).Post(robotsURL)
+			So(err, ShouldBeNil)
+
+			robot := api.RobotAccountInfo{}
+
+			if resp.StatusCode() == http.StatusCreated {
+				err = json.Unmarshal(resp.Body(), &robot)
+				So(err, ShouldBeNil)
+			}
+
+			return resp, robot
+		}
+
+		resp, robot := createRobot(`{"name": "ci", "description": "ci builds",
+			"repositories": ["ci/**"], "actions": ["read", "create"]}`)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+		So(robot.Username, ShouldEqual, "robot$ci")
+		So(robot.Secret, ShouldStartWith, constants.RobotSecretsPrefix)
+		So(robot.CreatedBy, ShouldEqual, adminUser)
+		So(robot.SecretExpirationDate.IsZero(), ShouldBeTrue)
+
+		img := CreateRandomImage()
+
+		Convey("Robot accounts can only act on their scope", func() {
+			err := UploadImageWithBasicAuth(img, baseURL, "ci/app", "latest", robot.Username, robot.Secret)
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth(robot.Username, robot.Secret).
+				Get(baseURL + "/v2/ci/app/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			// not allowed by the actions of the robot account
+			resp, err = resty.R().SetBasicAuth(robot.Username, robot.Secret).
+				Delete(baseURL + "/v2/ci/app/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			// the default policy doesn't apply to robot accounts
+			err = UploadImageWithBasicAuth(img, baseURL, "other", "latest", adminUser, adminPassword)
+			So(err, ShouldBeNil)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, robot.Secret).
+				Get(baseURL + "/v2/other/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			err = UploadImageWithBasicAuth(img, baseURL, "other", "latest", robot.Username, robot.Secret)
+			So(err, ShouldNotBeNil)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, "invalid").
+				Get(baseURL + "/v2/ci/app/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, constants.RobotSecretsPrefix+"invalid").
+				Get(baseURL + "/v2/ci/app/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth("robot$missing", robot.Secret).
+				Get(baseURL + "/v2/ci/app/manifests/latest")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			// robot accounts can't manage robot accounts
+			resp, err = resty.R().SetBasicAuth(robot.Username, robot.Secret).Get(robotsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			// the robot account is a distinct principal in the audit logs
+			auditLog, err := os.ReadFile(auditPath)
+			So(err, ShouldBeNil)
+			So(string(auditLog), ShouldContainSubstring, `"subject":"robot$ci"`)
+		})
+
+		Convey("Expired secrets are rejected", func() {
+			robotAccount, err := ctlr.MetaDB.GetRobotAccount("ci")
+			So(err, ShouldBeNil)
+
+			robotAccount.SecretExpirationDate = time.Now().Add(-time.Minute)
+
+			err = ctlr.MetaDB.UpdateRobotAccount(robotAccount)
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth(robot.Username, robot.Secret).Get(baseURL + "/v2/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(robotsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			robotList := api.RobotAccountList{}
+
+			err = json.Unmarshal(resp.Body(), &robotList)
+			So(err, ShouldBeNil)
+			So(robotList.Robots, ShouldHaveLength, 1)
+			So(robotList.Robots[0].IsExpired, ShouldBeTrue)
+			So(robotList.Robots[0].Secret, ShouldBeEmpty)
+		})
+
+		Convey("Secrets are rotated", func() {
+			expirationDate := time.Now().Add(time.Hour).Format(constants.APIKeyTimeFormat)
+
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).
+				SetBody(`{"expirationDate": "` + expirationDate + `"}`).Post(robotsURL + "/ci/rotate")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			rotatedRobot := api.RobotAccountInfo{}
+
+			err = json.Unmarshal(resp.Body(), &rotatedRobot)
+			So(err, ShouldBeNil)
+			So(rotatedRobot.Secret, ShouldNotEqual, robot.Secret)
+			So(rotatedRobot.SecretExpirationDate.Format(constants.APIKeyTimeFormat), ShouldEqual, expirationDate)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, robot.Secret).Get(baseURL + "/v2/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, rotatedRobot.Secret).Get(baseURL + "/v2/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			// the payload is optional
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(robotsURL + "/ci/rotate")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &rotatedRobot)
+			So(err, ShouldBeNil)
+			So(rotatedRobot.SecretExpirationDate.IsZero(), ShouldBeTrue)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).
+				SetBody(`{"expirationDate": "2020-01-02T03:04:05Z"}`).Post(robotsURL + "/ci/rotate")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).SetBody(`{`).Post(robotsURL + "/ci/rotate")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(robotsURL + "/missing/rotate")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Robot accounts are deleted", func() {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Delete(robotsURL + "/ci")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			resp, err = resty.R().SetBasicAuth(robot.Username, robot.Secret).Get(baseURL + "/v2/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Delete(robotsURL + "/ci")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Invalid robot accounts are rejected", func() {
+			for _, payload := range []string{
+				`{`,
+				`{"name": "Invalid Name", "repositories": ["**"], "actions": ["read"]}`,
+				`{"name": "robot", "actions": ["read"]}`,
+				`{"name": "robot", "repositories": ["**"]}`,
+				`{"name": "robot", "repositories": ["[a-"], "actions": ["read"]}`,
+				`{"name": "robot", "repositories": ["**"], "actions": ["detectManifestCollision"]}`,
+				`{"name": "robot", "repositories": ["**"], "actions": ["read"], "expirationDate": "tomorrow"}`,
+				`{"name": "robot", "repositories": ["**"], "actions": ["read"], "expirationDate": "2020-01-02T03:04:05Z"}`,
+			} {
+				resp, _ := createRobot(payload)
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			}
+
+			resp, _ := createRobot(`{"name": "ci", "repositories": ["**"], "actions": ["read"]}`)
+			So(resp.StatusCode(), ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("Only admins can manage robot accounts", func() {
+			resp, err := resty.R().SetBasicAuth(user, password).Get(robotsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().SetBasicAuth(user, password).
+				SetBody(`{"name": "other", "repositories": ["**"], "actions": ["read"]}`).Post(robotsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().Get(robotsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+
+	Convey("Robot accounts get bearer tokens from the token service", t, func() {
+		adminUser, adminPassword := "admin", "admin"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword))
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{Path: htpasswdPath},
+			TokenService: &config.TokenServiceConfig{
+				Key:     writeTokenSigningKey(t, signingKey),
+				Service: "zot-registry",
+			},
+			RobotAccounts: true,
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		adminToken := getBearerToken(baseURL, adminUser, adminPassword)
+
+		resp, err := resty.R().SetAuthToken(adminToken).
+			SetBody(`{"name": "mirror", "repositories": ["mirror/**"], "actions": ["read", "create"]}`).
+			Post(baseURL + constants.RobotAccountsPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		robot := api.RobotAccountInfo{}
+
+		err = json.Unmarshal(resp.Body(), &robot)
+		So(err, ShouldBeNil)
+
+		token := getBearerToken(baseURL, robot.Username, robot.Secret,
+			"repository:mirror/app:pull,push", "repository:other:pull")
+
+		resp, err = resty.R().SetAuthToken(token).Post(baseURL + "/v2/mirror/app/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		// the token doesn't grant access out of the scope of the robot account
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/v2/other/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + constants.RobotAccountsPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		// the tokens of deleted robot accounts are rejected
+		resp, err = resty.R().SetAuthToken(adminToken).Delete(baseURL + constants.RobotAccountsPath + "/mirror")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetAuthToken(token).Post(baseURL + "/v2/mirror/app/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+	})
+}
+
+func getBearerToken(baseURL, username, password string, scopes ...string) string {
+	client := resty.R().SetBasicAuth(username, password).SetQueryParam("service", "zot-registry")
+
+	for _, scope := range scopes {
+		client.QueryParam.Add("scope", scope)
+	}
+
+	resp, err := client.Get(baseURL + constants.TokenPath)
+	So(err, ShouldBeNil)
+	So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+	var token api.TokenResponse
+
+	err = json.Unmarshal(resp.Body(), &token)
+	So(err, ShouldBeNil)
+
+	return strings.TrimSpace(token.Token)
+}
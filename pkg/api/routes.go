@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	guuid "github.com/gofrs/uuid"
 	"github.com/google/go-github/v62/github"
 	"github.com/gorilla/mux"
@@ -37,6 +38,7 @@ import (
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	apiErr "zotregistry.dev/zot/pkg/api/errors"
+	"zotregistry.dev/zot/pkg/cluster"
 	zcommon "zotregistry.dev/zot/pkg/common"
 	gqlPlayground "zotregistry.dev/zot/pkg/debug/gqlplayground"
 	"zotregistry.dev/zot/pkg/debug/pprof"
@@ -49,6 +51,7 @@ import (
 	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
 	"zotregistry.dev/zot/pkg/storage"
 	storageCommon "zotregistry.dev/zot/pkg/storage/common"
+	storageConstants "zotregistry.dev/zot/pkg/storage/constants"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 	"zotregistry.dev/zot/pkg/test/inject"
 )
@@ -64,6 +67,20 @@ func NewRouteHandler(c *Controller) *RouteHandler {
 	return rh
 }
 
+// newAdminRouter returns a router for the given path which is only accessible to admins.
+func (rh *RouteHandler) newAdminRouter(pathPrefix string, authHandler mux.MiddlewareFunc) *mux.Router {
+	adminRouter := rh.c.Router.PathPrefix(pathPrefix).Subrouter()
+	adminRouter.Use(authHandler)
+
+	if rh.c.Config.HTTP.AccessControl != nil {
+		adminRouter.Use(BaseAuthzHandler(rh.c))
+	}
+
+	adminRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(rh.c.Config))
+
+	return adminRouter
+}
+
 func (rh *RouteHandler) SetupRoutes() {
 	// health endpoints get added first
 	rh.c.Router.Path("/livez").Handler(rh.c.Healthz.Handler)
@@ -101,12 +118,51 @@ func (rh *RouteHandler) SetupRoutes() {
 		apiKeyRouter.Use(zcommon.CORSHeadersMiddleware(rh.c.Config.HTTP.AllowOrigin))
 		apiKeyRouter.Use(zcommon.ACHeadersMiddleware(rh.c.Config,
 			http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions))
+		apiKeyRouter.Use(DenyScopedUsersHandler(rh.c))
 
 		apiKeyRouter.Methods(http.MethodPost, http.MethodOptions).HandlerFunc(rh.CreateAPIKey)
 		apiKeyRouter.Methods(http.MethodGet).HandlerFunc(rh.GetAPIKeys)
 		apiKeyRouter.Methods(http.MethodDelete).HandlerFunc(rh.RevokeAPIKey)
 	}
 
+	if rh.c.TokenService != nil {
+		// built-in token realm issuing bearer tokens to the users authenticated by the other backends
+		tokenRouter := rh.c.Router.PathPrefix(constants.TokenPath).Subrouter()
+		tokenRouter.Use(authHandler)
+		tokenRouter.Methods(http.MethodGet).HandlerFunc(rh.GetToken)
+	}
+
+	if rh.c.Config.IsAdminPolicyEnforced() {
+		// admin api for running gc, scrub and dedupe on demand, not exposed to users who can't be told apart from admins
+		adminJobsRouter := rh.newAdminRouter(constants.AdminJobsPath, authHandler)
+		adminJobsRouter.Methods(http.MethodPost).Path("").HandlerFunc(rh.CreateJob)
+		adminJobsRouter.Methods(http.MethodGet).Path("").HandlerFunc(rh.ListJobs)
+		adminJobsRouter.Methods(http.MethodGet).Path("/{id}").HandlerFunc(rh.GetJob)
+	}
+
+	if rh.c.Config.IsRobotAccountsEnabled() {
+		// admin api for the robot accounts, which authenticate with a secret instead of a password
+		robotsRouter := rh.newAdminRouter(constants.RobotAccountsPath, authHandler)
+		robotsRouter.Methods(http.MethodPost).Path("").HandlerFunc(rh.CreateRobotAccount)
+		robotsRouter.Methods(http.MethodGet).Path("").HandlerFunc(rh.ListRobotAccounts)
+		robotsRouter.Methods(http.MethodDelete).Path("/{robot}").HandlerFunc(rh.DeleteRobotAccount)
+		robotsRouter.Methods(http.MethodPost).Path("/{robot}/rotate").HandlerFunc(rh.RotateRobotSecret)
+	}
+
+	if rh.c.Config.IsAccessControlInMetaDB() {
+		// admin api for the versioned access control policies stored in MetaDB
+		policiesRouter := rh.newAdminRouter(constants.AccessControlPoliciesPath, authHandler)
+		policiesRouter.Methods(http.MethodGet).Path("").HandlerFunc(rh.GetAccessControlPolicies)
+		policiesRouter.Methods(http.MethodPut).Path("").HandlerFunc(rh.UpdateAccessControlPolicies)
+		policiesRouter.Methods(http.MethodGet).Path("/history").HandlerFunc(rh.GetAccessControlPoliciesHistory)
+		policiesRouter.Methods(http.MethodPost).Path("/{version}/rollback").
+			HandlerFunc(rh.RollbackAccessControlPolicies)
+	}
+
+	// admin api for the events which could not be delivered
+	ext.SetupEventsRoutes(rh.c.Config, rh.newAdminRouter(constants.AdminEventsPath, authHandler),
+		rh.c.EventRecorder, rh.c.Log)
+
 	/* on every route which may be used by UI we set OPTIONS as allowed METHOD
 	to enable preflight request from UI to backend */
 	if rh.c.Config.IsBasicAuthnEnabled() {
@@ -131,6 +187,13 @@ func (rh *RouteHandler) SetupRoutes() {
 
 		prefixedRouter.Use(BaseAuthzHandler(rh.c))
 		prefixedDistSpecRouter.Use(DistSpecAuthzHandler(rh.c))
+	} else if rh.c.Config.IsAPIKeyEnabled() || rh.c.Config.IsRobotAccountsEnabled() {
+		prefixedDistSpecRouter.Use(ScopeAuthzHandler(rh.c))
+	}
+
+	// pulls are counted once authorized, per user, api key, client address or repository
+	if rh.c.Config.IsRatelimitPolicyEnabled() {
+		prefixedDistSpecRouter.Use(RepositoryRateLimiter(rh.c))
 	}
 
 	clusterRouteProxy := ClusterProxy(rh.c)
@@ -216,7 +279,7 @@ func (rh *RouteHandler) SetupRoutes() {
 	ext.SetupSearchRoutes(rh.c.Config, prefixedRouter, rh.c.StoreController, rh.c.MetaDB, rh.c.CveScanner,
 		rh.c.Log)
 	ext.SetupImageTrustRoutes(rh.c.Config, prefixedRouter, rh.c.MetaDB, rh.c.Log)
-	ext.SetupMgmtRoutes(rh.c.Config, prefixedRouter, rh.c.Log)
+	ext.SetupMgmtRoutes(rh.c.Config, prefixedRouter, rh.c.StoreController, rh.c.MetaDB, rh.c.Log)
 	ext.SetupUserPreferencesRoutes(rh.c.Config, prefixedRouter, rh.c.MetaDB, rh.c.Log)
 	// last should always be UI because it will setup a http.FileServer and paths will be resolved by this FileServer.
 	ext.SetupUIRoutes(rh.c.Config, rh.c.Router, rh.c.Log)
@@ -272,6 +335,8 @@ func (rh *RouteHandler) CheckVersionSupport(response http.ResponseWriter, reques
 		if request.Header.Get(constants.SessionClientHeaderName) != constants.SessionClientHeaderValue {
 			if rh.c.Config.HTTP.Auth.Bearer != nil {
 				response.Header().Set("WWW-Authenticate", "bearer realm="+rh.c.Config.HTTP.Auth.Bearer.Realm)
+			} else if rh.c.TokenService != nil {
+				response.Header().Set("WWW-Authenticate", rh.c.TokenService.Challenge(request, nil).Header())
 			} else {
 				response.Header().Set("WWW-Authenticate", "basic realm="+rh.c.Config.HTTP.Realm)
 			}
@@ -353,7 +418,7 @@ func (rh *RouteHandler) ListTags(response http.ResponseWriter, request *http.Req
 		last = lastQuery[0]
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	tags, err := imgStore.GetImageTags(name)
 	if err != nil {
@@ -441,7 +506,7 @@ func (rh *RouteHandler) CheckManifest(response http.ResponseWriter, request *htt
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
@@ -515,7 +580,7 @@ func (rh *RouteHandler) GetManifest(response http.ResponseWriter, request *http.
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
@@ -551,8 +616,22 @@ func (rh *RouteHandler) GetManifest(response http.ResponseWriter, request *http.
 		return
 	}
 
-	if rh.c.MetaDB != nil {
-		err := meta.OnGetManifest(name, reference, mediaType, content, rh.c.StoreController, rh.c.MetaDB, rh.c.Log)
+	if err := rh.checkImageTrust(request.Context(), name, reference, digest, content, false); err != nil {
+		if errors.Is(err, zerr.ErrImageNotTrusted) {
+			details := zerr.GetDetails(err)
+			details["reference"] = reference
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+		} else {
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	if rh.c.MetaDB != nil || rh.c.EventRecorder != nil {
+		err := meta.OnGetManifest(name, reference, mediaType, digest, content, rh.c.StoreController, rh.c.MetaDB,
+			rh.c.EventRecorder, rh.c.Log)
 		if err != nil && !errors.Is(err, zerr.ErrImageMetaNotFound) && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
 			response.WriteHeader(http.StatusInternalServerError)
 
@@ -574,7 +653,7 @@ func getReferrers(ctx context.Context, routeHandler *RouteHandler,
 	imgStore storageTypes.ImageStore, name string, digest godigest.Digest,
 	artifactTypes []string,
 ) (ispec.Index, error) {
-	if isSyncOnDemandEnabled(*routeHandler.c) {
+	if isSyncOnDemandEnabled(routeHandler.c) {
 		routeHandler.c.Log.Info().Str("repository", name).Str("reference", digest.String()).
 			Msg("trying to get updated referrers by syncing on demand")
 
@@ -627,7 +706,7 @@ func (rh *RouteHandler) GetReferrers(response http.ResponseWriter, request *http
 
 	rh.c.Log.Info().Str("digest", digest.String()).Interface("artifactType", artifactTypes).Msg("getting manifest")
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	referrers, err := getReferrers(request.Context(), rh, imgStore, name, digest, artifactTypes)
 	if err != nil {
@@ -683,7 +762,7 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
@@ -711,6 +790,55 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	// immutable tags can't be moved to another manifest, re-pushing the same manifest is allowed
+	if !zcommon.IsDigest(reference) && rh.c.Config.IsImmutableTag(rh.c.AccessControlConfig(), name, reference) {
+		_, currentDigest, _, err := imgStore.GetImageManifest(name, reference)
+		if err == nil && currentDigest.Validate() == nil && currentDigest != currentDigest.Algorithm().FromBytes(body) {
+			if err := rh.checkImmutableTags(userAc, name, reference); err != nil {
+				e := apiErr.NewError(apiErr.DENIED).AddDetail(zerr.GetDetails(err))
+				zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+				return
+			}
+		}
+	}
+
+	// manifests are pushed by digest and signed before being tagged
+	if !zcommon.IsDigest(reference) {
+		manifestDigest := godigest.FromBytes(body)
+
+		if err := rh.checkImageTrust(request.Context(), name, reference, manifestDigest, body, true); err != nil {
+			if errors.Is(err, zerr.ErrImageNotTrusted) {
+				details := zerr.GetDetails(err)
+				details["reference"] = reference
+				e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+				zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+			} else {
+				response.WriteHeader(http.StatusInternalServerError)
+			}
+
+			return
+		}
+	}
+
+	if rh.c.Linter != nil {
+		if err := rh.c.Linter.Admit(request.Context(), name, reference, mediaType, body, imgStore); err != nil {
+			details := zerr.GetDetails(err)
+			details["reference"] = reference
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+			return
+		}
+	}
+
 	digest, subjectDigest, err := imgStore.PutImageManifest(name, reference, mediaType, body)
 	if err != nil {
 		details := zerr.GetDetails(err)
@@ -734,6 +862,10 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 			details["reference"] = reference
 			e := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["reference"] = reference
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error, performing cleanup")
@@ -762,6 +894,8 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		}
 	}
 
+	rh.recordManifestPushed(name, reference, mediaType, digest, subjectDigest, body)
+
 	if subjectDigest.String() != "" {
 		response.Header().Set(constants.SubjectDigestKey, subjectDigest.String())
 	}
@@ -790,7 +924,7 @@ func (rh *RouteHandler) DeleteManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
@@ -835,6 +969,19 @@ func (rh *RouteHandler) DeleteManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
+	// deleting a manifest by digest also removes all the tags pointing to it
+	tags := []string{reference}
+	if zcommon.IsDigest(reference) {
+		tags = getManifestTags(imgStore, name, manifestDigest, rh.c.Log)
+	}
+
+	if err := rh.checkImmutableTags(userAc, name, tags...); err != nil {
+		e := apiErr.NewError(apiErr.DENIED).AddDetail(zerr.GetDetails(err))
+		zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+		return
+	}
+
 	err = imgStore.DeleteImageManifest(name, reference, detectCollision)
 	if err != nil { //nolint: dupl
 		details := zerr.GetDetails(err)
@@ -877,9 +1024,57 @@ func (rh *RouteHandler) DeleteManifest(response http.ResponseWriter, request *ht
 		}
 	}
 
+	rh.recordManifestDeleted(name, reference, manifestDigest, manifestBlob)
+
 	response.WriteHeader(http.StatusAccepted)
 }
 
+// checkImageTrust applies the image trust policies to the manifest.
+func (rh *RouteHandler) checkImageTrust(ctx context.Context, repo, reference string, digest godigest.Digest,
+	content []byte, push bool,
+) error {
+	return ext.CheckImageTrustPolicy(ctx, rh.c.Config, rh.c.MetaDB, repo, reference, digest, content, push, rh.c.Log)
+}
+
+// checkImmutableTags returns ErrImmutableTag if any of the tags is protected by an immutable tags policy
+// in repo and the user is not allowed to override it.
+func (rh *RouteHandler) checkImmutableTags(userAc *reqCtx.UserAccessControl, repo string, tags ...string) error {
+	for _, tag := range tags {
+		if !rh.c.Config.IsImmutableTag(rh.c.AccessControlConfig(), repo, tag) {
+			continue
+		}
+
+		if userAc != nil && userAc.Can(constants.OverrideImmutableTagsPermission, repo) {
+			return nil
+		}
+
+		rh.c.Log.Info().Str("repository", repo).Str("tag", tag).Msg("denied changing an immutable tag")
+
+		return zerr.NewError(zerr.ErrImmutableTag).AddDetail("tag", tag)
+	}
+
+	return nil
+}
+
+// getManifestTags returns the tags pointing to the manifest with the given digest in repo.
+func getManifestTags(imgStore storageTypes.ImageStore, repo string, digest godigest.Digest, logger log.Logger,
+) []string {
+	tags := []string{}
+
+	index, err := storageCommon.GetIndex(imgStore, repo, logger)
+	if err != nil {
+		return tags
+	}
+
+	for _, desc := range index.Manifests {
+		if tag, ok := desc.Annotations[ispec.AnnotationRefName]; ok && desc.Digest == digest {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
 // canMount checks if a user has read permission on cached blobs with this specific digest.
 // returns true if the user have permission to copy blob from cache.
 func canMount(userAc *reqCtx.UserAccessControl, imgStore storageTypes.ImageStore, digest godigest.Digest,
@@ -909,6 +1104,46 @@ func canMount(userAc *reqCtx.UserAccessControl, imgStore storageTypes.ImageStore
 	return canMount, nil
 }
 
+// canMountFrom checks if a blob can be mounted from the fromRepo repository into a repository of imgStore.
+// the user needs read access to fromRepo, which must be stored by the same image store and, in a scale-out
+// cluster using local storage, be served by the local member.
+// If it can't, the mount request falls back to a regular upload (202 Accepted) as allowed by the dist-spec.
+func (rh *RouteHandler) canMountFrom(userAc *reqCtx.UserAccessControl, imgStore storageTypes.ImageStore,
+	fromRepo string,
+) bool {
+	if fromRepo == "" {
+		return false
+	}
+
+	if rh.c.Config.IsAuthzEnabled() && userAc != nil && !userAc.Can(constants.ReadPermission, fromRepo) {
+		return false
+	}
+
+	if rh.c.StoreController.GetImageStore(fromRepo).RootDir() != imgStore.RootDir() {
+		rh.c.Log.Debug().Str("from", fromRepo).
+			Msg("blob mount source is stored by another image store, falling back to a regular upload")
+
+		return false
+	}
+
+	// members sharing their storage (eg. s3) all see the blobs of fromRepo, while local storage
+	// only holds the repositories owned by this member.
+	clusterConfig := rh.c.ClusterConfig()
+	if clusterConfig != nil && len(clusterConfig.Members) > 1 &&
+		imgStore.Name() == storageConstants.LocalStorageDriverName {
+		targetMemberIndex, _ := cluster.ComputeTargetMemberWithHashing(clusterConfig.Hashing, clusterConfig.HashKey,
+			clusterConfig.Members, fromRepo)
+		if targetMemberIndex != clusterConfig.Proxy.LocalMemberClusterSocketIndex {
+			rh.c.Log.Debug().Str("from", fromRepo).
+				Msg("blob mount source is owned by another cluster member, falling back to a regular upload")
+
+			return false
+		}
+	}
+
+	return true
+}
+
 // CheckBlob godoc
 // @Summary Check image blob/layer
 // @Description Check an image's blob/layer given a digest
@@ -929,7 +1164,7 @@ func (rh *RouteHandler) CheckBlob(response http.ResponseWriter, request *http.Re
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	digestStr, ok := vars["digest"]
 
@@ -983,6 +1218,10 @@ func (rh *RouteHandler) CheckBlob(response http.ResponseWriter, request *http.Re
 			details["digest"] = digest.String()
 			e := apiErr.NewError(apiErr.BLOB_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["digest"] = digest.String()
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			rh.c.Log.Error().Err(err).Msg("unexpected error")
 			response.WriteHeader(http.StatusInternalServerError)
@@ -1071,7 +1310,7 @@ func (rh *RouteHandler) GetBlob(response http.ResponseWriter, request *http.Requ
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	digestStr, ok := vars["digest"]
 
@@ -1190,7 +1429,7 @@ func (rh *RouteHandler) DeleteBlob(response http.ResponseWriter, request *http.R
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	err = imgStore.DeleteBlob(name, digest)
 	if err != nil {
@@ -1228,10 +1467,15 @@ func (rh *RouteHandler) DeleteBlob(response http.ResponseWriter, request *http.R
 // @Accept  json
 // @Produce json
 // @Param   name    path    string     true        "repository name"
+// @Param   mount   query   string     false       "digest of the blob to mount"
+// @Param   from    query   string     false       "repository to mount the blob from"
+// @Success 201 {string} string "created"
+// @Header  201 {string} Location "/v2/{name}/blobs/{digest}"
 // @Success 202 {string} string "accepted"
 // @Header  202 {string} Location "/v2/{name}/blobs/uploads/{session_id}"
 // @Header  202 {string} Range "0-0"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /v2/{name}/blobs/uploads [post].
@@ -1245,9 +1489,9 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
-	// currently zot does not support cross-repository mounting, following dist-spec and returning 202
+	// if the blob can't be mounted, following dist-spec a new upload session is started and 202 is returned
 	if mountDigests, ok := request.URL.Query()["mount"]; ok {
 		if len(mountDigests) != 1 {
 			response.WriteHeader(http.StatusBadRequest)
@@ -1265,18 +1509,39 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		}
 
 		userCanMount := true
-		if rh.c.Config.IsAuthzEnabled() {
-			userCanMount, err = canMount(userAc, imgStore, mountDigest)
-			if err != nil {
-				rh.c.Log.Error().Err(err).Msg("unexpected error")
+
+		if fromRepos, ok := request.URL.Query()["from"]; ok {
+			if len(fromRepos) != 1 {
+				response.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			// mount the blob from the requested repository
+			userCanMount = rh.canMountFrom(userAc, imgStore, fromRepos[0])
+			if userCanMount {
+				_, err = imgStore.MountBlob(name, fromRepos[0], mountDigest)
+			}
+		} else {
+			if rh.c.Config.IsAuthzEnabled() {
+				userCanMount, err = canMount(userAc, imgStore, mountDigest)
+				if err != nil {
+					rh.c.Log.Error().Err(err).Msg("unexpected error")
+				}
+			}
+
+			// without a source repository, check blob looks for actual path (name+mountDigests[0]) first
+			// then look for cache and if found in cache, will do hard link and if fails we will start new upload.
+			if userCanMount {
+				_, _, err = imgStore.CheckBlob(name, mountDigest)
 			}
 		}
 
-		// zot does not support cross mounting directly and do a workaround creating using hard link.
-		// check blob looks for actual path (name+mountDigests[0]) first then look for cache and
-		// if found in cache, will do hard link and if fails we will start new upload.
-		if userCanMount {
-			_, _, err = imgStore.CheckBlob(name, mountDigest)
+		if errors.Is(err, zerr.ErrQuotaExceeded) {
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(zerr.GetDetails(err))
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+			return
 		}
 
 		if err != nil || !userCanMount {
@@ -1303,6 +1568,7 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		}
 
 		response.Header().Set("Location", getBlobUploadLocation(request.URL, name, mountDigest))
+		response.Header().Set(constants.DistContentDigestKey, mountDigest.String())
 		response.WriteHeader(http.StatusCreated)
 
 		return
@@ -1356,6 +1622,15 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 
 		sessionID, size, err := imgStore.FullBlobUpload(name, request.Body, digest)
 		if err != nil {
+			if errors.Is(err, zerr.ErrQuotaExceeded) {
+				details := zerr.GetDetails(err)
+				details["digest"] = digest.String()
+				e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+				zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+				return
+			}
+
 			rh.c.Log.Error().Err(err).Int64("actual", size).Int64("expected", contentLength).
 				Msg("failed to full blob upload")
 			response.WriteHeader(http.StatusInternalServerError)
@@ -1420,7 +1695,7 @@ func (rh *RouteHandler) GetBlobUpload(response http.ResponseWriter, request *htt
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
@@ -1484,7 +1759,7 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
@@ -1535,6 +1810,10 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 			details["session_id"] = sessionID
 			e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["session_id"] = sessionID
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error, removing .uploads/ files")
@@ -1581,7 +1860,7 @@ func (rh *RouteHandler) UpdateBlobUpload(response http.ResponseWriter, request *
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
@@ -1658,6 +1937,10 @@ func (rh *RouteHandler) UpdateBlobUpload(response http.ResponseWriter, request *
 				details["session_id"] = sessionID
 				e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 				zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+			} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+				details["session_id"] = sessionID
+				e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+				zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 			} else {
 				// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 				rh.c.Log.Error().Err(err).Msg("unexpected error, removing .uploads/ files")
@@ -1694,6 +1977,10 @@ finish:
 			details["session_id"] = sessionID
 			e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["session_id"] = sessionID
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error, removing .uploads/ files")
@@ -1735,7 +2022,7 @@ func (rh *RouteHandler) DeleteBlobUpload(response http.ResponseWriter, request *
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
+	imgStore := rh.getImageStore(request.Context(), name)
 
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
@@ -2081,16 +2368,17 @@ func WriteDataFromReader(response http.ResponseWriter, status int, length int64,
 	}
 }
 
-// will return image storage corresponding to subpath provided in config.
-func (rh *RouteHandler) getImageStore(name string) storageTypes.ImageStore {
-	return rh.c.StoreController.GetImageStore(name)
+// will return image storage corresponding to subpath provided in config,
+// storage operations are traced as part of the request if tracing is enabled.
+func (rh *RouteHandler) getImageStore(ctx context.Context, name string) storageTypes.ImageStore {
+	return rh.c.StoreController.GetImageStore(name).WithContext(ctx)
 }
 
 // will sync on demand if an image is not found, in case sync extensions is enabled.
 func getImageManifest(ctx context.Context, routeHandler *RouteHandler, imgStore storageTypes.ImageStore, name,
 	reference string,
 ) ([]byte, godigest.Digest, string, error) {
-	syncEnabled := isSyncOnDemandEnabled(*routeHandler.c)
+	syncEnabled := isSyncOnDemandEnabled(routeHandler.c)
 
 	_, digestErr := godigest.Parse(reference)
 	if digestErr == nil {
@@ -2118,6 +2406,10 @@ type APIKeyPayload struct { //nolint:revive
 	Label          string   `json:"label"`
 	Scopes         []string `json:"scopes"`
 	ExpirationDate string   `json:"expirationDate"`
+	// restrict the key to some repositories (glob patterns) and actions (read, create, update, delete),
+	// the key has all the permissions of its owner if both are empty
+	Repositories []string `json:"repositories"`
+	Actions      []string `json:"actions"`
 }
 
 // GetAPIKeys godoc
@@ -2163,6 +2455,8 @@ func (rh *RouteHandler) GetAPIKeys(resp http.ResponseWriter, req *http.Request)
 // CreateAPIKey godoc
 // @Summary Create an API key for the current user
 // @Description Can create an api key for a logged in user, based on the provided label and scopes.
+// @Description The key can be restricted to some repositories and actions, otherwise it has all the permissions
+// @Description of the user.
 // @Accept  json
 // @Produce json
 // @Param   id  body  APIKeyPayload  true  "api token id (UUID)"
@@ -2219,6 +2513,12 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 		}
 	}
 
+	if !isValidAPIKeyScope(payload.Repositories, payload.Actions) {
+		resp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
 	apiKeyDetails := &mTypes.APIKeyDetails{
 		CreatedAt:      createdAt,
 		ExpirationDate: expirationDate,
@@ -2228,6 +2528,8 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 		Label:          payload.Label,
 		Scopes:         payload.Scopes,
 		UUID:           apiKeyID,
+		Repositories:   payload.Repositories,
+		Actions:        payload.Actions,
 	}
 
 	err = rh.c.MetaDB.AddUserAPIKey(req.Context(), hashedAPIKey, apiKeyDetails)
@@ -2262,6 +2564,27 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 	_, _ = resp.Write(data)
 }
 
+// isValidAPIKeyScope checks the repositories of an api key are valid glob patterns
+// and its actions are among the ones checked by the access control policies.
+func isValidAPIKeyScope(repositories, actions []string) bool {
+	for _, pattern := range repositories {
+		if pattern == "" || !glob.ValidatePattern(pattern) {
+			return false
+		}
+	}
+
+	for _, action := range actions {
+		if !slices.Contains([]string{
+			constants.ReadPermission, constants.CreatePermission,
+			constants.UpdatePermission, constants.DeletePermission,
+		}, action) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // RevokeAPIKey godoc
 // @Summary Revokes one current user API key
 // @Description Revokes one current user API key based on given key ID
@@ -2294,6 +2617,70 @@ func (rh *RouteHandler) RevokeAPIKey(resp http.ResponseWriter, req *http.Request
 	resp.WriteHeader(http.StatusOK)
 }
 
+// GetToken godoc
+// @Summary Get a bearer token
+// @Description Issues a token granting the requested scopes which the access control policies allow to the
+// @Description authenticated user, as specified by the distribution token authentication specification.
+// @Produce json
+// @Param   service  query  string  false  "service the token is requested for"
+// @Param   scope    query  string  false  "requested scope, eg. repository:name:pull,push"
+// @Success 200 {object} api.TokenResponse
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/auth/token [get].
+func (rh *RouteHandler) GetToken(response http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	service := query.Get("service")
+	if service != "" && service != rh.c.Config.HTTP.Auth.TokenService.Service {
+		rh.c.Log.Info().Str("service", service).Msg("token requested for an unknown service")
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	// scopes are given either as separate query params or separated by spaces
+	scopes := []string{}
+	for _, scope := range query["scope"] {
+		scopes = append(scopes, strings.Fields(scope)...)
+	}
+
+	access, err := GetGrantedAccess(rh.c.AccessControlConfig(), userAc, scopes)
+	if err != nil {
+		rh.c.Log.Info().Err(err).Msg("failed to parse requested token scopes")
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	// the requests authenticated with the token are restricted as the ones authenticated with the api key,
+	// robot accounts are restricted by their current scope when their tokens are verified
+	var scope *TokenScope
+
+	if userAc.IsScoped() && !userAc.IsRobot() {
+		repositories, actions := userAc.GetScope()
+		scope = &TokenScope{Repositories: repositories, Actions: actions}
+	}
+
+	token, err := rh.c.TokenService.IssueToken(userAc.GetUsername(), access, scope)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to issue token")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, token)
+}
+
 // GetBlobUploadSessionLocation returns actual blob location to start/resume uploading blobs.
 // e.g. /v2/<name>/blobs/uploads/<session-id>.
 func getBlobUploadSessionLocation(url *url.URL, sessionID string) string {
@@ -2322,7 +2709,7 @@ func getBlobUploadLocation(url *url.URL, name string, digest godigest.Digest) st
 	return url.String()
 }
 
-func isSyncOnDemandEnabled(ctlr Controller) bool {
+func isSyncOnDemandEnabled(ctlr *Controller) bool {
 	if ctlr.Config.IsSyncEnabled() &&
 		fmt.Sprintf("%v", ctlr.SyncOnDemand) != fmt.Sprintf("%v", nil) {
 		return true
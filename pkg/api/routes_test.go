@@ -656,6 +656,59 @@ func TestRoutes(t *testing.T) {
 				})
 			So(statusCode, ShouldEqual, http.StatusNotFound)
 
+			// mount from another repository
+			statusCode = testCreateBlobUpload(
+				[]struct{ k, v string }{
+					{"mount", "1234"},
+					{"from", "source"},
+				},
+				map[string]string{},
+				&mocks.MockedImageStore{
+					MountBlobFn: func(repo, fromRepo string, digest godigest.Digest) (int64, error) {
+						return 0, nil
+					},
+				})
+			So(statusCode, ShouldEqual, http.StatusCreated)
+
+			// mount going over the storage quota
+			statusCode = testCreateBlobUpload(
+				[]struct{ k, v string }{
+					{"mount", "1234"},
+					{"from", "source"},
+				},
+				map[string]string{},
+				&mocks.MockedImageStore{
+					MountBlobFn: func(repo, fromRepo string, digest godigest.Digest) (int64, error) {
+						return -1, zerr.ErrQuotaExceeded
+					},
+				})
+			So(statusCode, ShouldEqual, http.StatusForbidden)
+
+			// blob missing from the source repository, a new upload is started instead
+			statusCode = testCreateBlobUpload(
+				[]struct{ k, v string }{
+					{"mount", "1234"},
+					{"from", "source"},
+				},
+				map[string]string{},
+				&mocks.MockedImageStore{
+					MountBlobFn: func(repo, fromRepo string, digest godigest.Digest) (int64, error) {
+						return -1, zerr.ErrBlobNotFound
+					},
+				})
+			So(statusCode, ShouldEqual, http.StatusAccepted)
+
+			// multiple source repositories
+			statusCode = testCreateBlobUpload(
+				[]struct{ k, v string }{
+					{"mount", "1234"},
+					{"from", "source"},
+					{"from", "other"},
+				},
+				map[string]string{},
+				&mocks.MockedImageStore{})
+			So(statusCode, ShouldEqual, http.StatusBadRequest)
+
 			// a full blob upload if multiple digests are present
 			statusCode = testCreateBlobUpload(
 				[]struct{ k, v string }{
@@ -0,0 +1,319 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	guuid "github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+)
+
+const (
+	defaultTokenExpiration = 5 * time.Minute
+
+	repositoryResourceType = "repository"
+
+	pullAction   = "pull"
+	pushAction   = "push"
+	deleteAction = "delete"
+	allActions   = "*"
+)
+
+// TokenResponse is returned by the token endpoint, as specified by the distribution token authentication
+// specification, 'token' and 'access_token' hold the same token for compatibility with both kinds of clients.
+type TokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"` //nolint:tagliatelle // token format
+	ExpiresIn   int    `json:"expires_in"`   //nolint:tagliatelle // token format
+	IssuedAt    string `json:"issued_at"`    //nolint:tagliatelle // token format
+}
+
+// TokenScope holds the repositories and actions of the scoped api key a token was issued to, so the token
+// doesn't grant more than the key itself.
+type TokenScope struct {
+	Repositories []string `json:"repositories,omitempty"`
+	Actions      []string `json:"actions,omitempty"`
+}
+
+// TokenService issues the bearer tokens of the built-in token realm and verifies them on the requests
+// sent to zot, the users are authenticated by the other authn backends and the scopes granted
+// to them are computed from the access control policies.
+type TokenService struct {
+	realm         string
+	service       string
+	issuer        string
+	expiration    time.Duration
+	signingKey    crypto.Signer
+	signingMethod jwt.SigningMethod
+}
+
+func NewTokenService(conf *config.TokenServiceConfig) (*TokenService, error) {
+	signingKey, err := loadPrivateKeyFromFile(conf.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	signingMethod, err := getSigningMethod(signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := conf.Issuer
+	if issuer == "" {
+		issuer = conf.Service
+	}
+
+	expiration := conf.Expiration
+	if expiration <= 0 {
+		expiration = defaultTokenExpiration
+	}
+
+	return &TokenService{
+		realm:         conf.Realm,
+		service:       conf.Service,
+		issuer:        issuer,
+		expiration:    expiration,
+		signingKey:    signingKey,
+		signingMethod: signingMethod,
+	}, nil
+}
+
+// IssueToken signs a token granting the given access to subject, an empty subject stands for anonymous users,
+// a non nil scope restricts the requests authenticated with the token as the scoped api key it was issued to.
+func (ts *TokenService) IssueToken(subject string, access []ResourceAccess, scope *TokenScope,
+) (TokenResponse, error) {
+	tokenID, err := guuid.NewV4()
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	now := time.Now()
+	// tolerate small clock skews between zot replicas sharing the same signing key
+	issuedAt := now.Add(-issuedAtOffset)
+
+	claims := ClaimsWithAccess{
+		Access: access,
+		Scope:  scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    ts.issuer,
+			Subject:   subject,
+			Audience:  []string{ts.service},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ts.expiration)),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ID:        tokenID.String(),
+		},
+	}
+
+	signedString, err := jwt.NewWithClaims(ts.signingMethod, claims).SignedString(ts.signingKey)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		Token:       signedString,
+		AccessToken: signedString,
+		ExpiresIn:   int(ts.expiration.Seconds()),
+		IssuedAt:    now.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// Verify checks the token in the Authorization header of the request was issued by this service and grants
+// the access needed by the request, if it doesn't an AuthChallengeError is returned.
+func (ts *TokenService) Verify(request *http.Request) (*ClaimsWithAccess, error) {
+	authorizer := NewBearerAuthorizer(ts.getRealm(request), ts.service, ts.signingKey.Public())
+
+	return authorizer.authorize(request.Header.Get("Authorization"), getRequestedAccess(request),
+		jwt.WithAudience(ts.service), jwt.WithIssuer(ts.issuer), jwt.WithExpirationRequired())
+}
+
+// Challenge returns the error used to ask the client for a token granting the access needed by the request.
+func (ts *TokenService) Challenge(request *http.Request, err error) *AuthChallengeError {
+	return &AuthChallengeError{
+		err:            err,
+		realm:          ts.getRealm(request),
+		service:        ts.service,
+		resourceAction: getRequestedAccess(request),
+	}
+}
+
+func (ts *TokenService) getRealm(request *http.Request) string {
+	if ts.realm != "" {
+		return ts.realm
+	}
+
+	scheme := "http"
+	if request.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + request.Host + constants.TokenPath
+}
+
+// GetGrantedAccess returns the subset of the requested scopes that the user is allowed by the access control
+// policies, the scopes are formatted as 'repository:<name>:<action>[,<action>]'.
+func GetGrantedAccess(accessControl *config.AccessControlConfig, userAc *reqCtx.UserAccessControl, scopes []string,
+) ([]ResourceAccess, error) {
+	access := []ResourceAccess{}
+
+	for _, scope := range scopes {
+		if scope == "" {
+			continue
+		}
+
+		// repository names can't contain ':' but the scope type may, eg. 'repository(plugin)'
+		firstSep := strings.Index(scope, ":")
+		lastSep := strings.LastIndex(scope, ":")
+
+		if firstSep == lastSep {
+			return nil, fmt.Errorf("%w: %s", zerr.ErrInvalidTokenScope, scope)
+		}
+
+		resourceType := scope[:firstSep]
+		name := scope[firstSep+1 : lastSep]
+		requestedActions := strings.Split(scope[lastSep+1:], ",")
+
+		// only repositories are protected by the access control policies
+		if resourceType != repositoryResourceType || name == "" {
+			continue
+		}
+
+		if slices.Contains(requestedActions, allActions) {
+			requestedActions = []string{pullAction, pushAction, deleteAction}
+		}
+
+		actions := []string{}
+
+		for _, action := range requestedActions {
+			if isActionAllowed(accessControl, userAc, action, name) && !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+
+		access = append(access, ResourceAccess{
+			Type:    resourceType,
+			Name:    name,
+			Actions: actions,
+		})
+	}
+
+	return access, nil
+}
+
+func isActionAllowed(accessControl *config.AccessControlConfig, userAc *reqCtx.UserAccessControl,
+	action, repository string,
+) bool {
+	acCtrlr := &AccessController{Config: accessControl}
+
+	can := func(permission string) bool {
+		// without access control every authenticated user can do anything its api key allows
+		if accessControl == nil {
+			return !userAc.IsAnonymous() && userAc.IsInScope(permission, repository)
+		}
+
+		return acCtrlr.can(userAc, permission, repository)
+	}
+
+	switch action {
+	case pullAction:
+		return can(constants.ReadPermission)
+	case pushAction:
+		// pushing new tags needs create, overwriting existing ones needs update
+		return can(constants.CreatePermission) || can(constants.UpdatePermission)
+	case deleteAction:
+		return can(constants.DeletePermission)
+	default:
+		return false
+	}
+}
+
+// getRequestedAccess returns the access needed by a request on a repository, nil for the requests which
+// don't target a single repository (eg. /v2/, the catalog or the extensions).
+func getRequestedAccess(request *http.Request) *ResourceAction {
+	name := mux.Vars(request)["name"]
+	if name == "" {
+		return nil
+	}
+
+	action := pushAction
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+		action = pullAction
+	case http.MethodDelete:
+		action = deleteAction
+	}
+
+	return &ResourceAction{
+		Type:   repositoryResourceType,
+		Name:   name,
+		Action: action,
+	}
+}
+
+func getSigningMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", zerr.ErrUnsupportedSigningKey, key)
+}
+
+func loadPrivateKeyFromFile(path string) (crypto.Signer, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w, path %s", zerr.ErrCouldNotLoadPrivateKey, err, path)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no valid PEM data found", zerr.ErrCouldNotLoadPrivateKey)
+	}
+
+	pemBytes := block.Bytes
+
+	if key, err := x509.ParsePKCS8PrivateKey(pemBytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(pemBytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(pemBytes); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: no valid private key found", zerr.ErrCouldNotLoadPrivateKey)
+}
@@ -0,0 +1,413 @@
+package api_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+	authutils "zotregistry.dev/zot/pkg/test/auth"
+	test "zotregistry.dev/zot/pkg/test/common"
+)
+
+func writeTokenSigningKey(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	keyPath := path.Join(t.TempDir(), "token.key")
+
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	if err != nil {
+		panic(err)
+	}
+
+	return keyPath
+}
+
+func TestTokenService(t *testing.T) {
+	Convey("Test token service signing keys", t, func() {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+		So(err, ShouldBeNil)
+
+		for alg, key := range map[string]crypto.Signer{"RS256": rsaKey, "ES384": ecdsaKey, "EdDSA": ed25519Key} {
+			tokenService, err := api.NewTokenService(&config.TokenServiceConfig{
+				Key:     writeTokenSigningKey(t, key),
+				Service: "zot",
+			})
+			So(err, ShouldBeNil)
+
+			access := []api.ResourceAccess{{Type: "repository", Name: "repo", Actions: []string{"pull"}}}
+
+			token, err := tokenService.IssueToken("alice", access, nil)
+			So(err, ShouldBeNil)
+			So(token.Token, ShouldEqual, token.AccessToken)
+			So(token.ExpiresIn, ShouldEqual, 300)
+
+			claims := &api.ClaimsWithAccess{}
+
+			parsed, err := jwt.ParseWithClaims(token.Token, claims, func(token *jwt.Token) (interface{}, error) {
+				return key.Public(), nil
+			})
+			So(err, ShouldBeNil)
+			So(parsed.Method.Alg(), ShouldEqual, alg)
+			So(claims.Subject, ShouldEqual, "alice")
+			So(claims.Issuer, ShouldEqual, "zot")
+			So(claims.Audience, ShouldResemble, jwt.ClaimStrings{"zot"})
+			So(claims.Access, ShouldResemble, access)
+		}
+
+		// PKCS1 and SEC1 encoded keys are supported too
+		keyPath := path.Join(t.TempDir(), "rsa.key")
+		err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+			Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+		}), 0o600)
+		So(err, ShouldBeNil)
+
+		_, err = api.NewTokenService(&config.TokenServiceConfig{Key: keyPath, Service: "zot"})
+		So(err, ShouldBeNil)
+
+		ecDER, err := x509.MarshalECPrivateKey(ecdsaKey)
+		So(err, ShouldBeNil)
+
+		keyPath = path.Join(t.TempDir(), "ec.key")
+		err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}), 0o600)
+		So(err, ShouldBeNil)
+
+		_, err = api.NewTokenService(&config.TokenServiceConfig{Key: keyPath, Service: "zot"})
+		So(err, ShouldBeNil)
+
+		Convey("Invalid keys", func() {
+			_, err := api.NewTokenService(&config.TokenServiceConfig{Key: "/does/not/exist", Service: "zot"})
+			So(err, ShouldWrap, zerr.ErrCouldNotLoadPrivateKey)
+
+			keyPath := path.Join(t.TempDir(), "token.key")
+
+			err = os.WriteFile(keyPath, []byte("not a key"), 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = api.NewTokenService(&config.TokenServiceConfig{Key: keyPath, Service: "zot"})
+			So(err, ShouldWrap, zerr.ErrCouldNotLoadPrivateKey)
+
+			err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("bad")}), 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = api.NewTokenService(&config.TokenServiceConfig{Key: keyPath, Service: "zot"})
+			So(err, ShouldWrap, zerr.ErrCouldNotLoadPrivateKey)
+
+			// curves other than the ones used by ES256, ES384 and ES512 can't sign tokens
+			p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+			So(err, ShouldBeNil)
+
+			p224DER, err := x509.MarshalECPrivateKey(p224Key)
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: p224DER}), 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = api.NewTokenService(&config.TokenServiceConfig{Key: keyPath, Service: "zot"})
+			So(err, ShouldWrap, zerr.ErrUnsupportedSigningKey)
+		})
+	})
+
+	Convey("Test granted token access", t, func() {
+		conf := config.New()
+
+		alice := reqCtx.NewUserAccessControl()
+		alice.SetUsername("alice")
+
+		anonymous := reqCtx.NewUserAccessControl()
+
+		Convey("Without access control authenticated users are granted everything", func() {
+			access, err := api.GetGrantedAccess(conf.HTTP.AccessControl, alice, []string{"repository:repo:pull,push", ""})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "repo", Actions: []string{"pull", "push"}},
+			})
+
+			access, err = api.GetGrantedAccess(conf.HTTP.AccessControl, anonymous, []string{"repository:repo:pull"})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "repo", Actions: []string{}},
+			})
+		})
+
+		Convey("Scoped api keys are only granted their scope", func() {
+			alice.SetScope([]string{"ci/**"}, []string{"create"})
+
+			access, err := api.GetGrantedAccess(conf.HTTP.AccessControl, alice, []string{"repository:ci/app:pull,push", "repository:repo:push"})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "ci/app", Actions: []string{"push"}},
+				{Type: "repository", Name: "repo", Actions: []string{}},
+			})
+		})
+
+		Convey("With access control the policies of the user are granted", func() {
+			conf.HTTP.AccessControl = &config.AccessControlConfig{
+				Repositories: config.Repositories{
+					"alice/**": config.PolicyGroup{
+						Policies: []config.Policy{
+							{Users: []string{"alice"}, Actions: []string{"read", "update"}},
+						},
+						AnonymousPolicy: []string{"read"},
+					},
+					"**": config.PolicyGroup{
+						DefaultPolicy: []string{"read"},
+					},
+				},
+				AdminPolicy: config.Policy{
+					Users:   []string{"admin"},
+					Actions: []string{"read", "create", "update", "delete"},
+				},
+			}
+
+			access, err := api.GetGrantedAccess(conf.HTTP.AccessControl, alice, []string{
+				"repository:alice/repo:pull,push,delete", "repository:other:*", "registry:catalog:*",
+				"repository(plugin):alice/repo:pull",
+			})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "alice/repo", Actions: []string{"pull", "push"}},
+				{Type: "repository", Name: "other", Actions: []string{"pull"}},
+			})
+
+			access, err = api.GetGrantedAccess(conf.HTTP.AccessControl, anonymous, []string{"repository:alice/repo:pull,push", "repository:other:pull"})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "alice/repo", Actions: []string{"pull"}},
+				{Type: "repository", Name: "other", Actions: []string{}},
+			})
+
+			admin := reqCtx.NewUserAccessControl()
+			admin.SetUsername("admin")
+
+			access, err = api.GetGrantedAccess(conf.HTTP.AccessControl, admin, []string{"repository:other:*"})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "other", Actions: []string{"pull", "push", "delete"}},
+			})
+		})
+
+		Convey("Robot accounts are only granted their scope, whatever the policies", func() {
+			conf.HTTP.AccessControl = &config.AccessControlConfig{
+				Repositories: config.Repositories{
+					"**": config.PolicyGroup{
+						DefaultPolicy: []string{"read", "create"},
+					},
+				},
+			}
+
+			robot := reqCtx.NewUserAccessControl()
+			robot.SetUsername("robot$ci")
+			robot.SetIsRobot(true)
+			robot.SetScope([]string{"ci/**"}, []string{"read", "delete"})
+
+			access, err := api.GetGrantedAccess(conf.HTTP.AccessControl, robot, []string{"repository:ci/app:*", "repository:other:pull"})
+			So(err, ShouldBeNil)
+			So(access, ShouldResemble, []api.ResourceAccess{
+				{Type: "repository", Name: "ci/app", Actions: []string{"pull", "delete"}},
+				{Type: "repository", Name: "other", Actions: []string{}},
+			})
+		})
+
+		Convey("Invalid scopes", func() {
+			_, err := api.GetGrantedAccess(conf.HTTP.AccessControl, alice, []string{"repository:pull"})
+			So(err, ShouldWrap, zerr.ErrInvalidTokenScope)
+
+			_, err = api.GetGrantedAccess(conf.HTTP.AccessControl, alice, []string{"repository"})
+			So(err, ShouldWrap, zerr.ErrInvalidTokenScope)
+		})
+	})
+}
+
+func TestTokenServiceAuth(t *testing.T) {
+	Convey("Make a new controller issuing its own bearer tokens", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("alice", "alicepass"))
+		defer os.Remove(htpasswdPath)
+
+		signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{Path: htpasswdPath},
+			TokenService: &config.TokenServiceConfig{
+				Key:        writeTokenSigningKey(t, signingKey),
+				Service:    "zot-registry",
+				Expiration: time.Minute,
+			},
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"alice/**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{"alice"}, Actions: []string{"read", "create"}},
+					},
+				},
+				"public/**": config.PolicyGroup{
+					AnonymousPolicy: []string{"read"},
+				},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		defer cm.StopServer()
+
+		getToken := func(client *resty.Request, scopes ...string) string {
+			for _, scope := range scopes {
+				client.QueryParam.Add("scope", scope)
+			}
+
+			resp, err := client.SetQueryParam("service", "zot-registry").Get(baseURL + "/zot/auth/token")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var token api.TokenResponse
+
+			err = json.Unmarshal(resp.Body(), &token)
+			So(err, ShouldBeNil)
+			So(token.ExpiresIn, ShouldEqual, 60)
+
+			return token.Token
+		}
+
+		// clients are sent to the token endpoint of zot
+		resp, err := resty.R().Get(baseURL + "/v2/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		challenge := authutils.ParseBearerAuthHeader(resp.Header().Get("WWW-Authenticate"))
+		So(challenge.Realm, ShouldEqual, baseURL+"/zot/auth/token")
+		So(challenge.Service, ShouldEqual, "zot-registry")
+
+		resp, err = resty.R().Get(baseURL + "/v2/alice/repo/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		challenge = authutils.ParseBearerAuthHeader(resp.Header().Get("WWW-Authenticate"))
+		So(challenge.Scope, ShouldEqual, "repository:alice/repo:pull")
+
+		token := getToken(resty.R().SetBasicAuth("alice", "alicepass"),
+			"repository:alice/repo:pull,push", "repository:other:pull")
+
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/v2/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/v2/alice/repo/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().SetAuthToken(token).Post(baseURL + "/v2/alice/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		// the token doesn't grant access to other repositories
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/v2/other/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		challenge = authutils.ParseBearerAuthHeader(resp.Header().Get("WWW-Authenticate"))
+		So(challenge.Scope, ShouldEqual, "repository:other:pull")
+
+		resp, err = resty.R().SetAuthToken(token).Delete(baseURL + "/v2/alice/repo/manifests/latest")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		// tokens can't be used to get other tokens
+		resp, err = resty.R().SetAuthToken(token).Get(baseURL + "/zot/auth/token")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = resty.R().SetAuthToken("invalid").Get(baseURL + "/v2/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+		So(resp.Header().Get("WWW-Authenticate"), ShouldStartWith, "Bearer realm=")
+
+		// tokens signed with other keys are rejected
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		otherService, err := api.NewTokenService(&config.TokenServiceConfig{
+			Key:     writeTokenSigningKey(t, otherKey),
+			Service: "zot-registry",
+		})
+		So(err, ShouldBeNil)
+
+		forged, err := otherService.IssueToken("alice", nil, nil)
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().SetAuthToken(forged.Token).Get(baseURL + "/v2/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		// anonymous users get tokens for what the anonymous policy allows
+		anonymousToken := getToken(resty.R(), "repository:public/repo:pull", "repository:alice/repo:pull")
+
+		resp, err = resty.R().SetAuthToken(anonymousToken).Get(baseURL + "/v2/public/repo/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().SetAuthToken(anonymousToken).Get(baseURL + "/v2/alice/repo/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		// basic auth keeps working for the clients which use it, the repo was created by the upload
+		resp, err = resty.R().SetBasicAuth("alice", "alicepass").Get(baseURL + "/v2/alice/repo/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// invalid token requests
+		resp, err = resty.R().SetBasicAuth("alice", "wrong").Get(baseURL + "/zot/auth/token")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+		So(resp.Header().Get("WWW-Authenticate"), ShouldStartWith, "Basic realm=")
+
+		resp, err = resty.R().SetBasicAuth("alice", "alicepass").
+			SetQueryParam("service", "other").Get(baseURL + "/zot/auth/token")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().SetBasicAuth("alice", "alicepass").
+			SetQueryParam("scope", "repository:pull").Get(baseURL + "/zot/auth/token")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,471 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+const (
+	jobSucceeded    = "succeeded"
+	jobFailed       = "failed"
+	jobPollInterval = time.Second
+)
+
+func NewAdminCommand() *cobra.Command {
+	adminCmd := &cobra.Command{
+		Use:   "admin [command]",
+		Short: "Run maintenance jobs and manage undelivered events on the server",
+		Long: `Run garbage collection, scrub and dedupe jobs on the server and check their status, ` +
+			`list and replay the events which could not be delivered, admins only`,
+		RunE: ShowSuggestionsIfUnknownCommand,
+	}
+
+	adminCmd.SetUsageTemplate(adminCmd.UsageTemplate() + usageFooter)
+
+	adminCmd.PersistentFlags().String(URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	adminCmd.PersistentFlags().String(ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	adminCmd.PersistentFlags().StringP(UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	adminCmd.PersistentFlags().StringP(OutputFormatFlag, "f", "text", "Specify output format [text/json/yaml]")
+	adminCmd.PersistentFlags().Bool(DebugFlag, false, "Show debug output")
+
+	adminCmd.AddCommand(NewAdminJobCommand("gc", "Run garbage collection, applying the retention policies"))
+	adminCmd.AddCommand(NewAdminJobCommand("scrub", "Check the integrity of the images"))
+	adminCmd.AddCommand(NewAdminJobCommand("dedupe", "Dedupe or restore the blobs, depending on the storage config"))
+	adminCmd.AddCommand(NewAdminJobStatusCommand())
+	adminCmd.AddCommand(NewAdminJobListCommand())
+	adminCmd.AddCommand(NewAdminDeadLettersCommand())
+	adminCmd.AddCommand(NewAdminReplayCommand())
+
+	return adminCmd
+}
+
+func NewAdminJobCommand(jobType, description string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   jobType,
+		Short: description,
+		Long: description + `. The job runs on a single repository if one is given, ` +
+			`otherwise on all the repositories`,
+		Example: fmt.Sprintf(`  # Run %[1]s on a single repository and wait for the results
+  zli admin %[1]s --repo alpine --priority high --wait`, jobType),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			repo, _ := cmd.Flags().GetString(RepoFlag)
+			priority, _ := cmd.Flags().GetString(PriorityFlag)
+			wait, _ := cmd.Flags().GetBool(WaitFlag)
+
+			return RunAdminJob(searchConfig, adminJobRequest{Type: jobType, Repository: repo, Priority: priority}, wait)
+		},
+	}
+
+	cmd.Flags().String(RepoFlag, "", "Run the job only on this repository")
+	cmd.Flags().String(PriorityFlag, "low", "Specify the priority of the job [low/medium/high]")
+	cmd.Flags().Bool(WaitFlag, false, "Wait for the job to finish and show its results")
+
+	return cmd
+}
+
+func NewAdminJobStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job [job-id]",
+		Short: "Show the status and the results of a job",
+		Long:  `Show the status and the results of a job`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			job, err := getAdminJob(searchConfig, args[0])
+			if err != nil {
+				return err
+			}
+
+			return printAdminJobs(searchConfig, adminJobList{Jobs: []adminJob{job}})
+		},
+	}
+
+	return cmd
+}
+
+func NewAdminJobListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List the queued, running and recently finished jobs",
+		Long:  `List the queued, running and recently finished jobs`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return ListAdminJobs(searchConfig)
+		},
+	}
+
+	return cmd
+}
+
+func NewAdminDeadLettersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deadletters",
+		Short: "List the events which could not be delivered to a sink",
+		Long:  `List the events which could not be delivered to a sink after all the attempts, oldest first`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return ListDeadLetters(searchConfig)
+		},
+	}
+
+	return cmd
+}
+
+func NewAdminReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [dead-letter-id]",
+		Short: "Replay dead-lettered events",
+		Long:  `Schedule a dead-lettered event, or all of them, for delivery with the attempts count reset`,
+		Example: `  # Replay a single event
+  zli admin replay 7f4c2a9e-5b1d-4c3e-9a8f-0d6e1b2c3a4f
+
+  # Replay all the dead-lettered events
+  zli admin replay --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool(AllFlag)
+
+			if all == (len(args) == 1) {
+				return fmt.Errorf("%w: either a dead letter id or --all is required", zerr.ErrInvalidArgs)
+			}
+
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			deadLetterID := ""
+			if len(args) == 1 {
+				deadLetterID = args[0]
+			}
+
+			return ReplayDeadLetters(searchConfig, deadLetterID)
+		},
+	}
+
+	cmd.Flags().Bool(AllFlag, false, "Replay all the dead-lettered events")
+
+	return cmd
+}
+
+type adminJobRequest struct {
+	Type       string `json:"type"`
+	Repository string `json:"repository,omitempty"`
+	Priority   string `json:"priority,omitempty"`
+}
+
+type scrubResult struct {
+	ImageName    string `json:"imageName"     yaml:"imageName"`
+	Tag          string `json:"tag"           yaml:"tag"`
+	Status       string `json:"status"        yaml:"status"`
+	AffectedBlob string `json:"affectedBlob"  yaml:"affectedBlob"`
+	Error        string `json:"error"         yaml:"error"`
+}
+
+type adminJob struct {
+	ID           string        `json:"id"                     yaml:"id"`
+	Type         string        `json:"type"                   yaml:"type"`
+	Repository   string        `json:"repository,omitempty"   yaml:"repository,omitempty"`
+	Priority     string        `json:"priority"               yaml:"priority"`
+	Status       string        `json:"status"                 yaml:"status"`
+	Error        string        `json:"error,omitempty"        yaml:"error,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"              yaml:"createdAt"`
+	StartedAt    *time.Time    `json:"startedAt,omitempty"    yaml:"startedAt,omitempty"`
+	FinishedAt   *time.Time    `json:"finishedAt,omitempty"   yaml:"finishedAt,omitempty"`
+	Repositories []string      `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	Digests      int           `json:"digests,omitempty"      yaml:"digests,omitempty"`
+	ScrubResults []scrubResult `json:"scrubResults,omitempty" yaml:"scrubResults,omitempty"`
+}
+
+func (job adminJob) isDone() bool {
+	return job.Status == jobSucceeded || job.Status == jobFailed
+}
+
+type adminJobList struct {
+	Jobs []adminJob `json:"jobs" yaml:"jobs"`
+}
+
+type deadLetter struct {
+	ID        string         `json:"id"                  yaml:"id"`
+	Sink      string         `json:"sink"                yaml:"sink"`
+	Event     map[string]any `json:"event"               yaml:"event"`
+	Attempts  int            `json:"attempts"            yaml:"attempts"`
+	CreatedAt time.Time      `json:"createdAt"           yaml:"createdAt"`
+	LastError string         `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+}
+
+type deadLetterList struct {
+	DeadLetters []deadLetter `json:"deadLetters" yaml:"deadLetters"`
+}
+
+type replayedDeadLetters struct {
+	Replayed int `json:"replayed" yaml:"replayed"`
+}
+
+func RunAdminJob(config SearchConfig, jobRequest adminJobRequest, wait bool) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	jobsEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AdminJobsPath)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(jobRequest)
+	if err != nil {
+		return err
+	}
+
+	job := adminJob{}
+
+	_, err = makePOSTRequest(context.Background(), jobsEndpoint, username, password, body, config.VerifyTLS,
+		config.Debug, &job, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	for wait && !job.isDone() {
+		time.Sleep(jobPollInterval)
+
+		job, err = getAdminJob(config, job.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := printAdminJobs(config, adminJobList{Jobs: []adminJob{job}}); err != nil {
+		return err
+	}
+
+	if job.Status == jobFailed {
+		return fmt.Errorf("%w: %s", zerr.ErrAdminJobFailed, job.Error)
+	}
+
+	return nil
+}
+
+func ListAdminJobs(config SearchConfig) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	jobsEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AdminJobsPath)
+	if err != nil {
+		return err
+	}
+
+	jobs := adminJobList{}
+
+	_, err = makeGETRequest(context.Background(), jobsEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &jobs, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printAdminJobs(config, jobs)
+}
+
+func ListDeadLetters(config SearchConfig) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	deadLettersEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AdminEventsPath+"/deadletters")
+	if err != nil {
+		return err
+	}
+
+	deadLetters := deadLetterList{}
+
+	_, err = makeGETRequest(context.Background(), deadLettersEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &deadLetters, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	output, err := formatAdminOutput(deadLetters, deadLetters.stringPlainText, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+// ReplayDeadLetters replays the dead letter with the given id, or all of them if the id is empty.
+func ReplayDeadLetters(config SearchConfig, deadLetterID string) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	replayPath := constants.AdminEventsPath + "/deadletters/replay"
+	if deadLetterID != "" {
+		replayPath = constants.AdminEventsPath + "/deadletters/" + deadLetterID + "/replay"
+	}
+
+	replayEndpoint, err := combineServerAndEndpointURL(config.ServURL, replayPath)
+	if err != nil {
+		return err
+	}
+
+	replayed := replayedDeadLetters{}
+
+	_, err = makePOSTRequest(context.Background(), replayEndpoint, username, password, nil, config.VerifyTLS,
+		config.Debug, &replayed, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	output, err := formatAdminOutput(replayed, func() string {
+		return fmt.Sprintf("%d event(s) scheduled for delivery\n", replayed.Replayed)
+	}, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func getAdminJob(config SearchConfig, jobID string) (adminJob, error) {
+	username, password := getUsernameAndPassword(config.User)
+
+	jobEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AdminJobsPath+"/"+jobID)
+	if err != nil {
+		return adminJob{}, err
+	}
+
+	job := adminJob{}
+
+	_, err = makeGETRequest(context.Background(), jobEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &job, config.ResultWriter)
+
+	return job, err
+}
+
+func printAdminJobs(config SearchConfig, jobs adminJobList) error {
+	output, err := jobs.stringFormat(config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func (jobs adminJobList) stringFormat(format string) (string, error) {
+	return formatAdminOutput(jobs, jobs.stringPlainText, format)
+}
+
+func formatAdminOutput(result any, plainText func() string, format string) (string, error) {
+	switch format {
+	case defaultOutputFormat, "":
+		return plainText(), nil
+	case jsonFormat:
+		body, err := json.MarshalIndent(result, "", "    ")
+
+		return string(body) + "\n", err
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(result)
+
+		return string(body), err
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (jobs adminJobList) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getCommonTableWriter(&builder)
+
+	table.Append([]string{"ID", "TYPE", "REPOSITORY", "PRIORITY", "STATUS", "ERROR"}) //nolint:errcheck
+
+	scrubResults := []scrubResult{}
+
+	for _, job := range jobs.Jobs {
+		repo := job.Repository
+		if repo == "" {
+			repo = "*"
+		}
+
+		table.Append([]string{job.ID, job.Type, repo, job.Priority, job.Status, job.Error}) //nolint:errcheck
+
+		scrubResults = append(scrubResults, job.ScrubResults...)
+	}
+
+	table.Render() //nolint:errcheck
+
+	if len(scrubResults) == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString("\n")
+
+	table = getCommonTableWriter(&builder)
+
+	table.Append([]string{"REPOSITORY", "TAG", "STATUS", "AFFECTED BLOB", "ERROR"}) //nolint:errcheck
+
+	for _, result := range scrubResults {
+		table.Append([]string{ //nolint:errcheck
+			result.ImageName, result.Tag, result.Status, result.AffectedBlob, result.Error,
+		})
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
+
+func (deadLetters deadLetterList) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getCommonTableWriter(&builder)
+
+	table.Append([]string{"ID", "SINK", "EVENT TYPE", "ATTEMPTS", "CREATED", "LAST ERROR"}) //nolint:errcheck
+
+	for _, deadLetter := range deadLetters.DeadLetters {
+		eventType, _ := deadLetter.Event["type"].(string)
+
+		table.Append([]string{ //nolint:errcheck
+			deadLetter.ID, deadLetter.Sink, eventType, strconv.Itoa(deadLetter.Attempts),
+			deadLetter.CreatedAt.Format(time.RFC3339), deadLetter.LastError,
+		})
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
@@ -0,0 +1,206 @@
+//go:build search
+// +build search
+
+package client //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func TestAdminCommand(t *testing.T) {
+	Convey("AdminCommand", t, func() {
+		var jobRequest adminJobRequest
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == constants.AdminJobsPath:
+				jobRequest = adminJobRequest{}
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &jobRequest)
+
+				status := "queued"
+				if jobRequest.Type == "dedupe" {
+					status = "failed"
+				}
+
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = fmt.Fprintf(w, `{"id": "job-1", "type": "%s", "repository": "%s", "priority": "%s",
+					"status": "%s", "error": "%s"}`, jobRequest.Type, jobRequest.Repository, jobRequest.Priority,
+					status, map[bool]string{true: "dedupe failed"}[status == "failed"])
+			case r.Method == http.MethodGet && r.URL.Path == constants.AdminJobsPath+"/job-1":
+				_, _ = w.Write([]byte(`{"id": "job-1", "type": "scrub", "repository": "alpine", "priority": "high",
+					"status": "succeeded", "scrubResults": [
+						{"imageName": "alpine", "tag": "latest", "status": "affected",
+							"affectedBlob": "2bd2cf4d", "error": "blob not found"}]}`))
+			case r.Method == http.MethodGet && r.URL.Path == constants.AdminJobsPath:
+				_, _ = w.Write([]byte(`{"jobs": [
+					{"id": "job-1", "type": "gc", "priority": "low", "status": "running"},
+					{"id": "job-2", "type": "dedupe", "priority": "low", "status": "queued"}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"admin-test","url":"%s","showspinner":false}]}`,
+			server.URL))
+		defer os.Remove(configPath)
+
+		runCommand := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			space := regexp.MustCompile(`\s+`)
+
+			return strings.TrimSpace(space.ReplaceAllString(buff.String(), " ")), err
+		}
+
+		output, err := runCommand("admin", "gc", "--config", "admin-test")
+		So(err, ShouldBeNil)
+		So(jobRequest, ShouldResemble, adminJobRequest{Type: "gc", Priority: "low"})
+		So(output, ShouldContainSubstring, "ID TYPE REPOSITORY PRIORITY STATUS ERROR")
+		So(output, ShouldContainSubstring, "job-1 gc * low queued")
+
+		output, err = runCommand("admin", "scrub", "--config", "admin-test", "--repo", "alpine",
+			"--priority", "high", "--wait")
+		So(err, ShouldBeNil)
+		So(jobRequest, ShouldResemble, adminJobRequest{Type: "scrub", Repository: "alpine", Priority: "high"})
+		So(output, ShouldContainSubstring, "job-1 scrub alpine high succeeded")
+		So(output, ShouldContainSubstring, "REPOSITORY TAG STATUS AFFECTED BLOB ERROR")
+		So(output, ShouldContainSubstring, "alpine latest affected 2bd2cf4d blob not found")
+
+		output, err = runCommand("admin", "dedupe", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+		So(output, ShouldContainSubstring, "job-1 dedupe * low failed dedupe failed")
+
+		output, err = runCommand("admin", "job", "job-1", "--config", "admin-test", "--format", "json")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `"affectedBlob": "2bd2cf4d"`)
+
+		output, err = runCommand("admin", "jobs", "--config", "admin-test", "--format", "yaml")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "id: job-2")
+
+		output, err = runCommand("admin", "jobs", "--config", "admin-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "job-1 gc * low running")
+		So(output, ShouldContainSubstring, "job-2 dedupe * low queued")
+
+		_, err = runCommand("admin", "jobs", "--config", "admin-test", "--format", "xml")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "job", "job-2", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "job", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "gc", "--url", "invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestAdminDeadLettersCommand(t *testing.T) {
+	Convey("AdminDeadLettersCommand", t, func() {
+		replayedPaths := []string{}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			deadLettersPath := constants.AdminEventsPath + "/deadletters"
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == deadLettersPath:
+				_, _ = w.Write([]byte(`{"deadLetters": [
+					{"id": "dl-1", "sink": "http:http://localhost:8090", "attempts": 10,
+						"createdAt": "2024-01-02T03:04:05Z", "lastError": "503: service unavailable",
+						"event": {"id": "event-1", "type": "zotregistry.image.updated", "data": {"name": "alpine"}}}]}`))
+			case r.Method == http.MethodPost && r.URL.Path == deadLettersPath+"/replay":
+				replayedPaths = append(replayedPaths, r.URL.Path)
+
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"replayed": 3}`))
+			case r.Method == http.MethodPost && r.URL.Path == deadLettersPath+"/dl-1/replay":
+				replayedPaths = append(replayedPaths, r.URL.Path)
+
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"replayed": 1}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"admin-test","url":"%s","showspinner":false}]}`,
+			server.URL))
+		defer os.Remove(configPath)
+
+		runCommand := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			space := regexp.MustCompile(`\s+`)
+
+			return strings.TrimSpace(space.ReplaceAllString(buff.String(), " ")), err
+		}
+
+		output, err := runCommand("admin", "deadletters", "--config", "admin-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "ID SINK EVENT TYPE ATTEMPTS CREATED LAST ERROR")
+		So(output, ShouldContainSubstring, "dl-1 http:http://localhost:8090 zotregistry.image.updated 10 "+
+			"2024-01-02T03:04:05Z 503: service unavailable")
+
+		output, err = runCommand("admin", "deadletters", "--config", "admin-test", "--format", "json")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `"name": "alpine"`)
+
+		output, err = runCommand("admin", "replay", "dl-1", "--config", "admin-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "1 event(s) scheduled for delivery")
+
+		output, err = runCommand("admin", "replay", "--all", "--config", "admin-test", "--format", "yaml")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "replayed: 3")
+
+		So(replayedPaths, ShouldResemble, []string{
+			constants.AdminEventsPath + "/deadletters/dl-1/replay",
+			constants.AdminEventsPath + "/deadletters/replay",
+		})
+
+		_, err = runCommand("admin", "replay", "dl-2", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "replay", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "replay", "dl-1", "--all", "--config", "admin-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "deadletters", "--config", "admin-test", "--format", "xml")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("admin", "deadletters", "--url", "invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -9,7 +9,12 @@ func enableCli(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(NewConfigCommand())
 	rootCmd.AddCommand(NewImageCommand(NewSearchService()))
 	rootCmd.AddCommand(NewCVECommand(NewSearchService()))
+	rootCmd.AddCommand(NewSbomCommand(NewSearchService()))
 	rootCmd.AddCommand(NewRepoCommand(NewSearchService()))
 	rootCmd.AddCommand(NewSearchCommand(NewSearchService()))
 	rootCmd.AddCommand(NewServerStatusCommand())
+	rootCmd.AddCommand(NewRetentionCommand())
+	rootCmd.AddCommand(NewAdminCommand())
+	rootCmd.AddCommand(NewRobotCommand())
+	rootCmd.AddCommand(NewPoliciesCommand())
 }
@@ -54,6 +54,47 @@ func makeHEADRequest(ctx context.Context, url, username, password string, verify
 	return doHTTPRequest(req, verifyTLS, debug, nil, io.Discard)
 }
 
+func makePOSTRequest(ctx context.Context, url, username, password string, body []byte,
+	verifyTLS bool, debug bool, resultsPtr interface{}, configWriter io.Writer,
+) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(username, password)
+	req.Header.Add("Content-Type", "application/json")
+
+	return doHTTPRequest(req, verifyTLS, debug, resultsPtr, configWriter)
+}
+
+func makePUTRequest(ctx context.Context, url, username, password string, body []byte,
+	verifyTLS bool, debug bool, resultsPtr interface{}, configWriter io.Writer,
+) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(username, password)
+	req.Header.Add("Content-Type", "application/json")
+
+	return doHTTPRequest(req, verifyTLS, debug, resultsPtr, configWriter)
+}
+
+func makeDELETERequest(ctx context.Context, url, username, password string,
+	verifyTLS bool, debug bool, configWriter io.Writer,
+) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(username, password)
+
+	return doHTTPRequest(req, verifyTLS, debug, nil, configWriter)
+}
+
 func makeGraphQLRequest(ctx context.Context, url, query, username,
 	password string, verifyTLS bool, debug bool, resultsPtr interface{}, configWriter io.Writer,
 ) error {
@@ -129,7 +170,9 @@ func doHTTPRequest(req *http.Request, verifyTLS bool, debug bool,
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	// jobs submitted to the server are accepted and run asynchronously
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted &&
+		resp.StatusCode != http.StatusCreated {
 		var err error
 
 		switch resp.StatusCode {
@@ -345,10 +345,10 @@ func TestCVEDiffList(t *testing.T) {
 
 			return getCveResults(repoMeta.Tags[ref].Digest), nil
 		},
-		GetCachedResultFn: func(digestStr string) map[string]cvemodel.CVE {
+		GetCachedResultFn: func(repo, digestStr string) map[string]cvemodel.CVE {
 			return getCveResults(digestStr)
 		},
-		IsResultCachedFn: func(digestStr string) bool {
+		IsResultCachedFn: func(repo, digestStr string) bool {
 			return true
 		},
 	}
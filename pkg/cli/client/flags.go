@@ -21,8 +21,18 @@ const (
 	VersionFlag      = "version"
 	DebugFlag        = "debug"
 	SearchedCVEID    = "cve-id"
+	SearchedPackage  = "package"
 	SortByFlag       = "sort-by"
 	PlatformFlag     = "platform"
+	RepoFlag         = "repo"
+	PriorityFlag     = "priority"
+	WaitFlag         = "wait"
+	AllFlag          = "all"
+	ActionFlag       = "action"
+	DescriptionFlag  = "description"
+	ExpirationFlag   = "expiration"
+	CommentFlag      = "comment"
+	BaseVersionFlag  = "base-version"
 )
 
 const (
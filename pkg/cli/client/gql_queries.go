@@ -25,6 +25,12 @@ func CVEResultForImage() GQLType {
 	}
 }
 
+func PackageResultForImage() GQLType {
+	return GQLType{
+		Name: "PackageResultForImage",
+	}
+}
+
 func CVEDiffResult() GQLType {
 	return GQLType{
 		Name: "CVEDiffResult",
@@ -105,6 +111,22 @@ func ImageListForCVEQuery() GQLQuery {
 	}
 }
 
+func PackageListForImageQuery() GQLQuery {
+	return GQLQuery{
+		Name:       "PackageListForImage",
+		Args:       []string{"image", "searchedPackage", "requestedPage"},
+		ReturnType: PackageResultForImage(),
+	}
+}
+
+func ImageListForPackageQuery() GQLQuery {
+	return GQLQuery{
+		Name:       "ImageListForPackage",
+		Args:       []string{"name", "version", "filter", "requestedPage"},
+		ReturnType: PaginatedImagesResult(),
+	}
+}
+
 func ImageListWithCVEFixedQuery() GQLQuery {
 	return GQLQuery{
 		Name:       "ImageListWithCVEFixed",
@@ -1126,6 +1126,14 @@ type mockService struct {
 	getCVEDiffListGQLFn func(ctx context.Context, config SearchConfig, username, password string,
 		minuend, subtrahend ImageIdentifier,
 	) (*cveDiffListResp, error)
+
+	getPackagesForImageGQLFn func(ctx context.Context, config SearchConfig, username, password,
+		imageName, searchedPackage string,
+	) (*packageListResult, error)
+
+	getImagesForPackageGQLFn func(ctx context.Context, config SearchConfig, username, password,
+		name, version string,
+	) (*common.ImagesForPackage, error)
 }
 
 func (service mockService) getCVEDiffListGQL(ctx context.Context, config SearchConfig, username, password string,
@@ -1424,6 +1432,49 @@ func (service mockService) getCveByImageGQL(ctx context.Context, config SearchCo
 	return cveRes, nil
 }
 
+func (service mockService) getPackagesForImageGQL(ctx context.Context, config SearchConfig, username, password,
+	imageName, searchedPackage string,
+) (*packageListResult, error) {
+	if service.getPackagesForImageGQLFn != nil {
+		return service.getPackagesForImageGQLFn(ctx, config, username, password, imageName, searchedPackage)
+	}
+
+	pkgRes := &packageListResult{}
+	pkgRes.Data = packageListData{
+		PackageListForImage: packageListForImage{
+			Tag: imageName,
+			PackageList: []sbomPackage{
+				{
+					Name:    "packagename",
+					Version: "1.0.0",
+					PURL:    "pkg:generic/packagename@1.0.0",
+				},
+			},
+			Summary: common.SbomSummary{
+				Formats:      []string{"spdx"},
+				PackageCount: 1,
+			},
+		},
+	}
+
+	return pkgRes, nil
+}
+
+func (service mockService) getImagesForPackageGQL(ctx context.Context, config SearchConfig, username, password,
+	name, version string,
+) (*common.ImagesForPackage, error) {
+	if service.getImagesForPackageGQLFn != nil {
+		return service.getImagesForPackageGQLFn(ctx, config, username, password, name, version)
+	}
+
+	images := &common.ImagesForPackage{}
+
+	mockedImage := service.getMockedImageByName("image-name")
+	images.Results = []common.ImageSummary{common.ImageSummary(mockedImage)}
+
+	return images, nil
+}
+
 //nolint:goconst
 func (service mockService) getMockedImageByName(imageName string) imageStruct {
 	image := imageStruct{}
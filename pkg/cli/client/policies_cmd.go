@@ -0,0 +1,306 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func NewPoliciesCommand() *cobra.Command {
+	policiesCmd := &cobra.Command{
+		Use:   "policies [command]",
+		Short: "Manage the access control policies of the server",
+		Long: `Show, update and roll back the access control policies stored in the server's MetaDB, ` +
+			`every update is kept as a new version, admins only`,
+		RunE: ShowSuggestionsIfUnknownCommand,
+	}
+
+	policiesCmd.SetUsageTemplate(policiesCmd.UsageTemplate() + usageFooter)
+
+	policiesCmd.PersistentFlags().String(URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	policiesCmd.PersistentFlags().String(ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	policiesCmd.PersistentFlags().StringP(UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	policiesCmd.PersistentFlags().StringP(OutputFormatFlag, "f", "text", "Specify output format [text/json/yaml]")
+	policiesCmd.PersistentFlags().Bool(DebugFlag, false, "Show debug output")
+
+	policiesCmd.AddCommand(NewPoliciesGetCommand())
+	policiesCmd.AddCommand(NewPoliciesSetCommand())
+	policiesCmd.AddCommand(NewPoliciesHistoryCommand())
+	policiesCmd.AddCommand(NewPoliciesRollbackCommand())
+
+	return policiesCmd
+}
+
+func NewPoliciesGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the current access control policies",
+		Long:  `Show the current version of the access control policies`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return GetAccessControlPolicies(searchConfig)
+		},
+	}
+
+	return cmd
+}
+
+func NewPoliciesSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [file]",
+		Short: "Update the access control policies",
+		Long: `Store the repositories, adminPolicy and groups policies of a JSON file as a new version ` +
+			`and apply them, the file is written like the accessControl section of the server config`,
+		Example: `  # Update the policies, failing if somebody else updated them since version 3
+  zli policies set policies.json --comment "give the ci team push access" --base-version 3`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			policiesBlob, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			policies := map[string]any{}
+
+			if err := json.Unmarshal(policiesBlob, &policies); err != nil {
+				return fmt.Errorf("%w: %s is not a valid JSON file: %w", zerr.ErrInvalidArgs, args[0], err)
+			}
+
+			comment, _ := cmd.Flags().GetString(CommentFlag)
+			baseVersion, _ := cmd.Flags().GetInt(BaseVersionFlag)
+
+			return SetAccessControlPolicies(searchConfig, policiesRequest{
+				Policies:    policies,
+				Comment:     comment,
+				BaseVersion: baseVersion,
+			})
+		},
+	}
+
+	cmd.Flags().String(CommentFlag, "", "Describe the change")
+	cmd.Flags().Int(BaseVersionFlag, 0,
+		"Only update the policies if this is still the current version")
+
+	return cmd
+}
+
+func NewPoliciesHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the versions of the access control policies",
+		Long:  `List the versions of the access control policies, who created them and why`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return GetAccessControlPoliciesHistory(searchConfig)
+		},
+	}
+
+	return cmd
+}
+
+func NewPoliciesRollbackCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [version]",
+		Short: "Roll back the access control policies",
+		Long:  `Apply a previous version of the access control policies, it's stored as a new version`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			version, err := strconv.Atoi(args[0])
+			if err != nil || version <= 0 {
+				return fmt.Errorf("%w: the version must be a positive number", zerr.ErrInvalidArgs)
+			}
+
+			return RollbackAccessControlPolicies(searchConfig, version)
+		},
+	}
+
+	return cmd
+}
+
+type policiesRequest struct {
+	Policies    map[string]any `json:"policies"`
+	Comment     string         `json:"comment,omitempty"`
+	BaseVersion int            `json:"baseVersion,omitempty"`
+}
+
+type policiesVersion struct {
+	Version   int            `json:"version"   yaml:"version"`
+	Policies  map[string]any `json:"policies"  yaml:"policies"`
+	CreatedBy string         `json:"createdBy" yaml:"createdBy"`
+	CreatedAt time.Time      `json:"createdAt" yaml:"createdAt"`
+	Comment   string         `json:"comment"   yaml:"comment"`
+}
+
+type policiesHistory struct {
+	Versions []policiesVersion `json:"versions" yaml:"versions"`
+}
+
+func GetAccessControlPolicies(config SearchConfig) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	policiesEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AccessControlPoliciesPath)
+	if err != nil {
+		return err
+	}
+
+	policies := policiesVersion{}
+
+	_, err = makeGETRequest(context.Background(), policiesEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &policies, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printPoliciesVersion(config, policies)
+}
+
+func SetAccessControlPolicies(config SearchConfig, request policiesRequest) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	policiesEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.AccessControlPoliciesPath)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	policies := policiesVersion{}
+
+	_, err = makePUTRequest(context.Background(), policiesEndpoint, username, password, body, config.VerifyTLS,
+		config.Debug, &policies, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printPoliciesVersion(config, policies)
+}
+
+func GetAccessControlPoliciesHistory(config SearchConfig) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	historyEndpoint, err := combineServerAndEndpointURL(config.ServURL,
+		constants.AccessControlPoliciesPath+"/history")
+	if err != nil {
+		return err
+	}
+
+	history := policiesHistory{}
+
+	_, err = makeGETRequest(context.Background(), historyEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &history, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	output, err := formatAdminOutput(history, history.stringPlainText, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func RollbackAccessControlPolicies(config SearchConfig, version int) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	rollbackEndpoint, err := combineServerAndEndpointURL(config.ServURL,
+		fmt.Sprintf("%s/%d/rollback", constants.AccessControlPoliciesPath, version))
+	if err != nil {
+		return err
+	}
+
+	policies := policiesVersion{}
+
+	_, err = makePOSTRequest(context.Background(), rollbackEndpoint, username, password, nil, config.VerifyTLS,
+		config.Debug, &policies, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printPoliciesVersion(config, policies)
+}
+
+func printPoliciesVersion(config SearchConfig, policies policiesVersion) error {
+	output, err := formatAdminOutput(policies, func() string {
+		var builder strings.Builder
+
+		fmt.Fprintf(&builder, "version: %d\ncreated by: %s\ncreated at: %s\n", policies.Version,
+			policies.CreatedBy, policies.CreatedAt.Format(time.RFC3339))
+
+		if policies.Comment != "" {
+			fmt.Fprintf(&builder, "comment: %s\n", policies.Comment)
+		}
+
+		// the policies are shown the way they are written in the files passed to zli policies set
+		policiesBlob, _ := json.MarshalIndent(policies.Policies, "", "    ")
+		fmt.Fprintf(&builder, "policies:\n%s\n", policiesBlob)
+
+		return builder.String()
+	}, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func (history policiesHistory) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getCommonTableWriter(&builder)
+
+	table.Append([]string{"VERSION", "CREATED BY", "CREATED AT", "COMMENT"}) //nolint:errcheck
+
+	for _, version := range history.Versions {
+		table.Append([]string{ //nolint:errcheck
+			strconv.Itoa(version.Version), version.CreatedBy, version.CreatedAt.Format(time.RFC3339),
+			version.Comment,
+		})
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
@@ -0,0 +1,146 @@
+//go:build search
+// +build search
+
+package client //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func TestPoliciesCommand(t *testing.T) {
+	Convey("PoliciesCommand", t, func() {
+		var (
+			request       policiesRequest
+			rollbackPaths []string
+			versionBody   = `{"version": %d, "createdBy": "admin", "createdAt": "2030-01-02T03:04:05Z",
+				"comment": "%s", "policies": {"adminPolicy": {"Users": ["admin"]}}}`
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == constants.AccessControlPoliciesPath:
+				_, _ = fmt.Fprintf(w, versionBody, 2, "give the ci team push access")
+			case r.Method == http.MethodPut && r.URL.Path == constants.AccessControlPoliciesPath:
+				request = policiesRequest{}
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &request)
+
+				if request.BaseVersion == 1 {
+					w.WriteHeader(http.StatusConflict)
+
+					return
+				}
+
+				_, _ = fmt.Fprintf(w, versionBody, 3, request.Comment)
+			case r.Method == http.MethodGet && r.URL.Path == constants.AccessControlPoliciesPath+"/history":
+				_, _ = w.Write([]byte(`{"versions": [
+					{"version": 1, "createdAt": "2030-01-01T03:04:05Z", "comment": "seeded from the config file"},
+					{"version": 2, "createdBy": "admin", "createdAt": "2030-01-02T03:04:05Z",
+						"comment": "give the ci team push access"}]}`))
+			case r.Method == http.MethodPost && r.URL.Path == constants.AccessControlPoliciesPath+"/1/rollback":
+				rollbackPaths = append(rollbackPaths, r.URL.Path)
+
+				_, _ = fmt.Fprintf(w, versionBody, 3, "rollback to version 1")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"policies-test","url":"%s","showspinner":false}]}`,
+			server.URL))
+		defer os.Remove(configPath)
+
+		runCommand := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			space := regexp.MustCompile(`\s+`)
+
+			return strings.TrimSpace(space.ReplaceAllString(buff.String(), " ")), err
+		}
+
+		output, err := runCommand("policies", "get", "--config", "policies-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "version: 2 created by: admin created at: 2030-01-02T03:04:05Z")
+		So(output, ShouldContainSubstring, "comment: give the ci team push access")
+		So(output, ShouldContainSubstring, `policies: { "adminPolicy": { "Users": [ "admin" ] } }`)
+
+		output, err = runCommand("policies", "get", "--config", "policies-test", "--format", "json")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `"version": 2`)
+
+		_, err = runCommand("policies", "get", "--config", "policies-test", "--format", "xml")
+		So(err, ShouldNotBeNil)
+
+		policiesPath := path.Join(t.TempDir(), "policies.json")
+		err = os.WriteFile(policiesPath, []byte(`{"repositories": {"ci/**": {"policies": [
+			{"groups": ["ci"], "actions": ["read", "create"]}]}}, "adminPolicy": {"users": ["admin"]}}`), 0o600)
+		So(err, ShouldBeNil)
+
+		output, err = runCommand("policies", "set", policiesPath, "--config", "policies-test",
+			"--comment", "ci pushes", "--base-version", "2")
+		So(err, ShouldBeNil)
+		So(request.Comment, ShouldEqual, "ci pushes")
+		So(request.BaseVersion, ShouldEqual, 2)
+		So(request.Policies, ShouldContainKey, "repositories")
+		So(request.Policies, ShouldContainKey, "adminPolicy")
+		So(output, ShouldContainSubstring, "version: 3")
+
+		// somebody else updated the policies
+		_, err = runCommand("policies", "set", policiesPath, "--config", "policies-test", "--base-version", "1")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("policies", "set", path.Join(t.TempDir(), "missing.json"), "--config", "policies-test")
+		So(err, ShouldNotBeNil)
+
+		err = os.WriteFile(policiesPath, []byte(`not json`), 0o600)
+		So(err, ShouldBeNil)
+
+		_, err = runCommand("policies", "set", policiesPath, "--config", "policies-test")
+		So(err, ShouldNotBeNil)
+
+		output, err = runCommand("policies", "history", "--config", "policies-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "VERSION CREATED BY CREATED AT COMMENT")
+		So(output, ShouldContainSubstring, "1 2030-01-01T03:04:05Z seeded from the config file")
+		So(output, ShouldContainSubstring, "2 admin 2030-01-02T03:04:05Z give the ci team push access")
+
+		output, err = runCommand("policies", "history", "--config", "policies-test", "--format", "yaml")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "comment: seeded from the config file")
+
+		output, err = runCommand("policies", "rollback", "1", "--config", "policies-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "comment: rollback to version 1")
+		So(rollbackPaths, ShouldResemble, []string{constants.AccessControlPoliciesPath + "/1/rollback"})
+
+		_, err = runCommand("policies", "rollback", "latest", "--config", "policies-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("policies", "rollback", "9", "--config", "policies-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("policies", "get", "--url", "invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
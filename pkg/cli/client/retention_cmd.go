@@ -0,0 +1,141 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/constants"
+	rTypes "zotregistry.dev/zot/pkg/retention/types"
+)
+
+func NewRetentionCommand() *cobra.Command {
+	retentionCmd := &cobra.Command{
+		Use:   "retention [command]",
+		Short: "Inspect the retention policies of the server",
+		Long:  `Inspect the retention policies of the server`,
+		RunE:  ShowSuggestionsIfUnknownCommand,
+	}
+
+	retentionCmd.SetUsageTemplate(retentionCmd.UsageTemplate() + usageFooter)
+
+	retentionCmd.PersistentFlags().String(URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	retentionCmd.PersistentFlags().String(ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	retentionCmd.PersistentFlags().StringP(UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	retentionCmd.PersistentFlags().StringP(OutputFormatFlag, "f", "text", "Specify output format [text/json/yaml]")
+	retentionCmd.PersistentFlags().Bool(DebugFlag, false, "Show debug output")
+
+	retentionCmd.AddCommand(NewRetentionPreviewCommand())
+
+	return retentionCmd
+}
+
+func NewRetentionPreviewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preview [repo-glob]",
+		Short: "List the tags which would be retained or deleted by the retention policies",
+		Long: `Evaluate the retention policies on the server without removing anything and list the tags ` +
+			`which would be retained or deleted, along with the rule which retained each tag`,
+		Example: `  # Preview the retention policies for all the repositories under infra/
+  zli retention preview "infra/**"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return PreviewRetention(searchConfig, args[0])
+		},
+	}
+
+	return cmd
+}
+
+type retentionPreviewResponse struct {
+	Repositories []rTypes.RetentionReport `json:"repositories" yaml:"repositories"`
+}
+
+func PreviewRetention(config SearchConfig, repoPattern string) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	retentionEndpoint, err := combineServerAndEndpointURL(config.ServURL, fmt.Sprintf("%s%s?resource=retention&%s",
+		constants.RoutePrefix, constants.ExtMgmt, url.Values{"repository": {repoPattern}}.Encode()))
+	if err != nil {
+		return err
+	}
+
+	preview := retentionPreviewResponse{}
+
+	_, err = makeGETRequest(context.Background(), retentionEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &preview, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	output, err := preview.stringFormat(config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func (preview retentionPreviewResponse) stringFormat(format string) (string, error) {
+	switch format {
+	case defaultOutputFormat, "":
+		return preview.stringPlainText(), nil
+	case jsonFormat:
+		body, err := json.MarshalIndent(preview, "", "    ")
+
+		return string(body) + "\n", err
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(preview)
+
+		return string(body), err
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (preview retentionPreviewResponse) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getCommonTableWriter(&builder)
+
+	table.Append([]string{"REPOSITORY", "TAG", "DIGEST", "DECISION", "REASON"}) //nolint:errcheck
+
+	for _, report := range preview.Repositories {
+		for _, tag := range report.Tags {
+			digest := ellipsize(godigest.Digest(tag.Digest).Encoded(), digestWidth, "")
+
+			table.Append([]string{report.Repository, tag.Tag, digest, tag.Decision, tag.Reason}) //nolint:errcheck
+		}
+
+		// untagged manifests and referrers, listed without a tag
+		for _, manifest := range report.Manifests {
+			digest := ellipsize(godigest.Digest(manifest.Digest).Encoded(), digestWidth, "")
+
+			table.Append([]string{report.Repository, "", digest, manifest.Decision, manifest.Reason}) //nolint:errcheck
+		}
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
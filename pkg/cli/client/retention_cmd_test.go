@@ -0,0 +1,91 @@
+//go:build search
+// +build search
+
+package client //nolint:testpackage
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func TestRetentionPreviewCommand(t *testing.T) {
+	Convey("RetentionPreviewCommand", t, func() {
+		var requestedPattern string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != constants.FullMgmt || r.URL.Query().Get("resource") != "retention" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			requestedPattern = r.URL.Query().Get("repository")
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"repositories": [{"repository": "infra/app", "deleteUntagged": true,
+				"tags": [
+					{"tag": "v1.0", "digest": "sha256:2bd2cf4d3ae8e01b3e8f0ee2c5f8c6fb6d8f2a3f8e0f0c1b0a8a9c9d9e9f9a9b",
+						"decision": "keep", "reason": "retained by mostRecentlyPushedCount:1 policy"},
+					{"tag": "v0.9", "digest": "sha256:9bd2cf4d3ae8e01b3e8f0ee2c5f8c6fb6d8f2a3f8e0f0c1b0a8a9c9d9e9f9a9b",
+						"decision": "delete", "reason": "didn't meet any tag retention rule"}
+				],
+				"manifests": [
+					{"digest": "sha256:3bd2cf4d3ae8e01b3e8f0ee2c5f8c6fb6d8f2a3f8e0f0c1b0a8a9c9d9e9f9a9b",
+						"decision": "delete", "reason": "deleteUntagged"}
+				]}]}`))
+		}))
+		defer server.Close()
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"retention-test","url":"%s","showspinner":false}]}`,
+			server.URL))
+		defer os.Remove(configPath)
+
+		runCommand := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			space := regexp.MustCompile(`\s+`)
+
+			return strings.TrimSpace(space.ReplaceAllString(buff.String(), " ")), err
+		}
+
+		output, err := runCommand("retention", "preview", "infra/**", "--config", "retention-test")
+		So(err, ShouldBeNil)
+		So(requestedPattern, ShouldEqual, "infra/**")
+		So(output, ShouldContainSubstring, "REPOSITORY TAG DIGEST DECISION REASON")
+		So(output, ShouldContainSubstring, "infra/app v1.0 2bd2cf4d keep retained by mostRecentlyPushedCount:1 policy")
+		So(output, ShouldContainSubstring, "infra/app v0.9 9bd2cf4d delete didn't meet any tag retention rule")
+		So(output, ShouldContainSubstring, "infra/app 3bd2cf4d delete deleteUntagged")
+
+		output, err = runCommand("retention", "preview", "infra/**", "--config", "retention-test", "--format", "json")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `"deleteUntagged": true`)
+		So(output, ShouldContainSubstring, `"decision": "delete"`)
+
+		output, err = runCommand("retention", "preview", "infra/**", "--config", "retention-test", "--format", "yaml")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "repository: infra/app")
+
+		_, err = runCommand("retention", "preview", "infra/**", "--config", "retention-test", "--format", "xml")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("retention", "preview", "--config", "retention-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("retention", "preview", "**", "--url", "invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
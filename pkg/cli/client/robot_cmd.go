@@ -0,0 +1,354 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func NewRobotCommand() *cobra.Command {
+	robotCmd := &cobra.Command{
+		Use:   "robot [command]",
+		Short: "Manage the robot accounts of the server",
+		Long: `Create, list, delete robot accounts and rotate their secrets, robot accounts authenticate ` +
+			`as robot$<name> and can only act on the repositories of their scope, admins only`,
+		RunE: ShowSuggestionsIfUnknownCommand,
+	}
+
+	robotCmd.SetUsageTemplate(robotCmd.UsageTemplate() + usageFooter)
+
+	robotCmd.PersistentFlags().String(URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	robotCmd.PersistentFlags().String(ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	robotCmd.PersistentFlags().StringP(UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	robotCmd.PersistentFlags().StringP(OutputFormatFlag, "f", "text", "Specify output format [text/json/yaml]")
+	robotCmd.PersistentFlags().Bool(DebugFlag, false, "Show debug output")
+
+	robotCmd.AddCommand(NewRobotCreateCommand())
+	robotCmd.AddCommand(NewRobotListCommand())
+	robotCmd.AddCommand(NewRobotDeleteCommand())
+	robotCmd.AddCommand(NewRobotRotateCommand())
+
+	return robotCmd
+}
+
+func NewRobotCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a robot account",
+		Long: `Create a robot account allowed the given actions on the given repositories, ` +
+			`its secret is only shown once`,
+		Example: `  # Create a robot account pushing to the ci/ repositories, with a secret expiring in 30 days
+  zli robot create ci-builder --repo "ci/**" --action read --action create --action update --expiration 720h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			repos, _ := cmd.Flags().GetStringSlice(RepoFlag)
+			actions, _ := cmd.Flags().GetStringSlice(ActionFlag)
+			description, _ := cmd.Flags().GetString(DescriptionFlag)
+
+			if len(repos) == 0 || len(actions) == 0 {
+				return fmt.Errorf("%w: at least one --repo and one --action are required", zerr.ErrInvalidArgs)
+			}
+
+			expirationDate, err := getSecretExpirationDate(cmd)
+			if err != nil {
+				return err
+			}
+
+			return CreateRobotAccount(searchConfig, robotAccountRequest{
+				Name:           args[0],
+				Description:    description,
+				Repositories:   repos,
+				Actions:        actions,
+				ExpirationDate: expirationDate,
+			})
+		},
+	}
+
+	cmd.Flags().StringSlice(RepoFlag, nil, "Glob pattern of the repositories the robot account can act on")
+	cmd.Flags().StringSlice(ActionFlag, nil, "Action allowed to the robot account [read/create/update/delete]")
+	cmd.Flags().String(DescriptionFlag, "", "Describe what the robot account is used for")
+	cmd.Flags().Duration(ExpirationFlag, 0, "Specify after how long the secret expires, it never expires if not set")
+
+	return cmd
+}
+
+func NewRobotListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the robot accounts",
+		Long:  `List the robot accounts, their scope and the expiration date of their secret`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return ListRobotAccounts(searchConfig)
+		},
+	}
+
+	return cmd
+}
+
+func NewRobotDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a robot account",
+		Long:  `Delete a robot account, its secret and the tokens issued to it can't be used anymore`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			return DeleteRobotAccount(searchConfig, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewRobotRotateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate [name]",
+		Short: "Rotate the secret of a robot account",
+		Long:  `Replace the secret of a robot account, the previous secret can't be used anymore`,
+		Example: `  # Rotate the secret of a robot account, the new secret expires in 30 days
+  zli robot rotate ci-builder --expiration 720h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, NewSearchService())
+			if err != nil {
+				return err
+			}
+
+			expirationDate, err := getSecretExpirationDate(cmd)
+			if err != nil {
+				return err
+			}
+
+			return RotateRobotSecret(searchConfig, args[0], expirationDate)
+		},
+	}
+
+	cmd.Flags().Duration(ExpirationFlag, 0,
+		"Specify after how long the new secret expires, it never expires if not set")
+
+	return cmd
+}
+
+// getSecretExpirationDate returns the date the secret expires at, formatted as expected by the server,
+// or an empty string if the secret doesn't expire.
+func getSecretExpirationDate(cmd *cobra.Command) (string, error) {
+	expiration, _ := cmd.Flags().GetDuration(ExpirationFlag)
+
+	if expiration < 0 {
+		return "", fmt.Errorf("%w: --%s must be positive", zerr.ErrInvalidArgs, ExpirationFlag)
+	}
+
+	if expiration == 0 {
+		return "", nil
+	}
+
+	return time.Now().Add(expiration).Format(constants.APIKeyTimeFormat), nil
+}
+
+type robotAccountRequest struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	Repositories   []string `json:"repositories"`
+	Actions        []string `json:"actions"`
+	ExpirationDate string   `json:"expirationDate,omitempty"`
+}
+
+type robotSecretRequest struct {
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+type robotAccount struct {
+	Name                 string    `json:"name"                 yaml:"name"`
+	Username             string    `json:"username"             yaml:"username"`
+	Description          string    `json:"description"          yaml:"description"`
+	Repositories         []string  `json:"repositories"         yaml:"repositories"`
+	Actions              []string  `json:"actions"              yaml:"actions"`
+	CreatedBy            string    `json:"createdBy"            yaml:"createdBy"`
+	CreatedAt            time.Time `json:"createdAt"            yaml:"createdAt"`
+	SecretCreatedAt      time.Time `json:"secretCreatedAt"      yaml:"secretCreatedAt"`
+	SecretExpirationDate time.Time `json:"secretExpirationDate" yaml:"secretExpirationDate"`
+	IsExpired            bool      `json:"isExpired"            yaml:"isExpired"`
+	Secret               string    `json:"secret,omitempty"     yaml:"secret,omitempty"`
+}
+
+type robotAccountList struct {
+	Robots []robotAccount `json:"robots" yaml:"robots"`
+}
+
+func CreateRobotAccount(config SearchConfig, robotRequest robotAccountRequest) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	robotsEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.RobotAccountsPath)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(robotRequest)
+	if err != nil {
+		return err
+	}
+
+	robot := robotAccount{}
+
+	_, err = makePOSTRequest(context.Background(), robotsEndpoint, username, password, body, config.VerifyTLS,
+		config.Debug, &robot, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printRobotSecret(config, robot)
+}
+
+func ListRobotAccounts(config SearchConfig) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	robotsEndpoint, err := combineServerAndEndpointURL(config.ServURL, constants.RobotAccountsPath)
+	if err != nil {
+		return err
+	}
+
+	robots := robotAccountList{}
+
+	_, err = makeGETRequest(context.Background(), robotsEndpoint, username, password, config.VerifyTLS,
+		config.Debug, &robots, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	output, err := formatAdminOutput(robots, robots.stringPlainText, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func DeleteRobotAccount(config SearchConfig, name string) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	robotEndpoint, err := combineServerAndEndpointURL(config.ServURL,
+		constants.RobotAccountsPath+"/"+url.PathEscape(name))
+	if err != nil {
+		return err
+	}
+
+	_, err = makeDELETERequest(context.Background(), robotEndpoint, username, password, config.VerifyTLS,
+		config.Debug, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.ResultWriter, "robot account %s deleted\n", name)
+
+	return nil
+}
+
+func RotateRobotSecret(config SearchConfig, name, expirationDate string) error {
+	username, password := getUsernameAndPassword(config.User)
+
+	rotateEndpoint, err := combineServerAndEndpointURL(config.ServURL,
+		constants.RobotAccountsPath+"/"+url.PathEscape(name)+"/rotate")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(robotSecretRequest{ExpirationDate: expirationDate})
+	if err != nil {
+		return err
+	}
+
+	robot := robotAccount{}
+
+	_, err = makePOSTRequest(context.Background(), rotateEndpoint, username, password, body, config.VerifyTLS,
+		config.Debug, &robot, config.ResultWriter)
+	if err != nil {
+		return err
+	}
+
+	return printRobotSecret(config, robot)
+}
+
+// printRobotSecret shows the credentials of a robot account, the server doesn't return the secret again.
+func printRobotSecret(config SearchConfig, robot robotAccount) error {
+	output, err := formatAdminOutput(robot, func() string {
+		var builder strings.Builder
+
+		fmt.Fprintf(&builder, "username: %s\nsecret: %s\n", robot.Username, robot.Secret)
+
+		if !robot.SecretExpirationDate.IsZero() {
+			fmt.Fprintf(&builder, "expires: %s\n", robot.SecretExpirationDate.Format(time.RFC3339))
+		}
+
+		builder.WriteString("the secret can't be retrieved later, store it safely\n")
+
+		return builder.String()
+	}, config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, output)
+
+	return nil
+}
+
+func (robots robotAccountList) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getCommonTableWriter(&builder)
+
+	table.Append([]string{ //nolint:errcheck
+		"USERNAME", "REPOSITORIES", "ACTIONS", "CREATED BY", "EXPIRES", "DESCRIPTION",
+	})
+
+	for _, robot := range robots.Robots {
+		expires := "never"
+
+		switch {
+		case robot.IsExpired:
+			expires = "expired"
+		case !robot.SecretExpirationDate.IsZero():
+			expires = robot.SecretExpirationDate.Format(time.RFC3339)
+		}
+
+		table.Append([]string{ //nolint:errcheck
+			robot.Username, strings.Join(robot.Repositories, ","), strings.Join(robot.Actions, ","),
+			robot.CreatedBy, expires, robot.Description,
+		})
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
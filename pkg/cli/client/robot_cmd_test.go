@@ -0,0 +1,158 @@
+//go:build search
+// +build search
+
+package client //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.dev/zot/pkg/api/constants"
+)
+
+func TestRobotCommand(t *testing.T) {
+	Convey("RobotCommand", t, func() {
+		var (
+			robotRequest  robotAccountRequest
+			secretRequest robotSecretRequest
+			deletedPaths  []string
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == constants.RobotAccountsPath:
+				robotRequest = robotAccountRequest{}
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &robotRequest)
+
+				if robotRequest.Name == "taken" {
+					w.WriteHeader(http.StatusConflict)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusCreated)
+				_, _ = fmt.Fprintf(w, `{"name": "%[1]s", "username": "robot$%[1]s", "secret": "zrs_secret1"}`,
+					robotRequest.Name)
+			case r.Method == http.MethodGet && r.URL.Path == constants.RobotAccountsPath:
+				_, _ = w.Write([]byte(`{"robots": [
+					{"name": "ci", "username": "robot$ci", "description": "ci builds", "repositories": ["ci/**"],
+						"actions": ["read", "create"], "createdBy": "admin", "secretExpirationDate": "2030-01-02T03:04:05Z"},
+					{"name": "old", "username": "robot$old", "repositories": ["**"], "actions": ["read"],
+						"createdBy": "admin", "secretExpirationDate": "2020-01-02T03:04:05Z", "isExpired": true},
+					{"name": "mirror", "username": "robot$mirror", "repositories": ["**"], "actions": ["read"],
+						"createdBy": "admin", "secretExpirationDate": "0001-01-01T00:00:00Z"}]}`))
+			case r.Method == http.MethodDelete && r.URL.Path == constants.RobotAccountsPath+"/ci":
+				deletedPaths = append(deletedPaths, r.URL.Path)
+			case r.Method == http.MethodPost && r.URL.Path == constants.RobotAccountsPath+"/ci/rotate":
+				secretRequest = robotSecretRequest{}
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &secretRequest)
+
+				_, _ = w.Write([]byte(`{"name": "ci", "username": "robot$ci", "secret": "zrs_secret2",
+					"secretExpirationDate": "2030-01-02T03:04:05Z"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"robot-test","url":"%s","showspinner":false}]}`,
+			server.URL))
+		defer os.Remove(configPath)
+
+		runCommand := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			space := regexp.MustCompile(`\s+`)
+
+			return strings.TrimSpace(space.ReplaceAllString(buff.String(), " ")), err
+		}
+
+		output, err := runCommand("robot", "create", "ci", "--config", "robot-test", "--repo", "ci/**",
+			"--action", "read", "--action", "create", "--description", "ci builds")
+		So(err, ShouldBeNil)
+		So(robotRequest, ShouldResemble, robotAccountRequest{
+			Name: "ci", Description: "ci builds", Repositories: []string{"ci/**"}, Actions: []string{"read", "create"},
+		})
+		So(output, ShouldContainSubstring, "username: robot$ci secret: zrs_secret1")
+
+		_, err = runCommand("robot", "create", "ci", "--config", "robot-test", "--repo", "ci/**",
+			"--action", "read", "--expiration", "24h")
+		So(err, ShouldBeNil)
+
+		expirationDate, err := time.Parse(constants.APIKeyTimeFormat, robotRequest.ExpirationDate)
+		So(err, ShouldBeNil)
+		So(expirationDate, ShouldHappenWithin, time.Minute, time.Now().Add(24*time.Hour))
+
+		output, err = runCommand("robot", "create", "ci", "--config", "robot-test", "--repo", "ci/**",
+			"--action", "read", "--format", "json")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `"secret": "zrs_secret1"`)
+
+		// no scope
+		_, err = runCommand("robot", "create", "ci", "--config", "robot-test", "--repo", "ci/**")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("robot", "create", "ci", "--config", "robot-test", "--repo", "ci/**",
+			"--action", "read", "--expiration", "-1h")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("robot", "create", "taken", "--config", "robot-test", "--repo", "ci/**",
+			"--action", "read")
+		So(err, ShouldNotBeNil)
+
+		output, err = runCommand("robot", "list", "--config", "robot-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "USERNAME REPOSITORIES ACTIONS CREATED BY EXPIRES DESCRIPTION")
+		So(output, ShouldContainSubstring, "robot$ci ci/** read,create admin 2030-01-02T03:04:05Z ci builds")
+		So(output, ShouldContainSubstring, "robot$old ** read admin expired")
+		So(output, ShouldContainSubstring, "robot$mirror ** read admin never")
+
+		output, err = runCommand("robot", "list", "--config", "robot-test", "--format", "yaml")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "username: robot$old")
+
+		_, err = runCommand("robot", "list", "--config", "robot-test", "--format", "xml")
+		So(err, ShouldNotBeNil)
+
+		output, err = runCommand("robot", "rotate", "ci", "--config", "robot-test", "--expiration", "720h")
+		So(err, ShouldBeNil)
+		So(secretRequest.ExpirationDate, ShouldNotBeEmpty)
+		So(output, ShouldContainSubstring, "username: robot$ci secret: zrs_secret2 expires: 2030-01-02T03:04:05Z")
+
+		_, err = runCommand("robot", "rotate", "missing", "--config", "robot-test")
+		So(err, ShouldNotBeNil)
+
+		output, err = runCommand("robot", "delete", "ci", "--config", "robot-test")
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "robot account ci deleted")
+		So(deletedPaths, ShouldResemble, []string{constants.RobotAccountsPath + "/ci"})
+
+		_, err = runCommand("robot", "delete", "missing", "--config", "robot-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("robot", "delete", "--config", "robot-test")
+		So(err, ShouldNotBeNil)
+
+		_, err = runCommand("robot", "list", "--url", "invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,34 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewSbomCommand(searchService SearchService) *cobra.Command {
+	sbomCmd := &cobra.Command{
+		Use:   "sbom [command]",
+		Short: "Lookup packages in the SBOMs of images hosted on the zot registry",
+		Long:  `List packages from the SPDX and CycloneDX SBOMs of images hosted on the zot registry`,
+		RunE:  ShowSuggestionsIfUnknownCommand,
+	}
+
+	sbomCmd.SetUsageTemplate(sbomCmd.UsageTemplate() + usageFooter)
+
+	sbomCmd.PersistentFlags().String(URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	sbomCmd.PersistentFlags().String(ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	sbomCmd.PersistentFlags().StringP(UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	sbomCmd.PersistentFlags().StringP(OutputFormatFlag, "f", "", "Specify output format [text/json/yaml]")
+	sbomCmd.PersistentFlags().Bool(VerboseFlag, false, "Show verbose output")
+	sbomCmd.PersistentFlags().Bool(DebugFlag, false, "Show debug output")
+
+	sbomCmd.AddCommand(NewPackagesForImageCommand(searchService))
+	sbomCmd.AddCommand(NewImagesByPackageCommand(searchService))
+
+	return sbomCmd
+}
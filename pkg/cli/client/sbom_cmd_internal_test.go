@@ -0,0 +1,244 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	"zotregistry.dev/zot/pkg/meta"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+func TestSbomCmd(t *testing.T) {
+	port := test.GetFreePort()
+	baseURL := test.GetBaseURL(port)
+	conf := config.New()
+	conf.HTTP.Port = port
+	conf.Storage.RootDirectory = t.TempDir()
+
+	defaultVal := true
+	conf.Extensions = &extconf.ExtensionConfig{
+		Search: &extconf.SearchConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+		},
+	}
+
+	ctlr := api.NewController(conf)
+	cm := test.NewControllerManager(ctlr)
+
+	cm.StartAndWait(port)
+	defer cm.StopServer()
+
+	space := regexp.MustCompile(`\s+`)
+
+	Convey("Test sbom help", t, func() {
+		cmd := NewSbomCommand(new(mockService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"--help"})
+		err := cmd.Execute()
+
+		So(buff.String(), ShouldContainSubstring, "Usage")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Test sbom no url", t, func() {
+		configPath := makeConfigFile(`{"configs":[{"_name":"sbomtest","showspinner":false}]}`)
+		defer os.Remove(configPath)
+
+		cmd := NewSbomCommand(new(mockService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--config", "sbomtest"})
+		err := cmd.Execute()
+		So(err, ShouldNotBeNil)
+		So(errors.Is(err, zerr.ErrNoURLProvided), ShouldBeTrue)
+	})
+
+	Convey("Test packages for image - in text format", t, func() {
+		cmd := NewSbomCommand(new(mockService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL})
+		err := cmd.Execute()
+		So(err, ShouldBeNil)
+
+		outputLines := strings.Split(buff.String(), "\n")
+
+		expected := []string{
+			"FORMATS spdx, PACKAGES 1",
+			"",
+			"PACKAGE VERSION PURL",
+			"packagename 1.0.0 pkg:generic/packagename@1.0.0",
+		}
+
+		for expectedLineIndex, expectedLine := range expected {
+			str := space.ReplaceAllString(outputLines[expectedLineIndex], " ")
+			So(strings.TrimSpace(str), ShouldEqual, expectedLine)
+		}
+	})
+
+	Convey("Test packages for image - in json and yaml format", t, func() {
+		cmd := NewSbomCommand(new(mockService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL, "-f", "json"})
+		err := cmd.Execute()
+		So(err, ShouldBeNil)
+		So(buff.String(), ShouldContainSubstring, `"PackageList":[{"Name":"packagename"`)
+
+		cmd = NewSbomCommand(new(mockService))
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL, "-f", "yaml"})
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+		So(buff.String(), ShouldContainSubstring, "name: packagename")
+
+		cmd = NewSbomCommand(new(mockService))
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL, "-f", "random"})
+		err = cmd.Execute()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test packages for image - errors and empty results", t, func() {
+		searchedPackage := ""
+
+		cmd := NewSbomCommand(mockService{
+			getPackagesForImageGQLFn: func(ctx context.Context, config SearchConfig, username, password,
+				imageName, searched string,
+			) (*packageListResult, error) {
+				searchedPackage = searched
+
+				return &packageListResult{}, nil
+			},
+		})
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL, "--package", "ssl"})
+		err := cmd.Execute()
+		So(err, ShouldBeNil)
+		So(searchedPackage, ShouldEqual, "ssl")
+		So(buff.String(), ShouldContainSubstring, "No packages found for image")
+
+		cmd = NewSbomCommand(mockService{
+			getPackagesForImageGQLFn: func(ctx context.Context, config SearchConfig, username, password,
+				imageName, searched string,
+			) (*packageListResult, error) {
+				return nil, zerr.ErrInjected
+			},
+		})
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test images by package", t, func() {
+		packageName, packageVersion := "", ""
+
+		cmd := NewSbomCommand(mockService{
+			getImagesForPackageGQLFn: func(ctx context.Context, config SearchConfig, username, password,
+				name, version string,
+			) (*zcommon.ImagesForPackage, error) {
+				packageName, packageVersion = name, version
+
+				return &zcommon.ImagesForPackage{}, nil
+			},
+		})
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"images", "openssl", "--version", "3.0.2", "--url", baseURL})
+		err := cmd.Execute()
+		So(err, ShouldBeNil)
+		So(packageName, ShouldEqual, "openssl")
+		So(packageVersion, ShouldEqual, "3.0.2")
+
+		cmd = NewSbomCommand(new(mockService))
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"images", "openssl", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+		So(space.ReplaceAllString(buff.String(), " "), ShouldContainSubstring, "image-name tag")
+
+		cmd = NewSbomCommand(new(mockService))
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"images", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test sbom commands against the server", t, func() {
+		image := CreateRandomImage()
+		err := UploadImage(image, baseURL, "repo", "tag")
+		So(err, ShouldBeNil)
+
+		sbom := CreateImageWith().LayerBlobs([][]byte{[]byte(`{
+			"spdxVersion": "SPDX-2.3",
+			"packages": [{"name": "openssl", "versionInfo": "3.0.2"}]
+		}`)}).EmptyConfig().Subject(image.DescriptorRef()).ArtifactType(meta.MediaTypeSPDX).Build()
+		err = UploadImage(sbom, baseURL, "repo", sbom.DigestStr())
+		So(err, ShouldBeNil)
+
+		cmd := NewSbomCommand(NewSearchService())
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:tag", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+
+		str := space.ReplaceAllString(buff.String(), " ")
+		So(str, ShouldContainSubstring, "FORMATS spdx, PACKAGES 1")
+		So(str, ShouldContainSubstring, "openssl 3.0.2 -")
+
+		cmd = NewSbomCommand(NewSearchService())
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"images", "openssl", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+
+		str = space.ReplaceAllString(buff.String(), " ")
+		So(str, ShouldContainSubstring, "repo tag")
+		So(str, ShouldContainSubstring, image.DigestStr()[7:15])
+
+		cmd = NewSbomCommand(NewSearchService())
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"list", "repo:missing", "--url", baseURL})
+		err = cmd.Execute()
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,74 @@
+//go:build search
+// +build search
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewPackagesForImageCommand(searchService SearchService) *cobra.Command {
+	var searchedPackage string
+
+	packagesForImageCmd := &cobra.Command{
+		Use:   "list [repo:tag]|[repo@digest]",
+		Short: "List packages by REPO:TAG or REPO@DIGEST",
+		Long:  `List the packages found in the SBOMs of REPO:TAG or REPO@DIGEST`,
+		Args:  OneImageWithRefArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			err = CheckExtEndPointQuery(searchConfig, PackageListForImageQuery())
+			if err != nil {
+				return fmt.Errorf("%w: '%s'", err, PackageListForImageQuery().Name)
+			}
+
+			image := args[0]
+
+			return SearchPackagesForImageGQL(searchConfig, image, searchedPackage)
+		},
+	}
+
+	packagesForImageCmd.Flags().StringVar(&searchedPackage, SearchedPackage, "",
+		"Search for packages by name or purl")
+
+	return packagesForImageCmd
+}
+
+func NewImagesByPackageCommand(searchService SearchService) *cobra.Command {
+	var (
+		version           string
+		imageListSortFlag = ImageListSortFlag(SortByAlphabeticAsc)
+	)
+
+	imagesByPackageCmd := &cobra.Command{
+		Use:   "images [package]",
+		Short: "List images containing a package",
+		Long:  `List images whose SBOMs contain a package, identified by its name or purl`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			err = CheckExtEndPointQuery(searchConfig, ImageListForPackageQuery())
+			if err != nil {
+				return fmt.Errorf("%w: '%s'", err, ImageListForPackageQuery().Name)
+			}
+
+			return SearchImagesByPackageGQL(searchConfig, args[0], version)
+		},
+	}
+
+	imagesByPackageCmd.Flags().StringVar(&version, VersionFlag, "", "Only list images containing this package version")
+	imagesByPackageCmd.Flags().Var(&imageListSortFlag, SortByFlag,
+		fmt.Sprintf("Options for sorting the output: [%s]", ImageListSortOptionsStr()))
+
+	return imagesByPackageCmd
+}
@@ -354,6 +354,66 @@ func SearchImagesByCVEIDGQL(config SearchConfig, repo, cveid string) error {
 	return printImageResult(config, imageListData)
 }
 
+func SearchPackagesForImageGQL(config SearchConfig, image, searchedPackage string) error {
+	username, password := getUsernameAndPassword(config.User)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	packageList, err := config.SearchService.getPackagesForImageGQL(ctx, config, username, password,
+		image, searchedPackage)
+	if err != nil {
+		return err
+	}
+
+	if len(packageList.Data.PackageListForImage.PackageList) == 0 {
+		fmt.Fprint(config.ResultWriter, "No packages found for image\n")
+
+		return nil
+	}
+
+	var builder strings.Builder
+
+	if config.OutputFormat == defaultOutputFormat || config.OutputFormat == "" {
+		sbomSummary := packageList.Data.PackageListForImage.Summary
+
+		fmt.Fprintf(config.ResultWriter, "FORMATS %s, PACKAGES %d\n\n",
+			strings.Join(sbomSummary.Formats, " "), sbomSummary.PackageCount)
+
+		printPackageTableHeader(&builder)
+		fmt.Fprint(config.ResultWriter, builder.String())
+	}
+
+	out, err := packageList.string(config.OutputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.ResultWriter, out)
+
+	return nil
+}
+
+func SearchImagesByPackageGQL(config SearchConfig, name, version string) error {
+	username, password := getUsernameAndPassword(config.User)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	imageList, err := config.SearchService.getImagesForPackageGQL(ctx, config, username, password, name, version)
+	if err != nil {
+		return err
+	}
+
+	imageListData := []imageStruct{}
+
+	for _, image := range imageList.Results {
+		imageListData = append(imageListData, imageStruct(image))
+	}
+
+	return printImageResult(config, imageListData)
+}
+
 func SearchFixedTagsGQL(config SearchConfig, repo, cveid string) error {
 	username, password := getUsernameAndPassword(config.User)
 	ctx, cancel := context.WithCancel(context.Background())
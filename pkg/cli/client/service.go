@@ -43,6 +43,10 @@ type SearchService interface { //nolint:interfacebloat
 		cveID string) (*common.ImagesForCve, error)
 	getFixedTagsForCVEGQL(ctx context.Context, config SearchConfig, username, password, imageName,
 		cveID string) (*common.ImageListWithCVEFixedResponse, error)
+	getPackagesForImageGQL(ctx context.Context, config SearchConfig, username, password,
+		imageName, searchedPackage string) (*packageListResult, error)
+	getImagesForPackageGQL(ctx context.Context, config SearchConfig, username, password,
+		name, version string) (*common.ImagesForPackage, error)
 	getDerivedImageListGQL(ctx context.Context, config SearchConfig, username, password string,
 		derivedImage string) (*common.DerivedImageListResponse, error)
 	getBaseImageListGQL(ctx context.Context, config SearchConfig, username, password string,
@@ -452,6 +456,63 @@ func (service searchService) getFixedTagsForCVEGQL(ctx context.Context, config S
 	return result, nil
 }
 
+func (service searchService) getPackagesForImageGQL(ctx context.Context, config SearchConfig, username, password,
+	imageName, searchedPackage string,
+) (*packageListResult, error) {
+	query := fmt.Sprintf(`
+	{
+		PackageListForImage (image:"%s", searchedPackage:"%s") {
+			Tag
+			PackageList {Name Version PURL}
+			Summary {Formats PackageCount}
+		}
+	}`, imageName, searchedPackage)
+	result := &packageListResult{}
+
+	err := service.makeGraphQLQuery(ctx, config, username, password, query, result)
+	if errResult := checkResultGraphQLQuery(ctx, err, result.Errors); errResult != nil {
+		return nil, errResult
+	}
+
+	return result, nil
+}
+
+func (service searchService) getImagesForPackageGQL(ctx context.Context, config SearchConfig,
+	username, password, name, version string,
+) (*common.ImagesForPackage, error) {
+	query := fmt.Sprintf(`
+		{
+			ImageListForPackage(name: "%s", version: "%s", requestedPage: {sortBy: %s}) {
+				Results {
+					RepoName Tag
+					Digest
+					MediaType
+					Manifests {
+						Digest
+						ConfigDigest
+						Size
+						Platform {Os Arch}
+						IsSigned
+						Layers {Size Digest}
+						LastUpdated
+					}
+					LastUpdated
+					Size
+					IsSigned
+				}
+			}
+		}`,
+		name, version, Flag2SortCriteria(config.SortBy))
+	result := &common.ImagesForPackage{}
+
+	err := service.makeGraphQLQuery(ctx, config, username, password, query, result)
+	if errResult := checkResultGraphQLQuery(ctx, err, result.Errors); errResult != nil {
+		return nil, errResult
+	}
+
+	return result, nil
+}
+
 func (service searchService) getReferrers(ctx context.Context, config SearchConfig, username, password string,
 	repo, digest string,
 ) (referrersResult, error) {
@@ -936,6 +997,89 @@ func (cve cveResult) stringYAML() (string, error) {
 	return "---\n" + string(body), nil
 }
 
+type packageListResult struct {
+	Errors []common.ErrorGQL `json:"errors"`
+	Data   packageListData   `json:"data"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type sbomPackage struct {
+	Name    string `json:"Name"`
+	Version string `json:"Version"`
+	PURL    string `json:"PURL"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type packageListForImage struct {
+	Tag         string             `json:"Tag"`
+	PackageList []sbomPackage      `json:"PackageList"`
+	Summary     common.SbomSummary `json:"Summary"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type packageListData struct {
+	PackageListForImage packageListForImage `json:"packageListForImage"`
+}
+
+func (pkgs packageListResult) string(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", defaultOutputFormat:
+		return pkgs.stringPlainText(), nil
+	case jsonFormat:
+		return pkgs.stringJSON()
+	case ymlFormat, yamlFormat:
+		return pkgs.stringYAML()
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (pkgs packageListResult) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getPackageTableWriter(&builder)
+
+	for _, pkg := range pkgs.Data.PackageListForImage.PackageList {
+		purl := "-"
+		if pkg.PURL != "" {
+			purl = ellipsize(pkg.PURL, sbomPkgPURLWidth, ellipsis)
+		}
+
+		row := make([]string, sbomColTotalCount)
+		row[colSbomPkgNameIndex] = ellipsize(pkg.Name, sbomPkgNameWidth, ellipsis)
+		row[colSbomPkgVersionIndex] = ellipsize(pkg.Version, sbomPkgVersionWidth, ellipsis)
+		row[colSbomPkgPURLIndex] = purl
+
+		table.Append(row) //nolint:errcheck
+	}
+
+	table.Render() //nolint:errcheck
+
+	return builder.String()
+}
+
+func (pkgs packageListResult) stringJSON() (string, error) {
+	// Output is in json lines format - do not indent, append new line after json
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+	body, err := json.Marshal(pkgs.Data.PackageListForImage)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body) + "\n", nil
+}
+
+func (pkgs packageListResult) stringYAML() (string, error) {
+	// Output will be a multidoc yaml - use triple-dash to indicate a new document
+	body, err := yaml.Marshal(&pkgs.Data.PackageListForImage)
+	if err != nil {
+		return "", err
+	}
+
+	return "---\n" + string(body), nil
+}
+
 type referrersResult []common.Referrer
 
 func (ref referrersResult) string(format string, maxArtifactTypeLen int) (string, error) {
@@ -1358,6 +1502,18 @@ func getCVETableWriter(writer io.Writer) *tablewriter.Table {
 	return table
 }
 
+func getPackageTableWriter(writer io.Writer) *tablewriter.Table {
+	table := getCommonTableWriter(writer)
+	table.Options(
+		tablewriter.WithColumnWidths(tw.NewMapper[int, int]().
+			Set(colSbomPkgNameIndex, sbomPkgNameWidth).
+			Set(colSbomPkgVersionIndex, sbomPkgVersionWidth).
+			Set(colSbomPkgPURLIndex, sbomPkgPURLWidth)),
+	)
+
+	return table
+}
+
 func (service searchService) getRepos(ctx context.Context, config SearchConfig, username, password string,
 	rch chan stringResult, wtgrp *sync.WaitGroup,
 ) {
@@ -1433,6 +1589,16 @@ const (
 
 	cveColTotalCount = 7
 
+	sbomPkgNameWidth    = 35
+	sbomPkgVersionWidth = 20
+	sbomPkgPURLWidth    = 60
+
+	colSbomPkgNameIndex    = 0
+	colSbomPkgVersionIndex = 1
+	colSbomPkgPURLIndex    = 2
+
+	sbomColTotalCount = 3
+
 	defaultOutputFormat = "text"
 )
 
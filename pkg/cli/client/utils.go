@@ -230,6 +230,14 @@ func printCVETableHeader(writer io.Writer) {
 	table.Render()                  //nolint:errcheck
 }
 
+func printPackageTableHeader(writer io.Writer) {
+	table := getPackageTableWriter(writer)
+	columnHeadingsRow := []string{"PACKAGE", "VERSION", "PURL"}
+
+	table.Append(columnHeadingsRow) //nolint:errcheck
+	table.Render()                  //nolint:errcheck
+}
+
 func printReferrersTableHeader(config SearchConfig, writer io.Writer, maxArtifactTypeLen int) {
 	if config.OutputFormat != "" && config.OutputFormat != defaultOutputFormat {
 		return
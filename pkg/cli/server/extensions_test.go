@@ -1109,7 +1109,8 @@ func TestServeSearchEnabledDefaultCVEDB(t *testing.T) {
 
 		// The default config handling logic will convert the 1h interval to a 2h interval
 		substring := "\"Search\":{\"Enable\":true,\"CVE\":{\"UpdateInterval\":7200000000000,\"Trivy\":" +
-			"{\"DBRepository\":\"ghcr.io/aquasecurity/trivy-db\",\"JavaDBRepository\":\"ghcr.io/aquasecurity/trivy-java-db\"}}}"
+			"{\"DBRepository\":\"ghcr.io/aquasecurity/trivy-db\",\"JavaDBRepository\":\"ghcr.io/aquasecurity/trivy-java-db\"," +
+			"\"SbomScanMode\":\"\"}}}"
 
 		found, err := ReadLogFileAndSearchString(logPath, substring, readLogFileTimeout)
 
@@ -2022,4 +2023,76 @@ func TestEventsExtension(t *testing.T) {
 		So(err, ShouldNotBeNil)
 		So(err.Error(), ShouldContainSubstring, zerr.ErrUnsupportedEventSink.Error())
 	})
+
+	Convey("Unsupported event delivery", t, func(c C) {
+		content := `{
+					"storage": {
+						"rootDirectory": "%s"
+					},
+					"http": {
+						"address": "127.0.0.1",
+						"port": "%s"
+					},
+					"log": {
+						"level": "debug",
+						"output": "%s"
+					},
+					"extensions": {
+						"events": {
+							"enable": true,
+							"sinks": [{
+								"type": "kafka",
+								"address": "127.0.0.1:9092",
+								"channel": "events",
+								"delivery": "exactly-once"
+							}]
+						}
+					}
+				}`
+
+		logPath, err := runCLIWithConfig(t.TempDir(), content)
+		defer func(p string) {
+			if p != "" {
+				os.Remove(p)
+			}
+		}(logPath) // clean up
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, zerr.ErrUnsupportedEventDelivery.Error())
+	})
+
+	Convey("Invalid event sink filter", t, func(c C) {
+		content := `{
+					"storage": {
+						"rootDirectory": "%s"
+					},
+					"http": {
+						"address": "127.0.0.1",
+						"port": "%s"
+					},
+					"log": {
+						"level": "debug",
+						"output": "%s"
+					},
+					"extensions": {
+						"events": {
+							"enable": true,
+							"sinks": [{
+								"type": "http",
+								"address": "http://localhost:8080/events",
+								"eventTypes": ["zotregistry.image.*"],
+								"repositories": ["library/[alpine"]
+							}]
+						}
+					}
+				}`
+
+		logPath, err := runCLIWithConfig(t.TempDir(), content)
+		defer func(p string) {
+			if p != "" {
+				os.Remove(p)
+			}
+		}(logPath) // clean up
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, zerr.ErrInvalidEventSinkFilter.Error())
+	})
 }
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,11 +27,14 @@ import (
 	"zotregistry.dev/zot/pkg/api"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
+	"zotregistry.dev/zot/pkg/cluster"
 	"zotregistry.dev/zot/pkg/common"
 	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	zlog "zotregistry.dev/zot/pkg/log"
+	"zotregistry.dev/zot/pkg/storage"
 	storageConstants "zotregistry.dev/zot/pkg/storage/constants"
 )
 
@@ -366,6 +371,217 @@ func validateExtensionsConfig(cfg *config.Config, log zlog.Logger) error {
 		}
 	}
 
+	if err := validateSbomScanMode(cfg, log); err != nil {
+		return err
+	}
+
+	if err := validateLintRules(cfg, log); err != nil {
+		return err
+	}
+
+	if err := validateAdmissionWebhooks(cfg, log); err != nil {
+		return err
+	}
+
+	if err := validateImageTrustPolicies(cfg, log); err != nil {
+		return err
+	}
+
+	return validateTracing(cfg, log)
+}
+
+func validateSbomScanMode(cfg *config.Config, log zlog.Logger) error {
+	if cfg.Extensions == nil || cfg.Extensions.Search == nil || cfg.Extensions.Search.CVE == nil ||
+		cfg.Extensions.Search.CVE.Trivy == nil {
+		return nil
+	}
+
+	switch cfg.Extensions.Search.CVE.Trivy.SbomScanMode {
+	case "", extconf.SbomScanModeIgnore, extconf.SbomScanModePrefer, extconf.SbomScanModeRequire:
+		return nil
+	default:
+		msg := "trivy sbomScanMode must be one of ignore, prefer or require"
+		log.Error().Err(zerr.ErrBadConfig).Str("sbomScanMode", cfg.Extensions.Search.CVE.Trivy.SbomScanMode).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+}
+
+func validateLintRules(cfg *config.Config, log zlog.Logger) error {
+	if cfg.Extensions == nil || cfg.Extensions.Lint == nil {
+		return nil
+	}
+
+	for _, rule := range cfg.Extensions.Lint.Rules {
+		var msg string
+
+		switch rule.Type {
+		case extconf.LintRuleMaxLayers:
+			if rule.MaxLayers <= 0 {
+				msg = "lint rule maxLayers must be positive"
+			}
+		case extconf.LintRuleMaxSize:
+			if rule.MaxSize <= 0 {
+				msg = "lint rule maxSize must be positive"
+			}
+		case extconf.LintRuleRequiredArchitectures:
+			if len(rule.Architectures) == 0 {
+				msg = "lint rule requiredArchitectures must list at least one architecture"
+			}
+		case extconf.LintRuleBannedMediaTypes:
+			if len(rule.MediaTypes) == 0 {
+				msg = "lint rule bannedMediaTypes must list at least one media type"
+			}
+		case extconf.LintRuleRequiredLabels:
+			if len(rule.Labels) == 0 {
+				msg = "lint rule requiredLabels must list at least one label"
+			}
+		case extconf.LintRuleForbiddenBaseImages:
+			if len(rule.BaseImages) == 0 {
+				msg = "lint rule forbiddenBaseImages must list at least one base image glob pattern"
+			}
+		case extconf.LintRuleNoRootUser:
+		default:
+			msg = "unsupported lint rule type"
+		}
+
+		if msg != "" {
+			log.Error().Err(zerr.ErrBadConfig).Str("rule", rule.Type).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, pattern := range append(slices.Clone(rule.Repositories), rule.BaseImages...) {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("rule", rule.Type).Str("pattern", pattern).
+					Msg("lint rule glob pattern could not be compiled")
+
+				return fmt.Errorf("%w: lint rule glob pattern could not be compiled: %s",
+					zerr.ErrBadConfig, pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateAdmissionWebhooks(cfg *config.Config, log zlog.Logger) error {
+	if cfg.Extensions == nil || cfg.Extensions.Lint == nil {
+		return nil
+	}
+
+	for _, webhook := range cfg.Extensions.Lint.AdmissionWebhooks {
+		webhookURL, err := url.ParseRequestURI(webhook.URL)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			msg := "admission webhook url must be a valid http(s) url"
+			log.Error().Err(zerr.ErrBadConfig).Str("webhook", webhook.Name).Str("url", webhook.URL).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, pattern := range webhook.Repositories {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("webhook", webhook.Name).Str("pattern", pattern).
+					Msg("admission webhook repo glob pattern could not be compiled")
+
+				return fmt.Errorf("%w: admission webhook repo glob pattern could not be compiled: %s",
+					zerr.ErrBadConfig, pattern)
+			}
+		}
+
+		if webhook.CACertFile != "" {
+			if _, err := common.CreateHTTPClient(&common.HTTPClientOptions{
+				TLSEnabled:  true,
+				VerifyTLS:   true,
+				CertOptions: common.HTTPClientCertOptions{RootCaCertFile: webhook.CACertFile},
+			}); err != nil {
+				log.Error().Err(err).Str("webhook", webhook.Name).Str("caCertFile", webhook.CACertFile).
+					Msg("admission webhook ca certificate could not be loaded")
+
+				return fmt.Errorf("%w: admission webhook ca certificate could not be loaded: %s",
+					zerr.ErrBadConfig, webhook.CACertFile)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateImageTrustPolicies(cfg *config.Config, log zlog.Logger) error {
+	if cfg.Extensions == nil || cfg.Extensions.Trust == nil {
+		return nil
+	}
+
+	for _, identity := range cfg.Extensions.Trust.CosignIdentities {
+		if (identity.Subject == "" && identity.SubjectRegExp == "") || (identity.Issuer == "" && identity.IssuerRegExp == "") {
+			msg := "cosign identity must set a subject or subjectRegExp and an issuer or issuerRegExp"
+			log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, expr := range []string{identity.SubjectRegExp, identity.IssuerRegExp} {
+			if _, err := regexp.Compile(expr); err != nil {
+				log.Error().Err(err).Str("regexp", expr).Msg("cosign identity regexp could not be compiled")
+
+				return fmt.Errorf("%w: cosign identity regexp could not be compiled: %s", zerr.ErrBadConfig, expr)
+			}
+		}
+	}
+
+	for _, policy := range cfg.Extensions.Trust.Policies {
+		if len(policy.Repositories) == 0 {
+			msg := "image trust policy must list at least one repository glob pattern"
+			log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, pattern := range policy.Repositories {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+					Msg("image trust policy repo glob pattern could not be compiled")
+
+				return fmt.Errorf("%w: image trust policy repo glob pattern could not be compiled: %s",
+					zerr.ErrBadConfig, pattern)
+			}
+		}
+
+		for _, signatureType := range policy.SignatureTypes {
+			if signatureType != common.CosignSignature && signatureType != common.NotationSignature {
+				msg := "image trust policy signature types must be cosign or notation"
+				log.Error().Err(zerr.ErrBadConfig).Str("signatureType", signatureType).Msg(msg)
+
+				return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateTracing(cfg *config.Config, log zlog.Logger) error {
+	if !cfg.IsTracingEnabled() {
+		return nil
+	}
+
+	tracingConfig := cfg.Extensions.Tracing
+
+	endpoint, err := url.ParseRequestURI(tracingConfig.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		msg := "tracing endpoint must be a valid url of an OTLP/HTTP collector"
+		log.Error().Err(zerr.ErrBadConfig).Str("endpoint", tracingConfig.Endpoint).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	if tracingConfig.SampleRate != nil && (*tracingConfig.SampleRate < 0 || *tracingConfig.SampleRate > 1) {
+		msg := "tracing sampleRate must be between 0 and 1"
+		log.Error().Err(zerr.ErrBadConfig).Float64("sampleRate", *tracingConfig.SampleRate).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
 	return nil
 }
 
@@ -378,6 +594,20 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		return err
 	}
 
+	if err := validateQuota(config, log); err != nil {
+		return err
+	}
+
+	if err := validateImmutableTags(config.Storage.ImmutableTags, log); err != nil {
+		return err
+	}
+
+	for _, subPathConfig := range config.Storage.SubPaths {
+		if err := validateImmutableTags(subPathConfig.ImmutableTags, log); err != nil {
+			return err
+		}
+	}
+
 	if err := validateLDAP(config, log); err != nil {
 		return err
 	}
@@ -386,6 +616,22 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		return err
 	}
 
+	if err := validateTokenService(config, log); err != nil {
+		return err
+	}
+
+	if err := validateRatelimitPolicies(config, log); err != nil {
+		return err
+	}
+
+	if err := validateRobotAccounts(config, log); err != nil {
+		return err
+	}
+
+	if err := validateAccessControlMetaDB(config, log); err != nil {
+		return err
+	}
+
 	if err := validateSync(config, log); err != nil {
 		return err
 	}
@@ -411,8 +657,8 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 	}
 
 	if len(config.Storage.StorageDriver) != 0 {
-		// enforce s3 driver in case of using storage driver
-		if config.Storage.StorageDriver["name"] != storageConstants.S3StorageDriverName {
+		// enforce a supported cloud driver in case of using storage driver
+		if !isObjectStorageDriver(config.Storage.StorageDriver["name"]) {
 			msg := "unsupported storage driver"
 			log.Error().Err(zerr.ErrBadConfig).Interface("cacheDriver", config.Storage.StorageDriver["name"]).Msg(msg)
 
@@ -428,14 +674,14 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		}
 	}
 
-	// enforce s3 driver on subpaths in case of using storage driver
+	// enforce a supported cloud driver on subpaths in case of using storage driver
 	if config.Storage.SubPaths != nil {
 		if len(config.Storage.SubPaths) > 0 {
 			subPaths := config.Storage.SubPaths
 
 			for route, storageConfig := range subPaths {
 				if len(storageConfig.StorageDriver) != 0 {
-					if storageConfig.StorageDriver["name"] != storageConstants.S3StorageDriverName {
+					if !isObjectStorageDriver(storageConfig.StorageDriver["name"]) {
 						msg := "unsupported storage driver"
 						log.Error().Err(zerr.ErrBadConfig).Str("subpath", route).Interface("storageDriver",
 							storageConfig.StorageDriver["name"]).Msg(msg)
@@ -466,6 +712,10 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 				return fmt.Errorf("%w: %s", glob.ErrBadPattern, msg)
 			}
 		}
+
+		if err := validateImmutableTags(config.HTTP.AccessControl.ImmutableTags, log); err != nil {
+			return err
+		}
 	}
 
 	// check validity of scale out cluster config
@@ -507,6 +757,12 @@ func validateOpenIDConfig(cfg *config.Config, log zlog.Logger) error {
 	return nil
 }
 
+func isObjectStorageDriver(name interface{}) bool {
+	driverName, ok := name.(string)
+
+	return ok && storage.IsObjectStorageDriver(driverName)
+}
+
 func validateAuthzPolicies(config *config.Config, log zlog.Logger) error {
 	if (config.HTTP.Auth == nil || (config.HTTP.Auth.HTPasswd.Path == "" && config.HTTP.Auth.LDAP == nil &&
 		config.HTTP.Auth.OpenID == nil)) && !authzContainsOnlyAnonymousPolicy(config) {
@@ -576,6 +832,10 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 					config.Extensions.Sync.Registries[id].TLSVerify = &defaultVal
 				}
 			}
+
+			if config.Extensions.Sync.ProxyCache != nil && config.Extensions.Sync.ProxyCache.Enable == nil {
+				config.Extensions.Sync.ProxyCache.Enable = &defaultVal
+			}
 		}
 
 		if config.Extensions.Search != nil {
@@ -625,6 +885,12 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 			}
 		}
 
+		if config.Extensions.Tracing != nil {
+			if config.Extensions.Tracing.Enable == nil {
+				config.Extensions.Tracing.Enable = &defaultVal
+			}
+		}
+
 		if config.Extensions.Scrub != nil {
 			if config.Extensions.Scrub.Enable == nil {
 				config.Extensions.Scrub.Enable = &defaultVal
@@ -1069,6 +1335,151 @@ func validateLDAP(config *config.Config, log zlog.Logger) error {
 	return nil
 }
 
+func validateTokenService(config *config.Config, log zlog.Logger) error {
+	if config.HTTP.Auth == nil || config.HTTP.Auth.TokenService == nil {
+		return nil
+	}
+
+	tokenService := config.HTTP.Auth.TokenService
+
+	if tokenService.Key == "" || tokenService.Service == "" {
+		msg := "invalid token service configuration, missing mandatory keys: key and service"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	if config.HTTP.Auth.Bearer != nil {
+		msg := "the token service can't be enabled together with bearer auth using an external token server"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	// tokens are only issued to users authenticated with credentials
+	if !config.IsHtpasswdAuthEnabled() && !config.IsLdapAuthEnabled() && !config.IsAPIKeyEnabled() {
+		msg := "the token service needs htpasswd, ldap or api keys authentication"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	if tokenService.Realm != "" {
+		realm, err := url.ParseRequestURI(tokenService.Realm)
+		if err != nil || (realm.Scheme != "http" && realm.Scheme != "https") || realm.Host == "" {
+			msg := "token service realm must be the url of the token endpoint"
+			log.Error().Err(zerr.ErrBadConfig).Str("realm", tokenService.Realm).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+	}
+
+	return nil
+}
+
+func validateRatelimitPolicies(cfg *config.Config, log zlog.Logger) error {
+	if !cfg.IsRatelimitPolicyEnabled() {
+		return nil
+	}
+
+	for _, policy := range cfg.HTTP.Ratelimit.Policies {
+		switch policy.Key {
+		case config.RatelimitKeyUsername, config.RatelimitKeyAPIKey, config.RatelimitKeyIP,
+			config.RatelimitKeyRepository:
+		default:
+			msg := "ratelimit policy key must be one of username, apikey, ip or repository"
+			log.Error().Err(zerr.ErrBadConfig).Str("key", policy.Key).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		if policy.ManifestPulls == nil && policy.BlobBytes == nil {
+			msg := "ratelimit policy must limit manifestPulls, blobBytes or both"
+			log.Error().Err(zerr.ErrBadConfig).Str("key", policy.Key).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, budget := range []*config.RatelimitBudget{policy.ManifestPulls, policy.BlobBytes} {
+			if budget != nil && (budget.Limit <= 0 || budget.Interval <= 0 || budget.Burst < 0) {
+				msg := "ratelimit policy budgets need a positive limit and interval"
+				log.Error().Err(zerr.ErrBadConfig).Int64("limit", budget.Limit).Str("interval", budget.Interval.String()).
+					Msg(msg)
+
+				return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+			}
+		}
+
+		for _, pattern := range policy.Repositories {
+			if !glob.ValidatePattern(pattern) {
+				msg := "failed to compile ratelimit policy repository pattern"
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).Msg(msg)
+
+				return fmt.Errorf("%w: %s", glob.ErrBadPattern, msg)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRobotAccounts(config *config.Config, log zlog.Logger) error {
+	if !config.IsRobotAccountsEnabled() {
+		return nil
+	}
+
+	// robot accounts are managed by admins, who log in with the other authn backends
+	if !config.IsHtpasswdAuthEnabled() && !config.IsLdapAuthEnabled() && !config.IsOpenIDAuthEnabled() {
+		msg := "robot accounts need htpasswd, ldap or openid authentication"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	if config.HTTP.AccessControl == nil ||
+		(len(config.HTTP.AccessControl.AdminPolicy.Users) == 0 && len(config.HTTP.AccessControl.AdminPolicy.Groups) == 0) {
+		msg := "robot accounts need an admin policy, only admins can manage them"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	return nil
+}
+
+func validateAccessControlMetaDB(config *config.Config, log zlog.Logger) error {
+	if !config.IsAccessControlInMetaDB() {
+		return nil
+	}
+
+	// the policies are managed by admins, who log in with the other authn backends
+	if !config.IsHtpasswdAuthEnabled() && !config.IsLdapAuthEnabled() && !config.IsOpenIDAuthEnabled() {
+		msg := "access control policies stored in metaDB need htpasswd, ldap or openid authentication"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	// the admin policy of the config file seeds metaDB, without it nobody could manage the policies
+	adminPolicy := config.HTTP.AccessControl.AdminPolicy
+	if len(adminPolicy.Users) == 0 && len(adminPolicy.Groups) == 0 {
+		msg := "access control policies stored in metaDB need an admin policy, only admins can manage them"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	if config.HTTP.AccessControl.MetaDB.ReloadInterval < 0 {
+		msg := "access control policies reload interval must be positive"
+		log.Error().Err(zerr.ErrBadConfig).
+			Dur("reloadInterval", config.HTTP.AccessControl.MetaDB.ReloadInterval).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	return nil
+}
+
 func validateHTTP(config *config.Config, log zlog.Logger) error {
 	if config.HTTP.Port != "" {
 		port, err := strconv.ParseInt(config.HTTP.Port, 10, 64)
@@ -1159,6 +1570,117 @@ func validateGCRules(retention config.ImageRetention, log zlog.Logger) error {
 						zerr.ErrBadConfig, regex)
 				}
 			}
+
+			for _, selector := range tagRule.Annotations {
+				if selector.Key == "" {
+					log.Error().Err(zerr.ErrBadConfig).Msg("retention annotation selector key is empty")
+
+					return fmt.Errorf("%w: retention annotation selector key is empty", zerr.ErrBadConfig)
+				}
+
+				if _, err := regexp.Compile(selector.Value); err != nil {
+					log.Error().Err(glob.ErrBadPattern).Str("regex", selector.Value).
+						Msg("retention annotation regex could not be compiled")
+
+					return fmt.Errorf("%w: retention annotation regex could not be compiled: %s",
+						zerr.ErrBadConfig, selector.Value)
+				}
+			}
+
+			if tagRule.MaxSize < 0 {
+				log.Error().Err(zerr.ErrBadConfig).Int64("maxSize", tagRule.MaxSize).
+					Msg("retention maxSize can not be negative")
+
+				return fmt.Errorf("%w: retention maxSize can not be negative: %d", zerr.ErrBadConfig, tagRule.MaxSize)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateQuota(config *config.Config, log zlog.Logger) error {
+	if err := validateQuotaPolicies(config.Storage.Quota, log); err != nil {
+		return err
+	}
+
+	// subpaths
+	for _, subPath := range config.Storage.SubPaths {
+		if err := validateQuotaPolicies(subPath.Quota, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateQuotaPolicies(quota config.StorageQuota, log zlog.Logger) error {
+	for _, policy := range quota.Policies {
+		if len(policy.Repositories) == 0 {
+			msg := "quota policy must specify at least one repository glob pattern"
+			log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, pattern := range policy.Repositories {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+					Msg("quota repo glob pattern could not be compiled")
+
+				return fmt.Errorf("%w: quota repo glob pattern could not be compiled: %s",
+					zerr.ErrBadConfig, pattern)
+			}
+		}
+
+		if policy.SoftLimit < 0 || policy.HardLimit < 0 {
+			log.Error().Err(zerr.ErrBadConfig).Strs("repositories", policy.Repositories).
+				Int64("softLimit", policy.SoftLimit).Int64("hardLimit", policy.HardLimit).
+				Msg("invalid quota limits, they cannot be negative")
+
+			return fmt.Errorf("%w: invalid quota limits, they cannot be negative: %v",
+				zerr.ErrBadConfig, policy.Repositories)
+		}
+
+		if policy.HardLimit > 0 && policy.SoftLimit > policy.HardLimit {
+			log.Error().Err(zerr.ErrBadConfig).Strs("repositories", policy.Repositories).
+				Int64("softLimit", policy.SoftLimit).Int64("hardLimit", policy.HardLimit).
+				Msg("invalid quota limits, soft limit is greater than hard limit")
+
+			return fmt.Errorf("%w: invalid quota limits, soft limit is greater than hard limit: %v",
+				zerr.ErrBadConfig, policy.Repositories)
+		}
+	}
+
+	return nil
+}
+
+func validateImmutableTags(policies []config.ImmutableTagsPolicy, log zlog.Logger) error {
+	for _, policy := range policies {
+		if len(policy.Repositories) == 0 || len(policy.Patterns) == 0 {
+			msg := "immutable tags policy must specify at least one repository glob pattern and one tag regex"
+			log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		for _, pattern := range policy.Repositories {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+					Msg("immutable tags repo glob pattern could not be compiled")
+
+				return fmt.Errorf("%w: immutable tags repo glob pattern could not be compiled: %s",
+					zerr.ErrBadConfig, pattern)
+			}
+		}
+
+		for _, regex := range policy.Patterns {
+			if _, err := regexp.Compile(regex); err != nil {
+				log.Error().Err(err).Str("regex", regex).Msg("immutable tags regex could not be compiled")
+
+				return fmt.Errorf("%w: immutable tags regex could not be compiled: %s",
+					zerr.ErrBadConfig, regex)
+			}
 		}
 	}
 
@@ -1230,6 +1752,64 @@ func validateSync(config *config.Config, log zlog.Logger) error {
 				}
 			}
 		}
+
+		if config.Extensions.Sync.ProxyCache != nil {
+			if err := validateProxyCache(config.Extensions.Sync.ProxyCache, log); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateProxyCache(proxyCache *syncconf.ProxyCacheConfig, log zlog.Logger) error {
+	if strings.ContainsAny(proxyCache.Prefix, "*?[{") {
+		msg := "sync proxyCache prefix can not contain glob patterns"
+		log.Error().Err(zerr.ErrBadConfig).Str("prefix", proxyCache.Prefix).Msg(msg)
+
+		return fmt.Errorf("%w: %s: %s", zerr.ErrBadConfig, msg, proxyCache.Prefix)
+	}
+
+	// the upstream host is taken from the pulled repository, allowing any host would make zot an open proxy
+	if len(proxyCache.Upstreams) == 0 {
+		msg := "sync proxyCache requires at least one upstream"
+		log.Error().Err(zerr.ErrBadConfig).Msg(msg)
+
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+	}
+
+	for upstreamID, upstream := range proxyCache.Upstreams {
+		if upstream.Host == "" || !glob.ValidatePattern(upstream.Host) {
+			msg := "sync proxyCache upstream host could not be compiled"
+			log.Error().Err(zerr.ErrBadConfig).Int("id", upstreamID).Str("host", upstream.Host).Msg(msg)
+
+			return fmt.Errorf("%w: %s: %q", zerr.ErrBadConfig, msg, upstream.Host)
+		}
+
+		if upstream.URL != "" {
+			// an url can only be used for a single upstream host
+			if strings.ContainsAny(upstream.Host, "*?[{") {
+				msg := "sync proxyCache upstream url can not be used with a glob pattern host"
+				log.Error().Err(zerr.ErrBadConfig).Int("id", upstreamID).Str("host", upstream.Host).Msg(msg)
+
+				return fmt.Errorf("%w: %s: %s", zerr.ErrBadConfig, msg, upstream.Host)
+			}
+
+			if _, err := url.ParseRequestURI(upstream.URL); err != nil {
+				msg := "sync proxyCache upstream url could not be parsed"
+				log.Error().Err(err).Int("id", upstreamID).Str("url", upstream.URL).Msg(msg)
+
+				return fmt.Errorf("%w: %s: %s", zerr.ErrBadConfig, msg, upstream.URL)
+			}
+		}
+
+		if upstream.MaxRetries != nil && upstream.RetryDelay == nil {
+			msg := "retryDelay is required when using maxRetries"
+			log.Error().Err(zerr.ErrBadConfig).Int("id", upstreamID).Str("host", upstream.Host).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
 	}
 
 	return nil
@@ -1255,7 +1835,68 @@ func validateClusterConfig(config *config.Config, log zlog.Logger) error {
 
 			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
 		}
+
+		if !cluster.IsValidHashing(config.Cluster.Hashing) {
+			msg := fmt.Sprintf("hashing for scale out cluster must be %q or %q",
+				cluster.ModuloHashing, cluster.RendezvousHashing)
+			log.Error().Err(zerr.ErrBadConfig).Str("hashing", config.Cluster.Hashing).Msg(msg)
+
+			return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+		}
+
+		// repositories are owned by members, so each member must be listed only once
+		for _, members := range [][]string{config.Cluster.Members, config.Cluster.PreviousMembers} {
+			if duplicate, found := findDuplicate(members); found {
+				msg := "duplicate member in scale out cluster"
+				log.Error().Err(zerr.ErrBadConfig).Str("member", duplicate).Msg(msg)
+
+				return fmt.Errorf("%w: %s %s", zerr.ErrBadConfig, msg, duplicate)
+			}
+		}
+
+		// repositories stored locally are pushed to their new owner with the client certificate of the member,
+		// which is only authenticated without basic and bearer authentication
+		if hasLocalStorage(config) && (config.IsBasicAuthnEnabled() || config.IsBearerAuthEnabled()) {
+			msg := "scale out cluster members can't move the repositories stored locally with basic or bearer authentication"
+
+			if len(config.Cluster.PreviousMembers) > 0 {
+				log.Error().Err(zerr.ErrBadConfig).Strs("previousMembers", config.Cluster.PreviousMembers).Msg(msg)
+
+				return fmt.Errorf("%w: %s", zerr.ErrBadConfig, msg)
+			}
+
+			log.Warn().Msg(msg + ", membership changes need a storage shared by all the members")
+		}
 	}
 
 	return nil
 }
+
+// hasLocalStorage returns true if the default storage or any subpath doesn't use a storage driver.
+func hasLocalStorage(config *config.Config) bool {
+	if config.Storage.StorageDriver == nil {
+		return true
+	}
+
+	for _, storageConfig := range config.Storage.SubPaths {
+		if storageConfig.StorageDriver == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findDuplicate(members []string) (string, bool) {
+	seen := make(map[string]struct{}, len(members))
+
+	for _, member := range members {
+		if _, ok := seen[member]; ok {
+			return member, true
+		}
+
+		seen[member] = struct{}{}
+	}
+
+	return "", false
+}
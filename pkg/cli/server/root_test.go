@@ -668,6 +668,239 @@ storage:
 		So(cli.NewServerRootCmd().Execute(), ShouldNotBeNil)
 	})
 
+	Convey("Test verify gc image retention annotations and maxSize rules", t, func(c C) {
+		tmpfile, err := os.CreateTemp("", "zot-test*.json")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(tmpfile.Name()) // clean up
+
+		verify := func(keepTags string) error {
+			content := []byte(`{
+				"distSpecVersion": "1.1.1",
+				"storage": {
+					"rootDirectory": "/tmp/zot",
+					"gc": true,
+					"retention": {
+						"policies": [
+							{
+								"repositories": ["infra/*"],
+								"keepTags": [` + keepTags + `]
+							}
+						]
+					}
+				},
+				"http": {
+					"address": "127.0.0.1",
+					"port": "8080"
+				},
+				"log": {
+					"level": "debug"
+				}
+			}`)
+
+			err := os.WriteFile(tmpfile.Name(), content, 0o0600)
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		So(verify(`{"annotations": [{"key": "org.opencontainers.image.vendor", "value": "["}]}`), ShouldNotBeNil)
+		So(verify(`{"annotations": [{"value": "zot"}]}`), ShouldNotBeNil)
+		So(verify(`{"maxSize": -1}`), ShouldNotBeNil)
+		So(verify(`{"annotations": [{"key": "org.opencontainers.image.vendor", "value": "^zot$"}],
+			"keepBaseImages": true, "signed": true, "maxSize": 1000000}`), ShouldBeNil)
+	})
+
+	Convey("Test verify storage quota policies", t, func(c C) {
+		tmpfile, err := os.CreateTemp("", "zot-test*.json")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(tmpfile.Name()) // clean up
+
+		verify := func(quota string) error {
+			content := []byte(`{
+				"distSpecVersion": "1.1.1",
+				"storage": {
+					"rootDirectory": "/tmp/zot",
+					"subPaths": {
+						"/a": {
+							"rootDirectory": "/tmp/zot-a",
+							"quota": ` + quota + `
+						}
+					}
+				},
+				"http": {
+					"address": "127.0.0.1",
+					"port": "8080"
+				},
+				"log": {
+					"level": "debug"
+				}
+			}`)
+
+			err := os.WriteFile(tmpfile.Name(), content, 0o0600)
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err = verify(`{"policies": [{"repositories": ["team-a/**"], "softLimit": 1024, "hardLimit": 2048}]}`)
+		So(err, ShouldBeNil)
+
+		err = verify(`{"policies": [{"repositories": ["["], "hardLimit": 2048}]}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"policies": [{"hardLimit": 2048}]}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"policies": [{"repositories": ["**"], "hardLimit": -1}]}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"policies": [{"repositories": ["**"], "softLimit": 4096, "hardLimit": 2048}]}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify immutable tags policies", t, func(c C) {
+		tmpfile, err := os.CreateTemp("", "zot-test*.json")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(tmpfile.Name()) // clean up
+
+		verify := func(immutableTags string) error {
+			content := []byte(`{
+				"distSpecVersion": "1.1.1",
+				"storage": {
+					"rootDirectory": "/tmp/zot"
+				},
+				"http": {
+					"address": "127.0.0.1",
+					"port": "8080",
+					"accessControl": {
+						"repositories": {
+							"**": {
+								"anonymousPolicy": ["read", "create"]
+							}
+						},
+						"immutableTags": ` + immutableTags + `
+					}
+				},
+				"log": {
+					"level": "debug"
+				}
+			}`)
+
+			err := os.WriteFile(tmpfile.Name(), content, 0o0600)
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err = verify(`[{"repositories": ["releases/**"], "patterns": ["^v\\d+\\.\\d+\\.\\d+$"]}]`)
+		So(err, ShouldBeNil)
+
+		err = verify(`[{"repositories": ["["], "patterns": ["^v.*"]}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"repositories": ["**"], "patterns": ["v("]}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"patterns": ["^v.*"]}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"repositories": ["**"]}]`)
+		So(err, ShouldNotBeNil)
+
+		// immutable tags of the storage config don't need authorization
+		content := []byte(`{
+			"distSpecVersion": "1.1.1",
+			"storage": {
+				"rootDirectory": "/tmp/zot",
+				"immutableTags": [{"repositories": ["releases/**"], "patterns": ["^v.*"]}]
+			},
+			"http": {
+				"address": "127.0.0.1",
+				"port": "8080"
+			}
+		}`)
+
+		err = os.WriteFile(tmpfile.Name(), content, 0o0600)
+		So(err, ShouldBeNil)
+
+		os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+		So(cli.NewServerRootCmd().Execute(), ShouldBeNil)
+
+		content = []byte(`{
+			"distSpecVersion": "1.1.1",
+			"storage": {
+				"rootDirectory": "/tmp/zot",
+				"immutableTags": [{"repositories": ["releases/**"], "patterns": ["v("]}]
+			},
+			"http": {
+				"address": "127.0.0.1",
+				"port": "8080"
+			}
+		}`)
+
+		err = os.WriteFile(tmpfile.Name(), content, 0o0600)
+		So(err, ShouldBeNil)
+
+		os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+		So(cli.NewServerRootCmd().Execute(), ShouldNotBeNil)
+
+		// subPaths have immutable tags policies of their own
+		content = []byte(`{
+			"distSpecVersion": "1.1.1",
+			"storage": {
+				"rootDirectory": "/tmp/zot",
+				"subPaths": {
+					"/releases": {
+						"rootDirectory": "/tmp/zot-releases",
+						"immutableTags": [{"repositories": ["**"], "patterns": ["^v.*"]}]
+					}
+				}
+			},
+			"http": {
+				"address": "127.0.0.1",
+				"port": "8080"
+			}
+		}`)
+
+		err = os.WriteFile(tmpfile.Name(), content, 0o0600)
+		So(err, ShouldBeNil)
+
+		os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+		So(cli.NewServerRootCmd().Execute(), ShouldBeNil)
+
+		content = []byte(`{
+			"distSpecVersion": "1.1.1",
+			"storage": {
+				"rootDirectory": "/tmp/zot",
+				"subPaths": {
+					"/releases": {
+						"rootDirectory": "/tmp/zot-releases",
+						"immutableTags": [{"repositories": ["**"], "patterns": ["v("]}]
+					}
+				}
+			},
+			"http": {
+				"address": "127.0.0.1",
+				"port": "8080"
+			}
+		}`)
+
+		err = os.WriteFile(tmpfile.Name(), content, 0o0600)
+		So(err, ShouldBeNil)
+
+		os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+		So(cli.NewServerRootCmd().Execute(), ShouldNotBeNil)
+	})
+
 	Convey("Test apply defaults cache db", t, func(c C) {
 		tmpfile, err := os.CreateTemp("", "zot-test*.json")
 		So(err, ShouldBeNil)
@@ -724,13 +957,13 @@ storage:
 		So(err, ShouldNotBeNil)
 	})
 
-	Convey("Test verify storage driver different than s3", t, func(c C) {
+	Convey("Test verify unsupported storage driver", t, func(c C) {
 		tmpfile, err := os.CreateTemp("", "zot-test*.json")
 		So(err, ShouldBeNil)
 
 		defer os.Remove(tmpfile.Name()) // clean up
 
-		content := []byte(`{"storage":{"rootDirectory":"/tmp/zot", "storageDriver": {"name": "gcs"}},
+		content := []byte(`{"storage":{"rootDirectory":"/tmp/zot", "storageDriver": {"name": "swift"}},
 							"http":{"address":"127.0.0.1","port":"8080","realm":"zot",
 							"auth":{"htpasswd":{"path":"test/data/htpasswd"},"failDelay":1}}}`)
 		_, err = tmpfile.Write(content)
@@ -743,14 +976,14 @@ storage:
 		So(err, ShouldNotBeNil)
 	})
 
-	Convey("Test verify subpath storage driver different than s3", t, func(c C) {
+	Convey("Test verify unsupported subpath storage driver", t, func(c C) {
 		tmpfile, err := os.CreateTemp("", "zot-test*.json")
 		So(err, ShouldBeNil)
 
 		defer os.Remove(tmpfile.Name()) // clean up
 
 		content := []byte(`{"storage":{"rootDirectory":"/tmp/zot", "storageDriver": {"name": "s3"},
-							"subPaths": {"/a": {"rootDirectory": "/zot-a","storageDriver": {"name": "gcs"}}}},
+							"subPaths": {"/a": {"rootDirectory": "/zot-a","storageDriver": {"name": "swift"}}}},
 							"http":{"address":"127.0.0.1","port":"8080","realm":"zot",
 							"auth":{"htpasswd":{"path":"test/data/htpasswd"},"failDelay":1}}}`)
 		_, err = tmpfile.Write(content)
@@ -1042,6 +1275,520 @@ storage:
 		So(err, ShouldBeNil)
 	})
 
+	Convey("Test verify sync proxy cache", t, func(c C) {
+		verify := func(proxyCache string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"sync": {"proxyCache": ` + proxyCache + `}}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"prefix": "cache", "tagTTL": "10m", "upstreams": [{"host": "docker.io"}]}`)
+		So(err, ShouldBeNil)
+
+		// no upstreams
+		err = verify(`{"prefix": "cache", "tagTTL": "10m"}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"upstreams": [{"host": "docker.io", "url": "https://registry-1.docker.io",
+			"tagTTL": "5m"}, {"host": "*.gcr.io"}]}`)
+		So(err, ShouldBeNil)
+
+		// prefix with glob patterns
+		err = verify(`{"prefix": "cache/**"}`)
+		So(err, ShouldNotBeNil)
+
+		// missing upstream host
+		err = verify(`{"upstreams": [{"url": "https://registry-1.docker.io"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// invalid upstream host pattern
+		err = verify(`{"upstreams": [{"host": "[docker.io"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// url used with a glob pattern host
+		err = verify(`{"upstreams": [{"host": "*.gcr.io", "url": "https://gcr.io"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// invalid url
+		err = verify(`{"upstreams": [{"host": "docker.io", "url": "registry-1.docker.io"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// maxRetries without retryDelay
+		err = verify(`{"upstreams": [{"host": "docker.io", "maxRetries": 3}]}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify tracing", t, func(c C) {
+		verify := func(tracing string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"tracing": ` + tracing + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"endpoint": "http://localhost:4318", "service": "zot", "sampleRate": 0.5}`)
+		So(err, ShouldBeNil)
+
+		// endpoint is ignored if tracing is disabled
+		err = verify(`{"enable": false}`)
+		So(err, ShouldBeNil)
+
+		// missing endpoint
+		err = verify(`{"service": "zot"}`)
+		So(err, ShouldNotBeNil)
+
+		// endpoint is not an url
+		err = verify(`{"endpoint": "localhost:4318"}`)
+		So(err, ShouldNotBeNil)
+
+		// sample rate out of range
+		err = verify(`{"endpoint": "http://localhost:4318", "sampleRate": 2}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify sbom scan mode", t, func(c C) {
+		verify := func(sbomScanMode string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"search": {"enable": true,
+							"cve": {"trivy": {"sbomScanMode": "` + sbomScanMode + `"}}}}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		for _, sbomScanMode := range []string{"", "ignore", "prefer", "require"} {
+			err := verify(sbomScanMode)
+			So(err, ShouldBeNil)
+		}
+
+		err := verify("always")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify token service", t, func(c C) {
+		verify := func(auth string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080", "auth": ` + auth + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"htpasswd": {"path": "test/data/htpasswd"},
+			"tokenService": {"key": "token.key", "service": "zot", "expiration": "10m"}}`)
+		So(err, ShouldBeNil)
+
+		err = verify(`{"htpasswd": {"path": "test/data/htpasswd"},
+			"tokenService": {"key": "token.key", "service": "zot", "realm": "https://zot.example.com/zot/auth/token"}}`)
+		So(err, ShouldBeNil)
+
+		// missing service
+		err = verify(`{"htpasswd": {"path": "test/data/htpasswd"}, "tokenService": {"key": "token.key"}}`)
+		So(err, ShouldNotBeNil)
+
+		// no authn backend to check the credentials of the users
+		err = verify(`{"tokenService": {"key": "token.key", "service": "zot"}}`)
+		So(err, ShouldNotBeNil)
+
+		// both zot and an external server issuing tokens
+		err = verify(`{"htpasswd": {"path": "test/data/htpasswd"},
+			"bearer": {"realm": "https://auth.example.com/token", "service": "zot", "cert": "auth.crt"},
+			"tokenService": {"key": "token.key", "service": "zot"}}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"htpasswd": {"path": "test/data/htpasswd"},
+			"tokenService": {"key": "token.key", "service": "zot", "realm": "zot/auth/token"}}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify robot accounts", t, func(c C) {
+		verify := func(http string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080", ` + http + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}, "robotAccounts": true},
+			"accessControl": {"adminPolicy": {"users": ["admin"], "actions": ["read", "create", "update", "delete"]}}`)
+		So(err, ShouldBeNil)
+
+		// no authn backend for the admins
+		err = verify(`"auth": {"apikey": true, "robotAccounts": true},
+			"accessControl": {"adminPolicy": {"users": ["admin"], "actions": ["read", "create", "update", "delete"]}}`)
+		So(err, ShouldNotBeNil)
+
+		// no admins to manage the robot accounts
+		err = verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}, "robotAccounts": true}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}, "robotAccounts": true},
+			"accessControl": {"repositories": {"**": {"defaultPolicy": ["read"]}}}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify access control policies stored in metaDB", t, func(c C) {
+		verify := func(http string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080", ` + http + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}},
+			"accessControl": {"metaDB": {"enable": true, "reloadInterval": "30s"},
+			"adminPolicy": {"users": ["admin"], "actions": ["read", "create", "update", "delete"]}}`)
+		So(err, ShouldBeNil)
+
+		// disabled, the policies of the config file are used
+		err = verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}},
+			"accessControl": {"metaDB": {"enable": false}, "repositories": {"**": {"defaultPolicy": ["read"]}}}`)
+		So(err, ShouldBeNil)
+
+		// no authn backend for the admins
+		err = verify(`"auth": {"apikey": true},
+			"accessControl": {"metaDB": {"enable": true},
+			"adminPolicy": {"users": ["admin"], "actions": ["read", "create", "update", "delete"]}}`)
+		So(err, ShouldNotBeNil)
+
+		// no admins to manage the policies
+		err = verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}},
+			"accessControl": {"metaDB": {"enable": true}, "repositories": {"**": {"defaultPolicy": ["read"]}}}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`"auth": {"htpasswd": {"path": "test/data/htpasswd"}},
+			"accessControl": {"metaDB": {"enable": true, "reloadInterval": "-1s"},
+			"adminPolicy": {"users": ["admin"], "actions": ["read", "create", "update", "delete"]}}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify ratelimit policies", t, func(c C) {
+		verify := func(policies string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080", "ratelimit": {"policies": ` + policies + `}}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`[{"key": "username", "repositories": ["library/**"],
+			"manifestPulls": {"limit": 100, "interval": "6h"},
+			"blobBytes": {"limit": 10737418240, "interval": "1h", "burst": 21474836480}},
+			{"key": "ip", "manifestPulls": {"limit": 10, "interval": "1m"}},
+			{"key": "apikey", "blobBytes": {"limit": 1048576, "interval": "1s"}},
+			{"key": "repository", "manifestPulls": {"limit": 1000, "interval": "1h"}}]`)
+		So(err, ShouldBeNil)
+
+		err = verify(`[{"key": "group", "manifestPulls": {"limit": 100, "interval": "6h"}}]`)
+		So(err, ShouldNotBeNil)
+
+		// no budget
+		err = verify(`[{"key": "ip"}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"key": "ip", "manifestPulls": {"limit": 100}}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"key": "ip", "blobBytes": {"limit": 0, "interval": "1h"}}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"key": "ip", "repositories": ["[a-"], "manifestPulls": {"limit": 100, "interval": "6h"}}]`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify lint rules", t, func(c C) {
+		verify := func(rules string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"lint": {"enable": true, "rules": ` + rules + `}}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`[{"type": "maxLayers", "repositories": ["prod/**"], "maxLayers": 20},
+			{"type": "maxSize", "maxSize": 1073741824},
+			{"type": "requiredArchitectures", "architectures": ["amd64", "arm64"]},
+			{"type": "noRootUser"},
+			{"type": "bannedMediaTypes", "mediaTypes": ["application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"]},
+			{"type": "requiredLabels", "labels": ["org.opencontainers.image.source"]},
+			{"type": "forbiddenBaseImages", "baseImages": ["docker.io/library/centos:*"]}]`)
+		So(err, ShouldBeNil)
+
+		// unknown rule type
+		err = verify(`[{"type": "maxTags"}]`)
+		So(err, ShouldNotBeNil)
+
+		// missing rule parameters
+		for _, ruleType := range []string{
+			"maxLayers", "maxSize", "requiredArchitectures", "bannedMediaTypes", "requiredLabels", "forbiddenBaseImages",
+		} {
+			err = verify(`[{"type": "` + ruleType + `"}]`)
+			So(err, ShouldNotBeNil)
+		}
+
+		// invalid glob patterns
+		err = verify(`[{"type": "noRootUser", "repositories": ["prod/[a"]}]`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`[{"type": "forbiddenBaseImages", "baseImages": ["docker.io/[a"]}]`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify admission webhooks", t, func(c C) {
+		verify := func(lint string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"lint": ` + lint + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"enable": true, "admissionWebhooks": [{"name": "policy",
+			"url": "https://policy.example.com/admit", "repositories": ["prod/**"], "timeout": "5s",
+			"failOpen": true}]}`)
+		So(err, ShouldBeNil)
+
+		// url is not an http(s) url
+		err = verify(`{"enable": true, "admissionWebhooks": [{"name": "policy", "url": "policy.example.com"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// invalid repository pattern
+		err = verify(`{"enable": true, "admissionWebhooks": [{"name": "policy",
+			"url": "https://policy.example.com/admit", "repositories": ["prod/[a"]}]}`)
+		So(err, ShouldNotBeNil)
+
+		// ca certificate can not be loaded
+		err = verify(`{"enable": true, "admissionWebhooks": [{"name": "policy",
+			"url": "https://policy.example.com/admit", "caCertFile": "/does/not/exist/ca.crt"}]}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify image trust policies", t, func(c C) {
+		verify := func(trust string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"trust": ` + trust + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"enable": true, "cosign": true, "policies": [{"repositories": ["prod/**"],
+			"signatureTypes": ["cosign"], "enforceOnPush": true}]}`)
+		So(err, ShouldBeNil)
+
+		// no repositories
+		err = verify(`{"enable": true, "cosign": true, "policies": [{"signatureTypes": ["cosign"]}]}`)
+		So(err, ShouldNotBeNil)
+
+		// invalid repository pattern
+		err = verify(`{"enable": true, "cosign": true, "policies": [{"repositories": ["prod/[a"]}]}`)
+		So(err, ShouldNotBeNil)
+
+		// unsupported signature type
+		err = verify(`{"enable": true, "cosign": true, "policies": [{"repositories": ["prod/**"],
+			"signatureTypes": ["gpg"]}]}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify cosign identities", t, func(c C) {
+		verify := func(trust string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":{"address":"127.0.0.1","port":"8080"},
+							"extensions":{"trust": ` + trust + `}}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		err := verify(`{"enable": true, "cosign": true, "cosignIdentities": [
+			{"subject": "release@example.com", "issuer": "https://accounts.google.com"},
+			{"subjectRegExp": "^https://github.com/example/.*$", "issuer": "https://token.actions.githubusercontent.com"}]}`)
+		So(err, ShouldBeNil)
+
+		// no issuer
+		err = verify(`{"enable": true, "cosign": true, "cosignIdentities": [{"subject": "release@example.com"}]}`)
+		So(err, ShouldNotBeNil)
+
+		// invalid regexp
+		err = verify(`{"enable": true, "cosign": true, "cosignIdentities": [{"subjectRegExp": "(",
+			"issuer": "https://accounts.google.com"}]}`)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test verify cluster members", t, func(c C) {
+		verifyWithHTTP := func(http, cluster string) error {
+			tmpfile, err := os.CreateTemp("", "zot-test*.json")
+			So(err, ShouldBeNil)
+
+			defer os.Remove(tmpfile.Name()) // clean up
+
+			content := []byte(`{"storage":{"rootDirectory":"/tmp/zot"},
+							"http":` + http + `,
+							"cluster": ` + cluster + `}`)
+			_, err = tmpfile.Write(content)
+			So(err, ShouldBeNil)
+			err = tmpfile.Close()
+			So(err, ShouldBeNil)
+
+			os.Args = []string{"cli_test", "verify", tmpfile.Name()}
+
+			return cli.NewServerRootCmd().Execute()
+		}
+
+		verify := func(cluster string) error {
+			return verifyWithHTTP(`{"address":"127.0.0.1","port":"8080"}`, cluster)
+		}
+
+		err := verify(`{"members": ["127.0.0.1:8080", "127.0.0.1:9000"], "hashKey": "loremipsumdolors",
+			"previousMembers": ["127.0.0.1:8080"]}`)
+		So(err, ShouldBeNil)
+
+		// the repositories stored locally can't be moved to their new owner with basic authentication
+		basicAuthHTTP := `{"address":"127.0.0.1","port":"8080","auth":{"htpasswd":{"path":"/tmp/zot-htpasswd"}}}`
+
+		err = verifyWithHTTP(basicAuthHTTP, `{"members": ["127.0.0.1:8080", "127.0.0.1:9000"],
+			"hashKey": "loremipsumdolors", "previousMembers": ["127.0.0.1:8080"]}`)
+		So(err, ShouldNotBeNil)
+
+		err = verifyWithHTTP(basicAuthHTTP, `{"members": ["127.0.0.1:8080", "127.0.0.1:9000"],
+			"hashKey": "loremipsumdolors"}`)
+		So(err, ShouldBeNil)
+
+		err = verify(`{"members": ["127.0.0.1:8080", "127.0.0.1:8080"], "hashKey": "loremipsumdolors"}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"members": ["127.0.0.1:8080"], "hashKey": "loremipsumdolors",
+			"previousMembers": ["127.0.0.1:9000", "127.0.0.1:9000"]}`)
+		So(err, ShouldNotBeNil)
+
+		err = verify(`{"members": ["127.0.0.1:8080"], "hashKey": "loremipsumdolors", "hashing": "rendezvous"}`)
+		So(err, ShouldBeNil)
+
+		err = verify(`{"members": ["127.0.0.1:8080"], "hashKey": "loremipsumdolors", "hashing": "consistent"}`)
+		So(err, ShouldNotBeNil)
+	})
+
 	Convey("Test verify with bad sync prefixes", t, func(c C) {
 		tmpfile, err := os.CreateTemp("", "zot-test*.json")
 		So(err, ShouldBeNil)
@@ -2,6 +2,14 @@ package cluster
 
 import "github.com/dchest/siphash"
 
+// algorithms used to compute the member owning a repository.
+const (
+	// the repository name hash modulo the number of members, the default.
+	ModuloHashing = "modulo"
+	// adding or removing a member only moves the repositories owned by that member.
+	RendezvousHashing = "rendezvous"
+)
+
 // computes the target member using siphash and returns the index and the member
 // siphash was chosen to prevent against hash attacks where an attacker
 // can target all requests to one given instance instead of balancing across the cluster
@@ -15,3 +23,40 @@ func ComputeTargetMember(hashKey string, members []string, repoName string) (uin
 
 	return targetIdx, members[targetIdx]
 }
+
+// computes the target member using rendezvous (highest random weight) hashing
+// and returns the index and the member.
+// each member is scored with a siphash of the member and the repository name and the member
+// with the highest score owns the repository, so adding or removing a member only moves
+// the repositories owned by that member instead of reshuffling most of them.
+// ref: https://en.wikipedia.org/wiki/Rendezvous_hashing
+func ComputeRendezvousTargetMember(hashKey string, members []string, repoName string) (uint64, string) {
+	var targetIdx, maxScore uint64
+
+	for idx, member := range members {
+		h := siphash.New([]byte(hashKey))
+		h.Write([]byte(member))
+		h.Write([]byte{0})
+		h.Write([]byte(repoName))
+
+		if score := h.Sum64(); idx == 0 || score > maxScore {
+			targetIdx, maxScore = uint64(idx), score
+		}
+	}
+
+	return targetIdx, members[targetIdx]
+}
+
+// computes the target member with the given hashing algorithm, modulo hashing is used if none is set.
+func ComputeTargetMemberWithHashing(hashing, hashKey string, members []string, repoName string) (uint64, string) {
+	if hashing == RendezvousHashing {
+		return ComputeRendezvousTargetMember(hashKey, members, repoName)
+	}
+
+	return ComputeTargetMember(hashKey, members, repoName)
+}
+
+// IsValidHashing returns true if hashing is one of the supported algorithms or empty.
+func IsValidHashing(hashing string) bool {
+	return hashing == "" || hashing == ModuloHashing || hashing == RendezvousHashing
+}
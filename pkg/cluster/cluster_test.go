@@ -1,6 +1,7 @@
 package cluster_test
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -22,4 +23,60 @@ func TestComputeTargetMember(t *testing.T) {
 		So(index, ShouldEqual, 1)
 		So(member, ShouldEqual, "member2")
 	})
+	Convey("Rendezvous hashing should only move the repositories owned by an added or removed member", t, func() {
+		members := []string{"member1", "member2", "member3"}
+		newMembers := append([]string{"member4"}, members...)
+
+		moved := 0
+
+		for idx := range 1000 {
+			repo := fmt.Sprintf("repo%d", idx)
+
+			_, member := cluster.ComputeRendezvousTargetMember("loremipsumdolors", members, repo)
+			_, newMember := cluster.ComputeRendezvousTargetMember("loremipsumdolors", newMembers, repo)
+
+			if member != newMember {
+				// repositories only move to the new member
+				So(newMember, ShouldEqual, "member4")
+
+				moved++
+			}
+
+			// the owner doesn't depend on the order of the members
+			_, reordered := cluster.ComputeRendezvousTargetMember("loremipsumdolors",
+				[]string{"member3", "member1", "member2"}, repo)
+			So(reordered, ShouldEqual, member)
+		}
+
+		// about a quarter of the repositories move to the new member
+		So(moved, ShouldBeBetween, 150, 350)
+	})
+
+	Convey("Should use the configured hashing", t, func() {
+		members := []string{"member1", "member2", "member3"}
+
+		for idx := range 100 {
+			repo := fmt.Sprintf("repo%d", idx)
+
+			index, member := cluster.ComputeTargetMemberWithHashing("", "loremipsumdolors", members, repo)
+			moduloIndex, moduloMember := cluster.ComputeTargetMember("loremipsumdolors", members, repo)
+			So(index, ShouldEqual, moduloIndex)
+			So(member, ShouldEqual, moduloMember)
+
+			index, member = cluster.ComputeTargetMemberWithHashing(cluster.ModuloHashing, "loremipsumdolors", members, repo)
+			So(index, ShouldEqual, moduloIndex)
+			So(member, ShouldEqual, moduloMember)
+
+			index, member = cluster.ComputeTargetMemberWithHashing(cluster.RendezvousHashing, "loremipsumdolors",
+				members, repo)
+			rendezvousIndex, rendezvousMember := cluster.ComputeRendezvousTargetMember("loremipsumdolors", members, repo)
+			So(index, ShouldEqual, rendezvousIndex)
+			So(member, ShouldEqual, rendezvousMember)
+		}
+
+		So(cluster.IsValidHashing(""), ShouldBeTrue)
+		So(cluster.IsValidHashing(cluster.ModuloHashing), ShouldBeTrue)
+		So(cluster.IsValidHashing(cluster.RendezvousHashing), ShouldBeTrue)
+		So(cluster.IsValidHashing("consistent"), ShouldBeFalse)
+	})
 }
@@ -23,9 +23,13 @@ const (
 	ClientKeyFilename  = "client.key"
 	CaCertFilename     = "ca.crt"
 
-	CosignSignature   = "cosign"
-	CosignSigKey      = "dev.cosignproject.cosign/signature"
-	NotationSignature = "notation"
+	CosignSignature = "cosign"
+	CosignSigKey    = "dev.cosignproject.cosign/signature"
+	// annotations of keyless cosign signatures
+	CosignCertificateKey = "dev.sigstore.cosign/certificate"
+	CosignChainKey       = "dev.sigstore.cosign/chain"
+	CosignBundleKey      = "dev.sigstore.cosign/bundle"
+	NotationSignature    = "notation"
 	// same value as github.com/notaryproject/notation-go/registry.ArtifactTypeNotation (assert by internal test).
 	// reason used: to reduce zot minimal binary size (otherwise adds oras.land/oras-go/v2 deps).
 	ArtifactTypeNotation = "application/vnd.cncf.notary.signature"
@@ -36,6 +40,8 @@ var cosignSignatureTagRule = regexp.MustCompile(`sha256\-.+\.sig`)
 
 var cosignSBOMTagRule = regexp.MustCompile(`sha256\-.+\.sbom`)
 
+var cosignAttestationTagRule = regexp.MustCompile(`sha256\-.+\.att`)
+
 func IsCosignSignature(tag string) bool {
 	return cosignSignatureTagRule.MatchString(tag)
 }
@@ -44,6 +50,10 @@ func IsCosignSBOM(tag string) bool {
 	return cosignSBOMTagRule.MatchString(tag)
 }
 
+func IsCosignAttestation(tag string) bool {
+	return cosignAttestationTagRule.MatchString(tag)
+}
+
 func IsCosignTag(tag string) bool {
 	return IsCosignSignature(tag) || IsCosignSBOM(tag)
 }
@@ -87,8 +87,9 @@ func AuthzOnlyAdminsMiddleware(conf *config.Config) mux.MiddlewareFunc {
 				return
 			}
 
-			// reject non-admin access if authentication is enabled
-			if userAc != nil && !userAc.IsAdmin() {
+			// reject non-admin access if authentication is enabled,
+			// api keys restricted to some repositories or actions can't be used for admin operations
+			if userAc != nil && (!userAc.IsAdmin() || userAc.IsScoped()) {
 				AuthzFail(response, request, userAc.GetUsername(), conf.HTTP.Realm, conf.HTTP.Auth.FailDelay)
 
 				return
@@ -93,6 +93,11 @@ type ImageVulnerabilitySummary struct {
 	Count         int    `json:"count"`
 }
 
+type SbomSummary struct {
+	Formats      []string `json:"formats"`
+	PackageCount int      `json:"packageCount"`
+}
+
 type LayerSummary struct {
 	Size   string `json:"size"`
 	Digest string `json:"digest"`
@@ -154,6 +159,15 @@ type ImagesForCVEList struct {
 	PaginatedImagesResult `json:"ImageListForCVE"` //nolint:tagliatelle // graphQL schema
 }
 
+type ImagesForPackage struct {
+	Errors               []ErrorGQL `json:"errors"`
+	ImagesForPackageList `json:"data"`
+}
+
+type ImagesForPackageList struct {
+	PaginatedImagesResult `json:"ImageListForPackage"` //nolint:tagliatelle // graphQL schema
+}
+
 type ImagesForDigest struct {
 	Errors              []ErrorGQL `json:"errors"`
 	ImagesForDigestList `json:"data"`
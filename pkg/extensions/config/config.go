@@ -16,6 +16,7 @@ type ExtensionConfig struct {
 	Search  *SearchConfig
 	Sync    *sync.Config
 	Metrics *MetricsConfig
+	Tracing *TracingConfig
 	Scrub   *ScrubConfig
 	Lint    *LintConfig
 	UI      *UIConfig
@@ -29,6 +30,29 @@ type ImageTrustConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	Cosign     bool
 	Notation   bool
+	// identities allowed to sign images keyless, with certificates issued by the uploaded Fulcio roots
+	CosignIdentities []CosignIdentity
+	// only images having a trusted signature are served from the repositories matching a policy
+	Policies []ImageTrustPolicy
+}
+
+// CosignIdentity matches the subject alternative name and the OIDC issuer of keyless signing certificates,
+// either exactly or with a regular expression.
+type CosignIdentity struct {
+	Subject       string
+	SubjectRegExp string
+	Issuer        string
+	IssuerRegExp  string
+}
+
+type ImageTrustPolicy struct {
+	Repositories []string // glob patterns
+	// signature types which are accepted, "cosign" and/or "notation", both are accepted if empty
+	SignatureTypes []string
+	// also deny pushing tags which point to manifests not having a trusted signature
+	EnforceOnPush bool
+	// users and groups allowed to pull manifests not having a trusted signature, so that they can sign them
+	Signers []string
 }
 
 type APIKeyConfig struct {
@@ -42,6 +66,46 @@ type MgmtConfig struct {
 type LintConfig struct {
 	BaseConfig           `mapstructure:",squash"`
 	MandatoryAnnotations []string
+	// additional checks of the pushed images and indexes
+	Rules []LintRule
+	// validating webhooks called before a manifest is pushed, any of them can deny the push
+	AdmissionWebhooks []AdmissionWebhookConfig
+}
+
+const (
+	LintRuleMaxLayers             = "maxLayers"
+	LintRuleMaxSize               = "maxSize"
+	LintRuleRequiredArchitectures = "requiredArchitectures"
+	LintRuleNoRootUser            = "noRootUser"
+	LintRuleBannedMediaTypes      = "bannedMediaTypes"
+	LintRuleRequiredLabels        = "requiredLabels"
+	LintRuleForbiddenBaseImages   = "forbiddenBaseImages"
+)
+
+type LintRule struct {
+	Type string
+	// glob patterns of the repositories checked by the rule, all repositories are checked if empty
+	Repositories  []string
+	MaxLayers     int      // maxLayers
+	MaxSize       int64    // maxSize, bytes of the config and layers
+	Architectures []string // requiredArchitectures, which image indexes must provide
+	MediaTypes    []string // bannedMediaTypes, of the config, layers, artifact type or index manifests
+	Labels        []string // requiredLabels, keys of the image config labels
+	// forbiddenBaseImages, glob patterns matched against the base image name annotation
+	// and against the repo:tag of the hosted images whose layers are all layers of the pushed image
+	BaseImages []string
+}
+
+type AdmissionWebhookConfig struct {
+	Name string
+	URL  string
+	// glob patterns of the repositories checked by the webhook, all repositories are checked if empty
+	Repositories []string
+	Timeout      time.Duration // default is 10s
+	// allow the push if the webhook can't be reached or doesn't return a valid review, the push is denied otherwise
+	FailOpen bool
+	// CA certificate used to verify the webhook server, the system certificates are used if empty
+	CACertFile string
 }
 
 type SearchConfig struct {
@@ -58,8 +122,20 @@ type CVEConfig struct {
 type TrivyConfig struct {
 	DBRepository     string // default is "ghcr.io/aquasecurity/trivy-db"
 	JavaDBRepository string // default is "ghcr.io/aquasecurity/trivy-java-db"
+	// scan the SPDX/CycloneDX sboms attached to images instead of their layers,
+	// one of "ignore", "prefer" or "require", default is "ignore"
+	SbomScanMode string
 }
 
+const (
+	// always scan the image layers.
+	SbomScanModeIgnore = "ignore"
+	// scan the sboms attached to the image if there are any, the image layers otherwise.
+	SbomScanModePrefer = "prefer"
+	// only scan the sboms attached to the image, images without sboms are not scanned.
+	SbomScanModeRequire = "require"
+)
+
 type MetricsConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	Prometheus *PrometheusConfig
@@ -69,6 +145,13 @@ type PrometheusConfig struct {
 	Path string // default is "/metrics"
 }
 
+type TracingConfig struct {
+	BaseConfig `mapstructure:",squash"`
+	Endpoint   string   // URL of the OTLP/HTTP collector, eg: "http://localhost:4318", default path is "/v1/traces"
+	Service    string   // service name reported in spans, default is "zot"
+	SampleRate *float64 // ratio of traces which are sampled, default is 1
+}
+
 type ScrubConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	Interval   time.Duration
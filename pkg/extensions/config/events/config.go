@@ -11,14 +11,20 @@ func (s SinkType) String() string {
 }
 
 const (
-	HTTP SinkType = "http"
-	NATS SinkType = "nats"
+	HTTP  SinkType = "http"
+	NATS  SinkType = "nats"
+	Kafka SinkType = "kafka"
+	AMQP  SinkType = "amqp"
+	Redis SinkType = "redis"
 )
 
 func IsSupportedSink(sinkType SinkType) bool {
 	supportedSinks := map[SinkType]struct{}{
-		HTTP: {},
-		NATS: {},
+		HTTP:  {},
+		NATS:  {},
+		Kafka: {},
+		AMQP:  {},
+		Redis: {},
 	}
 
 	_, ok := supportedSinks[sinkType]
@@ -26,10 +32,39 @@ func IsSupportedSink(sinkType SinkType) bool {
 	return ok
 }
 
+type Delivery string
+
+const (
+	// events are sent once, failures are only logged.
+	AtMostOnce Delivery = "at-most-once"
+	// sending is retried until the sink acknowledges the event or the retries are exhausted.
+	AtLeastOnce Delivery = "at-least-once"
+)
+
+func IsSupportedDelivery(delivery Delivery) bool {
+	return delivery == "" || delivery == AtMostOnce || delivery == AtLeastOnce
+}
+
 // Config holds configuration for the events extension.
 type Config struct {
 	Enable *bool
 	Sinks  []SinkConfig
+	Outbox *OutboxConfig
+}
+
+// OutboxConfig holds configuration for persisting events until they are delivered,
+// next to the metadb (in the root directory, or in redis if it's used as remote cache).
+type OutboxConfig struct {
+	Enable *bool
+	// number of delivery attempts after which an event is dead-lettered
+	MaxAttempts int
+	// delay before the first retry, doubled after each failed attempt up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// how often the outbox is checked for events due for delivery
+	Interval time.Duration
+	// how long dead-lettered events are kept for replay before they are removed
+	DeadLetterRetention time.Duration
 }
 
 type SinkConfig struct {
@@ -41,6 +76,17 @@ type SinkConfig struct {
 	Timeout time.Duration
 	Proxy   *string
 	Headers map[string]string
+	// delivery guarantee of the kafka, amqp and redis sinks, at-most-once if empty
+	Delivery Delivery
+	// number of times sending an event is retried with at-least-once delivery
+	MaxRetries int
+	// delay between retries
+	RetryDelay time.Duration
+	// maximum length of the redis stream, the oldest entries being trimmed, unbounded if negative
+	MaxLen int64
+	// glob patterns of the event types and repositories sent to the sink, all events are sent if empty
+	EventTypes   []string
+	Repositories []string
 }
 
 type Credentials struct {
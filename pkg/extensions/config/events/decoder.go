@@ -2,7 +2,9 @@ package events
 
 import (
 	"reflect"
+	"slices"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/mitchellh/mapstructure"
 
 	zerr "zotregistry.dev/zot/errors"
@@ -47,6 +49,16 @@ func SinkConfigDecoderHook() mapstructure.DecodeHookFunc {
 			return nil, zerr.ErrUnsupportedEventSink
 		}
 
+		if !IsSupportedDelivery(config.Delivery) {
+			return nil, zerr.ErrUnsupportedEventDelivery
+		}
+
+		for _, pattern := range append(slices.Clone(config.EventTypes), config.Repositories...) {
+			if !glob.ValidatePattern(pattern) {
+				return nil, zerr.ErrInvalidEventSinkFilter
+			}
+		}
+
 		return config, nil
 	}
 }
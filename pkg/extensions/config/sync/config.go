@@ -20,6 +20,27 @@ type Config struct {
 	and then move them into storage. */
 	DownloadDir string
 	Registries  []RegistryConfig
+	ProxyCache  *ProxyCacheConfig
+}
+
+// ProxyCacheConfig enables pulling through arbitrary upstreams without declaring them as registries,
+// a pull of <Prefix>/<upstream host>/<repo> is synced on demand from <upstream host>/<repo>.
+type ProxyCacheConfig struct {
+	Enable *bool
+	Prefix string // local namespace of cached repos, defaults to "cache"
+	// how long a synced tag is served without revalidating it against the upstream, defaults to 1h
+	TagTTL    time.Duration
+	Upstreams []ProxyCacheUpstream // allowed upstreams and their settings, pulls from other hosts are rejected
+}
+
+type ProxyCacheUpstream struct {
+	Host       string // glob pattern matched against the upstream host, eg: "docker.io", "*.gcr.io"
+	URL        string // defaults to https://<host>
+	TagTTL     *time.Duration
+	TLSVerify  *bool
+	CertDir    string
+	MaxRetries *int
+	RetryDelay *time.Duration
 }
 
 type RegistryConfig struct {
@@ -0,0 +1,202 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+)
+
+// prefix of the message headers holding the CloudEvents attributes.
+const amqpHeaderPrefix = "cloudEvents:"
+
+// AMQPSink implements a CloudEvents sink that publishes to an AMQP 0-9-1 (RabbitMQ) exchange,
+// the channel being the exchange name and the event type the routing key.
+type AMQPSink struct {
+	config eventsconf.SinkConfig
+	lock   sync.Mutex
+	conn   *amqp.Connection
+	ch     *amqp.Channel
+}
+
+// NewAMQPSink creates a new AMQP sink and connects to the broker.
+func NewAMQPSink(config eventsconf.SinkConfig) (*AMQPSink, error) {
+	if config.Type != eventsconf.AMQP {
+		return nil, zerr.ErrInvalidEventSinkType
+	}
+
+	if config.Address == "" {
+		return nil, zerr.ErrEventSinkAddressEmpty
+	}
+
+	sink := &AMQPSink{config: config}
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	if err := sink.connect(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+// connect opens the connection and the channel, with publisher confirms if at-least-once delivery is configured.
+func (s *AMQPSink) connect() error {
+	amqpConfig := amqp.Config{
+		Properties: amqp.Table{"connection_name": EventSource},
+		Dial:       amqp.DefaultDial(getSinkTimeout(s.config)),
+	}
+
+	if s.config.Credentials != nil && s.config.Credentials.Username != "" {
+		amqpConfig.SASL = []amqp.Authentication{&amqp.PlainAuth{
+			Username: s.config.Credentials.Username,
+			Password: s.config.Credentials.Password,
+		}}
+	}
+
+	if s.config.TLSConfig != nil && (s.config.TLSConfig.CACertFile != "" || s.config.TLSConfig.CertFile != "") {
+		tlsConfig, err := getTLSConfig(s.config)
+		if err != nil {
+			return err
+		}
+
+		amqpConfig.TLSClientConfig = tlsConfig
+	}
+
+	conn, err := amqp.DialConfig(s.config.Address, amqpConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+
+	if s.config.Delivery == eventsconf.AtLeastOnce {
+		if err := ch.Confirm(false); err != nil {
+			_ = conn.Close()
+
+			return fmt.Errorf("failed to enable AMQP publisher confirms: %w", err)
+		}
+	}
+
+	s.conn = conn
+	s.ch = ch
+
+	return nil
+}
+
+func (s *AMQPSink) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+
+	s.conn = nil
+	s.ch = nil
+}
+
+// Emit sends a CloudEvent to the AMQP exchange, reconnecting if the connection was lost.
+func (s *AMQPSink) Emit(event *cloudevents.Event) cloudevents.Result {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	msg := newAMQPPublishing(event)
+
+	if s.config.Delivery == eventsconf.AtLeastOnce {
+		msg.DeliveryMode = amqp.Persistent
+	}
+
+	return sendWithRetries(s.config, func(ctx context.Context) error {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.conn == nil || s.conn.IsClosed() {
+			s.disconnect()
+
+			if err := s.connect(); err != nil {
+				return err
+			}
+		}
+
+		confirmation, err := s.ch.PublishWithDeferredConfirmWithContext(ctx, s.config.Channel, event.Type(),
+			false, false, msg)
+		if err != nil {
+			s.disconnect()
+
+			return err
+		}
+
+		// nil unless publisher confirms are enabled
+		if confirmation == nil {
+			return nil
+		}
+
+		acked, err := confirmation.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !acked {
+			return fmt.Errorf("%w: %s", zerr.ErrEventNotAcknowledged, event.ID())
+		}
+
+		return nil
+	})
+}
+
+// newAMQPPublishing maps the event to a message in binary content mode:
+// the data is the message body and the attributes are message headers.
+func newAMQPPublishing(event *cloudevents.Event) amqp.Publishing {
+	headers := amqp.Table{
+		amqpHeaderPrefix + "specversion": event.SpecVersion(),
+		amqpHeaderPrefix + "id":          event.ID(),
+		amqpHeaderPrefix + "source":      event.Source(),
+		amqpHeaderPrefix + "type":        event.Type(),
+	}
+
+	if !event.Time().IsZero() {
+		headers[amqpHeaderPrefix+"time"] = event.Time().UTC().Format(time.RFC3339Nano)
+	}
+
+	if event.Subject() != "" {
+		headers[amqpHeaderPrefix+"subject"] = event.Subject()
+	}
+
+	for name, value := range event.Extensions() {
+		headers[amqpHeaderPrefix+name] = fmt.Sprint(value)
+	}
+
+	return amqp.Publishing{
+		Headers:     headers,
+		ContentType: event.DataContentType(),
+		MessageId:   event.ID(),
+		Timestamp:   event.Time(),
+		Type:        event.Type(),
+		AppId:       EventSource,
+		Body:        event.Data(),
+	}
+}
+
+// Close closes the AMQP connection.
+func (s *AMQPSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.disconnect()
+
+	return nil
+}
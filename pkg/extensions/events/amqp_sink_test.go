@@ -0,0 +1,479 @@
+//go:build events
+// +build events
+
+package events_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+func TestAMQPSink(t *testing.T) {
+	Convey("NewAMQPSink returns error for invalid type", t, func() {
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{Type: "invalid", Address: "amqp://localhost"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrInvalidEventSinkType)
+	})
+
+	Convey("NewAMQPSink returns error for empty address", t, func() {
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{Type: eventsconf.AMQP})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrEventSinkAddressEmpty)
+	})
+
+	Convey("NewAMQPSink fails if the broker is not reachable", t, func() {
+		broker := newFakeAMQPBroker(t)
+		address := broker.url()
+		broker.close()
+
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{
+			Type:    eventsconf.AMQP,
+			Address: address,
+			Timeout: time.Second,
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("NewAMQPSink fails with invalid TLS config", t, func() {
+		broker := newFakeAMQPBroker(t)
+		defer broker.close()
+
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{
+			Type:    eventsconf.AMQP,
+			Address: broker.url(),
+			TLSConfig: &eventsconf.TLSConfig{
+				CACertFile: "invalid",
+			},
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("AMQPSink publishes events to the exchange", t, func() {
+		broker := newFakeAMQPBroker(t)
+		defer broker.close()
+
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{
+			Type:    eventsconf.AMQP,
+			Address: broker.url(),
+			Channel: "zot-events",
+			Timeout: time.Second,
+			Credentials: &eventsconf.Credentials{
+				Username: "user",
+				Password: "pass",
+			},
+		})
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		So(broker.getAuthResponse(), ShouldEqual, "\x00user\x00pass")
+
+		recorder, err := events.NewRecorder(log.NewLogger("debug", ""), sink)
+		So(err, ShouldBeNil)
+
+		recorder.RepositoryCreated("alpine")
+
+		msg := broker.waitForMessage(t)
+		So(msg.exchange, ShouldEqual, "zot-events")
+		So(msg.routingKey, ShouldEqual, events.RepositoryCreatedEventType.String())
+		So(string(msg.body), ShouldEqual, `{"name":"alpine"}`)
+		So(string(msg.header), ShouldContainSubstring, cloudevents.ApplicationJSON)
+		So(string(msg.header), ShouldContainSubstring, "cloudEvents:type")
+		So(string(msg.header), ShouldContainSubstring, events.RepositoryCreatedEventType.String())
+		So(string(msg.header), ShouldContainSubstring, "cloudEvents:source")
+		So(broker.isConfirmMode(), ShouldBeFalse)
+	})
+
+	Convey("AMQPSink retries until the broker confirms with at-least-once delivery", t, func() {
+		broker := newFakeAMQPBroker(t)
+		defer broker.close()
+
+		config := eventsconf.SinkConfig{
+			Type:       eventsconf.AMQP,
+			Address:    broker.url(),
+			Channel:    "zot-events",
+			Timeout:    time.Second,
+			Delivery:   eventsconf.AtLeastOnce,
+			MaxRetries: 3,
+			RetryDelay: 10 * time.Millisecond,
+		}
+
+		sink, err := events.NewAMQPSink(config)
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		So(broker.isConfirmMode(), ShouldBeTrue)
+
+		broker.setNacks(2)
+
+		So(sink.Emit(newTestEvent()), ShouldBeNil)
+		So(broker.getMessageCount(), ShouldEqual, 3)
+
+		broker.setNacks(4)
+
+		err = sink.Emit(newTestEvent())
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, zerr.ErrEventNotAcknowledged.Error())
+		So(broker.getMessageCount(), ShouldEqual, 7)
+	})
+
+	Convey("AMQPSink reconnects if the connection was lost", t, func() {
+		broker := newFakeAMQPBroker(t)
+		defer broker.close()
+
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{
+			Type:       eventsconf.AMQP,
+			Address:    broker.url(),
+			Channel:    "zot-events",
+			Timeout:    time.Second,
+			Delivery:   eventsconf.AtLeastOnce,
+			MaxRetries: 3,
+			RetryDelay: 10 * time.Millisecond,
+		})
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		broker.dropConnections()
+
+		So(sink.Emit(newTestEvent()), ShouldBeNil)
+		So(broker.getConnectionCount(), ShouldEqual, 2)
+	})
+
+	Convey("AMQPSink.Emit returns error for invalid event", t, func() {
+		broker := newFakeAMQPBroker(t)
+		defer broker.close()
+
+		sink, err := events.NewAMQPSink(eventsconf.SinkConfig{
+			Type:    eventsconf.AMQP,
+			Address: broker.url(),
+		})
+		So(err, ShouldBeNil)
+
+		event := cloudevents.NewEvent()
+		So(sink.Emit(&event), ShouldNotBeNil)
+		So(sink.Close(), ShouldBeNil)
+	})
+}
+
+const (
+	amqpFrameMethod    = 1
+	amqpFrameHeader    = 2
+	amqpFrameBody      = 3
+	amqpFrameEnd       = 0xCE
+	amqpConnectionCls  = 10
+	amqpChannelCls     = 20
+	amqpBasicCls       = 60
+	amqpConfirmCls     = 85
+	amqpMaxFrameLength = 131072
+)
+
+type amqpMessage struct {
+	exchange   string
+	routingKey string
+	header     []byte
+	body       []byte
+}
+
+// fakeAMQPBroker implements just enough of AMQP 0-9-1 for publishing:
+// the connection handshake, channels, publisher confirms and basic.publish.
+type fakeAMQPBroker struct {
+	listener     net.Listener
+	lock         sync.Mutex
+	conns        []net.Conn
+	connCount    int
+	authResponse string
+	confirmMode  bool
+	nacks        int
+	messages     chan amqpMessage
+	messageCount int
+}
+
+func newFakeAMQPBroker(t *testing.T) *fakeAMQPBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := &fakeAMQPBroker{listener: listener, messages: make(chan amqpMessage, 100)}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			broker.lock.Lock()
+			broker.conns = append(broker.conns, conn)
+			broker.connCount++
+			broker.lock.Unlock()
+
+			go broker.serve(conn)
+		}
+	}()
+
+	return broker
+}
+
+func (b *fakeAMQPBroker) url() string {
+	return "amqp://" + b.listener.Addr().String() + "/"
+}
+
+func (b *fakeAMQPBroker) close() {
+	b.listener.Close()
+	b.dropConnections()
+}
+
+func (b *fakeAMQPBroker) dropConnections() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+
+	b.conns = nil
+}
+
+func (b *fakeAMQPBroker) setNacks(nacks int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.nacks = nacks
+}
+
+func (b *fakeAMQPBroker) getMessageCount() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.messageCount
+}
+
+func (b *fakeAMQPBroker) getConnectionCount() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.connCount
+}
+
+func (b *fakeAMQPBroker) getAuthResponse() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.authResponse
+}
+
+func (b *fakeAMQPBroker) isConfirmMode() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.confirmMode
+}
+
+func (b *fakeAMQPBroker) waitForMessage(t *testing.T) amqpMessage {
+	t.Helper()
+
+	select {
+	case msg := <-b.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for amqp message")
+	}
+
+	return amqpMessage{}
+}
+
+func (b *fakeAMQPBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	protocolHeader := make([]byte, 8)
+	if _, err := io.ReadFull(reader, protocolHeader); err != nil {
+		return
+	}
+
+	// connection.start: version 0-9, no server properties, PLAIN mechanism, en_US locale
+	start := &bytes.Buffer{}
+	start.Write([]byte{0, 9})
+	writeLongStr(start, "")
+	writeLongStr(start, "PLAIN")
+	writeLongStr(start, "en_US")
+	writeMethod(conn, 0, amqpConnectionCls, 10, start.Bytes())
+
+	var (
+		deliveryTag uint64
+		publishing  *amqpMessage
+		bodySize    uint64
+	)
+
+	for {
+		frameType, channel, payload, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+
+		switch frameType {
+		case amqpFrameHeader:
+			if publishing == nil {
+				return
+			}
+
+			publishing.header = payload
+			bodySize = binary.BigEndian.Uint64(payload[4:12])
+
+			if bodySize > 0 {
+				continue
+			}
+		case amqpFrameBody:
+			if publishing == nil {
+				return
+			}
+
+			publishing.body = append(publishing.body, payload...)
+
+			if uint64(len(publishing.body)) < bodySize {
+				continue
+			}
+		case amqpFrameMethod:
+			class, method := binary.BigEndian.Uint16(payload[0:2]), binary.BigEndian.Uint16(payload[2:4])
+			args := payload[4:]
+
+			switch {
+			case class == amqpConnectionCls && method == 11: // start-ok
+				b.lock.Lock()
+				b.authResponse = parseStartOkResponse(args)
+				b.lock.Unlock()
+
+				tune := &bytes.Buffer{}
+				_ = binary.Write(tune, binary.BigEndian, uint16(0))
+				_ = binary.Write(tune, binary.BigEndian, uint32(amqpMaxFrameLength))
+				_ = binary.Write(tune, binary.BigEndian, uint16(0))
+				writeMethod(conn, 0, amqpConnectionCls, 30, tune.Bytes())
+			case class == amqpConnectionCls && method == 40: // open
+				writeMethod(conn, 0, amqpConnectionCls, 41, []byte{0})
+			case class == amqpConnectionCls && method == 50: // close
+				writeMethod(conn, 0, amqpConnectionCls, 51, nil)
+
+				return
+			case class == amqpChannelCls && method == 10: // channel.open
+				writeMethod(conn, channel, amqpChannelCls, 11, []byte{0, 0, 0, 0})
+			case class == amqpChannelCls && method == 40: // channel.close
+				writeMethod(conn, channel, amqpChannelCls, 41, nil)
+			case class == amqpConfirmCls && method == 10: // confirm.select
+				b.lock.Lock()
+				b.confirmMode = true
+				b.lock.Unlock()
+
+				writeMethod(conn, channel, amqpConfirmCls, 11, nil)
+			case class == amqpBasicCls && method == 40: // basic.publish
+				exchange, rest := readShortStr(args[2:])
+				routingKey, _ := readShortStr(rest)
+				publishing = &amqpMessage{exchange: exchange, routingKey: routingKey}
+			}
+
+			continue
+		default:
+			continue
+		}
+
+		// the message is complete
+		deliveryTag++
+
+		b.lock.Lock()
+		b.messageCount++
+		nack := b.nacks > 0
+
+		if nack {
+			b.nacks--
+		}
+
+		confirmMode := b.confirmMode
+		b.lock.Unlock()
+
+		b.messages <- *publishing
+		publishing = nil
+
+		if confirmMode {
+			confirm := &bytes.Buffer{}
+			_ = binary.Write(confirm, binary.BigEndian, deliveryTag)
+			confirm.WriteByte(0)
+
+			if nack {
+				writeMethod(conn, channel, amqpBasicCls, 120, confirm.Bytes())
+			} else {
+				writeMethod(conn, channel, amqpBasicCls, 80, confirm.Bytes())
+			}
+		}
+	}
+}
+
+func readFrame(reader *bufio.Reader) (byte, uint16, []byte, error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, 0, nil, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[3:7])+1)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, 0, nil, err
+	}
+
+	return header[0], binary.BigEndian.Uint16(header[1:3]), payload[:len(payload)-1], nil
+}
+
+func writeMethod(conn net.Conn, channel uint16, class, method uint16, args []byte) {
+	payload := &bytes.Buffer{}
+	_ = binary.Write(payload, binary.BigEndian, class)
+	_ = binary.Write(payload, binary.BigEndian, method)
+	payload.Write(args)
+
+	frame := &bytes.Buffer{}
+	frame.WriteByte(amqpFrameMethod)
+	_ = binary.Write(frame, binary.BigEndian, channel)
+	_ = binary.Write(frame, binary.BigEndian, uint32(payload.Len())) //nolint:gosec
+	frame.Write(payload.Bytes())
+	frame.WriteByte(amqpFrameEnd)
+
+	_, _ = conn.Write(frame.Bytes())
+}
+
+func writeLongStr(buf *bytes.Buffer, value string) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(value))) //nolint:gosec
+	buf.WriteString(value)
+}
+
+func readShortStr(data []byte) (string, []byte) {
+	length := int(data[0])
+
+	return string(data[1 : 1+length]), data[1+length:]
+}
+
+// parseStartOkResponse skips the client properties table and the mechanism and returns the SASL response.
+func parseStartOkResponse(args []byte) string {
+	tableLength := binary.BigEndian.Uint32(args[0:4])
+	_, rest := readShortStr(args[4+tableLength:])
+	responseLength := binary.BigEndian.Uint32(rest[0:4])
+
+	return string(rest[4 : 4+responseLength])
+}
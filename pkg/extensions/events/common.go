@@ -12,10 +12,19 @@ const (
 type EventType string
 
 const (
-	ImageUpdatedEventType      EventType = "zotregistry.image.updated"
-	ImageDeletedEventType      EventType = "zotregistry.image.deleted"
-	ImageLintFailedEventType   EventType = "zotregistry.image.lint_failed"
-	RepositoryCreatedEventType EventType = "zotregistry.repository.created"
+	ImageUpdatedEventType          EventType = "zotregistry.image.updated"
+	ImageDeletedEventType          EventType = "zotregistry.image.deleted"
+	ImageLintFailedEventType       EventType = "zotregistry.image.lint_failed"
+	ImagePulledEventType           EventType = "zotregistry.image.pulled"
+	ImageGarbageCollectedEventType EventType = "zotregistry.image.garbage_collected"
+	RepositoryCreatedEventType     EventType = "zotregistry.repository.created"
+	BlobPushedEventType            EventType = "zotregistry.blob.pushed"
+	SignatureAddedEventType        EventType = "zotregistry.signature.added"
+	SignatureDeletedEventType      EventType = "zotregistry.signature.deleted"
+	ReferrerAddedEventType         EventType = "zotregistry.referrer.added"
+	SyncCompletedEventType         EventType = "zotregistry.sync.completed"
+	SyncFailedEventType            EventType = "zotregistry.sync.failed"
+	CVEScanCompletedEventType      EventType = "zotregistry.cve.scan_completed"
 )
 
 func (e EventType) String() string {
@@ -28,5 +37,19 @@ type Recorder interface {
 	RepositoryCreated(name string)
 	ImageUpdated(name, reference, digest, mediaType, manifest string)
 	ImageDeleted(name, reference, digest, mediaType string)
-	ImageLintFailed(name, reference, digest, mediaType, manifest string)
+	// ImageLintFailed is recorded for the manifests rejected by the linter, lintErr being the failed check.
+	ImageLintFailed(name, reference, digest, mediaType, manifest string, lintErr error)
+	ImagePulled(name, reference, digest, mediaType string)
+	// ImageGarbageCollected is recorded for the manifests removed by gc, rule is the retention
+	// or gc setting which caused the removal.
+	ImageGarbageCollected(name, reference, digest, mediaType, rule string)
+	BlobPushed(name, digest string, size int64)
+	SignatureAdded(name, subjectDigest, signatureDigest, signatureType string)
+	SignatureDeleted(name, subjectDigest, signatureDigest, signatureType string)
+	ReferrerAdded(name, subjectDigest, referrerDigest, artifactType string)
+	// SyncCompleted is recorded when a sync task succeeds, reference is empty for periodically synced repos.
+	SyncCompleted(name, reference string)
+	SyncFailed(name, reference string, err error)
+	// CVEScanCompleted is recorded with the number of vulnerabilities per severity found in the image.
+	CVEScanCompleted(name, digest, maxSeverity string, severityCounts map[string]int)
 }
@@ -0,0 +1,64 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"time"
+
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+)
+
+const (
+	DefaultSinkTimeout = 30 * time.Second
+	DefaultRetryDelay  = time.Second
+)
+
+func getSinkTimeout(config eventsconf.SinkConfig) time.Duration {
+	if config.Timeout == 0 {
+		return DefaultSinkTimeout
+	}
+
+	return config.Timeout
+}
+
+func getRetryDelay(config eventsconf.SinkConfig) time.Duration {
+	if config.RetryDelay == 0 {
+		return DefaultRetryDelay
+	}
+
+	return config.RetryDelay
+}
+
+func getMaxRetries(config eventsconf.SinkConfig) int {
+	if config.Delivery != eventsconf.AtLeastOnce || config.MaxRetries < 0 {
+		return 0
+	}
+
+	return config.MaxRetries
+}
+
+// sendWithRetries calls send, each attempt having its own timeout, until it succeeds or,
+// with at-least-once delivery, until the retries are exhausted.
+func sendWithRetries(config eventsconf.SinkConfig, send func(ctx context.Context) error) error {
+	var err error
+
+	for attempt := 0; attempt <= getMaxRetries(config); attempt++ {
+		if attempt > 0 {
+			time.Sleep(getRetryDelay(config))
+		}
+
+		err = func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), getSinkTimeout(config))
+			defer cancel()
+
+			return send(ctx)
+		}()
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
@@ -11,6 +11,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	zerr "zotregistry.dev/zot/errors"
 	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
 	"zotregistry.dev/zot/pkg/log"
 )
@@ -29,6 +30,25 @@ func (r eventRecorder) Close() {
 	}
 }
 
+type outboxRecorder struct {
+	eventRecorder
+	*Outbox
+}
+
+var (
+	_ Recorder        = (*outboxRecorder)(nil)
+	_ DeadLetterQueue = (*outboxRecorder)(nil)
+)
+
+// Close stops the outbox before closing the sinks, the events which were not delivered yet are kept in the outbox.
+func (r outboxRecorder) Close() {
+	if err := r.Outbox.Close(); err != nil {
+		r.eventRecorder.log.Error().Err(err).Msg("failed to close event outbox")
+	}
+
+	r.eventRecorder.Close()
+}
+
 func (r eventRecorder) closeSinks() error {
 	var retErr error
 
@@ -102,14 +122,174 @@ func (r eventRecorder) ImageDeleted(name, reference, digest, mediaType string) {
 	r.publish(event)
 }
 
-func (r eventRecorder) ImageLintFailed(name, reference, digest, mediaType, manifest string) {
-	event, err := newEventBuilder().
+func (r eventRecorder) ImageLintFailed(name, reference, digest, mediaType, manifest string, lintErr error) {
+	builder := newEventBuilder().
 		WithEventType(ImageLintFailedEventType).
 		WithDataField("name", name).
 		WithDataField("reference", reference).
 		WithDataField("digest", digest).
 		WithDataField("mediaType", mediaType).
-		WithDataField("manifest", manifest).
+		WithDataField("manifest", manifest)
+
+	if lintErr != nil {
+		builder = builder.WithDataField("error", lintErr.Error())
+
+		// the failed rule and the values it checked
+		if details := zerr.GetDetails(lintErr); len(details) > 0 {
+			builder = builder.WithDataField("details", details)
+		}
+	}
+
+	event, err := builder.Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) ImagePulled(name, reference, digest, mediaType string) {
+	event, err := newEventBuilder().
+		WithEventType(ImagePulledEventType).
+		WithDataField("name", name).
+		WithDataField("reference", reference).
+		WithDataField("digest", digest).
+		WithDataField("mediaType", mediaType).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) ImageGarbageCollected(name, reference, digest, mediaType, rule string) {
+	event, err := newEventBuilder().
+		WithEventType(ImageGarbageCollectedEventType).
+		WithDataField("name", name).
+		WithDataField("reference", reference).
+		WithDataField("digest", digest).
+		WithDataField("mediaType", mediaType).
+		WithDataField("rule", rule).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) BlobPushed(name, digest string, size int64) {
+	event, err := newEventBuilder().
+		WithEventType(BlobPushedEventType).
+		WithDataField("name", name).
+		WithDataField("digest", digest).
+		WithDataField("size", size).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) SignatureAdded(name, subjectDigest, signatureDigest, signatureType string) {
+	r.signatureEvent(SignatureAddedEventType, name, subjectDigest, signatureDigest, signatureType)
+}
+
+func (r eventRecorder) SignatureDeleted(name, subjectDigest, signatureDigest, signatureType string) {
+	r.signatureEvent(SignatureDeletedEventType, name, subjectDigest, signatureDigest, signatureType)
+}
+
+func (r eventRecorder) signatureEvent(eventType EventType, name, subjectDigest, signatureDigest,
+	signatureType string,
+) {
+	event, err := newEventBuilder().
+		WithEventType(eventType).
+		WithDataField("name", name).
+		WithDataField("subjectDigest", subjectDigest).
+		WithDataField("signatureDigest", signatureDigest).
+		WithDataField("signatureType", signatureType).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) ReferrerAdded(name, subjectDigest, referrerDigest, artifactType string) {
+	event, err := newEventBuilder().
+		WithEventType(ReferrerAddedEventType).
+		WithDataField("name", name).
+		WithDataField("subjectDigest", subjectDigest).
+		WithDataField("referrerDigest", referrerDigest).
+		WithDataField("artifactType", artifactType).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) SyncCompleted(name, reference string) {
+	event, err := newEventBuilder().
+		WithEventType(SyncCompletedEventType).
+		WithDataField("name", name).
+		WithDataField("reference", reference).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) SyncFailed(name, reference string, syncErr error) {
+	event, err := newEventBuilder().
+		WithEventType(SyncFailedEventType).
+		WithDataField("name", name).
+		WithDataField("reference", reference).
+		WithDataField("error", syncErr.Error()).
+		Build()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("failed to create event")
+
+		return
+	}
+
+	r.publish(event)
+}
+
+func (r eventRecorder) CVEScanCompleted(name, digest, maxSeverity string, severityCounts map[string]int) {
+	count := 0
+
+	for _, severityCount := range severityCounts {
+		count += severityCount
+	}
+
+	event, err := newEventBuilder().
+		WithEventType(CVEScanCompletedEventType).
+		WithDataField("name", name).
+		WithDataField("digest", digest).
+		WithDataField("maxSeverity", maxSeverity).
+		WithDataField("count", count).
+		WithDataField("severityCounts", severityCounts).
 		Build()
 	if err != nil {
 		r.log.Warn().Err(err).Msg("failed to create event")
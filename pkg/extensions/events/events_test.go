@@ -15,6 +15,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 	"k8s.io/apimachinery/pkg/util/rand"
 
@@ -75,9 +76,125 @@ func TestEvents(t *testing.T) {
 			So(ev.Type(), ShouldEqual, events.ImageDeletedEventType.String())
 		})
 		Convey("image lint failed", func() {
-			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "")
+			lintErr := zerr.NewError(zerr.ErrImageLintMaxLayers).AddDetail("rule", "maxLayers")
+			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "", lintErr)
 			ev := <-sink.store
 			So(ev.Type(), ShouldEqual, events.ImageLintFailedEventType.String())
+
+			data := map[string]any{}
+			So(ev.DataAs(&data), ShouldBeNil)
+			So(data["error"], ShouldEqual, zerr.ErrImageLintMaxLayers.Error())
+			So(data["details"], ShouldResemble, map[string]any{"rule": "maxLayers"})
+		})
+		Convey("image pulled", func() {
+			recorder.ImagePulled("test", "v1", "", string(types.OCIManifestSchema1))
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.ImagePulledEventType.String())
+		})
+		Convey("image garbage collected", func() {
+			recorder.ImageGarbageCollected("test", "v1", "", string(types.OCIManifestSchema1), "keepTags")
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.ImageGarbageCollectedEventType.String())
+
+			data := map[string]any{}
+			So(ev.DataAs(&data), ShouldBeNil)
+			So(data["rule"], ShouldEqual, "keepTags")
+		})
+		Convey("blob pushed", func() {
+			recorder.BlobPushed("test", "sha256:abc", 10)
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.BlobPushedEventType.String())
+		})
+		Convey("signature added and deleted", func() {
+			recorder.SignatureAdded("test", "sha256:abc", "sha256:def", "cosign")
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.SignatureAddedEventType.String())
+
+			recorder.SignatureDeleted("test", "sha256:abc", "sha256:def", "cosign")
+			ev = <-sink.store
+			So(ev.Type(), ShouldEqual, events.SignatureDeletedEventType.String())
+		})
+		Convey("referrer added", func() {
+			recorder.ReferrerAdded("test", "sha256:abc", "sha256:def", "application/spdx+json")
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.ReferrerAddedEventType.String())
+		})
+		Convey("sync completed and failed", func() {
+			recorder.SyncCompleted("test", "v1")
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.SyncCompletedEventType.String())
+
+			recorder.SyncFailed("test", "", zerr.ErrSyncReferrerNotFound)
+			ev = <-sink.store
+			So(ev.Type(), ShouldEqual, events.SyncFailedEventType.String())
+
+			data := map[string]any{}
+			So(ev.DataAs(&data), ShouldBeNil)
+			So(data["error"], ShouldEqual, zerr.ErrSyncReferrerNotFound.Error())
+		})
+		Convey("cve scan completed", func() {
+			recorder.CVEScanCompleted("test", "sha256:abc", "HIGH", map[string]int{"HIGH": 2, "LOW": 1})
+			ev := <-sink.store
+			So(ev.Type(), ShouldEqual, events.CVEScanCompletedEventType.String())
+
+			data := struct {
+				Count          int            `json:"count"`
+				MaxSeverity    string         `json:"maxSeverity"`
+				SeverityCounts map[string]int `json:"severityCounts"`
+			}{}
+			So(ev.DataAs(&data), ShouldBeNil)
+			So(data.Count, ShouldEqual, 3)
+			So(data.MaxSeverity, ShouldEqual, "HIGH")
+			So(data.SeverityCounts["HIGH"], ShouldEqual, 2)
+		})
+	})
+}
+
+func TestFilterSink(t *testing.T) {
+	Convey("filter sink only forwards the subscribed events", t, func() {
+		sink := &flakySink{}
+
+		Convey("by event type", func() {
+			filterSink := events.NewFilterSink(sink, []string{"zotregistry.image.*"}, nil)
+			recorder, err := events.NewRecorder(log.NewLogger("debug", ""), filterSink)
+			So(err, ShouldBeNil)
+
+			recorder.RepositoryCreated("alpine")
+			recorder.ImageUpdated("alpine", "latest", "", ispec.MediaTypeImageManifest, "")
+
+			So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+			// the filtered out event is not delivered afterwards
+			time.Sleep(100 * time.Millisecond)
+			So(sink.getDelivered()[0].Type(), ShouldEqual, events.ImageUpdatedEventType.String())
+			So(sink.getAttempts(), ShouldEqual, 1)
+		})
+
+		Convey("by repository", func() {
+			filterSink := events.NewFilterSink(sink, nil, []string{"library/**"})
+			recorder, err := events.NewRecorder(log.NewLogger("debug", ""), filterSink)
+			So(err, ShouldBeNil)
+
+			recorder.RepositoryCreated("alpine")
+			recorder.RepositoryCreated("library/alpine")
+
+			So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+			time.Sleep(100 * time.Millisecond)
+			So(sink.getAttempts(), ShouldEqual, 1)
+
+			data := map[string]any{}
+			So(sink.getDelivered()[0].DataAs(&data), ShouldBeNil)
+			So(data["name"], ShouldEqual, "library/alpine")
+		})
+
+		Convey("events without a repository don't match repository patterns", func() {
+			filterSink := events.NewFilterSink(sink, nil, []string{"**"})
+
+			event := cloudevents.NewEvent()
+			event.SetType(events.RepositoryCreatedEventType.String())
+
+			So(filterSink.Emit(&event), ShouldBeNil)
+			So(sink.getAttempts(), ShouldEqual, 0)
+			So(filterSink.Close(), ShouldBeNil)
 		})
 	})
 }
@@ -133,7 +250,7 @@ func TestHTTPSinkEvents(t *testing.T) {
 		})
 
 		Convey("image lint failed", func() {
-			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "")
+			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "", nil)
 			e := getEvent(t, eventChan)
 			So(e, ShouldNotBeNil)
 			So(e.Type(), ShouldEqual, events.ImageLintFailedEventType.String())
@@ -228,7 +345,7 @@ func TestNATSSinkEvents(t *testing.T) {
 			defer nc.Close()
 			So(err, ShouldBeNil)
 
-			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "")
+			recorder.ImageLintFailed("test", "v1", "", string(types.OCIManifestSchema1), "", nil)
 
 			e := getEvent(t, eventChan)
 			So(e, ShouldNotBeNil)
@@ -25,3 +25,21 @@ func NewRecorder(logger log.Logger, sinks ...Sink) (Recorder, error) {
 		log:   logger,
 	}, nil
 }
+
+// NewRecorderWithOutbox creates a recorder which persists the events in the outbox until they are delivered,
+// the sinks should be wrapped by the outbox. The recorder also gives access to the dead-lettered events.
+func NewRecorderWithOutbox(logger log.Logger, outbox *Outbox, sinks ...Sink) (Recorder, error) {
+	if sinks == nil {
+		return nil, zerr.ErrEventSinkIsNil
+	}
+
+	outbox.Start()
+
+	return &outboxRecorder{
+		eventRecorder: eventRecorder{
+			sinks: sinks,
+			log:   logger,
+		},
+		Outbox: outbox,
+	}, nil
+}
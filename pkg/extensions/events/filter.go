@@ -0,0 +1,69 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	glob "github.com/bmatcuk/doublestar/v4"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// filterSink only forwards the events a sink is subscribed to, the others are dropped.
+type filterSink struct {
+	sink         Sink
+	eventTypes   []string
+	repositories []string
+}
+
+// NewFilterSink returns a sink which only forwards the events whose type and repository match the given
+// glob patterns, an empty list of patterns matches everything. Events which are not about a repository
+// are not forwarded if repository patterns are given.
+func NewFilterSink(sink Sink, eventTypes, repositories []string) Sink {
+	return &filterSink{
+		sink:         sink,
+		eventTypes:   eventTypes,
+		repositories: repositories,
+	}
+}
+
+func (s *filterSink) Emit(event *cloudevents.Event) cloudevents.Result {
+	if !s.matches(event) {
+		return nil
+	}
+
+	return s.sink.Emit(event)
+}
+
+func (s *filterSink) Close() error {
+	return s.sink.Close()
+}
+
+func (s *filterSink) matches(event *cloudevents.Event) bool {
+	if len(s.eventTypes) > 0 && !matchesAny(s.eventTypes, event.Type()) {
+		return false
+	}
+
+	if len(s.repositories) == 0 {
+		return true
+	}
+
+	data := struct {
+		Name string `json:"name"`
+	}{}
+
+	if err := event.DataAs(&data); err != nil || data.Name == "" {
+		return false
+	}
+
+	return matchesAny(s.repositories, data.Name)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := glob.Match(pattern, value); matched {
+			return true
+		}
+	}
+
+	return false
+}
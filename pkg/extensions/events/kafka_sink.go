@@ -0,0 +1,116 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+	cekafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+)
+
+// KafkaSink implements a CloudEvents sink that publishes to a Kafka topic.
+type KafkaSink struct {
+	cloudevents.Client
+	sender *cekafka.Sender
+	config eventsconf.SinkConfig
+}
+
+// NewKafkaSink creates a new Kafka sink, the address being a comma separated list of brokers
+// and the channel the topic to publish to.
+func NewKafkaSink(config eventsconf.SinkConfig) (*KafkaSink, error) {
+	if config.Type != eventsconf.Kafka {
+		return nil, zerr.ErrInvalidEventSinkType
+	}
+
+	if config.Address == "" {
+		return nil, zerr.ErrEventSinkAddressEmpty
+	}
+
+	if config.Channel == "" {
+		return nil, zerr.ErrEventSinkChannelEmpty
+	}
+
+	saramaConfig, err := getSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := cekafka.NewSender(strings.Split(config.Address, ","), saramaConfig, config.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka protocol: %w", err)
+	}
+
+	ceClient, err := cloudevents.NewClient(sender)
+	if err != nil {
+		_ = sender.Close(context.Background())
+
+		return nil, fmt.Errorf("failed to create CloudEvents client: %w", err)
+	}
+
+	return &KafkaSink{
+		Client: ceClient,
+		sender: sender,
+		config: config,
+	}, nil
+}
+
+func getSaramaConfig(config eventsconf.SinkConfig) (*sarama.Config, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = EventSource
+	saramaConfig.Net.DialTimeout = getSinkTimeout(config)
+	saramaConfig.Producer.Timeout = getSinkTimeout(config)
+
+	// the producer retries on its own, as it also handles leader changes
+	if config.Delivery == eventsconf.AtLeastOnce {
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+		saramaConfig.Producer.Retry.Max = getMaxRetries(config)
+		saramaConfig.Producer.Retry.Backoff = getRetryDelay(config)
+	} else {
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForLocal
+		saramaConfig.Producer.Retry.Max = 0
+	}
+
+	if config.Credentials != nil && config.Credentials.Username != "" {
+		saramaConfig.Net.SASL.Enable = true
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		saramaConfig.Net.SASL.User = config.Credentials.Username
+		saramaConfig.Net.SASL.Password = config.Credentials.Password
+	}
+
+	if config.TLSConfig != nil && (config.TLSConfig.CACertFile != "" || config.TLSConfig.CertFile != "") {
+		tlsConfig, err := getTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		saramaConfig.Net.TLS.Enable = true
+		saramaConfig.Net.TLS.Config = tlsConfig
+	}
+
+	return saramaConfig, nil
+}
+
+// Emit sends a CloudEvent to Kafka.
+func (s *KafkaSink) Emit(event *cloudevents.Event) cloudevents.Result {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), getSinkTimeout(s.config))
+	defer cancel()
+
+	return s.Send(ctx, *event)
+}
+
+// Close closes the Kafka producer.
+func (s *KafkaSink) Close() error {
+	return s.sender.Close(context.Background())
+}
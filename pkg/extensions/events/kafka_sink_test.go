@@ -0,0 +1,169 @@
+//go:build events
+// +build events
+
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/events"
+)
+
+func TestKafkaSink(t *testing.T) {
+	Convey("NewKafkaSink returns error for invalid type", t, func() {
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{Type: "invalid", Address: "localhost:9092"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrInvalidEventSinkType)
+	})
+
+	Convey("NewKafkaSink returns error for empty address or topic", t, func() {
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{Type: eventsconf.Kafka, Channel: "events"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrEventSinkAddressEmpty)
+
+		sink, err = events.NewKafkaSink(eventsconf.SinkConfig{Type: eventsconf.Kafka, Address: "localhost:9092"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrEventSinkChannelEmpty)
+	})
+
+	Convey("NewKafkaSink fails with invalid TLS config", t, func() {
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Kafka,
+			Address: "localhost:9092",
+			Channel: "events",
+			TLSConfig: &eventsconf.TLSConfig{
+				CACertFile: "invalid",
+			},
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("NewKafkaSink fails if no broker is reachable", t, func() {
+		broker := sarama.NewMockBroker(t, 1)
+		address := broker.Addr()
+		broker.Close()
+
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Kafka,
+			Address: address,
+			Channel: "events",
+			Timeout: time.Second,
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("KafkaSink publishes events to the topic", t, func() {
+		broker := newMockKafkaBroker(t, "zot-events", sarama.ErrNoError)
+		defer broker.Close()
+
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{
+			Type:     eventsconf.Kafka,
+			Address:  broker.Addr(),
+			Channel:  "zot-events",
+			Timeout:  time.Second,
+			Delivery: eventsconf.AtLeastOnce,
+		})
+		So(err, ShouldBeNil)
+
+		err = sink.Emit(newTestEvent())
+		So(cloudevents.IsACK(err), ShouldBeTrue)
+
+		produceRequests := getProduceRequests(broker)
+		So(len(produceRequests), ShouldEqual, 1)
+		// at-least-once delivery waits for all the in-sync replicas
+		So(produceRequests[0].RequiredAcks, ShouldEqual, sarama.WaitForAll)
+
+		So(sink.Close(), ShouldBeNil)
+	})
+
+	Convey("KafkaSink retries with at-least-once delivery", t, func() {
+		broker := newMockKafkaBroker(t, "zot-events", sarama.ErrNotEnoughReplicas)
+		defer broker.Close()
+
+		config := eventsconf.SinkConfig{
+			Type:       eventsconf.Kafka,
+			Address:    broker.Addr(),
+			Channel:    "zot-events",
+			Timeout:    time.Second,
+			Delivery:   eventsconf.AtLeastOnce,
+			MaxRetries: 2,
+			RetryDelay: 10 * time.Millisecond,
+		}
+
+		sink, err := events.NewKafkaSink(config)
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		err = sink.Emit(newTestEvent())
+		So(cloudevents.IsACK(err), ShouldBeFalse)
+		So(len(getProduceRequests(broker)), ShouldEqual, 3)
+
+		// at-most-once delivery only tries once
+		config.Delivery = eventsconf.AtMostOnce
+
+		atMostOnceSink, err := events.NewKafkaSink(config)
+		So(err, ShouldBeNil)
+
+		defer atMostOnceSink.Close()
+
+		err = atMostOnceSink.Emit(newTestEvent())
+		So(cloudevents.IsACK(err), ShouldBeFalse)
+		So(len(getProduceRequests(broker)), ShouldEqual, 4)
+	})
+
+	Convey("KafkaSink.Emit returns error for invalid event", t, func() {
+		broker := newMockKafkaBroker(t, "zot-events", sarama.ErrNoError)
+		defer broker.Close()
+
+		sink, err := events.NewKafkaSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Kafka,
+			Address: broker.Addr(),
+			Channel: "zot-events",
+		})
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		event := cloudevents.NewEvent()
+		So(sink.Emit(&event), ShouldNotBeNil)
+	})
+}
+
+func newMockKafkaBroker(t *testing.T, topic string, produceErr sarama.KError) *sarama.MockBroker {
+	t.Helper()
+
+	broker := sarama.NewMockBroker(t, 1)
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError(topic, 0, produceErr),
+	})
+
+	return broker
+}
+
+func getProduceRequests(broker *sarama.MockBroker) []*sarama.ProduceRequest {
+	produceRequests := []*sarama.ProduceRequest{}
+
+	for _, reqRes := range broker.History() {
+		if produceRequest, ok := reqRes.Request.(*sarama.ProduceRequest); ok {
+			produceRequests = append(produceRequests, produceRequest)
+		}
+	}
+
+	return produceRequests
+}
@@ -0,0 +1,449 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+const (
+	DefaultOutboxMaxAttempts         = 10
+	DefaultOutboxInitialBackoff      = time.Second
+	DefaultOutboxMaxBackoff          = 5 * time.Minute
+	DefaultOutboxInterval            = 5 * time.Second
+	DefaultOutboxDeadLetterRetention = 7 * 24 * time.Hour
+
+	// how long an entry stays claimed by the instance delivering it, longer than any sink timeout
+	// so it's only reclaimed if that instance went away
+	outboxClaimLease = 5 * time.Minute
+
+	outboxPending      = "pending"
+	outboxDeadLettered = "deadlettered"
+)
+
+// OutboxEntry is an event persisted in the outbox until it's delivered to a sink.
+type OutboxEntry struct {
+	ID           string            `json:"id"`
+	Sink         string            `json:"sink"`
+	Event        cloudevents.Event `json:"event"               swaggertype:"object"`
+	Attempts     int               `json:"attempts"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	NextAttempt  time.Time         `json:"nextAttempt"`
+	LastError    string            `json:"lastError,omitempty"`
+	DeadLettered bool              `json:"deadLettered"`
+	// when the event was dead-lettered, it's removed from the outbox once the retention expired
+	DeadLetteredAt time.Time `json:"deadLetteredAt"`
+}
+
+// OutboxStore persists the outbox entries.
+type OutboxStore interface {
+	Put(entry OutboxEntry) error
+	// Get returns zerr.ErrEventNotFound if there is no entry with the given id.
+	Get(id string) (OutboxEntry, error)
+	Delete(id string) error
+	List() ([]OutboxEntry, error)
+	// Claim atomically marks the entry as being delivered by the owner until the lease expires, it returns
+	// false if the entry is already claimed by another owner. The outbox can be shared by several instances.
+	Claim(id, owner string, lease time.Duration) (bool, error)
+	// Release removes the claim of the owner on the entry.
+	Release(id, owner string) error
+	Close() error
+}
+
+// DeadLetterQueue gives access to the events which could not be delivered after all the attempts.
+type DeadLetterQueue interface {
+	ListDeadLetters() ([]OutboxEntry, error)
+	ReplayDeadLetter(id string) error
+	ReplayDeadLetters() (int, error)
+}
+
+// Outbox persists the events before sending them to the sinks and retries sending them with backoff,
+// events which still could not be delivered after the max number of attempts are dead-lettered.
+type Outbox struct {
+	store   OutboxStore
+	sinks   map[string]Sink
+	config  eventsconf.OutboxConfig
+	metrics monitoring.MetricServer
+	log     log.Logger
+	// identifies the claims of this instance on the outbox entries
+	owner string
+
+	wake      chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+var _ DeadLetterQueue = (*Outbox)(nil)
+
+func NewOutbox(store OutboxStore, config eventsconf.OutboxConfig, metrics monitoring.MetricServer,
+	log log.Logger,
+) *Outbox {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultOutboxInitialBackoff
+	}
+
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultOutboxMaxBackoff
+	}
+
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = DefaultOutboxInterval
+	}
+
+	if config.DeadLetterRetention <= 0 {
+		config.DeadLetterRetention = DefaultOutboxDeadLetterRetention
+	}
+
+	return &Outbox{
+		store:   store,
+		sinks:   map[string]Sink{},
+		config:  config,
+		metrics: metrics,
+		log:     log,
+		owner:   uuid.New().String(),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Wrap returns a sink which persists the events in the outbox before sending them to the given sink,
+// the name identifies the sink across restarts so it should only depend on its configuration.
+func (o *Outbox) Wrap(name string, sink Sink) Sink {
+	o.sinks[name] = sink
+
+	return &outboxSink{name: name, sink: sink, outbox: o}
+}
+
+// Start delivers the pending events in the background, including the ones left over by a previous run.
+func (o *Outbox) Start() {
+	o.wg.Add(1)
+
+	go func() {
+		defer o.wg.Done()
+
+		ticker := time.NewTicker(o.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			o.deliverPending()
+
+			select {
+			case <-o.done:
+				return
+			case <-ticker.C:
+			case <-o.wake:
+			}
+		}
+	}()
+}
+
+// Close stops the background delivery and closes the store.
+func (o *Outbox) Close() error {
+	var err error
+
+	o.closeOnce.Do(func() {
+		close(o.done)
+		o.wg.Wait()
+
+		err = o.store.Close()
+	})
+
+	return err
+}
+
+func (o *Outbox) isClosed() bool {
+	select {
+	case <-o.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// deliverPending scans the outbox once per round: it delivers the events which are due, removes the dead
+// letters past their retention and updates the outbox length metrics, as of the start of the round.
+func (o *Outbox) deliverPending() {
+	entries, err := o.store.List()
+	if err != nil {
+		o.log.Error().Err(err).Msg("failed to list the events in the outbox")
+
+		return
+	}
+
+	now := time.Now()
+	counts := map[string]int{outboxPending: 0, outboxDeadLettered: 0}
+
+	for _, entry := range entries {
+		if o.isClosed() {
+			return
+		}
+
+		if entry.DeadLettered {
+			if now.Sub(entry.DeadLetteredAt) > o.config.DeadLetterRetention && o.purge(entry.ID) {
+				continue
+			}
+
+			counts[outboxDeadLettered]++
+
+			continue
+		}
+
+		counts[outboxPending]++
+
+		// events of sinks which are not configured on this instance are left to the instances which have them
+		if _, ok := o.sinks[entry.Sink]; !ok || entry.NextAttempt.After(now) {
+			continue
+		}
+
+		_ = o.deliver(entry.ID)
+	}
+
+	monitoring.SetEventsOutboxLength(o.metrics, counts)
+}
+
+// purge removes a dead letter whose retention expired, unless it was replayed in the meantime.
+func (o *Outbox) purge(id string) bool {
+	claimed, err := o.store.Claim(id, o.owner, outboxClaimLease)
+	if err != nil || !claimed {
+		return false
+	}
+
+	defer o.release(id)
+
+	entry, err := o.store.Get(id)
+	if err != nil || !entry.DeadLettered || time.Since(entry.DeadLetteredAt) <= o.config.DeadLetterRetention {
+		return false
+	}
+
+	if err := o.store.Delete(id); err != nil {
+		o.log.Error().Err(err).Str("id", id).Msg("failed to remove expired dead letter")
+
+		return false
+	}
+
+	o.log.Info().Str("sink", entry.Sink).Str("id", entry.ID).Str("type", entry.Event.Type()).
+		Time("deadLetteredAt", entry.DeadLetteredAt).Msg("removed expired dead letter")
+
+	return true
+}
+
+// deliver sends the event of an outbox entry to its sink, the entry is removed once the event is delivered,
+// otherwise the next attempt is scheduled or the event is dead-lettered.
+func (o *Outbox) deliver(id string) error {
+	claimed, err := o.store.Claim(id, o.owner, outboxClaimLease)
+	if err != nil {
+		o.log.Error().Err(err).Str("id", id).Msg("failed to claim event in the outbox")
+
+		return err
+	}
+
+	if !claimed {
+		// already being delivered, possibly by another instance
+		return nil
+	}
+
+	defer o.release(id)
+
+	entry, err := o.store.Get(id)
+	if errors.Is(err, zerr.ErrEventNotFound) {
+		// delivered by another instance in the meantime
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sink, ok := o.sinks[entry.Sink]
+	if entry.DeadLettered || !ok {
+		return nil
+	}
+
+	result := sink.Emit(&entry.Event)
+	if isDelivered(result) {
+		monitoring.ObserveEventDeliveryLag(o.metrics, entry.Sink, time.Since(entry.CreatedAt))
+
+		return o.store.Delete(id)
+	}
+
+	entry.Attempts++
+	entry.LastError = result.Error()
+
+	if entry.Attempts >= o.config.MaxAttempts {
+		entry.DeadLettered = true
+		entry.DeadLetteredAt = time.Now()
+
+		o.log.Error().Err(result).Str("sink", entry.Sink).Str("id", entry.ID).Str("type", entry.Event.Type()).
+			Int("attempts", entry.Attempts).Msg("failed to deliver event, moved to the dead letters")
+	} else {
+		entry.NextAttempt = time.Now().Add(o.getBackoff(entry.Attempts))
+
+		o.log.Warn().Err(result).Str("sink", entry.Sink).Str("id", entry.ID).Str("type", entry.Event.Type()).
+			Int("attempts", entry.Attempts).Time("nextAttempt", entry.NextAttempt).
+			Msg("failed to deliver event, will retry")
+	}
+
+	if err := o.store.Put(entry); err != nil {
+		return errors.Join(result, err)
+	}
+
+	return result
+}
+
+// getBackoff returns the delay before the next attempt, doubled after each failed attempt.
+func (o *Outbox) getBackoff(attempts int) time.Duration {
+	backoff := o.config.InitialBackoff
+
+	for i := 1; i < attempts && backoff < o.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, o.config.MaxBackoff)
+}
+
+func (o *Outbox) release(id string) {
+	if err := o.store.Release(id, o.owner); err != nil {
+		// the claim expires with its lease
+		o.log.Error().Err(err).Str("id", id).Msg("failed to release event in the outbox")
+	}
+}
+
+func (o *Outbox) triggerDelivery() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ListDeadLetters returns the dead-lettered events, oldest first.
+func (o *Outbox) ListDeadLetters() ([]OutboxEntry, error) {
+	entries, err := o.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := []OutboxEntry{}
+
+	for _, entry := range entries {
+		if entry.DeadLettered {
+			deadLetters = append(deadLetters, entry)
+		}
+	}
+
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].CreatedAt.Before(deadLetters[j].CreatedAt)
+	})
+
+	return deadLetters, nil
+}
+
+// ReplayDeadLetter schedules a dead-lettered event for delivery, with the attempts count reset.
+func (o *Outbox) ReplayDeadLetter(id string) error {
+	entry, err := o.store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if !entry.DeadLettered {
+		return zerr.ErrEventNotDeadLettered
+	}
+
+	if err := o.store.Put(resetEntry(entry)); err != nil {
+		return err
+	}
+
+	o.triggerDelivery()
+
+	return nil
+}
+
+// ReplayDeadLetters schedules all the dead-lettered events for delivery and returns their number.
+func (o *Outbox) ReplayDeadLetters() (int, error) {
+	deadLetters, err := o.ListDeadLetters()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range deadLetters {
+		if err := o.store.Put(resetEntry(entry)); err != nil {
+			return 0, err
+		}
+	}
+
+	o.triggerDelivery()
+
+	return len(deadLetters), nil
+}
+
+func resetEntry(entry OutboxEntry) OutboxEntry {
+	entry.Attempts = 0
+	entry.DeadLettered = false
+	entry.DeadLetteredAt = time.Time{}
+	entry.NextAttempt = time.Now()
+
+	return entry
+}
+
+// outboxSink persists the events in the outbox, so they are not lost if they can't be sent right away.
+type outboxSink struct {
+	name   string
+	sink   Sink
+	outbox *Outbox
+}
+
+func (s *outboxSink) Emit(event *cloudevents.Event) cloudevents.Result {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	entry := OutboxEntry{
+		ID:          uuid.New().String(),
+		Sink:        s.name,
+		Event:       *event,
+		CreatedAt:   now,
+		NextAttempt: now,
+	}
+
+	if err := s.outbox.store.Put(entry); err != nil {
+		s.outbox.log.Error().Err(err).Str("sink", s.name).
+			Msg("failed to persist event in the outbox, sending it directly")
+
+		return s.sink.Emit(event)
+	}
+
+	// failures are retried by the outbox
+	_ = s.outbox.deliver(entry.ID)
+
+	return nil
+}
+
+func (s *outboxSink) Close() error {
+	return s.sink.Close()
+}
+
+func isDelivered(result cloudevents.Result) bool {
+	return !cloudevents.IsNACK(result) && !cloudevents.IsUndelivered(result)
+}
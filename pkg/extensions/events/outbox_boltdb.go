@@ -0,0 +1,176 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	zerr "zotregistry.dev/zot/errors"
+	storageConstants "zotregistry.dev/zot/pkg/storage/constants"
+)
+
+const (
+	OutboxDBName       = "events.db"
+	OutboxBucket       = "EventOutbox"
+	OutboxClaimsBucket = "EventOutboxClaims"
+
+	outboxDBTimeout = 10 * time.Second
+)
+
+// BoltDBOutboxStore keeps the outbox in a boltdb file in the root directory, next to the metadb.
+type BoltDBOutboxStore struct {
+	db *bolt.DB
+}
+
+var _ OutboxStore = (*BoltDBOutboxStore)(nil)
+
+func NewBoltDBOutboxStore(rootDir string) (*BoltDBOutboxStore, error) {
+	if err := os.MkdirAll(rootDir, storageConstants.DefaultDirPerms); err != nil {
+		return nil, err
+	}
+
+	boltDB, err := bolt.Open(path.Join(rootDir, OutboxDBName), storageConstants.DefaultFilePerms,
+		&bolt.Options{Timeout: outboxDBTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = boltDB.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists([]byte(OutboxBucket)); err != nil {
+			return err
+		}
+
+		_, err := tx.CreateBucketIfNotExists([]byte(OutboxClaimsBucket))
+
+		return err
+	})
+	if err != nil {
+		_ = boltDB.Close()
+
+		return nil, err
+	}
+
+	return &BoltDBOutboxStore{db: boltDB}, nil
+}
+
+func (s *BoltDBOutboxStore) Put(entry OutboxEntry) error {
+	entryBlob, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(OutboxBucket)).Put([]byte(entry.ID), entryBlob)
+	})
+}
+
+func (s *BoltDBOutboxStore) Get(id string) (OutboxEntry, error) {
+	entry := OutboxEntry{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		entryBlob := tx.Bucket([]byte(OutboxBucket)).Get([]byte(id))
+		if entryBlob == nil {
+			return zerr.ErrEventNotFound
+		}
+
+		return json.Unmarshal(entryBlob, &entry)
+	})
+
+	return entry, err
+}
+
+func (s *BoltDBOutboxStore) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(OutboxBucket)).Delete([]byte(id))
+	})
+}
+
+// outboxClaim is the owner of an entry being delivered and the expiry of its lease.
+type outboxClaim struct {
+	Owner string    `json:"owner"`
+	Until time.Time `json:"until"`
+}
+
+func (s *BoltDBOutboxStore) Claim(id, owner string, lease time.Duration) (bool, error) {
+	claimed := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		claimsBuck := tx.Bucket([]byte(OutboxClaimsBucket))
+
+		if claimBlob := claimsBuck.Get([]byte(id)); claimBlob != nil {
+			claim := outboxClaim{}
+
+			if err := json.Unmarshal(claimBlob, &claim); err != nil {
+				return err
+			}
+
+			if time.Now().Before(claim.Until) {
+				return nil
+			}
+		}
+
+		claimBlob, err := json.Marshal(outboxClaim{Owner: owner, Until: time.Now().Add(lease)})
+		if err != nil {
+			return err
+		}
+
+		claimed = true
+
+		return claimsBuck.Put([]byte(id), claimBlob)
+	})
+
+	return claimed && err == nil, err
+}
+
+func (s *BoltDBOutboxStore) Release(id, owner string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		claimsBuck := tx.Bucket([]byte(OutboxClaimsBucket))
+
+		claimBlob := claimsBuck.Get([]byte(id))
+		if claimBlob == nil {
+			return nil
+		}
+
+		claim := outboxClaim{}
+
+		if err := json.Unmarshal(claimBlob, &claim); err != nil {
+			return err
+		}
+
+		if claim.Owner != owner {
+			return nil
+		}
+
+		return claimsBuck.Delete([]byte(id))
+	})
+}
+
+func (s *BoltDBOutboxStore) List() ([]OutboxEntry, error) {
+	entries := []OutboxEntry{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(OutboxBucket)).ForEach(func(_, entryBlob []byte) error {
+			entry := OutboxEntry{}
+
+			if err := json.Unmarshal(entryBlob, &entry); err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+
+			return nil
+		})
+	})
+
+	return entries, err
+}
+
+func (s *BoltDBOutboxStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,105 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	zerr "zotregistry.dev/zot/errors"
+)
+
+// releaseClaimScript deletes the claim of an entry only if it's still held by the given owner,
+// it could have expired and been claimed by another instance.
+var releaseClaimScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`) //nolint: gochecknoglobals
+
+// RedisOutboxStore keeps the outbox in a redis hash, next to the metadb when redis is used as remote cache.
+// The claims on the entries are separate keys expiring with their lease.
+type RedisOutboxStore struct {
+	client redis.UniversalClient
+	key    string
+}
+
+var _ OutboxStore = (*RedisOutboxStore)(nil)
+
+func NewRedisOutboxStore(client redis.UniversalClient, keyPrefix string) *RedisOutboxStore {
+	return &RedisOutboxStore{
+		client: client,
+		key:    keyPrefix + ":" + OutboxBucket,
+	}
+}
+
+func (s *RedisOutboxStore) Put(entry OutboxEntry) error {
+	entryBlob, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.client.HSet(context.Background(), s.key, entry.ID, entryBlob).Err()
+}
+
+func (s *RedisOutboxStore) Get(id string) (OutboxEntry, error) {
+	entry := OutboxEntry{}
+
+	entryBlob, err := s.client.HGet(context.Background(), s.key, id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entry, zerr.ErrEventNotFound
+		}
+
+		return entry, err
+	}
+
+	err = json.Unmarshal(entryBlob, &entry)
+
+	return entry, err
+}
+
+func (s *RedisOutboxStore) Delete(id string) error {
+	return s.client.HDel(context.Background(), s.key, id).Err()
+}
+
+func (s *RedisOutboxStore) Claim(id, owner string, lease time.Duration) (bool, error) {
+	return s.client.SetNX(context.Background(), s.getClaimKey(id), owner, lease).Result()
+}
+
+func (s *RedisOutboxStore) Release(id, owner string) error {
+	return releaseClaimScript.Run(context.Background(), s.client, []string{s.getClaimKey(id)}, owner).Err()
+}
+
+func (s *RedisOutboxStore) getClaimKey(id string) string {
+	return s.key + ":claims:" + id
+}
+
+func (s *RedisOutboxStore) List() ([]OutboxEntry, error) {
+	entryBlobs, err := s.client.HGetAll(context.Background(), s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]OutboxEntry, 0, len(entryBlobs))
+
+	for _, entryBlob := range entryBlobs {
+		entry := OutboxEntry{}
+
+		if err := json.Unmarshal([]byte(entryBlob), &entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (s *RedisOutboxStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,528 @@
+//go:build events
+// +build events
+
+package events_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+var errSinkUnavailable = errors.New("sink unavailable")
+
+// flakySink fails to deliver the events until it's told to succeed.
+type flakySink struct {
+	lock      sync.Mutex
+	failing   bool
+	attempts  int
+	delivered []*cloudevents.Event
+}
+
+func (s *flakySink) Emit(event *cloudevents.Event) cloudevents.Result {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.attempts++
+
+	if s.failing {
+		return errSinkUnavailable
+	}
+
+	s.delivered = append(s.delivered, event)
+
+	return nil
+}
+
+func (s *flakySink) Close() error {
+	return nil
+}
+
+func (s *flakySink) setFailing(failing bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failing = failing
+}
+
+func (s *flakySink) getAttempts() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.attempts
+}
+
+func (s *flakySink) getDelivered() []*cloudevents.Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]*cloudevents.Event{}, s.delivered...)
+}
+
+func newTestOutbox(store events.OutboxStore, maxAttempts int) *events.Outbox {
+	logger := log.NewLogger("debug", "")
+
+	return events.NewOutbox(store, eventsconf.OutboxConfig{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     40 * time.Millisecond,
+		Interval:       10 * time.Millisecond,
+	}, monitoring.NewMetricsServer(false, logger), logger)
+}
+
+func waitFor(condition func() bool) bool {
+	for range 500 {
+		if condition() {
+			return true
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestOutbox(t *testing.T) {
+	Convey("Events are delivered right away if the sink is available", t, func() {
+		store, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		sink := &flakySink{}
+		outbox := newTestOutbox(store, 3)
+
+		recorder, err := events.NewRecorderWithOutbox(log.NewLogger("debug", ""), outbox,
+			outbox.Wrap("flaky", sink))
+		So(err, ShouldBeNil)
+
+		defer recorder.Close()
+
+		recorder.RepositoryCreated("alpine")
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+		So(sink.getDelivered()[0].Type(), ShouldEqual, events.RepositoryCreatedEventType.String())
+		So(sink.getAttempts(), ShouldEqual, 1)
+
+		So(waitFor(func() bool {
+			entries, err := store.List()
+
+			return err == nil && len(entries) == 0
+		}), ShouldBeTrue)
+	})
+
+	Convey("Events are retried with backoff until the sink is available", t, func() {
+		store, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		sink := &flakySink{failing: true}
+		outbox := newTestOutbox(store, 100)
+		wrapped := outbox.Wrap("flaky", sink)
+
+		outbox.Start()
+		defer outbox.Close()
+
+		// the event is kept in the outbox, so it's not reported as failed
+		So(wrapped.Emit(newTestEvent()), ShouldBeNil)
+
+		So(waitFor(func() bool { return sink.getAttempts() >= 3 }), ShouldBeTrue)
+
+		entries, err := store.List()
+		So(err, ShouldBeNil)
+		So(len(entries), ShouldEqual, 1)
+		So(entries[0].Sink, ShouldEqual, "flaky")
+		So(entries[0].LastError, ShouldEqual, errSinkUnavailable.Error())
+		So(entries[0].DeadLettered, ShouldBeFalse)
+
+		sink.setFailing(false)
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+		So(sink.getDelivered()[0].ID(), ShouldEqual, "test-id")
+
+		So(waitFor(func() bool {
+			entries, err := store.List()
+
+			return err == nil && len(entries) == 0
+		}), ShouldBeTrue)
+	})
+
+	Convey("Events are dead-lettered after the max attempts and can be replayed", t, func() {
+		store, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		sink := &flakySink{failing: true}
+		outbox := newTestOutbox(store, 2)
+		wrapped := outbox.Wrap("flaky", sink)
+
+		outbox.Start()
+		defer outbox.Close()
+
+		So(wrapped.Emit(newTestEvent()), ShouldBeNil)
+		So(wrapped.Emit(newTestEvent()), ShouldBeNil)
+
+		var deadLetters []events.OutboxEntry
+
+		So(waitFor(func() bool {
+			deadLetters, err = outbox.ListDeadLetters()
+
+			return err == nil && len(deadLetters) == 2
+		}), ShouldBeTrue)
+
+		So(deadLetters[0].Attempts, ShouldEqual, 2)
+		So(deadLetters[0].CreatedAt.After(deadLetters[1].CreatedAt), ShouldBeFalse)
+
+		// dead letters are not retried anymore
+		attempts := sink.getAttempts()
+		So(attempts, ShouldEqual, 4)
+
+		time.Sleep(100 * time.Millisecond)
+		So(sink.getAttempts(), ShouldEqual, attempts)
+
+		err = outbox.ReplayDeadLetter("unknown")
+		So(err, ShouldEqual, zerr.ErrEventNotFound)
+
+		sink.setFailing(false)
+
+		err = outbox.ReplayDeadLetter(deadLetters[0].ID)
+		So(err, ShouldBeNil)
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+
+		replayed, err := outbox.ReplayDeadLetters()
+		So(err, ShouldBeNil)
+		So(replayed, ShouldEqual, 1)
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 2 }), ShouldBeTrue)
+
+		deadLetters, err = outbox.ListDeadLetters()
+		So(err, ShouldBeNil)
+		So(deadLetters, ShouldBeEmpty)
+
+		replayed, err = outbox.ReplayDeadLetters()
+		So(err, ShouldBeNil)
+		So(replayed, ShouldEqual, 0)
+	})
+
+	Convey("Only dead letters can be replayed", t, func() {
+		store, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		outbox := newTestOutbox(store, 2)
+		defer outbox.Close()
+
+		err = store.Put(events.OutboxEntry{ID: "pending", Sink: "flaky", Event: *newTestEvent()})
+		So(err, ShouldBeNil)
+
+		err = outbox.ReplayDeadLetter("pending")
+		So(err, ShouldEqual, zerr.ErrEventNotDeadLettered)
+	})
+
+	Convey("Events left in the outbox are delivered after a restart", t, func() {
+		rootDir := t.TempDir()
+
+		store, err := events.NewBoltDBOutboxStore(rootDir)
+		So(err, ShouldBeNil)
+
+		sink := &flakySink{failing: true}
+		outbox := newTestOutbox(store, 100)
+		wrapped := outbox.Wrap("flaky", sink)
+
+		So(wrapped.Emit(newTestEvent()), ShouldBeNil)
+		So(outbox.Close(), ShouldBeNil)
+
+		store, err = events.NewBoltDBOutboxStore(rootDir)
+		So(err, ShouldBeNil)
+
+		sink = &flakySink{}
+		outbox = newTestOutbox(store, 100)
+		_ = outbox.Wrap("flaky", sink)
+
+		outbox.Start()
+		defer outbox.Close()
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+		So(sink.getDelivered()[0].ID(), ShouldEqual, "test-id")
+	})
+
+	Convey("Events of sinks which are not configured locally are left to the other instances", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		err := events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot").
+			Put(events.OutboxEntry{
+				ID:          "other",
+				Sink:        "other",
+				Event:       *newTestEvent(),
+				CreatedAt:   time.Now(),
+				NextAttempt: time.Now(),
+			})
+		So(err, ShouldBeNil)
+
+		store := events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot")
+		outbox := newTestOutbox(store, 5)
+		_ = outbox.Wrap("flaky", &flakySink{})
+
+		outbox.Start()
+
+		time.Sleep(100 * time.Millisecond)
+		So(outbox.Close(), ShouldBeNil)
+
+		store = events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot")
+
+		entry, err := store.Get("other")
+		So(err, ShouldBeNil)
+		So(entry.Attempts, ShouldEqual, 0)
+		So(entry.DeadLettered, ShouldBeFalse)
+
+		sink := &flakySink{}
+		outbox = newTestOutbox(store, 5)
+		_ = outbox.Wrap("other", sink)
+
+		outbox.Start()
+		defer outbox.Close()
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 1 }), ShouldBeTrue)
+	})
+
+	Convey("Events are delivered once by the instances sharing the outbox", t, func() {
+		miniRedis := miniredis.RunT(t)
+		sink := &flakySink{}
+
+		outboxes := []*events.Outbox{}
+		wrapped := []events.Sink{}
+
+		for range 3 {
+			store := events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot")
+			outbox := newTestOutbox(store, 5)
+			wrapped = append(wrapped, outbox.Wrap("flaky", sink))
+			outboxes = append(outboxes, outbox)
+		}
+
+		// the events are left in the outbox, so the instances compete to deliver them
+		sink.setFailing(true)
+
+		for range 10 {
+			So(wrapped[0].Emit(newTestEvent()), ShouldBeNil)
+		}
+
+		sink.setFailing(false)
+
+		for _, outbox := range outboxes {
+			outbox.Start()
+			defer outbox.Close()
+		}
+
+		So(waitFor(func() bool { return len(sink.getDelivered()) == 10 }), ShouldBeTrue)
+
+		time.Sleep(100 * time.Millisecond)
+		So(len(sink.getDelivered()), ShouldEqual, 10)
+	})
+
+	Convey("Dead letters are removed after their retention", t, func() {
+		store, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		for id, deadLetteredAt := range map[string]time.Time{
+			"expired": time.Now().Add(-2 * time.Hour),
+			"recent":  time.Now(),
+		} {
+			err = store.Put(events.OutboxEntry{
+				ID:             id,
+				Sink:           "flaky",
+				Event:          *newTestEvent(),
+				CreatedAt:      deadLetteredAt,
+				DeadLettered:   true,
+				DeadLetteredAt: deadLetteredAt,
+			})
+			So(err, ShouldBeNil)
+		}
+
+		logger := log.NewLogger("debug", "")
+		outbox := events.NewOutbox(store, eventsconf.OutboxConfig{
+			Interval:            10 * time.Millisecond,
+			DeadLetterRetention: time.Hour,
+		}, monitoring.NewMetricsServer(false, logger), logger)
+
+		outbox.Start()
+		defer outbox.Close()
+
+		var deadLetters []events.OutboxEntry
+
+		So(waitFor(func() bool {
+			deadLetters, err = outbox.ListDeadLetters()
+
+			return err == nil && len(deadLetters) == 1
+		}), ShouldBeTrue)
+
+		So(deadLetters[0].ID, ShouldEqual, "recent")
+	})
+
+	Convey("Events are sent directly if they can't be persisted", t, func() {
+		miniRedis := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: miniRedis.Addr()})
+
+		sink := &flakySink{}
+		outbox := newTestOutbox(events.NewRedisOutboxStore(client, "zot"), 2)
+		wrapped := outbox.Wrap("flaky", sink)
+
+		defer outbox.Close()
+
+		miniRedis.SetError("server unavailable")
+
+		So(wrapped.Emit(newTestEvent()), ShouldBeNil)
+		So(len(sink.getDelivered()), ShouldEqual, 1)
+
+		event := cloudevents.NewEvent()
+		So(wrapped.Emit(&event), ShouldNotBeNil)
+	})
+}
+
+func TestOutboxStores(t *testing.T) {
+	boltStore, err := events.NewBoltDBOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	miniRedis := miniredis.RunT(t)
+
+	stores := map[string]events.OutboxStore{
+		"boltdb": boltStore,
+		"redis":  events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot"),
+	}
+
+	for name, store := range stores {
+		Convey("Test "+name+" outbox store", t, func() {
+			_, err := store.Get("missing")
+			So(err, ShouldEqual, zerr.ErrEventNotFound)
+
+			entries, err := store.List()
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+
+			entry := events.OutboxEntry{
+				ID:        "entry",
+				Sink:      "http:http://localhost",
+				Event:     *newTestEvent(),
+				Attempts:  1,
+				CreatedAt: time.Now().Round(0),
+				LastError: "error",
+			}
+
+			So(store.Put(entry), ShouldBeNil)
+
+			stored, err := store.Get("entry")
+			So(err, ShouldBeNil)
+			So(stored.Sink, ShouldEqual, entry.Sink)
+			So(stored.Attempts, ShouldEqual, 1)
+			So(stored.LastError, ShouldEqual, "error")
+			So(stored.CreatedAt.Equal(entry.CreatedAt), ShouldBeTrue)
+			So(stored.Event.ID(), ShouldEqual, "test-id")
+			So(string(stored.Event.Data()), ShouldEqual, string(entry.Event.Data()))
+
+			entries, err = store.List()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 1)
+
+			claimed, err := store.Claim("entry", "owner", time.Minute)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldBeTrue)
+
+			claimed, err = store.Claim("entry", "other", time.Minute)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldBeFalse)
+
+			// only the owner can release its claim
+			So(store.Release("entry", "other"), ShouldBeNil)
+
+			claimed, err = store.Claim("entry", "other", time.Minute)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldBeFalse)
+
+			So(store.Release("entry", "owner"), ShouldBeNil)
+			So(store.Release("missing", "owner"), ShouldBeNil)
+
+			claimed, err = store.Claim("entry", "other", time.Minute)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldBeTrue)
+
+			So(store.Release("entry", "other"), ShouldBeNil)
+
+			So(store.Delete("entry"), ShouldBeNil)
+
+			_, err = store.Get("entry")
+			So(err, ShouldEqual, zerr.ErrEventNotFound)
+
+			So(store.Close(), ShouldBeNil)
+		})
+	}
+
+	Convey("Test expired claims can be taken over", t, func() {
+		boltStore, err := events.NewBoltDBOutboxStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		defer boltStore.Close()
+
+		claimed, err := boltStore.Claim("entry", "owner", time.Millisecond)
+		So(err, ShouldBeNil)
+		So(claimed, ShouldBeTrue)
+
+		time.Sleep(10 * time.Millisecond)
+
+		claimed, err = boltStore.Claim("entry", "other", time.Minute)
+		So(err, ShouldBeNil)
+		So(claimed, ShouldBeTrue)
+
+		miniRedis := miniredis.RunT(t)
+		redisStore := events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot")
+
+		defer redisStore.Close()
+
+		claimed, err = redisStore.Claim("entry", "owner", time.Second)
+		So(err, ShouldBeNil)
+		So(claimed, ShouldBeTrue)
+
+		miniRedis.FastForward(2 * time.Second)
+
+		claimed, err = redisStore.Claim("entry", "other", time.Minute)
+		So(err, ShouldBeNil)
+		So(claimed, ShouldBeTrue)
+	})
+
+	Convey("Test redis outbox store errors", t, func() {
+		miniRedis := miniredis.RunT(t)
+		store := events.NewRedisOutboxStore(redis.NewClient(&redis.Options{Addr: miniRedis.Addr()}), "zot")
+
+		defer store.Close()
+
+		miniRedis.HSet("zot:"+events.OutboxBucket, "corrupted", "{")
+
+		_, err := store.Get("corrupted")
+		So(err, ShouldNotBeNil)
+
+		_, err = store.List()
+		So(err, ShouldNotBeNil)
+
+		miniRedis.SetError("server unavailable")
+
+		_, err = store.Get("entry")
+		So(err, ShouldNotBeNil)
+		So(err, ShouldNotEqual, zerr.ErrEventNotFound)
+
+		_, err = store.List()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test boltdb outbox store errors", t, func() {
+		_, err := events.NewBoltDBOutboxStore("/proc/invalid")
+		So(err, ShouldNotBeNil)
+	})
+}
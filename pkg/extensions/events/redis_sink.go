@@ -0,0 +1,144 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/redis/go-redis/v9"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+)
+
+const DefaultRedisStreamMaxLen = 100000
+
+// RedisSink implements a CloudEvents sink that appends events to a Redis stream, the channel being the stream key.
+type RedisSink struct {
+	client *redis.Client
+	config eventsconf.SinkConfig
+}
+
+// NewRedisSink creates a new Redis Streams sink, the address being either host:port or a redis:// (rediss://) URL.
+func NewRedisSink(config eventsconf.SinkConfig) (*RedisSink, error) {
+	if config.Type != eventsconf.Redis {
+		return nil, zerr.ErrInvalidEventSinkType
+	}
+
+	if config.Address == "" {
+		return nil, zerr.ErrEventSinkAddressEmpty
+	}
+
+	if config.Channel == "" {
+		return nil, zerr.ErrEventSinkChannelEmpty
+	}
+
+	opts := &redis.Options{Addr: config.Address}
+
+	if strings.Contains(config.Address, "://") {
+		var err error
+
+		opts, err = redis.ParseURL(config.Address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts.DialTimeout = getSinkTimeout(config)
+	// retries are done by the sink itself, depending on the delivery guarantee
+	opts.MaxRetries = -1
+
+	if config.Credentials != nil && config.Credentials.Username != "" {
+		opts.Username = config.Credentials.Username
+		opts.Password = config.Credentials.Password
+	}
+
+	if config.TLSConfig != nil && (config.TLSConfig.CACertFile != "" || config.TLSConfig.CertFile != "") {
+		tlsConfig, err := getTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.TLSConfig = tlsConfig
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), getSinkTimeout(config))
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisSink{
+		client: client,
+		config: config,
+	}, nil
+}
+
+// Emit appends a CloudEvent to the Redis stream.
+func (s *RedisSink) Emit(event *cloudevents.Event) cloudevents.Result {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	values := newRedisStreamValues(event)
+
+	return sendWithRetries(s.config, func(ctx context.Context) error {
+		return s.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.config.Channel,
+			MaxLen: getRedisStreamMaxLen(s.config),
+			Approx: true,
+			Values: values,
+		}).Err()
+	})
+}
+
+// getRedisStreamMaxLen returns the number of entries the stream is trimmed to, 0 meaning it's not trimmed.
+func getRedisStreamMaxLen(config eventsconf.SinkConfig) int64 {
+	if config.MaxLen == 0 {
+		return DefaultRedisStreamMaxLen
+	}
+
+	return max(config.MaxLen, 0)
+}
+
+// newRedisStreamValues maps the event to the fields of a stream entry,
+// one for each attribute and one for the data.
+func newRedisStreamValues(event *cloudevents.Event) map[string]any {
+	values := map[string]any{
+		"specversion":     event.SpecVersion(),
+		"id":              event.ID(),
+		"source":          event.Source(),
+		"type":            event.Type(),
+		"datacontenttype": event.DataContentType(),
+		"data":            string(event.Data()),
+	}
+
+	if !event.Time().IsZero() {
+		values["time"] = event.Time().UTC().Format(time.RFC3339Nano)
+	}
+
+	if event.Subject() != "" {
+		values["subject"] = event.Subject()
+	}
+
+	for name, value := range event.Extensions() {
+		values[name] = fmt.Sprint(value)
+	}
+
+	return values
+}
+
+// Close closes the Redis client.
+func (s *RedisSink) Close() error {
+	return s.client.Close()
+}
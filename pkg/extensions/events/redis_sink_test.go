@@ -0,0 +1,225 @@
+//go:build events
+// +build events
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+func TestRedisSink(t *testing.T) {
+	Convey("NewRedisSink returns error for invalid type", t, func() {
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{Type: "invalid", Address: "localhost:6379"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrInvalidEventSinkType)
+	})
+
+	Convey("NewRedisSink returns error for empty address or stream", t, func() {
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{Type: eventsconf.Redis, Channel: "events"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrEventSinkAddressEmpty)
+
+		sink, err = events.NewRedisSink(eventsconf.SinkConfig{Type: eventsconf.Redis, Address: "localhost:6379"})
+		So(sink, ShouldBeNil)
+		So(err, ShouldEqual, zerr.ErrEventSinkChannelEmpty)
+	})
+
+	Convey("NewRedisSink fails if the server is not reachable", t, func() {
+		miniRedis := miniredis.RunT(t)
+		address := miniRedis.Addr()
+		miniRedis.Close()
+
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: address,
+			Channel: "events",
+			Timeout: time.Second,
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("NewRedisSink fails with invalid URL or TLS config", t, func() {
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: "redis://localhost:6379/notadb",
+			Channel: "events",
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+
+		sink, err = events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: "localhost:6379",
+			Channel: "events",
+			TLSConfig: &eventsconf.TLSConfig{
+				CACertFile: "invalid",
+			},
+		})
+		So(sink, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("RedisSink appends events to the stream", t, func() {
+		miniRedis := miniredis.RunT(t)
+		miniRedis.RequireUserAuth("user", "pass")
+
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: "redis://" + miniRedis.Addr(),
+			Channel: "zot-events",
+			Timeout: time.Second,
+			Credentials: &eventsconf.Credentials{
+				Username: "user",
+				Password: "pass",
+			},
+		})
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		recorder, err := events.NewRecorder(log.NewLogger("debug", ""), sink)
+		So(err, ShouldBeNil)
+
+		recorder.RepositoryCreated("alpine")
+
+		client := redis.NewClient(&redis.Options{Addr: miniRedis.Addr(), Username: "user", Password: "pass"})
+		defer client.Close()
+
+		var entries []redis.XMessage
+
+		So(func() bool {
+			for range 50 {
+				entries, err = client.XRange(context.Background(), "zot-events", "-", "+").Result()
+				if err == nil && len(entries) > 0 {
+					return true
+				}
+
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			return false
+		}(), ShouldBeTrue)
+
+		So(len(entries), ShouldEqual, 1)
+		So(entries[0].Values["type"], ShouldEqual, events.RepositoryCreatedEventType.String())
+		So(entries[0].Values["source"], ShouldEqual, events.EventSource)
+		So(entries[0].Values["datacontenttype"], ShouldEqual, cloudevents.ApplicationJSON)
+		So(entries[0].Values["data"], ShouldEqual, `{"name":"alpine"}`)
+		So(entries[0].Values["id"], ShouldNotBeEmpty)
+		So(entries[0].Values["time"], ShouldNotBeEmpty)
+	})
+
+	Convey("RedisSink retries with at-least-once delivery", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		config := eventsconf.SinkConfig{
+			Type:       eventsconf.Redis,
+			Address:    miniRedis.Addr(),
+			Channel:    "zot-events",
+			Timeout:    time.Second,
+			Delivery:   eventsconf.AtLeastOnce,
+			MaxRetries: 5,
+			RetryDelay: 200 * time.Millisecond,
+		}
+
+		sink, err := events.NewRedisSink(config)
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		event := newTestEvent()
+
+		miniRedis.SetError("server unavailable")
+
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			miniRedis.SetError("")
+		}()
+
+		err = sink.Emit(event)
+		So(err, ShouldBeNil)
+
+		entries, err := miniRedis.Stream("zot-events")
+		So(err, ShouldBeNil)
+		So(len(entries), ShouldEqual, 1)
+
+		// without retries the error is returned right away
+		config.Delivery = eventsconf.AtMostOnce
+
+		atMostOnceSink, err := events.NewRedisSink(config)
+		So(err, ShouldBeNil)
+
+		defer atMostOnceSink.Close()
+
+		miniRedis.SetError("server unavailable")
+
+		err = atMostOnceSink.Emit(event)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("RedisSink trims the stream to maxLen entries", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: miniRedis.Addr(),
+			Channel: "zot-events",
+			MaxLen:  2,
+		})
+		So(err, ShouldBeNil)
+
+		defer sink.Close()
+
+		for i := range 5 {
+			event := newTestEvent()
+			event.SetID(fmt.Sprintf("test-id-%d", i))
+
+			So(sink.Emit(event), ShouldBeNil)
+		}
+
+		entries, err := miniRedis.Stream("zot-events")
+		So(err, ShouldBeNil)
+		So(len(entries), ShouldEqual, 2)
+		So(entries[1].Values, ShouldContain, "test-id-4")
+	})
+
+	Convey("RedisSink.Emit returns error for invalid event", t, func() {
+		miniRedis := miniredis.RunT(t)
+
+		sink, err := events.NewRedisSink(eventsconf.SinkConfig{
+			Type:    eventsconf.Redis,
+			Address: miniRedis.Addr(),
+			Channel: "zot-events",
+		})
+		So(err, ShouldBeNil)
+
+		event := cloudevents.NewEvent()
+		So(sink.Emit(&event), ShouldNotBeNil)
+		So(sink.Close(), ShouldBeNil)
+	})
+}
+
+func newTestEvent() *cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID("test-id")
+	event.SetSource(events.EventSource)
+	event.SetType(events.ImageUpdatedEventType.String())
+	event.SetTime(time.Now())
+	_ = event.SetData(cloudevents.ApplicationJSON, map[string]string{"name": "alpine", "reference": "latest"})
+
+	return &event
+}
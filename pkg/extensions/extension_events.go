@@ -4,14 +4,27 @@
 package extensions
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/mux"
+
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
+	rediscfg "zotregistry.dev/zot/pkg/api/config/redis"
+	zcommon "zotregistry.dev/zot/pkg/common"
 	eventsconfig "zotregistry.dev/zot/pkg/extensions/config/events"
 	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	"zotregistry.dev/zot/pkg/log"
+	sconstants "zotregistry.dev/zot/pkg/storage/constants"
 )
 
-func NewEventRecorder(config *config.Config, log log.Logger) (events.Recorder, error) {
+func NewEventRecorder(config *config.Config, metrics monitoring.MetricServer, log log.Logger,
+) (events.Recorder, error) {
 	if !config.IsEventRecorderEnabled() {
 		log.Info().Msg("events disabled in configuration")
 
@@ -26,29 +39,47 @@ func NewEventRecorder(config *config.Config, log log.Logger) (events.Recorder, e
 		return nil, zerr.ErrExtensionNotEnabled
 	}
 
-	var sinks []events.Sink
+	var (
+		sinks     []events.Sink
+		sinkNames []string
+	)
 
 	log.Info().Msg("setting up event sinks")
 
 	for _, sinkConfig := range eventConfig.Sinks {
+		var (
+			sink events.Sink
+			err  error
+		)
+
 		switch sinkConfig.Type {
 		case eventsconfig.HTTP:
-			sink, err := events.NewHTTPSink(sinkConfig)
-			if err != nil {
-				return nil, err
-			}
-
-			sinks = append(sinks, sink)
+			sink, err = events.NewHTTPSink(sinkConfig)
 		case eventsconfig.NATS:
-			sink, err := events.NewNATSSink(sinkConfig)
-			if err != nil {
-				return nil, err
-			}
-
-			sinks = append(sinks, sink)
+			sink, err = events.NewNATSSink(sinkConfig)
+		case eventsconfig.Kafka:
+			sink, err = events.NewKafkaSink(sinkConfig)
+		case eventsconfig.AMQP:
+			sink, err = events.NewAMQPSink(sinkConfig)
+		case eventsconfig.Redis:
+			sink, err = events.NewRedisSink(sinkConfig)
 		default:
 			log.Warn().Msgf("skipping unsupported sink type: %s", sinkConfig.Type)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// filtered out events are not kept in the outbox either
+		if len(sinkConfig.EventTypes) > 0 || len(sinkConfig.Repositories) > 0 {
+			sink = events.NewFilterSink(sink, sinkConfig.EventTypes, sinkConfig.Repositories)
 		}
+
+		sinks = append(sinks, sink)
+		sinkNames = append(sinkNames, getSinkName(sinkConfig, sinkNames))
 	}
 
 	if len(sinks) == 0 {
@@ -57,5 +88,188 @@ func NewEventRecorder(config *config.Config, log log.Logger) (events.Recorder, e
 		return nil, zerr.ErrExtensionNotEnabled
 	}
 
-	return events.NewRecorder(log, sinks...)
+	if !config.IsEventOutboxEnabled() {
+		return events.NewRecorder(log, sinks...)
+	}
+
+	store, err := newEventOutboxStore(config, log)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := events.NewOutbox(store, *eventConfig.Outbox, metrics, log)
+
+	for i := range sinks {
+		sinks[i] = outbox.Wrap(sinkNames[i], sinks[i])
+	}
+
+	log.Info().Msg("event outbox enabled, undelivered events will be retried")
+
+	return events.NewRecorderWithOutbox(log, outbox, sinks...)
+}
+
+// getSinkName identifies a sink in the outbox by its type, address and channel,
+// the credentials in the address are left out and an index is added if there are several such sinks.
+func getSinkName(sinkConfig eventsconfig.SinkConfig, usedNames []string) string {
+	address := sinkConfig.Address
+
+	if addressURL, err := url.Parse(address); err == nil && addressURL.User != nil {
+		addressURL.User = nil
+		address = addressURL.String()
+	}
+
+	baseName := fmt.Sprintf("%s:%s", sinkConfig.Type, address)
+	if sinkConfig.Channel != "" {
+		baseName += "/" + sinkConfig.Channel
+	}
+
+	name := baseName
+
+	for i := 2; slices.Contains(usedNames, name); i++ {
+		name = fmt.Sprintf("%s#%d", baseName, i)
+	}
+
+	return name
+}
+
+// newEventOutboxStore keeps the outbox next to the metadb: in redis if it's used as remote cache,
+// otherwise in the root directory.
+func newEventOutboxStore(config *config.Config, log log.Logger) (events.OutboxStore, error) {
+	storageConfig := config.Storage.StorageConfig
+
+	if storageConfig.RemoteCache && storageConfig.CacheDriver["name"] == sconstants.RedisDriverName {
+		client, err := rediscfg.GetRedisClient(storageConfig.CacheDriver, log)
+		if err != nil {
+			return nil, err
+		}
+
+		keyPrefix, _ := storageConfig.CacheDriver["keyprefix"].(string)
+		if keyPrefix == "" {
+			keyPrefix = "zot"
+		}
+
+		return events.NewRedisOutboxStore(client, keyPrefix), nil
+	}
+
+	if storageConfig.RemoteCache {
+		log.Warn().Interface("cachedriver", storageConfig.CacheDriver["name"]).
+			Msg("event outbox is not supported by the remote cache driver, keeping it in the root directory")
+	}
+
+	return events.NewBoltDBOutboxStore(storageConfig.RootDirectory)
+}
+
+// SetupEventsRoutes sets up the routes for listing and replaying the dead-lettered events,
+// the router is expected to only allow admins.
+func SetupEventsRoutes(conf *config.Config, router *mux.Router, recorder events.Recorder, log log.Logger) {
+	if !conf.IsEventOutboxEnabled() {
+		log.Info().Msg("skip enabling the dead letter routes as the event outbox is not enabled")
+
+		return
+	}
+
+	deadLetterQueue, ok := recorder.(events.DeadLetterQueue)
+	if !ok {
+		log.Warn().Msg("skip enabling the dead letter routes as the event recorder has no outbox")
+
+		return
+	}
+
+	log.Info().Msg("setting up dead letter routes")
+
+	handler := DeadLetters{Queue: deadLetterQueue, Log: log}
+
+	router.Methods(http.MethodGet).Path("/deadletters").HandlerFunc(handler.ListDeadLetters)
+	router.Methods(http.MethodPost).Path("/deadletters/replay").HandlerFunc(handler.ReplayDeadLetters)
+	router.Methods(http.MethodPost).Path("/deadletters/{id}/replay").HandlerFunc(handler.ReplayDeadLetter)
+
+	log.Info().Msg("finished setting up dead letter routes")
+}
+
+type DeadLetters struct {
+	Queue events.DeadLetterQueue
+	Log   log.Logger
+}
+
+type DeadLetterList struct {
+	DeadLetters []events.OutboxEntry `json:"deadLetters"`
+}
+
+type ReplayedDeadLetters struct {
+	Replayed int `json:"replayed"`
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered events
+// @Description List the events which could not be delivered to a sink after all the attempts, oldest first.
+// @Produce json
+// @Success 200 {object} extensions.DeadLetterList
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/events/deadletters  [get].
+func (dl *DeadLetters) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := dl.Queue.ListDeadLetters()
+	if err != nil {
+		dl.Log.Error().Err(err).Msg("failed to list dead-lettered events")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(w, http.StatusOK, DeadLetterList{DeadLetters: deadLetters})
+}
+
+// ReplayDeadLetters godoc
+// @Summary Replay all dead-lettered events
+// @Description Schedule all the dead-lettered events for delivery, with their attempts count reset.
+// @Produce json
+// @Success 202 {object} extensions.ReplayedDeadLetters
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/events/deadletters/replay  [post].
+func (dl *DeadLetters) ReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	replayed, err := dl.Queue.ReplayDeadLetters()
+	if err != nil {
+		dl.Log.Error().Err(err).Msg("failed to replay dead-lettered events")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(w, http.StatusAccepted, ReplayedDeadLetters{Replayed: replayed})
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay a dead-lettered event
+// @Description Schedule a dead-lettered event for delivery, with its attempts count reset.
+// @Produce json
+// @Param   id  path  string  true  "dead letter id"
+// @Success 202 {object} extensions.ReplayedDeadLetters
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "conflict"
+// @Failure 500 {string} string "internal server error"
+// @Router  /zot/admin/events/deadletters/{id}/replay  [post].
+func (dl *DeadLetters) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	err := dl.Queue.ReplayDeadLetter(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, zerr.ErrEventNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, zerr.ErrEventNotDeadLettered):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			dl.Log.Error().Err(err).Str("id", id).Msg("failed to replay dead-lettered event")
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	zcommon.WriteJSON(w, http.StatusAccepted, ReplayedDeadLetters{Replayed: 1})
 }
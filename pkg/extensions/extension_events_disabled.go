@@ -4,13 +4,17 @@
 package extensions
 
 import (
+	"github.com/gorilla/mux"
+
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	"zotregistry.dev/zot/pkg/log"
 )
 
-func NewEventRecorder(config *config.Config, log log.Logger) (events.Recorder, error) {
+func NewEventRecorder(config *config.Config, metrics monitoring.MetricServer, log log.Logger,
+) (events.Recorder, error) {
 	if !config.IsEventRecorderEnabled() {
 		log.Info().Msg("events disabled in configuration")
 
@@ -22,3 +26,8 @@ func NewEventRecorder(config *config.Config, log log.Logger) (events.Recorder, e
 
 	return nil, zerr.ErrExtensionNotEnabled
 }
+
+func SetupEventsRoutes(config *config.Config, router *mux.Router, recorder events.Recorder, log log.Logger) {
+	log.Warn().Msg("skipping setting up dead letter routes because given zot binary doesn't include this feature, " +
+		"please build a binary that does so")
+}
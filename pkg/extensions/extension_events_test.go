@@ -0,0 +1,360 @@
+//go:build events
+// +build events
+
+package extensions_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/extensions"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	eventsconf "zotregistry.dev/zot/pkg/extensions/config/events"
+	"zotregistry.dev/zot/pkg/extensions/events"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+// eventServer receives events over http, failing with 503 while it's unavailable.
+type eventServer struct {
+	lock        sync.Mutex
+	available   bool
+	eventTypes  []string
+	httpHandler http.Handler
+}
+
+func newEventServer() *eventServer {
+	server := &eventServer{}
+
+	server.httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.lock.Lock()
+		defer server.lock.Unlock()
+
+		if !server.available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		event, err := cehttp.NewEventFromHTTPRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		server.eventTypes = append(server.eventTypes, event.Type())
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return server
+}
+
+func (s *eventServer) setAvailable(available bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.available = available
+}
+
+func (s *eventServer) getEventTypes() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]string{}, s.eventTypes...)
+}
+
+func TestEventsDeadLetters(t *testing.T) {
+	Convey("Dead-lettered events can be listed and replayed by admins", t, func() {
+		eventSrv := newEventServer()
+		httpServer := httptest.NewServer(eventSrv.httpHandler)
+
+		defer httpServer.Close()
+
+		adminUser, adminPassword := "admin", "admin"
+		user, password := "user", "user"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n" +
+			test.GetCredString(user, password))
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		enable := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth.HTPasswd.Path = htpasswdPath
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				test.AuthorizationAllRepos: config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{user},
+							Actions: []string{constants.ReadPermission},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Events = &eventsconf.Config{
+			Enable: &enable,
+			Sinks: []eventsconf.SinkConfig{{
+				Type:    eventsconf.HTTP,
+				Address: httpServer.URL,
+				Timeout: time.Second,
+				// blob events are not needed
+				EventTypes: []string{"zotregistry.repository.*", "zotregistry.image.*"},
+			}},
+			Outbox: &eventsconf.OutboxConfig{
+				Enable:         &enable,
+				MaxAttempts:    2,
+				InitialBackoff: 10 * time.Millisecond,
+				Interval:       50 * time.Millisecond,
+			},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		_, err := os.Stat(path.Join(conf.Storage.RootDirectory, events.OutboxDBName))
+		So(err, ShouldBeNil)
+
+		err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "alpine", "latest", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		deadLettersURL := baseURL + constants.AdminEventsPath + "/deadletters"
+
+		getDeadLetters := func() []events.OutboxEntry {
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(deadLettersURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			deadLetters := extensions.DeadLetterList{}
+			So(json.Unmarshal(resp.Body(), &deadLetters), ShouldBeNil)
+
+			return deadLetters.DeadLetters
+		}
+
+		var deadLetters []events.OutboxEntry
+
+		for range 100 {
+			deadLetters = getDeadLetters()
+			if len(deadLetters) == 2 {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		So(len(deadLetters), ShouldEqual, 2)
+
+		eventTypes := []string{deadLetters[0].Event.Type(), deadLetters[1].Event.Type()}
+		So(eventTypes, ShouldContain, events.RepositoryCreatedEventType.String())
+		So(eventTypes, ShouldContain, events.ImageUpdatedEventType.String())
+		So(deadLetters[0].Sink, ShouldEqual, "http:"+httpServer.URL)
+		So(deadLetters[0].Attempts, ShouldEqual, 2)
+		So(deadLetters[0].LastError, ShouldNotBeEmpty)
+
+		// only admins can list and replay the dead letters
+		resp, err := resty.R().SetBasicAuth(user, password).Get(deadLettersURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetBasicAuth(user, password).Post(deadLettersURL + "/replay")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(deadLettersURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(deadLettersURL + "/unknown/replay")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		eventSrv.setAvailable(true)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).
+			Post(deadLettersURL + "/" + deadLetters[0].ID + "/replay")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		for range 100 {
+			if len(eventSrv.getEventTypes()) == 1 {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		So(eventSrv.getEventTypes(), ShouldResemble, []string{deadLetters[0].Event.Type()})
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(deadLettersURL + "/replay")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		replayed := extensions.ReplayedDeadLetters{}
+		So(json.Unmarshal(resp.Body(), &replayed), ShouldBeNil)
+		So(replayed.Replayed, ShouldEqual, 1)
+
+		for range 100 {
+			if len(eventSrv.getEventTypes()) == 2 {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		So(len(eventSrv.getEventTypes()), ShouldEqual, 2)
+		So(getDeadLetters(), ShouldBeEmpty)
+	})
+
+	Convey("Dead letter routes are not available without the outbox", t, func() {
+		eventSrv := newEventServer()
+		httpServer := httptest.NewServer(eventSrv.httpHandler)
+
+		defer httpServer.Close()
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		enable := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Events = &eventsconf.Config{
+			Enable: &enable,
+			Sinks: []eventsconf.SinkConfig{{
+				Type:    eventsconf.HTTP,
+				Address: httpServer.URL,
+			}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		_, err := os.Stat(path.Join(conf.Storage.RootDirectory, events.OutboxDBName))
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		resp, err := resty.R().Get(baseURL + constants.AdminEventsPath + "/deadletters")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestEventsFilter(t *testing.T) {
+	Convey("Sinks only get the events they subscribed to", t, func() {
+		eventSrv := newEventServer()
+		eventSrv.setAvailable(true)
+
+		httpServer := httptest.NewServer(eventSrv.httpHandler)
+
+		defer httpServer.Close()
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		enable := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Events = &eventsconf.Config{
+			Enable: &enable,
+			Sinks: []eventsconf.SinkConfig{{
+				Type:    eventsconf.HTTP,
+				Address: httpServer.URL,
+				Timeout: time.Second,
+				EventTypes: []string{
+					events.ImagePulledEventType.String(),
+					"zotregistry.signature.*",
+					"zotregistry.referrer.*",
+				},
+				Repositories: []string{"alp*"},
+			}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		So(UploadImage(image, baseURL, "alpine", "latest"), ShouldBeNil)
+		So(UploadImage(CreateRandomImage(), baseURL, "busybox", "latest"), ShouldBeNil)
+
+		resp, err := resty.R().Get(baseURL + "/v2/alpine/manifests/latest")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().Get(baseURL + "/v2/busybox/manifests/latest")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		signature := CreateRandomImageWith().Subject(image.DescriptorRef()).
+			ArtifactType(zcommon.ArtifactTypeCosign).Build()
+		So(UploadImage(signature, baseURL, "alpine", signature.DigestStr()), ShouldBeNil)
+
+		referrer := CreateRandomImageWith().Subject(image.DescriptorRef()).
+			ArtifactType("application/vnd.test.artifact").Build()
+		So(UploadImage(referrer, baseURL, "alpine", referrer.DigestStr()), ShouldBeNil)
+
+		resp, err = resty.R().Delete(baseURL + "/v2/alpine/manifests/" + signature.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		expectedEventTypes := []string{
+			events.ImagePulledEventType.String(),
+			events.SignatureAddedEventType.String(),
+			events.ReferrerAddedEventType.String(),
+			events.SignatureDeletedEventType.String(),
+		}
+
+		for range 100 {
+			if len(eventSrv.getEventTypes()) >= len(expectedEventTypes) {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		// events are published asynchronously so their order is not guaranteed
+		time.Sleep(500 * time.Millisecond)
+		So(eventSrv.getEventTypes(), ShouldHaveLength, len(expectedEventTypes))
+
+		for _, eventType := range expectedEventTypes {
+			So(eventSrv.getEventTypes(), ShouldContain, eventType)
+		}
+	})
+}
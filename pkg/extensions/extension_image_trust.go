@@ -4,20 +4,27 @@
 package extensions
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
 
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	zcommon "zotregistry.dev/zot/pkg/common"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/extensions/imagetrust"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
 	"zotregistry.dev/zot/pkg/scheduler"
 	sconstants "zotregistry.dev/zot/pkg/storage/constants"
 )
@@ -36,7 +43,7 @@ func SetupImageTrustRoutes(conf *config.Config, router *mux.Router, metaDB mType
 	log.Info().Msg("setting up image trust routes")
 
 	imgTrustStore, _ := metaDB.ImageTrustStore().(*imagetrust.ImageTrustStore)
-	trust := ImageTrust{Conf: conf, ImageTrustStore: imgTrustStore, Log: log}
+	trust := &ImageTrust{Conf: conf, ImageTrustStore: imgTrustStore, MetaDB: metaDB, Log: log}
 	allowedMethods := zcommon.AllowedMethods(http.MethodPost)
 
 	if conf.IsNotationEnabled() {
@@ -69,16 +76,24 @@ func SetupImageTrustRoutes(conf *config.Config, router *mux.Router, metaDB mType
 type ImageTrust struct {
 	Conf            *config.Config
 	ImageTrustStore *imagetrust.ImageTrustStore
+	MetaDB          mTypes.MetaDB
 	Log             log.Logger
+
+	// the validity of the signatures is checked again in the background after keys or certificates
+	// are uploaded, uploads made while it is running trigger another check once it's done
+	refreshLock    sync.Mutex
+	refreshing     bool
+	refreshPending bool
 }
 
 // Cosign handler godoc
 // @Summary Upload cosign public keys for verifying signatures
-// @Description Upload cosign public keys for verifying signatures
+// @Description Upload cosign public keys, or Fulcio certificates and Rekor public keys for keyless signatures
 // @Router   /v2/_zot/ext/cosign [post]
 // @Accept  octet-stream
 // @Produce json
-// @Param   requestBody     body     string   true   "Public key content"
+// @Param   truststoreType  query    string   false  "truststore type: fulcio or rekor, public key if not set"
+// @Param   requestBody     body     string   true   "Public key or certificate content"
 // @Success 200 {string}   string    "ok"
 // @Failure 400 {string}   string    "bad request".
 // @Failure 500 {string}   string    "internal server error".
@@ -91,9 +106,16 @@ func (trust *ImageTrust) HandleCosignPublicKeyUpload(response http.ResponseWrite
 		return
 	}
 
-	err = imagetrust.UploadPublicKey(trust.ImageTrustStore.CosignStorage, body)
+	if zcommon.QueryHasParams(request.URL.Query(), []string{"truststoreType"}) {
+		err = imagetrust.UploadTrustRoot(trust.ImageTrustStore.CosignStorage, body,
+			request.URL.Query().Get("truststoreType"))
+	} else {
+		err = imagetrust.UploadPublicKey(trust.ImageTrustStore.CosignStorage, body)
+	}
+
 	if err != nil {
-		if errors.Is(err, zerr.ErrInvalidPublicKeyContent) {
+		if errors.Is(err, zerr.ErrInvalidPublicKeyContent) || errors.Is(err, zerr.ErrInvalidTruststoreType) ||
+			errors.Is(err, zerr.ErrInvalidCertificateContent) {
 			response.WriteHeader(http.StatusBadRequest)
 		} else {
 			trust.Log.Error().Err(err).Str("component", "image-trust").Msg("failed to save cosign key")
@@ -103,6 +125,8 @@ func (trust *ImageTrust) HandleCosignPublicKeyUpload(response http.ResponseWrite
 		return
 	}
 
+	trust.refreshSignaturesValidity()
+
 	response.WriteHeader(http.StatusOK)
 }
 
@@ -147,9 +171,50 @@ func (trust *ImageTrust) HandleNotationCertificateUpload(response http.ResponseW
 		return
 	}
 
+	trust.refreshSignaturesValidity()
+
 	response.WriteHeader(http.StatusOK)
 }
 
+// refreshSignaturesValidity checks again the validity of all the signatures in the background, the signatures
+// pushed before the key or certificate which verifies them was uploaded are trusted once it's done.
+func (trust *ImageTrust) refreshSignaturesValidity() {
+	if trust.MetaDB == nil {
+		return
+	}
+
+	trust.refreshLock.Lock()
+	defer trust.refreshLock.Unlock()
+
+	trust.refreshPending = true
+
+	if trust.refreshing {
+		return
+	}
+
+	trust.refreshing = true
+
+	go func() {
+		for {
+			trust.refreshLock.Lock()
+
+			if !trust.refreshPending {
+				trust.refreshing = false
+				trust.refreshLock.Unlock()
+
+				return
+			}
+
+			trust.refreshPending = false
+			trust.refreshLock.Unlock()
+
+			if err := imagetrust.UpdateAllSignaturesValidity(context.Background(), trust.MetaDB, trust.Log); err != nil {
+				trust.Log.Error().Err(err).Str("component", "image-trust").Msg("failed to update signatures validity")
+			}
+		}
+	}()
+}
+
 func EnableImageTrustVerification(conf *config.Config, taskScheduler *scheduler.Scheduler,
 	metaDB mTypes.MetaDB, log log.Logger,
 ) {
@@ -169,7 +234,7 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 		return nil
 	}
 
-	var imgTrustStore mTypes.ImageTrustStore
+	var imgTrustStore *imagetrust.ImageTrustStore
 
 	var err error
 
@@ -193,7 +258,83 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 		}
 	}
 
+	imgTrustStore.CosignIdentities = conf.Extensions.Trust.CosignIdentities
+
 	metaDB.SetImageTrustStore(imgTrustStore)
 
 	return nil
 }
+
+// CheckImageTrustPolicy returns zerr.ErrImageNotTrusted if the policy matching repo requires its images to have
+// a trusted signature and the manifest doesn't have one, push being set for the tags which are pushed.
+// Signatures are always allowed so that clients can verify the images.
+func CheckImageTrustPolicy(ctx context.Context, conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	digest godigest.Digest, content []byte, push bool, log log.Logger,
+) error {
+	if !conf.IsImageTrustEnabled() || metaDB == nil {
+		return nil
+	}
+
+	policy, ok := getImageTrustPolicy(conf.Extensions.Trust.Policies, repo)
+	if !ok || (push && !policy.EnforceOnPush) || (!push && isImageSigner(ctx, policy.Signers)) {
+		return nil
+	}
+
+	if imagetrust.IsSignatureManifest(repo, reference, content) {
+		return nil
+	}
+
+	trusted, err := imagetrust.HasTrustedSignature(ctx, metaDB, repo, digest, policy.SignatureTypes)
+	if err != nil {
+		log.Error().Err(err).Str("component", "image-trust").Str("repository", repo).Str("digest", digest.String()).
+			Msg("failed to check the signatures of the manifest")
+
+		return err
+	}
+
+	if !trusted {
+		log.Info().Str("component", "image-trust").Str("repository", repo).Str("digest", digest.String()).
+			Bool("push", push).Msg("denied manifest without a trusted signature")
+
+		return zerr.NewError(zerr.ErrImageNotTrusted).AddDetail("digest", digest.String())
+	}
+
+	return nil
+}
+
+// isImageSigner returns true if the user making the request is one of signers, by username or group.
+func isImageSigner(ctx context.Context, signers []string) bool {
+	if len(signers) == 0 {
+		return false
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil || userAc == nil || userAc.IsAnonymous() {
+		return false
+	}
+
+	if slices.Contains(signers, userAc.GetUsername()) {
+		return true
+	}
+
+	for _, group := range userAc.GetGroups() {
+		if slices.Contains(signers, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getImageTrustPolicy returns the first policy matching repo.
+func getImageTrustPolicy(policies []extconf.ImageTrustPolicy, repo string) (extconf.ImageTrustPolicy, bool) {
+	for _, policy := range policies {
+		for _, pattern := range policy.Repositories {
+			if matched, err := glob.Match(pattern, repo); err == nil && matched {
+				return policy, true
+			}
+		}
+	}
+
+	return extconf.ImageTrustPolicy{}, false
+}
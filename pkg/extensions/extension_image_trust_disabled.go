@@ -4,7 +4,10 @@
 package extensions
 
 import (
+	"context"
+
 	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
 
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/log"
@@ -34,3 +37,9 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 
 	return nil
 }
+
+func CheckImageTrustPolicy(ctx context.Context, conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	digest godigest.Digest, content []byte, push bool, log log.Logger,
+) error {
+	return nil
+}
@@ -19,9 +19,13 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	guuid "github.com/gofrs/uuid"
+	"github.com/google/go-containerregistry/pkg/authn"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	cosigntest "github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
@@ -296,6 +300,30 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		So(err, ShouldBeNil)
 		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
 
+		rootCert, _, err := cosigntest.GenerateRootCa()
+		So(err, ShouldBeNil)
+
+		fulcioPEM, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+		So(err, ShouldBeNil)
+
+		resp, err = client.R().SetHeader("Content-type", "application/octet-stream").
+			SetQueryParam("truststoreType", "fulcio").
+			SetBody(fulcioPEM).Post(baseURL + constants.FullCosign)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = client.R().SetHeader("Content-type", "application/octet-stream").
+			SetQueryParam("truststoreType", "rekor").
+			SetBody(fulcioPEM).Post(baseURL + constants.FullCosign)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = client.R().SetHeader("Content-type", "application/octet-stream").
+			SetQueryParam("truststoreType", "signingAuthority").
+			SetBody(fulcioPEM).Post(baseURL + constants.FullCosign)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
 		resp, err = client.R().Get(baseURL + constants.FullCosign)
 		So(err, ShouldBeNil)
 		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
@@ -1028,3 +1056,166 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		So(resp.StatusCode(), ShouldEqual, http.StatusInternalServerError)
 	})
 }
+
+func TestImageTrustPolicies(t *testing.T) {
+	Convey("Verify images without a trusted signature are denied in repos matching a policy", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		defaultValue := true
+
+		testCreds := test.GetCredString("admin", "admin") + "\n" + test.GetCredString("test", "test")
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(testCreds)
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth.HTPasswd.Path = htpasswdPath
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{"test"},
+							Actions: []string{constants.ReadPermission, constants.CreatePermission},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{"admin"},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Trust = &extconf.ImageTrustConfig{}
+		conf.Extensions.Trust.Enable = &defaultValue
+		conf.Extensions.Trust.Cosign = defaultValue
+		conf.Extensions.Trust.Policies = []extconf.ImageTrustPolicy{
+			{
+				Repositories:   []string{"prod/**"},
+				SignatureTypes: []string{zcommon.CosignSignature},
+				EnforceOnPush:  true,
+				Signers:        []string{"admin"},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		client := resty.New().SetBasicAuth("test", "test")
+		adminClient := resty.New().SetBasicAuth("admin", "admin")
+
+		// repos not matching a policy are not affected
+		image := CreateRandomImage()
+		err := UploadImageWithBasicAuth(image, baseURL, "dev/app", "1.0", "test", "test")
+		So(err, ShouldBeNil)
+
+		resp, err := client.R().Get(baseURL + "/v2/dev/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// unsigned images can be pushed by digest but not tagged
+		image = CreateRandomImage()
+		err = UploadImageWithBasicAuth(image, baseURL, "prod/app", image.DigestStr(), "test", "test")
+		So(err, ShouldBeNil)
+
+		resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/" + image.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		resp, err = client.R().SetHeader("Content-type", ispec.MediaTypeImageManifest).
+			SetBody(image.ManifestDescriptor.Data).Put(baseURL + "/v2/prod/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		// signers can pull the image in order to sign it
+		resp, err = adminClient.R().Get(baseURL + "/v2/prod/app/manifests/" + image.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// generate a keypair
+		keyDir := t.TempDir()
+
+		cwd, err := os.Getwd()
+		So(err, ShouldBeNil)
+
+		_ = os.Chdir(keyDir)
+
+		os.Setenv("COSIGN_PASSWORD", "")
+		err = generate.GenerateKeyPairCmd(context.TODO(), "", "cosign", nil)
+		So(err, ShouldBeNil)
+
+		_ = os.Chdir(cwd)
+
+		// sign the image before uploading the public key
+		err = sign.SignCmd(&options.RootOptions{Verbose: true, Timeout: 1 * time.Minute},
+			options.KeyOpts{KeyRef: path.Join(keyDir, "cosign.key"), PassFunc: generate.GetPass},
+			options.SignOptions{
+				Registry: options.RegistryOptions{
+					AllowInsecure: true,
+					AuthConfig:    authn.AuthConfig{Username: "admin", Password: "admin"},
+				},
+				Upload: true,
+			},
+			[]string{fmt.Sprintf("localhost:%s/%s@%s", port, "prod/app", image.DigestStr())})
+		So(err, ShouldBeNil)
+
+		// the signature is not trusted yet
+		resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/" + image.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		// signatures can always be pulled
+		sigTag := fmt.Sprintf("%s-%s.sig", image.Digest().Algorithm(), image.Digest().Encoded())
+
+		resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/" + sigTag)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// artifacts which only look like signatures are not
+		fakeSignature := CreateMockNotationSignature(image.DescriptorRef())
+
+		err = UploadImageWithBasicAuth(fakeSignature, baseURL, "prod/app", fakeSignature.DigestStr(), "test", "test")
+		So(err, ShouldBeNil)
+
+		resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/" + fakeSignature.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		publicKeyContent, err := os.ReadFile(path.Join(keyDir, "cosign.pub"))
+		So(err, ShouldBeNil)
+
+		resp, err = adminClient.R().SetHeader("Content-type", "application/octet-stream").
+			SetBody(publicKeyContent).Post(baseURL + constants.FullCosign)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// the signature is verified again with the uploaded key in the background
+		for range 50 {
+			resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/" + image.DigestStr())
+			So(err, ShouldBeNil)
+
+			if resp.StatusCode() == http.StatusOK {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = client.R().SetHeader("Content-type", ispec.MediaTypeImageManifest).
+			SetBody(image.ManifestDescriptor.Data).Put(baseURL + "/v2/prod/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		resp, err = client.R().Get(baseURL + "/v2/prod/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+}
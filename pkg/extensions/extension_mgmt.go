@@ -6,13 +6,20 @@ package extensions
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/gorilla/mux"
 
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	zcommon "zotregistry.dev/zot/pkg/common"
 	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	rTypes "zotregistry.dev/zot/pkg/retention/types"
+	"zotregistry.dev/zot/pkg/storage"
+	"zotregistry.dev/zot/pkg/storage/gc"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 )
 
 type HTPasswd struct {
@@ -53,6 +60,19 @@ type StrippedConfig struct {
 	} `json:"http" mapstructure:"http"`
 }
 
+type RepoQuota struct {
+	Route string `json:"route"`
+	storageTypes.QuotaUsage
+}
+
+type QuotaResponse struct {
+	Quotas []RepoQuota `json:"quotas"`
+}
+
+type RetentionPreviewResponse struct {
+	Repositories []rTypes.RetentionReport `json:"repositories"`
+}
+
 func IsBuiltWithMGMTExtension() bool {
 	return true
 }
@@ -84,7 +104,9 @@ func (auth Auth) MarshalJSON() ([]byte, error) {
 	return json.Marshal((localAuth)(auth))
 }
 
-func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
+func SetupMgmtRoutes(conf *config.Config, router *mux.Router, storeController storage.StoreController,
+	metaDB mTypes.MetaDB, log log.Logger,
+) {
 	if !conf.IsMgmtEnabled() {
 		log.Info().Msg("skip enabling the mgmt route as the config prerequisites are not met")
 
@@ -93,7 +115,7 @@ func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
 
 	log.Info().Msg("setting up mgmt routes")
 
-	mgmt := Mgmt{Conf: conf, Log: log}
+	mgmt := Mgmt{Conf: conf, StoreController: storeController, MetaDB: metaDB, Log: log}
 
 	// The endpoint for reading configuration should be available to all users
 	allowedMethods := zcommon.AllowedMethods(http.MethodGet)
@@ -102,14 +124,35 @@ func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
 	mgmtRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
 	mgmtRouter.Use(zcommon.AddExtensionSecurityHeaders())
 	mgmtRouter.Use(zcommon.ACHeadersMiddleware(conf, allowedMethods...))
-	mgmtRouter.Methods(allowedMethods...).HandlerFunc(mgmt.HandleGetConfig)
+	mgmtRouter.Methods(allowedMethods...).HandlerFunc(mgmt.handler)
 
 	log.Info().Msg("finished setting up mgmt routes")
 }
 
 type Mgmt struct {
-	Conf *config.Config
-	Log  log.Logger
+	Conf            *config.Config
+	StoreController storage.StoreController
+	MetaDB          mTypes.MetaDB
+	Log             log.Logger
+}
+
+func (mgmt *Mgmt) handler(w http.ResponseWriter, r *http.Request) {
+	resource := "config" // default value of "resource" query param
+	if zcommon.QueryHasParams(r.URL.Query(), []string{"resource"}) {
+		resource = r.URL.Query().Get("resource")
+	}
+
+	switch resource {
+	case "config":
+		mgmt.HandleGetConfig(w, r)
+	case "quota":
+		mgmt.HandleGetQuota(w, r)
+	case "retention":
+		// the retention decisions list the tags of all repositories, so they are only available to admins
+		zcommon.AuthzOnlyAdminsMiddleware(mgmt.Conf)(http.HandlerFunc(mgmt.HandleGetRetention)).ServeHTTP(w, r)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
 }
 
 // mgmtHandler godoc
@@ -118,7 +161,8 @@ type Mgmt struct {
 // @Router  /v2/_zot/ext/mgmt [get]
 // @Accept  json
 // @Produce json
-// @Param   resource       query     string   false   "specify resource" Enums(config)
+// @Param   resource       query     string   false   "specify resource" Enums(config, quota, retention)
+// @Param   repository     query     string   false   "repositories to preview retention for, defaults to **"
 // @Success 200 {object}   extensions.StrippedConfig
 // @Failure 500 {string}   string   "internal server error".
 func (mgmt *Mgmt) HandleGetConfig(w http.ResponseWriter, r *http.Request) {
@@ -132,3 +176,131 @@ func (mgmt *Mgmt) HandleGetConfig(w http.ResponseWriter, r *http.Request) {
 
 	_, _ = w.Write(buf)
 }
+
+// HandleGetQuota returns the storage used by each quota policy, per storage route.
+func (mgmt *Mgmt) HandleGetQuota(w http.ResponseWriter, r *http.Request) {
+	quotaResp := QuotaResponse{Quotas: []RepoQuota{}}
+
+	imgStores := map[string]storageTypes.ImageStore{"/": mgmt.StoreController.GetDefaultImageStore()}
+	for route, imgStore := range mgmt.StoreController.GetImageSubStores() {
+		imgStores[route] = imgStore
+	}
+
+	routes := make([]string, 0, len(imgStores))
+	for route := range imgStores {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		imgStore := imgStores[route]
+		if imgStore == nil {
+			continue
+		}
+
+		usage, err := imgStore.GetQuotaUsage()
+		if err != nil {
+			mgmt.Log.Error().Err(err).Str("component", "mgmt").Str("route", route).
+				Msg("failed to get quota usage")
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		for _, quota := range usage {
+			quotaResp.Quotas = append(quotaResp.Quotas, RepoQuota{Route: route, QuotaUsage: quota})
+		}
+	}
+
+	zcommon.WriteJSON(w, http.StatusOK, quotaResp)
+}
+
+// HandleGetRetention evaluates the retention policies without removing anything and returns the tags
+// which would be retained or deleted, along with the rule which retained each tag, and the untagged manifests
+// and referrers which gc would remove.
+func (mgmt *Mgmt) HandleGetRetention(w http.ResponseWriter, r *http.Request) {
+	repoPattern := r.URL.Query().Get("repository")
+	if repoPattern == "" {
+		repoPattern = "**"
+	}
+
+	if !glob.ValidatePattern(repoPattern) {
+		mgmt.Log.Error().Err(glob.ErrBadPattern).Str("component", "mgmt").Str("pattern", repoPattern).
+			Msg("failed to preview retention, invalid repository pattern")
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	retentionResp := RetentionPreviewResponse{Repositories: []rTypes.RetentionReport{}}
+
+	imgStores := map[string]storageTypes.ImageStore{"/": mgmt.StoreController.GetDefaultImageStore()}
+	storeConfigs := map[string]config.StorageConfig{"/": mgmt.Conf.Storage.StorageConfig}
+
+	for route, imgStore := range mgmt.StoreController.GetImageSubStores() {
+		imgStores[route] = imgStore
+		storeConfigs[route] = mgmt.Conf.Storage.SubPaths[route]
+	}
+
+	routes := make([]string, 0, len(imgStores))
+	for route := range imgStores {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		imgStore := imgStores[route]
+		if imgStore == nil {
+			continue
+		}
+
+		reports, err := mgmt.getRetentionReports(r, imgStore, storeConfigs[route], repoPattern)
+		if err != nil {
+			mgmt.Log.Error().Err(err).Str("component", "mgmt").Str("route", route).
+				Msg("failed to preview retention")
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		retentionResp.Repositories = append(retentionResp.Repositories, reports...)
+	}
+
+	zcommon.WriteJSON(w, http.StatusOK, retentionResp)
+}
+
+func (mgmt *Mgmt) getRetentionReports(r *http.Request, imgStore storageTypes.ImageStore,
+	storeConfig config.StorageConfig, repoPattern string,
+) ([]rTypes.RetentionReport, error) {
+	reports := []rTypes.RetentionReport{}
+
+	repos, err := imgStore.GetRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(repos)
+
+	// the same gc which runs periodically, the untagged manifests and referrers are only listed
+	garbageCollect := gc.NewGarbageCollect(imgStore, mgmt.MetaDB, gc.Options{
+		Delay:          storeConfig.GCDelay,
+		ImageRetention: storeConfig.Retention,
+	}, nil, mgmt.Log)
+
+	for _, repo := range repos {
+		if matched, _ := glob.Match(repoPattern, repo); !matched {
+			continue
+		}
+
+		report, err := garbageCollect.GetRetentionReport(r.Context(), repo)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
@@ -8,13 +8,17 @@ import (
 
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	"zotregistry.dev/zot/pkg/storage"
 )
 
 func IsBuiltWithMGMTExtension() bool {
 	return false
 }
 
-func SetupMgmtRoutes(config *config.Config, router *mux.Router, log log.Logger) {
+func SetupMgmtRoutes(config *config.Config, router *mux.Router, storeController storage.StoreController,
+	metaDB mTypes.MetaDB, log log.Logger,
+) {
 	log.Warn().Msg("skipping setting up mgmt routes because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
 }
@@ -13,6 +13,7 @@ import (
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/api/constants"
 	zcommon "zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/extensions/search"
 	cveinfo "zotregistry.dev/zot/pkg/extensions/search/cve"
 	"zotregistry.dev/zot/pkg/extensions/search/gql_generated"
@@ -39,18 +40,20 @@ func GetCveScanner(conf *config.Config, storeController storage.StoreController,
 
 	dbRepository := conf.Extensions.Search.CVE.Trivy.DBRepository
 	javaDBRepository := conf.Extensions.Search.CVE.Trivy.JavaDBRepository
+	sbomScanMode := conf.Extensions.Search.CVE.Trivy.SbomScanMode
 
-	return cveinfo.NewScanner(storeController, metaDB, dbRepository, javaDBRepository, log)
+	return cveinfo.NewScanner(storeController, metaDB, dbRepository, javaDBRepository, sbomScanMode, log)
 }
 
 func EnableSearchExtension(conf *config.Config, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, taskScheduler *scheduler.Scheduler, cveScanner CveScanner, log log.Logger,
+	metaDB mTypes.MetaDB, taskScheduler *scheduler.Scheduler, cveScanner CveScanner, recorder events.Recorder,
+	log log.Logger,
 ) {
 	if conf.IsCveScanningEnabled() {
 		updateInterval := conf.Extensions.Search.CVE.UpdateInterval
 
 		downloadTrivyDB(updateInterval, taskScheduler, cveScanner, log)
-		startScanner(scanInterval, metaDB, taskScheduler, cveScanner, log)
+		startScanner(scanInterval, metaDB, taskScheduler, cveScanner, recorder, log)
 	} else {
 		log.Info().Msg("cve config not provided, skipping cve-db update")
 	}
@@ -64,9 +67,9 @@ func downloadTrivyDB(interval time.Duration, sch *scheduler.Scheduler, cveScanne
 }
 
 func startScanner(interval time.Duration, metaDB mTypes.MetaDB, sch *scheduler.Scheduler,
-	cveScanner CveScanner, log log.Logger,
+	cveScanner CveScanner, recorder events.Recorder, log log.Logger,
 ) {
-	generator := cveinfo.NewScanTaskGenerator(metaDB, cveScanner, log)
+	generator := cveinfo.NewScanTaskGenerator(metaDB, cveScanner, recorder, log)
 
 	log.Info().Msg("submitting cve-scan generator to scheduler")
 	sch.SubmitGenerator(generator, interval, scheduler.MediumPriority)
@@ -11,6 +11,7 @@ import (
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/extensions/sync"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
@@ -19,10 +20,14 @@ import (
 )
 
 func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
-	storeController storage.StoreController, sch *scheduler.Scheduler, log log.Logger,
+	storeController storage.StoreController, sch *scheduler.Scheduler, recorder events.Recorder, log log.Logger,
 ) (*sync.BaseOnDemand, error) {
 	if config.Extensions.Sync != nil && *config.Extensions.Sync.Enable {
-		onDemand := sync.NewOnDemand(log)
+		onDemand := sync.NewOnDemand(recorder, log)
+
+		tmpDir := config.Extensions.Sync.DownloadDir
+		credsPath := config.Extensions.Sync.CredentialsFile
+		clusterCfg := config.Cluster
 
 		for _, registryConfig := range config.Extensions.Sync.Registries {
 			registryConfig := registryConfig
@@ -45,10 +50,6 @@ func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
 				continue
 			}
 
-			tmpDir := config.Extensions.Sync.DownloadDir
-			credsPath := config.Extensions.Sync.CredentialsFile
-			clusterCfg := config.Cluster
-
 			service, err := sync.New(registryConfig, credsPath, clusterCfg, tmpDir, storeController, metaDB, log)
 			if err != nil {
 				log.Error().Err(err).Msg("failed to initialize sync extension")
@@ -60,7 +61,7 @@ func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
 				// add to task scheduler periodic sync
 				interval := registryConfig.PollInterval
 
-				gen := sync.NewTaskGenerator(service, interval, log)
+				gen := sync.NewTaskGenerator(service, interval, recorder, log)
 				sch.SubmitGenerator(gen, interval, scheduler.MediumPriority)
 			}
 
@@ -70,6 +71,13 @@ func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
 			}
 		}
 
+		// proxy cache is tried last, after the registries explicitly configured
+		proxyCacheConfig := config.Extensions.Sync.ProxyCache
+		if proxyCacheConfig != nil && proxyCacheConfig.Enable != nil && *proxyCacheConfig.Enable {
+			onDemand.Add(sync.NewProxyCache(*proxyCacheConfig, credsPath, clusterCfg, tmpDir,
+				storeController, metaDB, log))
+		}
+
 		return onDemand, nil
 	}
 
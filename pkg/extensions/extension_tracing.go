@@ -0,0 +1,30 @@
+//go:build tracing
+// +build tracing
+
+package extensions
+
+import (
+	"context"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+// EnableTracingExtension sets up the exporter of spans, the returned function flushes pending spans
+// and has to be called on shutdown.
+func EnableTracingExtension(config *config.Config, log log.Logger) (func(context.Context) error, error) {
+	if !config.IsTracingEnabled() {
+		log.Info().Msg("tracing config not provided, skipping tracing setup")
+
+		return nil, zerr.ErrExtensionNotEnabled
+	}
+
+	provider, err := tracing.NewTracerProvider(*config.Extensions.Tracing, config.Commit, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,22 @@
+//go:build !tracing
+// +build !tracing
+
+package extensions
+
+import (
+	"context"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+// EnableTracingExtension ...
+func EnableTracingExtension(config *config.Config, log log.Logger) (func(context.Context) error, error) {
+	if config.IsTracingEnabled() {
+		log.Warn().Msg("skipping enabling tracing extension because given zot binary doesn't include this feature, " +
+			"please build a binary that does so")
+	}
+
+	return nil, zerr.ErrExtensionNotEnabled
+}
@@ -0,0 +1,219 @@
+//go:build tracing && search
+// +build tracing,search
+
+package extensions_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/cluster"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+// collector is a stand-in for an OTLP/HTTP collector, keeping all the spans it receives.
+type collector struct {
+	lock  sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var request coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	c.lock.Lock()
+	for _, resourceSpans := range request.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			c.spans = append(c.spans, scopeSpans.GetSpans()...)
+		}
+	}
+	c.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) spansByName() map[string][]*tracepb.Span {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	spans := map[string][]*tracepb.Span{}
+	for _, span := range c.spans {
+		spans[span.GetName()] = append(spans[span.GetName()], span)
+	}
+
+	return spans
+}
+
+func TestTracingExtension(t *testing.T) {
+	Convey("Verify spans of a push are exported to the OTLP collector", t, func() {
+		otlpCollector := &collector{}
+		collectorServer := httptest.NewServer(otlpCollector)
+
+		defer collectorServer.Close()
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		defaultValue := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &defaultValue},
+		}
+		conf.Extensions.Tracing = &extconf.TracingConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &defaultValue},
+			Endpoint:   collectorServer.URL,
+			Service:    "zot-test",
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+
+		err := UploadImage(CreateRandomImage(), baseURL, "alpine", "latest")
+		So(err, ShouldBeNil)
+
+		// pending spans are flushed on shutdown
+		ctlrManager.StopServer()
+
+		spans := otlpCollector.spansByName()
+
+		manifestSpans := spans["PUT /v2/{name}/manifests/{reference}"]
+		So(manifestSpans, ShouldHaveLength, 1)
+
+		manifestSpan := manifestSpans[0]
+
+		So(spans["POST /v2/{name}/blobs/uploads/"], ShouldNotBeEmpty)
+		So(spans["PUT /v2/{name}/blobs/uploads/{session_id}"], ShouldNotBeEmpty)
+
+		// storage, lock and metaDB spans are children of the request span
+		for _, name := range []string{
+			"imagestore.Lock", "imagestore.Lint", "driver.WriteFile", "metadb.SetRepoReference",
+		} {
+			So(spans[name], ShouldNotBeEmpty)
+
+			found := false
+
+			for _, span := range spans[name] {
+				if string(span.GetParentSpanId()) == string(manifestSpan.GetSpanId()) {
+					So(string(span.GetTraceId()), ShouldEqual, string(manifestSpan.GetTraceId()))
+
+					found = true
+				}
+			}
+
+			So(found, ShouldBeTrue)
+		}
+	})
+
+	Convey("Verify the trace of a request continues on the cluster member it is proxied to", t, func() {
+		otlpCollector := &collector{}
+		collectorServer := httptest.NewServer(otlpCollector)
+
+		defer collectorServer.Close()
+
+		const hashKey = "loremipsumdolors"
+
+		ports := []string{test.GetFreePort(), test.GetFreePort()}
+		members := []string{"127.0.0.1:" + ports[0], "127.0.0.1:" + ports[1]}
+
+		defaultValue := true
+
+		managers := make([]test.ControllerManager, 0, len(ports))
+
+		for _, port := range ports {
+			conf := config.New()
+			conf.HTTP.Port = port
+			conf.Storage.RootDirectory = t.TempDir()
+			conf.Cluster = &config.ClusterConfig{
+				Members: members,
+				HashKey: hashKey,
+			}
+			conf.Extensions = &extconf.ExtensionConfig{}
+			conf.Extensions.Tracing = &extconf.TracingConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultValue},
+				Endpoint:   collectorServer.URL,
+			}
+
+			ctlrManager := test.NewControllerManager(api.NewController(conf))
+			ctlrManager.StartAndWait(port)
+
+			managers = append(managers, ctlrManager)
+		}
+
+		// find a repo served by the second member
+		var repo string
+
+		for _, candidate := range []string{"alpine", "debian", "ubuntu", "busybox", "fedora", "centos"} {
+			if targetIdx, _ := cluster.ComputeTargetMember(hashKey, members, candidate); targetIdx == 1 {
+				repo = candidate
+
+				break
+			}
+		}
+
+		So(repo, ShouldNotBeEmpty)
+
+		err := UploadImage(CreateRandomImage(), test.GetBaseURL(ports[0]), repo, "latest")
+		So(err, ShouldBeNil)
+
+		for _, ctlrManager := range managers {
+			ctlrManager.StopServer()
+		}
+
+		spans := otlpCollector.spansByName()
+
+		proxySpans := map[string]*tracepb.Span{}
+		for _, span := range spans["cluster.proxy"] {
+			proxySpans[string(span.GetSpanId())] = span
+		}
+
+		So(proxySpans, ShouldNotBeEmpty)
+
+		proxiedManifestPuts := 0
+
+		for _, span := range spans["PUT /v2/{name}/manifests/{reference}"] {
+			if proxySpan, ok := proxySpans[string(span.GetParentSpanId())]; ok {
+				So(string(span.GetTraceId()), ShouldEqual, string(proxySpan.GetTraceId()))
+
+				proxiedManifestPuts++
+			}
+		}
+
+		So(proxiedManifestPuts, ShouldEqual, 1)
+	})
+}
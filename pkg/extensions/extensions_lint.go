@@ -9,7 +9,7 @@ import (
 	"zotregistry.dev/zot/pkg/log"
 )
 
-func GetLinter(config *config.Config, log log.Logger) *lint.Linter {
+func GetLinter(config *config.Config, log log.Logger) (*lint.Linter, error) {
 	if config.Extensions == nil {
 		return lint.NewLinter(nil, log)
 	}
@@ -9,9 +9,9 @@ import (
 	"zotregistry.dev/zot/pkg/log"
 )
 
-func GetLinter(config *config.Config, log log.Logger) *lint.Linter {
+func GetLinter(config *config.Config, log log.Logger) (*lint.Linter, error) {
 	log.Warn().Msg("lint extension is disabled because given zot binary doesn't " +
 		"include this feature please build a binary that does so")
 
-	return nil
+	return nil, nil //nolint: nilnil
 }
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	godigest "github.com/opencontainers/go-digest"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
@@ -23,6 +24,7 @@ import (
 	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
 	authutils "zotregistry.dev/zot/pkg/test/auth"
 	test "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
 )
 
 const (
@@ -1076,3 +1078,239 @@ func TestAllowedMethodsHeaderMgmt(t *testing.T) {
 		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
 	})
 }
+
+func TestMgmtRetentionPreview(t *testing.T) {
+	Convey("Preview the retention policies", t, func() {
+		adminUser, adminPassword := "admin", "admin"
+		user, password := "user", "user"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n" +
+			test.GetCredString(user, password))
+		defer os.Remove(htpasswdPath)
+
+		defaultValue := true
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth.HTPasswd.Path = htpasswdPath
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{constants.ReadPermission, constants.CreatePermission},
+			},
+		}
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.GC = false
+		conf.Storage.Retention = config.ImageRetention{
+			Policies: []config.RetentionPolicy{
+				{
+					Repositories:    []string{"infra/**"},
+					DeleteReferrers: true,
+					KeepTags: []config.KeepTagsPolicy{
+						{Patterns: []string{"v1.*"}},
+					},
+				},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultValue
+		conf.Extensions.Search.CVE = nil
+		conf.Extensions.UI = &extconf.UIConfig{}
+		conf.Extensions.UI.Enable = &defaultValue
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		for _, ref := range []struct{ repo, tag string }{{"infra/app", "v1.0"}, {"infra/app", "v2.0"}, {"other", "latest"}} {
+			err := UploadImageWithBasicAuth(image, baseURL, ref.repo, ref.tag, adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		// the image of a tag which is removed, its referrer and an untagged image are removed by gc afterwards
+		removedImage := CreateRandomImage()
+		referrer := CreateRandomImageWith().Subject(removedImage.DescriptorRef()).Build()
+		untaggedImage := CreateRandomImage()
+
+		err := UploadImageWithBasicAuth(removedImage, baseURL, "infra/app", "v3.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		for _, img := range []Image{referrer, untaggedImage} {
+			err = UploadImageWithBasicAuth(img, baseURL, "infra/app", img.DigestStr(), adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		previewURL := baseURL + constants.FullMgmt + "?resource=retention"
+
+		// only admins can preview the retention policies
+		resp, err := resty.R().SetBasicAuth(user, password).Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(previewURL + "&repository=" +
+			url.QueryEscape("["))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		preview := extensions.RetentionPreviewResponse{}
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(len(preview.Repositories), ShouldEqual, 2)
+		So(preview.Repositories[0].Repository, ShouldEqual, "infra/app")
+		So(preview.Repositories[1].Repository, ShouldEqual, "other")
+		So(len(preview.Repositories[1].Tags), ShouldEqual, 1)
+		So(preview.Repositories[1].Tags[0].Decision, ShouldEqual, "keep")
+		So(preview.Repositories[1].Manifests, ShouldBeEmpty)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(previewURL + "&repository=" +
+			url.QueryEscape("infra/*"))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		preview = extensions.RetentionPreviewResponse{}
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(len(preview.Repositories), ShouldEqual, 1)
+
+		decisions := map[string]string{}
+		for _, tag := range preview.Repositories[0].Tags {
+			if tag.Tag == "v3.0" {
+				So(tag.Digest, ShouldEqual, removedImage.DigestStr())
+			} else {
+				So(tag.Digest, ShouldEqual, image.DigestStr())
+			}
+
+			decisions[tag.Tag] = tag.Decision + ": " + tag.Reason
+		}
+
+		So(decisions, ShouldResemble, map[string]string{
+			"v1.0": "keep: retained by patterns policy",
+			"v2.0": "delete: didn't meet any tag 'patterns' rules",
+			"v3.0": "delete: didn't meet any tag 'patterns' rules",
+		})
+
+		decisions = map[string]string{}
+		for _, manifest := range preview.Repositories[0].Manifests {
+			decisions[manifest.Digest] = manifest.Decision + ": " + manifest.Reason
+		}
+
+		// the image of the removed tag is left untagged, then its referrer has no subject anymore
+		So(decisions, ShouldResemble, map[string]string{
+			removedImage.DigestStr():  "delete: deleteUntagged",
+			referrer.DigestStr():      "delete: deleteReferrers",
+			untaggedImage.DigestStr(): "delete: deleteUntagged",
+		})
+
+		// nothing was removed
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(baseURL + "/v2/infra/app/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(string(resp.Body()), ShouldContainSubstring, "v2.0")
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).
+			Get(baseURL + "/v2/infra/app/manifests/" + untaggedImage.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+}
+
+func TestMgmtQuota(t *testing.T) {
+	Convey("Quota usage and denied pushes", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		image := CreateRandomImage()
+		imageSize := int64(image.Size())
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.Dedupe = true
+		conf.Storage.Quota = config.StorageQuota{
+			Policies: []config.QuotaPolicy{
+				{
+					Repositories: []string{"team/**"},
+					SoftLimit:    imageSize / 2,
+					HardLimit:    imageSize,
+				},
+				{
+					Repositories:  []string{"ci/**"},
+					HardLimit:     1,
+					PerRepository: true,
+				},
+			},
+		}
+
+		defaultValue := true
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultValue
+		conf.Extensions.Search.CVE = nil
+		conf.Extensions.UI = &extconf.UIConfig{}
+		conf.Extensions.UI.Enable = &defaultValue
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImage(image, baseURL, "team/a", "1.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(image, baseURL, "other", "1.0")
+		So(err, ShouldBeNil)
+
+		// pushes going over the hard limit are denied
+		content := []byte("this blob goes over the quota")
+		digest := godigest.FromBytes(content)
+
+		resp, err := resty.R().Post(baseURL + "/v2/team/b/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		resp, err = resty.R().SetQueryParam("digest", digest.String()).
+			SetHeader("Content-Type", "application/octet-stream").SetBody(content).
+			Put(baseURL + resp.Header().Get("Location"))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		// and so is linking a deduped blob
+		resp, err = resty.R().SetQueryParam("mount", image.Manifest.Layers[0].Digest.String()).
+			Post(baseURL + "/v2/ci/app/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		resp, err = resty.R().Get(baseURL + constants.FullMgmt + "?resource=quota")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		quotas := extensions.QuotaResponse{}
+		err = json.Unmarshal(resp.Body(), &quotas)
+		So(err, ShouldBeNil)
+		So(quotas.Quotas, ShouldHaveLength, 1)
+		So(quotas.Quotas[0].Route, ShouldEqual, "/")
+		So(quotas.Quotas[0].Repositories, ShouldResemble, []string{"team/**"})
+		So(quotas.Quotas[0].SoftLimit, ShouldEqual, imageSize/2)
+		So(quotas.Quotas[0].HardLimit, ShouldEqual, imageSize)
+		So(quotas.Quotas[0].Used, ShouldEqual, imageSize)
+	})
+}
@@ -40,6 +40,8 @@ type publicKeyStorage interface {
 	StorePublicKey(name godigest.Digest, publicKeyContent []byte) error
 	GetPublicKeyVerifier(name string) (sigstoreSigs.Verifier, []byte, error)
 	GetPublicKeys() ([]string, error)
+	StoreTrustRoot(name godigest.Digest, content []byte, truststoreType string) error
+	GetTrustRoots(truststoreType string) ([][]byte, error)
 }
 
 func NewPublicKeyLocalStorage(rootDir string) (*PublicKeyLocalStorage, error) {
@@ -190,6 +192,11 @@ func (local *PublicKeyLocalStorage) GetPublicKeys() ([]string, error) {
 
 	publicKeys := []string{}
 	for _, file := range files {
+		// keyless trust roots are stored in subdirectories
+		if file.IsDir() {
+			continue
+		}
+
 		publicKeys = append(publicKeys, file.Name())
 	}
 
@@ -0,0 +1,281 @@
+//go:build imagetrust
+// +build imagetrust
+
+package imagetrust
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+
+	zerr "zotregistry.dev/zot/errors"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+)
+
+const (
+	// Fulcio root and intermediate certificates, PEM encoded.
+	CosignTruststoreFulcio = "fulcio"
+	// Rekor public keys, PEM encoded.
+	CosignTruststoreRekor = "rekor"
+)
+
+func cosignTruststoreDescription(truststoreType string) string {
+	return "cosign " + truststoreType + " trust root"
+}
+
+// VerifyCosignKeylessSignature verifies signatures made with short-lived certificates issued by the uploaded Fulcio
+// roots to one of identities. The Rekor bundle attached to the signature must be signed by one of the uploaded Rekor
+// keys, its integrated time proving the certificate was valid when signing, so no network access is needed.
+func VerifyCosignKeylessSignature(cosignStorage publicKeyStorage, identities []extconf.CosignIdentity,
+	digest godigest.Digest, layerInfo mTypes.LayerInfo,
+) (string, bool, error) {
+	if len(identities) == 0 || layerInfo.Certificate == "" || layerInfo.Bundle == "" {
+		return "", false, nil
+	}
+
+	checkOpts, err := getCosignKeylessCheckOpts(cosignStorage, identities)
+	if err != nil || checkOpts == nil {
+		return "", false, err
+	}
+
+	var rekorBundle bundle.RekorBundle
+
+	if err := json.Unmarshal([]byte(layerInfo.Bundle), &rekorBundle); err != nil {
+		return "", false, nil //nolint:nilerr // the signature is not trusted
+	}
+
+	signature, err := static.NewSignature(layerInfo.LayerContent, layerInfo.SignatureKey,
+		static.WithCertChain([]byte(layerInfo.Certificate), []byte(layerInfo.CertificateChain)),
+		static.WithBundle(&rekorBundle))
+	if err != nil {
+		return "", false, err
+	}
+
+	hash, err := v1.NewHash(digest.String())
+	if err != nil {
+		return "", false, err
+	}
+
+	if _, err := cosign.VerifyImageSignature(context.Background(), signature, hash, checkOpts); err != nil {
+		return "", false, nil //nolint:nilerr // the signature is not trusted
+	}
+
+	cert, err := signature.Cert()
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.Join(cryptoutils.GetSubjectAlternateNames(cert), ","), true, nil
+}
+
+// getCosignKeylessCheckOpts returns nil if the Fulcio or Rekor trust roots were not uploaded.
+func getCosignKeylessCheckOpts(cosignStorage publicKeyStorage, identities []extconf.CosignIdentity,
+) (*cosign.CheckOpts, error) {
+	fulcioCerts, err := cosignStorage.GetTrustRoots(CosignTruststoreFulcio)
+	if err != nil {
+		return nil, err
+	}
+
+	rekorKeys, err := cosignStorage.GetTrustRoots(CosignTruststoreRekor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fulcioCerts) == 0 || len(rekorKeys) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	roots := x509.NewCertPool()
+
+	var intermediates *x509.CertPool
+
+	for _, content := range fulcioCerts {
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM(content)
+		if err != nil {
+			continue
+		}
+
+		for _, cert := range certs {
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+				roots.AddCert(cert)
+
+				continue
+			}
+
+			// the chain attached to the signature is used if no intermediate certificate was uploaded
+			if intermediates == nil {
+				intermediates = x509.NewCertPool()
+			}
+
+			intermediates.AddCert(cert)
+		}
+	}
+
+	rekorPubKeys := cosign.NewTrustedTransparencyLogPubKeys()
+
+	for _, content := range rekorKeys {
+		_ = rekorPubKeys.AddTransparencyLogPubKey(content, tuf.Active)
+	}
+
+	cosignIdentities := make([]cosign.Identity, 0, len(identities))
+
+	for _, identity := range identities {
+		cosignIdentities = append(cosignIdentities, cosign.Identity{
+			Subject:       identity.Subject,
+			SubjectRegExp: identity.SubjectRegExp,
+			Issuer:        identity.Issuer,
+			IssuerRegExp:  identity.IssuerRegExp,
+		})
+	}
+
+	return &cosign.CheckOpts{
+		RootCerts:         roots,
+		IntermediateCerts: intermediates,
+		RekorPubKeys:      &rekorPubKeys,
+		Identities:        cosignIdentities,
+		ClaimVerifier:     cosign.SimpleClaimVerifier,
+		// certificate transparency logs can't be checked offline
+		IgnoreSCT: true,
+		Offline:   true,
+	}, nil
+}
+
+func UploadTrustRoot(cosignStorage publicKeyStorage, content []byte, truststoreType string) error {
+	switch truststoreType {
+	case CosignTruststoreFulcio:
+		if _, err := cryptoutils.UnmarshalCertificatesFromPEM(content); err != nil {
+			return fmt.Errorf("%w: %w", zerr.ErrInvalidCertificateContent, err)
+		}
+	case CosignTruststoreRekor:
+		publicKey, err := cryptoutils.UnmarshalPEMToPublicKey(content)
+		if err != nil {
+			return fmt.Errorf("%w: %w", zerr.ErrInvalidPublicKeyContent, err)
+		}
+
+		// rekor bundles are verified with ecdsa keys only
+		if _, ok := publicKey.(*ecdsa.PublicKey); !ok {
+			return fmt.Errorf("%w: rekor public keys must be ecdsa keys", zerr.ErrInvalidPublicKeyContent)
+		}
+	default:
+		return zerr.ErrInvalidTruststoreType
+	}
+
+	return cosignStorage.StoreTrustRoot(godigest.FromBytes(content), content, truststoreType)
+}
+
+func (local *PublicKeyLocalStorage) StoreTrustRoot(name godigest.Digest, content []byte, truststoreType string,
+) error {
+	// add trust root to "{rootDir}/_cosign/{truststoreType}/{name}"
+	cosignDir, err := local.GetCosignDirPath()
+	if err != nil {
+		return err
+	}
+
+	truststoreDir := path.Join(cosignDir, truststoreType)
+
+	if err := os.MkdirAll(truststoreDir, defaultDirPerms); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(truststoreDir, name.String()), content, defaultFilePerms)
+}
+
+func (cloud *PublicKeyAWSStorage) StoreTrustRoot(name godigest.Digest, content []byte, truststoreType string,
+) error {
+	n := truststoreType + "-" + name.Encoded()
+	description := cosignTruststoreDescription(truststoreType)
+	secret := base64.StdEncoding.EncodeToString(content)
+	secretInputParam := &secretsmanager.CreateSecretInput{
+		Name:         &n,
+		Description:  &description,
+		SecretString: &secret,
+	}
+
+	_, err := cloud.secretsManagerClient.CreateSecret(context.Background(), secretInputParam)
+	if err != nil && IsResourceExistsException(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (local *PublicKeyLocalStorage) GetTrustRoots(truststoreType string) ([][]byte, error) {
+	cosignDir, err := local.GetCosignDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	truststoreDir := path.Join(cosignDir, truststoreType)
+
+	files, err := os.ReadDir(truststoreDir)
+	if os.IsNotExist(err) {
+		return [][]byte{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	trustRoots := [][]byte{}
+
+	for _, file := range files {
+		content, err := os.ReadFile(path.Join(truststoreDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		trustRoots = append(trustRoots, content)
+	}
+
+	return trustRoots, nil
+}
+
+func (cloud *PublicKeyAWSStorage) GetTrustRoots(truststoreType string) ([][]byte, error) {
+	listSecretsInput := secretsmanager.ListSecretsInput{
+		Filters: []types.Filter{
+			{
+				Key:    types.FilterNameStringTypeDescription,
+				Values: []string{cosignTruststoreDescription(truststoreType)},
+			},
+		},
+	}
+
+	secrets, err := cloud.secretsManagerClient.ListSecrets(context.Background(), &listSecretsInput)
+	if err != nil {
+		return nil, err
+	}
+
+	trustRoots := [][]byte{}
+
+	for _, secret := range secrets.SecretList {
+		raw, err := cloud.secretsManagerCache.GetSecretString(*(secret.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		trustRoots = append(trustRoots, content)
+	}
+
+	return trustRoots, nil
+}
@@ -24,6 +24,7 @@ import (
 
 	zerr "zotregistry.dev/zot/errors"
 	zcommon "zotregistry.dev/zot/pkg/common"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/scheduler"
@@ -37,6 +38,8 @@ const (
 type ImageTrustStore struct {
 	CosignStorage   publicKeyStorage
 	NotationStorage certificateStorage
+	// identities allowed to sign images keyless
+	CosignIdentities []extconf.CosignIdentity
 }
 
 type SecretsManagerClient interface {
@@ -158,7 +161,7 @@ func IsResourceExistsException(err error) bool {
 }
 
 func (imgTrustStore *ImageTrustStore) VerifySignature(
-	signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
+	signatureType string, layerInfo mTypes.LayerInfo, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
 	repo string,
 ) (mTypes.Author, mTypes.ExpiryDate, mTypes.Validity, error) {
 	desc := ispec.Descriptor{
@@ -173,16 +176,44 @@ func (imgTrustStore *ImageTrustStore) VerifySignature(
 
 	switch signatureType {
 	case zcommon.CosignSignature:
-		author, isValid, err := VerifyCosignSignature(imgTrustStore.CosignStorage, repo, manifestDigest, sigKey, rawSignature)
+		if layerInfo.Certificate != "" {
+			author, isValid, err := VerifyCosignKeylessSignature(imgTrustStore.CosignStorage, imgTrustStore.CosignIdentities,
+				manifestDigest, layerInfo)
+
+			return author, time.Time{}, isValid, err
+		}
+
+		author, isValid, err := VerifyCosignSignature(imgTrustStore.CosignStorage, repo, manifestDigest,
+			layerInfo.SignatureKey, layerInfo.LayerContent)
 
 		return author, time.Time{}, isValid, err
 	case zcommon.NotationSignature:
-		return VerifyNotationSignature(imgTrustStore.NotationStorage, desc, manifestDigest.String(), rawSignature, sigKey)
+		return VerifyNotationSignature(imgTrustStore.NotationStorage, desc, manifestDigest.String(),
+			layerInfo.LayerContent, layerInfo.SignatureKey)
 	default:
 		return "", time.Time{}, false, zerr.ErrInvalidSignatureType
 	}
 }
 
+// UpdateAllSignaturesValidity checks again the validity of the signatures of all the repositories,
+// eg. after a key or certificate was uploaded.
+func UpdateAllSignaturesValidity(ctx context.Context, metaDB mTypes.MetaDB, log log.Logger) error {
+	repos, err := metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMeta) bool {
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		if err := NewValidityTask(metaDB, repo, log).DoWork(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func NewTaskGenerator(metaDB mTypes.MetaDB, log log.Logger) scheduler.TaskGenerator {
 	return &sigValidityTaskGenerator{
 		repos:     []mTypes.RepoMeta{},
@@ -22,7 +22,7 @@ func NewAWSImageTrustStore(region, endpoint string) (*imageTrustDisabled, error)
 type imageTrustDisabled struct{}
 
 func (imgTrustStore *imageTrustDisabled) VerifySignature(
-	signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
+	signatureType string, layerInfo mTypes.LayerInfo, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
 	repo string,
 ) (string, time.Time, bool, error) {
 	return "", time.Time{}, false, nil
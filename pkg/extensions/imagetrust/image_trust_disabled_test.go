@@ -10,6 +10,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"zotregistry.dev/zot/pkg/extensions/imagetrust"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
 )
 
@@ -33,7 +34,7 @@ func TestImageTrust(t *testing.T) {
 		So(err, ShouldBeNil)
 
 		author, expTime, ok, err := localImgTrustStore.VerifySignature("cosign",
-			[]byte(""), "", image.Digest(), image.AsImageMeta(), repo,
+			mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), repo,
 		)
 		So(author, ShouldBeEmpty)
 		So(expTime, ShouldBeZeroValue)
@@ -52,7 +53,7 @@ func TestImageTrust(t *testing.T) {
 		So(err, ShouldBeNil)
 
 		author, expTime, ok, err = cloudImgTrustStore.VerifySignature("cosign",
-			[]byte(""), "", image.Digest(), image.AsImageMeta(), repo,
+			mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), repo,
 		)
 		So(author, ShouldBeEmpty)
 		So(expTime, ShouldBeZeroValue)
@@ -5,7 +5,14 @@ package imagetrust_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,6 +38,8 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	cosigntest "github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
@@ -41,6 +50,7 @@ import (
 	zcommon "zotregistry.dev/zot/pkg/common"
 	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/extensions/imagetrust"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	test "zotregistry.dev/zot/pkg/test/common"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
 	"zotregistry.dev/zot/pkg/test/mocks"
@@ -155,7 +165,7 @@ func TestVerifySignatures(t *testing.T) {
 		image := CreateRandomImage()
 
 		imgTrustStore := &imagetrust.ImageTrustStore{}
-		_, _, _, err := imgTrustStore.VerifySignature("", []byte(""), "", "", image.AsImageMeta(), "repo")
+		_, _, _, err := imgTrustStore.VerifySignature("", mTypes.LayerInfo{}, "", image.AsImageMeta(), "repo")
 		So(err, ShouldNotBeNil)
 		So(err, ShouldEqual, zerr.ErrBadSignatureManifestDigest)
 	})
@@ -164,8 +174,8 @@ func TestVerifySignatures(t *testing.T) {
 		image := CreateRandomImage()
 
 		imgTrustStore := &imagetrust.ImageTrustStore{}
-		_, _, _, err := imgTrustStore.VerifySignature("wrongType", []byte(""), "", image.Digest(), image.AsImageMeta(),
-			"repo")
+		_, _, _, err := imgTrustStore.VerifySignature("wrongType",
+			mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), "repo")
 		So(err, ShouldNotBeNil)
 		So(err, ShouldEqual, zerr.ErrInvalidSignatureType)
 	})
@@ -181,7 +191,8 @@ func TestVerifySignatures(t *testing.T) {
 				CosignStorage: &imagetrust.PublicKeyLocalStorage{},
 			}
 
-			_, _, _, err := imgTrustStore.VerifySignature("cosign", []byte(""), "", image.Digest(), image.AsImageMeta(), repo)
+			_, _, _, err := imgTrustStore.VerifySignature("cosign",
+				mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 			So(err, ShouldEqual, zerr.ErrSignConfigDirNotSet)
 		})
@@ -201,7 +212,8 @@ func TestVerifySignatures(t *testing.T) {
 				CosignStorage: pubKeyStorage,
 			}
 
-			_, _, _, err = imgTrustStore.VerifySignature("cosign", []byte(""), "", image.Digest(), image.AsImageMeta(), repo)
+			_, _, _, err = imgTrustStore.VerifySignature("cosign",
+				mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 		})
 
@@ -221,8 +233,8 @@ func TestVerifySignatures(t *testing.T) {
 				CosignStorage: pubKeyStorage,
 			}
 
-			_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", []byte(""), "", image.Digest(), image.AsImageMeta(),
-				repo)
+			_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign",
+				mTypes.LayerInfo{}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldBeNil)
 			So(isTrusted, ShouldBeFalse)
 		})
@@ -312,7 +324,8 @@ func TestVerifySignatures(t *testing.T) {
 			}
 
 			// signature is trusted
-			author, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", rawSignature, sigKey, image.Digest(),
+			author, _, isTrusted, err := imgTrustStore.VerifySignature("cosign",
+				mTypes.LayerInfo{LayerContent: rawSignature, SignatureKey: sigKey}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldBeNil)
 			So(isTrusted, ShouldBeTrue)
@@ -330,8 +343,8 @@ func TestVerifySignatures(t *testing.T) {
 				NotationStorage: &imagetrust.CertificateLocalStorage{},
 			}
 
-			_, _, _, err := imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-				image.AsImageMeta(), repo)
+			_, _, _, err := imgTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 			So(err, ShouldEqual, zerr.ErrSignConfigDirNotSet)
 		})
@@ -346,7 +359,7 @@ func TestVerifySignatures(t *testing.T) {
 				NotationStorage: certStorage,
 			}
 
-			_, _, isTrusted, err := imgTrustStore.VerifySignature("notation", []byte(""), "", image.Digest(),
+			_, _, isTrusted, err := imgTrustStore.VerifySignature("notation", mTypes.LayerInfo{}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 			So(isTrusted, ShouldBeFalse)
@@ -367,8 +380,8 @@ func TestVerifySignatures(t *testing.T) {
 				NotationStorage: certStorage,
 			}
 
-			_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-				image.AsImageMeta(), repo)
+			_, _, _, err = imgTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 		})
 
@@ -389,8 +402,8 @@ func TestVerifySignatures(t *testing.T) {
 				NotationStorage: certStorage,
 			}
 
-			_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-				image.AsImageMeta(), repo)
+			_, _, _, err = imgTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 		})
 
@@ -495,7 +508,8 @@ func TestVerifySignatures(t *testing.T) {
 			}
 
 			// signature is trusted
-			author, _, isTrusted, err := imgTrustStore.VerifySignature("notation", rawSignature, sigKey, image.Digest(),
+			author, _, isTrusted, err := imgTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: rawSignature, SignatureKey: sigKey}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldBeNil)
 			So(isTrusted, ShouldBeTrue)
@@ -505,7 +519,8 @@ func TestVerifySignatures(t *testing.T) {
 			So(err, ShouldBeNil)
 
 			// signature is not trusted
-			author, _, isTrusted, err = imgTrustStore.VerifySignature("notation", rawSignature, sigKey, image.Digest(),
+			author, _, isTrusted, err = imgTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: rawSignature, SignatureKey: sigKey}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldNotBeNil)
 			So(isTrusted, ShouldBeFalse)
@@ -514,6 +529,192 @@ func TestVerifySignatures(t *testing.T) {
 	})
 }
 
+func TestVerifyCosignKeylessSignatures(t *testing.T) {
+	Convey("verify keyless cosign signatures", t, func() {
+		repo := "repo"
+		subject := "release@example.com"
+		issuer := "https://issuer.example.com"
+
+		image := CreateRandomImage()
+
+		rootCert, rootKey, err := cosigntest.GenerateRootCa()
+		So(err, ShouldBeNil)
+
+		subCert, subKey, err := cosigntest.GenerateSubordinateCa(rootCert, rootKey)
+		So(err, ShouldBeNil)
+
+		leafCert, leafKey, err := cosigntest.GenerateLeafCert(subject, issuer, subCert, subKey)
+		So(err, ShouldBeNil)
+
+		rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		fulcioPEM, err := cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{subCert, rootCert})
+		So(err, ShouldBeNil)
+
+		rekorPEM, err := cryptoutils.MarshalPublicKeyToPEM(rekorKey.Public())
+		So(err, ShouldBeNil)
+
+		layerInfo := signKeyless(image.Digest(), leafCert, leafKey, []*x509.Certificate{subCert, rootCert}, rekorKey)
+
+		imgTrustStore, err := imagetrust.NewLocalImageTrustStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		imgTrustStore.CosignIdentities = []extconf.CosignIdentity{{Subject: subject, Issuer: issuer}}
+
+		Convey("trust roots are not uploaded", func() {
+			_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, image.Digest(),
+				image.AsImageMeta(), repo)
+			So(err, ShouldBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("trust roots are uploaded", func() {
+			err := imagetrust.UploadTrustRoot(imgTrustStore.CosignStorage, fulcioPEM, imagetrust.CosignTruststoreFulcio)
+			So(err, ShouldBeNil)
+
+			err = imagetrust.UploadTrustRoot(imgTrustStore.CosignStorage, rekorPEM, imagetrust.CosignTruststoreRekor)
+			So(err, ShouldBeNil)
+
+			author, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, image.Digest(),
+				image.AsImageMeta(), repo)
+			So(err, ShouldBeNil)
+			So(isTrusted, ShouldBeTrue)
+			So(author, ShouldEqual, subject)
+
+			Convey("identity doesn't match", func() {
+				imgTrustStore.CosignIdentities = []extconf.CosignIdentity{
+					{SubjectRegExp: "^.*@other.example.com$", Issuer: issuer},
+					{Subject: subject, IssuerRegExp: "^https://other.example.com$"},
+				}
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, image.Digest(),
+					image.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+
+			Convey("no identities are configured", func() {
+				imgTrustStore.CosignIdentities = nil
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, image.Digest(),
+					image.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+
+			Convey("signature of another manifest", func() {
+				otherImage := CreateRandomImage()
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, otherImage.Digest(),
+					otherImage.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+
+			Convey("certificate issued by another CA", func() {
+				otherRootCert, otherRootKey, err := cosigntest.GenerateRootCa()
+				So(err, ShouldBeNil)
+
+				otherLeafCert, otherLeafKey, err := cosigntest.GenerateLeafCert(subject, issuer, otherRootCert, otherRootKey)
+				So(err, ShouldBeNil)
+
+				otherLayerInfo := signKeyless(image.Digest(), otherLeafCert, otherLeafKey,
+					[]*x509.Certificate{otherRootCert}, rekorKey)
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", otherLayerInfo, image.Digest(),
+					image.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+
+			Convey("bundle signed by another rekor key", func() {
+				otherRekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				So(err, ShouldBeNil)
+
+				otherLayerInfo := signKeyless(image.Digest(), leafCert, leafKey,
+					[]*x509.Certificate{subCert, rootCert}, otherRekorKey)
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", otherLayerInfo, image.Digest(),
+					image.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+
+			Convey("signature without bundle", func() {
+				layerInfo.Bundle = ""
+
+				_, _, isTrusted, err := imgTrustStore.VerifySignature("cosign", layerInfo, image.Digest(),
+					image.AsImageMeta(), repo)
+				So(err, ShouldBeNil)
+				So(isTrusted, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+// signKeyless mimics cosign keyless signing, with leafCert issued by a Fulcio CA and a Rekor bundle signed
+// by rekorKey.
+func signKeyless(manifestDigest digest.Digest, leafCert *x509.Certificate, leafKey *ecdsa.PrivateKey,
+	chain []*x509.Certificate, rekorKey *ecdsa.PrivateKey,
+) mTypes.LayerInfo {
+	payload := fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"localhost/repo"},`+
+		`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		manifestDigest)
+
+	payloadHash := sha256.Sum256([]byte(payload))
+
+	rawSignature, err := ecdsa.SignASN1(rand.Reader, leafKey, payloadHash[:])
+	So(err, ShouldBeNil)
+
+	b64Signature := base64.StdEncoding.EncodeToString(rawSignature)
+
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(leafCert)
+	So(err, ShouldBeNil)
+
+	chainPEM, err := cryptoutils.MarshalCertificatesToPEM(chain)
+	So(err, ShouldBeNil)
+
+	entry := fmt.Sprintf(`{"apiVersion":"0.0.1","kind":"hashedrekord","spec":{`+
+		`"data":{"hash":{"algorithm":"sha256","value":"%s"}},`+
+		`"signature":{"content":"%s","publicKey":{"content":"%s"}}}}`,
+		hex.EncodeToString(payloadHash[:]), b64Signature, base64.StdEncoding.EncodeToString(certPEM))
+
+	rekorPubKey, err := x509.MarshalPKIXPublicKey(rekorKey.Public())
+	So(err, ShouldBeNil)
+
+	logID := sha256.Sum256(rekorPubKey)
+	body := base64.StdEncoding.EncodeToString([]byte(entry))
+	integratedTime := time.Now().Unix()
+
+	// canonical json of the bundle payload, with sorted keys
+	setPayload := fmt.Sprintf(`{"body":"%s","integratedTime":%d,"logID":"%s","logIndex":1}`,
+		body, integratedTime, hex.EncodeToString(logID[:]))
+	setHash := sha256.Sum256([]byte(setPayload))
+
+	set, err := ecdsa.SignASN1(rand.Reader, rekorKey, setHash[:])
+	So(err, ShouldBeNil)
+
+	bundle, err := json.Marshal(map[string]interface{}{
+		"SignedEntryTimestamp": set,
+		"Payload": map[string]interface{}{
+			"body":           body,
+			"integratedTime": integratedTime,
+			"logIndex":       1,
+			"logID":          hex.EncodeToString(logID[:]),
+		},
+	})
+	So(err, ShouldBeNil)
+
+	return mTypes.LayerInfo{
+		LayerContent:     []byte(payload),
+		SignatureKey:     b64Signature,
+		Certificate:      string(certPEM),
+		CertificateChain: string(chainPEM),
+		Bundle:           string(bundle),
+	}
+}
+
 func TestCheckExpiryErr(t *testing.T) {
 	Convey("no expiry err", t, func() {
 		isExpiryErr := imagetrust.CheckExpiryErr([]*notation.ValidationResult{{Error: nil, Type: "wrongtype"}}, time.Now(),
@@ -1010,8 +1211,8 @@ func TestAWSTrustStore(t *testing.T) {
 			NotationStorage: notationStorage,
 		}
 
-		_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-			image.AsImageMeta(), repo)
+		_, _, _, err = imgTrustStore.VerifySignature("notation",
+			mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 		So(err, ShouldNotBeNil)
 	})
 
@@ -1040,8 +1241,8 @@ func TestAWSTrustStore(t *testing.T) {
 			NotationStorage: notationStorage,
 		}
 
-		_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-			image.AsImageMeta(), repo)
+		_, _, _, err = imgTrustStore.VerifySignature("notation",
+			mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 		So(err, ShouldNotBeNil)
 
 		secretsManagerCacheMock = mocks.SecretsManagerCacheMock{
@@ -1057,8 +1258,8 @@ func TestAWSTrustStore(t *testing.T) {
 			NotationStorage: notationStorage,
 		}
 
-		_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-			image.AsImageMeta(), repo)
+		_, _, _, err = imgTrustStore.VerifySignature("notation",
+			mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 		So(err, ShouldNotBeNil)
 
 		secretsManagerCacheMock = mocks.SecretsManagerCacheMock{
@@ -1074,8 +1275,8 @@ func TestAWSTrustStore(t *testing.T) {
 			NotationStorage: notationStorage,
 		}
 
-		_, _, _, err = imgTrustStore.VerifySignature("notation", []byte("signature"), "", image.Digest(),
-			image.AsImageMeta(), repo)
+		_, _, _, err = imgTrustStore.VerifySignature("notation",
+			mTypes.LayerInfo{LayerContent: []byte("signature")}, image.Digest(), image.AsImageMeta(), repo)
 		So(err, ShouldNotBeNil)
 	})
 
@@ -1150,6 +1351,48 @@ func RunUploadTests(t *testing.T, imageTrustStore imagetrust.ImageTrustStore) {
 		So(err, ShouldBeNil)
 	})
 
+	Convey("keyless trust roots - invalid truststore type", func() {
+		err := imagetrust.UploadTrustRoot(cosignStorage, []byte("content"), "wrongType")
+		So(err, ShouldEqual, zerr.ErrInvalidTruststoreType)
+	})
+
+	Convey("keyless trust roots - invalid content", func() {
+		err := imagetrust.UploadTrustRoot(cosignStorage, []byte("wrong content"), imagetrust.CosignTruststoreFulcio)
+		So(err, ShouldWrap, zerr.ErrInvalidCertificateContent)
+
+		err = imagetrust.UploadTrustRoot(cosignStorage, []byte("wrong content"), imagetrust.CosignTruststoreRekor)
+		So(err, ShouldWrap, zerr.ErrInvalidPublicKeyContent)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+
+		rsaPEM, err := cryptoutils.MarshalPublicKeyToPEM(rsaKey.Public())
+		So(err, ShouldBeNil)
+
+		err = imagetrust.UploadTrustRoot(cosignStorage, rsaPEM, imagetrust.CosignTruststoreRekor)
+		So(err, ShouldWrap, zerr.ErrInvalidPublicKeyContent)
+	})
+
+	Convey("upload keyless trust roots successfully", func() {
+		rootCert, _, err := cosigntest.GenerateRootCa()
+		So(err, ShouldBeNil)
+
+		fulcioPEM, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+		So(err, ShouldBeNil)
+
+		err = imagetrust.UploadTrustRoot(cosignStorage, fulcioPEM, imagetrust.CosignTruststoreFulcio)
+		So(err, ShouldBeNil)
+
+		rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		rekorPEM, err := cryptoutils.MarshalPublicKeyToPEM(rekorKey.Public())
+		So(err, ShouldBeNil)
+
+		err = imagetrust.UploadTrustRoot(cosignStorage, rekorPEM, imagetrust.CosignTruststoreRekor)
+		So(err, ShouldBeNil)
+	})
+
 	Convey("invalid truststore type", func() {
 		err := imagetrust.UploadCertificate(notationStorage,
 			[]byte("certificate content"), "wrongType",
@@ -1325,7 +1568,8 @@ func RunVerificationTests(t *testing.T, dbDriverParams map[string]interface{}) {
 			imageTrustStore := ctlr.MetaDB.ImageTrustStore()
 
 			// signature is trusted
-			author, _, isTrusted, err := imageTrustStore.VerifySignature("cosign", rawSignature, sigKey, image.Digest(),
+			author, _, isTrusted, err := imageTrustStore.VerifySignature("cosign",
+				mTypes.LayerInfo{LayerContent: rawSignature, SignatureKey: sigKey}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldBeNil)
 			So(isTrusted, ShouldBeTrue)
@@ -1430,7 +1674,8 @@ func RunVerificationTests(t *testing.T, dbDriverParams map[string]interface{}) {
 			imageTrustStore := ctlr.MetaDB.ImageTrustStore()
 
 			// signature is trusted
-			author, _, isTrusted, err := imageTrustStore.VerifySignature("notation", rawSignature, sigKey, image.Digest(),
+			author, _, isTrusted, err := imageTrustStore.VerifySignature("notation",
+				mTypes.LayerInfo{LayerContent: rawSignature, SignatureKey: sigKey}, image.Digest(),
 				image.AsImageMeta(), repo)
 			So(err, ShouldBeNil)
 			So(isTrusted, ShouldBeTrue)
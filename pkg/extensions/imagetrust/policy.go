@@ -0,0 +1,107 @@
+//go:build imagetrust
+// +build imagetrust
+
+package imagetrust
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	cosignTypes "github.com/sigstore/cosign/v2/pkg/types"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/meta/common"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	"zotregistry.dev/zot/pkg/storage"
+)
+
+// IsSignatureManifest returns true if the manifest is a cosign or notation signature, which the image trust
+// policies don't apply to so that clients can verify the images. Manifests only classified as signatures
+// by their artifact type or tag are not enough, all their layers have to be signature envelopes,
+// otherwise any artifact could be pulled without a trusted signature.
+func IsSignatureManifest(repo, reference string, content []byte) bool {
+	isSignature, signatureType, _, err := storage.CheckIsImageSignature(repo, content, reference)
+	if err != nil || !isSignature {
+		return false
+	}
+
+	var manifest ispec.Manifest
+
+	if err := json.Unmarshal(content, &manifest); err != nil || len(manifest.Layers) == 0 {
+		return false
+	}
+
+	signatureMediaTypes := []string{cosignTypes.SimpleSigningMediaType}
+	if signatureType == storage.NotationType {
+		signatureMediaTypes = []string{jws.MediaTypeEnvelope, cose.MediaTypeEnvelope}
+	}
+
+	for _, layer := range manifest.Layers {
+		if !slices.Contains(signatureMediaTypes, layer.MediaType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasTrustedSignature returns true if the manifest, or an index referencing it, has a signature of one of
+// signatureTypes (all types if empty) verified with the uploaded cosign keys or notation certificates.
+// The stored validity of the signatures is used, it is checked again when signatures are pushed,
+// when keys or certificates are uploaded and periodically.
+func HasTrustedSignature(ctx context.Context, metaDB mTypes.MetaDB, repo string, digest godigest.Digest,
+	signatureTypes []string,
+) (bool, error) {
+	repoMeta, err := metaDB.GetRepoMeta(ctx, repo)
+	if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, signedDigest := range getSignedDigests(repoMeta, digest, metaDB) {
+		if common.HasTrustedSignature(repoMeta.Signatures[signedDigest.String()], signatureTypes) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getSignedDigests returns the digests of the signed manifest and indexes which cover the manifest.
+func getSignedDigests(repoMeta mTypes.RepoMeta, digest godigest.Digest, metaDB mTypes.MetaDB) []godigest.Digest {
+	signedDigests := []godigest.Digest{}
+
+	for signedDigest, signatures := range repoMeta.Signatures {
+		if len(signatures) == 0 {
+			continue
+		}
+
+		if signedDigest == digest.String() {
+			signedDigests = append(signedDigests, digest)
+
+			continue
+		}
+
+		imageMeta, err := metaDB.GetImageMeta(godigest.Digest(signedDigest))
+		if err != nil || imageMeta.Index == nil {
+			continue
+		}
+
+		for _, manifest := range imageMeta.Index.Manifests {
+			if manifest.Digest == digest {
+				signedDigests = append(signedDigests, godigest.Digest(signedDigest))
+
+				break
+			}
+		}
+	}
+
+	return signedDigests
+}
@@ -0,0 +1,228 @@
+//go:build lint
+// +build lint
+
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/extensions/config"
+	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+const (
+	defaultAdmissionWebhookTimeout = 10 * time.Second
+	// webhook responses bigger than this are considered invalid.
+	maxAdmissionReviewSize = 1 << 20
+)
+
+// AdmissionRequest is the body POSTed to the admission webhooks.
+type AdmissionRequest struct {
+	Repository string          `json:"repository"`
+	Reference  string          `json:"reference"`
+	Digest     string          `json:"digest"`
+	MediaType  string          `json:"mediaType"`
+	Manifest   json.RawMessage `json:"manifest"`
+	// image config, only set for image manifests
+	Config json.RawMessage `json:"config,omitempty"`
+	User   AdmissionUser   `json:"user"`
+}
+
+// AdmissionUser is the authenticated identity pushing the manifest, the username is empty for anonymous pushes.
+type AdmissionUser struct {
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// AdmissionResponse is the review returned by the admission webhooks.
+type AdmissionResponse struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type admissionWebhook struct {
+	config config.AdmissionWebhookConfig
+	client *http.Client
+}
+
+func newAdmissionWebhooks(lintConfig *config.LintConfig) ([]admissionWebhook, error) {
+	if lintConfig == nil {
+		return nil, nil
+	}
+
+	webhooks := make([]admissionWebhook, 0, len(lintConfig.AdmissionWebhooks))
+
+	for _, webhookConfig := range lintConfig.AdmissionWebhooks {
+		client, err := common.CreateHTTPClient(&common.HTTPClientOptions{
+			TLSEnabled:  webhookConfig.CACertFile != "",
+			VerifyTLS:   true,
+			CertOptions: common.HTTPClientCertOptions{RootCaCertFile: webhookConfig.CACertFile},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the client of admission webhook %s: %w", webhookConfig.Name, err)
+		}
+
+		client.Timeout = webhookConfig.Timeout
+		if client.Timeout == 0 {
+			client.Timeout = defaultAdmissionWebhookTimeout
+		}
+
+		webhooks = append(webhooks, admissionWebhook{config: webhookConfig, client: client})
+	}
+
+	return webhooks, nil
+}
+
+// Admit asks the admission webhooks matching repo whether the manifest can be pushed,
+// the push is denied as soon as one of them denies it.
+func (linter *Linter) Admit(ctx context.Context, repo, reference, mediaType string, manifest []byte,
+	imgStore storageTypes.ImageStore,
+) error {
+	if linter.config == nil || linter.config.Enable == nil || !*linter.config.Enable || len(linter.webhooks) == 0 {
+		return nil
+	}
+
+	// invalid manifests are rejected by storage
+	if !json.Valid(manifest) {
+		return nil
+	}
+
+	var review *AdmissionRequest
+
+	for _, webhook := range linter.webhooks {
+		if !matchesRepository(webhook.config.Repositories, repo) {
+			continue
+		}
+
+		if review == nil {
+			review = newAdmissionRequest(ctx, repo, reference, mediaType, manifest, imgStore)
+		}
+
+		response, err := webhook.review(ctx, review)
+		if err != nil {
+			if webhook.config.FailOpen {
+				linter.log.Warn().Err(err).Str("component", "linter").Str("webhook", webhook.config.Name).
+					Str("repository", repo).Str("reference", reference).
+					Msg("admission webhook failed, allowing the push")
+
+				continue
+			}
+
+			linter.log.Error().Err(err).Str("component", "linter").Str("webhook", webhook.config.Name).
+				Str("repository", repo).Str("reference", reference).
+				Msg("admission webhook failed, denying the push")
+
+			return zerr.NewError(zerr.ErrAdmissionDenied).AddDetail("webhook", webhook.config.Name).
+				AddDetail("reason", err.Error())
+		}
+
+		if !response.Allowed {
+			linter.log.Info().Str("component", "linter").Str("webhook", webhook.config.Name).
+				Str("repository", repo).Str("reference", reference).Str("reason", response.Reason).
+				Msg("admission webhook denied the push")
+
+			return zerr.NewError(zerr.ErrAdmissionDenied).AddDetail("webhook", webhook.config.Name).
+				AddDetail("reason", response.Reason)
+		}
+	}
+
+	return nil
+}
+
+func (webhook admissionWebhook) review(ctx context.Context, review *AdmissionRequest) (AdmissionResponse, error) {
+	var response AdmissionResponse
+
+	body, err := json.Marshal(review)
+	if err != nil {
+		return response, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return response, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := webhook.client.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return response, fmt.Errorf("%w: unexpected status code %d", zerr.ErrAdmissionWebhookFailed, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAdmissionReviewSize)).Decode(&response); err != nil {
+		return response, fmt.Errorf("%w: %w", zerr.ErrAdmissionWebhookFailed, err)
+	}
+
+	return response, nil
+}
+
+func newAdmissionRequest(ctx context.Context, repo, reference, mediaType string, manifest []byte,
+	imgStore storageTypes.ImageStore,
+) *AdmissionRequest {
+	review := &AdmissionRequest{
+		Repository: repo,
+		Reference:  reference,
+		Digest:     godigest.FromBytes(manifest).String(),
+		MediaType:  mediaType,
+		Manifest:   manifest,
+		User:       AdmissionUser{Groups: []string{}},
+	}
+
+	if userAc, err := reqCtx.UserAcFromContext(ctx); err == nil && userAc != nil {
+		review.User.Username = userAc.GetUsername()
+
+		if groups := userAc.GetGroups(); groups != nil {
+			review.User.Groups = groups
+		}
+	}
+
+	if mediaType != ispec.MediaTypeImageManifest {
+		return review
+	}
+
+	var imageManifest ispec.Manifest
+	if err := json.Unmarshal(manifest, &imageManifest); err != nil ||
+		imageManifest.Config.MediaType != ispec.MediaTypeImageConfig {
+		return review
+	}
+
+	// a missing config is reported by storage once the webhooks allowed the push
+	content, err := imgStore.GetBlobContent(repo, imageManifest.Config.Digest)
+	if err == nil && json.Valid(content) {
+		review.Config = content
+	}
+
+	return review
+}
+
+func matchesRepository(patterns []string, repo string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, err := glob.Match(pattern, repo); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -13,19 +13,36 @@ import (
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 )
 
 type Linter struct {
-	config *config.LintConfig
-	log    log.Logger
+	config   *config.LintConfig
+	webhooks []admissionWebhook
+	metaDB   mTypes.MetaDB
+	log      log.Logger
 }
 
-func NewLinter(config *config.LintConfig, log log.Logger) *Linter {
-	return &Linter{
-		config: config,
-		log:    log,
+func NewLinter(config *config.LintConfig, log log.Logger) (*Linter, error) {
+	webhooks, err := newAdmissionWebhooks(config)
+	if err != nil {
+		log.Error().Err(err).Str("component", "linter").Msg("failed to create admission webhooks")
+
+		return nil, err
 	}
+
+	return &Linter{
+		config:   config,
+		webhooks: webhooks,
+		log:      log,
+	}, nil
+}
+
+// SetMetaDB gives the linter the images hosted by the registry, the forbiddenBaseImages rule
+// matching the layers of the pushed images against them.
+func (linter *Linter) SetMetaDB(metaDB mTypes.MetaDB) {
+	linter.metaDB = metaDB
 }
 
 func (linter *Linter) CheckMandatoryAnnotations(repo string, manifestDigest godigest.Digest,
@@ -48,6 +65,11 @@ func (linter *Linter) CheckMandatoryAnnotations(repo string, manifestDigest godi
 		return false, err
 	}
 
+	// mandatory annotations are only checked on image manifests
+	if isImageIndex(content) {
+		return true, nil
+	}
+
 	var manifest ispec.Manifest
 
 	if err := json.Unmarshal(content, &manifest); err != nil {
@@ -114,7 +136,12 @@ func (linter *Linter) CheckMandatoryAnnotations(repo string, manifestDigest godi
 func (linter *Linter) Lint(repo string, manifestDigest godigest.Digest,
 	imageStore storageTypes.ImageStore,
 ) (bool, error) {
-	return linter.CheckMandatoryAnnotations(repo, manifestDigest, imageStore)
+	pass, err := linter.CheckMandatoryAnnotations(repo, manifestDigest, imageStore)
+	if !pass {
+		return pass, err
+	}
+
+	return linter.CheckRules(repo, manifestDigest, imageStore)
 }
 
 func getMissingAnnotations(mandatoryAnnotationsMap map[string]bool) []string {
@@ -4,15 +4,26 @@
 package lint
 
 import (
+	"context"
+
 	godigest "github.com/opencontainers/go-digest"
 
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 )
 
 type Linter struct{}
 
+func (linter *Linter) SetMetaDB(metaDB mTypes.MetaDB) {}
+
 func (linter *Linter) Lint(repo string, manifestDigest godigest.Digest,
 	imageStore storageTypes.ImageStore,
 ) (bool, error) {
 	return true, nil
 }
+
+func (linter *Linter) Admit(ctx context.Context, repo, reference, mediaType string, manifest []byte,
+	imgStore storageTypes.ImageStore,
+) error {
+	return nil
+}
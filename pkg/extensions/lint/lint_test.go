@@ -4,28 +4,36 @@
 package lint_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
 	"testing"
+	"time"
 
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api"
 	"zotregistry.dev/zot/pkg/api/config"
 	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/extensions/lint"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	"zotregistry.dev/zot/pkg/log"
+	"zotregistry.dev/zot/pkg/meta/boltdb"
+	"zotregistry.dev/zot/pkg/storage"
 	"zotregistry.dev/zot/pkg/storage/local"
 	test "zotregistry.dev/zot/pkg/test/common"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
+	"zotregistry.dev/zot/pkg/test/mocks"
 	ociutils "zotregistry.dev/zot/pkg/test/oci-utils"
 )
 
@@ -487,7 +495,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		var index ispec.Index
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -519,7 +529,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		var index ispec.Index
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -589,7 +601,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		index.Manifests = append(index.Manifests, manifestDesc)
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -651,7 +665,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		index.Manifests = append(index.Manifests, manifestDesc)
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -715,7 +731,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		index.Manifests = append(index.Manifests, manifestDesc)
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -778,7 +796,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		index.Manifests = append(index.Manifests, manifestDesc)
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -876,7 +896,9 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 
 		index.Manifests = append(index.Manifests, manifestDesc)
 
-		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
 		imgStore := local.NewImageStore(dir, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil, nil, nil)
 
@@ -899,3 +921,380 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		So(pass, ShouldBeTrue)
 	})
 }
+
+func TestAdmissionWebhooks(t *testing.T) {
+	Convey("Admission webhooks veto manifest pushes", t, func() {
+		reviews := make(chan lint.AdmissionRequest, 10)
+
+		webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var review lint.AdmissionRequest
+
+			if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			reviews <- review
+
+			response := lint.AdmissionResponse{Allowed: true}
+			if review.Reference == "forbidden" {
+				response = lint.AdmissionResponse{Allowed: false, Reason: "forbidden tag"}
+			}
+
+			_ = json.NewEncoder(w).Encode(response)
+		}))
+		defer webhookServer.Close()
+
+		// nothing listens on this port
+		unreachableURL := test.GetBaseURL(test.GetFreePort()) + "/admit"
+
+		username, password := "alice", "secret"
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(username, password))
+
+		defer os.Remove(htpasswdPath)
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		enable := true
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{Lint: &extconf.LintConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enable},
+			AdmissionWebhooks: []extconf.AdmissionWebhookConfig{
+				{Name: "policy", URL: webhookServer.URL, Repositories: []string{"prod/**"}},
+				{Name: "closed", URL: unreachableURL, Repositories: []string{"closed"}, Timeout: time.Second},
+				{Name: "open", URL: unreachableURL, Repositories: []string{"open"}, Timeout: time.Second, FailOpen: true},
+			},
+		}}
+
+		ctlr := api.NewController(conf)
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		img := CreateRandomImage()
+
+		Convey("Allowed push", func() {
+			err := UploadImageWithBasicAuth(img, baseURL, "prod/app", "1.0", username, password)
+			So(err, ShouldBeNil)
+
+			review := <-reviews
+			So(review.Repository, ShouldEqual, "prod/app")
+			So(review.Reference, ShouldEqual, "1.0")
+			So(review.Digest, ShouldEqual, img.Digest().String())
+			So(review.MediaType, ShouldEqual, ispec.MediaTypeImageManifest)
+			So(review.User.Username, ShouldEqual, username)
+
+			resp, err := resty.R().SetBasicAuth(username, password).Get(baseURL + "/v2/prod/app/manifests/1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var imageConfig ispec.Image
+
+			err = json.Unmarshal(review.Config, &imageConfig)
+			So(err, ShouldBeNil)
+			So(imageConfig, ShouldResemble, img.Config)
+
+			var manifest ispec.Manifest
+
+			err = json.Unmarshal(review.Manifest, &manifest)
+			So(err, ShouldBeNil)
+			So(manifest.Config.Digest, ShouldEqual, img.ConfigDescriptor.Digest)
+		})
+
+		Convey("Denied push", func() {
+			// the helper doesn't check the status of the manifest push
+			err := UploadImageWithBasicAuth(img, baseURL, "prod/app", "forbidden", username, password)
+			So(err, ShouldBeNil)
+
+			review := <-reviews
+			So(review.Reference, ShouldEqual, "forbidden")
+
+			resp, err := resty.R().SetBasicAuth(username, password).
+				Get(baseURL + "/v2/prod/app/manifests/forbidden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			content, err := json.Marshal(img.Manifest)
+			So(err, ShouldBeNil)
+
+			resp, err = resty.R().SetBasicAuth(username, password).
+				SetHeader("Content-Type", ispec.MediaTypeImageManifest).SetBody(content).
+				Put(baseURL + "/v2/prod/app/manifests/forbidden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+			So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+			So(string(resp.Body()), ShouldContainSubstring, "forbidden tag")
+		})
+
+		Convey("Repositories not matching the webhooks are not checked", func() {
+			err := UploadImageWithBasicAuth(img, baseURL, "dev/app", "forbidden", username, password)
+			So(err, ShouldBeNil)
+			So(reviews, ShouldBeEmpty)
+		})
+
+		Convey("Unreachable fail-closed webhook denies the push", func() {
+			err := UploadImageWithBasicAuth(img, baseURL, "closed", "1.0", username, password)
+			So(err, ShouldBeNil)
+
+			content, err := json.Marshal(img.Manifest)
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth(username, password).
+				SetHeader("Content-Type", ispec.MediaTypeImageManifest).SetBody(content).
+				Put(baseURL + "/v2/closed/manifests/1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+			So(string(resp.Body()), ShouldContainSubstring, "closed")
+		})
+
+		Convey("Unreachable fail-open webhook allows the push", func() {
+			err := UploadImageWithBasicAuth(img, baseURL, "open", "1.0", username, password)
+			So(err, ShouldBeNil)
+		})
+	})
+
+	Convey("Admission webhooks with an invalid ca certificate", t, func() {
+		enable := true
+
+		lintConfig := &extconf.LintConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enable},
+			AdmissionWebhooks: []extconf.AdmissionWebhookConfig{
+				{Name: "policy", URL: "https://policy.example.com/admit", CACertFile: path.Join(t.TempDir(), "ca.crt")},
+			},
+		}
+
+		linter, err := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+		So(linter, ShouldBeNil)
+	})
+}
+
+func TestLintRules(t *testing.T) {
+	Convey("Lint rules", t, func() {
+		enable := true
+		lintConfig := &extconf.LintConfig{BaseConfig: extconf.BaseConfig{Enable: &enable}}
+
+		var lintErrors []error
+
+		recorder := mocks.EventRecorderMock{
+			ImageLintFailedFn: func(name, reference, digest, mediaType, manifest string, lintErr error) {
+				lintErrors = append(lintErrors, lintErr)
+			},
+		}
+
+		logger := log.NewLogger("debug", "")
+		linter, err := lint.NewLinter(lintConfig, logger)
+		So(err, ShouldBeNil)
+
+		imgStore := local.NewImageStore(t.TempDir(), false, false, logger,
+			monitoring.NewMetricsServer(false, logger), linter, nil, nil, recorder)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		Convey("Max layers", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Type: extconf.LintRuleMaxLayers, Repositories: []string{"prod/**"}, MaxLayers: 2},
+			}
+
+			image := CreateImageWith().RandomLayers(3, 10).DefaultConfig().Build()
+
+			err := WriteImageToFileSystem(image, "prod/app", "1.0", storeController)
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, zerr.ErrImageLintAnnotations), ShouldBeTrue)
+			So(errors.Is(err, zerr.ErrImageLintMaxLayers), ShouldBeTrue)
+			So(zerr.GetDetails(err), ShouldResemble, map[string]string{
+				"rule": extconf.LintRuleMaxLayers, "layers": "3", "maxLayers": "2",
+			})
+
+			So(lintErrors, ShouldHaveLength, 1)
+			So(errors.Is(lintErrors[0], zerr.ErrImageLintMaxLayers), ShouldBeTrue)
+
+			// rules only apply to the repositories matching their patterns
+			err = WriteImageToFileSystem(image, "dev/app", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			image = CreateImageWith().RandomLayers(2, 10).DefaultConfig().Build()
+
+			err = WriteImageToFileSystem(image, "prod/app", "1.0", storeController)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Max size", func() {
+			image := CreateImageWith().RandomLayers(2, 100).DefaultConfig().Build()
+
+			lintConfig.Rules = []extconf.LintRule{{Type: extconf.LintRuleMaxSize, MaxSize: 200}}
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(errors.Is(err, zerr.ErrImageLintMaxSize), ShouldBeTrue)
+			So(zerr.GetDetails(err)["maxSize"], ShouldEqual, "200")
+
+			lintConfig.Rules = []extconf.LintRule{{Type: extconf.LintRuleMaxSize, MaxSize: 1000}}
+
+			err = WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Required architectures", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Type: extconf.LintRuleRequiredArchitectures, Architectures: []string{"amd64", "arm64"}},
+			}
+
+			amd64Image := CreateImageWith().RandomLayers(1, 10).PlatformConfig("amd64", "linux").Build()
+			arm64Image := CreateImageWith().RandomLayers(1, 10).PlatformConfig("arm64", "linux").Build()
+
+			// the rule doesn't apply to the images of the index
+			multiarch := CreateMultiarchWith().Images([]Image{amd64Image}).Build()
+
+			err := WriteMultiArchImageToFileSystem(multiarch, "app", "1.0", storeController)
+			So(errors.Is(err, zerr.ErrImageLintArchitectures), ShouldBeTrue)
+			So(zerr.GetDetails(err)["missingArchitectures"], ShouldEqual, "arm64")
+
+			multiarch = CreateMultiarchWith().Images([]Image{amd64Image, arm64Image}).Build()
+
+			err = WriteMultiArchImageToFileSystem(multiarch, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("No root user", func() {
+			lintConfig.Rules = []extconf.LintRule{{Type: extconf.LintRuleNoRootUser}}
+
+			imageConfig := GetDefaultConfig()
+
+			for _, user := range []string{"", "root", "0:0"} {
+				imageConfig.Config.User = user
+				image := CreateImageWith().RandomLayers(1, 10).ImageConfig(imageConfig).Build()
+
+				err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+				So(errors.Is(err, zerr.ErrImageLintRootUser), ShouldBeTrue)
+			}
+
+			imageConfig.Config.User = "1000:1000"
+			image := CreateImageWith().RandomLayers(1, 10).ImageConfig(imageConfig).Build()
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			// artifacts don't have an image config
+			artifact := CreateImageWith().RandomLayers(1, 10).ArtifactConfig("application/vnd.test").Build()
+
+			err = WriteImageToFileSystem(artifact, "app", "artifact", storeController)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Banned media types", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Type: extconf.LintRuleBannedMediaTypes, MediaTypes: []string{ispec.MediaTypeImageLayerNonDistributable}}, //nolint:staticcheck,lll
+			}
+
+			image := CreateImageWith().RandomLayers(1, 10).DefaultConfig().Build()
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			lintConfig.Rules[0].MediaTypes = append(lintConfig.Rules[0].MediaTypes, ispec.MediaTypeImageLayerGzip)
+			image = CreateImageWith().RandomLayers(1, 10).DefaultConfig().Build()
+
+			err = WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(errors.Is(err, zerr.ErrImageLintMediaType), ShouldBeTrue)
+			So(zerr.GetDetails(err)["mediaType"], ShouldEqual, ispec.MediaTypeImageLayerGzip)
+		})
+
+		Convey("Required labels", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Type: extconf.LintRuleRequiredLabels, Labels: []string{"org.opencontainers.image.source", "team"}},
+			}
+
+			imageConfig := GetDefaultConfig()
+			imageConfig.Config.Labels = map[string]string{"team": "registry"}
+			image := CreateImageWith().RandomLayers(1, 10).ImageConfig(imageConfig).Build()
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(errors.Is(err, zerr.ErrImageLintLabels), ShouldBeTrue)
+			So(zerr.GetDetails(err)["missingLabels"], ShouldEqual, "org.opencontainers.image.source")
+
+			imageConfig.Config.Labels["org.opencontainers.image.source"] = "https://github.com/project-zot/zot"
+			image = CreateImageWith().RandomLayers(1, 10).ImageConfig(imageConfig).Build()
+
+			err = WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Forbidden base images", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Type: extconf.LintRuleForbiddenBaseImages, BaseImages: []string{"docker.io/library/centos:*"}},
+			}
+
+			image := CreateImageWith().RandomLayers(1, 10).DefaultConfig().
+				Annotations(map[string]string{ispec.AnnotationBaseImageName: "docker.io/library/centos:7"}).Build()
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(errors.Is(err, zerr.ErrImageLintBaseImage), ShouldBeTrue)
+			So(zerr.GetDetails(err)["baseImage"], ShouldEqual, "docker.io/library/centos:7")
+
+			image = CreateImageWith().RandomLayers(1, 10).DefaultConfig().
+				Annotations(map[string]string{ispec.AnnotationBaseImageName: "docker.io/library/alpine:3.20"}).Build()
+
+			err = WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			Convey("Images built on a hosted forbidden image without the annotation", func() {
+				boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: t.TempDir()})
+				So(err, ShouldBeNil)
+
+				metaDB, err := boltdb.New(boltDriver, logger)
+				So(err, ShouldBeNil)
+
+				linter.SetMetaDB(metaDB)
+
+				baseImage := CreateImageWith().RandomLayers(2, 10).DefaultConfig().Build()
+
+				err = metaDB.SetRepoReference(context.Background(), "docker.io/library/centos", "7",
+					baseImage.AsImageMeta())
+				So(err, ShouldBeNil)
+
+				image := CreateImageWith().LayerBlobs(append(baseImage.Layers, []byte("app layer"))).
+					DefaultConfig().Build()
+
+				err = WriteImageToFileSystem(image, "app", "2.0", storeController)
+				So(errors.Is(err, zerr.ErrImageLintBaseImage), ShouldBeTrue)
+				So(zerr.GetDetails(err)["baseImage"], ShouldEqual, "docker.io/library/centos:7")
+
+				// only some of the layers of the forbidden image
+				image = CreateImageWith().LayerBlobs([][]byte{baseImage.Layers[0], []byte("app layer")}).
+					DefaultConfig().Build()
+
+				err = WriteImageToFileSystem(image, "app", "2.0", storeController)
+				So(err, ShouldBeNil)
+
+				// base images not matching the patterns are allowed
+				err = metaDB.SetRepoReference(context.Background(), "docker.io/library/alpine", "3.20",
+					baseImage.AsImageMeta())
+				So(err, ShouldBeNil)
+
+				lintConfig.Rules[0].BaseImages = []string{"docker.io/library/fedora:*"}
+
+				image = CreateImageWith().LayerBlobs(append(baseImage.Layers, []byte("app layer"))).
+					DefaultConfig().Build()
+
+				err = WriteImageToFileSystem(image, "app", "2.0", storeController)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("Rules are skipped if lint is disabled", func() {
+			disable := false
+			lintConfig.Enable = &disable
+			lintConfig.Rules = []extconf.LintRule{{Type: extconf.LintRuleMaxLayers, MaxLayers: 1}}
+
+			image := CreateImageWith().RandomLayers(2, 10).DefaultConfig().Build()
+
+			err := WriteImageToFileSystem(image, "app", "1.0", storeController)
+			So(err, ShouldBeNil)
+		})
+	})
+}
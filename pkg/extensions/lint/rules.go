@@ -0,0 +1,347 @@
+//go:build lint
+// +build lint
+
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/extensions/config"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+// ruleChecker returns the error of the failed check if target doesn't comply with rule.
+type ruleChecker func(rule config.LintRule, target *lintTarget) error
+
+var ruleCheckers = map[string]ruleChecker{ //nolint:gochecknoglobals
+	config.LintRuleMaxLayers:             checkMaxLayers,
+	config.LintRuleMaxSize:               checkMaxSize,
+	config.LintRuleRequiredArchitectures: checkRequiredArchitectures,
+	config.LintRuleNoRootUser:            checkNoRootUser,
+	config.LintRuleBannedMediaTypes:      checkBannedMediaTypes,
+	config.LintRuleRequiredLabels:        checkRequiredLabels,
+	config.LintRuleForbiddenBaseImages:   checkForbiddenBaseImages,
+}
+
+// lintTarget is the image manifest or index checked by the rules.
+type lintTarget struct {
+	repo     string
+	digest   godigest.Digest
+	manifest *ispec.Manifest // set for image manifests
+	index    *ispec.Index    // set for image indexes
+	imgStore storageTypes.ImageStore
+	metaDB   mTypes.MetaDB // nil if the registry doesn't use one
+	config   *ispec.Image  // loaded by the first rule needing it
+}
+
+func newLintTarget(repo string, manifestDigest godigest.Digest, imgStore storageTypes.ImageStore,
+	metaDB mTypes.MetaDB,
+) (*lintTarget, error) {
+	content, err := imgStore.GetBlobContent(repo, manifestDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &lintTarget{repo: repo, digest: manifestDigest, imgStore: imgStore, metaDB: metaDB}
+
+	if isImageIndex(content) {
+		target.index = &ispec.Index{}
+
+		return target, json.Unmarshal(content, target.index)
+	}
+
+	target.manifest = &ispec.Manifest{}
+
+	return target, json.Unmarshal(content, target.manifest)
+}
+
+// isImage is false for indexes and artifacts, which don't have an image config.
+func (target *lintTarget) isImage() bool {
+	return target.manifest != nil && target.manifest.Config.MediaType == ispec.MediaTypeImageConfig
+}
+
+func (target *lintTarget) imageConfig() (*ispec.Image, error) {
+	if target.config != nil {
+		return target.config, nil
+	}
+
+	content, err := target.imgStore.GetBlobContent(target.repo, target.manifest.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var imageConfig ispec.Image
+	if err := json.Unmarshal(content, &imageConfig); err != nil {
+		return nil, err
+	}
+
+	target.config = &imageConfig
+
+	return target.config, nil
+}
+
+// CheckRules applies the lint rules matching repo to the pushed image manifest or index.
+func (linter *Linter) CheckRules(repo string, manifestDigest godigest.Digest,
+	imgStore storageTypes.ImageStore,
+) (bool, error) {
+	if linter.config == nil || linter.config.Enable == nil || !*linter.config.Enable || len(linter.config.Rules) == 0 {
+		return true, nil
+	}
+
+	var target *lintTarget
+
+	for _, rule := range linter.config.Rules {
+		checker, ok := ruleCheckers[rule.Type]
+		if !ok || !matchesRepository(rule.Repositories, repo) {
+			continue
+		}
+
+		if target == nil {
+			var err error
+
+			target, err = newLintTarget(repo, manifestDigest, imgStore, linter.metaDB)
+			if err != nil {
+				linter.log.Error().Err(err).Str("component", "linter").Str("repository", repo).
+					Str("digest", manifestDigest.String()).Msg("failed to get manifest")
+
+				return false, err
+			}
+		}
+
+		if err := checker(rule, target); err != nil {
+			linter.log.Error().Err(err).Str("component", "linter").Str("repository", repo).
+				Str("digest", manifestDigest.String()).Str("rule", rule.Type).Interface("details", zerr.GetDetails(err)).
+				Msg("lint rule failed")
+
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func checkMaxLayers(rule config.LintRule, target *lintTarget) error {
+	if !target.isImage() || len(target.manifest.Layers) <= rule.MaxLayers {
+		return nil
+	}
+
+	return newRuleError(rule, zerr.ErrImageLintMaxLayers).
+		AddDetail("layers", strconv.Itoa(len(target.manifest.Layers))).
+		AddDetail("maxLayers", strconv.Itoa(rule.MaxLayers))
+}
+
+func checkMaxSize(rule config.LintRule, target *lintTarget) error {
+	if !target.isImage() {
+		return nil
+	}
+
+	size := target.manifest.Config.Size
+	for _, layer := range target.manifest.Layers {
+		size += layer.Size
+	}
+
+	if size <= rule.MaxSize {
+		return nil
+	}
+
+	return newRuleError(rule, zerr.ErrImageLintMaxSize).
+		AddDetail("size", strconv.FormatInt(size, 10)).
+		AddDetail("maxSize", strconv.FormatInt(rule.MaxSize, 10))
+}
+
+func checkRequiredArchitectures(rule config.LintRule, target *lintTarget) error {
+	if target.index == nil {
+		return nil
+	}
+
+	architectures := map[string]bool{}
+
+	for _, manifest := range target.index.Manifests {
+		if manifest.Platform != nil {
+			architectures[manifest.Platform.Architecture] = true
+		}
+	}
+
+	missingArchitectures := []string{}
+
+	for _, architecture := range rule.Architectures {
+		if !architectures[architecture] {
+			missingArchitectures = append(missingArchitectures, architecture)
+		}
+	}
+
+	if len(missingArchitectures) == 0 {
+		return nil
+	}
+
+	return newRuleError(rule, zerr.ErrImageLintArchitectures).
+		AddDetail("missingArchitectures", strings.Join(missingArchitectures, ","))
+}
+
+func checkNoRootUser(rule config.LintRule, target *lintTarget) error {
+	if !target.isImage() {
+		return nil
+	}
+
+	imageConfig, err := target.imageConfig()
+	if err != nil {
+		return err
+	}
+
+	// the user is root unless the image config sets another one
+	user, _, _ := strings.Cut(imageConfig.Config.User, ":")
+	if user != "" && user != "root" && user != "0" {
+		return nil
+	}
+
+	return newRuleError(rule, zerr.ErrImageLintRootUser).AddDetail("user", imageConfig.Config.User)
+}
+
+func checkBannedMediaTypes(rule config.LintRule, target *lintTarget) error {
+	var mediaTypes []string
+
+	if target.index != nil {
+		mediaTypes = append(mediaTypes, target.index.ArtifactType)
+
+		for _, manifest := range target.index.Manifests {
+			mediaTypes = append(mediaTypes, manifest.MediaType)
+		}
+	} else {
+		mediaTypes = append(mediaTypes, target.manifest.ArtifactType, target.manifest.Config.MediaType)
+
+		for _, layer := range target.manifest.Layers {
+			mediaTypes = append(mediaTypes, layer.MediaType)
+		}
+	}
+
+	for _, mediaType := range mediaTypes {
+		if mediaType != "" && slices.Contains(rule.MediaTypes, mediaType) {
+			return newRuleError(rule, zerr.ErrImageLintMediaType).AddDetail("mediaType", mediaType)
+		}
+	}
+
+	return nil
+}
+
+func checkRequiredLabels(rule config.LintRule, target *lintTarget) error {
+	if !target.isImage() {
+		return nil
+	}
+
+	imageConfig, err := target.imageConfig()
+	if err != nil {
+		return err
+	}
+
+	missingLabels := []string{}
+
+	for _, label := range rule.Labels {
+		if _, ok := imageConfig.Config.Labels[label]; !ok {
+			missingLabels = append(missingLabels, label)
+		}
+	}
+
+	if len(missingLabels) == 0 {
+		return nil
+	}
+
+	return newRuleError(rule, zerr.ErrImageLintLabels).AddDetail("missingLabels", strings.Join(missingLabels, ","))
+}
+
+func checkForbiddenBaseImages(rule config.LintRule, target *lintTarget) error {
+	if target.manifest == nil {
+		return nil
+	}
+
+	if baseImage := target.manifest.Annotations[ispec.AnnotationBaseImageName]; baseImage != "" &&
+		matchesBaseImages(rule.BaseImages, baseImage) {
+		return newRuleError(rule, zerr.ErrImageLintBaseImage).AddDetail("baseImage", baseImage)
+	}
+
+	// the annotation is set by the client, so the layers are also matched against the forbidden images hosted here
+	if target.metaDB == nil || len(target.manifest.Layers) == 0 {
+		return nil
+	}
+
+	layers := map[godigest.Digest]bool{}
+	for _, layer := range target.manifest.Layers {
+		layers[layer.Digest] = true
+	}
+
+	baseImages, err := target.metaDB.FilterTags(context.Background(),
+		func(repo, tag string) bool {
+			return matchesBaseImages(rule.BaseImages, repo+":"+tag)
+		},
+		func(repoMeta mTypes.RepoMeta, imageMeta mTypes.ImageMeta) bool {
+			return isBaseImage(imageMeta, target.digest, layers)
+		})
+	if err != nil {
+		return err
+	}
+
+	if len(baseImages) > 0 {
+		return newRuleError(rule, zerr.ErrImageLintBaseImage).
+			AddDetail("baseImage", baseImages[0].Repo+":"+baseImages[0].Tag)
+	}
+
+	return nil
+}
+
+func matchesBaseImages(patterns []string, baseImage string) bool {
+	for _, pattern := range patterns {
+		if matched, err := glob.Match(pattern, baseImage); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBaseImage tells if all the layers of one of the image manifests are also layers of the checked image,
+// the same way as the BaseImageList query.
+func isBaseImage(imageMeta mTypes.ImageMeta, digest godigest.Digest, layers map[godigest.Digest]bool) bool {
+	for _, manifest := range imageMeta.Manifests {
+		if manifest.Digest == digest || len(manifest.Manifest.Layers) == 0 {
+			continue
+		}
+
+		if !slices.ContainsFunc(manifest.Manifest.Layers, func(layer ispec.Descriptor) bool {
+			return !layers[layer.Digest]
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newRuleError wraps the error of a rule so it's also handled as a generic lint failure.
+func newRuleError(rule config.LintRule, err error) *zerr.Error {
+	return zerr.NewError(fmt.Errorf("%w: %w", zerr.ErrImageLintAnnotations, err)).AddDetail("rule", rule.Type)
+}
+
+// isImageIndex tells indexes apart from image manifests, their mediaType field being optional.
+func isImageIndex(content []byte) bool {
+	var manifest struct {
+		MediaType string             `json:"mediaType"`
+		Config    *ispec.Descriptor  `json:"config"`
+		Manifests []ispec.Descriptor `json:"manifests"`
+	}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return false
+	}
+
+	return manifest.MediaType == ispec.MediaTypeImageIndex || (manifest.Config == nil && manifest.Manifests != nil)
+}
@@ -50,6 +50,22 @@ var (
 		},
 		[]string{"repo"},
 	)
+	repoQuotaUsedBytes = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "repo_quota_used_bytes",
+			Help:      "Storage used by the repositories matching a quota policy",
+		},
+		[]string{"repositories"},
+	)
+	repoQuotaLimitBytes = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "repo_quota_limit_bytes",
+			Help:      "Storage limits of a quota policy",
+		},
+		[]string{"repositories", "type"},
+	)
 	uploadCounter = promauto.NewCounterVec( //nolint: gochecknoglobals
 		prometheus.CounterOpts{
 			Namespace: metricsNamespace,
@@ -130,6 +146,23 @@ var (
 		},
 		[]string{"name"},
 	)
+	eventsDeliveryLag = promauto.NewHistogramVec( //nolint: gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "events_delivery_lag_seconds",
+			Help:      "How long it takes for an event in the outbox to be delivered to a sink",
+			Buckets:   GetDefaultBuckets(),
+		},
+		[]string{"sink"},
+	)
+	eventsOutboxLength = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "events_outbox_length",
+			Help:      "Number of events in the outbox by state",
+		},
+		[]string{"state"},
+	)
 )
 
 type metricServer struct {
@@ -228,6 +261,14 @@ func SetStorageUsage(ms MetricServer, rootDir, repo string) {
 	})
 }
 
+func SetQuotaUsage(ms MetricServer, repos string, used, softLimit, hardLimit int64) {
+	ms.ForceSendMetric(func() {
+		repoQuotaUsedBytes.WithLabelValues(repos).Set(float64(used))
+		repoQuotaLimitBytes.WithLabelValues(repos, "soft").Set(float64(softLimit))
+		repoQuotaLimitBytes.WithLabelValues(repos, "hard").Set(float64(hardLimit))
+	})
+}
+
 func IncUploadCounter(ms MetricServer, repo string) {
 	ms.SendMetric(func() {
 		uploadCounter.WithLabelValues(repo).Inc()
@@ -289,3 +330,17 @@ func ObserveWorkersTasksDuration(ms MetricServer, taskName string, duration time
 		workersTasksDuration.WithLabelValues(taskName).Observe(duration.Seconds())
 	})
 }
+
+func ObserveEventDeliveryLag(ms MetricServer, sink string, lag time.Duration) {
+	ms.SendMetric(func() {
+		eventsDeliveryLag.WithLabelValues(sink).Observe(lag.Seconds())
+	})
+}
+
+func SetEventsOutboxLength(ms MetricServer, ol map[string]int) {
+	ms.SendMetric(func() {
+		for state, value := range ol {
+			eventsOutboxLength.WithLabelValues(state).Set(float64(value))
+		}
+	})
+}
@@ -24,17 +24,21 @@ const (
 	schedulerGenerators = metricsNamespace + ".scheduler.generators"
 	// Gauge.
 	repoStorageBytes          = metricsNamespace + ".repo.storage.bytes"
+	repoQuotaUsedBytes        = metricsNamespace + ".repo.quota.used.bytes"
+	repoQuotaLimitBytes       = metricsNamespace + ".repo.quota.limit.bytes"
 	serverInfo                = metricsNamespace + ".info"
 	schedulerNumWorkers       = metricsNamespace + ".scheduler.workers.total"
 	schedulerWorkers          = metricsNamespace + ".scheduler.workers"
 	schedulerGeneratorsStatus = metricsNamespace + ".scheduler.generators.status"
 	schedulerTasksQueue       = metricsNamespace + ".scheduler.tasksqueue.length"
+	eventsOutboxLength        = metricsNamespace + ".events.outbox.length"
 	// Summary.
 	httpRepoLatencySeconds = metricsNamespace + ".http.repo.latency.seconds"
 	// Histogram.
 	httpMethodLatencySeconds  = metricsNamespace + ".http.method.latency.seconds"
 	storageLockLatencySeconds = metricsNamespace + ".storage.lock.latency.seconds"
 	workersTasksDuration      = metricsNamespace + ".scheduler.workers.tasks.duration.seconds"
+	eventsDeliveryLagSeconds  = metricsNamespace + ".events.delivery.lag.seconds"
 
 	metricsScrapeTimeout       = 2 * time.Minute
 	metricsScrapeCheckInterval = 30 * time.Second
@@ -259,11 +263,14 @@ func GetCounters() map[string][]string {
 func GetGauges() map[string][]string {
 	return map[string][]string{
 		repoStorageBytes:          {"repo"},
+		repoQuotaUsedBytes:        {"repositories"},
+		repoQuotaLimitBytes:       {"repositories", "type"},
 		serverInfo:                {"commit", "binaryType", "goVersion", "version"},
 		schedulerNumWorkers:       {},
 		schedulerGeneratorsStatus: {"priority", "state"},
 		schedulerTasksQueue:       {"priority"},
 		schedulerWorkers:          {"state"},
+		eventsOutboxLength:        {"state"},
 	}
 }
 
@@ -278,6 +285,7 @@ func GetHistograms() map[string][]string {
 		httpMethodLatencySeconds:  {"method"},
 		storageLockLatencySeconds: {"storageName", "lockType"},
 		workersTasksDuration:      {"name"},
+		eventsDeliveryLagSeconds:  {"sink"},
 	}
 }
 
@@ -542,6 +550,29 @@ func SetStorageUsage(ms MetricServer, rootDir, repo string) {
 	ms.ForceSendMetric(storage)
 }
 
+func SetQuotaUsage(ms MetricServer, repos string, used, softLimit, hardLimit int64) {
+	ms.ForceSendMetric(GaugeValue{
+		Name:        repoQuotaUsedBytes,
+		Value:       float64(used),
+		LabelNames:  []string{"repositories"},
+		LabelValues: []string{repos},
+	})
+
+	ms.ForceSendMetric(GaugeValue{
+		Name:        repoQuotaLimitBytes,
+		Value:       float64(softLimit),
+		LabelNames:  []string{"repositories", "type"},
+		LabelValues: []string{repos, "soft"},
+	})
+
+	ms.ForceSendMetric(GaugeValue{
+		Name:        repoQuotaLimitBytes,
+		Value:       float64(hardLimit),
+		LabelNames:  []string{"repositories", "type"},
+		LabelValues: []string{repos, "hard"},
+	})
+}
+
 func SetServerInfo(ms MetricServer, lvs ...string) {
 	info := GaugeValue{
 		Name:        serverInfo,
@@ -638,3 +669,25 @@ func SetSchedulerWorkers(ms MetricServer, w map[string]int) {
 		ms.SendMetric(workers)
 	}
 }
+
+func ObserveEventDeliveryLag(ms MetricServer, sink string, lag time.Duration) {
+	h := HistogramValue{
+		Name:        eventsDeliveryLagSeconds,
+		Sum:         lag.Seconds(), // convenient temporary store for Histogram latency value
+		LabelNames:  []string{"sink"},
+		LabelValues: []string{sink},
+	}
+	ms.SendMetric(h)
+}
+
+func SetEventsOutboxLength(ms MetricServer, ol map[string]int) {
+	for state, value := range ol {
+		outbox := GaugeValue{
+			Name:        eventsOutboxLength,
+			Value:       float64(value),
+			LabelNames:  []string{"state"},
+			LabelValues: []string{state},
+		}
+		ms.SendMetric(outbox)
+	}
+}
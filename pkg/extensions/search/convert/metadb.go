@@ -177,6 +177,7 @@ func GetFullImageMeta(tag string, repoMeta mTypes.RepoMeta, imageMeta mTypes.Ima
 		Referrers:  repoMeta.Referrers[imageMeta.Digest.String()],
 		Statistics: repoMeta.Statistics[imageMeta.Digest.String()],
 		Signatures: repoMeta.Signatures[imageMeta.Digest.String()],
+		Sboms:      repoMeta.Sboms[imageMeta.Digest.String()],
 	}
 }
 
@@ -189,12 +190,46 @@ func GetFullManifestMeta(repoMeta mTypes.RepoMeta, manifests []mTypes.ManifestMe
 			Referrers:    repoMeta.Referrers[manifests[i].Digest.String()],
 			Statistics:   repoMeta.Statistics[manifests[i].Digest.String()],
 			Signatures:   repoMeta.Signatures[manifests[i].Digest.String()],
+			Sboms:        repoMeta.Sboms[manifests[i].Digest.String()],
 		})
 	}
 
 	return results
 }
 
+// GetAllSboms returns the SBOMs referring to the image and, in case of an index, to its manifests.
+func GetAllSboms(fullImageMeta mTypes.FullImageMeta) []mTypes.SbomInfo {
+	sboms := append([]mTypes.SbomInfo{}, fullImageMeta.Sboms...)
+
+	if fullImageMeta.MediaType == ispec.MediaTypeImageIndex {
+		for _, manifest := range fullImageMeta.Manifests {
+			sboms = append(sboms, manifest.Sboms...)
+		}
+	}
+
+	return sboms
+}
+
+func GetSbomSummary(sboms []mTypes.SbomInfo) *gql_generated.SbomSummary {
+	formats := []*string{}
+	seenFormats := map[string]bool{}
+	packageCount := 0
+
+	for _, sbom := range sboms {
+		if !seenFormats[sbom.Format] {
+			seenFormats[sbom.Format] = true
+			formats = append(formats, ref(sbom.Format))
+		}
+
+		packageCount += sbom.PackageCount
+	}
+
+	return &gql_generated.SbomSummary{
+		Formats:      formats,
+		PackageCount: &packageCount,
+	}
+}
+
 func StringMap2Annotations(strMap map[string]string) []*gql_generated.Annotation {
 	annotations := make([]*gql_generated.Annotation, 0, len(strMap))
 
@@ -429,6 +464,7 @@ func ImageIndex2ImageSummary(ctx context.Context, fullImageMeta mTypes.FullImage
 			Referrers:  imageManifest.Referrers,
 			Statistics: imageManifest.Statistics,
 			Signatures: imageManifest.Signatures,
+			Sboms:      imageManifest.Sboms,
 		})
 		if err != nil {
 			return &gql_generated.ImageSummary{}, map[string]int64{}, err
@@ -488,6 +524,7 @@ func ImageIndex2ImageSummary(ctx context.Context, fullImageMeta mTypes.FullImage
 		Vendor:        &annotations.Vendor,
 		Authors:       &annotations.Authors,
 		Referrers:     getReferrers(fullImageMeta.Referrers),
+		SbomSummary:   GetSbomSummary(GetAllSboms(fullImageMeta)),
 	}
 
 	return &indexSummary, indexBlobs, nil
@@ -574,6 +611,7 @@ func ImageManifest2ImageSummary(ctx context.Context, fullImageMeta mTypes.FullIm
 		Vendor:            &annotations.Vendor,
 		Authors:           &authors,
 		Referrers:         manifestSummary.Referrers,
+		SbomSummary:       GetSbomSummary(fullImageMeta.Sboms),
 	}
 
 	return &imageSummary, imageBlobsMap, nil
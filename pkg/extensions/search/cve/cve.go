@@ -33,8 +33,8 @@ type Scanner interface {
 	ScanImage(ctx context.Context, image string) (map[string]cvemodel.CVE, error)
 	IsImageFormatScannable(repo, ref string) (bool, error)
 	IsImageMediaScannable(repo, digestStr, mediaType string) (bool, error)
-	IsResultCached(digestStr string) bool
-	GetCachedResult(digestStr string) map[string]cvemodel.CVE
+	IsResultCached(repo, digestStr string) bool
+	GetCachedResult(repo, digestStr string) map[string]cvemodel.CVE
 	UpdateDB(ctx context.Context) error
 }
 
@@ -45,9 +45,9 @@ type BaseCveInfo struct {
 }
 
 func NewScanner(storeController storage.StoreController, metaDB mTypes.MetaDB,
-	dbRepository, javaDBRepository string, log log.Logger,
+	dbRepository, javaDBRepository, sbomScanMode string, log log.Logger,
 ) Scanner {
-	return trivy.NewScanner(storeController, metaDB, dbRepository, javaDBRepository, log)
+	return trivy.NewScanner(storeController, metaDB, dbRepository, javaDBRepository, sbomScanMode, log)
 }
 
 func NewCVEInfo(scanner Scanner, metaDB mTypes.MetaDB, log log.Logger) *BaseCveInfo {
@@ -507,7 +507,7 @@ func (cveinfo BaseCveInfo) GetCVESummaryForImageMedia(ctx context.Context, repo,
 	// scannable no issues found           - max severity "NONE"        - cve count 0   - no Errors
 	// scannable issues found              - max severity from Scanner  - cve count >0  - no Errors
 	// For this call we only look at the scanner cache, we skip the actual scanning to save time
-	if !cveinfo.Scanner.IsResultCached(digestStr) {
+	if !cveinfo.Scanner.IsResultCached(repo, digestStr) {
 		isValidImage, err := cveinfo.Scanner.IsImageMediaScannable(repo, digestStr, mediaType)
 		if !isValidImage {
 			cveinfo.Log.Debug().Str("digest", digestStr).Str("mediaType", mediaType).
@@ -523,7 +523,7 @@ func (cveinfo BaseCveInfo) GetCVESummaryForImageMedia(ctx context.Context, repo,
 	}
 
 	// We will make due with cached results
-	cveMap := cveinfo.Scanner.GetCachedResult(digestStr)
+	cveMap := cveinfo.Scanner.GetCachedResult(repo, digestStr)
 
 	return initCVESummaryFromCVEMap(cveMap), nil
 }
@@ -334,7 +334,7 @@ func TestImageFormat(t *testing.T) {
 		err = meta.ParseStorage(metaDB, storeController, log)
 		So(err, ShouldBeNil)
 
-		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 		isValidImage, err := scanner.IsImageFormatScannable("zot-test", "")
 		So(err, ShouldNotBeNil)
@@ -407,7 +407,7 @@ func TestImageFormat(t *testing.T) {
 			DefaultStore: mocks.MockedImageStore{},
 		}
 
-		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 		isScanable, err := scanner.IsImageFormatScannable("repo", "tag")
 		So(err, ShouldBeNil)
@@ -1178,12 +1178,12 @@ func TestCVEStruct(t *testing.T) { //nolint:gocyclo
 
 				return true, nil
 			},
-			IsResultCachedFn: func(digest string) bool {
+			IsResultCachedFn: func(repo, digest string) bool {
 				t.Logf("IsResultCachedFn found in cache for digest %s: %v", digest, cache.Get(digest))
 
 				return cache.Contains(digest)
 			},
-			GetCachedResultFn: func(digest string) map[string]cvemodel.CVE {
+			GetCachedResultFn: func(repo, digest string) map[string]cvemodel.CVE {
 				t.Logf("GetCachedResultFn found in cache for digest %s: %v", digest, cache.Get(digest))
 
 				return cache.Get(digest)
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 
+	"zotregistry.dev/zot/pkg/extensions/events"
+	cvemodel "zotregistry.dev/zot/pkg/extensions/search/cve/model"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	reqCtx "zotregistry.dev/zot/pkg/requestcontext"
@@ -14,6 +16,7 @@ import (
 func NewScanTaskGenerator(
 	metaDB mTypes.MetaDB,
 	scanner Scanner,
+	recorder events.Recorder,
 	logC log.Logger,
 ) scheduler.TaskGenerator {
 	sublogger := logC.With().Str("component", "cve").Logger()
@@ -22,6 +25,7 @@ func NewScanTaskGenerator(
 		log:        log.Logger{Logger: sublogger},
 		metaDB:     metaDB,
 		scanner:    scanner,
+		events:     recorder,
 		lock:       &sync.Mutex{},
 		scanErrors: map[string]error{},
 		scheduled:  map[string]bool{},
@@ -37,6 +41,7 @@ type scanTaskGenerator struct {
 	log        log.Logger
 	metaDB     mTypes.MetaDB
 	scanner    Scanner
+	events     events.Recorder
 	lock       *sync.Mutex
 	scanErrors map[string]error
 	scheduled  map[string]bool
@@ -64,7 +69,7 @@ func (gen *scanTaskGenerator) getMatcherFunc() mTypes.FilterFunc {
 			return false
 		}
 
-		if gen.scanner.IsResultCached(manifestDigest) {
+		if gen.scanner.IsResultCached(repoName, manifestDigest) {
 			// We skip this manifest, it was already scanned
 			return false
 		}
@@ -189,8 +194,9 @@ func (st *scanTask) DoWork(ctx context.Context) error {
 	image := st.repo + "@" + st.digest
 
 	// We cache the results internally in the scanner
-	// so we can discard the actual results for now
-	if _, err := st.generator.scanner.ScanImage(ctx, image); err != nil {
+	// so we only need them for the event
+	cveMap, err := st.generator.scanner.ScanImage(ctx, image)
+	if err != nil {
 		st.generator.log.Error().Err(err).Str("image", image).Msg("failed to perform scheduled cve scan for image")
 		st.generator.addError(st.digest, err)
 
@@ -199,6 +205,18 @@ func (st *scanTask) DoWork(ctx context.Context) error {
 
 	st.generator.log.Debug().Str("image", image).Msg("scheduled cve scan completed successfully for image")
 
+	if st.generator.events != nil {
+		summary := initCVESummaryFromCVEMap(cveMap)
+
+		st.generator.events.CVEScanCompleted(st.repo, st.digest, summary.MaxSeverity, map[string]int{
+			cvemodel.SeverityUnknown:  summary.UnknownCount,
+			cvemodel.SeverityLow:      summary.LowCount,
+			cvemodel.SeverityMedium:   summary.MediumCount,
+			cvemodel.SeverityHigh:     summary.HighCount,
+			cvemodel.SeverityCritical: summary.CriticalCount,
+		})
+	}
+
 	return nil
 }
 
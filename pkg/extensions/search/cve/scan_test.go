@@ -8,6 +8,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -407,7 +408,7 @@ func TestScanGeneratorWithMockedData(t *testing.T) { //nolint: gocyclo
 
 				return true, nil
 			},
-			IsResultCachedFn: func(digest string) bool {
+			IsResultCachedFn: func(repo, digest string) bool {
 				return cache.Contains(digest)
 			},
 			UpdateDBFn: func(ctx context.Context) error {
@@ -424,12 +425,28 @@ func TestScanGeneratorWithMockedData(t *testing.T) { //nolint: gocyclo
 		t.Log("verify cache is initially empty")
 
 		for image, digestStr := range imageMap {
+			repo, _, _ := zcommon.GetImageDirAndReference(image)
+
 			t.Log("expecting " + image + " " + digestStr + " to be absent from cache")
-			So(scanner.IsResultCached(digestStr), ShouldBeFalse)
+			So(scanner.IsResultCached(repo, digestStr), ShouldBeFalse)
+		}
+
+		scanEventsLock := sync.Mutex{}
+		scanEvents := map[string]map[string]int{}
+		maxSeverities := map[string]string{}
+
+		recorder := mocks.EventRecorderMock{
+			CVEScanCompletedFn: func(name, digest, maxSeverity string, severityCounts map[string]int) {
+				scanEventsLock.Lock()
+				defer scanEventsLock.Unlock()
+
+				scanEvents[name+"@"+digest] = severityCounts
+				maxSeverities[name+"@"+digest] = maxSeverity
+			},
 		}
 
 		// Start the generator
-		generator := cveinfo.NewScanTaskGenerator(metaDB, scanner, logger)
+		generator := cveinfo.NewScanTaskGenerator(metaDB, scanner, recorder, logger)
 
 		sch.SubmitGenerator(generator, 10*time.Second, scheduler.MediumPriority)
 
@@ -452,14 +469,28 @@ func TestScanGeneratorWithMockedData(t *testing.T) { //nolint: gocyclo
 			ok, err := scanner.IsImageFormatScannable(repo, digestStr)
 			if ok && err == nil && repo != "repo7" {
 				t.Log("expecting " + image + " " + digestStr + " to be present in cache")
-				So(scanner.IsResultCached(digestStr), ShouldBeTrue)
+				So(scanner.IsResultCached(repo, digestStr), ShouldBeTrue)
 			} else {
 				// We don't cache results for un-scannable manifests
 				t.Log("expecting " + image + " " + digestStr + " to be absent from cache")
-				So(scanner.IsResultCached(digestStr), ShouldBeFalse)
+				So(scanner.IsResultCached(repo, digestStr), ShouldBeFalse)
 			}
 		}
 
+		// an event is recorded with the severity counts of each scanned image
+		scanEventsLock.Lock()
+		So(maxSeverities[repo1+"@"+image11Digest], ShouldEqual, cvemodel.SeverityMedium)
+		So(scanEvents[repo1+"@"+image11Digest][cvemodel.SeverityMedium], ShouldEqual, 1)
+		So(maxSeverities[repo1+"@"+image12Digest], ShouldEqual, cvemodel.SeverityHigh)
+		So(scanEvents[repo1+"@"+image12Digest], ShouldResemble, map[string]int{
+			cvemodel.SeverityUnknown:  0,
+			cvemodel.SeverityLow:      1,
+			cvemodel.SeverityMedium:   1,
+			cvemodel.SeverityHigh:     1,
+			cvemodel.SeverityCritical: 0,
+		})
+		scanEventsLock.Unlock()
+
 		found, err = test.ReadLogFileAndSearchString(logPath,
 			"failed to obtain repo metadata during scheduled cve scan", 20*time.Second)
 		So(err, ShouldBeNil)
@@ -516,15 +547,15 @@ func TestScanGeneratorWithRealData(t *testing.T) {
 		err = meta.ParseStorage(metaDB, storeController, logger)
 		So(err, ShouldBeNil)
 
-		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", logger)
+		scanner := cveinfo.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", logger)
 		err = scanner.UpdateDB(context.Background())
 		So(err, ShouldBeNil)
 
-		So(scanner.IsResultCached(image.DigestStr()), ShouldBeFalse)
+		So(scanner.IsResultCached("zot-test", image.DigestStr()), ShouldBeFalse)
 
 		sch := scheduler.NewScheduler(cfg, metrics, logger)
 
-		generator := cveinfo.NewScanTaskGenerator(metaDB, scanner, logger)
+		generator := cveinfo.NewScanTaskGenerator(metaDB, scanner, nil, logger)
 
 		// Start the generator
 		sch.SubmitGenerator(generator, 120*time.Second, scheduler.MediumPriority)
@@ -549,7 +580,7 @@ func TestScanGeneratorWithRealData(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(found, ShouldBeTrue)
 
-		So(scanner.IsResultCached(image.DigestStr()), ShouldBeTrue)
+		So(scanner.IsResultCached("zot-test", image.DigestStr()), ShouldBeTrue)
 
 		cveMap, err := scanner.ScanImage(context.Background(), "zot-test:0.0.1")
 		So(err, ShouldBeNil)
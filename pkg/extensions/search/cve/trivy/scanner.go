@@ -2,12 +2,16 @@ package trivy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
@@ -27,9 +31,12 @@ import (
 	zerr "zotregistry.dev/zot/errors"
 	zcommon "zotregistry.dev/zot/pkg/common"
 	"zotregistry.dev/zot/pkg/compat"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	cvecache "zotregistry.dev/zot/pkg/extensions/search/cve/cache"
 	cvemodel "zotregistry.dev/zot/pkg/extensions/search/cve/model"
 	"zotregistry.dev/zot/pkg/log"
+	"zotregistry.dev/zot/pkg/meta"
+	"zotregistry.dev/zot/pkg/meta/common"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/storage"
 )
@@ -88,10 +95,14 @@ type Scanner struct {
 	cache               *cvecache.CveCache
 	dbRepositoryRef     name.Reference
 	javaDBRepositoryRef name.Reference
+	sbomScanMode        string
+	// metadata of the trivy DB, its schema version and build time are recorded with the scan results
+	// persisted in MetaDB
+	dbMetadata *atomic.Pointer[metadata.Metadata]
 }
 
 func NewScanner(storeController storage.StoreController,
-	metaDB mTypes.MetaDB, dbRepository, javaDBRepository string, log log.Logger,
+	metaDB mTypes.MetaDB, dbRepository, javaDBRepository, sbomScanMode string, log log.Logger,
 ) *Scanner {
 	// The logic to set defaults is similar to what trivy itself uses:
 	// https://github.com/aquasecurity/trivy/blob/v0.51.4/pkg/flag/db_flags.go#L152
@@ -157,6 +168,8 @@ func NewScanner(storeController storage.StoreController,
 		cache:               cvecache.NewCveCache(cacheSize, log),
 		dbRepositoryRef:     dbRepositoryRef,
 		javaDBRepositoryRef: javaDBRepositoryRef,
+		sbomScanMode:        sbomScanMode,
+		dbMetadata:          &atomic.Pointer[metadata.Metadata]{},
 	}
 }
 
@@ -193,6 +206,17 @@ func (scanner Scanner) getTrivyOptions(image string) flag.Options {
 }
 
 func (scanner Scanner) runTrivy(ctx context.Context, opts flag.Options) (types.Report, error) {
+	return scanner.runTrivyScan(ctx, opts, artifact.Runner.ScanImage)
+}
+
+// runTrivySbom scans the SBOM file given as target in the options instead of the image layers.
+func (scanner Scanner) runTrivySbom(ctx context.Context, opts flag.Options) (types.Report, error) {
+	return scanner.runTrivyScan(ctx, opts, artifact.Runner.ScanSBOM)
+}
+
+func (scanner Scanner) runTrivyScan(ctx context.Context, opts flag.Options,
+	scan func(artifact.Runner, context.Context, flag.Options) (types.Report, error),
+) (types.Report, error) {
 	err := scanner.checkDBPresence()
 	if err != nil {
 		return types.Report{}, err
@@ -204,7 +228,7 @@ func (scanner Scanner) runTrivy(ctx context.Context, opts flag.Options) (types.R
 	}
 	defer runner.Close(ctx)
 
-	report, err := runner.ScanImage(ctx, opts)
+	report, err := scan(runner, ctx, opts)
 	if err != nil {
 		return types.Report{}, err
 	}
@@ -248,7 +272,7 @@ func (scanner Scanner) IsImageMediaScannable(repo, digestStr, mediaType string)
 
 	if mediaType == ispec.MediaTypeImageManifest || //nolint:gocritic // not converting to switch-case
 		compat.IsCompatibleManifestMediaType(mediaType) {
-		ok, err := scanner.isManifestScanable(digestStr)
+		ok, err := scanner.isManifestScanable(repo, digestStr)
 		if err != nil {
 			return ok, fmt.Errorf("image '%s' %w", image, err)
 		}
@@ -256,7 +280,7 @@ func (scanner Scanner) IsImageMediaScannable(repo, digestStr, mediaType string)
 		return ok, nil
 	} else if mediaType == ispec.MediaTypeImageIndex ||
 		compat.IsCompatibleManifestListMediaType(mediaType) {
-		ok, err := scanner.isIndexScannable(digestStr)
+		ok, err := scanner.isIndexScannable(repo, digestStr)
 		if err != nil {
 			return ok, fmt.Errorf("image '%s' %w", image, err)
 		}
@@ -267,17 +291,36 @@ func (scanner Scanner) IsImageMediaScannable(repo, digestStr, mediaType string)
 	}
 }
 
-func (scanner Scanner) isManifestScanable(digestStr string) (bool, error) {
-	if scanner.cache.Get(digestStr) != nil {
-		return true, nil
+func (scanner Scanner) isManifestScanable(repo, digestStr string) (bool, error) {
+	manifestData, err := scanner.metaDB.GetImageMeta(godigest.Digest(digestStr))
+	if err != nil {
+		return false, err
 	}
 
-	manifestData, err := scanner.metaDB.GetImageMeta(godigest.Digest(digestStr))
+	return scanner.isManifestDataScannable(repo, manifestData.Manifests[0])
+}
+
+func (scanner Scanner) isManifestDataScannable(repo string, manifestData mTypes.ManifestMeta) (bool, error) {
+	digest := manifestData.Digest.String()
+
+	sbomDigests, err := scanner.getTrustedSbomDigests(context.Background(), repo, digest)
 	if err != nil {
 		return false, err
 	}
 
-	for _, imageLayer := range manifestData.Manifests[0].Manifest.Layers {
+	if scanner.getScanResult(scanResultKey(digest, sbomDigests)) != nil {
+		return true, nil
+	}
+
+	if len(sbomDigests) > 0 {
+		return true, nil
+	}
+
+	if scanner.sbomScanMode == extconf.SbomScanModeRequire {
+		return false, zerr.ErrSbomNotFound
+	}
+
+	for _, imageLayer := range manifestData.Manifest.Layers {
 		switch imageLayer.MediaType {
 		case ispec.MediaTypeImageLayerGzip, ispec.MediaTypeImageLayer, string(regTypes.DockerLayer):
 			continue
@@ -289,25 +332,84 @@ func (scanner Scanner) isManifestScanable(digestStr string) (bool, error) {
 	return true, nil
 }
 
-func (scanner Scanner) isManifestDataScannable(manifestData mTypes.ManifestMeta) (bool, error) {
-	if scanner.cache.Get(manifestData.Digest.String()) != nil {
-		return true, nil
+// isSbomScanEnabled returns true if the SBOMs attached to the images are scanned instead of their layers.
+func (scanner Scanner) isSbomScanEnabled() bool {
+	return scanner.sbomScanMode == extconf.SbomScanModePrefer || scanner.sbomScanMode == extconf.SbomScanModeRequire
+}
+
+// getTrustedSbomDigests returns the sorted digests of the SBOMs referring to the manifests in the repo which
+// have a trusted signature. Anyone allowed to push to the repo can attach an SBOM, for instance an empty one
+// hiding all the CVEs of the image, so unsigned SBOMs are not used and the layers are scanned instead.
+func (scanner Scanner) getTrustedSbomDigests(ctx context.Context, repo string, digests ...string,
+) ([]string, error) {
+	if !scanner.isSbomScanEnabled() {
+		return nil, nil
 	}
 
-	for _, imageLayer := range manifestData.Manifest.Layers {
-		switch imageLayer.MediaType {
-		case ispec.MediaTypeImageLayerGzip, ispec.MediaTypeImageLayer, string(regTypes.DockerLayer):
-			continue
-		default:
-			return false, zerr.ErrScanNotSupported
+	repoMeta, err := scanner.metaDB.GetRepoMeta(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	sbomDigests := []string{}
+
+	for _, digest := range digests {
+		for _, sbomInfo := range repoMeta.Sboms[digest] {
+			if common.HasTrustedSignature(repoMeta.Signatures[sbomInfo.SbomManifestDigest], nil) {
+				sbomDigests = append(sbomDigests, sbomInfo.SbomManifestDigest)
+			}
 		}
 	}
 
-	return true, nil
+	slices.Sort(sbomDigests)
+
+	return slices.Compact(sbomDigests), nil
 }
 
-func (scanner Scanner) isIndexScannable(digestStr string) (bool, error) {
-	if scanner.cache.Get(digestStr) != nil {
+// getScanResultKey returns the key of the scan result of the manifest or index in the repo.
+func (scanner Scanner) getScanResultKey(ctx context.Context, repo, digest string) (string, error) {
+	if !scanner.isSbomScanEnabled() {
+		return digest, nil
+	}
+
+	imageMeta, err := scanner.metaDB.GetImageMeta(godigest.Digest(digest))
+	if err != nil {
+		return "", err
+	}
+
+	manifestDigests := []string{}
+
+	for _, manifest := range imageMeta.Manifests {
+		manifestDigests = append(manifestDigests, manifest.Digest.String())
+	}
+
+	sbomDigests, err := scanner.getTrustedSbomDigests(ctx, repo, manifestDigests...)
+	if err != nil {
+		return "", err
+	}
+
+	return scanResultKey(digest, sbomDigests), nil
+}
+
+// scanResultKey returns the key under which the scan result of an image is cached and persisted. The same
+// image can be pushed to several repos with different SBOMs, so the digests of the SBOMs which were scanned
+// instead of the layers are part of the key. The results of SBOMs which are no longer used are dropped with
+// the other stale results when the trivy DB is updated.
+func scanResultKey(digest string, sbomDigests []string) string {
+	if len(sbomDigests) == 0 {
+		return digest
+	}
+
+	return digest + "+" + strings.Join(sbomDigests, "+")
+}
+
+func (scanner Scanner) isIndexScannable(repo, digestStr string) (bool, error) {
+	resultKey, err := scanner.getScanResultKey(context.Background(), repo, digestStr)
+	if err != nil {
+		return false, err
+	}
+
+	if scanner.getScanResult(resultKey) != nil {
 		return true, nil
 	}
 
@@ -327,7 +429,7 @@ func (scanner Scanner) isIndexScannable(digestStr string) (bool, error) {
 	}
 
 	for _, manifest := range indexData.Manifests {
-		isScannable, err := scanner.isManifestDataScannable(manifest)
+		isScannable, err := scanner.isManifestDataScannable(repo, manifest)
 		if err != nil {
 			continue
 		}
@@ -341,13 +443,125 @@ func (scanner Scanner) isIndexScannable(digestStr string) (bool, error) {
 	return false, nil
 }
 
-func (scanner Scanner) IsResultCached(digest string) bool {
+func (scanner Scanner) IsResultCached(repo, digest string) bool {
+	resultKey, err := scanner.getScanResultKey(context.Background(), repo, digest)
+	if err != nil {
+		return false
+	}
+
 	// Check if the entry exists in cache without updating the recent-ness
-	return scanner.cache.Contains(digest)
+	if scanner.cache.Contains(resultKey) {
+		return true
+	}
+
+	return scanner.getPersistedScanResult(resultKey) != nil
+}
+
+func (scanner Scanner) GetCachedResult(repo, digest string) map[string]cvemodel.CVE {
+	resultKey, err := scanner.getScanResultKey(context.Background(), repo, digest)
+	if err != nil {
+		return nil
+	}
+
+	return scanner.getScanResult(resultKey)
+}
+
+// getScanResult returns the result of a previous scan of the image, see scanResultKey, looked up in the in-memory cache first
+// and then in MetaDB, where the results of scans made by other zot instances sharing the DB can be found.
+func (scanner Scanner) getScanResult(digest string) map[string]cvemodel.CVE {
+	if cveMap := scanner.cache.Get(digest); cveMap != nil {
+		return cveMap
+	}
+
+	return scanner.getPersistedScanResult(digest)
+}
+
+// getPersistedScanResult returns the scan result stored in MetaDB, if it was computed with the current
+// trivy DB or a newer one, and adds it to the in-memory cache.
+func (scanner Scanner) getPersistedScanResult(digest string) map[string]cvemodel.CVE {
+	dbMetadata := scanner.getDBMetadata()
+	if dbMetadata == nil {
+		return nil
+	}
+
+	result, err := scanner.metaDB.GetCVEScanResult(godigest.Digest(digest))
+	if err != nil {
+		if !errors.Is(err, zerr.ErrCVEScanResultNotFound) {
+			scanner.log.Warn().Err(err).Str("digest", digest).Msg("failed to get cve scan result from metadb")
+		}
+
+		return nil
+	}
+
+	if isStaleScanResult(result, dbMetadata) {
+		return nil
+	}
+
+	cveMap := getCVEMap(result)
+	scanner.cache.Add(digest, cveMap)
+
+	return cveMap
 }
 
-func (scanner Scanner) GetCachedResult(digest string) map[string]cvemodel.CVE {
-	return scanner.cache.Get(digest)
+// setScanResult adds the scan result to the in-memory cache and persists it in MetaDB.
+func (scanner Scanner) setScanResult(digest string, cveMap map[string]cvemodel.CVE) {
+	scanner.cache.Add(digest, cveMap)
+
+	dbMetadata := scanner.getDBMetadata()
+	if dbMetadata == nil {
+		return
+	}
+
+	err := scanner.metaDB.SetCVEScanResult(godigest.Digest(digest), getCVEScanResult(cveMap, dbMetadata))
+	if err != nil {
+		scanner.log.Warn().Err(err).Str("digest", digest).Msg("failed to store cve scan result in metadb")
+	}
+}
+
+// getDBMetadata returns the metadata of the downloaded trivy DB, or nil if there is none.
+func (scanner Scanner) getDBMetadata() *metadata.Metadata {
+	if dbMetadata := scanner.dbMetadata.Load(); dbMetadata != nil {
+		return dbMetadata
+	}
+
+	dbMetadata := scanner.readDBMetadata()
+	if dbMetadata != nil {
+		scanner.dbMetadata.Store(dbMetadata)
+	}
+
+	return dbMetadata
+}
+
+// readDBMetadata reads the metadata file of the trivy DB, the DB of every store is
+// downloaded from the same repository so the first one found is used.
+func (scanner Scanner) readDBMetadata() *metadata.Metadata {
+	rootDirs := []string{}
+
+	if scanner.storeController.DefaultStore != nil {
+		rootDirs = append(rootDirs, scanner.storeController.DefaultStore.RootDir())
+	}
+
+	for _, storage := range scanner.storeController.SubStore {
+		rootDirs = append(rootDirs, storage.RootDir())
+	}
+
+	for _, rootDir := range rootDirs {
+		dbMetadata, err := metadata.NewClient(path.Join(rootDir, "_trivy", "db")).Get()
+		if err != nil {
+			continue
+		}
+
+		return &dbMetadata
+	}
+
+	return nil
+}
+
+// isStaleScanResult returns true if the result was computed with another schema version or an older build
+// of the trivy DB. The results computed by replicas which already downloaded a newer build are used as they are,
+// so replicas updating the DB at different times don't keep invalidating each other's results.
+func isStaleScanResult(result mTypes.CVEScanResult, dbMetadata *metadata.Metadata) bool {
+	return result.DBVersion != strconv.Itoa(dbMetadata.Version) || result.DBUpdatedAt.Before(dbMetadata.UpdatedAt)
 }
 
 func (scanner Scanner) ScanImage(ctx context.Context, image string) (map[string]cvemodel.CVE, error) {
@@ -401,16 +615,34 @@ func (scanner Scanner) ScanImage(ctx context.Context, image string) (map[string]
 }
 
 func (scanner Scanner) scanManifest(ctx context.Context, repo, digest string) (map[string]cvemodel.CVE, error) {
-	if cachedMap := scanner.cache.Get(digest); cachedMap != nil {
+	cveidMap := map[string]cvemodel.CVE{}
+	image := repo + "@" + digest
+
+	sbomDigests, err := scanner.getTrustedSbomDigests(ctx, repo, digest)
+	if err != nil {
+		return cveidMap, err
+	}
+
+	resultKey := scanResultKey(digest, sbomDigests)
+
+	if cachedMap := scanner.getScanResult(resultKey); cachedMap != nil {
 		return cachedMap, nil
 	}
 
-	cveidMap := map[string]cvemodel.CVE{}
-	image := repo + "@" + digest
+	sbomDocuments, err := scanner.getSbomDocuments(ctx, repo, digest, sbomDigests)
+	if err != nil {
+		return cveidMap, err
+	}
+
+	var report types.Report
 
 	scanner.dbLock.Lock()
-	opts := scanner.getTrivyOptions(image)
-	report, err := scanner.runTrivy(ctx, opts)
+	if len(sbomDocuments) > 0 {
+		report, err = scanner.scanSbomDocuments(ctx, image, sbomDocuments)
+	} else {
+		opts := scanner.getTrivyOptions(image)
+		report, err = scanner.runTrivy(ctx, opts)
+	}
 	scanner.dbLock.Unlock()
 
 	if err != nil { //nolint: wsl
@@ -481,11 +713,92 @@ func (scanner Scanner) scanManifest(ctx context.Context, repo, digest string) (m
 		}
 	}
 
-	scanner.cache.Add(digest, cveidMap)
+	scanner.setScanResult(resultKey, cveidMap)
 
 	return cveidMap, nil
 }
 
+// getSbomDocuments returns the documents of the trusted SBOMs attached to the manifest, see
+// getTrustedSbomDigests, which should be scanned instead of its layers depending on the configured sbom scan mode.
+func (scanner Scanner) getSbomDocuments(ctx context.Context, repo, digest string, sbomDigests []string,
+) ([]meta.SbomDocument, error) {
+	if !scanner.isSbomScanEnabled() {
+		return nil, nil
+	}
+
+	imageStore := scanner.storeController.GetImageStore(repo)
+	sbomDocuments := []meta.SbomDocument{}
+
+	for _, sbomDigestStr := range sbomDigests {
+		sbomDigest := godigest.Digest(sbomDigestStr)
+
+		sbomMeta, err := scanner.metaDB.GetImageMeta(sbomDigest)
+		if err != nil || len(sbomMeta.Manifests) == 0 {
+			scanner.log.Warn().Err(err).Str("repository", repo).Str("digest", digest).
+				Str("sbomDigest", sbomDigest.String()).Msg("failed to get sbom manifest")
+
+			continue
+		}
+
+		documents, err := meta.GetSbomDocuments(repo, sbomDigest, sbomMeta.Manifests[0].Manifest,
+			imageStore, scanner.log)
+		if err != nil {
+			continue
+		}
+
+		sbomDocuments = append(sbomDocuments, documents...)
+	}
+
+	if len(sbomDocuments) == 0 && scanner.sbomScanMode == extconf.SbomScanModeRequire {
+		return nil, zerr.ErrSbomNotFound
+	}
+
+	return sbomDocuments, nil
+}
+
+// scanSbomDocuments scans the SBOMs of an image, trivy reads them from files so each document is
+// written to a temporary file which is removed after the scan.
+func (scanner Scanner) scanSbomDocuments(ctx context.Context, image string, documents []meta.SbomDocument,
+) (types.Report, error) {
+	report := types.Report{}
+
+	for _, document := range documents {
+		documentReport, err := scanner.scanSbomDocument(ctx, image, document)
+		if err != nil {
+			return types.Report{}, err
+		}
+
+		report.Results = append(report.Results, documentReport.Results...)
+	}
+
+	return report, nil
+}
+
+func (scanner Scanner) scanSbomDocument(ctx context.Context, image string, document meta.SbomDocument,
+) (types.Report, error) {
+	sbomFile, err := os.CreateTemp("", "zot-sbom-*.json")
+	if err != nil {
+		return types.Report{}, err
+	}
+
+	defer os.Remove(sbomFile.Name())
+
+	_, err = sbomFile.Write(document.Content)
+	if closeErr := sbomFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return types.Report{}, err
+	}
+
+	opts := scanner.getTrivyOptions(image)
+	opts.ScanOptions.Target = sbomFile.Name()
+	opts.ImageOptions.Input = ""
+
+	return scanner.runTrivySbom(ctx, opts)
+}
+
 func getCVEReference(primaryURL string, references []string) string {
 	if primaryURL != "" {
 		return primaryURL
@@ -515,7 +828,12 @@ func getNVDReference(references []string) (string, bool) {
 }
 
 func (scanner Scanner) scanIndex(ctx context.Context, repo, digest string) (map[string]cvemodel.CVE, error) {
-	if cachedMap := scanner.cache.Get(digest); cachedMap != nil {
+	resultKey, err := scanner.getScanResultKey(ctx, repo, digest)
+	if err != nil {
+		return map[string]cvemodel.CVE{}, err
+	}
+
+	if cachedMap := scanner.getScanResult(resultKey); cachedMap != nil {
 		return cachedMap, nil
 	}
 
@@ -531,7 +849,7 @@ func (scanner Scanner) scanIndex(ctx context.Context, repo, digest string) (map[
 	indexCveIDMap := map[string]cvemodel.CVE{}
 
 	for _, manifest := range indexData.Index.Manifests {
-		if isScannable, err := scanner.isManifestScanable(manifest.Digest.String()); isScannable && err == nil {
+		if isScannable, err := scanner.isManifestScanable(repo, manifest.Digest.String()); isScannable && err == nil {
 			manifestCveIDMap, err := scanner.scanManifest(ctx, repo, manifest.Digest.String())
 			if err != nil {
 				return nil, err
@@ -543,7 +861,7 @@ func (scanner Scanner) scanIndex(ctx context.Context, repo, digest string) (map[
 		}
 	}
 
-	scanner.cache.Add(digest, indexCveIDMap)
+	scanner.setScanResult(resultKey, indexCveIDMap)
 
 	return indexCveIDMap, nil
 }
@@ -576,8 +894,26 @@ func (scanner Scanner) UpdateDB(ctx context.Context) error {
 		}
 	}
 
+	// the results of previous scans are kept unless a new build of the DB was downloaded
+	dbMetadata := scanner.readDBMetadata()
+
+	previousDBMetadata := scanner.dbMetadata.Swap(dbMetadata)
+	if dbMetadata != nil && previousDBMetadata != nil && dbMetadata.Version == previousDBMetadata.Version &&
+		dbMetadata.UpdatedAt.Equal(previousDBMetadata.UpdatedAt) {
+		return nil
+	}
+
 	scanner.cache.Purge()
 
+	if dbMetadata != nil {
+		err := scanner.metaDB.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool {
+			return isStaleScanResult(result, dbMetadata)
+		})
+		if err != nil {
+			scanner.log.Warn().Err(err).Msg("failed to delete stale cve scan results from metadb")
+		}
+	}
+
 	return nil
 }
 
@@ -670,6 +1006,67 @@ func findMediaTypeForDigest(metaDB mTypes.MetaDB, digest godigest.Digest) (bool,
 	return false, ""
 }
 
+func getCVEScanResult(cveMap map[string]cvemodel.CVE, dbMetadata *metadata.Metadata) mTypes.CVEScanResult {
+	result := mTypes.CVEScanResult{
+		ScannedAt:   time.Now(),
+		DBVersion:   strconv.Itoa(dbMetadata.Version),
+		DBUpdatedAt: dbMetadata.UpdatedAt,
+		CVEs:        make(map[string]mTypes.CVE, len(cveMap)),
+	}
+
+	for id, cve := range cveMap {
+		packageList := make([]mTypes.CVEPackage, 0, len(cve.PackageList))
+
+		for _, pkg := range cve.PackageList {
+			packageList = append(packageList, mTypes.CVEPackage{
+				Name:             pkg.Name,
+				PackagePath:      pkg.PackagePath,
+				InstalledVersion: pkg.InstalledVersion,
+				FixedVersion:     pkg.FixedVersion,
+			})
+		}
+
+		result.CVEs[id] = mTypes.CVE{
+			ID:          cve.ID,
+			Description: cve.Description,
+			Severity:    cve.Severity,
+			Title:       cve.Title,
+			Reference:   cve.Reference,
+			PackageList: packageList,
+		}
+	}
+
+	return result
+}
+
+func getCVEMap(result mTypes.CVEScanResult) map[string]cvemodel.CVE {
+	cveMap := make(map[string]cvemodel.CVE, len(result.CVEs))
+
+	for id, cve := range result.CVEs {
+		packageList := make([]cvemodel.Package, 0, len(cve.PackageList))
+
+		for _, pkg := range cve.PackageList {
+			packageList = append(packageList, cvemodel.Package{
+				Name:             pkg.Name,
+				PackagePath:      pkg.PackagePath,
+				InstalledVersion: pkg.InstalledVersion,
+				FixedVersion:     pkg.FixedVersion,
+			})
+		}
+
+		cveMap[id] = cvemodel.CVE{
+			ID:          cve.ID,
+			Description: cve.Description,
+			Severity:    cve.Severity,
+			Title:       cve.Title,
+			Reference:   cve.Reference,
+			PackageList: packageList,
+		}
+	}
+
+	return cveMap
+}
+
 func convertSeverity(detectedSeverity string) string {
 	trivySeverity, _ := dbTypes.NewSeverity(detectedSeverity)
 
@@ -5,17 +5,21 @@ package trivy
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/common"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	cvecache "zotregistry.dev/zot/pkg/extensions/search/cve/cache"
 	"zotregistry.dev/zot/pkg/extensions/search/cve/model"
@@ -80,7 +84,7 @@ func TestMultipleStoragePath(t *testing.T) {
 		metaDB, err := boltdb.New(boltDriver, log)
 		So(err, ShouldBeNil)
 
-		scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+		scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 		So(scanner.storeController.DefaultStore, ShouldNotBeNil)
 		So(scanner.storeController.SubStore, ShouldNotBeNil)
@@ -196,7 +200,7 @@ func TestTrivyLibraryErrors(t *testing.T) {
 		img := "zot-test:0.0.1" //nolint:goconst
 
 		// Download DB fails for invalid DB url
-		scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-not-db", "", log)
+		scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-not-db", "", "", log)
 
 		ctx := context.Background()
 
@@ -211,14 +215,14 @@ func TestTrivyLibraryErrors(t *testing.T) {
 
 		// Download DB fails for invalid Java DB
 		scanner = NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db",
-			"ghcr.io/project-zot/trivy-not-db", log)
+			"ghcr.io/project-zot/trivy-not-db", "", log)
 
 		err = scanner.UpdateDB(ctx)
 		So(err, ShouldNotBeNil)
 
 		// Download DB passes for valid Trivy DB url, and missing Trivy Java DB url
 		// Download DB is necessary since DB download on scan is disabled
-		scanner = NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+		scanner = NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 		// UpdateDB with good ctx
 		err = scanner.UpdateDB(ctx)
@@ -319,7 +323,7 @@ func TestImageScannable(t *testing.T) {
 	storeController.DefaultStore = store
 
 	scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db",
-		"ghcr.io/project-zot/trivy-java-db", log)
+		"ghcr.io/project-zot/trivy-java-db", "", log)
 
 	Convey("Valid image should be scannable", t, func() {
 		result, err := scanner.IsImageFormatScannable("repo1", "valid")
@@ -352,6 +356,364 @@ func TestImageScannable(t *testing.T) {
 	})
 }
 
+func TestSbomScanMode(t *testing.T) {
+	Convey("Sbom scan modes", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "")
+		ctx := context.Background()
+
+		boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		store := local.NewImageStore(rootDir, false, false, log, monitoring.NewMetricsServer(false, log),
+			nil, nil, nil, nil)
+		storeController := storage.StoreController{DefaultStore: store}
+
+		spdxSbom := []byte(`{"spdxVersion": "SPDX-2.3", "packages": [{"name": "openssl", "versionInfo": "3.0.2"}]}`)
+
+		// an artifact which can't be scanned by unpacking its layers
+		artifact := CreateImageWith().Layers([]Layer{{
+			MediaType: "application/vnd.unscannable.layer",
+			Digest:    godigest.FromString("artifact"),
+			Blob:      []byte("artifact"),
+		}}).DefaultConfig().Build()
+		image := CreateRandomImage()
+
+		err = WriteImageToFileSystem(artifact, "repo", "artifact", storeController)
+		So(err, ShouldBeNil)
+
+		err = WriteImageToFileSystem(image, "repo", "image", storeController)
+		So(err, ShouldBeNil)
+
+		sbom := CreateImageWith().LayerBlobs([][]byte{spdxSbom}).ArtifactConfig(meta.MediaTypeSPDX).
+			Subject(artifact.DescriptorRef()).Build()
+
+		err = WriteImageToFileSystem(sbom, "repo", sbom.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		multiarch := CreateMultiarchWith().Images([]Image{artifact}).Build()
+
+		err = WriteMultiArchImageToFileSystem(multiarch, "repo", "multiarch", storeController)
+		So(err, ShouldBeNil)
+
+		// the same artifact is pushed to another repo with an unsigned, empty sbom
+		err = WriteImageToFileSystem(artifact, "other", "artifact", storeController)
+		So(err, ShouldBeNil)
+
+		unsignedSbom := CreateImageWith().LayerBlobs([][]byte{[]byte(`{"spdxVersion": "SPDX-2.3"}`)}).
+			ArtifactConfig(meta.MediaTypeSPDX).Subject(artifact.DescriptorRef()).Build()
+
+		err = WriteImageToFileSystem(unsignedSbom, "other", unsignedSbom.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		err = meta.ParseStorage(metaDB, storeController, log)
+		So(err, ShouldBeNil)
+
+		// only sboms with a trusted signature are scanned
+		err = metaDB.AddManifestSignature("repo", sbom.Digest(), types.SignatureMetadata{
+			SignatureType:   "cosign",
+			SignatureDigest: godigest.FromString("signature").String(),
+			LayersInfo:      []types.LayerInfo{{Signer: "signer"}},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("Ignore", func() {
+			scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "",
+				extconf.SbomScanModeIgnore, log)
+
+			ok, err := scanner.IsImageFormatScannable("repo", "artifact")
+			So(errors.Is(err, zerr.ErrScanNotSupported), ShouldBeTrue)
+			So(ok, ShouldBeFalse)
+
+			ok, err = scanner.IsImageFormatScannable("repo", "image")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			sbomDigests, err := scanner.getTrustedSbomDigests(ctx, "repo", artifact.DigestStr())
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldBeEmpty)
+
+			documents, err := scanner.getSbomDocuments(ctx, "repo", artifact.DigestStr(), []string{sbom.DigestStr()})
+			So(err, ShouldBeNil)
+			So(documents, ShouldBeEmpty)
+		})
+
+		Convey("Prefer", func() {
+			scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "",
+				extconf.SbomScanModePrefer, log)
+
+			ok, err := scanner.IsImageFormatScannable("repo", "artifact")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = scanner.IsImageFormatScannable("repo", "image")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = scanner.IsImageFormatScannable("repo", "multiarch")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			// the unsigned sbom is not used, the layers of the artifact are scanned instead
+			ok, err = scanner.IsImageFormatScannable("other", "artifact")
+			So(errors.Is(err, zerr.ErrScanNotSupported), ShouldBeTrue)
+			So(ok, ShouldBeFalse)
+
+			sbomDigests, err := scanner.getTrustedSbomDigests(ctx, "other", artifact.DigestStr())
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldBeEmpty)
+
+			sbomDigests, err = scanner.getTrustedSbomDigests(ctx, "repo", artifact.DigestStr())
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldResemble, []string{sbom.DigestStr()})
+
+			documents, err := scanner.getSbomDocuments(ctx, "repo", artifact.DigestStr(), sbomDigests)
+			So(err, ShouldBeNil)
+			So(documents, ShouldHaveLength, 1)
+			So(documents[0].Format, ShouldEqual, meta.SbomFormatSPDX)
+			So(documents[0].Content, ShouldResemble, spdxSbom)
+
+			documents, err = scanner.getSbomDocuments(ctx, "repo", image.DigestStr(), nil)
+			So(err, ShouldBeNil)
+			So(documents, ShouldBeEmpty)
+
+			// the sbom is scanned, which requires the trivy DB
+			_, err = scanner.ScanImage(ctx, "repo:artifact")
+			So(err, ShouldEqual, zerr.ErrCVEDBNotFound)
+
+			_, err = scanner.getTrustedSbomDigests(ctx, "missing", artifact.DigestStr())
+			So(err, ShouldNotBeNil)
+
+			// the results of sbom scans are cached per sbom, they don't apply to the same image in other repos
+			resultKey, err := scanner.getScanResultKey(ctx, "repo", artifact.DigestStr())
+			So(err, ShouldBeNil)
+			So(resultKey, ShouldEqual, artifact.DigestStr()+"+"+sbom.DigestStr())
+
+			resultKey, err = scanner.getScanResultKey(ctx, "repo", multiarch.DigestStr())
+			So(err, ShouldBeNil)
+			So(resultKey, ShouldEqual, multiarch.DigestStr()+"+"+sbom.DigestStr())
+
+			resultKey, err = scanner.getScanResultKey(ctx, "other", artifact.DigestStr())
+			So(err, ShouldBeNil)
+			So(resultKey, ShouldEqual, artifact.DigestStr())
+
+			scanner.setScanResult(scanResultKey(artifact.DigestStr(), sbomDigests), map[string]model.CVE{})
+
+			So(scanner.IsResultCached("repo", artifact.DigestStr()), ShouldBeTrue)
+			So(scanner.GetCachedResult("repo", artifact.DigestStr()), ShouldNotBeNil)
+			So(scanner.IsResultCached("other", artifact.DigestStr()), ShouldBeFalse)
+			So(scanner.GetCachedResult("other", artifact.DigestStr()), ShouldBeNil)
+
+			cveMap, err := scanner.ScanImage(ctx, "repo:artifact")
+			So(err, ShouldBeNil)
+			So(cveMap, ShouldBeEmpty)
+
+			_, err = scanner.ScanImage(ctx, "other:artifact")
+			So(err, ShouldEqual, zerr.ErrCVEDBNotFound)
+
+			_, err = scanner.getScanResultKey(ctx, "repo", godigest.FromString("missing").String())
+			So(err, ShouldNotBeNil)
+			So(scanner.IsResultCached("repo", godigest.FromString("missing").String()), ShouldBeFalse)
+			So(scanner.GetCachedResult("repo", godigest.FromString("missing").String()), ShouldBeNil)
+		})
+
+		Convey("Require", func() {
+			scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "",
+				extconf.SbomScanModeRequire, log)
+
+			ok, err := scanner.IsImageFormatScannable("repo", "artifact")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = scanner.IsImageFormatScannable("repo", "image")
+			So(errors.Is(err, zerr.ErrSbomNotFound), ShouldBeTrue)
+			So(ok, ShouldBeFalse)
+
+			_, err = scanner.ScanImage(ctx, "repo:image")
+			So(err, ShouldEqual, zerr.ErrSbomNotFound)
+
+			_, err = scanner.ScanImage(ctx, "repo:artifact")
+			So(err, ShouldEqual, zerr.ErrCVEDBNotFound)
+
+			ok, err = scanner.IsImageFormatScannable("other", "artifact")
+			So(errors.Is(err, zerr.ErrSbomNotFound), ShouldBeTrue)
+			So(ok, ShouldBeFalse)
+
+			_, err = scanner.ScanImage(ctx, "other:artifact")
+			So(err, ShouldEqual, zerr.ErrSbomNotFound)
+		})
+
+		Convey("Errors", func() {
+			scanner := NewScanner(storeController, mocks.MetaDBMock{
+				GetRepoMetaFn: func(ctx context.Context, repo string) (types.RepoMeta, error) {
+					return types.RepoMeta{}, zerr.ErrRepoMetaNotFound
+				},
+				GetImageMetaFn: func(digest godigest.Digest) (types.ImageMeta, error) {
+					return artifact.AsImageMeta(), nil
+				},
+			}, "ghcr.io/project-zot/trivy-db", "", extconf.SbomScanModePrefer, log)
+
+			ok, err := scanner.IsImageMediaScannable("repo", artifact.DigestStr(), ispec.MediaTypeImageManifest)
+			So(err, ShouldNotBeNil)
+			So(ok, ShouldBeFalse)
+
+			scanner = NewScanner(storeController, mocks.MetaDBMock{
+				GetRepoMetaFn: func(ctx context.Context, repo string) (types.RepoMeta, error) {
+					return types.RepoMeta{Sboms: map[string][]types.SbomInfo{
+						artifact.DigestStr(): {{SbomManifestDigest: sbom.DigestStr()}},
+					}}, nil
+				},
+				GetImageMetaFn: func(digest godigest.Digest) (types.ImageMeta, error) {
+					return types.ImageMeta{}, zerr.ErrImageMetaNotFound
+				},
+			}, "ghcr.io/project-zot/trivy-db", "", extconf.SbomScanModeRequire, log)
+
+			// sboms which can't be read are skipped
+			_, err = scanner.getSbomDocuments(ctx, "repo", artifact.DigestStr(), []string{sbom.DigestStr()})
+			So(err, ShouldEqual, zerr.ErrSbomNotFound)
+		})
+	})
+}
+
+func TestPersistedScanResults(t *testing.T) {
+	Convey("Scan results are persisted in MetaDB", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "")
+
+		boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		store := local.NewImageStore(rootDir, false, false, log, monitoring.NewMetricsServer(false, log),
+			nil, nil, nil, nil)
+		storeController := storage.StoreController{DefaultStore: store}
+
+		// the version of the trivy DB is read from its metadata, the DB itself is not needed
+		setDBMetadata := func(updatedAt time.Time) {
+			dbDir := path.Join(rootDir, "_trivy", "db")
+			So(os.MkdirAll(dbDir, 0o755), ShouldBeNil)
+
+			err := metadata.NewClient(dbDir).Update(metadata.Metadata{Version: 2, UpdatedAt: updatedAt})
+			So(err, ShouldBeNil)
+		}
+
+		digest := godigest.FromString("image").String()
+		cveMap := map[string]model.CVE{
+			"CVE-2023-1255": {
+				ID:          "CVE-2023-1255",
+				Severity:    model.SeverityMedium,
+				Title:       "openssl: Input buffer over-read in AES-XTS implementation on 64 bit ARM",
+				PackageList: []model.Package{{Name: "libcrypto3", InstalledVersion: "3.0.8-r3", FixedVersion: "3.0.8-r4"}},
+			},
+		}
+
+		Convey("Without trivy DB the results are only cached in memory", func() {
+			scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
+			scanner.setScanResult(digest, cveMap)
+			So(scanner.IsResultCached("repo", digest), ShouldBeTrue)
+
+			_, err := metaDB.GetCVEScanResult(godigest.Digest(digest))
+			So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+		})
+
+		Convey("Results are reused by other scanners until the trivy DB is updated", func() {
+			setDBMetadata(time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC))
+
+			scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
+			scanner.setScanResult(digest, cveMap)
+
+			result, err := metaDB.GetCVEScanResult(godigest.Digest(digest))
+			So(err, ShouldBeNil)
+			So(result.DBVersion, ShouldEqual, "2")
+			So(result.DBUpdatedAt.Equal(time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			So(result.ScannedAt, ShouldNotBeZeroValue)
+
+			// eg. after a restart or on another replica sharing the same MetaDB
+			otherScanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
+			So(otherScanner.IsResultCached("repo", digest), ShouldBeTrue)
+			So(otherScanner.GetCachedResult("repo", digest), ShouldResemble, cveMap)
+			So(otherScanner.IsResultCached("repo", godigest.FromString("other").String()), ShouldBeFalse)
+
+			cachedMap, err := otherScanner.scanManifest(context.Background(), "repo", digest)
+			So(err, ShouldBeNil)
+			So(cachedMap, ShouldResemble, cveMap)
+
+			setDBMetadata(time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC))
+
+			updatedScanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
+			So(updatedScanner.IsResultCached("repo", digest), ShouldBeFalse)
+			So(updatedScanner.GetCachedResult("repo", digest), ShouldBeNil)
+
+			// the results of a newer build are used by the replicas which didn't download it yet
+			updatedScanner.setScanResult(digest, cveMap)
+
+			setDBMetadata(time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC))
+
+			outdatedScanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
+			So(outdatedScanner.IsResultCached("repo", digest), ShouldBeTrue)
+		})
+
+		Convey("Stale results are deleted", func() {
+			dbMetadata := &metadata.Metadata{Version: 2, UpdatedAt: time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC)}
+
+			So(isStaleScanResult(getCVEScanResult(cveMap, dbMetadata), dbMetadata), ShouldBeFalse)
+			So(isStaleScanResult(getCVEScanResult(cveMap, &metadata.Metadata{
+				Version: 2, UpdatedAt: dbMetadata.UpdatedAt.Add(time.Hour),
+			}), dbMetadata), ShouldBeFalse)
+			So(isStaleScanResult(getCVEScanResult(cveMap, &metadata.Metadata{
+				Version: 2, UpdatedAt: dbMetadata.UpdatedAt.Add(-time.Hour),
+			}), dbMetadata), ShouldBeTrue)
+			So(isStaleScanResult(getCVEScanResult(cveMap, &metadata.Metadata{
+				Version: 1, UpdatedAt: dbMetadata.UpdatedAt,
+			}), dbMetadata), ShouldBeTrue)
+
+			staleDigest := godigest.FromString("stale")
+
+			err := metaDB.SetCVEScanResult(staleDigest, getCVEScanResult(cveMap, &metadata.Metadata{
+				Version: 2, UpdatedAt: dbMetadata.UpdatedAt.Add(-time.Hour),
+			}))
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetCVEScanResult(godigest.Digest(digest), getCVEScanResult(cveMap, dbMetadata))
+			So(err, ShouldBeNil)
+
+			err = metaDB.DeleteCVEScanResults(func(result types.CVEScanResult) bool {
+				return isStaleScanResult(result, dbMetadata)
+			})
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetCVEScanResult(staleDigest)
+			So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+
+			_, err = metaDB.GetCVEScanResult(godigest.Digest(digest))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("MetaDB errors are not fatal", func() {
+			setDBMetadata(time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC))
+
+			scanner := NewScanner(storeController, mocks.MetaDBMock{
+				SetCVEScanResultFn: func(digest godigest.Digest, result types.CVEScanResult) error {
+					return zerr.ErrInjected
+				},
+				GetCVEScanResultFn: func(digest godigest.Digest) (types.CVEScanResult, error) {
+					return types.CVEScanResult{}, zerr.ErrInjected
+				},
+			}, "ghcr.io/project-zot/trivy-db", "", "", log)
+
+			So(scanner.IsResultCached("repo", digest), ShouldBeFalse)
+
+			scanner.setScanResult(digest, cveMap)
+			So(scanner.IsResultCached("repo", digest), ShouldBeTrue)
+		})
+	})
+}
+
 func TestTrivyDBUrl(t *testing.T) {
 	Convey("Test trivy DB download", t, func() {
 		// Create temporary directory
@@ -389,7 +751,7 @@ func TestTrivyDBUrl(t *testing.T) {
 		// But we are getting `response status code 429: toomanyrequests` from
 		// `ghcr.io/aquasecurity/trivy-db` and `ghcr.io/aquasecurity/trivy-java-db`
 		scanner := NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db",
-			"ghcr.io/project-zot/trivy-java-db", log)
+			"ghcr.io/project-zot/trivy-java-db", "", log)
 
 		ctx := context.Background()
 
@@ -434,11 +796,12 @@ func TestIsIndexScanable(t *testing.T) {
 				metaDB:          metaDB,
 				storeController: storeController,
 				cache:           cvecache.NewCveCache(cacheSize, log),
+				dbMetadata:      &atomic.Pointer[metadata.Metadata]{},
 			}
 
 			scanner.cache.Add("digest", make(map[string]model.CVE))
 
-			found, err := scanner.isIndexScannable("digest")
+			found, err := scanner.isIndexScannable("repo", "digest")
 			So(err, ShouldBeNil)
 			So(found, ShouldBeTrue)
 		})
@@ -472,9 +835,10 @@ func TestIsIndexScannableErrors(t *testing.T) {
 				metaDB:          metaDB,
 				storeController: storeController,
 				cache:           cvecache.NewCveCache(cacheSize, log),
+				dbMetadata:      &atomic.Pointer[metadata.Metadata]{},
 			}
 
-			ok, err := scanner.isIndexScannable(multiarch.DigestStr())
+			ok, err := scanner.isIndexScannable("repo", multiarch.DigestStr())
 			So(err, ShouldBeNil)
 			So(ok, ShouldBeFalse)
 		})
@@ -59,7 +59,7 @@ func TestScanBigTestFile(t *testing.T) {
 		cm.StartAndWait(port)
 		defer cm.StopServer()
 		// scan
-		scanner := trivy.NewScanner(ctlr.StoreController, ctlr.MetaDB, "ghcr.io/project-zot/trivy-db", "", ctlr.Log)
+		scanner := trivy.NewScanner(ctlr.StoreController, ctlr.MetaDB, "ghcr.io/project-zot/trivy-db", "", "", ctlr.Log)
 
 		err = scanner.UpdateDB(context.Background())
 		So(err, ShouldBeNil)
@@ -103,7 +103,7 @@ func TestScanningByDigest(t *testing.T) {
 		So(err, ShouldBeNil)
 
 		// scan
-		scanner := trivy.NewScanner(ctlr.StoreController, ctlr.MetaDB, "ghcr.io/project-zot/trivy-db", "", ctlr.Log)
+		scanner := trivy.NewScanner(ctlr.StoreController, ctlr.MetaDB, "ghcr.io/project-zot/trivy-db", "", "", ctlr.Log)
 
 		ctx := context.Background()
 
@@ -189,7 +189,7 @@ func TestVulnerableLayer(t *testing.T) {
 		err = meta.ParseStorage(metaDB, storeController, log)
 		So(err, ShouldBeNil)
 
-		scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+		scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 		err = scanner.UpdateDB(context.Background())
 		So(err, ShouldBeNil)
@@ -261,7 +261,7 @@ func TestVulnerableLayer(t *testing.T) {
 		So(err, ShouldBeNil)
 
 		scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db",
-			"ghcr.io/project-zot/trivy-java-db", log)
+			"ghcr.io/project-zot/trivy-java-db", "", log)
 
 		err = scanner.UpdateDB(context.Background())
 		So(err, ShouldBeNil)
@@ -287,6 +287,90 @@ func TestVulnerableLayer(t *testing.T) {
 	})
 }
 
+func TestScanSbom(t *testing.T) {
+	Convey("Scan the sbom attached to an image instead of its layers", t, func() {
+		// lodash 4.17.4 is affected by CVE-2019-10744, fixed in 4.17.12
+		cycloneDXSbom := []byte(`{
+			"bomFormat": "CycloneDX",
+			"specVersion": "1.5",
+			"version": 1,
+			"metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+			"components": [{
+				"bom-ref": "pkg:npm/lodash@4.17.4",
+				"type": "library",
+				"name": "lodash",
+				"version": "4.17.4",
+				"purl": "pkg:npm/lodash@4.17.4"
+			}]
+		}`)
+
+		img := CreateRandomImage()
+		sbom := CreateImageWith().LayerBlobs([][]byte{cycloneDXSbom}).
+			ArtifactConfig(meta.MediaTypeCycloneDX).Subject(img.DescriptorRef()).Build()
+
+		tempDir := t.TempDir()
+
+		log := log.NewLogger("debug", "")
+		imageStore := local.NewImageStore(tempDir, false, false,
+			log, monitoring.NewMetricsServer(false, log), nil, nil, nil, nil)
+
+		storeController := storage.StoreController{
+			DefaultStore: imageStore,
+		}
+
+		err := WriteImageToFileSystem(img, "repo", "tag", storeController)
+		So(err, ShouldBeNil)
+
+		err = WriteImageToFileSystem(sbom, "repo", sbom.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		params := boltdb.DBParameters{
+			RootDir: tempDir,
+		}
+		boltDriver, err := boltdb.GetBoltDriver(params)
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		err = meta.ParseStorage(metaDB, storeController, log)
+		So(err, ShouldBeNil)
+
+		// only sboms with a trusted signature are scanned instead of the layers
+		err = metaDB.AddManifestSignature("repo", sbom.Digest(), types.SignatureMetadata{
+			SignatureType:   "cosign",
+			SignatureDigest: godigest.FromString("signature").String(),
+			LayersInfo:      []types.LayerInfo{{Signer: "signer"}},
+		})
+		So(err, ShouldBeNil)
+
+		scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "",
+			extconf.SbomScanModePrefer, log)
+
+		err = scanner.UpdateDB(context.Background())
+		So(err, ShouldBeNil)
+
+		cveMap, err := scanner.ScanImage(context.Background(), "repo:tag")
+		So(err, ShouldBeNil)
+		So(cveMap, ShouldContainKey, "CVE-2019-10744")
+		So(cveMap["CVE-2019-10744"].PackageList[0].Name, ShouldEqual, "lodash")
+		So(cveMap["CVE-2019-10744"].PackageList[0].InstalledVersion, ShouldEqual, "4.17.4")
+
+		// the results are cached under the digests of the image and of the scanned sbom
+		So(scanner.GetCachedResult("repo", img.DigestStr()), ShouldResemble, cveMap)
+
+		scanner = trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "",
+			extconf.SbomScanModeIgnore, log)
+
+		err = scanner.UpdateDB(context.Background())
+		So(err, ShouldBeNil)
+
+		cveMap, err = scanner.ScanImage(context.Background(), "repo:tag")
+		So(err, ShouldBeNil)
+		So(cveMap, ShouldNotContainKey, "CVE-2019-10744")
+	})
+}
+
 func TestScannerErrors(t *testing.T) {
 	Convey("Errors", t, func() {
 		storeController := storage.StoreController{}
@@ -298,7 +382,7 @@ func TestScannerErrors(t *testing.T) {
 			metaDB.GetImageMetaFn = func(digest godigest.Digest) (types.ImageMeta, error) {
 				return types.ImageMeta{}, ErrTestError
 			}
-			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 			_, err := scanner.IsImageFormatScannable("repo", godigest.FromString("dig").String())
 			So(err, ShouldNotBeNil)
@@ -308,7 +392,7 @@ func TestScannerErrors(t *testing.T) {
 			metaDB.GetImageMetaFn = func(digest godigest.Digest) (types.ImageMeta, error) {
 				return types.ImageMeta{}, ErrTestError
 			}
-			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 			Convey("Manifest", func() {
 				_, err := scanner.IsImageMediaScannable("repo", godigest.FromString("dig").String(), ispec.MediaTypeImageManifest)
@@ -322,7 +406,7 @@ func TestScannerErrors(t *testing.T) {
 				metaDB.GetImageMetaFn = func(digest godigest.Digest) (types.ImageMeta, error) {
 					return types.ImageMeta{}, nil
 				}
-				scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+				scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 				_, err := scanner.IsImageMediaScannable("repo", godigest.FromString("dig").String(), ispec.MediaTypeImageIndex)
 				So(err, ShouldNotBeNil)
@@ -338,7 +422,7 @@ func TestScannerErrors(t *testing.T) {
 						}}},
 					}, nil
 				}
-				scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+				scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 				_, err := scanner.IsImageMediaScannable("repo", godigest.FromString("dig").String(), ispec.MediaTypeImageIndex)
 				So(err, ShouldBeNil)
@@ -350,7 +434,7 @@ func TestScannerErrors(t *testing.T) {
 				return types.ImageMeta{}, ErrTestError
 			}
 
-			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", log)
+			scanner := trivy.NewScanner(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", "", log)
 
 			_, err := scanner.ScanImage(context.Background(), "image@"+godigest.FromString("digest").String())
 			So(err, ShouldNotBeNil)
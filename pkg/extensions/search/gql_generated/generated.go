@@ -113,6 +113,7 @@ type ComplexityRoot struct {
 		PushTimestamp     func(childComplexity int) int
 		Referrers         func(childComplexity int) int
 		RepoName          func(childComplexity int) int
+		SbomSummary       func(childComplexity int) int
 		SignatureInfo     func(childComplexity int) int
 		Size              func(childComplexity int) int
 		Source            func(childComplexity int) int
@@ -165,6 +166,13 @@ type ComplexityRoot struct {
 		PackagePath      func(childComplexity int) int
 	}
 
+	PackageResultForImage struct {
+		PackageList func(childComplexity int) int
+		Page        func(childComplexity int) int
+		Summary     func(childComplexity int) int
+		Tag         func(childComplexity int) int
+	}
+
 	PageInfo struct {
 		ItemCount  func(childComplexity int) int
 		TotalCount func(childComplexity int) int
@@ -197,7 +205,9 @@ type ComplexityRoot struct {
 		ImageList               func(childComplexity int, repo string, requestedPage *PageInput) int
 		ImageListForCve         func(childComplexity int, id string, filter *Filter, requestedPage *PageInput) int
 		ImageListForDigest      func(childComplexity int, id string, requestedPage *PageInput) int
+		ImageListForPackage     func(childComplexity int, name string, version *string, filter *Filter, requestedPage *PageInput) int
 		ImageListWithCVEFixed   func(childComplexity int, id string, image string, filter *Filter, requestedPage *PageInput) int
+		PackageListForImage     func(childComplexity int, image string, searchedPackage *string, requestedPage *PageInput) int
 		Referrers               func(childComplexity int, repo string, digest string, typeArg []string) int
 		RepoListWithNewestImage func(childComplexity int, requestedPage *PageInput) int
 		StarredRepos            func(childComplexity int, requestedPage *PageInput) int
@@ -230,6 +240,17 @@ type ComplexityRoot struct {
 		Vendors       func(childComplexity int) int
 	}
 
+	SbomPackage struct {
+		Name    func(childComplexity int) int
+		Purl    func(childComplexity int) int
+		Version func(childComplexity int) int
+	}
+
+	SbomSummary struct {
+		Formats      func(childComplexity int) int
+		PackageCount func(childComplexity int) int
+	}
+
 	SignatureSummary struct {
 		Author    func(childComplexity int) int
 		IsTrusted func(childComplexity int) int
@@ -241,6 +262,8 @@ type QueryResolver interface {
 	CVEListForImage(ctx context.Context, image string, requestedPage *PageInput, searchedCve *string, excludedCve *string, severity *string) (*CVEResultForImage, error)
 	CVEDiffListForImages(ctx context.Context, minuend ImageInput, subtrahend ImageInput, requestedPage *PageInput, searchedCve *string, excludedCve *string) (*CVEDiffResult, error)
 	ImageListForCve(ctx context.Context, id string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
+	PackageListForImage(ctx context.Context, image string, searchedPackage *string, requestedPage *PageInput) (*PackageResultForImage, error)
+	ImageListForPackage(ctx context.Context, name string, version *string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
 	ImageListWithCVEFixed(ctx context.Context, id string, image string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
 	ImageListForDigest(ctx context.Context, id string, requestedPage *PageInput) (*PaginatedImagesResult, error)
 	RepoListWithNewestImage(ctx context.Context, requestedPage *PageInput) (*PaginatedReposResult, error)
@@ -596,6 +619,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.ImageSummary.RepoName(childComplexity), true
 
+	case "ImageSummary.SbomSummary":
+		if e.complexity.ImageSummary.SbomSummary == nil {
+			break
+		}
+
+		return e.complexity.ImageSummary.SbomSummary(childComplexity), true
+
 	case "ImageSummary.SignatureInfo":
 		if e.complexity.ImageSummary.SignatureInfo == nil {
 			break
@@ -841,6 +871,34 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.PackageInfo.PackagePath(childComplexity), true
 
+	case "PackageResultForImage.PackageList":
+		if e.complexity.PackageResultForImage.PackageList == nil {
+			break
+		}
+
+		return e.complexity.PackageResultForImage.PackageList(childComplexity), true
+
+	case "PackageResultForImage.Page":
+		if e.complexity.PackageResultForImage.Page == nil {
+			break
+		}
+
+		return e.complexity.PackageResultForImage.Page(childComplexity), true
+
+	case "PackageResultForImage.Summary":
+		if e.complexity.PackageResultForImage.Summary == nil {
+			break
+		}
+
+		return e.complexity.PackageResultForImage.Summary(childComplexity), true
+
+	case "PackageResultForImage.Tag":
+		if e.complexity.PackageResultForImage.Tag == nil {
+			break
+		}
+
+		return e.complexity.PackageResultForImage.Tag(childComplexity), true
+
 	case "PageInfo.ItemCount":
 		if e.complexity.PageInfo.ItemCount == nil {
 			break
@@ -1029,6 +1087,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Query.ImageListForDigest(childComplexity, args["id"].(string), args["requestedPage"].(*PageInput)), true
 
+	case "Query.ImageListForPackage":
+		if e.complexity.Query.ImageListForPackage == nil {
+			break
+		}
+
+		args, err := ec.field_Query_ImageListForPackage_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.ImageListForPackage(childComplexity, args["name"].(string), args["version"].(*string), args["filter"].(*Filter), args["requestedPage"].(*PageInput)), true
+
 	case "Query.ImageListWithCVEFixed":
 		if e.complexity.Query.ImageListWithCVEFixed == nil {
 			break
@@ -1041,6 +1111,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Query.ImageListWithCVEFixed(childComplexity, args["id"].(string), args["image"].(string), args["filter"].(*Filter), args["requestedPage"].(*PageInput)), true
 
+	case "Query.PackageListForImage":
+		if e.complexity.Query.PackageListForImage == nil {
+			break
+		}
+
+		args, err := ec.field_Query_PackageListForImage_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.PackageListForImage(childComplexity, args["image"].(string), args["searchedPackage"].(*string), args["requestedPage"].(*PageInput)), true
+
 	case "Query.Referrers":
 		if e.complexity.Query.Referrers == nil {
 			break
@@ -1203,6 +1285,41 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.RepoSummary.Vendors(childComplexity), true
 
+	case "SbomPackage.Name":
+		if e.complexity.SbomPackage.Name == nil {
+			break
+		}
+
+		return e.complexity.SbomPackage.Name(childComplexity), true
+
+	case "SbomPackage.PURL":
+		if e.complexity.SbomPackage.Purl == nil {
+			break
+		}
+
+		return e.complexity.SbomPackage.Purl(childComplexity), true
+
+	case "SbomPackage.Version":
+		if e.complexity.SbomPackage.Version == nil {
+			break
+		}
+
+		return e.complexity.SbomPackage.Version(childComplexity), true
+
+	case "SbomSummary.Formats":
+		if e.complexity.SbomSummary.Formats == nil {
+			break
+		}
+
+		return e.complexity.SbomSummary.Formats(childComplexity), true
+
+	case "SbomSummary.PackageCount":
+		if e.complexity.SbomSummary.PackageCount == nil {
+			break
+		}
+
+		return e.complexity.SbomSummary.PackageCount(childComplexity), true
+
 	case "SignatureSummary.Author":
 		if e.complexity.SignatureSummary.Author == nil {
 			break
@@ -1454,6 +1571,60 @@ type PackageInfo {
     FixedVersion: String
 }
 
+"""
+Contains the tag of the image and the list of packages found in its SBOMs
+"""
+type PackageResultForImage {
+    """
+    Tag of the image
+    """
+    Tag: String
+    """
+    List of packages listed by the SBOMs of this image
+    """
+    PackageList: [SbomPackage]
+    """
+    Summary of the SBOMs of this image
+    """
+    Summary: SbomSummary
+    """
+    The package pagination information, see PageInfo object for more details
+    """
+    Page: PageInfo
+}
+
+"""
+Contains a package listed by an SPDX or CycloneDX SBOM
+"""
+type SbomPackage {
+    """
+    Name of the package
+    """
+    Name: String
+    """
+    Version of the package
+    """
+    Version: String
+    """
+    Package URL identifying the package, if present in the SBOM
+    """
+    PURL: String
+}
+
+"""
+Contains summary of the SBOMs referring to a specific image
+"""
+type SbomSummary {
+    """
+    Formats of the SBOMs found for this image, "spdx" or "cyclonedx"
+    """
+    Formats: [String]
+    """
+    Number of packages listed by the SBOMs of this image
+    """
+    PackageCount: Int
+}
+
 """
 Contains details about the repo: both general information on the repo, and the list of images
 """
@@ -1586,6 +1757,10 @@ type ImageSummary {
     """
     Referrers: [Referrer]
     """
+    Summary of the SBOMs referring to this image or to its manifests
+    """
+    SbomSummary: SbomSummary
+    """
     True if current user has delete permission on this tag.
     """
     IsDeletable: Boolean
@@ -2085,6 +2260,32 @@ type Query {
         requestedPage: PageInput
     ): PaginatedImagesResult!
 
+    """
+    Returns the list of packages found in the SBOMs of the image specified in the argument
+    """
+    PackageListForImage(
+        "Image name in format ` + "`" + `repository:tag` + "`" + ` or ` + "`" + `repository@digest` + "`" + `"
+        image: String!,
+        "Search term for specific packages by name or purl"
+        searchedPackage: String
+        "Sets the parameters of the requested page"
+        requestedPage: PageInput
+    ): PackageResultForImage!
+
+    """
+    Returns a list of images whose SBOMs contain the specified package
+    """
+    ImageListForPackage(
+        "Package name or purl"
+        name: String!,
+        "Package version, if missing images containing any version of the package are returned"
+        version: String,
+        "Filter to apply before returning the results"
+        filter: Filter,
+        "Sets the parameters of the requested page"
+        requestedPage: PageInput
+    ): PaginatedImagesResult!
+
     """
     Returns a list of images that are no longer vulnerable to the CVE of the specified ID,
     from the specified repository
@@ -2863,6 +3064,103 @@ func (ec *executionContext) field_Query_ImageListForDigest_argsRequestedPage(
 	return zeroVal, nil
 }
 
+func (ec *executionContext) field_Query_ImageListForPackage_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field_Query_ImageListForPackage_argsName(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["name"] = arg0
+	arg1, err := ec.field_Query_ImageListForPackage_argsVersion(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["version"] = arg1
+	arg2, err := ec.field_Query_ImageListForPackage_argsFilter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["filter"] = arg2
+	arg3, err := ec.field_Query_ImageListForPackage_argsRequestedPage(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["requestedPage"] = arg3
+	return args, nil
+}
+func (ec *executionContext) field_Query_ImageListForPackage_argsName(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (string, error) {
+	if _, ok := rawArgs["name"]; !ok {
+		var zeroVal string
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+	if tmp, ok := rawArgs["name"]; ok {
+		return ec.unmarshalNString2string(ctx, tmp)
+	}
+
+	var zeroVal string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_ImageListForPackage_argsVersion(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	if _, ok := rawArgs["version"]; !ok {
+		var zeroVal *string
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("version"))
+	if tmp, ok := rawArgs["version"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_ImageListForPackage_argsFilter(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*Filter, error) {
+	if _, ok := rawArgs["filter"]; !ok {
+		var zeroVal *Filter
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("filter"))
+	if tmp, ok := rawArgs["filter"]; ok {
+		return ec.unmarshalOFilter2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFilter(ctx, tmp)
+	}
+
+	var zeroVal *Filter
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_ImageListForPackage_argsRequestedPage(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*PageInput, error) {
+	if _, ok := rawArgs["requestedPage"]; !ok {
+		var zeroVal *PageInput
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("requestedPage"))
+	if tmp, ok := rawArgs["requestedPage"]; ok {
+		return ec.unmarshalOPageInput2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInput(ctx, tmp)
+	}
+
+	var zeroVal *PageInput
+	return zeroVal, nil
+}
+
 func (ec *executionContext) field_Query_ImageListWithCVEFixed_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -3039,55 +3337,37 @@ func (ec *executionContext) field_Query_Image_argsImage(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_Referrers_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Query_PackageListForImage_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field_Query_Referrers_argsRepo(ctx, rawArgs)
+	arg0, err := ec.field_Query_PackageListForImage_argsImage(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["repo"] = arg0
-	arg1, err := ec.field_Query_Referrers_argsDigest(ctx, rawArgs)
+	args["image"] = arg0
+	arg1, err := ec.field_Query_PackageListForImage_argsSearchedPackage(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["digest"] = arg1
-	arg2, err := ec.field_Query_Referrers_argsType(ctx, rawArgs)
+	args["searchedPackage"] = arg1
+	arg2, err := ec.field_Query_PackageListForImage_argsRequestedPage(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["type"] = arg2
+	args["requestedPage"] = arg2
 	return args, nil
 }
-func (ec *executionContext) field_Query_Referrers_argsRepo(
-	ctx context.Context,
-	rawArgs map[string]any,
-) (string, error) {
-	if _, ok := rawArgs["repo"]; !ok {
-		var zeroVal string
-		return zeroVal, nil
-	}
-
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("repo"))
-	if tmp, ok := rawArgs["repo"]; ok {
-		return ec.unmarshalNString2string(ctx, tmp)
-	}
-
-	var zeroVal string
-	return zeroVal, nil
-}
-
-func (ec *executionContext) field_Query_Referrers_argsDigest(
+func (ec *executionContext) field_Query_PackageListForImage_argsImage(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (string, error) {
-	if _, ok := rawArgs["digest"]; !ok {
+	if _, ok := rawArgs["image"]; !ok {
 		var zeroVal string
 		return zeroVal, nil
 	}
 
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("digest"))
-	if tmp, ok := rawArgs["digest"]; ok {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("image"))
+	if tmp, ok := rawArgs["image"]; ok {
 		return ec.unmarshalNString2string(ctx, tmp)
 	}
 
@@ -3095,35 +3375,25 @@ func (ec *executionContext) field_Query_Referrers_argsDigest(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_Referrers_argsType(
+func (ec *executionContext) field_Query_PackageListForImage_argsSearchedPackage(
 	ctx context.Context,
 	rawArgs map[string]any,
-) ([]string, error) {
-	if _, ok := rawArgs["type"]; !ok {
-		var zeroVal []string
+) (*string, error) {
+	if _, ok := rawArgs["searchedPackage"]; !ok {
+		var zeroVal *string
 		return zeroVal, nil
 	}
 
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
-	if tmp, ok := rawArgs["type"]; ok {
-		return ec.unmarshalOString2ᚕstringᚄ(ctx, tmp)
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("searchedPackage"))
+	if tmp, ok := rawArgs["searchedPackage"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
-	var zeroVal []string
+	var zeroVal *string
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_RepoListWithNewestImage_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
-	var err error
-	args := map[string]any{}
-	arg0, err := ec.field_Query_RepoListWithNewestImage_argsRequestedPage(ctx, rawArgs)
-	if err != nil {
-		return nil, err
-	}
-	args["requestedPage"] = arg0
-	return args, nil
-}
-func (ec *executionContext) field_Query_RepoListWithNewestImage_argsRequestedPage(
+func (ec *executionContext) field_Query_PackageListForImage_argsRequestedPage(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*PageInput, error) {
@@ -3141,7 +3411,109 @@ func (ec *executionContext) field_Query_RepoListWithNewestImage_argsRequestedPag
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_StarredRepos_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Query_Referrers_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field_Query_Referrers_argsRepo(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["repo"] = arg0
+	arg1, err := ec.field_Query_Referrers_argsDigest(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["digest"] = arg1
+	arg2, err := ec.field_Query_Referrers_argsType(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["type"] = arg2
+	return args, nil
+}
+func (ec *executionContext) field_Query_Referrers_argsRepo(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (string, error) {
+	if _, ok := rawArgs["repo"]; !ok {
+		var zeroVal string
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("repo"))
+	if tmp, ok := rawArgs["repo"]; ok {
+		return ec.unmarshalNString2string(ctx, tmp)
+	}
+
+	var zeroVal string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_Referrers_argsDigest(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (string, error) {
+	if _, ok := rawArgs["digest"]; !ok {
+		var zeroVal string
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("digest"))
+	if tmp, ok := rawArgs["digest"]; ok {
+		return ec.unmarshalNString2string(ctx, tmp)
+	}
+
+	var zeroVal string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_Referrers_argsType(
+	ctx context.Context,
+	rawArgs map[string]any,
+) ([]string, error) {
+	if _, ok := rawArgs["type"]; !ok {
+		var zeroVal []string
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
+	if tmp, ok := rawArgs["type"]; ok {
+		return ec.unmarshalOString2ᚕstringᚄ(ctx, tmp)
+	}
+
+	var zeroVal []string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_RepoListWithNewestImage_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field_Query_RepoListWithNewestImage_argsRequestedPage(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["requestedPage"] = arg0
+	return args, nil
+}
+func (ec *executionContext) field_Query_RepoListWithNewestImage_argsRequestedPage(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*PageInput, error) {
+	if _, ok := rawArgs["requestedPage"]; !ok {
+		var zeroVal *PageInput
+		return zeroVal, nil
+	}
+
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("requestedPage"))
+	if tmp, ok := rawArgs["requestedPage"]; ok {
+		return ec.unmarshalOPageInput2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInput(ctx, tmp)
+	}
+
+	var zeroVal *PageInput
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_StarredRepos_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
 	arg0, err := ec.field_Query_StarredRepos_argsRequestedPage(ctx, rawArgs)
@@ -4249,6 +4621,8 @@ func (ec *executionContext) fieldContext_GlobalSearchResult_Images(_ context.Con
 				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
 			case "Referrers":
 				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			case "SbomSummary":
+				return ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
 			case "IsDeletable":
 				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
 			}
@@ -5717,6 +6091,53 @@ func (ec *executionContext) fieldContext_ImageSummary_Referrers(_ context.Contex
 	return fc, nil
 }
 
+func (ec *executionContext) _ImageSummary_SbomSummary(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.SbomSummary, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*SbomSummary)
+	fc.Result = res
+	return ec.marshalOSbomSummary2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomSummary(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ImageSummary_SbomSummary(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ImageSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Formats":
+				return ec.fieldContext_SbomSummary_Formats(ctx, field)
+			case "PackageCount":
+				return ec.fieldContext_SbomSummary_PackageCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SbomSummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ImageSummary_IsDeletable(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
 	if err != nil {
@@ -6978,8 +7399,8 @@ func (ec *executionContext) fieldContext_PackageInfo_FixedVersion(_ context.Cont
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PageInfo_TotalCount(ctx, field)
+func (ec *executionContext) _PackageResultForImage_Tag(ctx context.Context, field graphql.CollectedField, obj *PackageResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageResultForImage_Tag(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6992,38 +7413,35 @@ func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.TotalCount, nil
+		return obj.Tag, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_TotalCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PackageResultForImage_Tag(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "PackageResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PageInfo_ItemCount(ctx, field)
+func (ec *executionContext) _PackageResultForImage_PackageList(ctx context.Context, field graphql.CollectedField, obj *PackageResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageResultForImage_PackageList(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7036,38 +7454,43 @@ func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ItemCount, nil
+		return obj.PackageList, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.([]*SbomPackage)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOSbomPackage2ᚕᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomPackage(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_ItemCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PackageResultForImage_PackageList(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "PackageResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "Name":
+				return ec.fieldContext_SbomPackage_Name(ctx, field)
+			case "Version":
+				return ec.fieldContext_SbomPackage_Version(ctx, field)
+			case "PURL":
+				return ec.fieldContext_SbomPackage_PURL(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SbomPackage", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+func (ec *executionContext) _PackageResultForImage_Summary(ctx context.Context, field graphql.CollectedField, obj *PackageResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageResultForImage_Summary(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7080,7 +7503,7 @@ func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.Summary, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7089,32 +7512,32 @@ func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, fie
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.(*SbomSummary)
 	fc.Result = res
-	return ec.marshalOPageInfo2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOSbomSummary2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedImagesResult_Page(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PackageResultForImage_Summary(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedImagesResult",
+		Object:     "PackageResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "TotalCount":
-				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
-			case "ItemCount":
-				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			case "Formats":
+				return ec.fieldContext_SbomSummary_Formats(ctx, field)
+			case "PackageCount":
+				return ec.fieldContext_SbomSummary_PackageCount(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type SbomSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+func (ec *executionContext) _PackageResultForImage_Page(ctx context.Context, field graphql.CollectedField, obj *PackageResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageResultForImage_Page(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7127,86 +7550,41 @@ func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Results, nil
+		return obj.Page, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*ImageSummary)
+	res := resTmp.(*PageInfo)
 	fc.Result = res
-	return ec.marshalNImageSummary2ᚕᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummaryᚄ(ctx, field.Selections, res)
+	return ec.marshalOPageInfo2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedImagesResult_Results(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_PackageResultForImage_Page(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedImagesResult",
+		Object:     "PackageResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "RepoName":
-				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
-			case "Tag":
-				return ec.fieldContext_ImageSummary_Tag(ctx, field)
-			case "Digest":
-				return ec.fieldContext_ImageSummary_Digest(ctx, field)
-			case "MediaType":
-				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
-			case "Manifests":
-				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
-			case "Size":
-				return ec.fieldContext_ImageSummary_Size(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
-			case "LastPullTimestamp":
-				return ec.fieldContext_ImageSummary_LastPullTimestamp(ctx, field)
-			case "PushTimestamp":
-				return ec.fieldContext_ImageSummary_PushTimestamp(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
-			case "Description":
-				return ec.fieldContext_ImageSummary_Description(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
-			case "Licenses":
-				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
-			case "Labels":
-				return ec.fieldContext_ImageSummary_Labels(ctx, field)
-			case "Title":
-				return ec.fieldContext_ImageSummary_Title(ctx, field)
-			case "Source":
-				return ec.fieldContext_ImageSummary_Source(ctx, field)
-			case "Documentation":
-				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
-			case "Vendor":
-				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
-			case "Authors":
-				return ec.fieldContext_ImageSummary_Authors(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
-			case "IsDeletable":
-				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_TotalCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7219,16 +7597,245 @@ func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.TotalCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_TotalCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_ItemCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ItemCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_ItemCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Page, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*PageInfo)
+	fc.Result = res
+	return ec.marshalOPageInfo2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedImagesResult_Page(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedImagesResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Results, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*ImageSummary)
+	fc.Result = res
+	return ec.marshalNImageSummary2ᚕᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummaryᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedImagesResult_Results(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedImagesResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "RepoName":
+				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
+			case "Tag":
+				return ec.fieldContext_ImageSummary_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_ImageSummary_Digest(ctx, field)
+			case "MediaType":
+				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
+			case "Manifests":
+				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
+			case "Size":
+				return ec.fieldContext_ImageSummary_Size(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPullTimestamp":
+				return ec.fieldContext_ImageSummary_LastPullTimestamp(ctx, field)
+			case "PushTimestamp":
+				return ec.fieldContext_ImageSummary_PushTimestamp(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+			case "Description":
+				return ec.fieldContext_ImageSummary_Description(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+			case "Licenses":
+				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
+			case "Labels":
+				return ec.fieldContext_ImageSummary_Labels(ctx, field)
+			case "Title":
+				return ec.fieldContext_ImageSummary_Title(ctx, field)
+			case "Source":
+				return ec.fieldContext_ImageSummary_Source(ctx, field)
+			case "Documentation":
+				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
+			case "Vendor":
+				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
+			case "Authors":
+				return ec.fieldContext_ImageSummary_Authors(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			case "SbomSummary":
+				return ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
+			case "IsDeletable":
+				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Page, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*PageInfo)
 	fc.Result = res
 	return ec.marshalOPageInfo2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
 }
@@ -7402,8 +8009,140 @@ func (ec *executionContext) fieldContext_Platform_Arch(_ context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_CVEListForImage(ctx, field)
+func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_CVEListForImage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().CVEListForImage(rctx, fc.Args["image"].(string), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string), fc.Args["excludedCVE"].(*string), fc.Args["severity"].(*string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*CVEResultForImage)
+	fc.Result = res
+	return ec.marshalNCVEResultForImage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Tag":
+				return ec.fieldContext_CVEResultForImage_Tag(ctx, field)
+			case "CVEList":
+				return ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
+			case "Summary":
+				return ec.fieldContext_CVEResultForImage_Summary(ctx, field)
+			case "Page":
+				return ec.fieldContext_CVEResultForImage_Page(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type CVEResultForImage", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_CVEListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_CVEDiffListForImages(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_CVEDiffListForImages(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().CVEDiffListForImages(rctx, fc.Args["minuend"].(ImageInput), fc.Args["subtrahend"].(ImageInput), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string), fc.Args["excludedCVE"].(*string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*CVEDiffResult)
+	fc.Result = res
+	return ec.marshalNCVEDiffResult2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEDiffResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_CVEDiffListForImages(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Minuend":
+				return ec.fieldContext_CVEDiffResult_Minuend(ctx, field)
+			case "Subtrahend":
+				return ec.fieldContext_CVEDiffResult_Subtrahend(ctx, field)
+			case "CVEList":
+				return ec.fieldContext_CVEDiffResult_CVEList(ctx, field)
+			case "Summary":
+				return ec.fieldContext_CVEDiffResult_Summary(ctx, field)
+			case "Page":
+				return ec.fieldContext_CVEDiffResult_Page(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type CVEDiffResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_CVEDiffListForImages_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListForCVE(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7416,7 +8155,7 @@ func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().CVEListForImage(rctx, fc.Args["image"].(string), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string), fc.Args["excludedCVE"].(*string), fc.Args["severity"].(*string))
+		return ec.resolvers.Query().ImageListForCve(rctx, fc.Args["id"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7428,12 +8167,12 @@ func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field gr
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*CVEResultForImage)
+	res := resTmp.(*PaginatedImagesResult)
 	fc.Result = res
-	return ec.marshalNCVEResultForImage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx, field.Selections, res)
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -7441,16 +8180,12 @@ func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Conte
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Tag":
-				return ec.fieldContext_CVEResultForImage_Tag(ctx, field)
-			case "CVEList":
-				return ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
-			case "Summary":
-				return ec.fieldContext_CVEResultForImage_Summary(ctx, field)
 			case "Page":
-				return ec.fieldContext_CVEResultForImage_Page(ctx, field)
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type CVEResultForImage", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
 		},
 	}
 	defer func() {
@@ -7460,15 +8195,15 @@ func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Conte
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_CVEListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_ImageListForCVE_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_CVEDiffListForImages(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_CVEDiffListForImages(ctx, field)
+func (ec *executionContext) _Query_PackageListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_PackageListForImage(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7481,7 +8216,7 @@ func (ec *executionContext) _Query_CVEDiffListForImages(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().CVEDiffListForImages(rctx, fc.Args["minuend"].(ImageInput), fc.Args["subtrahend"].(ImageInput), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string), fc.Args["excludedCVE"].(*string))
+		return ec.resolvers.Query().PackageListForImage(rctx, fc.Args["image"].(string), fc.Args["searchedPackage"].(*string), fc.Args["requestedPage"].(*PageInput))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7493,12 +8228,12 @@ func (ec *executionContext) _Query_CVEDiffListForImages(ctx context.Context, fie
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*CVEDiffResult)
+	res := resTmp.(*PackageResultForImage)
 	fc.Result = res
-	return ec.marshalNCVEDiffResult2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEDiffResult(ctx, field.Selections, res)
+	return ec.marshalNPackageResultForImage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageResultForImage(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_CVEDiffListForImages(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_PackageListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -7506,18 +8241,16 @@ func (ec *executionContext) fieldContext_Query_CVEDiffListForImages(ctx context.
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Minuend":
-				return ec.fieldContext_CVEDiffResult_Minuend(ctx, field)
-			case "Subtrahend":
-				return ec.fieldContext_CVEDiffResult_Subtrahend(ctx, field)
-			case "CVEList":
-				return ec.fieldContext_CVEDiffResult_CVEList(ctx, field)
+			case "Tag":
+				return ec.fieldContext_PackageResultForImage_Tag(ctx, field)
+			case "PackageList":
+				return ec.fieldContext_PackageResultForImage_PackageList(ctx, field)
 			case "Summary":
-				return ec.fieldContext_CVEDiffResult_Summary(ctx, field)
+				return ec.fieldContext_PackageResultForImage_Summary(ctx, field)
 			case "Page":
-				return ec.fieldContext_CVEDiffResult_Page(ctx, field)
+				return ec.fieldContext_PackageResultForImage_Page(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type CVEDiffResult", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PackageResultForImage", field.Name)
 		},
 	}
 	defer func() {
@@ -7527,15 +8260,15 @@ func (ec *executionContext) fieldContext_Query_CVEDiffListForImages(ctx context.
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_CVEDiffListForImages_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_PackageListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ImageListForCVE(ctx, field)
+func (ec *executionContext) _Query_ImageListForPackage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListForPackage(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7548,7 +8281,7 @@ func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ImageListForCve(rctx, fc.Args["id"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+		return ec.resolvers.Query().ImageListForPackage(rctx, fc.Args["name"].(string), fc.Args["version"].(*string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7565,7 +8298,7 @@ func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field gr
 	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_ImageListForPackage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
@@ -7588,7 +8321,7 @@ func (ec *executionContext) fieldContext_Query_ImageListForCVE(ctx context.Conte
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ImageListForCVE_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_ImageListForPackage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
@@ -8170,6 +8903,8 @@ func (ec *executionContext) fieldContext_Query_Image(ctx context.Context, field
 				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
 			case "Referrers":
 				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			case "SbomSummary":
+				return ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
 			case "IsDeletable":
 				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
 			}
@@ -8804,6 +9539,8 @@ func (ec *executionContext) fieldContext_RepoInfo_Images(_ context.Context, fiel
 				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
 			case "Referrers":
 				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			case "SbomSummary":
+				return ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
 			case "IsDeletable":
 				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
 			}
@@ -9169,6 +9906,8 @@ func (ec *executionContext) fieldContext_RepoSummary_NewestImage(_ context.Conte
 				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
 			case "Referrers":
 				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			case "SbomSummary":
+				return ec.fieldContext_ImageSummary_SbomSummary(ctx, field)
 			case "IsDeletable":
 				return ec.fieldContext_ImageSummary_IsDeletable(ctx, field)
 			}
@@ -9178,8 +9917,213 @@ func (ec *executionContext) fieldContext_RepoSummary_NewestImage(_ context.Conte
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DownloadCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoSummary_DownloadCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_StarCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.StarCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoSummary_StarCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.IsBookmarked, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*bool)
+	fc.Result = res
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoSummary_IsBookmarked(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.IsStarred, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*bool)
+	fc.Result = res
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoSummary_IsStarred(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Rank(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Rank, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoSummary_Rank(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SbomPackage_Name(ctx context.Context, field graphql.CollectedField, obj *SbomPackage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SbomPackage_Name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -9192,7 +10136,7 @@ func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DownloadCount, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -9201,26 +10145,26 @@ func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_DownloadCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SbomPackage_Name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SbomPackage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_StarCount(ctx, field)
+func (ec *executionContext) _SbomPackage_Version(ctx context.Context, field graphql.CollectedField, obj *SbomPackage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SbomPackage_Version(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -9233,7 +10177,7 @@ func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StarCount, nil
+		return obj.Version, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -9242,26 +10186,26 @@ func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_StarCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SbomPackage_Version(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SbomPackage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+func (ec *executionContext) _SbomPackage_PURL(ctx context.Context, field graphql.CollectedField, obj *SbomPackage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SbomPackage_PURL(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -9274,7 +10218,7 @@ func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsBookmarked, nil
+		return obj.Purl, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -9283,26 +10227,26 @@ func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_IsBookmarked(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SbomPackage_PURL(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SbomPackage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+func (ec *executionContext) _SbomSummary_Formats(ctx context.Context, field graphql.CollectedField, obj *SbomSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SbomSummary_Formats(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -9315,7 +10259,7 @@ func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsStarred, nil
+		return obj.Formats, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -9324,26 +10268,26 @@ func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.([]*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚕᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_IsStarred(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SbomSummary_Formats(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SbomSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Rank(ctx, field)
+func (ec *executionContext) _SbomSummary_PackageCount(ctx context.Context, field graphql.CollectedField, obj *SbomSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SbomSummary_PackageCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -9356,7 +10300,7 @@ func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Rank, nil
+		return obj.PackageCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -9370,9 +10314,9 @@ func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql
 	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Rank(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SbomSummary_PackageCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SbomSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -12008,6 +12952,8 @@ func (ec *executionContext) _ImageSummary(ctx context.Context, sel ast.Selection
 			out.Values[i] = ec._ImageSummary_Vulnerabilities(ctx, field, obj)
 		case "Referrers":
 			out.Values[i] = ec._ImageSummary_Referrers(ctx, field, obj)
+		case "SbomSummary":
+			out.Values[i] = ec._ImageSummary_SbomSummary(ctx, field, obj)
 		case "IsDeletable":
 			out.Values[i] = ec._ImageSummary_IsDeletable(ctx, field, obj)
 		default:
@@ -12259,6 +13205,48 @@ func (ec *executionContext) _PackageInfo(ctx context.Context, sel ast.SelectionS
 	return out
 }
 
+var packageResultForImageImplementors = []string{"PackageResultForImage"}
+
+func (ec *executionContext) _PackageResultForImage(ctx context.Context, sel ast.SelectionSet, obj *PackageResultForImage) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, packageResultForImageImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PackageResultForImage")
+		case "Tag":
+			out.Values[i] = ec._PackageResultForImage_Tag(ctx, field, obj)
+		case "PackageList":
+			out.Values[i] = ec._PackageResultForImage_PackageList(ctx, field, obj)
+		case "Summary":
+			out.Values[i] = ec._PackageResultForImage_Summary(ctx, field, obj)
+		case "Page":
+			out.Values[i] = ec._PackageResultForImage_Page(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var pageInfoImplementors = []string{"PageInfo"}
 
 func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *PageInfo) graphql.Marshaler {
@@ -12507,6 +13495,50 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "PackageListForImage":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_PackageListForImage(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "ImageListForPackage":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_ImageListForPackage(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "ImageListWithCVEFixed":
 			field := field
@@ -12944,6 +13976,84 @@ func (ec *executionContext) _RepoSummary(ctx context.Context, sel ast.SelectionS
 	return out
 }
 
+var sbomPackageImplementors = []string{"SbomPackage"}
+
+func (ec *executionContext) _SbomPackage(ctx context.Context, sel ast.SelectionSet, obj *SbomPackage) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, sbomPackageImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SbomPackage")
+		case "Name":
+			out.Values[i] = ec._SbomPackage_Name(ctx, field, obj)
+		case "Version":
+			out.Values[i] = ec._SbomPackage_Version(ctx, field, obj)
+		case "PURL":
+			out.Values[i] = ec._SbomPackage_PURL(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var sbomSummaryImplementors = []string{"SbomSummary"}
+
+func (ec *executionContext) _SbomSummary(ctx context.Context, sel ast.SelectionSet, obj *SbomSummary) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, sbomSummaryImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SbomSummary")
+		case "Formats":
+			out.Values[i] = ec._SbomSummary_Formats(ctx, field, obj)
+		case "PackageCount":
+			out.Values[i] = ec._SbomSummary_PackageCount(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var signatureSummaryImplementors = []string{"SignatureSummary"}
 
 func (ec *executionContext) _SignatureSummary(ctx context.Context, sel ast.SelectionSet, obj *SignatureSummary) graphql.Marshaler {
@@ -13504,6 +14614,20 @@ func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.Selecti
 	return res
 }
 
+func (ec *executionContext) marshalNPackageResultForImage2zotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageResultForImage(ctx context.Context, sel ast.SelectionSet, v PackageResultForImage) graphql.Marshaler {
+	return ec._PackageResultForImage(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNPackageResultForImage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageResultForImage(ctx context.Context, sel ast.SelectionSet, v *PackageResultForImage) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._PackageResultForImage(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNPaginatedImagesResult2zotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx context.Context, sel ast.SelectionSet, v PaginatedImagesResult) graphql.Marshaler {
 	return ec._PaginatedImagesResult(ctx, sel, &v)
 }
@@ -14439,6 +15563,61 @@ func (ec *executionContext) marshalORepoSummary2ᚖzotregistryᚗdevᚋzotᚋpkg
 	return ec._RepoSummary(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOSbomPackage2ᚕᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomPackage(ctx context.Context, sel ast.SelectionSet, v []*SbomPackage) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOSbomPackage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomPackage(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalOSbomPackage2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomPackage(ctx context.Context, sel ast.SelectionSet, v *SbomPackage) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._SbomPackage(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalOSbomSummary2ᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSbomSummary(ctx context.Context, sel ast.SelectionSet, v *SbomSummary) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._SbomSummary(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalOSignatureSummary2ᚕᚖzotregistryᚗdevᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx context.Context, sel ast.SelectionSet, v []*SignatureSummary) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -179,6 +179,8 @@ type ImageSummary struct {
 	Vulnerabilities *ImageVulnerabilitySummary `json:"Vulnerabilities,omitempty"`
 	// Information about objects that reference this image
 	Referrers []*Referrer `json:"Referrers,omitempty"`
+	// Summary of the SBOMs referring to this image or to its manifests
+	SbomSummary *SbomSummary `json:"SbomSummary,omitempty"`
 	// True if current user has delete permission on this tag.
 	IsDeletable *bool `json:"IsDeletable,omitempty"`
 }
@@ -260,6 +262,18 @@ type PackageInfo struct {
 	FixedVersion *string `json:"FixedVersion,omitempty"`
 }
 
+// Contains the tag of the image and the list of packages found in its SBOMs
+type PackageResultForImage struct {
+	// Tag of the image
+	Tag *string `json:"Tag,omitempty"`
+	// List of packages listed by the SBOMs of this image
+	PackageList []*SbomPackage `json:"PackageList,omitempty"`
+	// Summary of the SBOMs of this image
+	Summary *SbomSummary `json:"Summary,omitempty"`
+	// The package pagination information, see PageInfo object for more details
+	Page *PageInfo `json:"Page,omitempty"`
+}
+
 // Information on current page returned by the API
 type PageInfo struct {
 	// The total number of objects on all pages
@@ -370,6 +384,24 @@ type RepoSummary struct {
 	Rank *int `json:"Rank,omitempty"`
 }
 
+// Contains a package listed by an SPDX or CycloneDX SBOM
+type SbomPackage struct {
+	// Name of the package
+	Name *string `json:"Name,omitempty"`
+	// Version of the package
+	Version *string `json:"Version,omitempty"`
+	// Package URL identifying the package, if present in the SBOM
+	Purl *string `json:"PURL,omitempty"`
+}
+
+// Contains summary of the SBOMs referring to a specific image
+type SbomSummary struct {
+	// Formats of the SBOMs found for this image, "spdx" or "cyclonedx"
+	Formats []*string `json:"Formats,omitempty"`
+	// Number of packages listed by the SBOMs of this image
+	PackageCount *int `json:"PackageCount,omitempty"`
+}
+
 // Contains details about the signature
 type SignatureSummary struct {
 	// Tool is the tool used for signing image
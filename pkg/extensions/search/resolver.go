@@ -741,6 +741,270 @@ func getImageListWithCVEFixed(
 	}, nil
 }
 
+func getPackageListForImage(
+	ctx context.Context,
+	image string,
+	searchedPackage string,
+	requestedPage *gql_generated.PageInput,
+	metaDB mTypes.MetaDB,
+	log log.Logger,
+) (*gql_generated.PackageResultForImage, error) {
+	if requestedPage == nil {
+		requestedPage = &gql_generated.PageInput{}
+	}
+
+	repo, ref, isTag := zcommon.GetImageDirAndReference(image)
+
+	if ref == "" {
+		return &gql_generated.PackageResultForImage{}, gqlerror.Errorf("no reference provided")
+	}
+
+	repoMeta, err := metaDB.GetRepoMeta(ctx, repo)
+	if err != nil {
+		return &gql_generated.PackageResultForImage{}, err
+	}
+
+	imageDigest := ref
+
+	if isTag {
+		descriptor, ok := repoMeta.Tags[ref]
+		if !ok {
+			return &gql_generated.PackageResultForImage{}, gqlerror.Errorf("can't find image: %s:%s", repo, ref)
+		}
+
+		imageDigest = descriptor.Digest
+	}
+
+	imageMeta, err := metaDB.GetImageMeta(godigest.Digest(imageDigest))
+	if err != nil {
+		log.Error().Err(err).Str("repository", repo).Str("reference", ref).Msg("failed to get image meta")
+
+		return &gql_generated.PackageResultForImage{}, err
+	}
+
+	sboms := convert.GetAllSboms(convert.GetFullImageMeta(ref, repoMeta, imageMeta))
+	searchedPackage = strings.ToLower(searchedPackage)
+
+	packages := []mTypes.SbomPackage{}
+
+	for _, pkg := range getUniqueSbomPackages(metaDB, sboms, log) {
+		if searchedPackage != "" && !strings.Contains(strings.ToLower(pkg.Name), searchedPackage) &&
+			!strings.Contains(strings.ToLower(pkg.PURL), searchedPackage) {
+			continue
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+
+		return packages[i].Version < packages[j].Version
+	})
+
+	totalCount := len(packages)
+	offset := min(deref(requestedPage.Offset, 0), totalCount)
+	limit := deref(requestedPage.Limit, 0)
+
+	packages = packages[offset:]
+	if limit > 0 && limit < len(packages) {
+		packages = packages[:limit]
+	}
+
+	packageList := make([]*gql_generated.SbomPackage, 0, len(packages))
+
+	for _, pkg := range packages {
+		pkg := pkg
+
+		packageList = append(packageList, &gql_generated.SbomPackage{
+			Name:    &pkg.Name,
+			Version: &pkg.Version,
+			Purl:    &pkg.PURL,
+		})
+	}
+
+	return &gql_generated.PackageResultForImage{
+		Tag:         &ref,
+		PackageList: packageList,
+		Summary:     convert.GetSbomSummary(sboms),
+		Page: &gql_generated.PageInfo{
+			TotalCount: totalCount,
+			ItemCount:  len(packageList),
+		},
+	}, nil
+}
+
+// getUniqueSbomPackages merges the packages of all the SBOMs, as the same image can have
+// both an SPDX and a CycloneDX SBOM listing the same packages.
+func getUniqueSbomPackages(metaDB mTypes.MetaDB, sboms []mTypes.SbomInfo, log log.Logger,
+) []mTypes.SbomPackage {
+	packages := []mTypes.SbomPackage{}
+	seen := map[mTypes.SbomPackage]bool{}
+
+	for _, sbom := range sboms {
+		sbomPackages, err := metaDB.GetSbomPackages(godigest.Digest(sbom.SbomManifestDigest))
+		if err != nil {
+			log.Error().Err(err).Str("digest", sbom.SbomManifestDigest).Msg("failed to get sbom packages")
+
+			continue
+		}
+
+		for _, pkg := range sbomPackages {
+			if seen[pkg] {
+				continue
+			}
+
+			seen[pkg] = true
+
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages
+}
+
+// sbomPackageMatches checks if the package has the given name, either as the package name or as its purl,
+// with or without the version part. An empty version matches any version.
+func sbomPackageMatches(pkg mTypes.SbomPackage, name, version string) bool {
+	if version != "" && pkg.Version != version {
+		return false
+	}
+
+	if pkg.Name == name {
+		return true
+	}
+
+	if pkg.PURL == "" {
+		return false
+	}
+
+	purlWithoutVersion, _, _ := strings.Cut(pkg.PURL, "@")
+
+	return pkg.PURL == name || purlWithoutVersion == name
+}
+
+// getSbomsWithPackage returns the digests of the SBOM manifests listing the given package. The SBOMs are
+// looked up in the package index, only their packages are read to match the version.
+func getSbomsWithPackage(metaDB mTypes.MetaDB, name, version string, log log.Logger) (map[string]bool, error) {
+	sbomDigests, err := metaDB.GetSbomsWithPackage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingSboms := map[string]bool{}
+
+	for _, sbomDigest := range sbomDigests {
+		if version == "" {
+			matchingSboms[sbomDigest.String()] = true
+
+			continue
+		}
+
+		packages, err := metaDB.GetSbomPackages(sbomDigest)
+		if err != nil {
+			log.Error().Err(err).Str("digest", sbomDigest.String()).Msg("failed to get sbom packages")
+
+			continue
+		}
+
+		for _, pkg := range packages {
+			if sbomPackageMatches(pkg, name, version) {
+				matchingSboms[sbomDigest.String()] = true
+
+				break
+			}
+		}
+	}
+
+	return matchingSboms, nil
+}
+
+// FilterByPackage accepts the images described by one of the given SBOM manifests.
+func FilterByPackage(matchingSboms map[string]bool) mTypes.FilterFunc {
+	return func(repoMeta mTypes.RepoMeta, imageMeta mTypes.ImageMeta) bool {
+		// for images part of an index the sbom can describe either the index or the manifest
+		sboms := append([]mTypes.SbomInfo{}, repoMeta.Sboms[imageMeta.Digest.String()]...)
+
+		if imageMeta.Manifests[0].Digest != imageMeta.Digest {
+			sboms = append(sboms, repoMeta.Sboms[imageMeta.Manifests[0].Digest.String()]...)
+		}
+
+		for _, sbom := range sboms {
+			if matchingSboms[sbom.SbomManifestDigest] {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func getImageListForPackage(
+	ctx context.Context,
+	name string,
+	version string,
+	cveInfo cveinfo.CveInfo,
+	filter *gql_generated.Filter,
+	requestedPage *gql_generated.PageInput,
+	metaDB mTypes.MetaDB,
+	log log.Logger,
+) (*gql_generated.PaginatedImagesResult, error) {
+	log.Info().Str("package", name).Str("version", version).Msg("extracting list of images containing this package")
+
+	skip := convert.SkipQGLField{
+		Vulnerabilities: canSkipField(convert.GetPreloads(ctx), "Results.Vulnerabilities"),
+	}
+
+	if requestedPage == nil {
+		requestedPage = &gql_generated.PageInput{}
+	}
+
+	localFilter := mTypes.Filter{}
+	if filter != nil {
+		localFilter = mTypes.Filter{
+			Os:            filter.Os,
+			Arch:          filter.Arch,
+			HasToBeSigned: filter.HasToBeSigned,
+			IsBookmarked:  filter.IsBookmarked,
+			IsStarred:     filter.IsStarred,
+		}
+	}
+
+	pageInput := pagination.PageInput{
+		Limit:  deref(requestedPage.Limit, 0),
+		Offset: deref(requestedPage.Offset, 0),
+		SortBy: pagination.SortCriteria(
+			deref(requestedPage.SortBy, gql_generated.SortCriteriaUpdateTime),
+		),
+	}
+
+	matchingSboms, err := getSbomsWithPackage(metaDB, name, version, log)
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	fullImageMetaList, err := metaDB.FilterTags(ctx, mTypes.AcceptAllRepoTag, FilterByPackage(matchingSboms))
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	imageSummaries, pageInfo, err := convert.PaginatedFullImageMeta2ImageSummaries(ctx, fullImageMetaList,
+		skip, cveInfo, localFilter, pageInput)
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	return &gql_generated.PaginatedImagesResult{
+		Results: imageSummaries,
+		Page: &gql_generated.PageInfo{
+			TotalCount: pageInfo.TotalCount,
+			ItemCount:  pageInfo.ItemCount,
+		},
+	}, nil
+}
+
 func repoListWithNewestImage(
 	ctx context.Context,
 	cveInfo cveinfo.CveInfo,
@@ -1189,10 +1189,10 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 
 			return getCveResults(repoMeta.Tags[ref].Digest), nil
 		},
-		GetCachedResultFn: func(digestStr string) map[string]cvemodel.CVE {
+		GetCachedResultFn: func(repo, digestStr string) map[string]cvemodel.CVE {
 			return getCveResults(digestStr)
 		},
-		IsResultCachedFn: func(digestStr string) bool {
+		IsResultCachedFn: func(repo, digestStr string) bool {
 			return true
 		},
 	}
@@ -2052,10 +2052,10 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 
 				return getCveResults(repoMeta.Tags[ref].Digest), nil
 			},
-			GetCachedResultFn: func(digestStr string) map[string]cvemodel.CVE {
+			GetCachedResultFn: func(repo, digestStr string) map[string]cvemodel.CVE {
 				return getCveResults(digestStr)
 			},
-			IsResultCachedFn: func(digestStr string) bool {
+			IsResultCachedFn: func(repo, digestStr string) bool {
 				return true
 			},
 		}
@@ -2730,6 +2730,61 @@ func TestUtils(t *testing.T) {
 	})
 }
 
+func TestGetSbomsWithPackage(t *testing.T) {
+	Convey("getSbomsWithPackage", t, func() {
+		logger := log.NewLogger("debug", "")
+		sbom1 := godigest.FromString("sbom1")
+		sbom2 := godigest.FromString("sbom2")
+		readPackages := []godigest.Digest{}
+
+		metaDB := mocks.MetaDBMock{
+			GetSbomsWithPackageFn: func(name string) ([]godigest.Digest, error) {
+				if name != "openssl" {
+					return []godigest.Digest{}, nil
+				}
+
+				return []godigest.Digest{sbom1, sbom2}, nil
+			},
+			GetSbomPackagesFn: func(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error) {
+				readPackages = append(readPackages, sbomDigest)
+
+				if sbomDigest == sbom2 {
+					return nil, ErrTestError
+				}
+
+				return []mTypes.SbomPackage{{Name: "openssl", Version: "3.0.2"}}, nil
+			},
+		}
+
+		Convey("the sboms found in the index match any version without reading their packages", func() {
+			matchingSboms, err := getSbomsWithPackage(metaDB, "openssl", "", logger)
+			So(err, ShouldBeNil)
+			So(matchingSboms, ShouldResemble, map[string]bool{sbom1.String(): true, sbom2.String(): true})
+			So(readPackages, ShouldBeEmpty)
+		})
+
+		Convey("only the packages of the sboms found in the index are read to match the version", func() {
+			matchingSboms, err := getSbomsWithPackage(metaDB, "openssl", "3.0.2", logger)
+			So(err, ShouldBeNil)
+			So(matchingSboms, ShouldResemble, map[string]bool{sbom1.String(): true})
+			So(readPackages, ShouldResemble, []godigest.Digest{sbom1, sbom2})
+
+			matchingSboms, err = getSbomsWithPackage(metaDB, "zlib", "1.3", logger)
+			So(err, ShouldBeNil)
+			So(matchingSboms, ShouldBeEmpty)
+		})
+
+		Convey("index errors are returned", func() {
+			metaDB.GetSbomsWithPackageFn = func(name string) ([]godigest.Digest, error) {
+				return nil, ErrTestError
+			}
+
+			_, err := getSbomsWithPackage(metaDB, "openssl", "", logger)
+			So(err, ShouldEqual, ErrTestError)
+		})
+	})
+}
+
 func getGQLPageInput(limit int, offset int) *gql_generated.PageInput {
 	sortCriteria := gql_generated.SortCriteriaAlphabeticAsc
 
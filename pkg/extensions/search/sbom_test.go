@@ -0,0 +1,238 @@
+//go:build search
+// +build search
+
+package search_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.dev/zot/pkg/api"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/api/constants"
+	"zotregistry.dev/zot/pkg/common"
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	"zotregistry.dev/zot/pkg/meta"
+	. "zotregistry.dev/zot/pkg/test/common"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+)
+
+const (
+	spdxSbom = `{
+		"spdxVersion": "SPDX-2.3",
+		"packages": [
+			{
+				"name": "zlib",
+				"versionInfo": "1.2.11"
+			},
+			{
+				"name": "openssl",
+				"versionInfo": "3.0.2",
+				"externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:deb/ubuntu/openssl@3.0.2"}]
+			}
+		]
+	}`
+	cycloneDXSbom = `{
+		"bomFormat": "CycloneDX",
+		"components": [
+			{"name": "openssl", "version": "1.1.1", "purl": "pkg:apk/alpine/openssl@1.1.1"}
+		]
+	}`
+)
+
+//nolint:tagliatelle // graphQL schema
+type sbomPackageResp struct {
+	Name    string `json:"Name"`
+	Version string `json:"Version"`
+	PURL    string `json:"PURL"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type sbomSummaryResp struct {
+	Formats      []string `json:"Formats"`
+	PackageCount int      `json:"PackageCount"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type packageListForImageResp struct {
+	Data struct {
+		PackageListForImage struct {
+			Tag         string            `json:"Tag"`
+			PackageList []sbomPackageResp `json:"PackageList"`
+			Summary     sbomSummaryResp   `json:"Summary"`
+			Page        common.PageInfo   `json:"Page"`
+		} `json:"PackageListForImage"`
+	} `json:"data"`
+	Errors []common.ErrorGQL `json:"errors"`
+}
+
+//nolint:tagliatelle // graphQL schema
+type imageListForPackageResp struct {
+	Data struct {
+		ImageListForPackage struct {
+			Results []struct {
+				RepoName    string          `json:"RepoName"`
+				Tag         string          `json:"Tag"`
+				SbomSummary sbomSummaryResp `json:"SbomSummary"`
+			} `json:"Results"`
+			Page common.PageInfo `json:"Page"`
+		} `json:"ImageListForPackage"`
+	} `json:"data"`
+	Errors []common.ErrorGQL `json:"errors"`
+}
+
+func TestSbomSearch(t *testing.T) {
+	Convey("Test searching packages found in sboms", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		defaultVal := true
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		ctrlManager := NewControllerManager(ctlr)
+
+		ctrlManager.StartAndWait(port)
+		defer ctrlManager.StopServer()
+
+		image := CreateRandomImage()
+		err := UploadImage(image, baseURL, "repo1", "1.0")
+		So(err, ShouldBeNil)
+
+		spdxReferrer := CreateImageWith().LayerBlobs([][]byte{[]byte(spdxSbom)}).EmptyConfig().
+			Subject(image.DescriptorRef()).ArtifactType(meta.MediaTypeSPDX).Build()
+		err = UploadImage(spdxReferrer, baseURL, "repo1", spdxReferrer.DigestStr())
+		So(err, ShouldBeNil)
+
+		multiarch := CreateRandomMultiarch()
+		err = UploadMultiarchImage(multiarch, baseURL, "repo2", "2.0")
+		So(err, ShouldBeNil)
+
+		cycloneDXReferrer := CreateImageWith().LayerBlobs([][]byte{[]byte(cycloneDXSbom)}).EmptyConfig().
+			Subject(multiarch.Images[0].DescriptorRef()).ArtifactType(meta.MediaTypeCycloneDX).Build()
+		err = UploadImage(cycloneDXReferrer, baseURL, "repo2", cycloneDXReferrer.DigestStr())
+		So(err, ShouldBeNil)
+
+		getPackageList := func(query string) packageListForImageResp {
+			resp, err := resty.R().Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(query))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, 200)
+
+			result := packageListForImageResp{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+
+			return result
+		}
+
+		getImageList := func(query string) imageListForPackageResp {
+			resp, err := resty.R().Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(query))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, 200)
+
+			result := imageListForPackageResp{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+
+			return result
+		}
+
+		Convey("PackageListForImage", func() {
+			result := getPackageList(`{
+				PackageListForImage(image: "repo1:1.0") {
+					Tag PackageList {Name Version PURL} Summary {Formats PackageCount} Page {TotalCount ItemCount}
+				}
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+
+			packageList := result.Data.PackageListForImage
+			So(packageList.Tag, ShouldEqual, "1.0")
+			So(packageList.PackageList, ShouldResemble, []sbomPackageResp{
+				{Name: "openssl", Version: "3.0.2", PURL: "pkg:deb/ubuntu/openssl@3.0.2"},
+				{Name: "zlib", Version: "1.2.11"},
+			})
+			So(packageList.Summary, ShouldResemble, sbomSummaryResp{Formats: []string{"spdx"}, PackageCount: 2})
+			So(packageList.Page, ShouldResemble, common.PageInfo{TotalCount: 2, ItemCount: 2})
+
+			result = getPackageList(`{
+				PackageListForImage(image: "repo1@` + image.DigestStr() + `", searchedPackage: "SSL") {
+					PackageList {Name} Page {TotalCount ItemCount}
+				}
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.PackageListForImage.PackageList, ShouldHaveLength, 1)
+			So(result.Data.PackageListForImage.PackageList[0].Name, ShouldEqual, "openssl")
+
+			result = getPackageList(`{
+				PackageListForImage(image: "repo1:1.0", requestedPage: {limit: 1, offset: 1}) {
+					PackageList {Name} Page {TotalCount ItemCount}
+				}
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.PackageListForImage.PackageList[0].Name, ShouldEqual, "zlib")
+			So(result.Data.PackageListForImage.Page, ShouldResemble, common.PageInfo{TotalCount: 2, ItemCount: 1})
+
+			// the sboms of the manifests are included in the packages of the index
+			result = getPackageList(`{
+				PackageListForImage(image: "repo2:2.0") { PackageList {Name Version} Summary {Formats PackageCount} }
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.PackageListForImage.PackageList, ShouldResemble, []sbomPackageResp{
+				{Name: "openssl", Version: "1.1.1"},
+			})
+			So(result.Data.PackageListForImage.Summary.Formats, ShouldResemble, []string{"cyclonedx"})
+
+			result = getPackageList(`{ PackageListForImage(image: "repo1:2.0") { Tag } }`)
+			So(result.Errors, ShouldNotBeEmpty)
+
+			result = getPackageList(`{ PackageListForImage(image: "repo1") { Tag } }`)
+			So(result.Errors, ShouldNotBeEmpty)
+		})
+
+		Convey("ImageListForPackage", func() {
+			result := getImageList(`{
+				ImageListForPackage(name: "openssl") {
+					Results { RepoName Tag SbomSummary {Formats PackageCount} } Page {TotalCount ItemCount}
+				}
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.ImageListForPackage.Page.TotalCount, ShouldEqual, 2)
+
+			summaries := map[string]sbomSummaryResp{}
+			for _, imageSummary := range result.Data.ImageListForPackage.Results {
+				summaries[imageSummary.RepoName+":"+imageSummary.Tag] = imageSummary.SbomSummary
+			}
+
+			So(summaries["repo1:1.0"], ShouldResemble, sbomSummaryResp{Formats: []string{"spdx"}, PackageCount: 2})
+			So(summaries["repo2:2.0"], ShouldResemble, sbomSummaryResp{Formats: []string{"cyclonedx"}, PackageCount: 1})
+
+			result = getImageList(`{
+				ImageListForPackage(name: "openssl", version: "3.0.2") { Results { RepoName Tag } }
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.ImageListForPackage.Results, ShouldHaveLength, 1)
+			So(result.Data.ImageListForPackage.Results[0].RepoName, ShouldEqual, "repo1")
+
+			result = getImageList(`{
+				ImageListForPackage(name: "pkg:apk/alpine/openssl") { Results { RepoName Tag } }
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.ImageListForPackage.Results, ShouldHaveLength, 1)
+			So(result.Data.ImageListForPackage.Results[0].RepoName, ShouldEqual, "repo2")
+
+			result = getImageList(`{
+				ImageListForPackage(name: "openssl", version: "0.9") { Results { RepoName Tag } }
+			}`)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.ImageListForPackage.Results, ShouldBeEmpty)
+		})
+	})
+}
@@ -44,6 +44,18 @@ func (r *queryResolver) ImageListForCve(ctx context.Context, id string, filter *
 	return getImageListForCVE(ctx, id, r.cveInfo, filter, requestedPage, r.metaDB, r.log)
 }
 
+// PackageListForImage is the resolver for the PackageListForImage field.
+func (r *queryResolver) PackageListForImage(ctx context.Context, image string, searchedPackage *string, requestedPage *gql_generated.PageInput) (*gql_generated.PackageResultForImage, error) {
+	return getPackageListForImage(ctx, image, deref(searchedPackage, ""), requestedPage, r.metaDB, r.log)
+}
+
+// ImageListForPackage is the resolver for the ImageListForPackage field.
+func (r *queryResolver) ImageListForPackage(ctx context.Context, name string, version *string, filter *gql_generated.Filter, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
+	filter = cleanFilter(filter)
+
+	return getImageListForPackage(ctx, name, deref(version, ""), r.cveInfo, filter, requestedPage, r.metaDB, r.log)
+}
+
 // ImageListWithCVEFixed is the resolver for the ImageListWithCVEFixed field.
 func (r *queryResolver) ImageListWithCVEFixed(ctx context.Context, id string, image string, filter *gql_generated.Filter, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
 	if r.cveInfo == nil {
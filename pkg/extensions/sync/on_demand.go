@@ -10,6 +10,7 @@ import (
 
 	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/log"
 )
 
@@ -31,11 +32,12 @@ type BaseOnDemand struct {
 	services []Service
 	// map[request]chan err
 	requestStore *sync.Map
+	events       events.Recorder
 	log          log.Logger
 }
 
-func NewOnDemand(log log.Logger) *BaseOnDemand {
-	return &BaseOnDemand{log: log, requestStore: &sync.Map{}}
+func NewOnDemand(recorder events.Recorder, log log.Logger) *BaseOnDemand {
+	return &BaseOnDemand{log: log, events: recorder, requestStore: &sync.Map{}}
 }
 
 func (onDemand *BaseOnDemand) Add(service Service) {
@@ -228,5 +230,20 @@ func (onDemand *BaseOnDemand) syncImage(ctx context.Context, repo, reference str
 		}
 	}
 
+	onDemand.recordSyncResult(repo, reference, err)
+
 	syncResult <- err
 }
+
+func (onDemand *BaseOnDemand) recordSyncResult(repo, reference string, err error) {
+	// images filtered out by all the registries were not synced at all
+	if onDemand.events == nil || errors.Is(err, zerr.ErrSyncImageFilteredOut) {
+		return
+	}
+
+	if err != nil {
+		onDemand.events.SyncFailed(repo, reference, err)
+	} else {
+		onDemand.events.SyncCompleted(repo, reference)
+	}
+}
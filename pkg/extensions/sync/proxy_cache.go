@@ -0,0 +1,338 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.dev/zot/errors"
+	zconfig "zotregistry.dev/zot/pkg/api/config"
+	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
+	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	"zotregistry.dev/zot/pkg/storage"
+)
+
+const (
+	DefaultProxyCachePrefix = "cache"
+	DefaultProxyCacheTagTTL = time.Hour
+	// bounds of the in memory state, hosts matching glob patterns and pulled tags are not known in advance.
+	maxProxyCacheServices     = 100
+	maxProxyCacheResolvedTags = 10000
+)
+
+/*
+ProxyCache syncs on demand images from arbitrary upstreams, the upstream host is the first path component
+after the proxy cache prefix, eg: a pull of cache/docker.io/library/alpine:latest is synced from
+docker.io/library/alpine:latest.
+
+Synced tags are served locally until their TTL expires, after that they are revalidated against the upstream:
+the upstream manifest is only checked with a HEAD request, the image is synced again if its digest changed.
+*/
+type ProxyCache struct {
+	config          syncconf.ProxyCacheConfig
+	credentialsPath string
+	clusterConfig   *zconfig.ClusterConfig
+	tmpDir          string
+	storeController storage.StoreController
+	metaDB          mTypes.MetaDB
+	// one service per upstream host, created on first pull
+	services map[string]*BaseService
+	// repo:tag resolved against their upstream
+	resolvedTags map[string]resolvedTag
+	lock         *sync.Mutex
+	log          log.Logger
+}
+
+type resolvedTag struct {
+	// time until which the tag is served without revalidating it
+	expiresAt time.Time
+	// digest of the upstream manifest when the tag was resolved, unknown if empty
+	upstreamDigest godigest.Digest
+}
+
+func NewProxyCache(
+	config syncconf.ProxyCacheConfig,
+	credentialsPath string,
+	clusterConfig *zconfig.ClusterConfig,
+	tmpDir string,
+	storeController storage.StoreController,
+	metaDB mTypes.MetaDB,
+	log log.Logger,
+) *ProxyCache {
+	config.Prefix = strings.Trim(config.Prefix, "/")
+	if config.Prefix == "" {
+		config.Prefix = DefaultProxyCachePrefix
+	}
+
+	if config.TagTTL == 0 {
+		config.TagTTL = DefaultProxyCacheTagTTL
+	}
+
+	return &ProxyCache{
+		config:          config,
+		credentialsPath: credentialsPath,
+		clusterConfig:   clusterConfig,
+		tmpDir:          tmpDir,
+		storeController: storeController,
+		metaDB:          metaDB,
+		services:        make(map[string]*BaseService),
+		resolvedTags:    make(map[string]resolvedTag),
+		lock:            &sync.Mutex{},
+		log:             log,
+	}
+}
+
+// proxy cache only syncs on demand, there is no catalog to sync periodically.
+func (pc *ProxyCache) GetNextRepo(lastRepo string) (string, error) {
+	return "", nil
+}
+
+func (pc *ProxyCache) SyncRepo(ctx context.Context, repo string) error {
+	return nil
+}
+
+func (pc *ProxyCache) ResetCatalog() {}
+
+func (pc *ProxyCache) CanRetryOnError() bool {
+	return false
+}
+
+// SyncImage on demand, tags synced within their TTL are not revalidated against the upstream.
+func (pc *ProxyCache) SyncImage(ctx context.Context, repo, reference string) error {
+	host, upstream, err := pc.getUpstream(repo)
+	if err != nil {
+		return err
+	}
+
+	_, digestErr := godigest.Parse(reference)
+	isTag := digestErr != nil
+
+	if isTag && pc.isTagFresh(repo, reference) {
+		pc.log.Debug().Str("repository", repo).Str("reference", reference).
+			Msg("proxy cache: tag resolved within ttl, skipping upstream revalidation")
+
+		return nil
+	}
+
+	service, err := pc.getService(host, upstream)
+	if err != nil {
+		return err
+	}
+
+	var upstreamDigest godigest.Digest
+
+	if isTag {
+		// a failed HEAD request is not fatal, the image is synced again and the sync reports the upstream errors
+		upstreamDigest, err = service.getRemoteDigest(ctx, repo, reference)
+		if err != nil {
+			pc.log.Debug().Err(err).Str("repository", repo).Str("reference", reference).
+				Msg("proxy cache: failed to get the upstream manifest digest")
+		}
+
+		if upstreamDigest != "" && pc.isTagUnchanged(repo, reference, upstreamDigest) {
+			pc.log.Debug().Str("repository", repo).Str("reference", reference).
+				Msg("proxy cache: tag unchanged on the upstream, skipping sync")
+
+			pc.setTagResolved(repo, reference, upstreamDigest, pc.getTagTTL(upstream))
+
+			return nil
+		}
+	}
+
+	if err := service.SyncImage(ctx, repo, reference); err != nil {
+		return err
+	}
+
+	if isTag {
+		pc.setTagResolved(repo, reference, upstreamDigest, pc.getTagTTL(upstream))
+	}
+
+	pc.markRepo(repo, host)
+
+	return nil
+}
+
+func (pc *ProxyCache) SyncReferrers(ctx context.Context, repo string,
+	subjectDigestStr string, referenceTypes []string,
+) error {
+	host, upstream, err := pc.getUpstream(repo)
+	if err != nil {
+		return err
+	}
+
+	service, err := pc.getService(host, upstream)
+	if err != nil {
+		return err
+	}
+
+	if err := service.SyncReferrers(ctx, repo, subjectDigestStr, referenceTypes); err != nil {
+		return err
+	}
+
+	pc.markRepo(repo, host)
+
+	return nil
+}
+
+// getUpstream returns the upstream host of a local repo and its settings.
+func (pc *ProxyCache) getUpstream(repo string) (string, syncconf.ProxyCacheUpstream, error) {
+	upstreamRepo, found := strings.CutPrefix(repo, pc.config.Prefix+"/")
+	if !found {
+		return "", syncconf.ProxyCacheUpstream{}, zerr.ErrSyncImageFilteredOut
+	}
+
+	host, remoteRepo, found := strings.Cut(upstreamRepo, "/")
+	if !found || host == "" || remoteRepo == "" {
+		return "", syncconf.ProxyCacheUpstream{}, zerr.ErrSyncImageFilteredOut
+	}
+
+	// only the configured upstreams are allowed, otherwise zot could be used to reach any host
+	for _, upstream := range pc.config.Upstreams {
+		matched, err := glob.Match(upstream.Host, host)
+		if err == nil && matched {
+			return host, upstream, nil
+		}
+	}
+
+	pc.log.Info().Str("repository", repo).Str("upstream", host).
+		Msg("proxy cache: will not sync image, upstream not allowed")
+
+	return "", syncconf.ProxyCacheUpstream{}, zerr.ErrSyncImageFilteredOut
+}
+
+func (pc *ProxyCache) getService(host string, upstream syncconf.ProxyCacheUpstream) (*BaseService, error) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if service, ok := pc.services[host]; ok {
+		return service, nil
+	}
+
+	url := upstream.URL
+	if url == "" {
+		url = "https://" + host
+	}
+
+	registryConfig := syncconf.RegistryConfig{
+		URLs:       []string{url},
+		OnDemand:   true,
+		TLSVerify:  upstream.TLSVerify,
+		CertDir:    upstream.CertDir,
+		MaxRetries: upstream.MaxRetries,
+		RetryDelay: upstream.RetryDelay,
+		// every repo under <prefix>/<host> is synced from the same repo on the upstream
+		Content: []syncconf.Content{
+			{
+				Prefix:      "**",
+				Destination: "/" + path.Join(pc.config.Prefix, host),
+			},
+		},
+	}
+
+	service, err := New(registryConfig, pc.credentialsPath, pc.clusterConfig, pc.tmpDir,
+		pc.storeController, pc.metaDB, pc.log)
+	if err != nil {
+		pc.log.Error().Err(err).Str("upstream", host).Msg("proxy cache: failed to initialize sync client")
+
+		return nil, err
+	}
+
+	if len(pc.services) >= maxProxyCacheServices {
+		// services don't hold any resources, the evicted one is recreated on its next pull
+		for evictedHost := range pc.services {
+			delete(pc.services, evictedHost)
+
+			break
+		}
+	}
+
+	pc.services[host] = service
+
+	return service, nil
+}
+
+func (pc *ProxyCache) getTagTTL(upstream syncconf.ProxyCacheUpstream) time.Duration {
+	if upstream.TagTTL != nil {
+		return *upstream.TagTTL
+	}
+
+	return pc.config.TagTTL
+}
+
+// returns true if the tag was resolved within ttl and it's still present in storage.
+func (pc *ProxyCache) isTagFresh(repo, tag string) bool {
+	resolved, ok := pc.getResolvedTag(repo, tag)
+
+	return ok && time.Now().Before(resolved.expiresAt)
+}
+
+// returns true if the tag was resolved to the same upstream digest and it's still present in storage.
+func (pc *ProxyCache) isTagUnchanged(repo, tag string, upstreamDigest godigest.Digest) bool {
+	resolved, ok := pc.getResolvedTag(repo, tag)
+
+	return ok && resolved.upstreamDigest == upstreamDigest
+}
+
+// returns the tag resolved against its upstream, if it's still present in storage.
+func (pc *ProxyCache) getResolvedTag(repo, tag string) (resolvedTag, bool) {
+	pc.lock.Lock()
+	resolved, ok := pc.resolvedTags[repo+":"+tag]
+	pc.lock.Unlock()
+
+	if !ok {
+		return resolvedTag{}, false
+	}
+
+	imgStore := pc.storeController.GetImageStore(repo)
+
+	_, _, _, err := imgStore.GetImageManifest(repo, tag)
+
+	return resolved, err == nil
+}
+
+func (pc *ProxyCache) setTagResolved(repo, tag string, upstreamDigest godigest.Digest, ttl time.Duration) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	now := time.Now()
+
+	if len(pc.resolvedTags) >= maxProxyCacheResolvedTags {
+		for key, resolved := range pc.resolvedTags {
+			if now.After(resolved.expiresAt) {
+				delete(pc.resolvedTags, key)
+			}
+		}
+	}
+
+	// still full, evict any tag, it will be synced again from its upstream on the next pull
+	if len(pc.resolvedTags) >= maxProxyCacheResolvedTags {
+		for key := range pc.resolvedTags {
+			delete(pc.resolvedTags, key)
+
+			break
+		}
+	}
+
+	pc.resolvedTags[repo+":"+tag] = resolvedTag{expiresAt: now.Add(ttl), upstreamDigest: upstreamDigest}
+}
+
+// markRepo marks the repo as cached in MetaDB, so that retention can apply the proxy cache eviction policy.
+func (pc *ProxyCache) markRepo(repo, host string) {
+	if pc.metaDB == nil {
+		return
+	}
+
+	if err := pc.metaDB.SetRepoProxyCache(repo, host); err != nil {
+		pc.log.Error().Err(err).Str("repository", repo).Str("upstream", host).
+			Msg("proxy cache: failed to mark repo as cached in metaDB")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/regclient/regclient/mod"
 	"github.com/regclient/regclient/scheme/reg"
 	"github.com/regclient/regclient/types/ref"
+	"go.opentelemetry.io/otel/attribute"
 
 	zerr "zotregistry.dev/zot/errors"
 	zconfig "zotregistry.dev/zot/pkg/api/config"
@@ -26,6 +27,7 @@ import (
 	"zotregistry.dev/zot/pkg/cluster"
 	"zotregistry.dev/zot/pkg/common"
 	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/storage"
@@ -33,6 +35,9 @@ import (
 
 const defaultExpireMinutes = 30 * time.Minute
 
+// span attribute holding the host of the remote registry.
+const remoteKey = attribute.Key("zot.sync.remote")
+
 type BaseService struct {
 	config           syncconf.RegistryConfig
 	credentials      syncconf.CredentialsFile
@@ -262,7 +267,7 @@ func (service *BaseService) GetNextRepo(lastRepo string) (string, error) {
 		}
 
 		if service.clusterConfig != nil {
-			targetIdx, targetMember := cluster.ComputeTargetMember(
+			targetIdx, targetMember := cluster.ComputeTargetMemberWithHashing(service.clusterConfig.Hashing,
 				service.clusterConfig.HashKey, service.clusterConfig.Members, lastRepo)
 
 			// if the target index does not match with the local socket index,
@@ -285,7 +290,7 @@ func (service *BaseService) GetNextRepo(lastRepo string) (string, error) {
 }
 
 // SyncImage on demand.
-func (service *BaseService) SyncImage(ctx context.Context, repo, reference string) error {
+func (service *BaseService) SyncImage(ctx context.Context, repo, reference string) (err error) {
 	remoteRepo := repo
 
 	remoteURL := service.remote.GetHostName()
@@ -303,6 +308,10 @@ func (service *BaseService) SyncImage(ctx context.Context, repo, reference strin
 	service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("reference", reference).
 		Msg("sync: syncing image")
 
+	ctx, span := tracing.StartChildSpan(ctx, "sync.SyncImage", tracing.RepositoryKey.String(repo),
+		tracing.ReferenceKey.String(reference), remoteKey.String(remoteURL))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	if err := service.refreshRegistryTemporaryCredentials(); err != nil {
 		service.log.Error().Err(err).Msg("failed to refresh credentials")
 	}
@@ -310,9 +319,30 @@ func (service *BaseService) SyncImage(ctx context.Context, repo, reference strin
 	return service.syncImage(ctx, repo, remoteRepo, reference, nil, false)
 }
 
+// getRemoteDigest returns the digest of the manifest of reference on the remote, with a single HEAD request.
+func (service *BaseService) getRemoteDigest(ctx context.Context, repo, reference string) (godigest.Digest, error) {
+	remoteRepo := repo
+
+	if len(service.config.Content) > 0 {
+		remoteRepo = service.contentManager.GetRepoSource(repo)
+		if remoteRepo == "" {
+			return "", zerr.ErrSyncImageFilteredOut
+		}
+	}
+
+	if err := service.refreshRegistryTemporaryCredentials(); err != nil {
+		service.log.Error().Err(err).Msg("failed to refresh credentials")
+	}
+
+	service.clientLock.RLock()
+	defer service.clientLock.RUnlock()
+
+	return service.remote.GetDigest(ctx, remoteRepo, reference)
+}
+
 func (service *BaseService) SyncReferrers(ctx context.Context, repo string,
 	subjectDigestStr string, referenceTypes []string,
-) error {
+) (err error) {
 	service.clientLock.RLock()
 	defer service.clientLock.RUnlock()
 
@@ -333,6 +363,10 @@ func (service *BaseService) SyncReferrers(ctx context.Context, repo string,
 	service.log.Info().Str("remote", remoteURL).Str("repository", repo).Str("subject", subjectDigestStr).
 		Interface("reference types", referenceTypes).Msg("syncing reference for image")
 
+	ctx, span := tracing.StartChildSpan(ctx, "sync.SyncReferrers", tracing.RepositoryKey.String(repo),
+		tracing.ReferenceKey.String(subjectDigestStr), remoteKey.String(remoteURL))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	tags, err := service.getTags(ctx, remoteRepo, false)
 	if err != nil {
 		service.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).
@@ -388,11 +422,13 @@ func (service *BaseService) SyncReferrers(ctx context.Context, repo string,
 }
 
 // sync repo periodically.
-func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
+func (service *BaseService) SyncRepo(ctx context.Context, repo string) (err error) {
 	service.log.Info().Str("repo", repo).Str("registry", service.remote.GetHostName()).
 		Msg("sync: syncing repo")
 
-	var err error
+	ctx, span := tracing.StartChildSpan(ctx, "sync.SyncRepo", tracing.RepositoryKey.String(repo),
+		remoteKey.String(service.remote.GetHostName()))
+	defer func() { tracing.EndSpan(span, err) }()
 
 	var tags []string
 
@@ -12,7 +12,9 @@ import (
 	godigest "github.com/opencontainers/go-digest"
 	"github.com/regclient/regclient/types/ref"
 
+	"zotregistry.dev/zot/pkg/common"
 	syncconf "zotregistry.dev/zot/pkg/extensions/config/sync"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/log"
 	"zotregistry.dev/zot/pkg/scheduler"
 )
@@ -101,11 +103,14 @@ type TaskGenerator struct {
 	waitTime     time.Duration
 	lastTaskTime time.Time
 	maxWaitTime  time.Duration
+	events       events.Recorder
 	lock         *sync.Mutex
 	log          log.Logger
 }
 
-func NewTaskGenerator(service Service, maxWaitTime time.Duration, log log.Logger) *TaskGenerator {
+func NewTaskGenerator(service Service, maxWaitTime time.Duration, recorder events.Recorder,
+	log log.Logger,
+) *TaskGenerator {
 	return &TaskGenerator{
 		Service:      service,
 		done:         false,
@@ -114,6 +119,7 @@ func NewTaskGenerator(service Service, maxWaitTime time.Duration, log log.Logger
 		lock:         &sync.Mutex{},
 		lastRepo:     "",
 		maxWaitTime:  maxWaitTime,
+		events:       recorder,
 		log:          log,
 	}
 }
@@ -148,7 +154,7 @@ func (gen *TaskGenerator) Next() (scheduler.Task, error) {
 
 	gen.lastRepo = repo
 
-	return newSyncRepoTask(gen.lastRepo, gen.Service), nil
+	return newSyncRepoTask(gen.lastRepo, gen.Service, gen.events), nil
 }
 
 func (gen *TaskGenerator) IsDone() bool {
@@ -193,14 +199,26 @@ func (gen *TaskGenerator) resetWaitTime() {
 type syncRepoTask struct {
 	repo    string
 	service Service
+	events  events.Recorder
 }
 
-func newSyncRepoTask(repo string, service Service) *syncRepoTask {
-	return &syncRepoTask{repo, service}
+func newSyncRepoTask(repo string, service Service, recorder events.Recorder) *syncRepoTask {
+	return &syncRepoTask{repo, service, recorder}
 }
 
 func (srt *syncRepoTask) DoWork(ctx context.Context) error {
-	return srt.service.SyncRepo(ctx, srt.repo)
+	err := srt.service.SyncRepo(ctx, srt.repo)
+
+	// tasks interrupted by shutdown are neither completed nor failed
+	if srt.events != nil && !common.IsContextDone(ctx) {
+		if err != nil {
+			srt.events.SyncFailed(srt.repo, "", err)
+		} else {
+			srt.events.SyncCompleted(srt.repo, "")
+		}
+	}
+
+	return err
 }
 
 func (srt *syncRepoTask) String() string {
@@ -7,8 +7,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -178,12 +181,13 @@ func TestDestinationRegistry(t *testing.T) {
 
 		Convey("trigger linter error in CommitImage()", func() {
 			defaultVal := true
-			linter := lint.NewLinter(&config.LintConfig{
+			linter, err := lint.NewLinter(&config.LintConfig{
 				BaseConfig: config.BaseConfig{
 					Enable: &defaultVal,
 				},
 				MandatoryAnnotations: []string{"annot1"},
 			}, log)
+			So(err, ShouldBeNil)
 
 			syncImgStore := local.NewImageStore(dir, true, true, log, metrics, linter, cacheDriver, nil, nil)
 			repoName := "repo"
@@ -330,3 +334,64 @@ func TestDestinationRegistry(t *testing.T) {
 		})
 	})
 }
+
+func TestProxyCacheUpstreams(t *testing.T) {
+	Convey("Only configured upstreams are synced", t, func() {
+		proxyCache := NewProxyCache(syncconf.ProxyCacheConfig{}, "", nil, "", storage.StoreController{},
+			mocks.MetaDBMock{}, log.NewLogger("debug", ""))
+
+		err := proxyCache.SyncImage(context.Background(), "cache/docker.io/library/alpine", "latest")
+		So(err, ShouldEqual, zerr.ErrSyncImageFilteredOut)
+
+		proxyCache = NewProxyCache(syncconf.ProxyCacheConfig{
+			Upstreams: []syncconf.ProxyCacheUpstream{{Host: "*.gcr.io"}},
+		}, "", nil, "", storage.StoreController{}, mocks.MetaDBMock{}, log.NewLogger("debug", ""))
+
+		host, _, err := proxyCache.getUpstream("cache/mirror.gcr.io/library/alpine")
+		So(err, ShouldBeNil)
+		So(host, ShouldEqual, "mirror.gcr.io")
+
+		_, _, err = proxyCache.getUpstream("cache/docker.io/library/alpine")
+		So(err, ShouldEqual, zerr.ErrSyncImageFilteredOut)
+
+		_, _, err = proxyCache.getUpstream("cache/mirror.gcr.io")
+		So(err, ShouldEqual, zerr.ErrSyncImageFilteredOut)
+
+		_, _, err = proxyCache.getUpstream("library/alpine")
+		So(err, ShouldEqual, zerr.ErrSyncImageFilteredOut)
+	})
+
+	Convey("In memory state is bounded", t, func() {
+		proxyCache := NewProxyCache(syncconf.ProxyCacheConfig{
+			Upstreams: []syncconf.ProxyCacheUpstream{{Host: "*.gcr.io"}},
+		}, "", nil, "", storage.StoreController{}, mocks.MetaDBMock{}, log.NewLogger("debug", ""))
+
+		for i := range maxProxyCacheServices + 10 {
+			host := fmt.Sprintf("mirror%d.gcr.io", i)
+
+			_, err := proxyCache.getService(host, syncconf.ProxyCacheUpstream{Host: "*.gcr.io"})
+			So(err, ShouldBeNil)
+		}
+
+		So(len(proxyCache.services), ShouldEqual, maxProxyCacheServices)
+
+		// expired tags are evicted first
+		proxyCache.setTagResolved("cache/mirror.gcr.io/expired", "latest", "", -time.Second)
+
+		for i := range maxProxyCacheResolvedTags - 1 {
+			proxyCache.setTagResolved("cache/mirror.gcr.io/repo", strconv.Itoa(i), "", time.Hour)
+		}
+
+		So(len(proxyCache.resolvedTags), ShouldEqual, maxProxyCacheResolvedTags)
+
+		proxyCache.setTagResolved("cache/mirror.gcr.io/repo", "new", "", time.Hour)
+		So(len(proxyCache.resolvedTags), ShouldEqual, maxProxyCacheResolvedTags)
+		So(proxyCache.resolvedTags, ShouldNotContainKey, "cache/mirror.gcr.io/expired:latest")
+		So(proxyCache.resolvedTags, ShouldContainKey, "cache/mirror.gcr.io/repo:new")
+
+		// no expired tags left, any tag is evicted
+		proxyCache.setTagResolved("cache/mirror.gcr.io/repo", "newer", "", time.Hour)
+		So(len(proxyCache.resolvedTags), ShouldEqual, maxProxyCacheResolvedTags)
+		So(proxyCache.resolvedTags, ShouldContainKey, "cache/mirror.gcr.io/repo:newer")
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httputil"
 	"net/url"
 	"os"
 	"path"
@@ -1086,6 +1087,148 @@ func TestOnDemandWithScaleOutClusterWithReposNotAddedForSync(t *testing.T) {
 	})
 }
 
+func TestProxyCache(t *testing.T) {
+	Convey("Verify sync proxy cache", t, func() {
+		sctlr, srcBaseURL, _, _, srcClient := makeUpstreamServer(t, false, false)
+		scm := test.NewControllerManager(sctlr)
+		scm.StartAndWait(sctlr.Config.HTTP.Port)
+
+		defer scm.StopServer()
+
+		upstreamHost := "upstream.local"
+		cachedRepo := "cache/" + upstreamHost + "/" + testImage
+
+		resp, err := srcClient.R().Get(srcBaseURL + "/v2/" + testImage + "/manifests/" + testImageTag)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		upstreamDigest := resp.Header().Get("Docker-Content-Digest")
+
+		newImage := CreateRandomImage()
+
+		makeProxyCacheServer := func(upstreamURL string, tagTTL time.Duration) (*api.Controller, string, *resty.Client) {
+			defaultVal := true
+			tlsVerify := false
+
+			syncConfig := &syncconf.Config{
+				Enable: &defaultVal,
+				ProxyCache: &syncconf.ProxyCacheConfig{
+					Enable: &defaultVal,
+					Upstreams: []syncconf.ProxyCacheUpstream{
+						{
+							Host:      upstreamHost,
+							URL:       upstreamURL,
+							TLSVerify: &tlsVerify,
+							TagTTL:    &tagTTL,
+						},
+					},
+				},
+			}
+
+			dctlr, destBaseURL, _, destClient := makeDownstreamServer(t, false, syncConfig)
+
+			return dctlr, destBaseURL, destClient
+		}
+
+		Convey("Pull through the proxy cache", func() {
+			dctlr, destBaseURL, destClient := makeProxyCacheServer(srcBaseURL, time.Hour)
+
+			dcm := test.NewControllerManager(dctlr)
+			dcm.StartAndWait(dctlr.Config.HTTP.Port)
+			defer dcm.StopServer()
+
+			resp, err := destClient.R().Get(destBaseURL + "/v2/" + cachedRepo + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, upstreamDigest)
+
+			// upstreams which are not configured are not synced
+			resp, err = destClient.R().Get(destBaseURL + "/v2/cache/other.io/" + testImage + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			// repos outside the proxy cache prefix are not synced
+			resp, err = destClient.R().Get(destBaseURL + "/v2/" + testImage + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			repoMeta, err := dctlr.MetaDB.GetRepoMeta(context.Background(), cachedRepo)
+			So(err, ShouldBeNil)
+			So(repoMeta.IsProxyCache, ShouldBeTrue)
+			So(repoMeta.Upstream, ShouldEqual, upstreamHost)
+
+			// the tag is updated on the upstream, but it's still served from cache until its ttl expires
+			err = UploadImage(newImage, srcBaseURL, testImage, testImageTag)
+			So(err, ShouldBeNil)
+
+			resp, err = destClient.R().Get(destBaseURL + "/v2/" + cachedRepo + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, upstreamDigest)
+		})
+
+		Convey("Revalidate tags after their ttl expires", func() {
+			// records the requests reaching the upstream
+			var (
+				upstreamRequests []string
+				requestsLock     goSync.Mutex
+			)
+
+			srcURL, err := url.Parse(srcBaseURL)
+			So(err, ShouldBeNil)
+
+			upstreamProxy := httputil.NewSingleHostReverseProxy(srcURL)
+			upstreamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestsLock.Lock()
+				upstreamRequests = append(upstreamRequests, r.Method+" "+r.URL.Path)
+				requestsLock.Unlock()
+
+				upstreamProxy.ServeHTTP(w, r)
+			}))
+			defer upstreamServer.Close()
+
+			getUpstreamRequests := func() []string {
+				requestsLock.Lock()
+				defer requestsLock.Unlock()
+
+				requests := upstreamRequests
+				upstreamRequests = nil
+
+				return requests
+			}
+
+			dctlr, destBaseURL, destClient := makeProxyCacheServer(upstreamServer.URL, time.Nanosecond)
+
+			dcm := test.NewControllerManager(dctlr)
+			dcm.StartAndWait(dctlr.Config.HTTP.Port)
+			defer dcm.StopServer()
+
+			resp, err := destClient.R().Get(destBaseURL + "/v2/" + cachedRepo + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			getUpstreamRequests()
+
+			// the tag didn't change on the upstream, it is revalidated with a single request
+			resp, err = destClient.R().Get(destBaseURL + "/v2/" + cachedRepo + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, upstreamDigest)
+			So(getUpstreamRequests(), ShouldResemble, []string{
+				http.MethodHead + " /v2/" + testImage + "/manifests/" + testImageTag,
+			})
+
+			err = UploadImage(newImage, srcBaseURL, testImage, testImageTag)
+			So(err, ShouldBeNil)
+
+			resp, err = destClient.R().Get(destBaseURL + "/v2/" + cachedRepo + "/manifests/" + testImageTag)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, newImage.DigestStr())
+		})
+	})
+}
+
 func TestSyncReferenceInLoop(t *testing.T) {
 	Convey("Verify sync doesn't end up in an infinite loop when syncing image references", t, func() {
 		sctlr, srcBaseURL, srcDir, _, _ := makeUpstreamServer(t, false, false)
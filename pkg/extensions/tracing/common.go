@@ -0,0 +1,97 @@
+//go:build tracing
+// +build tracing
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of all spans recorded by zot.
+const TracerName = "zotregistry.dev/zot"
+
+// span attribute keys shared by the instrumented packages.
+const (
+	RepositoryKey = attribute.Key("zot.repository")
+	ReferenceKey  = attribute.Key("zot.reference")
+	RootDirKey    = attribute.Key("zot.storage.rootdir")
+	DriverKey     = attribute.Key("zot.storage.driver")
+	PathKey       = attribute.Key("zot.storage.path")
+	TaskKey       = attribute.Key("zot.task")
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// StartSpan starts a new span, a child of the span found in ctx if any, otherwise a new trace is started.
+// The global tracer provider is a no-op unless the tracing extension is enabled.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartChildSpan starts a new span only if ctx is part of a recorded trace, it is used by components
+// called both from traced requests/tasks and from untraced code paths which should not start their own traces.
+func StartChildSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !IsRecording(ctx) {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return StartSpan(ctx, name, attrs...)
+}
+
+// RecordError marks the span as failed if err is not nil.
+func RecordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// EndSpan marks the span as failed if err is not nil, then ends it.
+func EndSpan(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// IsRecording returns true if ctx carries a span which is being recorded.
+func IsRecording(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// Middleware starts a span for each routed request, named after the matched route template
+// so that span names have a low cardinality, eg: "PUT /v2/{name}/manifests/{reference}".
+// The trace context sent by the client (or by another cluster member) is used as parent.
+func Middleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, "", otelhttp.WithSpanNameFormatter(routeSpanName))
+	}
+}
+
+// matches route variables with a pattern, eg: {name:[a-z0-9]+}.
+var routeVarPattern = regexp.MustCompile(`\{(\w+):[^}]*\}`)
+
+func routeSpanName(_ string, request *http.Request) string {
+	if route := mux.CurrentRoute(request); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return request.Method + " " + routeVarPattern.ReplaceAllString(template, "{$1}")
+		}
+	}
+
+	return request.Method
+}
+
+// InjectHeaders propagates the trace context found in ctx to an outgoing request.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
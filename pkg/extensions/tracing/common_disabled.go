@@ -0,0 +1,57 @@
+//go:build !tracing
+// +build !tracing
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of all spans recorded by zot.
+const TracerName = "zotregistry.dev/zot"
+
+// span attribute keys shared by the instrumented packages.
+const (
+	RepositoryKey = attribute.Key("zot.repository")
+	ReferenceKey  = attribute.Key("zot.reference")
+	RootDirKey    = attribute.Key("zot.storage.rootdir")
+	DriverKey     = attribute.Key("zot.storage.driver")
+	PathKey       = attribute.Key("zot.storage.path")
+	TaskKey       = attribute.Key("zot.task")
+)
+
+// StartSpan doesn't start any span, the zot binary is built without the tracing extension.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return ctx, trace.SpanFromContext(ctx)
+}
+
+// StartChildSpan doesn't start any span, the zot binary is built without the tracing extension.
+func StartChildSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return ctx, trace.SpanFromContext(ctx)
+}
+
+// RecordError ...
+func RecordError(span trace.Span, err error) {}
+
+// EndSpan ...
+func EndSpan(span trace.Span, err error) {}
+
+// IsRecording ...
+func IsRecording(ctx context.Context) bool {
+	return false
+}
+
+// Middleware ...
+func Middleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return next
+	}
+}
+
+// InjectHeaders ...
+func InjectHeaders(ctx context.Context, header http.Header) {}
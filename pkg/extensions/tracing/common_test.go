@@ -0,0 +1,107 @@
+//go:build tracing
+// +build tracing
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"zotregistry.dev/zot/pkg/extensions/tracing"
+)
+
+var errTest = errors.New("test error")
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	Convey("Child spans are only started as part of a recorded trace", t, func() {
+		ctx, span := tracing.StartChildSpan(context.Background(), "orphan")
+		So(span.IsRecording(), ShouldBeFalse)
+		So(tracing.IsRecording(ctx), ShouldBeFalse)
+		span.End()
+
+		ctx, parent := tracing.StartSpan(context.Background(), "parent")
+		So(tracing.IsRecording(ctx), ShouldBeTrue)
+
+		_, child := tracing.StartChildSpan(ctx, "child", tracing.RepositoryKey.String("alpine"))
+		So(child.IsRecording(), ShouldBeTrue)
+		tracing.EndSpan(child, errTest)
+		parent.End()
+
+		spans := recorder.Ended()
+		So(len(spans), ShouldEqual, 2)
+		So(spans[0].Name(), ShouldEqual, "child")
+		So(spans[0].Parent().SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+		So(spans[0].Status().Code, ShouldEqual, codes.Error)
+		So(spans[0].Attributes(), ShouldContain, tracing.RepositoryKey.String("alpine"))
+		So(spans[1].Name(), ShouldEqual, "parent")
+		So(spans[1].Status().Code, ShouldEqual, codes.Unset)
+	})
+
+	Convey("Request spans are named after the route and continue the trace of the caller", t, func() {
+		router := mux.NewRouter()
+		router.Use(tracing.Middleware())
+
+		handlerTraced := false
+
+		router.HandleFunc("/v2/{name:[a-z0-9]+(?:[._-][a-z0-9]+)*}/manifests/{reference}",
+			func(w http.ResponseWriter, r *http.Request) {
+				handlerTraced = tracing.IsRecording(r.Context())
+			}).Methods(http.MethodGet)
+
+		server := httptest.NewServer(router)
+		defer server.Close()
+
+		ctx, caller := tracing.StartSpan(context.Background(), "caller")
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v2/alpine/manifests/latest", nil)
+		So(err, ShouldBeNil)
+
+		tracing.InjectHeaders(ctx, request.Header)
+		So(request.Header.Get("traceparent"), ShouldNotBeEmpty)
+
+		response, err := http.DefaultClient.Do(request)
+		So(err, ShouldBeNil)
+		So(response.StatusCode, ShouldEqual, http.StatusOK)
+		response.Body.Close()
+		caller.End()
+
+		So(handlerTraced, ShouldBeTrue)
+
+		var requestSpan sdktrace.ReadOnlySpan
+
+		for _, span := range recorder.Ended() {
+			if span.Name() == "GET /v2/{name}/manifests/{reference}" {
+				requestSpan = span
+			}
+		}
+
+		So(requestSpan, ShouldNotBeNil)
+		So(requestSpan.SpanContext().TraceID(), ShouldEqual, caller.SpanContext().TraceID())
+		So(requestSpan.Parent().SpanID(), ShouldEqual, caller.SpanContext().SpanID())
+	})
+}
@@ -0,0 +1,79 @@
+//go:build tracing
+// +build tracing
+
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	extconf "zotregistry.dev/zot/pkg/extensions/config"
+	"zotregistry.dev/zot/pkg/log"
+)
+
+const (
+	DefaultServiceName = "zot"
+	DefaultTracesPath  = "/v1/traces"
+)
+
+// NewTracerProvider returns a tracer provider exporting spans in batches to an OTLP/HTTP collector,
+// it is also registered as the global tracer provider used by all instrumented components.
+func NewTracerProvider(config extconf.TracingConfig, version string, log log.Logger,
+) (*sdktrace.TracerProvider, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", config.Endpoint).Msg("failed to parse OTLP collector url")
+
+		return nil, err
+	}
+
+	// same as the OTLP exporters, spans are sent to the default traces path if the url has none
+	if strings.Trim(endpoint.Path, "/") == "" {
+		endpoint.Path = DefaultTracesPath
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", config.Endpoint).Msg("failed to create OTLP trace exporter")
+
+		return nil, err
+	}
+
+	serviceName := config.Service
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	sampleRate := 1.0
+	if config.SampleRate != nil {
+		sampleRate = *config.SampleRate
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		)),
+		// requests proxied by other cluster members follow the sampling decision taken by the first member
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	log.Info().Str("endpoint", config.Endpoint).Str("service", serviceName).Float64("sampleRate", sampleRate).
+		Msg("tracing enabled, exporting spans to OTLP collector")
+
+	return provider, nil
+}
@@ -2,6 +2,7 @@ package boltdb
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -66,6 +67,31 @@ func New(boltDB *bbolt.DB, log log.Logger) (*BoltDB, error) {
 			return err
 		}
 
+		_, err = transaction.CreateBucketIfNotExists([]byte(CVEScanResultsBuck))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(SbomPackagesBuck))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(SbomPackageIndexBuck))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(RobotAccountsBuck))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(AccessControlPoliciesBuck))
+		if err != nil {
+			return err
+		}
+
 		repoBlobsBuck, err := transaction.CreateBucketIfNotExists([]byte(RepoBlobsBuck))
 		if err != nil {
 			return err
@@ -1008,6 +1034,242 @@ func (bdw *BoltDB) AddManifestSignature(repo string, signedManifestDigest godige
 	return err
 }
 
+func (bdw *BoltDB) AddManifestSbom(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+	packages []mTypes.SbomPackage,
+) error {
+	packagesBlob, err := proto.Marshal(mConvert.GetProtoSbomPackages(packages))
+	if err != nil {
+		return err
+	}
+
+	err = bdw.DB.Update(func(tx *bbolt.Tx) error {
+		repoMetaBuck := tx.Bucket([]byte(RepoMetaBuck))
+
+		repoMetaBlob := repoMetaBuck.Get([]byte(repo))
+		if len(repoMetaBlob) == 0 {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		protoRepoMeta, err := unmarshalProtoRepoMeta(repo, repoMetaBlob)
+		if err != nil {
+			return err
+		}
+
+		sbomPackagesBuck := tx.Bucket([]byte(SbomPackagesBuck))
+		if sbomPackagesBuck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		oldPackages := &proto_go.SbomPackages{}
+
+		if oldPackagesBlob := sbomPackagesBuck.Get([]byte(sbom.SbomManifestDigest)); len(oldPackagesBlob) > 0 {
+			err = proto.Unmarshal(oldPackagesBlob, oldPackages)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = sbomPackagesBuck.Put([]byte(sbom.SbomManifestDigest), packagesBlob)
+		if err != nil {
+			return err
+		}
+
+		err = indexSbomPackages(tx, sbom.SbomManifestDigest, mConvert.GetSbomPackages(oldPackages), packages)
+		if err != nil {
+			return err
+		}
+
+		common.AddProtoSbom(protoRepoMeta, subjectDigest.String(), mConvert.GetProtoSbomInfo(sbom))
+
+		return setProtoRepoMeta(protoRepoMeta, repoMetaBuck)
+	})
+
+	return err
+}
+
+func (bdw *BoltDB) GetSbomPackages(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error) {
+	protoPackages := &proto_go.SbomPackages{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(SbomPackagesBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		packagesBlob := buck.Get([]byte(sbomDigest.String()))
+		if len(packagesBlob) == 0 {
+			return zerr.ErrSbomPackagesNotFound
+		}
+
+		return proto.Unmarshal(packagesBlob, protoPackages)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mConvert.GetSbomPackages(protoPackages), nil
+}
+
+// indexSbomPackages updates the package index with the packages listed by an sbom, removing the sbom from the
+// entries of the packages it no longer lists.
+func indexSbomPackages(tx *bbolt.Tx, sbomDigest string, oldPackages, packages []mTypes.SbomPackage) error {
+	indexBuck := tx.Bucket([]byte(SbomPackageIndexBuck))
+	if indexBuck == nil {
+		return zerr.ErrBucketDoesNotExist
+	}
+
+	keys := map[string]bool{}
+
+	for _, pkg := range packages {
+		for _, key := range common.SbomPackageIndexKeys(pkg) {
+			keys[key] = true
+		}
+	}
+
+	for _, pkg := range oldPackages {
+		for _, key := range common.SbomPackageIndexKeys(pkg) {
+			if keys[key] {
+				continue
+			}
+
+			sbomsBuck := indexBuck.Bucket([]byte(key))
+			if sbomsBuck == nil {
+				continue
+			}
+
+			err := sbomsBuck.Delete([]byte(sbomDigest))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for key := range keys {
+		sbomsBuck, err := indexBuck.CreateBucketIfNotExists([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		err = sbomsBuck.Put([]byte(sbomDigest), []byte{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bdw *BoltDB) GetSbomsWithPackage(name string) ([]godigest.Digest, error) {
+	sbomDigests := []godigest.Digest{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		indexBuck := tx.Bucket([]byte(SbomPackageIndexBuck))
+		if indexBuck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		sbomsBuck := indexBuck.Bucket([]byte(name))
+		if sbomsBuck == nil {
+			return nil
+		}
+
+		return sbomsBuck.ForEach(func(sbomDigest, _ []byte) error {
+			sbomDigests = append(sbomDigests, godigest.Digest(sbomDigest))
+
+			return nil
+		})
+	})
+
+	return sbomDigests, err
+}
+
+func (bdw *BoltDB) SetCVEScanResult(digest godigest.Digest, result mTypes.CVEScanResult) error {
+	resultBlob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	err = bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(CVEScanResultsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		return buck.Put([]byte(digest.String()), resultBlob)
+	})
+
+	return err
+}
+
+func (bdw *BoltDB) GetCVEScanResult(digest godigest.Digest) (mTypes.CVEScanResult, error) {
+	var result mTypes.CVEScanResult
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(CVEScanResultsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		resultBlob := buck.Get([]byte(digest.String()))
+		if len(resultBlob) == 0 {
+			return zerr.ErrCVEScanResultNotFound
+		}
+
+		return json.Unmarshal(resultBlob, &result)
+	})
+
+	return result, err
+}
+
+func (bdw *BoltDB) DeleteCVEScanResult(digest godigest.Digest) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(CVEScanResultsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		return buck.Delete([]byte(digest.String()))
+	})
+
+	return err
+}
+
+func (bdw *BoltDB) DeleteCVEScanResults(isStale func(result mTypes.CVEScanResult) bool) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(CVEScanResultsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		staleDigests := [][]byte{}
+
+		err := buck.ForEach(func(digest, resultBlob []byte) error {
+			var result mTypes.CVEScanResult
+
+			// results which can't be read are stale too
+			if err := json.Unmarshal(resultBlob, &result); err != nil || isStale(result) {
+				staleDigests = append(staleDigests, digest)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// the bucket can't be changed while iterating over it
+		for _, digest := range staleDigests {
+			if err := buck.Delete(digest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
 func (bdw *BoltDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
 	sigMeta mTypes.SignatureMetadata,
 ) error {
@@ -1097,6 +1359,33 @@ func (bdw *BoltDB) DecrementRepoStars(repo string) error {
 	return err
 }
 
+func (bdw *BoltDB) SetRepoProxyCache(repo string, upstream string) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		repoMetaBuck := tx.Bucket([]byte(RepoMetaBuck))
+
+		repoMetaBlob := repoMetaBuck.Get([]byte(repo))
+		if len(repoMetaBlob) == 0 {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		protoRepoMeta, err := unmarshalProtoRepoMeta(repo, repoMetaBlob)
+		if err != nil {
+			return err
+		}
+
+		if protoRepoMeta.IsProxyCache && protoRepoMeta.Upstream == upstream {
+			return nil
+		}
+
+		protoRepoMeta.IsProxyCache = true
+		protoRepoMeta.Upstream = upstream
+
+		return setProtoRepoMeta(protoRepoMeta, repoMetaBuck)
+	})
+
+	return err
+}
+
 func (bdw *BoltDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMeta) error {
 	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
 		buck := tx.Bucket([]byte(RepoMetaBuck))
@@ -1155,12 +1444,14 @@ func (bdw *BoltDB) ResetRepoReferences(repo string) error {
 		}
 
 		repoMetaBlob, err = proto.Marshal(&proto_go.RepoMeta{
-			Name:       repo,
-			Statistics: protoRepoMeta.Statistics,
-			Stars:      protoRepoMeta.Stars,
-			Tags:       map[string]*proto_go.TagDescriptor{"": {}},
-			Signatures: map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
-			Referrers:  map[string]*proto_go.ReferrersInfo{"": {}},
+			Name:         repo,
+			Statistics:   protoRepoMeta.Statistics,
+			Stars:        protoRepoMeta.Stars,
+			IsProxyCache: protoRepoMeta.GetIsProxyCache(),
+			Upstream:     protoRepoMeta.GetUpstream(),
+			Tags:         map[string]*proto_go.TagDescriptor{"": {}},
+			Signatures:   map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
+			Referrers:    map[string]*proto_go.ReferrersInfo{"": {}},
 		})
 		if err != nil {
 			return err
@@ -1299,8 +1590,8 @@ func (bdw *BoltDB) UpdateSignaturesValidity(ctx context.Context, repo string, ma
 				layersInfo := []*proto_go.LayersInfo{}
 
 				for _, layerInfo := range sigInfo.LayersInfo {
-					author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
-						layerInfo.SignatureKey, manifestDigest, mConvert.GetImageMeta(&protoImageMeta), repo)
+					author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType,
+						mConvert.GetLayerInfo(layerInfo), manifestDigest, mConvert.GetImageMeta(&protoImageMeta), repo)
 
 					if isTrusted {
 						layerInfo.Signer = author
@@ -1409,6 +1700,8 @@ func (bdw *BoltDB) RemoveRepoReference(repo, reference string, manifestDigest go
 			delete(protoRepoMeta.Statistics, manifestDigest.String())
 			delete(protoRepoMeta.Signatures, manifestDigest.String())
 			delete(protoRepoMeta.Referrers, manifestDigest.String())
+			delete(protoRepoMeta.Sboms, manifestDigest.String())
+			common.RemoveProtoSbom(protoRepoMeta, manifestDigest.String())
 		}
 
 		repoBlobsBytes := repoBlobsBuck.Get([]byte(protoRepoMeta.Name))
@@ -1924,6 +2217,184 @@ func (bdw *BoltDB) GetUserAPIKeyInfo(hashedKey string) (string, error) {
 	return userid, err
 }
 
+func (bdw *BoltDB) AddRobotAccount(robot mTypes.RobotAccount) error {
+	return bdw.putRobotAccount(robot, false)
+}
+
+func (bdw *BoltDB) UpdateRobotAccount(robot mTypes.RobotAccount) error {
+	return bdw.putRobotAccount(robot, true)
+}
+
+func (bdw *BoltDB) putRobotAccount(robot mTypes.RobotAccount, exists bool) error {
+	robotBlob, err := json.Marshal(robot)
+	if err != nil {
+		return err
+	}
+
+	return bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(RobotAccountsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		found := len(buck.Get([]byte(robot.Name))) != 0
+
+		if exists && !found {
+			return zerr.ErrRobotAccountNotFound
+		}
+
+		if !exists && found {
+			return zerr.ErrRobotAccountAlreadyExists
+		}
+
+		return buck.Put([]byte(robot.Name), robotBlob)
+	})
+}
+
+func (bdw *BoltDB) GetRobotAccount(name string) (mTypes.RobotAccount, error) {
+	var robot mTypes.RobotAccount
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(RobotAccountsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		robotBlob := buck.Get([]byte(name))
+		if len(robotBlob) == 0 {
+			return zerr.ErrRobotAccountNotFound
+		}
+
+		return json.Unmarshal(robotBlob, &robot)
+	})
+
+	return robot, err
+}
+
+func (bdw *BoltDB) GetRobotAccounts() ([]mTypes.RobotAccount, error) {
+	robots := []mTypes.RobotAccount{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(RobotAccountsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		// the keys are iterated in byte-sorted order
+		return buck.ForEach(func(_, robotBlob []byte) error {
+			var robot mTypes.RobotAccount
+
+			if err := json.Unmarshal(robotBlob, &robot); err != nil {
+				return err
+			}
+
+			robots = append(robots, robot)
+
+			return nil
+		})
+	})
+
+	return robots, err
+}
+
+func (bdw *BoltDB) DeleteRobotAccount(name string) error {
+	return bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(RobotAccountsBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		return buck.Delete([]byte(name))
+	})
+}
+
+// policiesVersionKey encodes the version in big endian so the keys are sorted by version.
+func policiesVersionKey(version int) []byte {
+	key := make([]byte, 8) //nolint:mnd
+
+	binary.BigEndian.PutUint64(key, uint64(version)) //nolint:gosec
+
+	return key
+}
+
+func (bdw *BoltDB) AddAccessControlPolicies(policies mTypes.AccessControlPolicies) error {
+	policiesBlob, err := json.Marshal(policies)
+	if err != nil {
+		return err
+	}
+
+	return bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(AccessControlPoliciesBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		latestVersion := 0
+
+		if latestKey, _ := buck.Cursor().Last(); latestKey != nil {
+			latestVersion = int(binary.BigEndian.Uint64(latestKey)) //nolint:gosec
+		}
+
+		if policies.Version != latestVersion+1 {
+			return zerr.ErrAccessControlPoliciesConflict
+		}
+
+		return buck.Put(policiesVersionKey(policies.Version), policiesBlob)
+	})
+}
+
+func (bdw *BoltDB) GetAccessControlPolicies(version int) (mTypes.AccessControlPolicies, error) {
+	var policies mTypes.AccessControlPolicies
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(AccessControlPoliciesBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		var policiesBlob []byte
+
+		if version == 0 {
+			_, policiesBlob = buck.Cursor().Last()
+		} else {
+			policiesBlob = buck.Get(policiesVersionKey(version))
+		}
+
+		if len(policiesBlob) == 0 {
+			return zerr.ErrAccessControlPoliciesNotFound
+		}
+
+		return json.Unmarshal(policiesBlob, &policies)
+	})
+
+	return policies, err
+}
+
+func (bdw *BoltDB) GetAccessControlPoliciesHistory() ([]mTypes.AccessControlPolicies, error) {
+	history := []mTypes.AccessControlPolicies{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(AccessControlPoliciesBuck))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		return buck.ForEach(func(_, policiesBlob []byte) error {
+			var policies mTypes.AccessControlPolicies
+
+			if err := json.Unmarshal(policiesBlob, &policies); err != nil {
+				return err
+			}
+
+			history = append(history, policies)
+
+			return nil
+		})
+	})
+
+	return history, err
+}
+
 func (bdw *BoltDB) GetUserData(ctx context.Context) (mTypes.UserData, error) {
 	var userData mTypes.UserData
 
@@ -2059,6 +2530,31 @@ func (bdw *BoltDB) ResetDB() error {
 			return err
 		}
 
+		err = resetBucket(transaction, CVEScanResultsBuck)
+		if err != nil {
+			return err
+		}
+
+		err = resetBucket(transaction, SbomPackagesBuck)
+		if err != nil {
+			return err
+		}
+
+		err = resetBucket(transaction, SbomPackageIndexBuck)
+		if err != nil {
+			return err
+		}
+
+		err = resetBucket(transaction, RobotAccountsBuck)
+		if err != nil {
+			return err
+		}
+
+		err = resetBucket(transaction, AccessControlPoliciesBuck)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
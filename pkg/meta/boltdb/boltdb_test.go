@@ -26,7 +26,7 @@ import (
 type imgTrustStore struct{}
 
 func (its imgTrustStore) VerifySignature(
-	signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
+	signatureType string, layerInfo mTypes.LayerInfo, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
 	repo string,
 ) (mTypes.Author, mTypes.ExpiryDate, mTypes.Validity, error) {
 	return "", time.Time{}, false, nil
@@ -254,6 +254,130 @@ func TestWrapperErrors(t *testing.T) {
 			})
 		})
 
+		Convey("AddManifestSbom", func() {
+			Convey("unmarshalProtoRepoMeta error", func() {
+				err := setRepoMeta("repo", badProtoBlob, boltdbWrapper.DB)
+				So(err, ShouldBeNil)
+
+				err = boltdbWrapper.AddManifestSbom("repo", godigest.FromString("dig"), mTypes.SbomInfo{}, nil)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("repo meta not found", func() {
+				err := boltdbWrapper.AddManifestSbom("repo", godigest.FromString("dig"), mTypes.SbomInfo{}, nil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("CVEScanResult", func() {
+			Convey("unmarshal error", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					buck := tx.Bucket([]byte(boltdb.CVEScanResultsBuck))
+
+					return buck.Put([]byte(godigest.FromString("dig").String()), []byte("bad json"))
+				})
+				So(err, ShouldBeNil)
+
+				_, err = boltdbWrapper.GetCVEScanResult(godigest.FromString("dig"))
+				So(err, ShouldNotBeNil)
+
+				// results which can't be read are deleted with the stale ones
+				err = boltdbWrapper.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool { return false })
+				So(err, ShouldBeNil)
+
+				_, err = boltdbWrapper.GetCVEScanResult(godigest.FromString("dig"))
+				So(err, ShouldEqual, zerr.ErrCVEScanResultNotFound)
+			})
+
+			Convey("bucket doesn't exist", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					return tx.DeleteBucket([]byte(boltdb.CVEScanResultsBuck))
+				})
+				So(err, ShouldBeNil)
+
+				err = boltdbWrapper.SetCVEScanResult(godigest.FromString("dig"), mTypes.CVEScanResult{})
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				_, err = boltdbWrapper.GetCVEScanResult(godigest.FromString("dig"))
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				err = boltdbWrapper.DeleteCVEScanResult(godigest.FromString("dig"))
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				err = boltdbWrapper.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool { return true })
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+			})
+		})
+
+		Convey("RobotAccounts", func() {
+			Convey("unmarshal error", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					buck := tx.Bucket([]byte(boltdb.RobotAccountsBuck))
+
+					return buck.Put([]byte("ci"), []byte("bad json"))
+				})
+				So(err, ShouldBeNil)
+
+				_, err = boltdbWrapper.GetRobotAccount("ci")
+				So(err, ShouldNotBeNil)
+
+				_, err = boltdbWrapper.GetRobotAccounts()
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("bucket doesn't exist", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					return tx.DeleteBucket([]byte(boltdb.RobotAccountsBuck))
+				})
+				So(err, ShouldBeNil)
+
+				err = boltdbWrapper.AddRobotAccount(mTypes.RobotAccount{Name: "ci"})
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				_, err = boltdbWrapper.GetRobotAccount("ci")
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				_, err = boltdbWrapper.GetRobotAccounts()
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				err = boltdbWrapper.DeleteRobotAccount("ci")
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+			})
+		})
+
+		Convey("AccessControlPolicies", func() {
+			Convey("unmarshal error", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					buck := tx.Bucket([]byte(boltdb.AccessControlPoliciesBuck))
+
+					return buck.Put([]byte{0, 0, 0, 0, 0, 0, 0, 1}, []byte("bad json"))
+				})
+				So(err, ShouldBeNil)
+
+				_, err = boltdbWrapper.GetAccessControlPolicies(0)
+				So(err, ShouldNotBeNil)
+
+				_, err = boltdbWrapper.GetAccessControlPoliciesHistory()
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("bucket doesn't exist", func() {
+				err := boltdbWrapper.DB.Update(func(tx *bbolt.Tx) error {
+					return tx.DeleteBucket([]byte(boltdb.AccessControlPoliciesBuck))
+				})
+				So(err, ShouldBeNil)
+
+				err = boltdbWrapper.AddAccessControlPolicies(mTypes.AccessControlPolicies{Version: 1})
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				_, err = boltdbWrapper.GetAccessControlPolicies(1)
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+
+				_, err = boltdbWrapper.GetAccessControlPoliciesHistory()
+				So(err, ShouldEqual, zerr.ErrBucketDoesNotExist)
+			})
+		})
+
 		Convey("GetMultipleRepoMeta", func() {
 			Convey("unmarshalProtoRepoMeta error", func() {
 				err := setRepoMeta("repo", badProtoBlob, boltdbWrapper.DB)
@@ -2,11 +2,16 @@ package boltdb
 
 // MetadataDB.
 const (
-	ImageMetaBuck     = "ImageMeta"
-	RepoMetaBuck      = "RepoMeta"
-	UserDataBucket    = "UserData"
-	VersionBucket     = "Version"
-	UserAPIKeysBucket = "UserAPIKeys"
+	ImageMetaBuck             = "ImageMeta"
+	RepoMetaBuck              = "RepoMeta"
+	UserDataBucket            = "UserData"
+	VersionBucket             = "Version"
+	UserAPIKeysBucket         = "UserAPIKeys"
+	CVEScanResultsBuck        = "CVEScanResults"
+	SbomPackagesBuck          = "SbomPackages"
+	SbomPackageIndexBuck      = "SbomPackageIndex"
+	RobotAccountsBuck         = "RobotAccounts"
+	AccessControlPoliciesBuck = "AccessControlPolicies"
 )
 
 const (
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -34,6 +35,88 @@ func ProtoSignatureAlreadyExists(signatureSlice []*proto_go.SignatureInfo, sm mT
 	return false
 }
 
+// HasTrustedSignature returns true if one of the signatures of the given types (all types if empty) was
+// verified with the uploaded cosign keys or notation certificates and its certificate didn't expire, same as
+// the IsTrusted field of the signatures exposed through graphql.
+func HasTrustedSignature(signatures mTypes.ManifestSignatures, signatureTypes []string) bool {
+	for signatureType, signatureInfos := range signatures {
+		if len(signatureTypes) > 0 && !slices.Contains(signatureTypes, signatureType) {
+			continue
+		}
+
+		for _, signatureInfo := range signatureInfos {
+			for _, layer := range signatureInfo.LayersInfo {
+				if layer.Signer != "" && (layer.Date.IsZero() || time.Now().Before(layer.Date)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// AddProtoSbom adds the sbom to the sboms of the subject manifest, replacing the entry of the same sbom manifest.
+func AddProtoSbom(repoMeta *proto_go.RepoMeta, subjectDigest string, sbom *proto_go.SbomInfo) {
+	if repoMeta.Sboms == nil {
+		repoMeta.Sboms = map[string]*proto_go.SbomsInfo{}
+	}
+
+	sbomsInfo, found := repoMeta.Sboms[subjectDigest]
+	if !found {
+		sbomsInfo = &proto_go.SbomsInfo{}
+	}
+
+	for i := range sbomsInfo.List {
+		if sbomsInfo.List[i].SbomManifestDigest == sbom.SbomManifestDigest {
+			sbomsInfo.List[i] = sbom
+			repoMeta.Sboms[subjectDigest] = sbomsInfo
+
+			return
+		}
+	}
+
+	sbomsInfo.List = append(sbomsInfo.List, sbom)
+	repoMeta.Sboms[subjectDigest] = sbomsInfo
+}
+
+// RemoveProtoSbom removes the packages added from the given sbom manifest from all manifests in the repo.
+func RemoveProtoSbom(repoMeta *proto_go.RepoMeta, sbomManifestDigest string) {
+	for subjectDigest, sbomsInfo := range repoMeta.Sboms {
+		sboms := make([]*proto_go.SbomInfo, 0, len(sbomsInfo.GetList()))
+
+		for _, sbom := range sbomsInfo.GetList() {
+			if sbom.SbomManifestDigest != sbomManifestDigest {
+				sboms = append(sboms, sbom)
+			}
+		}
+
+		if len(sboms) == 0 {
+			delete(repoMeta.Sboms, subjectDigest)
+
+			continue
+		}
+
+		sbomsInfo.List = sboms
+	}
+}
+
+// SbomPackageIndexKeys returns the names a package is looked up by in the package index: its name, its purl
+// and its purl without the version part.
+func SbomPackageIndexKeys(pkg mTypes.SbomPackage) []string {
+	keys := []string{}
+
+	purlWithoutVersion, _, _ := strings.Cut(pkg.PURL, "@")
+
+	for _, key := range []string{pkg.Name, pkg.PURL, purlWithoutVersion} {
+		if key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
 func ReferenceIsDigest(reference string) bool {
 	_, err := godigest.Parse(reference)
 
@@ -224,6 +224,44 @@ func GetImageReferrers(refs *proto_go.ReferrersInfo) []mTypes.ReferrerInfo {
 	return results
 }
 
+func GetSboms(sboms map[string]*proto_go.SbomsInfo) map[string][]mTypes.SbomInfo {
+	results := map[string][]mTypes.SbomInfo{}
+
+	for digest, sbomsInfo := range sboms {
+		results[digest] = GetImageSboms(sbomsInfo)
+	}
+
+	return results
+}
+
+func GetImageSboms(sboms *proto_go.SbomsInfo) []mTypes.SbomInfo {
+	results := []mTypes.SbomInfo{}
+
+	for _, sbom := range sboms.GetList() {
+		results = append(results, mTypes.SbomInfo{
+			SbomManifestDigest: sbom.GetSbomManifestDigest(),
+			Format:             sbom.GetFormat(),
+			PackageCount:       int(sbom.GetPackageCount()),
+		})
+	}
+
+	return results
+}
+
+func GetSbomPackages(sbomPackages *proto_go.SbomPackages) []mTypes.SbomPackage {
+	packages := make([]mTypes.SbomPackage, 0, len(sbomPackages.GetPackages()))
+
+	for _, pkg := range sbomPackages.GetPackages() {
+		packages = append(packages, mTypes.SbomPackage{
+			Name:    pkg.GetName(),
+			Version: pkg.GetVersion(),
+			PURL:    pkg.GetPURL(),
+		})
+	}
+
+	return packages
+}
+
 func GetSignatures(sigs map[string]*proto_go.ManifestSignatures) map[string]mTypes.ManifestSignatures {
 	results := map[string]mTypes.ManifestSignatures{}
 
@@ -271,22 +309,29 @@ func GetLayersInfo(layersInfo []*proto_go.LayersInfo) []mTypes.LayerInfo {
 	results := []mTypes.LayerInfo{}
 
 	for _, layerInfo := range layersInfo {
-		date := time.Time{}
+		results = append(results, GetLayerInfo(layerInfo))
+	}
 
-		if layerInfo.GetDate() != nil {
-			date = layerInfo.GetDate().AsTime()
-		}
+	return results
+}
 
-		results = append(results, mTypes.LayerInfo{
-			LayerDigest:  layerInfo.GetLayerDigest(),
-			LayerContent: layerInfo.GetLayerContent(),
-			SignatureKey: layerInfo.GetSignatureKey(),
-			Signer:       layerInfo.GetSigner(),
-			Date:         date,
-		})
+func GetLayerInfo(layerInfo *proto_go.LayersInfo) mTypes.LayerInfo {
+	date := time.Time{}
+
+	if layerInfo.GetDate() != nil {
+		date = layerInfo.GetDate().AsTime()
 	}
 
-	return results
+	return mTypes.LayerInfo{
+		LayerDigest:      layerInfo.GetLayerDigest(),
+		LayerContent:     layerInfo.GetLayerContent(),
+		SignatureKey:     layerInfo.GetSignatureKey(),
+		Signer:           layerInfo.GetSigner(),
+		Date:             date,
+		Certificate:      layerInfo.GetCertificate(),
+		CertificateChain: layerInfo.GetCertificateChain(),
+		Bundle:           layerInfo.GetBundle(),
+	}
 }
 
 func GetStatisticsMap(stats map[mTypes.ImageDigest]*proto_go.DescriptorStatistics,
@@ -410,6 +455,7 @@ func GetFullImageMetaFromProto(tag string, protoRepoMeta *proto_go.RepoMeta, pro
 		Referrers:  GetImageReferrers(protoRepoMeta.GetReferrers()[imageDigest]),
 		Statistics: GetImageStatistics(protoRepoMeta.GetStatistics()[imageDigest]),
 		Signatures: GetImageSignatures(protoRepoMeta.GetSignatures()[imageDigest]),
+		Sboms:      GetImageSboms(protoRepoMeta.GetSboms()[imageDigest]),
 	}
 }
 
@@ -427,6 +473,7 @@ func GetFullManifestData(protoRepoMeta *proto_go.RepoMeta, manifestData []mTypes
 			Referrers:    GetImageReferrers(protoRepoMeta.GetReferrers()[manifestData[i].Digest.String()]),
 			Statistics:   GetImageStatistics(protoRepoMeta.GetStatistics()[manifestData[i].Digest.String()]),
 			Signatures:   GetImageSignatures(protoRepoMeta.GetSignatures()[manifestData[i].Digest.String()]),
+			Sboms:        GetImageSboms(protoRepoMeta.GetSboms()[manifestData[i].Digest.String()]),
 		})
 	}
 
@@ -461,6 +508,9 @@ func GetRepoMeta(protoRepoMeta *proto_go.RepoMeta) mTypes.RepoMeta {
 		Statistics:       GetStatisticsMap(protoRepoMeta.GetStatistics()),
 		Signatures:       GetSignatures(protoRepoMeta.GetSignatures()),
 		Referrers:        GetReferrers(protoRepoMeta.GetReferrers()),
+		Sboms:            GetSboms(protoRepoMeta.GetSboms()),
+		IsProxyCache:     protoRepoMeta.GetIsProxyCache(),
+		Upstream:         protoRepoMeta.GetUpstream(),
 	}
 }
 
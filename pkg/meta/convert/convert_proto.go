@@ -19,12 +19,15 @@ func GetProtoRepoMeta(repo mTypes.RepoMeta) *proto_go.RepoMeta {
 		Statistics:       GetProtoStatistics(repo.Statistics),
 		Signatures:       GetProtoSignatures(repo.Signatures),
 		Referrers:        GetProtoReferrers(repo.Referrers),
+		Sboms:            GetProtoSboms(repo.Sboms),
 		Size:             repo.Size,
 		Vendors:          repo.Vendors,
 		Platforms:        GetProtoPlatforms(repo.Platforms),
 		LastUpdatedImage: GetProtoLastUpdatedImage(repo.LastUpdatedImage),
 		Stars:            int32(repo.StarCount),     //nolint:gosec // ignore overflow
 		Downloads:        int32(repo.DownloadCount), //nolint:gosec // ignore overflow
+		IsProxyCache:     repo.IsProxyCache,
+		Upstream:         repo.Upstream,
 	}
 }
 
@@ -164,6 +167,44 @@ func GetProtoReferrers(refs map[string][]mTypes.ReferrerInfo) map[string]*proto_
 	return results
 }
 
+func GetProtoSboms(sboms map[string][]mTypes.SbomInfo) map[string]*proto_go.SbomsInfo {
+	results := map[string]*proto_go.SbomsInfo{}
+
+	for digest, sbomsInfo := range sboms {
+		sbomsList := make([]*proto_go.SbomInfo, 0, len(sbomsInfo))
+
+		for _, sbom := range sbomsInfo {
+			sbomsList = append(sbomsList, GetProtoSbomInfo(sbom))
+		}
+
+		results[digest] = &proto_go.SbomsInfo{List: sbomsList}
+	}
+
+	return results
+}
+
+func GetProtoSbomInfo(sbom mTypes.SbomInfo) *proto_go.SbomInfo {
+	return &proto_go.SbomInfo{
+		SbomManifestDigest: sbom.SbomManifestDigest,
+		Format:             sbom.Format,
+		PackageCount:       int64(sbom.PackageCount),
+	}
+}
+
+func GetProtoSbomPackages(packages []mTypes.SbomPackage) *proto_go.SbomPackages {
+	protoPackages := make([]*proto_go.SbomPackage, 0, len(packages))
+
+	for _, pkg := range packages {
+		protoPackages = append(protoPackages, &proto_go.SbomPackage{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    pkg.PURL,
+		})
+	}
+
+	return &proto_go.SbomPackages{Packages: protoPackages}
+}
+
 func GetProtoSignatures(sigs map[string]mTypes.ManifestSignatures) map[string]*proto_go.ManifestSignatures {
 	results := map[string]*proto_go.ManifestSignatures{}
 
@@ -198,11 +239,14 @@ func GetProtoLayersInfo(layersInfo []mTypes.LayerInfo) []*proto_go.LayersInfo {
 
 	for _, layerInfo := range layersInfo {
 		result = append(result, &proto_go.LayersInfo{
-			LayerDigest:  layerInfo.LayerDigest,
-			LayerContent: layerInfo.LayerContent,
-			SignatureKey: layerInfo.SignatureKey,
-			Signer:       layerInfo.Signer,
-			Date:         timestamppb.New(layerInfo.Date),
+			LayerDigest:      layerInfo.LayerDigest,
+			LayerContent:     layerInfo.LayerContent,
+			SignatureKey:     layerInfo.SignatureKey,
+			Signer:           layerInfo.Signer,
+			Date:             timestamppb.New(layerInfo.Date),
+			Certificate:      layerInfo.Certificate,
+			CertificateChain: layerInfo.CertificateChain,
+			Bundle:           layerInfo.Bundle,
 		})
 	}
 
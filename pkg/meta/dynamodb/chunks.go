@@ -0,0 +1,196 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// dynamodb items can't be larger than 400KB, blobs which could grow past that (sbom packages, cve scan
+// results) are split in chunks stored as separate items. The item keyed by the blob key holds the first
+// chunk and the number of chunks, the others are keyed "<key>#<index>".
+const maxItemBlobSize = 350 * 1024
+
+func chunkKey(key string, index int) string {
+	if index == 0 {
+		return key
+	}
+
+	return key + "#" + strconv.Itoa(index)
+}
+
+func (dwr *DynamoDB) setChunkedBlob(ctx context.Context, tableName, key string, blob []byte) error {
+	chunks := [][]byte{}
+
+	for start := 0; start < len(blob); start += maxItemBlobSize {
+		chunks = append(chunks, blob[start:min(start+maxItemBlobSize, len(blob))])
+	}
+
+	if len(chunks) == 0 {
+		chunks = append(chunks, []byte{})
+	}
+
+	oldChunkCount, err := dwr.getChunkCount(ctx, tableName, key)
+	if err != nil {
+		return err
+	}
+
+	// the first chunk is written last so readers never see a chunk count the other items don't match yet
+	for index := len(chunks) - 1; index >= 0; index-- {
+		_, err := dwr.Client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(tableName),
+			Item: map[string]types.AttributeValue{
+				"TableKey": &types.AttributeValueMemberS{Value: chunkKey(key, index)},
+				"Blob":     &types.AttributeValueMemberB{Value: chunks[index]},
+				"Chunks":   &types.AttributeValueMemberN{Value: strconv.Itoa(len(chunks))},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for index := len(chunks); index < oldChunkCount; index++ {
+		_, err := dwr.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"TableKey": &types.AttributeValueMemberS{Value: chunkKey(key, index)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getChunkedBlob returns the blob stored with setChunkedBlob and false if there is none.
+func (dwr *DynamoDB) getChunkedBlob(ctx context.Context, tableName, key string) ([]byte, bool, error) {
+	blob := []byte{}
+
+	chunkCount := 1
+
+	for index := 0; index < chunkCount; index++ {
+		resp, err := dwr.Client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"TableKey": &types.AttributeValueMemberS{Value: chunkKey(key, index)},
+			},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		if resp.Item == nil {
+			if index == 0 {
+				return nil, false, nil
+			}
+
+			return nil, false, fmt.Errorf("missing chunk %d of %s in table %s", index, key, tableName)
+		}
+
+		if index == 0 {
+			err = attributevalue.Unmarshal(resp.Item["Chunks"], &chunkCount)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+
+		chunk := []byte{}
+
+		err = attributevalue.Unmarshal(resp.Item["Blob"], &chunk)
+		if err != nil {
+			return nil, false, err
+		}
+
+		blob = append(blob, chunk...)
+	}
+
+	return blob, true, nil
+}
+
+// deleteChunkedBlob deletes all the chunks of a blob stored with setChunkedBlob.
+func (dwr *DynamoDB) deleteChunkedBlob(ctx context.Context, tableName, key string) error {
+	chunkCount, err := dwr.getChunkCount(ctx, tableName, key)
+	if err != nil {
+		return err
+	}
+
+	// the first chunk is deleted last so the others are found again if a deletion fails
+	for index := chunkCount - 1; index >= 0; index-- {
+		_, err := dwr.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"TableKey": &types.AttributeValueMemberS{Value: chunkKey(key, index)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getChunkedBlobKeys returns the keys of all the blobs stored in the table with setChunkedBlob.
+func (dwr *DynamoDB) getChunkedBlobKeys(ctx context.Context, tableName string) ([]string, error) {
+	keys := []string{}
+
+	paginator := dynamodb.NewScanPaginator(dwr.Client, &dynamodb.ScanInput{
+		TableName:            aws.String(tableName),
+		ProjectionExpression: aws.String("TableKey"),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			var key string
+
+			if err := attributevalue.Unmarshal(item["TableKey"], &key); err != nil {
+				return nil, err
+			}
+
+			// the items holding the other chunks are keyed "<key>#<index>"
+			if !strings.Contains(key, "#") {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+func (dwr *DynamoDB) getChunkCount(ctx context.Context, tableName, key string) (int, error) {
+	resp, err := dwr.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: key},
+		},
+		ProjectionExpression: aws.String("Chunks"),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	chunkCount := 0
+
+	if resp.Item != nil && resp.Item["Chunks"] != nil {
+		err = attributevalue.Unmarshal(resp.Item["Chunks"], &chunkCount)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return chunkCount, nil
+}
@@ -2,8 +2,11 @@ package dynamodb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,31 +33,39 @@ import (
 )
 
 type DynamoDB struct {
-	Client             *dynamodb.Client
-	APIKeyTablename    string
-	RepoMetaTablename  string
-	RepoBlobsTablename string
-	ImageMetaTablename string
-	UserDataTablename  string
-	VersionTablename   string
-	Patches            []func(client *dynamodb.Client, tableNames map[string]string) error
-	imgTrustStore      mTypes.ImageTrustStore
-	Log                log.Logger
+	Client                  *dynamodb.Client
+	APIKeyTablename         string
+	RepoMetaTablename       string
+	RepoBlobsTablename      string
+	ImageMetaTablename      string
+	UserDataTablename       string
+	VersionTablename        string
+	CVEScanResultsTablename string
+	SbomPackagesTablename   string
+	RobotAccountsTablename  string
+	AccessControlTablename  string
+	Patches                 []func(client *dynamodb.Client, tableNames map[string]string) error
+	imgTrustStore           mTypes.ImageTrustStore
+	Log                     log.Logger
 }
 
 func New(client *dynamodb.Client, params DBDriverParameters, log log.Logger,
 ) (*DynamoDB, error) {
 	dynamoWrapper := DynamoDB{
-		Client:             client,
-		VersionTablename:   params.VersionTablename,
-		UserDataTablename:  params.UserDataTablename,
-		APIKeyTablename:    params.APIKeyTablename,
-		RepoMetaTablename:  params.RepoMetaTablename,
-		ImageMetaTablename: params.ImageMetaTablename,
-		RepoBlobsTablename: params.RepoBlobsInfoTablename,
-		Patches:            version.GetDynamoDBPatches(),
-		imgTrustStore:      nil,
-		Log:                log,
+		Client:                  client,
+		VersionTablename:        params.VersionTablename,
+		UserDataTablename:       params.UserDataTablename,
+		APIKeyTablename:         params.APIKeyTablename,
+		RepoMetaTablename:       params.RepoMetaTablename,
+		ImageMetaTablename:      params.ImageMetaTablename,
+		RepoBlobsTablename:      params.RepoBlobsInfoTablename,
+		CVEScanResultsTablename: params.CVEScanResultsTablename,
+		SbomPackagesTablename:   params.SbomPackagesTablename,
+		RobotAccountsTablename:  params.RobotAccountsTablename,
+		AccessControlTablename:  params.AccessControlTablename,
+		Patches:                 version.GetDynamoDBPatches(),
+		imgTrustStore:           nil,
+		Log:                     log,
 	}
 
 	err := dynamoWrapper.createVersionTable()
@@ -87,6 +98,36 @@ func New(client *dynamodb.Client, params DBDriverParameters, log log.Logger,
 		return nil, err
 	}
 
+	// the cve scan results are only persisted if a table is configured for them
+	if dynamoWrapper.CVEScanResultsTablename != "" {
+		err = dynamoWrapper.createTable(dynamoWrapper.CVEScanResultsTablename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the packages listed by sboms are only persisted if a table is configured for them
+	if dynamoWrapper.SbomPackagesTablename != "" {
+		err = dynamoWrapper.createTable(dynamoWrapper.SbomPackagesTablename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dynamoWrapper.RobotAccountsTablename != "" {
+		err = dynamoWrapper.createTable(dynamoWrapper.RobotAccountsTablename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dynamoWrapper.AccessControlTablename != "" {
+		err = dynamoWrapper.createTable(dynamoWrapper.AccessControlTablename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Using the Config value, create the DynamoDB client
 	return &dynamoWrapper, nil
 }
@@ -860,12 +901,14 @@ func (dwr *DynamoDB) ResetRepoReferences(repo string) error {
 	}
 
 	return dwr.setProtoRepoMeta(repo, &proto_go.RepoMeta{
-		Name:       repo,
-		Statistics: protoRepoMeta.Statistics,
-		Stars:      protoRepoMeta.Stars,
-		Tags:       map[string]*proto_go.TagDescriptor{"": {}},
-		Referrers:  map[string]*proto_go.ReferrersInfo{"": {}},
-		Signatures: map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
+		Name:         repo,
+		Statistics:   protoRepoMeta.Statistics,
+		Stars:        protoRepoMeta.Stars,
+		IsProxyCache: protoRepoMeta.GetIsProxyCache(),
+		Upstream:     protoRepoMeta.GetUpstream(),
+		Tags:         map[string]*proto_go.TagDescriptor{"": {}},
+		Referrers:    map[string]*proto_go.ReferrersInfo{"": {}},
+		Signatures:   map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
 	})
 }
 
@@ -1067,6 +1110,22 @@ func (dwr *DynamoDB) DecrementRepoStars(repo string) error {
 	return dwr.setProtoRepoMeta(repo, repoMeta)
 }
 
+func (dwr *DynamoDB) SetRepoProxyCache(repo string, upstream string) error {
+	repoMeta, err := dwr.getProtoRepoMeta(context.Background(), repo)
+	if err != nil {
+		return err
+	}
+
+	if repoMeta.IsProxyCache && repoMeta.Upstream == upstream {
+		return nil
+	}
+
+	repoMeta.IsProxyCache = true
+	repoMeta.Upstream = upstream
+
+	return dwr.setProtoRepoMeta(repo, repoMeta)
+}
+
 func (dwr *DynamoDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMeta) error {
 	protoRepoMeta := mConvert.GetProtoRepoMeta(repoMeta)
 
@@ -1191,8 +1250,8 @@ func (dwr *DynamoDB) UpdateSignaturesValidity(ctx context.Context, repo string,
 			layersInfo := []*proto_go.LayersInfo{}
 
 			for _, layerInfo := range sigInfo.LayersInfo {
-				author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
-					layerInfo.SignatureKey, manifestDigest, mConvert.GetImageMeta(protoImageMeta), repo)
+				author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType,
+					mConvert.GetLayerInfo(layerInfo), manifestDigest, mConvert.GetImageMeta(protoImageMeta), repo)
 
 				if isTrusted {
 					layerInfo.Signer = author
@@ -1308,6 +1367,245 @@ func (dwr *DynamoDB) AddManifestSignature(repo string, signedManifestDigest godi
 	return dwr.setProtoRepoMeta(protoRepoMeta.Name, protoRepoMeta)
 }
 
+func (dwr *DynamoDB) AddManifestSbom(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+	packages []mTypes.SbomPackage,
+) error {
+	if dwr.SbomPackagesTablename == "" {
+		return zerr.ErrSbomPackagesTableNotSet
+	}
+
+	ctx := context.Background()
+
+	protoRepoMeta, err := dwr.getProtoRepoMeta(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	packagesBlob, err := proto.Marshal(mConvert.GetProtoSbomPackages(packages))
+	if err != nil {
+		return err
+	}
+
+	oldPackages, err := dwr.GetSbomPackages(godigest.Digest(sbom.SbomManifestDigest))
+	if err != nil && !errors.Is(err, zerr.ErrSbomPackagesNotFound) {
+		return err
+	}
+
+	// the packages of large sboms don't fit in a single item
+	err = dwr.setChunkedBlob(ctx, dwr.SbomPackagesTablename, sbom.SbomManifestDigest, packagesBlob)
+	if err != nil {
+		return err
+	}
+
+	err = dwr.indexSbomPackages(ctx, sbom.SbomManifestDigest, oldPackages, packages)
+	if err != nil {
+		return err
+	}
+
+	common.AddProtoSbom(protoRepoMeta, subjectDigest.String(), mConvert.GetProtoSbomInfo(sbom))
+
+	return dwr.setProtoRepoMeta(protoRepoMeta.Name, protoRepoMeta)
+}
+
+func (dwr *DynamoDB) GetSbomPackages(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error) {
+	if dwr.SbomPackagesTablename == "" {
+		return nil, zerr.ErrSbomPackagesTableNotSet
+	}
+
+	packagesBlob, found, err := dwr.getChunkedBlob(context.Background(), dwr.SbomPackagesTablename,
+		sbomDigest.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, zerr.ErrSbomPackagesNotFound
+	}
+
+	protoPackages := &proto_go.SbomPackages{}
+
+	err = proto.Unmarshal(packagesBlob, protoPackages)
+	if err != nil {
+		return nil, err
+	}
+
+	return mConvert.GetSbomPackages(protoPackages), nil
+}
+
+// sbomIndexKey is the key of the item holding the set of sbom digests listing a package, the index is kept in
+// the sbom packages table next to the packages which are keyed by sbom digest.
+func sbomIndexKey(name string) string {
+	return "package:" + name
+}
+
+// indexSbomPackages updates the package index with the packages listed by an sbom, removing the sbom from the
+// entries of the packages it no longer lists.
+func (dwr *DynamoDB) indexSbomPackages(ctx context.Context, sbomDigest string,
+	oldPackages, packages []mTypes.SbomPackage,
+) error {
+	keys := map[string]bool{}
+
+	for _, pkg := range packages {
+		for _, key := range common.SbomPackageIndexKeys(pkg) {
+			keys[key] = true
+		}
+	}
+
+	updateSet := func(key, operation string) error {
+		_, err := dwr.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			ExpressionAttributeNames: map[string]string{
+				"#SD": "SbomDigests",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":SbomDigest": &types.AttributeValueMemberSS{Value: []string{sbomDigest}},
+			},
+			Key: map[string]types.AttributeValue{
+				"TableKey": &types.AttributeValueMemberS{Value: sbomIndexKey(key)},
+			},
+			TableName:        aws.String(dwr.SbomPackagesTablename),
+			UpdateExpression: aws.String(operation + " #SD :SbomDigest"),
+		})
+
+		return err
+	}
+
+	for _, pkg := range oldPackages {
+		for _, key := range common.SbomPackageIndexKeys(pkg) {
+			if keys[key] {
+				continue
+			}
+
+			if err := updateSet(key, "DELETE"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for key := range keys {
+		if err := updateSet(key, "ADD"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (dwr *DynamoDB) GetSbomsWithPackage(name string) ([]godigest.Digest, error) {
+	if dwr.SbomPackagesTablename == "" {
+		return nil, zerr.ErrSbomPackagesTableNotSet
+	}
+
+	resp, err := dwr.Client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.SbomPackagesTablename),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: sbomIndexKey(name)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sbomDigests := []godigest.Digest{}
+
+	if resp.Item == nil || resp.Item["SbomDigests"] == nil {
+		return sbomDigests, nil
+	}
+
+	members := []string{}
+
+	err = attributevalue.Unmarshal(resp.Item["SbomDigests"], &members)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		sbomDigests = append(sbomDigests, godigest.Digest(member))
+	}
+
+	return sbomDigests, nil
+}
+
+func (dwr *DynamoDB) SetCVEScanResult(digest godigest.Digest, result mTypes.CVEScanResult) error {
+	if dwr.CVEScanResultsTablename == "" {
+		return zerr.ErrCVEScanResultsTableNotSet
+	}
+
+	resultBlob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// the results of images with many vulnerabilities don't fit in a single item
+	return dwr.setChunkedBlob(context.Background(), dwr.CVEScanResultsTablename, digest.String(), resultBlob)
+}
+
+func (dwr *DynamoDB) GetCVEScanResult(digest godigest.Digest) (mTypes.CVEScanResult, error) {
+	var result mTypes.CVEScanResult
+
+	if dwr.CVEScanResultsTablename == "" {
+		return result, zerr.ErrCVEScanResultsTableNotSet
+	}
+
+	resultBlob, found, err := dwr.getChunkedBlob(context.Background(), dwr.CVEScanResultsTablename,
+		digest.String())
+	if err != nil {
+		return result, err
+	}
+
+	if !found {
+		return result, zerr.ErrCVEScanResultNotFound
+	}
+
+	err = json.Unmarshal(resultBlob, &result)
+
+	return result, err
+}
+
+func (dwr *DynamoDB) DeleteCVEScanResult(digest godigest.Digest) error {
+	if dwr.CVEScanResultsTablename == "" {
+		return zerr.ErrCVEScanResultsTableNotSet
+	}
+
+	return dwr.deleteChunkedBlob(context.Background(), dwr.CVEScanResultsTablename, digest.String())
+}
+
+func (dwr *DynamoDB) DeleteCVEScanResults(isStale func(result mTypes.CVEScanResult) bool) error {
+	if dwr.CVEScanResultsTablename == "" {
+		return zerr.ErrCVEScanResultsTableNotSet
+	}
+
+	ctx := context.Background()
+
+	digests, err := dwr.getChunkedBlobKeys(ctx, dwr.CVEScanResultsTablename)
+	if err != nil {
+		return err
+	}
+
+	for _, digest := range digests {
+		resultBlob, found, err := dwr.getChunkedBlob(ctx, dwr.CVEScanResultsTablename, digest)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			continue
+		}
+
+		var result mTypes.CVEScanResult
+
+		// results which can't be read are stale too
+		if err := json.Unmarshal(resultBlob, &result); err == nil && !isStale(result) {
+			continue
+		}
+
+		if err := dwr.deleteChunkedBlob(ctx, dwr.CVEScanResultsTablename, digest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (dwr *DynamoDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
 	sigMeta mTypes.SignatureMetadata,
 ) error {
@@ -1457,6 +1755,8 @@ func (dwr *DynamoDB) RemoveRepoReference(repo, reference string, manifestDigest
 		delete(protoRepoMeta.Statistics, manifestDigest.String())
 		delete(protoRepoMeta.Signatures, manifestDigest.String())
 		delete(protoRepoMeta.Referrers, manifestDigest.String())
+		delete(protoRepoMeta.Sboms, manifestDigest.String())
+		common.RemoveProtoSbom(protoRepoMeta, manifestDigest.String())
 	}
 
 	repoBlobsInfo, err := dwr.getProtoRepoBlobs(ctx, repo)
@@ -1897,6 +2197,284 @@ func (dwr DynamoDB) GetUserAPIKeyInfo(hashedKey string) (string, error) {
 	return userid, nil
 }
 
+func (dwr *DynamoDB) AddRobotAccount(robot mTypes.RobotAccount) error {
+	return dwr.putRobotAccount(robot, "attribute_not_exists(TableKey)", zerr.ErrRobotAccountAlreadyExists)
+}
+
+func (dwr *DynamoDB) UpdateRobotAccount(robot mTypes.RobotAccount) error {
+	return dwr.putRobotAccount(robot, "attribute_exists(TableKey)", zerr.ErrRobotAccountNotFound)
+}
+
+// putRobotAccount writes the robot account if the condition holds, otherwise conditionErr is returned.
+func (dwr *DynamoDB) putRobotAccount(robot mTypes.RobotAccount, condition string, conditionErr error) error {
+	if dwr.RobotAccountsTablename == "" {
+		return zerr.ErrRobotAccountsTableNotSet
+	}
+
+	robotAttributeValue, err := attributevalue.Marshal(robot)
+	if err != nil {
+		return err
+	}
+
+	_, err = dwr.Client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(dwr.RobotAccountsTablename),
+		Item: map[string]types.AttributeValue{
+			"TableKey":     &types.AttributeValueMemberS{Value: robot.Name},
+			"RobotAccount": robotAttributeValue,
+		},
+		ConditionExpression: aws.String(condition),
+	})
+
+	if conditionFailed := new(types.ConditionalCheckFailedException); errors.As(err, &conditionFailed) {
+		return conditionErr
+	}
+
+	return err
+}
+
+func (dwr *DynamoDB) GetRobotAccount(name string) (mTypes.RobotAccount, error) {
+	var robot mTypes.RobotAccount
+
+	if dwr.RobotAccountsTablename == "" {
+		return robot, zerr.ErrRobotAccountsTableNotSet
+	}
+
+	resp, err := dwr.Client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.RobotAccountsTablename),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: name},
+		},
+	})
+	if err != nil {
+		return robot, err
+	}
+
+	if resp.Item == nil {
+		return robot, zerr.ErrRobotAccountNotFound
+	}
+
+	err = attributevalue.Unmarshal(resp.Item["RobotAccount"], &robot)
+
+	return robot, err
+}
+
+func (dwr *DynamoDB) GetRobotAccounts() ([]mTypes.RobotAccount, error) {
+	robots := []mTypes.RobotAccount{}
+
+	if dwr.RobotAccountsTablename == "" {
+		return nil, zerr.ErrRobotAccountsTableNotSet
+	}
+
+	ctx := context.Background()
+
+	robotAttributeIterator := NewBaseDynamoAttributesIterator(
+		dwr.Client, dwr.RobotAccountsTablename, "RobotAccount", 0, dwr.Log,
+	)
+
+	robotAttribute, err := robotAttributeIterator.First(ctx)
+
+	for ; robotAttribute != nil; robotAttribute, err = robotAttributeIterator.Next(ctx) {
+		if err != nil {
+			return nil, err
+		}
+
+		var robot mTypes.RobotAccount
+
+		if err := attributevalue.Unmarshal(robotAttribute, &robot); err != nil {
+			return nil, err
+		}
+
+		robots = append(robots, robot)
+	}
+
+	sort.Slice(robots, func(i, j int) bool {
+		return robots[i].Name < robots[j].Name
+	})
+
+	return robots, nil
+}
+
+func (dwr *DynamoDB) DeleteRobotAccount(name string) error {
+	if dwr.RobotAccountsTablename == "" {
+		return zerr.ErrRobotAccountsTableNotSet
+	}
+
+	_, err := dwr.Client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(dwr.RobotAccountsTablename),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: name},
+		},
+	})
+
+	return err
+}
+
+// the item keyed accessControlLatestKey in the access control table holds the latest version of the policies,
+// so it can be read without scanning the history.
+const accessControlLatestKey = "latest"
+
+func (dwr *DynamoDB) AddAccessControlPolicies(policies mTypes.AccessControlPolicies) error {
+	if dwr.AccessControlTablename == "" {
+		return zerr.ErrAccessControlPoliciesTableNotSet
+	}
+
+	ctx := context.Background()
+
+	latestVersion, err := dwr.getLatestAccessControlPoliciesVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if policies.Version != latestVersion+1 {
+		return zerr.ErrAccessControlPoliciesConflict
+	}
+
+	policiesAttributeValue, err := attributevalue.Marshal(policies)
+	if err != nil {
+		return err
+	}
+
+	// the conditions guard against another instance storing a version concurrently
+	_, err = dwr.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String(dwr.AccessControlTablename),
+					Item: map[string]types.AttributeValue{
+						"TableKey":              &types.AttributeValueMemberS{Value: strconv.Itoa(policies.Version)},
+						"AccessControlPolicies": policiesAttributeValue,
+					},
+					ConditionExpression: aws.String("attribute_not_exists(TableKey)"),
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: aws.String(dwr.AccessControlTablename),
+					Item: map[string]types.AttributeValue{
+						"TableKey": &types.AttributeValueMemberS{Value: accessControlLatestKey},
+						"Version":  &types.AttributeValueMemberN{Value: strconv.Itoa(policies.Version)},
+					},
+					ConditionExpression: aws.String("attribute_not_exists(TableKey) OR Version = :LatestVersion"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":LatestVersion": &types.AttributeValueMemberN{Value: strconv.Itoa(latestVersion)},
+					},
+				},
+			},
+		},
+	})
+
+	if canceled := new(types.TransactionCanceledException); errors.As(err, &canceled) {
+		return zerr.ErrAccessControlPoliciesConflict
+	}
+
+	return err
+}
+
+func (dwr *DynamoDB) getLatestAccessControlPoliciesVersion(ctx context.Context) (int, error) {
+	resp, err := dwr.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.AccessControlTablename),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: accessControlLatestKey},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	latestVersion := 0
+
+	if resp.Item != nil {
+		err = attributevalue.Unmarshal(resp.Item["Version"], &latestVersion)
+	}
+
+	return latestVersion, err
+}
+
+func (dwr *DynamoDB) GetAccessControlPolicies(version int) (mTypes.AccessControlPolicies, error) {
+	var policies mTypes.AccessControlPolicies
+
+	if dwr.AccessControlTablename == "" {
+		return policies, zerr.ErrAccessControlPoliciesTableNotSet
+	}
+
+	ctx := context.Background()
+
+	if version == 0 {
+		latestVersion, err := dwr.getLatestAccessControlPoliciesVersion(ctx)
+		if err != nil {
+			return policies, err
+		}
+
+		if latestVersion == 0 {
+			return policies, zerr.ErrAccessControlPoliciesNotFound
+		}
+
+		version = latestVersion
+	}
+
+	resp, err := dwr.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.AccessControlTablename),
+		Key: map[string]types.AttributeValue{
+			"TableKey": &types.AttributeValueMemberS{Value: strconv.Itoa(version)},
+		},
+	})
+	if err != nil {
+		return policies, err
+	}
+
+	if resp.Item == nil {
+		return policies, zerr.ErrAccessControlPoliciesNotFound
+	}
+
+	err = attributevalue.Unmarshal(resp.Item["AccessControlPolicies"], &policies)
+
+	return policies, err
+}
+
+func (dwr *DynamoDB) GetAccessControlPoliciesHistory() ([]mTypes.AccessControlPolicies, error) {
+	history := []mTypes.AccessControlPolicies{}
+
+	if dwr.AccessControlTablename == "" {
+		return nil, zerr.ErrAccessControlPoliciesTableNotSet
+	}
+
+	ctx := context.Background()
+
+	// the attributes iterator stops at the latest version item, which has no policies
+	paginator := dynamodb.NewScanPaginator(dwr.Client, &dynamodb.ScanInput{
+		TableName: aws.String(dwr.AccessControlTablename),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			policiesAttribute, ok := item["AccessControlPolicies"]
+			if !ok {
+				continue
+			}
+
+			var policies mTypes.AccessControlPolicies
+
+			if err := attributevalue.Unmarshal(policiesAttribute, &policies); err != nil {
+				return nil, err
+			}
+
+			history = append(history, policies)
+		}
+	}
+
+	// the scan doesn't return the items in order
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Version < history[j].Version
+	})
+
+	return history, nil
+}
+
 func (dwr DynamoDB) GetUserData(ctx context.Context) (mTypes.UserData, error) {
 	var userData mTypes.UserData
 
@@ -2119,6 +2697,34 @@ func (dwr *DynamoDB) ResetDB() error {
 		return err
 	}
 
+	if dwr.CVEScanResultsTablename != "" {
+		err = dwr.ResetTable(dwr.CVEScanResultsTablename)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dwr.SbomPackagesTablename != "" {
+		err = dwr.ResetTable(dwr.SbomPackagesTablename)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dwr.RobotAccountsTablename != "" {
+		err = dwr.ResetTable(dwr.RobotAccountsTablename)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dwr.AccessControlTablename != "" {
+		err = dwr.ResetTable(dwr.AccessControlTablename)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -10,7 +10,8 @@ import (
 
 type DBDriverParameters struct {
 	Endpoint, Region, RepoMetaTablename, RepoBlobsInfoTablename, ImageMetaTablename,
-	UserDataTablename, APIKeyTablename, VersionTablename string
+	UserDataTablename, APIKeyTablename, VersionTablename, CVEScanResultsTablename, SbomPackagesTablename,
+	RobotAccountsTablename, AccessControlTablename string
 }
 
 func GetDynamoClient(params DBDriverParameters) (*dynamodb.Client, error) {
@@ -8,6 +8,7 @@ import (
 
 	zcommon "zotregistry.dev/zot/pkg/common"
 	"zotregistry.dev/zot/pkg/compat"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/storage"
@@ -89,6 +90,10 @@ func OnDeleteManifest(repo, reference, mediaType string, digest godigest.Digest,
 			}
 
 			manageRepoMetaSuccessfully = false
+		} else if err := metaDB.DeleteCVEScanResult(digest); err != nil {
+			// the result is only used as a cache, the image is scanned again if it is pushed back
+			log.Warn().Err(err).Str("digest", digest.String()).Str("component", "metadb").
+				Msg("failed to delete cve scan result")
 		}
 	}
 
@@ -102,9 +107,10 @@ func OnDeleteManifest(repo, reference, mediaType string, digest godigest.Digest,
 	return nil
 }
 
-// OnGetManifest is called when a manifest is downloaded. It increments the download couter on that manifest.
-func OnGetManifest(name, reference, mediaType string, body []byte,
-	storeController storage.StoreController, metaDB mTypes.MetaDB, log log.Logger,
+// OnGetManifest is called when a manifest is downloaded. It increments the download couter on that manifest
+// and records an image pulled event, metaDB and recorder are optional.
+func OnGetManifest(name, reference, mediaType string, digest godigest.Digest, body []byte,
+	storeController storage.StoreController, metaDB mTypes.MetaDB, recorder events.Recorder, log log.Logger,
 ) error {
 	// check if image is a signature
 	isSignature, _, _, err := storage.CheckIsImageSignature(name, body, reference)
@@ -123,12 +129,18 @@ func OnGetManifest(name, reference, mediaType string, body []byte,
 		return nil
 	}
 
-	err = metaDB.UpdateStatsOnDownload(name, reference)
-	if err != nil {
-		log.Error().Err(err).Str("repository", name).Str("reference", reference).
-			Msg("failed to update stats on download image")
+	if metaDB != nil {
+		err = metaDB.UpdateStatsOnDownload(name, reference)
+		if err != nil {
+			log.Error().Err(err).Str("repository", name).Str("reference", reference).
+				Msg("failed to update stats on download image")
 
-		return err
+			return err
+		}
+	}
+
+	if recorder != nil {
+		recorder.ImagePulled(name, reference, digest.String(), mediaType)
 	}
 
 	return nil
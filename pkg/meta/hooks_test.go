@@ -5,13 +5,16 @@ import (
 	"errors"
 	"testing"
 
+	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	"zotregistry.dev/zot/pkg/log"
 	"zotregistry.dev/zot/pkg/meta"
 	"zotregistry.dev/zot/pkg/meta/boltdb"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/storage"
 	"zotregistry.dev/zot/pkg/storage/local"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
@@ -53,6 +56,107 @@ func TestOnUpdateManifest(t *testing.T) {
 	})
 }
 
+func TestOnDeleteManifest(t *testing.T) {
+	Convey("On DeleteManifest the cve scan result is deleted", t, func() {
+		rootDir := t.TempDir()
+		storeController := storage.StoreController{}
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+		storeController.DefaultStore = local.NewImageStore(rootDir, true, true, log, metrics, nil, nil, nil, nil)
+
+		boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		image := CreateDefaultImage()
+
+		err = WriteImageToFileSystem(image, "repo", "tag1", storeController)
+		So(err, ShouldBeNil)
+
+		err = meta.OnUpdateManifest(context.Background(), "repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+			image.ManifestDescriptor.Data, storeController, metaDB, log)
+		So(err, ShouldBeNil)
+
+		err = metaDB.SetCVEScanResult(image.Digest(), mTypes.CVEScanResult{DBVersion: "2"})
+		So(err, ShouldBeNil)
+
+		err = meta.OnDeleteManifest("repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+			image.ManifestDescriptor.Data, storeController, metaDB, log)
+		So(err, ShouldBeNil)
+
+		_, err = metaDB.GetCVEScanResult(image.Digest())
+		So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+
+		Convey("Failing to delete it doesn't fail the deletion", func() {
+			err := meta.OnDeleteManifest("repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+				image.ManifestDescriptor.Data, storeController, mocks.MetaDBMock{
+					DeleteCVEScanResultFn: func(digest godigest.Digest) error {
+						return ErrTestError
+					},
+				}, log)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestOnGetManifest(t *testing.T) {
+	Convey("On GetManifest the image pulled event is recorded", t, func() {
+		storeController := storage.StoreController{DefaultStore: &mocks.MockedImageStore{}}
+		log := log.NewLogger("debug", "")
+		image := CreateDefaultImage()
+		pulled := []string{}
+
+		recorder := mocks.EventRecorderMock{
+			ImagePulledFn: func(name, reference, digest, mediaType string) {
+				pulled = append(pulled, name+":"+reference+"@"+digest)
+			},
+		}
+
+		downloads := 0
+
+		metaDB := mocks.MetaDBMock{
+			UpdateStatsOnDownloadFn: func(repo, reference string) error {
+				downloads++
+
+				return nil
+			},
+		}
+
+		err := meta.OnGetManifest("repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+			image.ManifestDescriptor.Data, storeController, metaDB, recorder, log)
+		So(err, ShouldBeNil)
+		So(downloads, ShouldEqual, 1)
+		So(pulled, ShouldResemble, []string{"repo:tag1@" + image.DigestStr()})
+
+		Convey("Without metaDB", func() {
+			err := meta.OnGetManifest("repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+				image.ManifestDescriptor.Data, storeController, nil, recorder, log)
+			So(err, ShouldBeNil)
+			So(pulled, ShouldHaveLength, 2)
+		})
+
+		Convey("Not recorded if the stats can't be updated", func() {
+			metaDB.UpdateStatsOnDownloadFn = func(repo, reference string) error {
+				return ErrTestError
+			}
+
+			err := meta.OnGetManifest("repo", "tag1", ispec.MediaTypeImageManifest, image.Digest(),
+				image.ManifestDescriptor.Data, storeController, metaDB, recorder, log)
+			So(err, ShouldNotBeNil)
+			So(pulled, ShouldHaveLength, 1)
+		})
+
+		Convey("Not recorded for referrers tags", func() {
+			err := meta.OnGetManifest("repo", "sha256-123", ispec.MediaTypeImageManifest, image.Digest(),
+				image.ManifestDescriptor.Data, storeController, metaDB, recorder, log)
+			So(err, ShouldBeNil)
+			So(pulled, ShouldHaveLength, 1)
+		})
+	})
+}
+
 func TestUpdateErrors(t *testing.T) {
 	Convey("Update operations", t, func() {
 		imageStore := mocks.MockedImageStore{}
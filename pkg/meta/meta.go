@@ -87,19 +87,39 @@ func getDynamoParams(cacheDriverConfig map[string]interface{}, log log.Logger) m
 	userDataTablename, ok := toStringIfOk(cacheDriverConfig, "userdatatablename", "", log)
 	allParametersOk = allParametersOk && ok
 
+	cveScanResultsTablename, ok := toStringIfOk(cacheDriverConfig, "cvescanresultstablename",
+		"ZotCVEScanResultsTable", log)
+	allParametersOk = allParametersOk && ok
+
+	sbomPackagesTablename, ok := toStringIfOk(cacheDriverConfig, "sbompackagestablename",
+		"ZotSbomPackagesTable", log)
+	allParametersOk = allParametersOk && ok
+
+	robotAccountsTablename, ok := toStringIfOk(cacheDriverConfig, "robotaccountstablename",
+		"ZotRobotAccountsTable", log)
+	allParametersOk = allParametersOk && ok
+
+	accessControlTablename, ok := toStringIfOk(cacheDriverConfig, "accesscontroltablename",
+		"ZotAccessControlTable", log)
+	allParametersOk = allParametersOk && ok
+
 	if !allParametersOk {
 		log.Panic().Msg("dynamo parameters are not specified correctly, can't proceed")
 	}
 
 	return mdynamodb.DBDriverParameters{
-		Endpoint:               endpoint,
-		Region:                 region,
-		RepoMetaTablename:      repoMetaTablename,
-		RepoBlobsInfoTablename: repoBlobsInfoTablename,
-		ImageMetaTablename:     imageMetaTablename,
-		UserDataTablename:      userDataTablename,
-		APIKeyTablename:        apiKeyTablename,
-		VersionTablename:       versionTablename,
+		Endpoint:                endpoint,
+		Region:                  region,
+		RepoMetaTablename:       repoMetaTablename,
+		RepoBlobsInfoTablename:  repoBlobsInfoTablename,
+		ImageMetaTablename:      imageMetaTablename,
+		UserDataTablename:       userDataTablename,
+		APIKeyTablename:         apiKeyTablename,
+		VersionTablename:        versionTablename,
+		CVEScanResultsTablename: cveScanResultsTablename,
+		SbomPackagesTablename:   sbomPackagesTablename,
+		RobotAccountsTablename:  robotAccountsTablename,
+		AccessControlTablename:  accessControlTablename,
 	}
 }
 
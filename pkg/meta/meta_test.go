@@ -6,9 +6,11 @@ package meta_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -118,17 +120,25 @@ func TestDynamoDBWrapper(t *testing.T) {
 	apiKeyTablename := "ApiKeyTable" + uuid.String()
 	imageMetaTablename := "ImageMeta" + uuid.String()
 	repoBlobsTablename := "RepoBlobs" + uuid.String()
+	cveScanResultsTablename := "CVEScanResults" + uuid.String()
+	sbomPackagesTablename := "SbomPackages" + uuid.String()
+	robotAccountsTablename := "RobotAccounts" + uuid.String()
+	accessControlTablename := "AccessControl" + uuid.String()
 
 	Convey("DynamoDB Wrapper", t, func() {
 		dynamoDBDriverParams := mdynamodb.DBDriverParameters{
-			Endpoint:               os.Getenv("DYNAMODBMOCK_ENDPOINT"),
-			RepoMetaTablename:      repoMetaTablename,
-			RepoBlobsInfoTablename: repoBlobsTablename,
-			ImageMetaTablename:     imageMetaTablename,
-			VersionTablename:       versionTablename,
-			UserDataTablename:      userDataTablename,
-			APIKeyTablename:        apiKeyTablename,
-			Region:                 "us-east-2",
+			Endpoint:                os.Getenv("DYNAMODBMOCK_ENDPOINT"),
+			RepoMetaTablename:       repoMetaTablename,
+			RepoBlobsInfoTablename:  repoBlobsTablename,
+			ImageMetaTablename:      imageMetaTablename,
+			VersionTablename:        versionTablename,
+			UserDataTablename:       userDataTablename,
+			APIKeyTablename:         apiKeyTablename,
+			CVEScanResultsTablename: cveScanResultsTablename,
+			SbomPackagesTablename:   sbomPackagesTablename,
+			RobotAccountsTablename:  robotAccountsTablename,
+			AccessControlTablename:  accessControlTablename,
+			Region:                  "us-east-2",
 		}
 
 		dynamoClient, err := mdynamodb.GetDynamoClient(dynamoDBDriverParams)
@@ -1027,6 +1037,41 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(err, ShouldNotBeNil)
 		})
 
+		Convey("Test SetRepoProxyCache", func() {
+			var (
+				repo1     = "cache/docker.io/library/alpine"
+				tag1      = "0.0.1"
+				imageMeta = CreateDefaultImage().AsImageMeta()
+			)
+
+			err := metaDB.SetRepoProxyCache("missing-repo", "docker.io")
+			So(err, ShouldNotBeNil)
+
+			err = metaDB.SetRepoReference(ctx, repo1, tag1, imageMeta)
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(ctx, repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.IsProxyCache, ShouldBeFalse)
+
+			err = metaDB.SetRepoProxyCache(repo1, "docker.io")
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(ctx, repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.IsProxyCache, ShouldBeTrue)
+			So(repoMeta.Upstream, ShouldEqual, "docker.io")
+
+			// the mark survives resetting the layout specific data
+			err = metaDB.ResetRepoReferences(repo1)
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(ctx, repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.IsProxyCache, ShouldBeTrue)
+			So(repoMeta.Upstream, ShouldEqual, "docker.io")
+		})
+
 		Convey("Test Repo Stars", func() {
 			var (
 				repo1 = "repo1"
@@ -1727,6 +1772,374 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(err, ShouldNotBeNil)
 		})
 
+		Convey("Test AddManifestSbom", func() {
+			var (
+				repo1     = "repo1"
+				tag1      = "0.0.1"
+				image1    = CreateRandomImage()
+				sbomImage = CreateRandomImageWith().Subject(image1.DescriptorRef()).Build()
+			)
+
+			err := metaDB.SetRepoReference(ctx, repo1, tag1, image1.AsImageMeta())
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetRepoReference(ctx, repo1, sbomImage.DigestStr(), sbomImage.AsImageMeta())
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetSbomPackages(sbomImage.Digest())
+			So(err, ShouldEqual, zerr.ErrSbomPackagesNotFound)
+
+			err = metaDB.AddManifestSbom(repo1, image1.Digest(), mTypes.SbomInfo{
+				SbomManifestDigest: sbomImage.DigestStr(),
+				Format:             "spdx",
+				PackageCount:       1,
+			}, []mTypes.SbomPackage{{Name: "openssl", Version: "3.0.2"}})
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(ctx, repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Sboms[image1.DigestStr()], ShouldHaveLength, 1)
+			So(repoMeta.Sboms[image1.DigestStr()][0].PackageCount, ShouldEqual, 1)
+
+			packages, err := metaDB.GetSbomPackages(sbomImage.Digest())
+			So(err, ShouldBeNil)
+			So(packages, ShouldResemble, []mTypes.SbomPackage{{Name: "openssl", Version: "3.0.2"}})
+
+			sbomDigests, err := metaDB.GetSbomsWithPackage("openssl")
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldResemble, []godigest.Digest{sbomImage.Digest()})
+
+			sbomDigests, err = metaDB.GetSbomsWithPackage("zlib")
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldBeEmpty)
+
+			// the packages of the same sbom manifest are replaced
+			err = metaDB.AddManifestSbom(repo1, image1.Digest(), mTypes.SbomInfo{
+				SbomManifestDigest: sbomImage.DigestStr(),
+				Format:             "spdx",
+				PackageCount:       2,
+			}, []mTypes.SbomPackage{
+				{Name: "openssl", Version: "3.0.3", PURL: "pkg:generic/openssl@3.0.3"},
+				{Name: "zlib", Version: "1.3"},
+			})
+			So(err, ShouldBeNil)
+
+			fullImageMeta, err := metaDB.GetFullImageMeta(ctx, repo1, tag1)
+			So(err, ShouldBeNil)
+			So(fullImageMeta.Sboms, ShouldHaveLength, 1)
+			So(fullImageMeta.Sboms[0].Format, ShouldEqual, "spdx")
+			So(fullImageMeta.Sboms[0].PackageCount, ShouldEqual, 2)
+
+			packages, err = metaDB.GetSbomPackages(sbomImage.Digest())
+			So(err, ShouldBeNil)
+			So(packages, ShouldResemble, []mTypes.SbomPackage{
+				{Name: "openssl", Version: "3.0.3", PURL: "pkg:generic/openssl@3.0.3"},
+				{Name: "zlib", Version: "1.3"},
+			})
+
+			// the packages are indexed by name and by purl, with and without the version
+			for _, name := range []string{"openssl", "pkg:generic/openssl", "pkg:generic/openssl@3.0.3", "zlib"} {
+				sbomDigests, err = metaDB.GetSbomsWithPackage(name)
+				So(err, ShouldBeNil)
+				So(sbomDigests, ShouldResemble, []godigest.Digest{sbomImage.Digest()})
+			}
+
+			// the packages of large sboms don't have to fit in a single record
+			largeSbomPackages := make([]mTypes.SbomPackage, 0, 20000)
+			for i := range 20000 {
+				largeSbomPackages = append(largeSbomPackages, mTypes.SbomPackage{
+					Name:    fmt.Sprintf("package-%d", i),
+					Version: "1.0.0",
+					PURL:    fmt.Sprintf("pkg:generic/package-%d@1.0.0", i),
+				})
+			}
+
+			err = metaDB.AddManifestSbom(repo1, image1.Digest(), mTypes.SbomInfo{
+				SbomManifestDigest: sbomImage.DigestStr(),
+				Format:             "spdx",
+				PackageCount:       len(largeSbomPackages),
+			}, largeSbomPackages)
+			So(err, ShouldBeNil)
+
+			packages, err = metaDB.GetSbomPackages(sbomImage.Digest())
+			So(err, ShouldBeNil)
+			So(packages, ShouldResemble, largeSbomPackages)
+
+			sbomDigests, err = metaDB.GetSbomsWithPackage("pkg:generic/package-19999")
+			So(err, ShouldBeNil)
+			So(sbomDigests, ShouldResemble, []godigest.Digest{sbomImage.Digest()})
+
+			// the sbom is removed from the index entries of the packages it no longer lists
+			for _, name := range []string{"openssl", "pkg:generic/openssl", "zlib"} {
+				sbomDigests, err = metaDB.GetSbomsWithPackage(name)
+				So(err, ShouldBeNil)
+				So(sbomDigests, ShouldBeEmpty)
+			}
+
+			err = metaDB.RemoveRepoReference(repo1, sbomImage.DigestStr(), sbomImage.Digest())
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(ctx, repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Sboms[image1.DigestStr()], ShouldBeEmpty)
+		})
+
+		Convey("Test CVEScanResult", func() {
+			image := CreateRandomImage()
+			scannedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			dbUpdatedAt := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+
+			_, err := metaDB.GetCVEScanResult(image.Digest())
+			So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+
+			result := mTypes.CVEScanResult{
+				ScannedAt:   scannedAt,
+				DBVersion:   "2",
+				DBUpdatedAt: dbUpdatedAt,
+				CVEs: map[string]mTypes.CVE{
+					"CVE-2023-1255": {
+						ID:       "CVE-2023-1255",
+						Severity: "MEDIUM",
+						Title:    "openssl: Input buffer over-read in AES-XTS implementation on 64 bit ARM",
+						PackageList: []mTypes.CVEPackage{
+							{Name: "libcrypto3", InstalledVersion: "3.0.8-r3", FixedVersion: "3.0.8-r4"},
+						},
+					},
+				},
+			}
+
+			err = metaDB.SetCVEScanResult(image.Digest(), result)
+			So(err, ShouldBeNil)
+
+			storedResult, err := metaDB.GetCVEScanResult(image.Digest())
+			So(err, ShouldBeNil)
+			So(storedResult.ScannedAt.Equal(scannedAt), ShouldBeTrue)
+			So(storedResult.DBVersion, ShouldEqual, result.DBVersion)
+			So(storedResult.DBUpdatedAt.Equal(dbUpdatedAt), ShouldBeTrue)
+			So(storedResult.CVEs, ShouldResemble, result.CVEs)
+
+			// the result of a new scan replaces the previous one
+			err = metaDB.SetCVEScanResult(image.Digest(), mTypes.CVEScanResult{
+				ScannedAt:   scannedAt.Add(time.Hour),
+				DBVersion:   "2",
+				DBUpdatedAt: dbUpdatedAt.Add(24 * time.Hour),
+				CVEs:        map[string]mTypes.CVE{},
+			})
+			So(err, ShouldBeNil)
+
+			storedResult, err = metaDB.GetCVEScanResult(image.Digest())
+			So(err, ShouldBeNil)
+			So(storedResult.DBUpdatedAt.Equal(dbUpdatedAt.Add(24*time.Hour)), ShouldBeTrue)
+			So(storedResult.CVEs, ShouldBeEmpty)
+
+			err = metaDB.DeleteCVEScanResult(image.Digest())
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetCVEScanResult(image.Digest())
+			So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+
+			// deleting a result which isn't stored is not an error
+			err = metaDB.DeleteCVEScanResult(image.Digest())
+			So(err, ShouldBeNil)
+
+			Convey("Stale results are deleted", func() {
+				oldImage := CreateRandomImage()
+				newImage := CreateRandomImage()
+
+				err := metaDB.SetCVEScanResult(oldImage.Digest(), mTypes.CVEScanResult{
+					DBVersion:   "2",
+					DBUpdatedAt: dbUpdatedAt,
+				})
+				So(err, ShouldBeNil)
+
+				err = metaDB.SetCVEScanResult(newImage.Digest(), mTypes.CVEScanResult{
+					DBVersion:   "2",
+					DBUpdatedAt: dbUpdatedAt.Add(24 * time.Hour),
+				})
+				So(err, ShouldBeNil)
+
+				err = metaDB.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool {
+					return result.DBUpdatedAt.Before(dbUpdatedAt.Add(time.Hour))
+				})
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetCVEScanResult(oldImage.Digest())
+				So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+
+				_, err = metaDB.GetCVEScanResult(newImage.Digest())
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Results larger than a DB item", func() {
+				cves := map[string]mTypes.CVE{}
+
+				for i := range 2000 {
+					id := fmt.Sprintf("CVE-2024-%d", i)
+					cves[id] = mTypes.CVE{
+						ID:          id,
+						Severity:    "HIGH",
+						Description: strings.Repeat("a long description of the vulnerability ", 10),
+						PackageList: []mTypes.CVEPackage{{Name: "package", InstalledVersion: "1.0.0"}},
+					}
+				}
+
+				err := metaDB.SetCVEScanResult(image.Digest(), mTypes.CVEScanResult{DBVersion: "2", CVEs: cves})
+				So(err, ShouldBeNil)
+
+				storedResult, err := metaDB.GetCVEScanResult(image.Digest())
+				So(err, ShouldBeNil)
+				So(storedResult.CVEs, ShouldResemble, cves)
+
+				// a smaller result replaces all the chunks
+				err = metaDB.SetCVEScanResult(image.Digest(), result)
+				So(err, ShouldBeNil)
+
+				storedResult, err = metaDB.GetCVEScanResult(image.Digest())
+				So(err, ShouldBeNil)
+				So(storedResult.CVEs, ShouldResemble, result.CVEs)
+
+				err = metaDB.SetCVEScanResult(image.Digest(), mTypes.CVEScanResult{DBVersion: "2", CVEs: cves})
+				So(err, ShouldBeNil)
+
+				err = metaDB.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool {
+					return len(result.CVEs) > 0
+				})
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetCVEScanResult(image.Digest())
+				So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+			})
+		})
+
+		Convey("Test RobotAccounts", func() {
+			createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			robots, err := metaDB.GetRobotAccounts()
+			So(err, ShouldBeNil)
+			So(robots, ShouldBeEmpty)
+
+			_, err = metaDB.GetRobotAccount("ci")
+			So(errors.Is(err, zerr.ErrRobotAccountNotFound), ShouldBeTrue)
+
+			err = metaDB.UpdateRobotAccount(mTypes.RobotAccount{Name: "ci"})
+			So(errors.Is(err, zerr.ErrRobotAccountNotFound), ShouldBeTrue)
+
+			robot := mTypes.RobotAccount{
+				Name:            "ci",
+				Description:     "pushes the app images",
+				HashedSecret:    "hash1",
+				Repositories:    []string{"app/**"},
+				Actions:         []string{"create", "update"},
+				CreatedBy:       "admin",
+				CreatedAt:       createdAt,
+				SecretCreatedAt: createdAt,
+			}
+
+			err = metaDB.AddRobotAccount(robot)
+			So(err, ShouldBeNil)
+
+			err = metaDB.AddRobotAccount(mTypes.RobotAccount{Name: "ci"})
+			So(errors.Is(err, zerr.ErrRobotAccountAlreadyExists), ShouldBeTrue)
+
+			err = metaDB.AddRobotAccount(mTypes.RobotAccount{Name: "backup", Actions: []string{"read"}})
+			So(err, ShouldBeNil)
+
+			storedRobot, err := metaDB.GetRobotAccount("ci")
+			So(err, ShouldBeNil)
+			So(storedRobot.HashedSecret, ShouldEqual, "hash1")
+			So(storedRobot.Repositories, ShouldResemble, robot.Repositories)
+			So(storedRobot.Actions, ShouldResemble, robot.Actions)
+			So(storedRobot.CreatedAt.Equal(createdAt), ShouldBeTrue)
+			So(storedRobot.IsSecretExpired(), ShouldBeFalse)
+
+			// rotating the secret
+			robot.HashedSecret = "hash2"
+			robot.SecretExpirationDate = createdAt.Add(time.Hour)
+
+			err = metaDB.UpdateRobotAccount(robot)
+			So(err, ShouldBeNil)
+
+			storedRobot, err = metaDB.GetRobotAccount("ci")
+			So(err, ShouldBeNil)
+			So(storedRobot.HashedSecret, ShouldEqual, "hash2")
+			So(storedRobot.IsSecretExpired(), ShouldBeTrue)
+
+			robots, err = metaDB.GetRobotAccounts()
+			So(err, ShouldBeNil)
+			So(len(robots), ShouldEqual, 2)
+			So(robots[0].Name, ShouldEqual, "backup")
+			So(robots[1].Name, ShouldEqual, "ci")
+
+			err = metaDB.DeleteRobotAccount("ci")
+			So(err, ShouldBeNil)
+
+			err = metaDB.DeleteRobotAccount("ci")
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetRobotAccount("ci")
+			So(errors.Is(err, zerr.ErrRobotAccountNotFound), ShouldBeTrue)
+
+			robots, err = metaDB.GetRobotAccounts()
+			So(err, ShouldBeNil)
+			So(len(robots), ShouldEqual, 1)
+		})
+
+		Convey("Test AccessControlPolicies", func() {
+			createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			history, err := metaDB.GetAccessControlPoliciesHistory()
+			So(err, ShouldBeNil)
+			So(history, ShouldBeEmpty)
+
+			_, err = metaDB.GetAccessControlPolicies(0)
+			So(errors.Is(err, zerr.ErrAccessControlPoliciesNotFound), ShouldBeTrue)
+
+			// versions start at 1
+			err = metaDB.AddAccessControlPolicies(mTypes.AccessControlPolicies{Version: 2})
+			So(errors.Is(err, zerr.ErrAccessControlPoliciesConflict), ShouldBeTrue)
+
+			err = metaDB.AddAccessControlPolicies(mTypes.AccessControlPolicies{
+				Version:   1,
+				Policies:  []byte(`{"adminPolicy":{"users":["admin"]}}`),
+				CreatedBy: "admin",
+				CreatedAt: createdAt,
+				Comment:   "initial",
+			})
+			So(err, ShouldBeNil)
+
+			err = metaDB.AddAccessControlPolicies(mTypes.AccessControlPolicies{Version: 1})
+			So(errors.Is(err, zerr.ErrAccessControlPoliciesConflict), ShouldBeTrue)
+
+			err = metaDB.AddAccessControlPolicies(mTypes.AccessControlPolicies{
+				Version:   2,
+				Policies:  []byte(`{"adminPolicy":{"users":["admin","bob"]}}`),
+				CreatedBy: "bob",
+				CreatedAt: createdAt.Add(time.Hour),
+			})
+			So(err, ShouldBeNil)
+
+			latest, err := metaDB.GetAccessControlPolicies(0)
+			So(err, ShouldBeNil)
+			So(latest.Version, ShouldEqual, 2)
+			So(latest.CreatedBy, ShouldEqual, "bob")
+			So(string(latest.Policies), ShouldEqual, `{"adminPolicy":{"users":["admin","bob"]}}`)
+
+			first, err := metaDB.GetAccessControlPolicies(1)
+			So(err, ShouldBeNil)
+			So(first.Version, ShouldEqual, 1)
+			So(first.Comment, ShouldEqual, "initial")
+			So(first.CreatedAt.Equal(createdAt), ShouldBeTrue)
+
+			_, err = metaDB.GetAccessControlPolicies(3)
+			So(errors.Is(err, zerr.ErrAccessControlPoliciesNotFound), ShouldBeTrue)
+
+			history, err = metaDB.GetAccessControlPoliciesHistory()
+			So(err, ShouldBeNil)
+			So(len(history), ShouldEqual, 2)
+			So(history[0].Version, ShouldEqual, 1)
+			So(history[1].Version, ShouldEqual, 2)
+		})
+
 		Convey("Test SearchRepos", func() {
 			var (
 				repo1  = "repo1"
@@ -243,9 +243,12 @@ func getCosignSignatureLayersInfo(
 		}
 
 		layers = append(layers, mTypes.LayerInfo{
-			LayerDigest:  layer.Digest.String(),
-			LayerContent: layerContent,
-			SignatureKey: layerSigKey,
+			LayerDigest:      layer.Digest.String(),
+			LayerContent:     layerContent,
+			SignatureKey:     layerSigKey,
+			Certificate:      layer.Annotations[zcommon.CosignCertificateKey],
+			CertificateChain: layer.Annotations[zcommon.CosignChainKey],
+			Bundle:           layer.Annotations[zcommon.CosignBundleKey],
 		})
 	}
 
@@ -308,7 +311,13 @@ func getNotationSignatureLayersInfo(
 func SetImageMetaFromInput(ctx context.Context, repo, reference, mediaType string, digest godigest.Digest, blob []byte,
 	imageStore stypes.ImageStore, metaDB mTypes.MetaDB, log log.Logger,
 ) error {
-	var imageMeta mTypes.ImageMeta
+	var (
+		imageMeta mTypes.ImageMeta
+
+		// set for SBOMs and attestations, which are indexed under the manifest they describe
+		sbomManifest      *ispec.Manifest
+		sbomSubjectDigest godigest.Digest
+	)
 
 	if mediaType == ispec.MediaTypeImageManifest || compat.IsCompatibleManifestMediaType(mediaType) { //nolint:gocritic,lll // mixing checking mechanisms
 		manifestContent := ispec.Manifest{}
@@ -368,6 +377,11 @@ func SetImageMetaFromInput(ctx context.Context, repo, reference, mediaType strin
 		}
 
 		imageMeta = convert.GetImageManifestMeta(manifestContent, configContent, int64(len(blob)), digest)
+
+		if isSbom, subjectDigest := isSbom(reference, manifestContent); isSbom {
+			sbomManifest = &manifestContent
+			sbomSubjectDigest = subjectDigest
+		}
 	} else if mediaType == ispec.MediaTypeImageIndex || compat.IsCompatibleManifestListMediaType(mediaType) {
 		indexContent := ispec.Index{}
 
@@ -388,9 +402,37 @@ func SetImageMetaFromInput(ctx context.Context, repo, reference, mediaType strin
 		return err
 	}
 
+	if sbomManifest != nil {
+		setSbomMeta(repo, digest, sbomSubjectDigest, *sbomManifest, imageStore, metaDB, log)
+	}
+
 	return nil
 }
 
+// setSbomMeta indexes the packages listed by an SBOM, the SBOM manifest itself is already stored so a
+// failure only leaves it out of the package searches.
+func setSbomMeta(repo string, digest, subjectDigest godigest.Digest, manifestContent ispec.Manifest,
+	imageStore stypes.ImageStore, metaDB mTypes.MetaDB, log log.Logger,
+) {
+	sbomInfo, packages, found, err := GetSbomInfo(repo, digest, manifestContent, imageStore, log)
+	if err != nil {
+		log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+			Str("subject", subjectDigest.String()).Msg("failed to read sbom of image")
+
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	err = metaDB.AddManifestSbom(repo, subjectDigest, sbomInfo, packages)
+	if err != nil {
+		log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+			Str("subject", subjectDigest.String()).Msg("failed to set sbom meta for image")
+	}
+}
+
 func isSignature(reference string, manifestContent ispec.Manifest) (bool, string, godigest.Digest) {
 	manifestArtifactType := zcommon.GetManifestArtifactType(manifestContent)
 
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,6 +40,11 @@ const (
 	VersionBucket         = "Version"
 	UserAPIKeysBucket     = "UserAPIKeys"
 	LocksBucket           = "Locks"
+	CVEScanResultsBucket  = "CVEScanResults"
+	SbomPackagesBucket    = "SbomPackages"
+	SbomIndexBucket       = "SbomPackageIndex"
+	RobotAccountsBucket   = "RobotAccounts"
+	AccessControlBucket   = "AccessControlPolicies"
 )
 
 type RedisDB struct {
@@ -55,6 +62,11 @@ type RedisDB struct {
 	VersionKey         string
 	UserAPIKeysKey     string
 	LocksKey           string
+	CVEScanResultsKey  string
+	SbomPackagesKey    string
+	SbomIndexKey       string
+	RobotAccountsKey   string
+	AccessControlKey   string
 }
 
 type DBDriverParameters struct {
@@ -76,6 +88,11 @@ func New(client redis.UniversalClient, params DBDriverParameters, log log.Logger
 		VersionKey:         join(params.KeyPrefix, VersionBucket),
 		UserAPIKeysKey:     join(params.KeyPrefix, UserAPIKeysBucket),
 		LocksKey:           join(params.KeyPrefix, LocksBucket),
+		CVEScanResultsKey:  join(params.KeyPrefix, CVEScanResultsBucket),
+		SbomPackagesKey:    join(params.KeyPrefix, SbomPackagesBucket),
+		SbomIndexKey:       join(params.KeyPrefix, SbomIndexBucket),
+		RobotAccountsKey:   join(params.KeyPrefix, RobotAccountsBucket),
+		AccessControlKey:   join(params.KeyPrefix, AccessControlBucket),
 	}
 
 	if err := client.Ping(context.Background()).Err(); err != nil {
@@ -430,6 +447,221 @@ func (rc *RedisDB) GetUserAPIKeyInfo(hashedKey string) (string, error) {
 	return userid, err
 }
 
+// AddRobotAccount stores a new robot account, it fails if an account with the same name already exists.
+func (rc *RedisDB) AddRobotAccount(robot mTypes.RobotAccount) error {
+	ctx := context.Background()
+
+	robotBlob, err := json.Marshal(robot)
+	if err != nil {
+		return err
+	}
+
+	added, err := rc.Client.HSetNX(ctx, rc.RobotAccountsKey, robot.Name, robotBlob).Result()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("hsetnx", rc.RobotAccountsKey).Str("robot", robot.Name).
+			Msg("failed to put robot account record")
+
+		return fmt.Errorf("failed to put robot account record for %s: %w", robot.Name, err)
+	}
+
+	if !added {
+		return zerr.ErrRobotAccountAlreadyExists
+	}
+
+	return nil
+}
+
+// UpdateRobotAccount replaces an existing robot account.
+func (rc *RedisDB) UpdateRobotAccount(robot mTypes.RobotAccount) error {
+	ctx := context.Background()
+
+	robotBlob, err := json.Marshal(robot)
+	if err != nil {
+		return err
+	}
+
+	return rc.withRSLocks(ctx, []string{rc.getRobotAccountLockKey(robot.Name)}, func() error {
+		exists, err := rc.Client.HExists(ctx, rc.RobotAccountsKey, robot.Name).Result()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hexists", rc.RobotAccountsKey).Str("robot", robot.Name).
+				Msg("failed to check robot account record")
+
+			return fmt.Errorf("failed to check robot account record for %s: %w", robot.Name, err)
+		}
+
+		if !exists {
+			return zerr.ErrRobotAccountNotFound
+		}
+
+		if err := rc.Client.HSet(ctx, rc.RobotAccountsKey, robot.Name, robotBlob).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("hset", rc.RobotAccountsKey).Str("robot", robot.Name).
+				Msg("failed to put robot account record")
+
+			return fmt.Errorf("failed to put robot account record for %s: %w", robot.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// GetRobotAccount returns the robot account with the given name.
+func (rc *RedisDB) GetRobotAccount(name string) (mTypes.RobotAccount, error) {
+	var robot mTypes.RobotAccount
+
+	robotBlob, err := rc.Client.HGet(context.Background(), rc.RobotAccountsKey, name).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		rc.Log.Error().Err(err).Str("hget", rc.RobotAccountsKey).Str("robot", name).
+			Msg("failed to get robot account record")
+
+		return robot, fmt.Errorf("failed to get robot account record for %s: %w", name, err)
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return robot, zerr.ErrRobotAccountNotFound
+	}
+
+	err = json.Unmarshal(robotBlob, &robot)
+
+	return robot, err
+}
+
+// GetRobotAccounts returns all the robot accounts, sorted by name.
+func (rc *RedisDB) GetRobotAccounts() ([]mTypes.RobotAccount, error) {
+	robots := []mTypes.RobotAccount{}
+
+	robotBlobs, err := rc.Client.HGetAll(context.Background(), rc.RobotAccountsKey).Result()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("hgetall", rc.RobotAccountsKey).Msg("failed to get robot account records")
+
+		return nil, fmt.Errorf("failed to get robot account records: %w", err)
+	}
+
+	for _, robotBlob := range robotBlobs {
+		var robot mTypes.RobotAccount
+
+		if err := json.Unmarshal([]byte(robotBlob), &robot); err != nil {
+			return nil, err
+		}
+
+		robots = append(robots, robot)
+	}
+
+	sort.Slice(robots, func(i, j int) bool {
+		return robots[i].Name < robots[j].Name
+	})
+
+	return robots, nil
+}
+
+// DeleteRobotAccount removes a robot account.
+func (rc *RedisDB) DeleteRobotAccount(name string) error {
+	if err := rc.Client.HDel(context.Background(), rc.RobotAccountsKey, name).Err(); err != nil {
+		rc.Log.Error().Err(err).Str("hdel", rc.RobotAccountsKey).Str("robot", name).
+			Msg("failed to delete robot account record")
+
+		return fmt.Errorf("failed to delete robot account record for %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// AddAccessControlPolicies stores a new version of the access control policies, the versions are stored
+// in a hash keyed by version, so its length is the latest version.
+func (rc *RedisDB) AddAccessControlPolicies(policies mTypes.AccessControlPolicies) error {
+	ctx := context.Background()
+
+	policiesBlob, err := json.Marshal(policies)
+	if err != nil {
+		return err
+	}
+
+	return rc.withRSLocks(ctx, []string{rc.getAccessControlLockKey()}, func() error {
+		latestVersion, err := rc.Client.HLen(ctx, rc.AccessControlKey).Result()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hlen", rc.AccessControlKey).Msg("failed to get access control policies count")
+
+			return fmt.Errorf("failed to get access control policies count: %w", err)
+		}
+
+		if int64(policies.Version) != latestVersion+1 {
+			return zerr.ErrAccessControlPoliciesConflict
+		}
+
+		version := strconv.Itoa(policies.Version)
+
+		if err := rc.Client.HSet(ctx, rc.AccessControlKey, version, policiesBlob).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("hset", rc.AccessControlKey).Str("version", version).
+				Msg("failed to put access control policies record")
+
+			return fmt.Errorf("failed to put access control policies record for version %s: %w", version, err)
+		}
+
+		return nil
+	})
+}
+
+// GetAccessControlPolicies returns the given version of the access control policies, the latest one if 0.
+func (rc *RedisDB) GetAccessControlPolicies(version int) (mTypes.AccessControlPolicies, error) {
+	var policies mTypes.AccessControlPolicies
+
+	ctx := context.Background()
+
+	if version == 0 {
+		latestVersion, err := rc.Client.HLen(ctx, rc.AccessControlKey).Result()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hlen", rc.AccessControlKey).Msg("failed to get access control policies count")
+
+			return policies, fmt.Errorf("failed to get access control policies count: %w", err)
+		}
+
+		version = int(latestVersion)
+	}
+
+	policiesBlob, err := rc.Client.HGet(ctx, rc.AccessControlKey, strconv.Itoa(version)).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		rc.Log.Error().Err(err).Str("hget", rc.AccessControlKey).Int("version", version).
+			Msg("failed to get access control policies record")
+
+		return policies, fmt.Errorf("failed to get access control policies record for version %d: %w", version, err)
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return policies, zerr.ErrAccessControlPoliciesNotFound
+	}
+
+	err = json.Unmarshal(policiesBlob, &policies)
+
+	return policies, err
+}
+
+// GetAccessControlPoliciesHistory returns all the versions of the access control policies, oldest first.
+func (rc *RedisDB) GetAccessControlPoliciesHistory() ([]mTypes.AccessControlPolicies, error) {
+	history := []mTypes.AccessControlPolicies{}
+
+	policiesBlobs, err := rc.Client.HGetAll(context.Background(), rc.AccessControlKey).Result()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("hgetall", rc.AccessControlKey).Msg("failed to get access control policies records")
+
+		return nil, fmt.Errorf("failed to get access control policies records: %w", err)
+	}
+
+	for _, policiesBlob := range policiesBlobs {
+		var policies mTypes.AccessControlPolicies
+
+		if err := json.Unmarshal([]byte(policiesBlob), &policies); err != nil {
+			return nil, err
+		}
+
+		history = append(history, policies)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Version < history[j].Version
+	})
+
+	return history, nil
+}
+
 func (rc *RedisDB) GetUserAPIKeys(ctx context.Context) ([]mTypes.APIKeyDetails, error) {
 	apiKeys := make([]mTypes.APIKeyDetails, 0)
 
@@ -1411,6 +1643,243 @@ func (rc *RedisDB) AddManifestSignature(repo string, signedManifestDigest godige
 	return err
 }
 
+// AddManifestSbom adds an SBOM to a given manifest in the database, the packages it lists are stored
+// under the SBOM manifest digest.
+func (rc *RedisDB) AddManifestSbom(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+	packages []mTypes.SbomPackage,
+) error {
+	ctx := context.Background()
+
+	packagesBlob, err := proto.Marshal(mConvert.GetProtoSbomPackages(packages))
+	if err != nil {
+		return err
+	}
+
+	err = rc.withRSLocks(ctx, []string{rc.getRepoLockKey(repo)}, func() error {
+		protoRepoMeta, err := rc.getProtoRepoMeta(ctx, repo)
+		if err != nil {
+			return err
+		}
+
+		oldPackages, err := rc.GetSbomPackages(godigest.Digest(sbom.SbomManifestDigest))
+		if err != nil && !errors.Is(err, zerr.ErrSbomPackagesNotFound) {
+			return err
+		}
+
+		err = rc.Client.HSet(ctx, rc.SbomPackagesKey, sbom.SbomManifestDigest, packagesBlob).Err()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hset", rc.SbomPackagesKey).Str("digest", sbom.SbomManifestDigest).
+				Msg("failed to put sbom packages record")
+
+			return fmt.Errorf("failed to put sbom packages record for digest %s: %w", sbom.SbomManifestDigest, err)
+		}
+
+		err = rc.indexSbomPackages(ctx, sbom.SbomManifestDigest, oldPackages, packages)
+		if err != nil {
+			return err
+		}
+
+		common.AddProtoSbom(protoRepoMeta, subjectDigest.String(), mConvert.GetProtoSbomInfo(sbom))
+
+		repoMetaBlob, err := proto.Marshal(protoRepoMeta)
+		if err != nil {
+			return err
+		}
+
+		err = rc.Client.HSet(ctx, rc.RepoMetaKey, repo, repoMetaBlob).Err()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hset", rc.RepoMetaKey).Str("repo", repo).
+				Msg("failed to put repo meta record")
+
+			return fmt.Errorf("failed to put repometa record for repo %s: %w", repo, err)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// indexSbomPackages updates the package index with the packages listed by an SBOM, removing the SBOM from
+// the entries of the packages it no longer lists. Each package has a set of SBOM digests, the names of the
+// packages are kept in a set of their own so the index can be reset.
+func (rc *RedisDB) indexSbomPackages(ctx context.Context, sbomDigest string,
+	oldPackages, packages []mTypes.SbomPackage,
+) error {
+	keys := map[string]bool{}
+
+	for _, pkg := range packages {
+		for _, key := range common.SbomPackageIndexKeys(pkg) {
+			keys[key] = true
+		}
+	}
+
+	_, err := rc.Client.TxPipelined(ctx, func(txrp redis.Pipeliner) error {
+		for _, pkg := range oldPackages {
+			for _, key := range common.SbomPackageIndexKeys(pkg) {
+				if !keys[key] {
+					txrp.SRem(ctx, join(rc.SbomIndexKey, key), sbomDigest)
+				}
+			}
+		}
+
+		for key := range keys {
+			txrp.SAdd(ctx, rc.SbomIndexKey, key)
+			txrp.SAdd(ctx, join(rc.SbomIndexKey, key), sbomDigest)
+		}
+
+		return nil
+	})
+	if err != nil {
+		rc.Log.Error().Err(err).Str("sadd", rc.SbomIndexKey).Str("digest", sbomDigest).
+			Msg("failed to index sbom packages")
+
+		return fmt.Errorf("failed to index the packages of sbom %s: %w", sbomDigest, err)
+	}
+
+	return nil
+}
+
+// SetCVEScanResult stores the result of the CVE scan of a manifest or an index.
+func (rc *RedisDB) SetCVEScanResult(digest godigest.Digest, result mTypes.CVEScanResult) error {
+	ctx := context.Background()
+
+	resultBlob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	err = rc.Client.HSet(ctx, rc.CVEScanResultsKey, digest.String(), resultBlob).Err()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("hset", rc.CVEScanResultsKey).Str("digest", digest.String()).
+			Msg("failed to put cve scan result record")
+
+		return fmt.Errorf("failed to put cve scan result record for digest %s: %w", digest, err)
+	}
+
+	return nil
+}
+
+// GetCVEScanResult returns the stored result of the last CVE scan of a manifest or an index.
+func (rc *RedisDB) GetCVEScanResult(digest godigest.Digest) (mTypes.CVEScanResult, error) {
+	var result mTypes.CVEScanResult
+
+	resultBlob, err := rc.Client.HGet(context.Background(), rc.CVEScanResultsKey, digest.String()).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		rc.Log.Error().Err(err).Str("hget", rc.CVEScanResultsKey).Str("digest", digest.String()).
+			Msg("failed to get cve scan result record")
+
+		return result, fmt.Errorf("failed to get cve scan result record for digest %s: %w", digest, err)
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return result, zerr.ErrCVEScanResultNotFound
+	}
+
+	err = json.Unmarshal(resultBlob, &result)
+
+	return result, err
+}
+
+// DeleteCVEScanResult deletes the stored result of the CVE scan of a manifest or an index.
+func (rc *RedisDB) DeleteCVEScanResult(digest godigest.Digest) error {
+	err := rc.Client.HDel(context.Background(), rc.CVEScanResultsKey, digest.String()).Err()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("hdel", rc.CVEScanResultsKey).Str("digest", digest.String()).
+			Msg("failed to delete cve scan result record")
+
+		return fmt.Errorf("failed to delete cve scan result record for digest %s: %w", digest, err)
+	}
+
+	return nil
+}
+
+// DeleteCVEScanResults deletes the stored CVE scan results for which isStale returns true.
+func (rc *RedisDB) DeleteCVEScanResults(isStale func(result mTypes.CVEScanResult) bool) error {
+	ctx := context.Background()
+
+	iter := rc.Client.HScan(ctx, rc.CVEScanResultsKey, 0, "", 0).Iterator()
+
+	staleDigests := []string{}
+
+	// the iterator returns the fields and their values alternately
+	for iter.Next(ctx) {
+		digest := iter.Val()
+
+		if !iter.Next(ctx) {
+			break
+		}
+
+		var result mTypes.CVEScanResult
+
+		// results which can't be read are stale too
+		if err := json.Unmarshal([]byte(iter.Val()), &result); err != nil || isStale(result) {
+			staleDigests = append(staleDigests, digest)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		rc.Log.Error().Err(err).Str("hscan", rc.CVEScanResultsKey).Msg("failed to scan cve scan result records")
+
+		return fmt.Errorf("failed to scan cve scan result records: %w", err)
+	}
+
+	if len(staleDigests) == 0 {
+		return nil
+	}
+
+	if err := rc.Client.HDel(ctx, rc.CVEScanResultsKey, staleDigests...).Err(); err != nil {
+		rc.Log.Error().Err(err).Str("hdel", rc.CVEScanResultsKey).Msg("failed to delete stale cve scan result records")
+
+		return fmt.Errorf("failed to delete stale cve scan result records: %w", err)
+	}
+
+	return nil
+}
+
+// GetSbomPackages returns the packages listed by an SBOM manifest.
+func (rc *RedisDB) GetSbomPackages(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error) {
+	packagesBlob, err := rc.Client.HGet(context.Background(), rc.SbomPackagesKey, sbomDigest.String()).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		rc.Log.Error().Err(err).Str("hget", rc.SbomPackagesKey).Str("digest", sbomDigest.String()).
+			Msg("failed to get sbom packages record")
+
+		return nil, fmt.Errorf("failed to get sbom packages record for digest %s: %w", sbomDigest, err)
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return nil, zerr.ErrSbomPackagesNotFound
+	}
+
+	protoPackages := &proto_go.SbomPackages{}
+
+	err = proto.Unmarshal(packagesBlob, protoPackages)
+	if err != nil {
+		return nil, err
+	}
+
+	return mConvert.GetSbomPackages(protoPackages), nil
+}
+
+// GetSbomsWithPackage returns the digests of the SBOM manifests listing a package with the given name or purl.
+func (rc *RedisDB) GetSbomsWithPackage(name string) ([]godigest.Digest, error) {
+	sbomDigests := []godigest.Digest{}
+
+	members, err := rc.Client.SMembers(context.Background(), join(rc.SbomIndexKey, name)).Result()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("smembers", join(rc.SbomIndexKey, name)).
+			Msg("failed to get sboms listing package")
+
+		return sbomDigests, fmt.Errorf("failed to get the sboms listing package %s: %w", name, err)
+	}
+
+	for _, member := range members {
+		sbomDigests = append(sbomDigests, godigest.Digest(member))
+	}
+
+	return sbomDigests, nil
+}
+
 // DeleteSignature deletes signature metadata to a given manifest from the database.
 func (rc *RedisDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
 	sigMeta mTypes.SignatureMetadata,
@@ -1499,8 +1968,8 @@ func (rc *RedisDB) UpdateSignaturesValidity(ctx context.Context, repo string, ma
 				layersInfo := []*proto_go.LayersInfo{}
 
 				for _, layerInfo := range sigInfo.LayersInfo {
-					author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
-						layerInfo.SignatureKey, manifestDigest, mConvert.GetImageMeta(protoImageMeta), repo)
+					author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType,
+						mConvert.GetLayerInfo(layerInfo), manifestDigest, mConvert.GetImageMeta(protoImageMeta), repo)
 
 					if isTrusted {
 						layerInfo.Signer = author
@@ -1610,6 +2079,42 @@ func (rc *RedisDB) DecrementRepoStars(repo string) error {
 	return err
 }
 
+// SetRepoProxyCache marks a repo as populated by the sync proxy cache from the given upstream.
+func (rc *RedisDB) SetRepoProxyCache(repo string, upstream string) error {
+	ctx := context.Background()
+
+	err := rc.withRSLocks(ctx, []string{rc.getRepoLockKey(repo)}, func() error {
+		protoRepoMeta, err := rc.getProtoRepoMeta(ctx, repo)
+		if err != nil {
+			return err
+		}
+
+		if protoRepoMeta.IsProxyCache && protoRepoMeta.Upstream == upstream {
+			return nil
+		}
+
+		protoRepoMeta.IsProxyCache = true
+		protoRepoMeta.Upstream = upstream
+
+		repoMetaBlob, err := proto.Marshal(protoRepoMeta)
+		if err != nil {
+			return err
+		}
+
+		err = rc.Client.HSet(ctx, rc.RepoMetaKey, repo, repoMetaBlob).Err()
+		if err != nil {
+			rc.Log.Error().Err(err).Str("hset", rc.RepoMetaKey).Str("repo", repo).
+				Msg("failed to put repo meta record")
+
+			return fmt.Errorf("failed to put repometa record for repo %s: %w", repo, err)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
 // SetRepoMeta should NEVER be used in production as both GetRepoMeta and SetRepoMeta
 // should be locked for the duration of the entire transaction at a higher level in the app.
 func (rc *RedisDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMeta) error {
@@ -1883,6 +2388,8 @@ func (rc *RedisDB) RemoveRepoReference(repo, reference string, manifestDigest go
 			delete(protoRepoMeta.Statistics, manifestDigest.String())
 			delete(protoRepoMeta.Signatures, manifestDigest.String())
 			delete(protoRepoMeta.Referrers, manifestDigest.String())
+			delete(protoRepoMeta.Sboms, manifestDigest.String())
+			common.RemoveProtoSbom(protoRepoMeta, manifestDigest.String())
 		}
 
 		repoBlobsBytes, err := rc.Client.HGet(ctx, rc.RepoBlobsKey, repo).Bytes()
@@ -1959,12 +2466,14 @@ func (rc *RedisDB) ResetRepoReferences(repo string) error {
 		}
 
 		repoMetaBlob, err := proto.Marshal(&proto_go.RepoMeta{
-			Name:       repo,
-			Statistics: protoRepoMeta.Statistics,
-			Stars:      protoRepoMeta.Stars,
-			Tags:       map[string]*proto_go.TagDescriptor{"": {}},
-			Signatures: map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
-			Referrers:  map[string]*proto_go.ReferrersInfo{"": {}},
+			Name:         repo,
+			Statistics:   protoRepoMeta.Statistics,
+			Stars:        protoRepoMeta.Stars,
+			IsProxyCache: protoRepoMeta.GetIsProxyCache(),
+			Upstream:     protoRepoMeta.GetUpstream(),
+			Tags:         map[string]*proto_go.TagDescriptor{"": {}},
+			Signatures:   map[string]*proto_go.ManifestSignatures{"": {Map: map[string]*proto_go.SignaturesInfo{"": {}}}},
+			Referrers:    map[string]*proto_go.ReferrersInfo{"": {}},
 		})
 		if err != nil {
 			return err
@@ -2077,10 +2586,65 @@ func (rc *RedisDB) ResetDB() error {
 			return fmt.Errorf("failed to delete version bucket: %w", err)
 		}
 
+		if err := txrp.Del(ctx, rc.CVEScanResultsKey).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("del", rc.CVEScanResultsKey).Msg("failed to delete cve scan results bucket")
+
+			return fmt.Errorf("failed to delete cve scan results bucket: %w", err)
+		}
+
+		if err := txrp.Del(ctx, rc.SbomPackagesKey).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("del", rc.SbomPackagesKey).Msg("failed to delete sbom packages bucket")
+
+			return fmt.Errorf("failed to delete sbom packages bucket: %w", err)
+		}
+
+		if err := txrp.Del(ctx, rc.RobotAccountsKey).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("del", rc.RobotAccountsKey).Msg("failed to delete robot accounts bucket")
+
+			return fmt.Errorf("failed to delete robot accounts bucket: %w", err)
+		}
+
+		if err := txrp.Del(ctx, rc.AccessControlKey).Err(); err != nil {
+			rc.Log.Error().Err(err).Str("del", rc.AccessControlKey).Msg("failed to delete access control bucket")
+
+			return fmt.Errorf("failed to delete access control bucket: %w", err)
+		}
+
 		return nil
 	})
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	return rc.resetSbomIndex(ctx)
+}
+
+// resetSbomIndex deletes the sets of sbom digests of all the indexed packages.
+func (rc *RedisDB) resetSbomIndex(ctx context.Context) error {
+	indexedPackages, err := rc.Client.SMembers(ctx, rc.SbomIndexKey).Result()
+	if err != nil {
+		rc.Log.Error().Err(err).Str("smembers", rc.SbomIndexKey).Msg("failed to get indexed sbom packages")
+
+		return fmt.Errorf("failed to get indexed sbom packages: %w", err)
+	}
+
+	_, err = rc.Client.TxPipelined(ctx, func(txrp redis.Pipeliner) error {
+		for _, pkg := range indexedPackages {
+			txrp.Del(ctx, join(rc.SbomIndexKey, pkg))
+		}
+
+		txrp.Del(ctx, rc.SbomIndexKey)
+
+		return nil
+	})
+	if err != nil {
+		rc.Log.Error().Err(err).Str("del", rc.SbomIndexKey).Msg("failed to delete sbom package index")
+
+		return fmt.Errorf("failed to delete sbom package index: %w", err)
+	}
+
+	return nil
 }
 
 func (rc *RedisDB) PatchDB() error {
@@ -2256,6 +2820,14 @@ func (rc *RedisDB) getUserLockKey(name string) string {
 	return strings.Join([]string{rc.LocksKey, "User", name}, ":")
 }
 
+func (rc *RedisDB) getRobotAccountLockKey(name string) string {
+	return strings.Join([]string{rc.LocksKey, "RobotAccount", name}, ":")
+}
+
+func (rc *RedisDB) getAccessControlLockKey() string {
+	return strings.Join([]string{rc.LocksKey, "AccessControlPolicies"}, ":")
+}
+
 func (rc *RedisDB) getVersionLockKey() string {
 	return strings.Join([]string{rc.LocksKey, "Version"}, ":")
 }
@@ -16,6 +16,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"google.golang.org/protobuf/proto"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/log"
 	proto_go "zotregistry.dev/zot/pkg/meta/proto/gen"
 	"zotregistry.dev/zot/pkg/meta/redis"
@@ -32,7 +33,7 @@ var ErrTestError = errors.New("TestError")
 type imgTrustStore struct{}
 
 func (its imgTrustStore) VerifySignature(
-	signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
+	signatureType string, layerInfo mTypes.LayerInfo, manifestDigest godigest.Digest, imageMeta mTypes.ImageMeta,
 	repo string,
 ) (mTypes.Author, mTypes.ExpiryDate, mTypes.Validity, error) {
 	return "", time.Time{}, false, nil
@@ -784,6 +785,60 @@ func TestWrapperErrors(t *testing.T) {
 			})
 		})
 
+		Convey("AddManifestSbom", func() {
+			Convey("unmarshalProtoRepoMeta error", func() {
+				err := setRepoMeta("repo", badProtoBlob, client)
+				So(err, ShouldBeNil)
+
+				err = metaDB.AddManifestSbom("repo", godigest.FromString("dig"), mTypes.SbomInfo{}, nil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("GetCVEScanResult", func() {
+			Convey("unmarshal error", func() {
+				err := client.HSet(ctx, keyPrefix+":"+redis.CVEScanResultsBucket,
+					godigest.FromString("dig").String(), []byte("bad json")).Err()
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetCVEScanResult(godigest.FromString("dig"))
+				So(err, ShouldNotBeNil)
+
+				// results which can't be read are deleted with the stale ones
+				err = metaDB.DeleteCVEScanResults(func(result mTypes.CVEScanResult) bool { return false })
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetCVEScanResult(godigest.FromString("dig"))
+				So(errors.Is(err, zerr.ErrCVEScanResultNotFound), ShouldBeTrue)
+			})
+		})
+
+		Convey("GetRobotAccount", func() {
+			Convey("unmarshal error", func() {
+				err := client.HSet(ctx, keyPrefix+":"+redis.RobotAccountsBucket, "ci", []byte("bad json")).Err()
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetRobotAccount("ci")
+				So(err, ShouldNotBeNil)
+
+				_, err = metaDB.GetRobotAccounts()
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("GetAccessControlPolicies", func() {
+			Convey("unmarshal error", func() {
+				err := client.HSet(ctx, keyPrefix+":"+redis.AccessControlBucket, "1", []byte("bad json")).Err()
+				So(err, ShouldBeNil)
+
+				_, err = metaDB.GetAccessControlPolicies(0)
+				So(err, ShouldNotBeNil)
+
+				_, err = metaDB.GetAccessControlPoliciesHistory()
+				So(err, ShouldNotBeNil)
+			})
+		})
+
 		Convey("GetMultipleRepoMeta", func() {
 			Convey("unmarshalProtoRepoMeta error", func() {
 				err := setRepoMeta("repo", badProtoBlob, client)
@@ -0,0 +1,283 @@
+package meta
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.dev/zot/errors"
+	zcommon "zotregistry.dev/zot/pkg/common"
+	"zotregistry.dev/zot/pkg/log"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	stypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+const (
+	SbomFormatSPDX      = "spdx"
+	SbomFormatCycloneDX = "cyclonedx"
+
+	MediaTypeSPDX      = "application/spdx+json"
+	MediaTypeCycloneDX = "application/vnd.cyclonedx+json"
+	// media type of the sboms attached by "cosign attach sbom".
+	MediaTypeCosignSPDX = "text/spdx+json"
+	// media type of in-toto attestations, signed by "cosign attest".
+	MediaTypeDSSE = "application/vnd.dsse.envelope.v1+json"
+
+	PredicateTypeSPDX      = "https://spdx.dev/Document"
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+)
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+type inTotoStatement struct {
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type spdxDocument struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+type cycloneDXComponent struct {
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXDocument struct {
+	BOMFormat  string               `json:"bomFormat"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// isSbom checks if the manifest is an SBOM or an in-toto attestation possibly containing one,
+// and returns the digest of the manifest it describes.
+func isSbom(reference string, manifestContent ispec.Manifest) (bool, godigest.Digest) {
+	var subjectDigest godigest.Digest
+
+	switch {
+	case manifestContent.Subject != nil:
+		subjectDigest = manifestContent.Subject.Digest
+	case zcommon.IsCosignSBOM(reference) || zcommon.IsCosignAttestation(reference):
+		prefixLen := len("sha256-")
+		digestLen := 64
+
+		subjectDigest = godigest.NewDigestFromEncoded(godigest.SHA256, reference[prefixLen:prefixLen+digestLen])
+	default:
+		return false, ""
+	}
+
+	artifactType := zcommon.GetManifestArtifactType(manifestContent)
+
+	if getSbomFormat(artifactType) != "" {
+		return true, subjectDigest
+	}
+
+	for _, layer := range manifestContent.Layers {
+		if getSbomFormat(layer.MediaType) != "" || layer.MediaType == MediaTypeDSSE {
+			return true, subjectDigest
+		}
+	}
+
+	return false, ""
+}
+
+func getSbomFormat(mediaType string) string {
+	switch mediaType {
+	case MediaTypeSPDX, MediaTypeCosignSPDX:
+		return SbomFormatSPDX
+	case MediaTypeCycloneDX:
+		return SbomFormatCycloneDX
+	default:
+		return ""
+	}
+}
+
+// SbomDocument is an SPDX or CycloneDX document read from a layer of an SBOM manifest.
+type SbomDocument struct {
+	Format      string
+	Content     []byte
+	LayerDigest godigest.Digest
+}
+
+// GetSbomDocuments reads the SBOM documents found in the layers of the manifest, the SBOMs wrapped in
+// in-toto attestations are extracted from their envelope.
+func GetSbomDocuments(repo string, manifestDigest godigest.Digest, manifestContent ispec.Manifest,
+	imageStore stypes.ImageStore, log log.Logger,
+) ([]SbomDocument, error) {
+	documents := []SbomDocument{}
+	artifactFormat := getSbomFormat(zcommon.GetManifestArtifactType(manifestContent))
+
+	for _, layer := range manifestContent.Layers {
+		isAttestation := layer.MediaType == MediaTypeDSSE
+
+		format := getSbomFormat(layer.MediaType)
+		if format == "" && !isAttestation {
+			// the layers of referrers pushed with an sbom artifact type can have a generic media type
+			if format = artifactFormat; format == "" {
+				continue
+			}
+		}
+
+		layerContent, err := imageStore.GetBlobContent(repo, layer.Digest)
+		if err != nil {
+			log.Error().Err(err).Str("repository", repo).Str("digest", manifestDigest.String()).
+				Str("layerDigest", layer.Digest.String()).Msg("failed to get sbom layer content")
+
+			return nil, err
+		}
+
+		if isAttestation {
+			format, layerContent = getAttestedSbom(layerContent)
+			if format == "" {
+				continue
+			}
+		}
+
+		documents = append(documents, SbomDocument{Format: format, Content: layerContent, LayerDigest: layer.Digest})
+	}
+
+	return documents, nil
+}
+
+// GetSbomInfo reads the packages listed by the SBOM layers of the manifest. SBOMs which can't be parsed
+// are skipped, a manifest without any valid SBOM returns false.
+func GetSbomInfo(repo string, manifestDigest godigest.Digest, manifestContent ispec.Manifest,
+	imageStore stypes.ImageStore, log log.Logger,
+) (mTypes.SbomInfo, []mTypes.SbomPackage, bool, error) {
+	sbomInfo := mTypes.SbomInfo{
+		SbomManifestDigest: manifestDigest.String(),
+	}
+
+	sbomPackages := []mTypes.SbomPackage{}
+
+	documents, err := GetSbomDocuments(repo, manifestDigest, manifestContent, imageStore, log)
+	if err != nil {
+		return mTypes.SbomInfo{}, nil, false, err
+	}
+
+	found := false
+
+	for _, document := range documents {
+		packages, err := parseSbomPackages(document.Format, document.Content)
+		if err != nil {
+			log.Warn().Err(err).Str("repository", repo).Str("digest", manifestDigest.String()).
+				Str("layerDigest", document.LayerDigest.String()).Str("format", document.Format).
+				Msg("failed to parse sbom")
+
+			continue
+		}
+
+		found = true
+		sbomInfo.Format = document.Format
+		sbomPackages = append(sbomPackages, packages...)
+	}
+
+	sbomInfo.PackageCount = len(sbomPackages)
+
+	return sbomInfo, sbomPackages, found, nil
+}
+
+// getAttestedSbom returns the SBOM predicate of an in-toto statement wrapped in a DSSE envelope.
+func getAttestedSbom(envelopeContent []byte) (string, []byte) {
+	var envelope dsseEnvelope
+
+	if err := json.Unmarshal(envelopeContent, &envelope); err != nil {
+		return "", nil
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return "", nil
+	}
+
+	var statement inTotoStatement
+
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return "", nil
+	}
+
+	// predicate types may be versioned, eg. "https://cyclonedx.org/bom/v1.4"
+	switch {
+	case strings.HasPrefix(statement.PredicateType, PredicateTypeSPDX):
+		return SbomFormatSPDX, statement.Predicate
+	case strings.HasPrefix(statement.PredicateType, PredicateTypeCycloneDX):
+		return SbomFormatCycloneDX, statement.Predicate
+	default:
+		return "", nil
+	}
+}
+
+func parseSbomPackages(format string, content []byte) ([]mTypes.SbomPackage, error) {
+	packages := []mTypes.SbomPackage{}
+
+	switch format {
+	case SbomFormatSPDX:
+		var document spdxDocument
+
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, err
+		}
+
+		if document.SPDXVersion == "" {
+			return nil, zerr.ErrInvalidSbom
+		}
+
+		for _, pkg := range document.Packages {
+			sbomPackage := mTypes.SbomPackage{Name: pkg.Name, Version: pkg.VersionInfo}
+
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					sbomPackage.PURL = ref.ReferenceLocator
+
+					break
+				}
+			}
+
+			packages = append(packages, sbomPackage)
+		}
+	case SbomFormatCycloneDX:
+		var document cycloneDXDocument
+
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, err
+		}
+
+		if document.BOMFormat != "CycloneDX" {
+			return nil, zerr.ErrInvalidSbom
+		}
+
+		packages = appendCycloneDXComponents(packages, document.Components)
+	}
+
+	return packages, nil
+}
+
+func appendCycloneDXComponents(packages []mTypes.SbomPackage, components []cycloneDXComponent,
+) []mTypes.SbomPackage {
+	for _, component := range components {
+		packages = append(packages, mTypes.SbomPackage{
+			Name:    component.Name,
+			Version: component.Version,
+			PURL:    component.PURL,
+		})
+
+		packages = appendCycloneDXComponents(packages, component.Components)
+	}
+
+	return packages
+}
@@ -0,0 +1,255 @@
+package meta_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/log"
+	"zotregistry.dev/zot/pkg/meta"
+	"zotregistry.dev/zot/pkg/meta/boltdb"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+	"zotregistry.dev/zot/pkg/storage"
+	"zotregistry.dev/zot/pkg/storage/local"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+	"zotregistry.dev/zot/pkg/test/mocks"
+)
+
+const (
+	spdxDocument = `{
+		"spdxVersion": "SPDX-2.3",
+		"packages": [
+			{
+				"name": "openssl",
+				"versionInfo": "3.0.2",
+				"externalRefs": [
+					{"referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:openssl:openssl:3.0.2"},
+					{"referenceType": "purl", "referenceLocator": "pkg:deb/ubuntu/openssl@3.0.2"}
+				]
+			},
+			{"name": "zlib", "versionInfo": "1.2.11"}
+		]
+	}`
+	cycloneDXDocument = `{
+		"bomFormat": "CycloneDX",
+		"components": [
+			{
+				"name": "spring-core",
+				"version": "5.3.20",
+				"purl": "pkg:maven/org.springframework/spring-core@5.3.20",
+				"components": [{"name": "spring-jcl", "version": "5.3.20"}]
+			}
+		]
+	}`
+)
+
+func getCycloneDXAttestation() []byte {
+	statement := fmt.Sprintf(`{
+		"_type": "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://cyclonedx.org/bom/v1.4",
+		"predicate": %s
+	}`, cycloneDXDocument)
+
+	envelope, _ := json.Marshal(map[string]string{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString([]byte(statement)),
+	})
+
+	return envelope
+}
+
+func TestParseStorageSboms(t *testing.T) {
+	Convey("Test sboms are indexed in metadb", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "/dev/null")
+		ctx := context.Background()
+
+		boltDB, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDB, log)
+		So(err, ShouldBeNil)
+
+		imageStore := local.NewImageStore(rootDir, false, false,
+			log, monitoring.NewMetricsServer(false, log), nil, nil, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imageStore}
+
+		image := CreateRandomImage()
+		err = WriteImageToFileSystem(image, repo, "tag", storeController)
+		So(err, ShouldBeNil)
+
+		spdxReferrer := CreateImageWith().LayerBlobs([][]byte{[]byte(spdxDocument)}).
+			ArtifactConfig(meta.MediaTypeSPDX).Subject(image.DescriptorRef()).Build()
+		err = WriteImageToFileSystem(spdxReferrer, repo, spdxReferrer.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		attestation := getCycloneDXAttestation()
+		cosignAttestation := CreateImageWith().Layers([]Layer{{
+			Blob:      attestation,
+			MediaType: meta.MediaTypeDSSE,
+			Digest:    godigest.FromBytes(attestation),
+		}}).DefaultConfig().Build()
+		err = WriteImageToFileSystem(cosignAttestation, repo,
+			fmt.Sprintf("sha256-%s.att", image.Digest().Encoded()), storeController)
+		So(err, ShouldBeNil)
+
+		invalidSbom := CreateImageWith().LayerBlobs([][]byte{[]byte(`{"packages": []}`)}).
+			ArtifactConfig(meta.MediaTypeSPDX).Subject(image.DescriptorRef()).Build()
+		err = WriteImageToFileSystem(invalidSbom, repo, invalidSbom.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		image2 := CreateRandomImage()
+		err = WriteImageToFileSystem(image2, repo, "tag2", storeController)
+		So(err, ShouldBeNil)
+
+		cosignSbom := CreateImageWith().Layers([]Layer{{
+			Blob:      []byte(spdxDocument),
+			MediaType: meta.MediaTypeCosignSPDX,
+			Digest:    godigest.FromBytes([]byte(spdxDocument)),
+		}}).DefaultConfig().Build()
+		err = WriteImageToFileSystem(cosignSbom, repo,
+			fmt.Sprintf("sha256-%s.sbom", image2.Digest().Encoded()), storeController)
+		So(err, ShouldBeNil)
+
+		err = meta.ParseStorage(metaDB, storeController, log)
+		So(err, ShouldBeNil)
+
+		repoMeta, err := metaDB.GetRepoMeta(ctx, repo)
+		So(err, ShouldBeNil)
+
+		sboms := map[string]mTypes.SbomInfo{}
+		for _, sbom := range repoMeta.Sboms[image.DigestStr()] {
+			sboms[sbom.SbomManifestDigest] = sbom
+		}
+
+		So(sboms, ShouldHaveLength, 2)
+		So(sboms[spdxReferrer.DigestStr()].Format, ShouldEqual, meta.SbomFormatSPDX)
+		So(sboms[spdxReferrer.DigestStr()].PackageCount, ShouldEqual, 2)
+		So(sboms[cosignAttestation.DigestStr()].Format, ShouldEqual, meta.SbomFormatCycloneDX)
+		So(sboms[cosignAttestation.DigestStr()].PackageCount, ShouldEqual, 2)
+
+		packages, err := metaDB.GetSbomPackages(spdxReferrer.Digest())
+		So(err, ShouldBeNil)
+		So(packages, ShouldResemble, []mTypes.SbomPackage{
+			{Name: "openssl", Version: "3.0.2", PURL: "pkg:deb/ubuntu/openssl@3.0.2"},
+			{Name: "zlib", Version: "1.2.11"},
+		})
+
+		packages, err = metaDB.GetSbomPackages(cosignAttestation.Digest())
+		So(err, ShouldBeNil)
+		So(packages, ShouldResemble, []mTypes.SbomPackage{
+			{Name: "spring-core", Version: "5.3.20", PURL: "pkg:maven/org.springframework/spring-core@5.3.20"},
+			{Name: "spring-jcl", Version: "5.3.20"},
+		})
+
+		So(repoMeta.Sboms[image2.DigestStr()], ShouldHaveLength, 1)
+		So(repoMeta.Sboms[image2.DigestStr()][0].SbomManifestDigest, ShouldEqual, cosignSbom.DigestStr())
+
+		packages, err = metaDB.GetSbomPackages(cosignSbom.Digest())
+		So(err, ShouldBeNil)
+		So(packages, ShouldHaveLength, 2)
+
+		_, err = metaDB.GetSbomPackages(invalidSbom.Digest())
+		So(err, ShouldEqual, zerr.ErrSbomPackagesNotFound)
+
+		Convey("Deleting the sbom removes it from the repo meta", func() {
+			err := metaDB.RemoveRepoReference(repo, spdxReferrer.DigestStr(), spdxReferrer.Digest())
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(ctx, repo)
+			So(err, ShouldBeNil)
+			So(repoMeta.Sboms[image.DigestStr()], ShouldHaveLength, 1)
+			So(repoMeta.Sboms[image.DigestStr()][0].SbomManifestDigest, ShouldEqual, cosignAttestation.DigestStr())
+		})
+	})
+
+	Convey("Failing to index an sbom doesn't fail the push", t, func() {
+		log := log.NewLogger("debug", "/dev/null")
+		sbom := CreateImageWith().LayerBlobs([][]byte{[]byte(spdxDocument)}).
+			ArtifactConfig(meta.MediaTypeSPDX).Subject(CreateRandomImage().DescriptorRef()).Build()
+
+		imageStore := mocks.MockedImageStore{
+			GetBlobContentFn: func(repo string, digest godigest.Digest) ([]byte, error) {
+				return []byte(spdxDocument), nil
+			},
+		}
+
+		sbomAdded := false
+
+		metaDB := mocks.MetaDBMock{
+			AddManifestSbomFn: func(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+				packages []mTypes.SbomPackage,
+			) error {
+				sbomAdded = true
+
+				return ErrTestError
+			},
+		}
+
+		err := meta.SetImageMetaFromInput(context.Background(), repo, sbom.DigestStr(), ispec.MediaTypeImageManifest,
+			sbom.Digest(), sbom.ManifestDescriptor.Data, imageStore, metaDB, log)
+		So(err, ShouldBeNil)
+		So(sbomAdded, ShouldBeTrue)
+
+		imageStore.GetBlobContentFn = func(repo string, digest godigest.Digest) ([]byte, error) {
+			return nil, ErrTestError
+		}
+
+		err = meta.SetImageMetaFromInput(context.Background(), repo, sbom.DigestStr(), ispec.MediaTypeImageManifest,
+			sbom.Digest(), sbom.ManifestDescriptor.Data, imageStore, metaDB, log)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("GetSbomInfo errors", t, func() {
+		log := log.NewLogger("debug", "/dev/null")
+		sbom := CreateImageWith().LayerBlobs([][]byte{[]byte(spdxDocument)}).EmptyConfig().
+			ArtifactType(meta.MediaTypeSPDX).Build()
+
+		imageStore := mocks.MockedImageStore{
+			GetBlobContentFn: func(repo string, digest godigest.Digest) ([]byte, error) {
+				return nil, ErrTestError
+			},
+		}
+
+		_, _, found, err := meta.GetSbomInfo(repo, sbom.Digest(), sbom.Manifest, imageStore, log)
+		So(err, ShouldNotBeNil)
+		So(found, ShouldBeFalse)
+
+		imageStore.GetBlobContentFn = func(repo string, digest godigest.Digest) ([]byte, error) {
+			return []byte("{bad json"), nil
+		}
+
+		_, _, found, err = meta.GetSbomInfo(repo, sbom.Digest(), sbom.Manifest, imageStore, log)
+		So(err, ShouldBeNil)
+		So(found, ShouldBeFalse)
+
+		Convey("Attestations with other predicates are skipped", func() {
+			statement := `{"predicateType": "https://slsa.dev/provenance/v0.2", "predicate": {}}`
+			envelope, _ := json.Marshal(map[string]string{
+				"payload": base64.StdEncoding.EncodeToString([]byte(statement)),
+			})
+
+			attestation := CreateImageWith().Layers([]Layer{{
+				Blob:      envelope,
+				MediaType: meta.MediaTypeDSSE,
+				Digest:    godigest.FromBytes(envelope),
+			}}).EmptyConfig().Build()
+
+			imageStore.GetBlobContentFn = func(repo string, digest godigest.Digest) ([]byte, error) {
+				return envelope, nil
+			}
+
+			_, _, found, err := meta.GetSbomInfo(repo, attestation.Digest(), attestation.Manifest, imageStore, log)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeFalse)
+		})
+	})
+}
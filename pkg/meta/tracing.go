@@ -0,0 +1,260 @@
+package meta
+
+import (
+	"context"
+
+	godigest "github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel/trace"
+
+	"zotregistry.dev/zot/pkg/extensions/tracing"
+	mTypes "zotregistry.dev/zot/pkg/meta/types"
+)
+
+// TracedMetaDB records a span for every MetaDB call taking a context which is part of a recorded trace,
+// calls without a context are passed through to the underlying MetaDB.
+type TracedMetaDB struct {
+	mTypes.MetaDB
+}
+
+func NewTracedMetaDB(metaDB mTypes.MetaDB) *TracedMetaDB {
+	return &TracedMetaDB{MetaDB: metaDB}
+}
+
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracing.StartChildSpan(ctx, "metadb."+method)
+}
+
+func (tdb *TracedMetaDB) SetRepoReference(ctx context.Context, repo string, reference string,
+	imageMeta mTypes.ImageMeta,
+) error {
+	ctx, span := startSpan(ctx, "SetRepoReference")
+	span.SetAttributes(tracing.RepositoryKey.String(repo), tracing.ReferenceKey.String(reference))
+
+	err := tdb.MetaDB.SetRepoReference(ctx, repo, reference, imageMeta)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) SearchRepos(ctx context.Context, searchText string) ([]mTypes.RepoMeta, error) {
+	ctx, span := startSpan(ctx, "SearchRepos")
+
+	repos, err := tdb.MetaDB.SearchRepos(ctx, searchText)
+	tracing.EndSpan(span, err)
+
+	return repos, err
+}
+
+func (tdb *TracedMetaDB) SearchTags(ctx context.Context, searchText string) ([]mTypes.FullImageMeta, error) {
+	ctx, span := startSpan(ctx, "SearchTags")
+
+	images, err := tdb.MetaDB.SearchTags(ctx, searchText)
+	tracing.EndSpan(span, err)
+
+	return images, err
+}
+
+func (tdb *TracedMetaDB) FilterTags(ctx context.Context, filterRepoTag mTypes.FilterRepoTagFunc,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.FullImageMeta, error) {
+	ctx, span := startSpan(ctx, "FilterTags")
+
+	images, err := tdb.MetaDB.FilterTags(ctx, filterRepoTag, filterFunc)
+	tracing.EndSpan(span, err)
+
+	return images, err
+}
+
+func (tdb *TracedMetaDB) FilterRepos(ctx context.Context, rankName mTypes.FilterRepoNameFunc,
+	filterFunc mTypes.FilterFullRepoFunc,
+) ([]mTypes.RepoMeta, error) {
+	ctx, span := startSpan(ctx, "FilterRepos")
+
+	repos, err := tdb.MetaDB.FilterRepos(ctx, rankName, filterFunc)
+	tracing.EndSpan(span, err)
+
+	return repos, err
+}
+
+func (tdb *TracedMetaDB) GetRepoMeta(ctx context.Context, repo string) (mTypes.RepoMeta, error) {
+	ctx, span := startSpan(ctx, "GetRepoMeta")
+	span.SetAttributes(tracing.RepositoryKey.String(repo))
+
+	repoMeta, err := tdb.MetaDB.GetRepoMeta(ctx, repo)
+	tracing.EndSpan(span, err)
+
+	return repoMeta, err
+}
+
+func (tdb *TracedMetaDB) GetFullImageMeta(ctx context.Context, repo string, tag string,
+) (mTypes.FullImageMeta, error) {
+	ctx, span := startSpan(ctx, "GetFullImageMeta")
+	span.SetAttributes(tracing.RepositoryKey.String(repo), tracing.ReferenceKey.String(tag))
+
+	imageMeta, err := tdb.MetaDB.GetFullImageMeta(ctx, repo, tag)
+	tracing.EndSpan(span, err)
+
+	return imageMeta, err
+}
+
+func (tdb *TracedMetaDB) GetMultipleRepoMeta(ctx context.Context, filter func(repoMeta mTypes.RepoMeta) bool,
+) ([]mTypes.RepoMeta, error) {
+	ctx, span := startSpan(ctx, "GetMultipleRepoMeta")
+
+	repos, err := tdb.MetaDB.GetMultipleRepoMeta(ctx, filter)
+	tracing.EndSpan(span, err)
+
+	return repos, err
+}
+
+func (tdb *TracedMetaDB) UpdateSignaturesValidity(ctx context.Context, repo string,
+	manifestDigest godigest.Digest,
+) error {
+	ctx, span := startSpan(ctx, "UpdateSignaturesValidity")
+	span.SetAttributes(tracing.RepositoryKey.String(repo), tracing.ReferenceKey.String(manifestDigest.String()))
+
+	err := tdb.MetaDB.UpdateSignaturesValidity(ctx, repo, manifestDigest)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) FilterImageMeta(ctx context.Context, digests []string,
+) (map[mTypes.ImageDigest]mTypes.ImageMeta, error) {
+	ctx, span := startSpan(ctx, "FilterImageMeta")
+
+	images, err := tdb.MetaDB.FilterImageMeta(ctx, digests)
+	tracing.EndSpan(span, err)
+
+	return images, err
+}
+
+func (tdb *TracedMetaDB) GetStarredRepos(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "GetStarredRepos")
+
+	repos, err := tdb.MetaDB.GetStarredRepos(ctx)
+	tracing.EndSpan(span, err)
+
+	return repos, err
+}
+
+func (tdb *TracedMetaDB) GetBookmarkedRepos(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "GetBookmarkedRepos")
+
+	repos, err := tdb.MetaDB.GetBookmarkedRepos(ctx)
+	tracing.EndSpan(span, err)
+
+	return repos, err
+}
+
+func (tdb *TracedMetaDB) ToggleStarRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	ctx, span := startSpan(ctx, "ToggleStarRepo")
+	span.SetAttributes(tracing.RepositoryKey.String(reponame))
+
+	state, err := tdb.MetaDB.ToggleStarRepo(ctx, reponame)
+	tracing.EndSpan(span, err)
+
+	return state, err
+}
+
+func (tdb *TracedMetaDB) ToggleBookmarkRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	ctx, span := startSpan(ctx, "ToggleBookmarkRepo")
+	span.SetAttributes(tracing.RepositoryKey.String(reponame))
+
+	state, err := tdb.MetaDB.ToggleBookmarkRepo(ctx, reponame)
+	tracing.EndSpan(span, err)
+
+	return state, err
+}
+
+func (tdb *TracedMetaDB) GetUserData(ctx context.Context) (mTypes.UserData, error) {
+	ctx, span := startSpan(ctx, "GetUserData")
+
+	userData, err := tdb.MetaDB.GetUserData(ctx)
+	tracing.EndSpan(span, err)
+
+	return userData, err
+}
+
+func (tdb *TracedMetaDB) SetUserData(ctx context.Context, userData mTypes.UserData) error {
+	ctx, span := startSpan(ctx, "SetUserData")
+
+	err := tdb.MetaDB.SetUserData(ctx, userData)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) SetUserGroups(ctx context.Context, groups []string) error {
+	ctx, span := startSpan(ctx, "SetUserGroups")
+
+	err := tdb.MetaDB.SetUserGroups(ctx, groups)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) GetUserGroups(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "GetUserGroups")
+
+	groups, err := tdb.MetaDB.GetUserGroups(ctx)
+	tracing.EndSpan(span, err)
+
+	return groups, err
+}
+
+func (tdb *TracedMetaDB) DeleteUserData(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "DeleteUserData")
+
+	err := tdb.MetaDB.DeleteUserData(ctx)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) GetUserAPIKeys(ctx context.Context) ([]mTypes.APIKeyDetails, error) {
+	ctx, span := startSpan(ctx, "GetUserAPIKeys")
+
+	apiKeys, err := tdb.MetaDB.GetUserAPIKeys(ctx)
+	tracing.EndSpan(span, err)
+
+	return apiKeys, err
+}
+
+func (tdb *TracedMetaDB) AddUserAPIKey(ctx context.Context, hashedKey string,
+	apiKeyDetails *mTypes.APIKeyDetails,
+) error {
+	ctx, span := startSpan(ctx, "AddUserAPIKey")
+
+	err := tdb.MetaDB.AddUserAPIKey(ctx, hashedKey, apiKeyDetails)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) IsAPIKeyExpired(ctx context.Context, hashedKey string) (bool, error) {
+	ctx, span := startSpan(ctx, "IsAPIKeyExpired")
+
+	expired, err := tdb.MetaDB.IsAPIKeyExpired(ctx, hashedKey)
+	tracing.EndSpan(span, err)
+
+	return expired, err
+}
+
+func (tdb *TracedMetaDB) UpdateUserAPIKeyLastUsed(ctx context.Context, hashedKey string) error {
+	ctx, span := startSpan(ctx, "UpdateUserAPIKeyLastUsed")
+
+	err := tdb.MetaDB.UpdateUserAPIKeyLastUsed(ctx, hashedKey)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (tdb *TracedMetaDB) DeleteUserAPIKey(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "DeleteUserAPIKey")
+
+	err := tdb.MetaDB.DeleteUserAPIKey(ctx, id)
+	tracing.EndSpan(span, err)
+
+	return err
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	godigest "github.com/opencontainers/go-digest"
@@ -100,6 +101,30 @@ type MetaDB interface { //nolint:interfacebloat
 	// AddManifestSignature adds signature metadata to a given manifest in the database
 	AddManifestSignature(repo string, signedManifestDigest godigest.Digest, sm SignatureMetadata) error
 
+	// AddManifestSbom adds an SBOM to a given manifest in the database, replacing the one previously added from
+	// the same SBOM manifest. The packages it lists are stored apart from the repo meta, keyed by the SBOM
+	// manifest digest, and indexed by package name
+	AddManifestSbom(repo string, subjectDigest godigest.Digest, sbom SbomInfo, packages []SbomPackage) error
+
+	// GetSbomPackages returns the packages listed by an SBOM manifest
+	GetSbomPackages(sbomDigest godigest.Digest) ([]SbomPackage, error)
+
+	// GetSbomsWithPackage returns the digests of the SBOM manifests listing a package with the given name or purl,
+	// with or without its version part
+	GetSbomsWithPackage(name string) ([]godigest.Digest, error)
+
+	// SetCVEScanResult stores the result of the CVE scan of a manifest or an index, replacing the previous one
+	SetCVEScanResult(digest godigest.Digest, result CVEScanResult) error
+
+	// GetCVEScanResult returns the stored result of the last CVE scan of a manifest or an index
+	GetCVEScanResult(digest godigest.Digest) (CVEScanResult, error)
+
+	// DeleteCVEScanResult deletes the stored result of the CVE scan of a manifest or an index
+	DeleteCVEScanResult(digest godigest.Digest) error
+
+	// DeleteCVEScanResults deletes the stored CVE scan results for which isStale returns true
+	DeleteCVEScanResults(isStale func(result CVEScanResult) bool) error
+
 	// DeleteSignature deletes signature metadata to a given manifest from the database
 	DeleteSignature(repo string, signedManifestDigest godigest.Digest, sigMeta SignatureMetadata) error
 
@@ -112,6 +137,9 @@ type MetaDB interface { //nolint:interfacebloat
 	// DecrementRepoStars subtracts 1 from the star count of an image
 	DecrementRepoStars(repo string) error
 
+	// SetRepoProxyCache marks a repo as populated by the sync proxy cache from the given upstream
+	SetRepoProxyCache(repo string, upstream string) error
+
 	// SetRepoMeta sets RepoMetadata for a given repo in the database
 	// should NEVER be used in production as both GetRepoMeta and SetRepoMeta
 	// should be locked for the duration of the entire transaction at a higher level in the app
@@ -196,6 +224,31 @@ type UserDB interface { //nolint:interfacebloat
 	UpdateUserAPIKeyLastUsed(ctx context.Context, hashedKey string) error
 
 	DeleteUserAPIKey(ctx context.Context, id string) error
+
+	// AddRobotAccount stores a new robot account, it fails if an account with the same name already exists
+	AddRobotAccount(robot RobotAccount) error
+
+	// UpdateRobotAccount replaces an existing robot account, eg. after its secret is rotated
+	UpdateRobotAccount(robot RobotAccount) error
+
+	// GetRobotAccount returns the robot account with the given name
+	GetRobotAccount(name string) (RobotAccount, error)
+
+	// GetRobotAccounts returns all the robot accounts, sorted by name
+	GetRobotAccounts() ([]RobotAccount, error)
+
+	// DeleteRobotAccount removes a robot account, deleting a missing account is not an error
+	DeleteRobotAccount(name string) error
+
+	// AddAccessControlPolicies stores a new version of the access control policies, its version must follow
+	// the latest stored version, otherwise ErrAccessControlPoliciesConflict is returned
+	AddAccessControlPolicies(policies AccessControlPolicies) error
+
+	// GetAccessControlPolicies returns the given version of the access control policies, the latest one if 0
+	GetAccessControlPolicies(version int) (AccessControlPolicies, error)
+
+	// GetAccessControlPoliciesHistory returns all the versions of the access control policies, oldest first
+	GetAccessControlPoliciesHistory() ([]AccessControlPolicies, error)
 }
 
 type (
@@ -206,8 +259,7 @@ type (
 
 type ImageTrustStore interface {
 	VerifySignature(
-		signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, imageMeta ImageMeta,
-		repo string,
+		signatureType string, layerInfo LayerInfo, manifestDigest godigest.Digest, imageMeta ImageMeta, repo string,
 	) (Author, ExpiryDate, Validity, error)
 }
 
@@ -240,6 +292,7 @@ type RepoMeta struct {
 	Statistics map[ImageDigest]DescriptorStatistics
 	Signatures map[ImageDigest]ManifestSignatures
 	Referrers  map[ImageDigest][]ReferrerInfo
+	Sboms      map[ImageDigest][]SbomInfo
 
 	LastUpdatedImage *LastUpdatedImage
 	Platforms        []ispec.Platform
@@ -252,6 +305,10 @@ type RepoMeta struct {
 
 	StarCount     int
 	DownloadCount int
+
+	// IsProxyCache is set for repos populated by the sync proxy cache from Upstream
+	IsProxyCache bool
+	Upstream     string
 }
 
 // FullImageMeta is a condensed structure of all information needed about an image when searching MetaDB.
@@ -269,6 +326,7 @@ type FullImageMeta struct {
 	Referrers  []ReferrerInfo
 	Statistics DescriptorStatistics
 	Signatures ManifestSignatures
+	Sboms      []SbomInfo
 }
 
 type FullManifestMeta struct {
@@ -277,6 +335,7 @@ type FullManifestMeta struct {
 	Referrers  []ReferrerInfo
 	Statistics DescriptorStatistics
 	Signatures ManifestSignatures
+	Sboms      []SbomInfo
 }
 
 type LastUpdatedImage struct {
@@ -318,6 +377,10 @@ type LayerInfo struct {
 	SignatureKey string
 	Signer       string
 	Date         time.Time
+	// keyless cosign signatures carry the signing certificate, its chain and the transparency log bundle
+	Certificate      string
+	CertificateChain string
+	Bundle           string
 }
 
 type SignatureInfo struct {
@@ -325,6 +388,20 @@ type SignatureInfo struct {
 	LayersInfo              []LayerInfo
 }
 
+// SbomInfo describes an SPDX or CycloneDX SBOM referring to a manifest, the packages it lists are read
+// with GetSbomPackages.
+type SbomInfo struct {
+	SbomManifestDigest string
+	Format             string
+	PackageCount       int
+}
+
+type SbomPackage struct {
+	Name    string
+	Version string
+	PURL    string
+}
+
 type SignatureMetadata struct {
 	SignatureType   string
 	SignatureDigest string
@@ -371,4 +448,64 @@ type APIKeyDetails struct {
 	Label          string    `json:"label"`
 	Scopes         []string  `json:"scopes"`
 	UUID           string    `json:"uuid"`
+	// Repositories and Actions restrict the key to a subset of the permissions of its owner,
+	// an empty list doesn't restrict anything
+	Repositories []string `json:"repositories"` // glob patterns
+	Actions      []string `json:"actions"`      // subset of read, create, update and delete
+}
+
+// RobotAccount is a non-human identity managed through the API. It authenticates with a secret
+// and is only allowed the actions on the repositories of its scope, the access control policies don't apply to it.
+type RobotAccount struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	HashedSecret string    `json:"hashedSecret"`
+	Repositories []string  `json:"repositories"` // glob patterns
+	Actions      []string  `json:"actions"`      // subset of read, create, update and delete
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	// the secret is replaced when rotated, the zero expiration date means the secret never expires
+	SecretCreatedAt      time.Time `json:"secretCreatedAt"`
+	SecretExpirationDate time.Time `json:"secretExpirationDate"`
+}
+
+func (robot RobotAccount) IsSecretExpired() bool {
+	return !robot.SecretExpirationDate.IsZero() && time.Now().After(robot.SecretExpirationDate)
+}
+
+// AccessControlPolicies is a version of the access control policies managed through the API, the latest
+// version is the one applied. Versions are never modified, a rollback stores a copy of an older version.
+type AccessControlPolicies struct {
+	Version int `json:"version"`
+	// repositories, adminPolicy and groups, the same way they are written in the config file
+	Policies  json.RawMessage `json:"policies"`
+	CreatedBy string          `json:"createdBy"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Comment   string          `json:"comment"`
+}
+
+// CVEScanResult holds the vulnerabilities found by scanning a manifest or an index. The schema version
+// and the build time of the vulnerability DB used for the scan tell if the result is stale, once a newer DB
+// is downloaded.
+type CVEScanResult struct {
+	ScannedAt   time.Time
+	DBVersion   string
+	DBUpdatedAt time.Time
+	CVEs        map[string]CVE
+}
+
+type CVE struct {
+	ID          string
+	Description string
+	Severity    string
+	Title       string
+	Reference   string
+	PackageList []CVEPackage
+}
+
+type CVEPackage struct {
+	Name             string
+	PackagePath      string
+	InstalledVersion string
+	FixedVersion     string
 }
@@ -3,6 +3,7 @@ package uac
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	glob "github.com/bmatcuk/doublestar/v4" //nolint:gci
 
@@ -36,6 +37,15 @@ type UserAuthzInfo struct {
 type UserAuthnInfo struct {
 	groups   []string
 	username string
+	isRobot  bool
+	// set when the user authenticated with an api key or a robot account restricted to some repositories
+	// and/or actions
+	scope *Scope
+}
+
+type Scope struct {
+	repositories []string
+	actions      []string
 }
 
 func NewUserAccessControl() *UserAccessControl {
@@ -47,7 +57,10 @@ func NewUserAccessControl() *UserAccessControl {
 		// if no authn enabled on server this will be nil
 		authnInfo: nil,
 		// actions type
-		behaviourActions: []string{constants.DetectManifestCollisionPermission},
+		behaviourActions: []string{
+			constants.DetectManifestCollisionPermission,
+			constants.OverrideImmutableTagsPermission,
+		},
 		methodActions: []string{
 			constants.ReadPermission,
 			constants.CreatePermission,
@@ -91,6 +104,81 @@ func (uac *UserAccessControl) GetGroups() []string {
 	return uac.authnInfo.groups
 }
 
+/*
+SetScope restricts the user to the given repositories (glob patterns) and actions,
+used when authenticating with a scoped api key or a robot account. Empty lists don't restrict anything.
+*/
+func (uac *UserAccessControl) SetScope(repositories, actions []string) {
+	if len(repositories) == 0 && len(actions) == 0 {
+		return
+	}
+
+	if uac.authnInfo == nil {
+		uac.authnInfo = &UserAuthnInfo{}
+	}
+
+	uac.authnInfo.scope = &Scope{
+		repositories: repositories,
+		actions:      actions,
+	}
+}
+
+// GetScope returns the repositories and actions the credentials used by the request are restricted to,
+// both are empty if they aren't restricted.
+func (uac *UserAccessControl) GetScope() ([]string, []string) {
+	if !uac.IsScoped() {
+		return []string{}, []string{}
+	}
+
+	return uac.authnInfo.scope.repositories, uac.authnInfo.scope.actions
+}
+
+// IsScoped returns whether the user authenticated with credentials restricted to some repositories or actions.
+func (uac *UserAccessControl) IsScoped() bool {
+	return uac.authnInfo != nil && uac.authnInfo.scope != nil
+}
+
+/*
+IsInScope returns whether the credentials used by the request allow 'action' on 'repository',
+behaviour actions are only restricted by the repositories of the scope.
+*/
+func (uac *UserAccessControl) IsInScope(action, repository string) bool {
+	if !uac.IsScoped() {
+		return true
+	}
+
+	scope := uac.authnInfo.scope
+
+	if len(scope.actions) > 0 && uac.isMethodAction(action) && !slices.Contains(scope.actions, action) {
+		return false
+	}
+
+	if len(scope.repositories) == 0 {
+		return true
+	}
+
+	for _, pattern := range scope.repositories {
+		if matched, err := glob.Match(pattern, repository); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetIsRobot marks the user as a robot account, which is only granted the actions of its scope.
+func (uac *UserAccessControl) SetIsRobot(isRobot bool) {
+	if uac.authnInfo == nil {
+		uac.authnInfo = &UserAuthnInfo{}
+	}
+
+	uac.authnInfo.isRobot = isRobot
+}
+
+func (uac *UserAccessControl) IsRobot() bool {
+	return uac.authnInfo != nil && uac.authnInfo.isRobot
+}
+
 func (uac *UserAccessControl) IsAnonymous() bool {
 	if uac.authnInfo == nil {
 		return true
@@ -152,6 +240,11 @@ func (uac *UserAccessControl) SetGlobPatterns(action string, patterns map[string
 Can returns whether or not the user/anonymous who made the request has 'action' permission on 'repository'.
 */
 func (uac *UserAccessControl) Can(action, repository string) bool {
+	// api keys and robot accounts can't do more than their scope allows, whatever the policies say
+	if !uac.IsInScope(action, repository) {
+		return false
+	}
+
 	var defaultRet bool
 	if uac.isBehaviourAction(action) {
 		defaultRet = false
@@ -159,7 +252,8 @@ func (uac *UserAccessControl) Can(action, repository string) bool {
 		defaultRet = true
 	}
 
-	if uac.IsAdmin() {
+	// admins can't override immutable tags unless the admin policy allows it
+	if uac.IsAdmin() && action != constants.OverrideImmutableTagsPermission {
 		return defaultRet
 	}
 
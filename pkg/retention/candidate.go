@@ -1,6 +1,9 @@
 package retention
 
 import (
+	"context"
+	"time"
+
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
@@ -51,3 +54,128 @@ func GetCandidatesFromIndex(index ispec.Index) []*types.Candidate {
 
 	return candidates
 }
+
+// adds the annotations, layers and blob sizes of the images to their candidates.
+func (p policyManager) addImageMeta(ctx context.Context, candidates []*types.Candidate) error {
+	digests := make([]string, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		digests = append(digests, candidate.DigestStr)
+	}
+
+	imageMetaMap, err := p.metaDB.FilterImageMeta(ctx, digests)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		imageMeta, ok := imageMetaMap[candidate.DigestStr]
+		if !ok {
+			continue
+		}
+
+		candidate.Annotations = map[string]string{}
+		candidate.Blobs = map[string]int64{imageMeta.Digest.String(): imageMeta.Size}
+
+		// the annotations of an index take precedence over the ones of its manifests
+		if imageMeta.Index != nil {
+			for key, value := range imageMeta.Index.Annotations {
+				candidate.Annotations[key] = value
+			}
+		}
+
+		for _, manifestMeta := range imageMeta.Manifests {
+			for key, value := range manifestMeta.Manifest.Annotations {
+				if _, ok := candidate.Annotations[key]; !ok {
+					candidate.Annotations[key] = value
+				}
+			}
+
+			candidate.Blobs[manifestMeta.Digest.String()] = manifestMeta.Size
+			candidate.Blobs[manifestMeta.Manifest.Config.Digest.String()] = manifestMeta.Manifest.Config.Size
+
+			for _, layer := range manifestMeta.Manifest.Layers {
+				candidate.Blobs[layer.Digest.String()] = layer.Size
+			}
+		}
+
+		candidate.Layers = getLayers(imageMeta)
+	}
+
+	return nil
+}
+
+// returns the layer digests of each manifest of the image.
+func getLayers(imageMeta mTypes.ImageMeta) [][]string {
+	layers := make([][]string, 0, len(imageMeta.Manifests))
+
+	for _, manifestMeta := range imageMeta.Manifests {
+		manifestLayers := make([]string, 0, len(manifestMeta.Manifest.Layers))
+
+		for _, layer := range manifestMeta.Manifest.Layers {
+			manifestLayers = append(manifestLayers, layer.Digest.String())
+		}
+
+		layers = append(layers, manifestLayers)
+	}
+
+	return layers
+}
+
+// returns the tagged images of the other repos built on top of any of the candidates, the same way the
+// BaseImageList search query looks for the images using a base image in all the repos.
+func (p policyManager) getImagesOfOtherRepos(ctx context.Context, repo string, candidates []*types.Candidate,
+) ([]*types.Candidate, error) {
+	images := make([]*types.Candidate, 0)
+
+	if p.metaDB == nil {
+		return images, nil
+	}
+
+	imageMetaList, err := p.metaDB.FilterTags(ctx,
+		func(otherRepo, tag string) bool {
+			return otherRepo != repo
+		},
+		func(repoMeta mTypes.RepoMeta, imageMeta mTypes.ImageMeta) bool {
+			image := &types.Candidate{DigestStr: imageMeta.Digest.String(), Layers: getLayers(imageMeta)}
+
+			for _, candidate := range candidates {
+				if isBaseImage(candidate, image) {
+					return true
+				}
+			}
+
+			return false
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fullImageMeta := range imageMetaList {
+		imageMeta := mTypes.ImageMeta{Digest: fullImageMeta.Digest}
+
+		for _, manifestMeta := range fullImageMeta.Manifests {
+			imageMeta.Manifests = append(imageMeta.Manifests, manifestMeta.ManifestMeta)
+		}
+
+		images = append(images, &types.Candidate{DigestStr: imageMeta.Digest.String(), Layers: getLayers(imageMeta)})
+	}
+
+	return images, nil
+}
+
+// marks the candidates having a trusted signature, same as the search extension a signature is trusted
+// if its signer was verified and its certificate is not expired.
+func addSignatures(repoMeta mTypes.RepoMeta, candidates []*types.Candidate) {
+	for _, candidate := range candidates {
+		for _, signatures := range repoMeta.Signatures[candidate.DigestStr] {
+			for _, signature := range signatures {
+				for _, layer := range signature.LayersInfo {
+					if layer.Signer != "" && (layer.Date.IsZero() || !time.Now().After(layer.Date)) {
+						candidate.IsSigned = true
+					}
+				}
+			}
+		}
+	}
+}
@@ -3,6 +3,8 @@ package retention
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	glob "github.com/bmatcuk/doublestar/v4"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -19,26 +21,39 @@ const (
 	// reasons for gc.
 	filteredByTagRules = "didn't meet any tag retention rule"
 	filteredByTagNames = "didn't meet any tag 'patterns' rules"
+	filteredByEviction = "didn't meet proxy cache eviction rule"
+	filteredByMaxSize  = "exceeded the maxSize tag retention limit"
 	// reasons for retention.
-	retainedStrFormat = "retained by %s policy"
+	statisticsNotFound = "tag statistics not found"
+	imageMetaNotFound  = "image metadata not found"
+	noTagRetention     = "no keepTags policy applies to the repository"
+	retainedStrFormat  = "retained by %s policy"
 )
 
 type candidatesRules struct {
 	candidates []*types.Candidate
 	// tag retention rules
 	rules []types.Rule
+	// base images of the retained candidates are also retained
+	keepBaseImages bool
+	// limit of the total size of the candidates retained by the rules, 0 if there is none
+	maxSize int64
 }
 
 type policyManager struct {
 	config   config.ImageRetention
+	metaDB   mTypes.MetaDB
 	regex    *RegexMatcher
 	log      zlog.Logger
 	auditLog *zlog.Logger
 }
 
-func NewPolicyManager(config config.ImageRetention, log zlog.Logger, auditLog *zlog.Logger) policyManager {
+func NewPolicyManager(config config.ImageRetention, metaDB mTypes.MetaDB, log zlog.Logger,
+	auditLog *zlog.Logger,
+) policyManager {
 	return policyManager{
 		config:   config,
+		metaDB:   metaDB,
 		regex:    NewRegexMatcher(),
 		log:      log,
 		auditLog: auditLog,
@@ -76,6 +91,10 @@ func (p policyManager) HasTagRetention(repo string) bool {
 	return false
 }
 
+func (p policyManager) HasProxyCacheEviction() bool {
+	return p.config.ProxyCache != nil && p.config.ProxyCache.EvictNotPulledWithin > 0
+}
+
 func (p policyManager) getRules(tagPolicy config.KeepTagsPolicy) []types.Rule {
 	rules := make([]types.Rule, 0)
 
@@ -95,22 +114,54 @@ func (p policyManager) getRules(tagPolicy config.KeepTagsPolicy) []types.Rule {
 		rules = append(rules, NewDaysPush(*tagPolicy.PushedWithin))
 	}
 
+	if len(tagPolicy.Annotations) > 0 {
+		rules = append(rules, NewAnnotations(tagPolicy.Annotations, p.regex))
+	}
+
+	if tagPolicy.Signed {
+		rules = append(rules, NewSigned())
+	}
+
 	return rules
 }
 
+// returns true if the tag rules of the repo policy need the image metadata (annotations, layers and sizes).
+func (p policyManager) needsImageMeta(repo string) bool {
+	policy, err := p.getRepoPolicy(repo)
+	if err != nil {
+		return false
+	}
+
+	for _, tagPolicy := range policy.KeepTags {
+		if len(tagPolicy.Annotations) > 0 || tagPolicy.KeepBaseImages || tagPolicy.MaxSize > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetRetainedTagsFromIndex uses only index information to match tags against patterns and determine
-// a list of tags to be retained. This function is to be used only in case MetaDB information is not available,
-// if the DB is not instantiated.
-func (p policyManager) GetRetainedTagsFromIndex(ctx context.Context, repo string, index ispec.Index) []string {
+// a list of tags to be retained, the other tags are mapped to the rule which caused their removal.
+// This function is to be used only in case MetaDB information is not available, if the DB is not instantiated.
+func (p policyManager) GetRetainedTagsFromIndex(ctx context.Context, repo string, index ispec.Index,
+) ([]string, map[string]string) {
+	return p.getRetainedTagsFromIndex(ctx, repo, index, nil)
+}
+
+func (p policyManager) getRetainedTagsFromIndex(ctx context.Context, repo string, index ispec.Index,
+	report *types.RetentionReport,
+) ([]string, map[string]string) {
 	candidates := GetCandidatesFromIndex(index)
 	retainTags := make([]string, 0)
+	deleteRules := make(map[string]string)
 
 	// group all tags by tag policy
 	grouped := p.groupCandidatesByTagPolicy(repo, candidates)
 
 	for _, candidates := range grouped {
 		if zcommon.IsContextDone(ctx) {
-			return nil
+			return nil, nil
 		}
 
 		for _, retainCandidate := range candidates.candidates {
@@ -118,7 +169,7 @@ func (p policyManager) GetRetainedTagsFromIndex(ctx context.Context, repo string
 			if !zcommon.Contains(retainTags, retainCandidate.Tag) {
 				reason := fmt.Sprintf(retainedStrFormat, retainCandidate.RetainedBy)
 
-				logAction(repo, "keep", reason, retainCandidate, p.config.DryRun, &p.log)
+				p.recordAction(report, repo, "keep", reason, retainCandidate)
 
 				retainTags = append(retainTags, retainCandidate.Tag)
 			}
@@ -128,31 +179,56 @@ func (p policyManager) GetRetainedTagsFromIndex(ctx context.Context, repo string
 	// log tags which will be removed
 	for _, candidate := range candidates {
 		if !zcommon.Contains(retainTags, candidate.Tag) {
-			logAction(repo, "delete", filteredByTagNames, candidate, p.config.DryRun, &p.log)
+			p.recordAction(report, repo, "delete", filteredByTagNames, candidate)
 
-			if p.auditLog != nil {
-				logAction(repo, "delete", filteredByTagNames, candidate, p.config.DryRun, p.auditLog)
-			}
+			deleteRules[candidate.Tag] = patternsName
 		}
 	}
 
-	return retainTags
+	return retainTags, deleteRules
 }
 
-// GetRetainedTagsFromMetaDB uses MetaDB information to apply retention rules and obtain a list of tags to be retained.
+// GetRetainedTagsFromMetaDB uses MetaDB information to apply retention rules and obtain a list of tags to be retained,
+// the other tags are mapped to the rule which caused their removal.
 func (p policyManager) GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta mTypes.RepoMeta,
 	index ispec.Index,
-) []string {
+) ([]string, map[string]string) {
+	return p.getRetainedTagsFromMetaDB(ctx, repoMeta, index, nil)
+}
+
+func (p policyManager) getRetainedTagsFromMetaDB(ctx context.Context, repoMeta mTypes.RepoMeta,
+	index ispec.Index, report *types.RetentionReport,
+) ([]string, map[string]string) {
 	repo := repoMeta.Name
 
 	matchedByName := make([]string, 0)
+	exceededMaxSize := make([]string, 0)
+	// the rules which caused the removal of the tags matched by name
+	deleteRules := make(map[string]string)
 
 	candidates := GetCandidates(repoMeta)
 	retainTags := make([]string, 0)
+	retainedCandidates := make([]*types.Candidate, 0)
 
 	// we need to make sure tags for which we can not find statistics in repoDB are not removed
 	actualTags := getIndexTags(index)
 
+	if p.metaDB != nil && p.needsImageMeta(repo) {
+		if err := p.addImageMeta(ctx, candidates); err != nil {
+			// without image metadata the rules can't be applied, so no tag is removed
+			p.log.Error().Err(err).Str("module", "retention").Str("repository", repo).
+				Msg("failed to get image metadata, will keep all tags")
+
+			for _, candidate := range GetCandidatesFromIndex(index) {
+				p.recordAction(report, repo, "keep", imageMetaNotFound, candidate)
+			}
+
+			return actualTags, map[string]string{}
+		}
+	}
+
+	addSignatures(repoMeta, candidates)
+
 	// find tags which are not in candidates list, if they are not in repoDB we want to keep them
 	for _, tag := range actualTags {
 		found := false
@@ -164,12 +240,16 @@ func (p policyManager) GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta m
 		}
 
 		if !found {
-			p.log.Info().Str("module", "retention").
-				Bool("dry-run", p.config.DryRun).
-				Str("repository", repo).
-				Str("tag", tag).
-				Str("decision", "keep").
-				Str("reason", "tag statistics not found").Msg("will keep tag")
+			if report != nil {
+				report.Tags = append(report.Tags, types.TagDecision{Tag: tag, Decision: "keep", Reason: statisticsNotFound})
+			} else {
+				p.log.Info().Str("module", "retention").
+					Bool("dry-run", p.config.DryRun).
+					Str("repository", repo).
+					Str("tag", tag).
+					Str("decision", "keep").
+					Str("reason", statisticsNotFound).Msg("will keep tag")
+			}
 
 			retainTags = append(retainTags, tag)
 		}
@@ -178,9 +258,14 @@ func (p policyManager) GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta m
 	// group all tags by tag policy
 	grouped := p.groupCandidatesByTagPolicy(repo, candidates)
 
-	for _, candidates := range grouped {
+	// the candidates retained by each tag policy, before its size limit is applied
+	retainedByPolicy := make(map[int][]*types.Candidate)
+	// the reasons of the candidates kept without being retained by a rule
+	keepReasons := make(map[*types.Candidate]string)
+
+	for policyID, candidates := range grouped {
 		if zcommon.IsContextDone(ctx) {
-			return nil
+			return nil, nil
 		}
 
 		retainCandidates := candidates.candidates // copy
@@ -201,17 +286,90 @@ func (p policyManager) GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta m
 		}
 
 		// if we applied any rule
-		if len(rules) > 0 {
+		if len(rules) > 0 || candidates.keepBaseImages {
 			retainCandidates = rulesCandidates
+
+			// the candidates not retained by any rule are removed because of all of them
+			for _, candidate := range candidates.candidates {
+				if !slices.Contains(retainCandidates, candidate) {
+					deleteRules[candidate.Tag] = getRuleNames(candidates)
+				}
+			}
 		} // else we retain just the one matching name rule
 
+		retainedByPolicy[policyID] = dedupeCandidates(retainCandidates)
+		retainedCandidates = append(retainedCandidates, retainedByPolicy[policyID]...)
+	}
+
+	// base images are retained once all the other rules were applied, as they may be used by images retained
+	// by any tag policy or by the images of other repos
+	notRetained := make(map[int][]*types.Candidate)
+	baseImageCandidates := make([]*types.Candidate, 0)
+
+	for policyID, candidates := range grouped {
+		if !candidates.keepBaseImages {
+			continue
+		}
+
+		for _, candidate := range candidates.candidates {
+			if !slices.Contains(retainedCandidates, candidate) {
+				notRetained[policyID] = append(notRetained[policyID], candidate)
+				baseImageCandidates = append(baseImageCandidates, candidate)
+			}
+		}
+	}
+
+	if len(baseImageCandidates) > 0 {
+		otherImages, err := p.getImagesOfOtherRepos(ctx, repo, baseImageCandidates)
+		if err != nil {
+			// without the images of the other repos the base images can't be found, so none of them is removed
+			p.log.Error().Err(err).Str("module", "retention").Str("repository", repo).
+				Msg("failed to get the images of other repositories, will keep base image candidates")
+
+			for policyID, candidates := range notRetained {
+				for _, candidate := range candidates {
+					keepReasons[candidate] = imageMetaNotFound
+				}
+
+				retainedByPolicy[policyID] = append(retainedByPolicy[policyID], candidates...)
+			}
+		} else {
+			baseImages := NewBaseImages(append(retainedCandidates, otherImages...))
+
+			for policyID, candidates := range notRetained {
+				retainedByPolicy[policyID] = append(retainedByPolicy[policyID], baseImages.Perform(candidates)...)
+			}
+		}
+	}
+
+	for policyID, candidates := range grouped {
+		retainCandidates := retainedByPolicy[policyID]
+
+		// the size limit applies to all the retained candidates, base images included,
+		// the oldest ones are removed first
+		if candidates.maxSize > 0 {
+			limit := NewMaxSize(candidates.maxSize)
+			limited := limit.Perform(retainCandidates)
+
+			for _, candidate := range retainCandidates {
+				if !slices.Contains(limited, candidate) {
+					exceededMaxSize = append(exceededMaxSize, candidate.Tag)
+					deleteRules[candidate.Tag] = limit.Name()
+				}
+			}
+
+			retainCandidates = limited
+		}
+
 		for _, retainCandidate := range retainCandidates {
 			// there may be duplicates
 			if !zcommon.Contains(retainTags, retainCandidate.Tag) {
-				// format reason log msg
-				reason := fmt.Sprintf(retainedStrFormat, retainCandidate.RetainedBy)
+				reason, ok := keepReasons[retainCandidate]
+				if !ok {
+					reason = fmt.Sprintf(retainedStrFormat, retainCandidate.RetainedBy)
+				}
 
-				logAction(repo, "keep", reason, retainCandidate, p.config.DryRun, &p.log)
+				p.recordAction(report, repo, "keep", reason, retainCandidate)
 
 				retainTags = append(retainTags, retainCandidate.Tag)
 			}
@@ -222,18 +380,83 @@ func (p policyManager) GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta m
 	for _, candidateInfo := range candidates {
 		if !zcommon.Contains(retainTags, candidateInfo.Tag) {
 			var reason string
-			if zcommon.Contains(matchedByName, candidateInfo.Tag) {
+
+			switch {
+			case zcommon.Contains(exceededMaxSize, candidateInfo.Tag):
+				reason = filteredByMaxSize
+			case zcommon.Contains(matchedByName, candidateInfo.Tag):
 				reason = filteredByTagRules
-			} else {
+			default:
 				reason = filteredByTagNames
+				deleteRules[candidateInfo.Tag] = patternsName
 			}
 
-			logAction(repo, "delete", reason, candidateInfo, p.config.DryRun, &p.log)
+			p.recordAction(report, repo, "delete", reason, candidateInfo)
+		}
+	}
+
+	// the tags retained as base images are no longer removed
+	for _, tag := range retainTags {
+		delete(deleteRules, tag)
+	}
+
+	return retainTags, deleteRules
+}
+
+// GetRetainedProxyCacheTags applies the proxy cache eviction rule to a repo populated by the sync proxy cache
+// and returns the tags pulled (or synced) recently enough to be retained.
+func (p policyManager) GetRetainedProxyCacheTags(ctx context.Context, repoMeta mTypes.RepoMeta,
+	index ispec.Index,
+) []string {
+	return p.getRetainedProxyCacheTags(ctx, repoMeta, index, nil)
+}
+
+func (p policyManager) getRetainedProxyCacheTags(ctx context.Context, repoMeta mTypes.RepoMeta,
+	index ispec.Index, report *types.RetentionReport,
+) []string {
+	repo := repoMeta.Name
+
+	candidates := GetCandidates(repoMeta)
+	retainTags := make([]string, 0)
+
+	// tags for which there are no statistics in metaDB are kept
+	for _, tag := range getIndexTags(index) {
+		found := false
+
+		for _, candidate := range candidates {
+			if candidate.Tag == tag {
+				found = true
 
-			if p.auditLog != nil {
-				logAction(repo, "delete", reason, candidateInfo, p.config.DryRun, p.auditLog)
+				break
 			}
 		}
+
+		if !found {
+			retainTags = append(retainTags, tag)
+		}
+	}
+
+	if zcommon.IsContextDone(ctx) {
+		return nil
+	}
+
+	// a synced image counts as pushed, so freshly cached tags are not evicted before being pulled
+	rule := NewDaysPull(p.config.ProxyCache.EvictNotPulledWithin)
+
+	for _, retainCandidate := range rule.Perform(candidates) {
+		if !zcommon.Contains(retainTags, retainCandidate.Tag) {
+			reason := fmt.Sprintf(retainedStrFormat, retainCandidate.RetainedBy)
+
+			p.recordAction(report, repo, "keep", reason, retainCandidate)
+
+			retainTags = append(retainTags, retainCandidate.Tag)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !zcommon.Contains(retainTags, candidate.Tag) {
+			p.recordAction(report, repo, "delete", filteredByEviction, candidate)
+		}
 	}
 
 	return retainTags
@@ -278,11 +501,13 @@ func (p policyManager) groupCandidatesByTagPolicy(repo string, candidates []*typ
 			continue
 		}
 
-		candidateInfo.RetainedBy = "patterns"
+		candidateInfo.RetainedBy = patternsName
 
 		if _, ok := candidatesByTagPolicy[tagPolicyID]; !ok {
 			candidatesRules := candidatesRules{candidates: []*types.Candidate{candidateInfo}}
 			candidatesRules.rules = p.getRules(tagPolicy)
+			candidatesRules.keepBaseImages = tagPolicy.KeepBaseImages
+			candidatesRules.maxSize = tagPolicy.MaxSize
 			candidatesByTagPolicy[tagPolicyID] = candidatesRules
 		} else {
 			candidatesRules := candidatesByTagPolicy[tagPolicyID]
@@ -294,8 +519,60 @@ func (p policyManager) groupCandidatesByTagPolicy(repo string, candidates []*typ
 	return candidatesByTagPolicy
 }
 
+// GetRetentionReport evaluates the retention policies the same way gc does, without logging or removing anything,
+// and returns the decision taken for each tag of the repo. If repoMeta is nil only the tag patterns are applied.
+func (p policyManager) GetRetentionReport(ctx context.Context, repo string, repoMeta *mTypes.RepoMeta,
+	index ispec.Index,
+) types.RetentionReport {
+	report := types.RetentionReport{
+		Repository:      repo,
+		DeleteUntagged:  p.HasDeleteUntagged(repo),
+		DeleteReferrers: p.HasDeleteReferrer(repo),
+		Tags:            []types.TagDecision{},
+		Manifests:       []types.ManifestDecision{},
+	}
+
+	switch {
+	case repoMeta != nil && repoMeta.IsProxyCache && p.HasProxyCacheEviction():
+		p.getRetainedProxyCacheTags(ctx, *repoMeta, index, &report)
+	case !p.HasTagRetention(repo):
+		for _, candidate := range GetCandidatesFromIndex(index) {
+			p.recordAction(&report, repo, "keep", noTagRetention, candidate)
+		}
+	case repoMeta != nil:
+		p.getRetainedTagsFromMetaDB(ctx, *repoMeta, index, &report)
+	default:
+		p.getRetainedTagsFromIndex(ctx, repo, index, &report)
+	}
+
+	return report
+}
+
+// logs the decision taken for a candidate, or adds it to the report if the policies are only evaluated.
+func (p policyManager) recordAction(report *types.RetentionReport, repo, decision, reason string,
+	candidate *types.Candidate,
+) {
+	if report != nil {
+		report.Tags = append(report.Tags, types.TagDecision{
+			Tag:       candidate.Tag,
+			Digest:    candidate.DigestStr,
+			MediaType: candidate.MediaType,
+			Decision:  decision,
+			Reason:    reason,
+		})
+
+		return
+	}
+
+	logAction(repo, decision, reason, candidate, p.config.DryRun, &p.log)
+
+	if decision == "delete" && p.auditLog != nil {
+		logAction(repo, decision, reason, candidate, p.config.DryRun, p.auditLog)
+	}
+}
+
 func logAction(repo, decision, reason string, candidate *types.Candidate, dryRun bool, log *zlog.Logger) {
-	log.Info().Str("module", "retention").
+	event := log.Info().Str("module", "retention").
 		Bool("dry-run", dryRun).
 		Str("repository", repo).
 		Str("mediaType", candidate.MediaType).
@@ -303,10 +580,51 @@ func logAction(repo, decision, reason string, candidate *types.Candidate, dryRun
 		Str("tag", candidate.Tag).
 		Str("lastPullTimestamp", candidate.PullTimestamp.String()).
 		Str("pushTimestamp", candidate.PushTimestamp.String()).
-		Str("decision", decision).
+		Bool("signed", candidate.IsSigned)
+
+	// the size is known only if the image metadata was needed by the rules
+	if candidate.Blobs != nil {
+		var size int64
+
+		for _, blobSize := range candidate.Blobs {
+			size += blobSize
+		}
+
+		event = event.Int64("size", size)
+	}
+
+	event.Str("decision", decision).
 		Str("reason", reason).Msg("applied policy")
 }
 
+// returns the names of the rules of a tag policy, which all have to be unmet for a tag to be removed.
+func getRuleNames(candidates candidatesRules) string {
+	names := make([]string, 0, len(candidates.rules)+1)
+
+	for _, rule := range candidates.rules {
+		names = append(names, rule.Name())
+	}
+
+	if candidates.keepBaseImages {
+		names = append(names, baseImagesName)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// returns the candidates without the duplicates added by the rules retaining the same candidates.
+func dedupeCandidates(candidates []*types.Candidate) []*types.Candidate {
+	deduped := make([]*types.Candidate, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		if !slices.Contains(deduped, candidate) {
+			deduped = append(deduped, candidate)
+		}
+	}
+
+	return deduped
+}
+
 func getIndexTags(index ispec.Index) []string {
 	tags := make([]string, 0)
 
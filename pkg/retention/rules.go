@@ -5,18 +5,24 @@ import (
 	"sort"
 	"time"
 
+	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/retention/types"
 )
 
 const (
 	// rules name.
-	daysPullName   = "pulledWithin"
-	daysPushName   = "pushedWithin"
-	latestPullName = "mostRecentlyPulledCount"
-	latestPushName = "mostRecentlyPushedCount"
+	patternsName    = "patterns"
+	daysPullName    = "pulledWithin"
+	daysPushName    = "pushedWithin"
+	latestPullName  = "mostRecentlyPulledCount"
+	latestPushName  = "mostRecentlyPushedCount"
+	annotationsName = "annotations"
+	baseImagesName  = "keepBaseImages"
+	signedName      = "signed"
+	maxSizeName     = "maxSize"
 )
 
-// rules implementatio
+// rules implementation
 
 type DaysPull struct {
 	duration time.Duration
@@ -138,3 +144,189 @@ func (lp latestPush) Perform(candidates []*types.Candidate) []*types.Candidate {
 
 	return candidates
 }
+
+type annotations struct {
+	selectors []config.AnnotationSelector
+	regex     *RegexMatcher
+}
+
+func NewAnnotations(selectors []config.AnnotationSelector, regex *RegexMatcher) annotations {
+	return annotations{selectors: selectors, regex: regex}
+}
+
+func (a annotations) Name() string {
+	return annotationsName
+}
+
+func (a annotations) Perform(candidates []*types.Candidate) []*types.Candidate {
+	filtered := make([]*types.Candidate, 0)
+
+	for _, candidate := range candidates {
+		if a.matches(candidate.Annotations) {
+			candidate.RetainedBy = a.Name()
+
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
+
+// all selectors need to match, an empty selector value only requires the annotation to be present.
+func (a annotations) matches(annotations map[string]string) bool {
+	for _, selector := range a.selectors {
+		value, ok := annotations[selector.Key]
+		if !ok {
+			return false
+		}
+
+		if selector.Value != "" && !a.regex.MatchesListOfRegex(value, []string{selector.Value}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type signed struct{}
+
+func NewSigned() signed {
+	return signed{}
+}
+
+func (s signed) Name() string {
+	return signedName
+}
+
+func (s signed) Perform(candidates []*types.Candidate) []*types.Candidate {
+	filtered := make([]*types.Candidate, 0)
+
+	for _, candidate := range candidates {
+		if candidate.IsSigned {
+			candidate.RetainedBy = s.Name()
+
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
+
+type maxSize struct {
+	size int64
+}
+
+func NewMaxSize(size int64) maxSize {
+	return maxSize{size: size}
+}
+
+func (ms maxSize) Name() string {
+	return fmt.Sprintf("%s:%d", maxSizeName, ms.size)
+}
+
+// Perform keeps the most recently pushed candidates while the total size of their blobs is within the limit,
+// blobs shared between candidates are counted once. Once the limit is exceeded all older candidates are dropped.
+// It limits the candidates retained by the other rules, so it doesn't change the rule they were retained by.
+func (ms maxSize) Perform(candidates []*types.Candidate) []*types.Candidate {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].PushTimestamp.After(candidates[j].PushTimestamp)
+	})
+
+	filtered := make([]*types.Candidate, 0)
+	counted := make(map[string]struct{})
+
+	var total int64
+
+	for _, candidate := range candidates {
+		candidateSize := int64(0)
+
+		for digest, size := range candidate.Blobs {
+			if _, ok := counted[digest]; !ok {
+				candidateSize += size
+			}
+		}
+
+		if total+candidateSize > ms.size {
+			break
+		}
+
+		total += candidateSize
+
+		for digest := range candidate.Blobs {
+			counted[digest] = struct{}{}
+		}
+
+		filtered = append(filtered, candidate)
+	}
+
+	return filtered
+}
+
+type baseImages struct {
+	retained []*types.Candidate
+}
+
+// NewBaseImages returns a rule keeping the candidates used as a base image by any of the retained candidates.
+func NewBaseImages(retained []*types.Candidate) baseImages {
+	return baseImages{retained: retained}
+}
+
+func (bi baseImages) Name() string {
+	return baseImagesName
+}
+
+func (bi baseImages) Perform(candidates []*types.Candidate) []*types.Candidate {
+	filtered := make([]*types.Candidate, 0)
+
+	for _, candidate := range candidates {
+		for _, retained := range bi.retained {
+			if isBaseImage(candidate, retained) {
+				candidate.RetainedBy = bi.Name()
+
+				filtered = append(filtered, candidate)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// same logic as the BaseImageList search query: an image is a base image of another image
+// if all of its layers are also layers of the other image, which has more layers, for at least one of their manifests.
+func isBaseImage(base, image *types.Candidate) bool {
+	if base.DigestStr == image.DigestStr {
+		return false
+	}
+
+	for _, imageLayers := range image.Layers {
+		layers := make(map[string]struct{}, len(imageLayers))
+
+		for _, layer := range imageLayers {
+			layers[layer] = struct{}{}
+		}
+
+		for _, baseLayers := range base.Layers {
+			if len(baseLayers) == 0 || len(baseLayers) >= len(imageLayers) {
+				continue
+			}
+
+			isBase := true
+
+			for _, layer := range baseLayers {
+				if _, ok := layers[layer]; !ok {
+					isBase = false
+
+					break
+				}
+			}
+
+			if isBase {
+				return true
+			}
+		}
+	}
+
+	return false
+}
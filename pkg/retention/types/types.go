@@ -16,14 +16,49 @@ type Candidate struct {
 	PushTimestamp time.Time
 	PullTimestamp time.Time
 	RetainedBy    string
+	// populated from the image metadata only when required by the tag rules
+	Annotations map[string]string
+	Layers      [][]string       // layer digests of each manifest of the image
+	Blobs       map[string]int64 // size of each blob (manifests, configs and layers) of the image
+	IsSigned    bool
+}
+
+// TagDecision is the decision taken by the retention policies for a tag.
+type TagDecision struct {
+	Tag       string `json:"tag"`
+	Digest    string `json:"digest,omitempty"`
+	MediaType string `json:"mediaType,omitempty"`
+	Decision  string `json:"decision"` // keep or delete
+	Reason    string `json:"reason"`   // the rule which retained the tag or the reason it is deleted
+}
+
+// ManifestDecision is the decision taken by gc for an untagged manifest or a referrer.
+type ManifestDecision struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType,omitempty"`
+	Decision  string `json:"decision"` // delete
+	Reason    string `json:"reason"`   // deleteUntagged or deleteReferrers
+}
+
+type RetentionReport struct {
+	Repository      string        `json:"repository"`
+	DeleteUntagged  bool          `json:"deleteUntagged"`
+	DeleteReferrers bool          `json:"deleteReferrers"`
+	Tags            []TagDecision `json:"tags"`
+	// untagged manifests and referrers which would be removed once the tags above are removed
+	Manifests []ManifestDecision `json:"manifests"`
 }
 
 type PolicyManager interface {
 	HasDeleteReferrer(repo string) bool
 	HasDeleteUntagged(repo string) bool
 	HasTagRetention(repo string) bool
-	GetRetainedTagsFromIndex(ctx context.Context, repo string, index ispec.Index) []string
-	GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta mTypes.RepoMeta, index ispec.Index) []string
+	HasProxyCacheEviction() bool
+	GetRetainedTagsFromIndex(ctx context.Context, repo string, index ispec.Index) ([]string, map[string]string)
+	GetRetainedTagsFromMetaDB(ctx context.Context, repoMeta mTypes.RepoMeta, index ispec.Index,
+	) ([]string, map[string]string)
+	GetRetainedProxyCacheTags(ctx context.Context, repoMeta mTypes.RepoMeta, index ispec.Index) []string
+	GetRetentionReport(ctx context.Context, repo string, repoMeta *mTypes.RepoMeta, index ispec.Index) RetentionReport
 }
 
 type Rule interface {
@@ -9,8 +9,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
 	"zotregistry.dev/zot/pkg/log"
 )
 
@@ -140,11 +142,16 @@ func (scheduler *Scheduler) poolWorker(ctx context.Context) {
 					workStart = time.Now()
 				}
 
-				if err := task.DoWork(ctx); err != nil {
+				taskCtx, span := tracing.StartSpan(ctx, "scheduler."+task.Name(), tracing.TaskKey.String(task.String()))
+
+				err := task.DoWork(taskCtx)
+				if err != nil {
 					scheduler.log.Error().Int("worker", workerID).Str("task", task.String()).Err(err).
 						Msg("failed to execute task")
 				}
 
+				tracing.EndSpan(span, err)
+
 				if metricsEnabled {
 					scheduler.tasksLock.Lock()
 					scheduler.tasksDoWork--
@@ -398,24 +405,36 @@ func (scheduler *Scheduler) getTasksChannelByPriority(priority Priority) chan Ta
 }
 
 func (scheduler *Scheduler) SubmitTask(task Task, priority Priority) {
+	_ = scheduler.TrySubmitTask(task, priority)
+}
+
+// TrySubmitTask is SubmitTask for callers which need to know if the task was dropped, because the queue of
+// its priority is full or the scheduler is shutting down.
+func (scheduler *Scheduler) TrySubmitTask(task Task, priority Priority) error {
 	// get by priority the channel where the task should be added to
 	tasksQ := scheduler.getTasksChannelByPriority(priority)
 	if tasksQ == nil {
-		return
+		return zerr.ErrBadTaskPriority
 	}
 
 	// check if the scheduler is still running in order to add the task to the channel
 	if scheduler.inShutdown() {
-		return
+		return zerr.ErrSchedulerShutdown
 	}
 
 	select {
 	case tasksQ <- task:
 		scheduler.log.Info().Msg("adding a new task")
+
+		return nil
 	default:
 		if scheduler.inShutdown() {
-			return
+			return zerr.ErrSchedulerShutdown
 		}
+
+		scheduler.log.Warn().Str("task", task.String()).Msg("task queue is full, dropping the task")
+
+		return zerr.ErrSchedulerQueueFull
 	}
 }
 
@@ -12,6 +12,7 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	zerr "zotregistry.dev/zot/errors"
 	"zotregistry.dev/zot/pkg/api/config"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	"zotregistry.dev/zot/pkg/log"
@@ -361,6 +362,29 @@ func TestScheduler(t *testing.T) {
 		So(string(data), ShouldNotContainSubstring, "adding a new task")
 	})
 
+	Convey("Test tasks which can't be submitted are reported", t, func() {
+		logger := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(true, logger)
+		sch := scheduler.NewScheduler(config.New(), metrics, logger)
+
+		t := &task{log: logger, msg: "", err: false}
+		So(sch.TrySubmitTask(t, -1), ShouldEqual, zerr.ErrBadTaskPriority)
+
+		// the scheduler is not running, so the queue fills up
+		var err error
+
+		for err == nil {
+			err = sch.TrySubmitTask(t, scheduler.LowPriority)
+		}
+
+		So(err, ShouldEqual, zerr.ErrSchedulerQueueFull)
+
+		sch.RunScheduler()
+		sch.Shutdown()
+
+		So(sch.TrySubmitTask(t, scheduler.HighPriority), ShouldEqual, zerr.ErrSchedulerShutdown)
+	})
+
 	Convey("Test adding a new task when context is done", t, func() {
 		logFile, err := os.CreateTemp("", "zot-log*.txt")
 		So(err, ShouldBeNil)
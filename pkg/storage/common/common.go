@@ -600,8 +600,8 @@ func ApplyLinter(imgStore storageTypes.ImageStore, linter Lint, repo string, des
 ) (bool, error) {
 	pass := true
 
-	// we'll skip anything that's not a image manifest
-	if descriptor.MediaType != ispec.MediaTypeImageManifest {
+	// we'll skip anything that's not a image manifest or index
+	if descriptor.MediaType != ispec.MediaTypeImageManifest && descriptor.MediaType != ispec.MediaTypeImageIndex {
 		return pass, nil
 	}
 
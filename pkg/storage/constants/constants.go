@@ -25,5 +25,7 @@ const (
 	DefaultRetentionDelay   = 24 * time.Hour
 	DefaultGCInterval       = 1 * time.Hour
 	S3StorageDriverName     = "s3"
+	GCSStorageDriverName    = "gcs"
+	AzureStorageDriverName  = "azure"
 	LocalStorageDriverName  = "local"
 )
@@ -18,6 +18,7 @@ import (
 	"zotregistry.dev/zot/pkg/api/config"
 	zcommon "zotregistry.dev/zot/pkg/common"
 	"zotregistry.dev/zot/pkg/compat"
+	"zotregistry.dev/zot/pkg/extensions/events"
 	zlog "zotregistry.dev/zot/pkg/log"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 	"zotregistry.dev/zot/pkg/retention"
@@ -33,11 +34,22 @@ const (
 	SBOMTagSuffix            = "sbom"
 )
 
+// retention settings which caused a manifest to be removed, reported in the gc events. The tags removed by
+// the keepTags policies are reported with the rules they didn't meet, as named in the retention logs.
+const (
+	ruleEvictNotPulledWithin = "evictNotPulledWithin"
+	ruleDeleteReferrers      = "deleteReferrers"
+	ruleDeleteUntagged       = "deleteUntagged"
+)
+
 type Options struct {
 	// will garbage collect blobs older than Delay
 	Delay time.Duration
 
 	ImageRetention config.ImageRetention
+
+	// records an event for each removed manifest, optional
+	Events events.Recorder
 }
 
 type GarbageCollect struct {
@@ -47,6 +59,8 @@ type GarbageCollect struct {
 	policyMgr rTypes.PolicyManager
 	auditLog  *zlog.Logger
 	log       zlog.Logger
+	// set when only previewing, the manifests which would be removed are added to it instead
+	report *rTypes.RetentionReport
 }
 
 func NewGarbageCollect(imgStore types.ImageStore, metaDB mTypes.MetaDB, opts Options,
@@ -56,7 +70,7 @@ func NewGarbageCollect(imgStore types.ImageStore, metaDB mTypes.MetaDB, opts Opt
 		imgStore:  imgStore,
 		metaDB:    metaDB,
 		opts:      opts,
-		policyMgr: retention.NewPolicyManager(opts.ImageRetention, log, auditLog),
+		policyMgr: retention.NewPolicyManager(opts.ImageRetention, metaDB, log, auditLog),
 		auditLog:  auditLog,
 		log:       log,
 	}
@@ -162,6 +176,66 @@ func (gc GarbageCollect) cleanRepo(ctx context.Context, repo string) error {
 	return nil
 }
 
+/*
+GetRetentionReport evaluates the retention policies of repo and goes through the same steps as the gc,
+without removing anything, to also list the untagged manifests and referrers which would be removed.
+*/
+func (gc GarbageCollect) GetRetentionReport(ctx context.Context, repo string) (rTypes.RetentionReport, error) {
+	var lockLatency time.Time
+
+	dir := path.Join(gc.imgStore.RootDir(), repo)
+	if !gc.imgStore.DirExists(dir) {
+		return rTypes.RetentionReport{}, zerr.ErrRepoNotFound
+	}
+
+	gc.imgStore.RLock(&lockLatency)
+	defer gc.imgStore.RUnlock(&lockLatency)
+
+	index, err := common.GetIndex(gc.imgStore, repo, gc.log)
+	if err != nil {
+		return rTypes.RetentionReport{}, err
+	}
+
+	// without metaDB only the tag patterns can be applied
+	var repoMeta *mTypes.RepoMeta
+
+	if gc.metaDB != nil {
+		meta, err := gc.metaDB.GetRepoMeta(ctx, repo)
+		if err != nil && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			return rTypes.RetentionReport{}, err
+		}
+
+		if err == nil {
+			repoMeta = &meta
+		}
+	}
+
+	report := gc.policyMgr.GetRetentionReport(ctx, repo, repoMeta, index)
+
+	retainTags := []string{}
+
+	for _, tag := range report.Tags {
+		if tag.Decision == "keep" {
+			retainTags = append(retainTags, tag.Tag)
+		}
+	}
+
+	gc.report = &report
+
+	// the tags are already in the report, they are only removed from the index
+	if err := gc.removeTagsNotRetained(ctx, repo, &index, retainTags, func(tag string) string {
+		return ""
+	}); err != nil {
+		return rTypes.RetentionReport{}, err
+	}
+
+	if err := gc.removeManifestsPerRepoPolicy(ctx, repo, &index); err != nil {
+		return rTypes.RetentionReport{}, err
+	}
+
+	return report, nil
+}
+
 func (gc GarbageCollect) removeManifestsPerRepoPolicy(ctx context.Context, repo string, index *ispec.Index) error {
 	var err error
 
@@ -297,12 +371,13 @@ func (gc GarbageCollect) removeReferrer(repo string, index *ispec.Index, manifes
 		}
 
 		if !referenced {
-			gced, err = gc.gcManifest(repo, index, manifestDesc, signatureType, subject.Digest, gc.opts.ImageRetention.Delay)
+			gced, err = gc.gcManifest(repo, index, manifestDesc, signatureType, subject.Digest,
+				gc.opts.ImageRetention.Delay, ruleDeleteReferrers)
 			if err != nil {
 				return false, err
 			}
 
-			if gced {
+			if gced && gc.report == nil {
 				gc.log.Info().Str("module", "gc").
 					Str("repository", repo).
 					Str("reference", manifestDesc.Digest.String()).
@@ -330,12 +405,13 @@ func (gc GarbageCollect) removeReferrer(repo string, index *ispec.Index, manifes
 			referenced := isManifestReferencedInIndex(index, subjectDigest)
 
 			if !referenced {
-				gced, err = gc.gcManifest(repo, index, manifestDesc, storage.CosignType, subjectDigest, gc.opts.Delay)
+				gced, err = gc.gcManifest(repo, index, manifestDesc, storage.CosignType, subjectDigest, gc.opts.Delay,
+					ruleDeleteReferrers)
 				if err != nil {
 					return false, err
 				}
 
-				if gced {
+				if gced && gc.report == nil {
 					gc.log.Info().Str("module", "gc").
 						Bool("dry-run", gc.opts.ImageRetention.DryRun).
 						Str("repository", repo).
@@ -362,11 +438,33 @@ func (gc GarbageCollect) removeReferrer(repo string, index *ispec.Index, manifes
 }
 
 func (gc GarbageCollect) removeTagsPerRetentionPolicy(ctx context.Context, repo string, index *ispec.Index) error {
+	// repos populated by the sync proxy cache have their own eviction policy
+	if gc.metaDB != nil && gc.policyMgr.HasProxyCacheEviction() {
+		repoMeta, err := gc.metaDB.GetRepoMeta(ctx, repo)
+		if err != nil && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			gc.log.Error().Err(err).Str("module", "gc").Str("repository", repo).
+				Msg("failed to get repoMeta")
+
+			return err
+		}
+
+		if err == nil && repoMeta.IsProxyCache {
+			retainTags := gc.policyMgr.GetRetainedProxyCacheTags(ctx, repoMeta, *index)
+
+			return gc.removeTagsNotRetained(ctx, repo, index, retainTags, func(tag string) string {
+				return ruleEvictNotPulledWithin
+			})
+		}
+	}
+
 	if !gc.policyMgr.HasTagRetention(repo) {
 		return nil
 	}
 
-	var retainTags []string
+	var (
+		retainTags  []string
+		deleteRules map[string]string
+	)
 
 	if gc.metaDB != nil {
 		repoMeta, err := gc.metaDB.GetRepoMeta(ctx, repo)
@@ -377,12 +475,20 @@ func (gc GarbageCollect) removeTagsPerRetentionPolicy(ctx context.Context, repo
 			return err
 		}
 
-		retainTags = gc.policyMgr.GetRetainedTagsFromMetaDB(ctx, repoMeta, *index)
+		retainTags, deleteRules = gc.policyMgr.GetRetainedTagsFromMetaDB(ctx, repoMeta, *index)
 	} else {
-		retainTags = gc.policyMgr.GetRetainedTagsFromIndex(ctx, repo, *index)
+		retainTags, deleteRules = gc.policyMgr.GetRetainedTagsFromIndex(ctx, repo, *index)
 	}
 
-	// remove
+	return gc.removeTagsNotRetained(ctx, repo, index, retainTags, func(tag string) string {
+		return deleteRules[tag]
+	})
+}
+
+// removeTagsNotRetained removes the tags which are not retained, getRule returns the rule which caused the removal.
+func (gc GarbageCollect) removeTagsNotRetained(ctx context.Context, repo string, index *ispec.Index,
+	retainTags []string, getRule func(tag string) string,
+) error {
 	for _, desc := range index.Manifests {
 		if zcommon.IsContextDone(ctx) {
 			return ctx.Err()
@@ -392,7 +498,7 @@ func (gc GarbageCollect) removeTagsPerRetentionPolicy(ctx context.Context, repo
 		tag, ok := getDescriptorTag(desc)
 		if ok && !zcommon.Contains(retainTags, tag) {
 			// remove tags which should not be retained
-			_, err := gc.removeManifest(repo, index, desc, tag, "", "")
+			_, err := gc.removeManifest(repo, index, desc, tag, "", "", getRule(tag))
 			if err != nil && !errors.Is(err, zerr.ErrManifestNotFound) {
 				return err
 			}
@@ -404,7 +510,7 @@ func (gc GarbageCollect) removeTagsPerRetentionPolicy(ctx context.Context, repo
 
 // gcManifest removes a manifest entry from an index and syncs metaDB accordingly if the blob is older than gc.Delay.
 func (gc GarbageCollect) gcManifest(repo string, index *ispec.Index, desc ispec.Descriptor,
-	signatureType string, subjectDigest godigest.Digest, delay time.Duration,
+	signatureType string, subjectDigest godigest.Digest, delay time.Duration, rule string,
 ) (bool, error) {
 	var gced bool
 
@@ -417,7 +523,8 @@ func (gc GarbageCollect) gcManifest(repo string, index *ispec.Index, desc ispec.
 	}
 
 	if canGC {
-		if gced, err = gc.removeManifest(repo, index, desc, desc.Digest.String(), signatureType, subjectDigest); err != nil {
+		gced, err = gc.removeManifest(repo, index, desc, desc.Digest.String(), signatureType, subjectDigest, rule)
+		if err != nil {
 			return false, err
 		}
 	}
@@ -425,9 +532,10 @@ func (gc GarbageCollect) gcManifest(repo string, index *ispec.Index, desc ispec.
 	return gced, nil
 }
 
-// removeManifest removes a manifest entry from an index and syncs metaDB accordingly.
+// removeManifest removes a manifest entry from an index and syncs metaDB accordingly,
+// rule is the retention setting which caused the removal.
 func (gc GarbageCollect) removeManifest(repo string, index *ispec.Index,
-	desc ispec.Descriptor, reference string, signatureType string, subjectDigest godigest.Digest,
+	desc ispec.Descriptor, reference string, signatureType string, subjectDigest godigest.Digest, rule string,
 ) (bool, error) {
 	_, err := common.RemoveManifestDescByReference(index, reference, true)
 	if err != nil {
@@ -438,6 +546,19 @@ func (gc GarbageCollect) removeManifest(repo string, index *ispec.Index,
 		return false, err
 	}
 
+	if gc.report != nil {
+		if reference == desc.Digest.String() {
+			gc.report.Manifests = append(gc.report.Manifests, rTypes.ManifestDecision{
+				Digest:    desc.Digest.String(),
+				MediaType: desc.MediaType,
+				Decision:  "delete",
+				Reason:    rule,
+			})
+		}
+
+		return true, nil
+	}
+
 	if gc.opts.ImageRetention.DryRun {
 		return true, nil
 	}
@@ -463,6 +584,19 @@ func (gc GarbageCollect) removeManifest(repo string, index *ispec.Index,
 
 				return false, err
 			}
+
+			if err := gc.metaDB.DeleteCVEScanResult(desc.Digest); err != nil {
+				gc.log.Warn().Err(err).Str("module", "gc").Str("component", "metadb").
+					Str("digest", desc.Digest.String()).Msg("failed to delete cve scan result in metaDB")
+			}
+		}
+	}
+
+	if gc.opts.Events != nil {
+		gc.opts.Events.ImageGarbageCollected(repo, reference, desc.Digest.String(), desc.MediaType, rule)
+
+		if signatureType != "" {
+			gc.opts.Events.SignatureDeleted(repo, subjectDigest.String(), desc.Digest.String(), signatureType)
 		}
 	}
 
@@ -488,12 +622,12 @@ func (gc GarbageCollect) removeUntaggedManifests(repo string, index *ispec.Index
 		if desc.MediaType == ispec.MediaTypeImageManifest || desc.MediaType == ispec.MediaTypeImageIndex {
 			_, ok := getDescriptorTag(desc)
 			if !ok {
-				gced, err = gc.gcManifest(repo, index, desc, "", "", gc.opts.ImageRetention.Delay)
+				gced, err = gc.gcManifest(repo, index, desc, "", "", gc.opts.ImageRetention.Delay, ruleDeleteUntagged)
 				if err != nil {
 					return false, err
 				}
 
-				if gced {
+				if gced && gc.report == nil {
 					gc.log.Info().Str("module", "gc").
 						Bool("dry-run", gc.opts.ImageRetention.DryRun).
 						Str("repository", repo).
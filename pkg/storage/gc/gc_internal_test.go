@@ -330,7 +330,7 @@ func TestGarbageCollectWithMockedImageStore(t *testing.T) {
 				},
 			}, gcOptions, audit, log)
 
-			_, err := gc.removeManifest("", &ispec.Index{}, ispec.DescriptorEmptyJSON, "tag", "", "")
+			_, err := gc.removeManifest("", &ispec.Index{}, ispec.DescriptorEmptyJSON, "tag", "", "", "patterns")
 			So(err, ShouldNotBeNil)
 		})
 
@@ -631,7 +631,7 @@ func TestGarbageCollectWithMockedImageStore(t *testing.T) {
 				Manifests: []ispec.Descriptor{desc},
 			}
 			_, err = gc.removeManifest(repoName, index, desc, desc.Digest.String(), storage.NotationType,
-				godigest.FromBytes([]byte("digest2")))
+				godigest.FromBytes([]byte("digest2")), ruleDeleteReferrers)
 
 			So(err, ShouldNotBeNil)
 		})
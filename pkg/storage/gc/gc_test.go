@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"zotregistry.dev/zot/pkg/storage/s3"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
+	"zotregistry.dev/zot/pkg/test/mocks"
 	tskip "zotregistry.dev/zot/pkg/test/skip"
 )
 
@@ -518,6 +520,8 @@ func TestGarbageCollectAndRetentionMetaDB(t *testing.T) {
 				})
 
 				Convey("gc all tags, untagged, and afterwards referrers", func() {
+					gcEvents := map[string]string{}
+
 					gc := gc.NewGarbageCollect(imgStore, metaDB, gc.Options{
 						Delay: 1 * time.Millisecond,
 						ImageRetention: config.ImageRetention{
@@ -535,11 +539,23 @@ func TestGarbageCollectAndRetentionMetaDB(t *testing.T) {
 								},
 							},
 						},
+						Events: mocks.EventRecorderMock{
+							ImageGarbageCollectedFn: func(name, reference, digest, mediaType, rule string) {
+								gcEvents[reference] = rule
+							},
+						},
 					}, audit, log)
 
 					err := gc.CleanRepo(ctx, "gc-test1")
 					So(err, ShouldBeNil)
 
+					// the events record the retention setting which caused the removal
+					So(gcEvents["0.0.1"], ShouldEqual, "patterns")
+					So(gcEvents["0.0.2"], ShouldEqual, "patterns")
+					So(gcEvents[gcUntagged1.DigestStr()], ShouldEqual, "deleteUntagged")
+					So(gcEvents[ref1.DigestStr()], ShouldEqual, "deleteReferrers")
+					So(gcEvents[refOfRef1.DigestStr()], ShouldEqual, "deleteReferrers")
+
 					_, _, _, err = imgStore.GetImageManifest("gc-test1", gcUntagged1.DigestStr())
 					So(err, ShouldNotBeNil)
 
@@ -834,6 +850,95 @@ func TestGarbageCollectAndRetentionMetaDB(t *testing.T) {
 					So(tags, ShouldNotContain, "0.0.7")
 				})
 
+				Convey("evict cached tags not pulled recently", func() {
+					sevenDays := 7 * 24 * time.Hour
+					gcRules := map[string]string{}
+
+					err := metaDB.SetRepoProxyCache("retention", "docker.io")
+					So(err, ShouldBeNil)
+
+					// the mark is kept in metaDB across storage parsing, remove it for the next tests
+					Reset(func() {
+						repoMeta, err := metaDB.GetRepoMeta(ctx, "retention")
+						So(err, ShouldBeNil)
+
+						repoMeta.IsProxyCache = false
+						repoMeta.Upstream = ""
+
+						err = metaDB.SetRepoMeta("retention", repoMeta)
+						So(err, ShouldBeNil)
+					})
+
+					gc := gc.NewGarbageCollect(imgStore, metaDB, gc.Options{
+						Delay: storageConstants.DefaultGCDelay,
+						ImageRetention: config.ImageRetention{
+							Delay: storageConstants.DefaultRetentionDelay,
+							// keepTags policies are not applied to cached repos
+							Policies: []config.RetentionPolicy{
+								{
+									Repositories: []string{"**"},
+									KeepTags: []config.KeepTagsPolicy{
+										{
+											Patterns: []string{".*"},
+										},
+									},
+								},
+							},
+							ProxyCache: &config.ProxyCacheRetention{
+								EvictNotPulledWithin: sevenDays,
+							},
+						},
+						Events: mocks.EventRecorderMock{
+							ImageGarbageCollectedFn: func(name, reference, digest, mediaType, rule string) {
+								gcRules[reference] = rule
+							},
+						},
+					}, audit, log)
+
+					err = gc.CleanRepo(ctx, "retention")
+					So(err, ShouldBeNil)
+
+					So(gcRules, ShouldResemble, map[string]string{
+						"0.0.1": "evictNotPulledWithin",
+						"0.0.2": "evictNotPulledWithin",
+						"0.0.3": "evictNotPulledWithin",
+						"0.0.7": "evictNotPulledWithin",
+					})
+
+					tags, err := imgStore.GetImageTags("retention")
+					So(err, ShouldBeNil)
+
+					So(tags, ShouldContain, "0.0.4")
+					So(tags, ShouldContain, "0.0.5")
+					So(tags, ShouldContain, "0.0.6")
+					So(tags, ShouldContain, "0.0.8")
+
+					So(tags, ShouldNotContain, "0.0.1")
+					So(tags, ShouldNotContain, "0.0.2")
+					So(tags, ShouldNotContain, "0.0.3")
+					So(tags, ShouldNotContain, "0.0.7")
+				})
+
+				Convey("proxy cache eviction does not apply to repos which are not cached", func() {
+					gc := gc.NewGarbageCollect(imgStore, metaDB, gc.Options{
+						Delay: storageConstants.DefaultGCDelay,
+						ImageRetention: config.ImageRetention{
+							Delay: storageConstants.DefaultRetentionDelay,
+							ProxyCache: &config.ProxyCacheRetention{
+								EvictNotPulledWithin: time.Hour,
+							},
+						},
+					}, audit, log)
+
+					err = gc.CleanRepo(ctx, "retention")
+					So(err, ShouldBeNil)
+
+					tags, err := imgStore.GetImageTags("retention")
+					So(err, ShouldBeNil)
+
+					So(len(tags), ShouldEqual, 8)
+				})
+
 				Convey("retain 3 most recently pushed images", func() {
 					gc := gc.NewGarbageCollect(imgStore, metaDB, gc.Options{
 						Delay: storageConstants.DefaultGCDelay,
@@ -2462,3 +2567,248 @@ func TestGarbageCollectAndRetentionNoMetaDB(t *testing.T) {
 		})
 	}
 }
+
+func TestGarbageCollectRetentionImageMetaRules(t *testing.T) {
+	log := zlog.NewLogger("debug", "")
+	audit := zlog.NewAuditLogger("debug", "/dev/null")
+
+	metrics := monitoring.NewMetricsServer(false, log)
+
+	Convey("setup retention images", t, func() {
+		rootDir := t.TempDir()
+
+		imgStore := local.NewImageStore(rootDir, false, false, log, metrics, nil, nil, nil, nil)
+
+		boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		ctx := context.Background()
+		repo := "retention"
+
+		base := CreateImageWith().RandomLayers(1, 100).RandomConfig().Build()
+		app := CreateImageWith().LayerBlobs(append([][]byte{}, base.Layers[0], []byte("app layer"))).
+			RandomConfig().Build()
+		vendor := CreateRandomImageWith().Annotations(map[string]string{ispec.AnnotationVendor: "zot"}).Build()
+		signed := CreateRandomImage()
+		unsigned := CreateRandomImage()
+		old := CreateRandomImage()
+
+		images := []struct {
+			tag   string
+			image Image
+		}{
+			{"app", app}, {"vendor", vendor}, {"signed", signed}, {"unsigned", unsigned}, {"base", base}, {"old", old},
+		}
+
+		for _, image := range images {
+			err = WriteImageToFileSystem(image.image, repo, image.tag, storeController)
+			So(err, ShouldBeNil)
+		}
+
+		err = meta.ParseStorage(metaDB, storeController, log) //nolint: contextcheck
+		So(err, ShouldBeNil)
+
+		repoMeta, err := metaDB.GetRepoMeta(ctx, repo)
+		So(err, ShouldBeNil)
+
+		// the most recently pushed image is the first one
+		for idx, image := range images {
+			stats := repoMeta.Statistics[image.image.DigestStr()]
+			stats.PushTimestamp = time.Now().Add(-time.Duration(idx+1) * time.Hour)
+			repoMeta.Statistics[image.image.DigestStr()] = stats
+		}
+
+		repoMeta.Signatures = map[mTypes.ImageDigest]mTypes.ManifestSignatures{
+			signed.DigestStr(): {
+				"cosign": []mTypes.SignatureInfo{{LayersInfo: []mTypes.LayerInfo{{Signer: "ci"}}}},
+			},
+			unsigned.DigestStr(): {
+				"cosign": []mTypes.SignatureInfo{{LayersInfo: []mTypes.LayerInfo{{Signer: ""}}}},
+			},
+		}
+
+		err = metaDB.SetRepoMeta(repo, repoMeta)
+		So(err, ShouldBeNil)
+
+		gcEvents := map[string]string{}
+
+		newGarbageCollect := func(keepTags []config.KeepTagsPolicy, dryRun bool) gc.GarbageCollect {
+			return gc.NewGarbageCollect(imgStore, metaDB, gc.Options{
+				Delay: storageConstants.DefaultGCDelay,
+				ImageRetention: config.ImageRetention{
+					DryRun: dryRun,
+					Delay:  storageConstants.DefaultRetentionDelay,
+					Policies: []config.RetentionPolicy{
+						{
+							Repositories: []string{"**"},
+							KeepTags:     keepTags,
+						},
+					},
+				},
+				Events: mocks.EventRecorderMock{
+					ImageGarbageCollectedFn: func(name, reference, digest, mediaType, rule string) {
+						gcEvents[reference] = rule
+					},
+				},
+			}, audit, log)
+		}
+
+		assertTags := func(kept ...string) {
+			for _, image := range images {
+				_, _, _, err := imgStore.GetImageManifest(repo, image.tag)
+				if slices.Contains(kept, image.tag) {
+					So(err, ShouldBeNil)
+				} else {
+					So(err, ShouldNotBeNil)
+				}
+			}
+		}
+
+		Convey("retain by annotations, signatures and base images", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{Patterns: []string{"app"}, MostRecentlyPushedCount: 1},
+				{Patterns: []string{"vendor"}, Annotations: []config.AnnotationSelector{
+					{Key: ispec.AnnotationVendor, Value: "^zot$"},
+				}},
+				{Patterns: []string{"signed", "unsigned"}, Signed: true},
+				{Patterns: []string{"base", "old"}, KeepBaseImages: true},
+			}, false)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "vendor", "signed", "base")
+		})
+
+		Convey("annotation selectors need to match", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{Annotations: []config.AnnotationSelector{
+					{Key: ispec.AnnotationVendor, Value: "^other$"},
+				}},
+			}, false)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags()
+		})
+
+		Convey("retain most recently pushed images within max size", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{MaxSize: int64(app.Size() + vendor.Size())},
+			}, false)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "vendor")
+		})
+
+		Convey("max size limits the images retained by the other rules", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{MostRecentlyPushedCount: 4, MaxSize: int64(app.Size() + vendor.Size())},
+			}, false)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "vendor")
+
+			// the events record the rule which caused the removal
+			So(gcEvents, ShouldResemble, map[string]string{
+				"signed":   fmt.Sprintf("maxSize:%d", app.Size()+vendor.Size()),
+				"unsigned": fmt.Sprintf("maxSize:%d", app.Size()+vendor.Size()),
+				"base":     "mostRecentlyPushedCount:4",
+				"old":      "mostRecentlyPushedCount:4",
+			})
+		})
+
+		Convey("the events record the rules which caused the removal", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{Patterns: []string{"app", "vendor"}, MostRecentlyPushedCount: 1, Signed: true},
+				{Patterns: []string{"base"}, KeepBaseImages: true},
+			}, false)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "base")
+
+			So(gcEvents, ShouldResemble, map[string]string{
+				"vendor":   "mostRecentlyPushedCount:1,signed",
+				"signed":   "patterns",
+				"unsigned": "patterns",
+				"old":      "patterns",
+			})
+		})
+
+		Convey("retain base images of the images of other repos", func() {
+			other := CreateImageWith().LayerBlobs(append([][]byte{}, old.Layers[0], []byte("other layer"))).
+				RandomConfig().Build()
+
+			err := metaDB.SetRepoReference(ctx, "other", "latest", other.AsImageMeta())
+			So(err, ShouldBeNil)
+
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{Patterns: []string{"base", "old"}, KeepBaseImages: true},
+			}, false)
+
+			err = gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("old")
+		})
+
+		Convey("images with the same layers are not base images of each other", func() {
+			twin := CreateImageWith().LayerBlobs(old.Layers).RandomConfig().Build()
+
+			err := metaDB.SetRepoReference(ctx, "other", "latest", twin.AsImageMeta())
+			So(err, ShouldBeNil)
+
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{Patterns: []string{"base", "old"}, KeepBaseImages: true},
+			}, false)
+
+			err = gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags()
+		})
+
+		Convey("max size limits the base images", func() {
+			newBaseImagesGC := func(maxSize int64) gc.GarbageCollect {
+				return newGarbageCollect([]config.KeepTagsPolicy{
+					{Patterns: []string{"app", "base"}, MostRecentlyPushedCount: 1, KeepBaseImages: true, MaxSize: maxSize},
+				}, false)
+			}
+
+			// the base image is kept if it fits
+			err := newBaseImagesGC(int64(app.Size()+base.Size())).CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "base")
+
+			err = newBaseImagesGC(int64(app.Size())).CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app")
+			So(gcEvents["base"], ShouldEqual, fmt.Sprintf("maxSize:%d", app.Size()))
+		})
+
+		Convey("dry-run does not remove images", func() {
+			gc := newGarbageCollect([]config.KeepTagsPolicy{
+				{MaxSize: 1},
+			}, true)
+
+			err := gc.CleanRepo(ctx, repo)
+			So(err, ShouldBeNil)
+
+			assertTags("app", "vendor", "signed", "unsigned", "base", "old")
+		})
+	})
+}
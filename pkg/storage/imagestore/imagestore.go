@@ -25,6 +25,7 @@ import (
 	"zotregistry.dev/zot/pkg/extensions/events"
 	"zotregistry.dev/zot/pkg/extensions/monitoring"
 	syncConstants "zotregistry.dev/zot/pkg/extensions/sync/constants"
+	"zotregistry.dev/zot/pkg/extensions/tracing"
 	zlog "zotregistry.dev/zot/pkg/log"
 	zreg "zotregistry.dev/zot/pkg/regexp"
 	"zotregistry.dev/zot/pkg/scheduler"
@@ -52,6 +53,9 @@ type ImageStore struct {
 	linter      common.Lint
 	commit      bool
 	compat      []compat.MediaCompatibility
+	quota       *quotaTracker
+	// set on copies returned by WithContext, spans are recorded as part of its trace
+	ctx context.Context //nolint:containedctx
 }
 
 func (is *ImageStore) Name() string {
@@ -100,7 +104,9 @@ func NewImageStore(rootDir string, cacheDir string, dedupe, commit bool, log zlo
 func (is *ImageStore) RLock(lockStart *time.Time) {
 	*lockStart = time.Now()
 
+	span := is.startSpan("imagestore.RLock")
 	is.lock.RLock()
+	span.End()
 }
 
 // RUnlock read-unlock.
@@ -117,7 +123,9 @@ func (is *ImageStore) RUnlock(lockStart *time.Time) {
 func (is *ImageStore) Lock(lockStart *time.Time) {
 	*lockStart = time.Now()
 
+	span := is.startSpan("imagestore.Lock")
 	is.lock.Lock()
+	span.End()
 }
 
 // Unlock write-unlock.
@@ -358,11 +366,17 @@ func (is *ImageStore) GetNextRepositories(lastRepo string, maxEntries int, filte
 func (is *ImageStore) GetRepositories() ([]string, error) {
 	var lockLatency time.Time
 
-	dir := is.rootDir
-
 	is.RLock(&lockLatency)
 	defer is.RUnlock(&lockLatency)
 
+	return is.getRepositories()
+}
+
+// getRepositories returns a list of all the repositories under this store.
+// the caller function MUST lock from outside.
+func (is *ImageStore) getRepositories() ([]string, error) {
+	dir := is.rootDir
+
 	stores := make([]string, 0)
 
 	err := is.storeDriver.Walk(dir, func(fileInfo driver.FileInfo) error {
@@ -638,12 +652,18 @@ func (is *ImageStore) PutImageManifest(repo, reference, mediaType string, //noli
 
 	binfo, err := is.storeDriver.Stat(manifestPath)
 	if err != nil || binfo.Size() != desc.Size {
+		if err = is.checkQuota(repo, mDigest, desc.Size); err != nil {
+			return "", "", err
+		}
+
 		// The blob isn't already there, or it is corrupted, and needs a correction
 		if _, err = is.storeDriver.WriteFile(manifestPath, body); err != nil {
 			is.log.Error().Err(err).Str("file", manifestPath).Msg("failed to write")
 
 			return "", "", err
 		}
+
+		is.chargeQuota(repo, mDigest, desc.Size)
 	}
 
 	err = common.UpdateIndexWithPrunedImageManifests(is, &index, repo, desc, oldDgst, is.log)
@@ -666,13 +686,17 @@ func (is *ImageStore) PutImageManifest(repo, reference, mediaType string, //noli
 	desc.ArtifactType = artifactType
 
 	// apply linter only on images, not signatures
+	lintSpan := is.startSpan("imagestore.Lint", tracing.RepositoryKey.String(repo),
+		tracing.ReferenceKey.String(reference))
 	pass, err := common.ApplyLinter(is, is.linter, repo, desc)
+	tracing.EndSpan(lintSpan, err)
+
 	if !pass {
 		is.log.Error().Err(err).Str("repository", repo).Str("reference", reference).
 			Msg("linter didn't pass")
 
 		if is.events != nil {
-			is.events.ImageLintFailed(repo, reference, mDigest.String(), mediaType, string(body))
+			is.events.ImageLintFailed(repo, reference, mDigest.String(), mediaType, string(body), err)
 		}
 
 		return "", "", err
@@ -714,6 +738,8 @@ func (is *ImageStore) deleteImageManifest(repo, reference string, detectCollisio
 		if is.storeDriver.Name() == storageConstants.LocalStorageDriverName {
 			monitoring.SetStorageUsage(is.metrics, is.rootDir, repo)
 		}
+
+		is.invalidateQuota(repo)
 	}()
 
 	index, err := common.GetIndex(is, repo, is.log)
@@ -914,7 +940,9 @@ func (is *ImageStore) PutBlobChunkStreamed(repo, uuid string, body io.Reader) (i
 		err = file.Close()
 	}()
 
-	n, err = io.Copy(file, body)
+	is.startQuotaUpload(repo, blobUploadPath, file.Size())
+
+	n, err = is.copyWithinQuota(file, body, repo, blobUploadPath, "")
 
 	return n, err
 }
@@ -950,7 +978,9 @@ func (is *ImageStore) PutBlobChunk(repo, uuid string, from, to int64,
 		return -1, zerr.ErrBadUploadRange
 	}
 
-	n, err := io.Copy(file, body)
+	is.startQuotaUpload(repo, blobUploadPath, file.Size())
+
+	n, err := is.copyWithinQuota(file, body, repo, blobUploadPath, "")
 
 	return n, err
 }
@@ -981,6 +1011,9 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 
 	src := is.BlobUploadPath(repo, uuid)
 
+	// the blob is charged to the quota once stored, a failed upload is reserved again if it's resumed
+	defer is.releaseQuotaUpload(src)
+
 	// complete multiUploadPart
 	fileWriter, err := is.storeDriver.Writer(src, true)
 	if err != nil {
@@ -1031,6 +1064,22 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 	is.Lock(&lockLatency)
 	defer is.Unlock(&lockLatency)
 
+	binfo, err := is.storeDriver.Stat(src)
+	if err != nil {
+		is.log.Error().Err(err).Str("blob", src).Msg("failed to stat blob")
+
+		return zerr.ErrUploadNotFound
+	}
+
+	if err := is.checkQuota(repo, dstDigest, binfo.Size()); err != nil {
+		// the upload can't be finished, don't keep its contents around
+		if err := is.storeDriver.Delete(src); err != nil {
+			is.log.Error().Err(err).Str("blob", src).Msg("failed to delete blob upload")
+		}
+
+		return err
+	}
+
 	if is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
 		err = is.DedupeBlob(src, dstDigest, repo, dst)
 		if err := inject.Error(err); err != nil {
@@ -1048,6 +1097,12 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 		}
 	}
 
+	is.chargeQuota(repo, dstDigest, binfo.Size())
+
+	if is.events != nil {
+		is.events.BlobPushed(repo, dstDigest.String(), binfo.Size())
+	}
+
 	return nil
 }
 
@@ -1084,8 +1139,14 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 
 	mw := io.MultiWriter(blobFile, digester)
 
-	nbytes, err := io.Copy(mw, body)
+	defer is.releaseQuotaUpload(src)
+
+	nbytes, err := is.copyWithinQuota(mw, body, repo, src, dstDigest)
 	if err != nil {
+		if errors.Is(err, zerr.ErrQuotaExceeded) {
+			_ = blobFile.Cancel(context.Background())
+		}
+
 		return "", -1, err
 	}
 
@@ -1113,6 +1174,15 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 
 	dst := is.BlobPath(repo, dstDigest)
 
+	if err := is.checkQuota(repo, dstDigest, nbytes); err != nil {
+		// the upload can't be finished, don't keep its contents around
+		if err := is.storeDriver.Delete(src); err != nil {
+			is.log.Error().Err(err).Str("blob", src).Msg("failed to delete blob upload")
+		}
+
+		return "", -1, err
+	}
+
 	if is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
 		if err := is.DedupeBlob(src, dstDigest, repo, dst); err != nil {
 			is.log.Error().Err(err).Str("src", src).Str("dstDigest", dstDigest.String()).
@@ -1129,6 +1199,12 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 		}
 	}
 
+	is.chargeQuota(repo, dstDigest, nbytes)
+
+	if is.events != nil {
+		is.events.BlobPushed(repo, dstDigest.String(), nbytes)
+	}
+
 	return uuid, nbytes, nil
 }
 
@@ -1253,6 +1329,8 @@ func (is *ImageStore) DeleteBlobUpload(repo, uuid string) error {
 		return err
 	}
 
+	is.releaseQuotaUpload(blobUploadPath)
+
 	return nil
 }
 
@@ -1299,7 +1377,8 @@ func (is *ImageStore) GetAllDedupeReposCandidates(digest godigest.Digest) ([]str
 /*
 	CheckBlob verifies a blob and returns true if the blob is correct
 
-If the blob is not found but it's found in cache then it will be copied over.
+If the blob is not found but it's found in cache then it will be copied over,
+unless it doesn't fit in the quota of the repository.
 */
 func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int64, error) {
 	var lockLatency time.Time
@@ -1345,11 +1424,25 @@ func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int6
 		return false, -1, zerr.ErrBlobNotFound
 	}
 
+	// linking the blob makes it part of repo, it has to fit in its quota like an upload
+	if is.quota != nil {
+		binfo, err := is.storeDriver.Stat(dstRecord)
+		if err != nil {
+			return false, -1, zerr.ErrBlobNotFound
+		}
+
+		if err := is.checkQuota(repo, digest, binfo.Size()); err != nil {
+			return false, -1, err
+		}
+	}
+
 	blobSize, err := is.copyBlob(repo, blobPath, dstRecord)
 	if err != nil {
 		return false, -1, zerr.ErrBlobNotFound
 	}
 
+	is.chargeQuota(repo, digest, blobSize)
+
 	// put deduped blob in cache
 	if err := is.cache.PutBlob(digest, blobPath); err != nil {
 		is.log.Error().Err(err).Str("blobPath", blobPath).Str("component", "dedupe").Msg("failed to insert blob record")
@@ -1360,6 +1453,134 @@ func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int6
 	return true, blobSize, nil
 }
 
+// MountBlob makes a blob stored in fromRepo available in repo without uploading it again.
+// If dedupe is enabled the blob is linked to its original copy and recorded in cache,
+// otherwise its content is copied over.
+func (is *ImageStore) MountBlob(repo, fromRepo string, digest godigest.Digest) (int64, error) {
+	var lockLatency time.Time
+
+	if err := digest.Validate(); err != nil {
+		return -1, err
+	}
+
+	is.Lock(&lockLatency)
+	defer is.Unlock(&lockLatency)
+
+	srcPath := is.BlobPath(fromRepo, digest)
+
+	binfo, err := is.storeDriver.Stat(srcPath)
+	if err != nil {
+		is.log.Debug().Err(err).Str("blob", srcPath).Msg("blob to mount not found")
+
+		return -1, zerr.ErrBlobNotFound
+	}
+
+	useCache := is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil)
+
+	if binfo.Size() == 0 {
+		// deduped blob (empty file), mount the original one
+		srcPath, err = is.checkCacheBlob(digest)
+		if err != nil {
+			is.log.Debug().Err(err).Str("digest", digest.String()).Msg("not found in cache")
+
+			return -1, zerr.ErrBlobNotFound
+		}
+
+		binfo, err = is.storeDriver.Stat(srcPath)
+		if err != nil {
+			return -1, zerr.ErrBlobNotFound
+		}
+	} else if useCache {
+		// blobs pushed before dedupe was enabled may not be recorded in cache yet
+		if _, err := is.checkCacheBlob(digest); err != nil {
+			if err := is.cache.PutBlob(digest, srcPath); err != nil {
+				is.log.Error().Err(err).Str("blobPath", srcPath).Str("component", "dedupe").
+					Msg("failed to insert blob record")
+
+				return -1, err
+			}
+		}
+	}
+
+	blobPath := is.BlobPath(repo, digest)
+
+	if _, err := is.storeDriver.Stat(blobPath); err == nil {
+		// already present in the target repository
+		return binfo.Size(), nil
+	}
+
+	if err := is.checkQuota(repo, digest, binfo.Size()); err != nil {
+		return -1, err
+	}
+
+	if useCache {
+		if _, err := is.copyBlob(repo, blobPath, srcPath); err != nil {
+			return -1, err
+		}
+
+		if err := is.cache.PutBlob(digest, blobPath); err != nil {
+			is.log.Error().Err(err).Str("blobPath", blobPath).Str("component", "dedupe").
+				Msg("failed to insert blob record")
+
+			return -1, err
+		}
+	} else if err := is.copyBlobContent(repo, blobPath, srcPath); err != nil {
+		return -1, err
+	}
+
+	is.chargeQuota(repo, digest, binfo.Size())
+
+	is.log.Debug().Str("repository", repo).Str("from", fromRepo).Str("digest", digest.String()).
+		Msg("mounted blob")
+
+	return binfo.Size(), nil
+}
+
+// copyBlobContent writes a full copy of the blob found at srcPath to blobPath in repo.
+func (is *ImageStore) copyBlobContent(repo, blobPath, srcPath string) error {
+	if err := is.initRepo(repo); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("failed to initialize an empty repo")
+
+		return err
+	}
+
+	_ = is.storeDriver.EnsureDir(filepath.Dir(blobPath))
+
+	reader, err := is.storeDriver.Reader(srcPath, 0)
+	if err != nil {
+		is.log.Error().Err(err).Str("blob", srcPath).Msg("failed to open blob")
+
+		return zerr.ErrBlobNotFound
+	}
+
+	defer reader.Close()
+
+	writer, err := is.storeDriver.Writer(blobPath, false)
+	if err != nil {
+		is.log.Error().Err(err).Str("blob", blobPath).Msg("failed to open blob")
+
+		return err
+	}
+
+	defer writer.Close()
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		_ = writer.Cancel(context.Background())
+
+		is.log.Error().Err(err).Str("src", srcPath).Str("dst", blobPath).Msg("failed to copy blob")
+
+		return err
+	}
+
+	if err := writer.Commit(context.Background()); err != nil {
+		is.log.Error().Err(err).Str("blob", blobPath).Msg("failed to commit blob")
+
+		return err
+	}
+
+	return nil
+}
+
 // StatBlob verifies if a blob is present inside a repository. The caller function MUST lock from outside.
 func (is *ImageStore) StatBlob(repo string, digest godigest.Digest) (bool, int64, time.Time, error) {
 	if err := digest.Validate(); err != nil {
@@ -1678,6 +1899,8 @@ func (is *ImageStore) DeleteBlob(repo string, digest godigest.Digest) error {
 	is.Lock(&lockLatency)
 	defer is.Unlock(&lockLatency)
 
+	defer is.invalidateQuota(repo)
+
 	return is.deleteBlob(repo, digest)
 }
 
@@ -1733,6 +1956,10 @@ func (is *ImageStore) CleanupRepo(repo string, blobs []godigest.Digest, removeRe
 		monitoring.SetStorageUsage(is.metrics, is.rootDir, repo)
 	}
 
+	if count > 0 {
+		is.invalidateQuota(repo)
+	}
+
 	return count, nil
 }
 
@@ -0,0 +1,447 @@
+package imagestore
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.dev/zot/errors"
+	"zotregistry.dev/zot/pkg/api/config"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+// quotaScope accounts the blobs stored by all the repositories matching a quota policy.
+// A digest shared by several of these repositories (deduped or not) is only counted once.
+type quotaScope struct {
+	policy config.QuotaPolicy
+	// repo is only set for the scopes of the policies enforced per repository, they account a single repository
+	repo string
+	// repoScopes holds the scope of each repository matching a policy enforced per repository
+	repoScopes map[string]*quotaScope
+	// blobs is lazily computed from storage and dropped whenever content is removed
+	blobs map[godigest.Digest]int64
+	used  int64
+}
+
+// quotaUpload is an upload in progress, the bytes it already holds are reserved in the quota of its repository
+// until it is finished or canceled.
+type quotaUpload struct {
+	repo string
+	size int64
+}
+
+type quotaTracker struct {
+	lock   sync.Mutex
+	scopes []*quotaScope
+	// uploads in progress, keyed by upload path
+	uploads map[string]*quotaUpload
+}
+
+// SetQuota configures the storage quota policies enforced on pushes to this image store.
+func (is *ImageStore) SetQuota(quota config.StorageQuota) {
+	if len(quota.Policies) == 0 {
+		is.quota = nil
+
+		return
+	}
+
+	tracker := &quotaTracker{uploads: map[string]*quotaUpload{}}
+
+	for _, policy := range quota.Policies {
+		scope := &quotaScope{policy: policy}
+		if policy.PerRepository {
+			scope.repoScopes = map[string]*quotaScope{}
+		}
+
+		tracker.scopes = append(tracker.scopes, scope)
+	}
+
+	is.quota = tracker
+}
+
+// GetQuotaUsage returns the storage used by each quota policy of this image store.
+func (is *ImageStore) GetQuotaUsage() ([]storageTypes.QuotaUsage, error) {
+	usage := []storageTypes.QuotaUsage{}
+
+	if is.quota == nil {
+		return usage, nil
+	}
+
+	var lockLatency time.Time
+
+	is.RLock(&lockLatency)
+	defer is.RUnlock(&lockLatency)
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	var repos []string
+
+	for _, scope := range is.quota.scopes {
+		scopes := []*quotaScope{scope}
+
+		if scope.policy.PerRepository {
+			if repos == nil {
+				var err error
+
+				repos, err = is.getRepositories()
+				if err != nil {
+					is.log.Error().Err(err).Msg("failed to list repositories for quota accounting")
+
+					return usage, err
+				}
+			}
+
+			scopes = []*quotaScope{}
+
+			for _, repo := range repos {
+				if scope.matches(repo) {
+					scopes = append(scopes, scope.repoScope(repo))
+				}
+			}
+		}
+
+		for _, usageScope := range scopes {
+			if err := is.loadQuotaScope(usageScope); err != nil {
+				return usage, err
+			}
+
+			usage = append(usage, storageTypes.QuotaUsage{
+				Repositories: usageScope.policy.Repositories,
+				Repository:   usageScope.repo,
+				SoftLimit:    usageScope.policy.SoftLimit,
+				HardLimit:    usageScope.policy.HardLimit,
+				Used:         usageScope.used,
+			})
+		}
+	}
+
+	return usage, nil
+}
+
+// scopesFor returns the scopes accounting the blobs of repo, the quota lock MUST be held by the caller.
+func (tracker *quotaTracker) scopesFor(repo string) []*quotaScope {
+	scopes := []*quotaScope{}
+
+	for _, scope := range tracker.scopes {
+		if !scope.matches(repo) {
+			continue
+		}
+
+		if scope.policy.PerRepository {
+			scope = scope.repoScope(repo)
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes
+}
+
+// repoScope returns the scope of repo for a policy enforced per repository, creating it if needed.
+func (scope *quotaScope) repoScope(repo string) *quotaScope {
+	repoScope, ok := scope.repoScopes[repo]
+	if !ok {
+		repoScope = &quotaScope{policy: scope.policy, repo: repo}
+		scope.repoScopes[repo] = repoScope
+	}
+
+	return repoScope
+}
+
+func (scope *quotaScope) matches(repo string) bool {
+	if scope.repo != "" {
+		return scope.repo == repo
+	}
+
+	for _, pattern := range scope.policy.Repositories {
+		matched, err := glob.Match(pattern, repo)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (scope *quotaScope) name() string {
+	if scope.repo != "" {
+		return scope.repo
+	}
+
+	return strings.Join(scope.policy.Repositories, ",")
+}
+
+// loadQuotaScope computes the blobs accounted in scope if they are not already known.
+// it does not acquire any lock, the image store lock (read or write) and the quota lock MUST be held by the caller.
+func (is *ImageStore) loadQuotaScope(scope *quotaScope) error {
+	if scope.blobs != nil {
+		return nil
+	}
+
+	repos, err := is.getRepositories()
+	if err != nil {
+		is.log.Error().Err(err).Msg("failed to list repositories for quota accounting")
+
+		return err
+	}
+
+	blobs := map[godigest.Digest]int64{}
+
+	var used int64
+
+	for _, repo := range repos {
+		if !scope.matches(repo) {
+			continue
+		}
+
+		digests, err := is.GetAllBlobs(repo)
+		if err != nil {
+			is.log.Error().Err(err).Str("repository", repo).Msg("failed to list blobs for quota accounting")
+
+			return err
+		}
+
+		for _, digest := range digests {
+			if _, ok := blobs[digest]; ok {
+				continue
+			}
+
+			// deduped blobs are empty files, account the size of the original one
+			binfo, err := is.originalBlobInfo(repo, digest)
+			if err != nil {
+				continue
+			}
+
+			blobs[digest] = binfo.Size()
+			used += binfo.Size()
+		}
+	}
+
+	scope.blobs = blobs
+	scope.used = used
+
+	monitoring.SetQuotaUsage(is.metrics, scope.name(), scope.used, scope.policy.SoftLimit, scope.policy.HardLimit)
+
+	return nil
+}
+
+// reserved returns the bytes held by the uploads in progress accounted in scope,
+// the quota lock MUST be held by the caller.
+func (tracker *quotaTracker) reserved(scope *quotaScope) int64 {
+	var reserved int64
+
+	for _, upload := range tracker.uploads {
+		if scope.matches(upload.repo) {
+			reserved += upload.size
+		}
+	}
+
+	return reserved
+}
+
+// startQuotaUpload reserves the bytes an upload in progress already holds, from its previous chunks or
+// from before a restart, so that uploads in progress can't go over the hard limits together.
+func (is *ImageStore) startQuotaUpload(repo, uploadPath string, size int64) {
+	if is.quota == nil {
+		return
+	}
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	is.quota.uploads[uploadPath] = &quotaUpload{repo: repo, size: size}
+}
+
+// reserveQuota reserves size more bytes for an upload in progress, failing with ErrQuotaExceeded if the blobs
+// stored and the uploads in progress would go over the hard limit of any quota policy matching repo.
+// Policies which already account the given digest (if known) are not taken into consideration.
+func (is *ImageStore) reserveQuota(repo, uploadPath string, digest godigest.Digest, size int64) error {
+	var lockLatency time.Time
+
+	// computing the usage walks the repositories
+	is.RLock(&lockLatency)
+	defer is.RUnlock(&lockLatency)
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	upload, ok := is.quota.uploads[uploadPath]
+	if !ok {
+		upload = &quotaUpload{repo: repo}
+		is.quota.uploads[uploadPath] = upload
+	}
+
+	for _, scope := range is.quota.scopesFor(repo) {
+		if scope.policy.HardLimit <= 0 {
+			continue
+		}
+
+		if err := is.loadQuotaScope(scope); err != nil {
+			return err
+		}
+
+		if _, ok := scope.blobs[digest]; ok {
+			continue
+		}
+
+		reserved := is.quota.reserved(scope)
+
+		if scope.used+reserved+size > scope.policy.HardLimit {
+			is.log.Error().Err(zerr.ErrQuotaExceeded).Str("repository", repo).Strs("quota", scope.policy.Repositories).
+				Int64("used", scope.used).Int64("reserved", reserved).Int64("size", size).
+				Int64("hardLimit", scope.policy.HardLimit).Msg("failed to upload blob, quota hard limit reached")
+
+			return zerr.NewError(zerr.ErrQuotaExceeded).AddDetail("repositories", scope.name()).
+				AddDetail("hardLimit", strconv.FormatInt(scope.policy.HardLimit, 10))
+		}
+	}
+
+	upload.size += size
+
+	return nil
+}
+
+// releaseQuotaUpload drops the reservation of an upload which was finished or canceled,
+// the blob it produced (if any) is accounted by chargeQuota.
+func (is *ImageStore) releaseQuotaUpload(uploadPath string) {
+	if is.quota == nil {
+		return
+	}
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	delete(is.quota.uploads, uploadPath)
+}
+
+// checkQuota returns ErrQuotaExceeded if storing a blob of the given size in repo would go over the hard limit
+// of any quota policy matching repo. Blobs already accounted by a policy don't use additional quota.
+// the image store lock (read or write) MUST be held by the caller.
+func (is *ImageStore) checkQuota(repo string, digest godigest.Digest, size int64) error {
+	if is.quota == nil {
+		return nil
+	}
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	for _, scope := range is.quota.scopesFor(repo) {
+		if scope.policy.HardLimit <= 0 {
+			continue
+		}
+
+		if err := is.loadQuotaScope(scope); err != nil {
+			return err
+		}
+
+		if _, ok := scope.blobs[digest]; ok {
+			continue
+		}
+
+		if scope.used+size > scope.policy.HardLimit {
+			is.log.Error().Err(zerr.ErrQuotaExceeded).Str("repository", repo).Str("digest", digest.String()).
+				Strs("quota", scope.policy.Repositories).Int64("used", scope.used).Int64("size", size).
+				Int64("hardLimit", scope.policy.HardLimit).Msg("failed to store blob, quota hard limit reached")
+
+			return zerr.NewError(zerr.ErrQuotaExceeded).AddDetail("repositories", scope.name()).
+				AddDetail("hardLimit", strconv.FormatInt(scope.policy.HardLimit, 10))
+		}
+	}
+
+	return nil
+}
+
+// chargeQuota accounts a blob newly stored in repo against all the quota policies matching repo.
+func (is *ImageStore) chargeQuota(repo string, digest godigest.Digest, size int64) {
+	if is.quota == nil {
+		return
+	}
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	for _, scope := range is.quota.scopesFor(repo) {
+		// scopes not yet loaded will pick up the blob when computed from storage
+		if scope.blobs == nil {
+			continue
+		}
+
+		if _, ok := scope.blobs[digest]; ok {
+			continue
+		}
+
+		scope.blobs[digest] = size
+		scope.used += size
+
+		if scope.policy.SoftLimit > 0 && scope.used > scope.policy.SoftLimit {
+			is.log.Warn().Str("repository", repo).Strs("quota", scope.policy.Repositories).
+				Int64("used", scope.used).Int64("softLimit", scope.policy.SoftLimit).
+				Msg("quota soft limit exceeded")
+		}
+
+		monitoring.SetQuotaUsage(is.metrics, scope.name(), scope.used, scope.policy.SoftLimit, scope.policy.HardLimit)
+	}
+}
+
+// invalidateQuota drops the accounting of all the quota policies matching repo after content was removed,
+// it is recomputed from storage on next use.
+func (is *ImageStore) invalidateQuota(repo string) {
+	if is.quota == nil {
+		return
+	}
+
+	is.quota.lock.Lock()
+	defer is.quota.lock.Unlock()
+
+	for _, scope := range is.quota.scopes {
+		if !scope.matches(repo) {
+			continue
+		}
+
+		if scope.policy.PerRepository {
+			// the repository may have been deleted, its scope is recreated on next use
+			delete(scope.repoScopes, repo)
+
+			continue
+		}
+
+		scope.blobs = nil
+		scope.used = 0
+	}
+}
+
+// quotaWriter writes an upload in progress, reserving quota for each chunk before writing it.
+type quotaWriter struct {
+	is         *ImageStore
+	dst        io.Writer
+	repo       string
+	uploadPath string
+	digest     godigest.Digest
+}
+
+func (writer quotaWriter) Write(buf []byte) (int, error) {
+	if err := writer.is.reserveQuota(writer.repo, writer.uploadPath, writer.digest, int64(len(buf))); err != nil {
+		return 0, err
+	}
+
+	return writer.dst.Write(buf)
+}
+
+// copyWithinQuota copies src to the upload stored at uploadPath through dst, failing with ErrQuotaExceeded as soon
+// as the upload doesn't fit in the quota of repo anymore, the copy is unbounded if repo is not subject to any quota.
+func (is *ImageStore) copyWithinQuota(dst io.Writer, src io.Reader, repo, uploadPath string,
+	digest godigest.Digest,
+) (int64, error) {
+	if is.quota == nil {
+		return io.Copy(dst, src)
+	}
+
+	return io.Copy(quotaWriter{is: is, dst: dst, repo: repo, uploadPath: uploadPath, digest: digest}, src)
+}
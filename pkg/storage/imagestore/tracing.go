@@ -0,0 +1,157 @@
+package imagestore
+
+import (
+	"context"
+	"io"
+
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"zotregistry.dev/zot/pkg/extensions/tracing"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+// WithContext returns a copy of the image store recording lock waits, lint and storage driver calls
+// as spans of the trace found in ctx, the copy shares its locks, cache and driver with the image store.
+// The image store itself is returned if ctx is not part of a recorded trace.
+func (is *ImageStore) WithContext(ctx context.Context) storageTypes.ImageStore {
+	if !tracing.IsRecording(ctx) {
+		return is
+	}
+
+	tracedStore := *is
+	tracedStore.ctx = ctx
+	tracedStore.storeDriver = &tracedDriver{Driver: is.storeDriver, ctx: ctx}
+
+	return &tracedStore
+}
+
+func (is *ImageStore) startSpan(name string, attrs ...attribute.KeyValue) trace.Span {
+	if is.ctx == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+
+	_, span := tracing.StartChildSpan(is.ctx, name, append(attrs, tracing.RootDirKey.String(is.rootDir))...)
+
+	return span
+}
+
+// tracedDriver records a span for each call made to the storage driver.
+type tracedDriver struct {
+	storageTypes.Driver
+	ctx context.Context //nolint:containedctx
+}
+
+func (d *tracedDriver) startSpan(method, path string) trace.Span {
+	_, span := tracing.StartChildSpan(d.ctx, "driver."+method,
+		tracing.DriverKey.String(d.Driver.Name()), tracing.PathKey.String(path))
+
+	return span
+}
+
+func (d *tracedDriver) EnsureDir(path string) error {
+	span := d.startSpan("EnsureDir", path)
+
+	err := d.Driver.EnsureDir(path)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (d *tracedDriver) DirExists(path string) bool {
+	span := d.startSpan("DirExists", path)
+	defer span.End()
+
+	return d.Driver.DirExists(path)
+}
+
+func (d *tracedDriver) Reader(path string, offset int64) (io.ReadCloser, error) {
+	span := d.startSpan("Reader", path)
+
+	reader, err := d.Driver.Reader(path, offset)
+	tracing.EndSpan(span, err)
+
+	return reader, err
+}
+
+func (d *tracedDriver) ReadFile(path string) ([]byte, error) {
+	span := d.startSpan("ReadFile", path)
+
+	content, err := d.Driver.ReadFile(path)
+	tracing.EndSpan(span, err)
+
+	return content, err
+}
+
+func (d *tracedDriver) Delete(path string) error {
+	span := d.startSpan("Delete", path)
+
+	err := d.Driver.Delete(path)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (d *tracedDriver) Stat(path string) (driver.FileInfo, error) {
+	span := d.startSpan("Stat", path)
+
+	fileInfo, err := d.Driver.Stat(path)
+	tracing.EndSpan(span, err)
+
+	return fileInfo, err
+}
+
+func (d *tracedDriver) Writer(filepath string, append bool) (driver.FileWriter, error) { //nolint: predeclared
+	span := d.startSpan("Writer", filepath)
+
+	writer, err := d.Driver.Writer(filepath, append)
+	tracing.EndSpan(span, err)
+
+	return writer, err
+}
+
+func (d *tracedDriver) WriteFile(filepath string, content []byte) (int, error) {
+	span := d.startSpan("WriteFile", filepath)
+
+	written, err := d.Driver.WriteFile(filepath, content)
+	tracing.EndSpan(span, err)
+
+	return written, err
+}
+
+func (d *tracedDriver) Walk(path string, walkFn driver.WalkFn) error {
+	span := d.startSpan("Walk", path)
+
+	err := d.Driver.Walk(path, walkFn)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (d *tracedDriver) List(fullpath string) ([]string, error) {
+	span := d.startSpan("List", fullpath)
+
+	entries, err := d.Driver.List(fullpath)
+	tracing.EndSpan(span, err)
+
+	return entries, err
+}
+
+func (d *tracedDriver) Move(sourcePath string, destPath string) error {
+	span := d.startSpan("Move", sourcePath)
+
+	err := d.Driver.Move(sourcePath, destPath)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+func (d *tracedDriver) Link(src, dest string) error {
+	span := d.startSpan("Link", src)
+
+	err := d.Driver.Link(src, dest)
+	tracing.EndSpan(span, err)
+
+	return err
+}
@@ -34,6 +34,7 @@ import (
 	"zotregistry.dev/zot/pkg/storage/cache"
 	storageConstants "zotregistry.dev/zot/pkg/storage/constants"
 	"zotregistry.dev/zot/pkg/storage/gc"
+	"zotregistry.dev/zot/pkg/storage/imagestore"
 	"zotregistry.dev/zot/pkg/storage/local"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 	. "zotregistry.dev/zot/pkg/test/image-utils"
@@ -3019,6 +3020,261 @@ func TestPutBlobChunkStreamed(t *testing.T) {
 	})
 }
 
+func TestMountBlob(t *testing.T) {
+	for _, dedupe := range []bool{true, false} {
+		Convey(fmt.Sprintf("Mount blobs across repositories, dedupe: %t", dedupe), t, func() {
+			dir := t.TempDir()
+
+			log := zlog.Logger{Logger: zerolog.New(os.Stdout)}
+			metrics := monitoring.NewMetricsServer(false, log)
+			cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+				RootDir:     dir,
+				Name:        "cache",
+				UseRelPaths: true,
+			}, log)
+
+			imgStore := local.NewImageStore(dir, dedupe, true, log, metrics, nil, cacheDriver, nil, nil)
+
+			content := []byte("blob to mount")
+			digest := godigest.FromBytes(content)
+
+			_, _, err := imgStore.FullBlobUpload("src", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+
+			size, err := imgStore.MountBlob("dst", "src", digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			blob, err := imgStore.GetBlobContent("dst", digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+
+			srcInfo, err := os.Stat(imgStore.BlobPath("src", digest))
+			So(err, ShouldBeNil)
+
+			dstInfo, err := os.Stat(imgStore.BlobPath("dst", digest))
+			So(err, ShouldBeNil)
+
+			So(os.SameFile(srcInfo, dstInfo), ShouldEqual, dedupe)
+
+			// mounting again is a no-op
+			size, err = imgStore.MountBlob("dst", "src", digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			_, err = imgStore.MountBlob("dst", "missing", digest)
+			So(err, ShouldEqual, zerr.ErrBlobNotFound)
+
+			_, err = imgStore.MountBlob("dst", "src", "sha256:")
+			So(err, ShouldNotBeNil)
+		})
+	}
+}
+
+func TestStorageQuota(t *testing.T) {
+	Convey("Quota policies are enforced on pushes", t, func() {
+		dir := t.TempDir()
+
+		log := zlog.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, log, metrics, nil, cacheDriver, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		image := CreateRandomImage()
+		imageSize := int64(image.Size())
+
+		store, ok := imgStore.(*imagestore.ImageStore)
+		So(ok, ShouldBeTrue)
+
+		store.SetQuota(config.StorageQuota{
+			Policies: []config.QuotaPolicy{
+				{
+					Repositories: []string{"team/**"},
+					SoftLimit:    imageSize / 2,
+					HardLimit:    imageSize,
+				},
+			},
+		})
+
+		err := WriteImageToFileSystem(image, "team/a", tag, storeController)
+		So(err, ShouldBeNil)
+
+		usage, err := imgStore.GetQuotaUsage()
+		So(err, ShouldBeNil)
+		So(usage, ShouldHaveLength, 1)
+		So(usage[0].Repositories, ShouldResemble, []string{"team/**"})
+		So(usage[0].Used, ShouldEqual, imageSize)
+		So(usage[0].HardLimit, ShouldEqual, imageSize)
+
+		Convey("Content already accounted doesn't use additional quota", func() {
+			err := WriteImageToFileSystem(image, "team/b", tag, storeController)
+			So(err, ShouldBeNil)
+
+			usage, err := imgStore.GetQuotaUsage()
+			So(err, ShouldBeNil)
+			So(usage[0].Used, ShouldEqual, imageSize)
+		})
+
+		Convey("Pushes going over the hard limit are denied", func() {
+			content := []byte("this blob goes over the quota")
+			digest := godigest.FromBytes(content)
+
+			_, _, err := imgStore.FullBlobUpload("team/b", bytes.NewReader(content), digest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			upload, err := imgStore.NewBlobUpload("team/b")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("team/b", upload, bytes.NewReader(content))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			upload, err = imgStore.NewBlobUpload("team/b")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunk("team/b", upload, 0, int64(len(content))-1, bytes.NewReader(content))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			manifest := image.Manifest
+			manifest.Annotations = map[string]string{"quota": "exceeded"}
+
+			manifestBlob, err := json.Marshal(manifest)
+			So(err, ShouldBeNil)
+
+			_, _, err = imgStore.PutImageManifest("team/a", "2.0", ispec.MediaTypeImageManifest, manifestBlob)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			// repositories not matching any policy are not restricted
+			_, _, err = imgStore.FullBlobUpload("other", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Uploads in progress reserve their quota", func() {
+			store.SetQuota(config.StorageQuota{
+				Policies: []config.QuotaPolicy{
+					{
+						Repositories: []string{"team/**"},
+						HardLimit:    imageSize + 10,
+					},
+				},
+			})
+
+			content := []byte("0123456789")
+			digest := godigest.FromBytes(content)
+
+			upload, err := imgStore.NewBlobUpload("team/b")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("team/b", upload, bytes.NewReader(content))
+			So(err, ShouldBeNil)
+
+			// the headroom is held by the first upload until it is finished or canceled
+			otherUpload, err := imgStore.NewBlobUpload("team/a")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("team/a", otherUpload, bytes.NewReader([]byte("a")))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			_, _, err = imgStore.FullBlobUpload("team/a", bytes.NewReader([]byte("a")), godigest.FromString("a"))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			err = imgStore.DeleteBlobUpload("team/b", upload)
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("team/a", otherUpload, bytes.NewReader(content))
+			So(err, ShouldBeNil)
+
+			err = imgStore.FinishBlobUpload("team/a", otherUpload, bytes.NewReader([]byte{}), digest)
+			So(err, ShouldBeNil)
+
+			usage, err := imgStore.GetQuotaUsage()
+			So(err, ShouldBeNil)
+			So(usage[0].Used, ShouldEqual, imageSize+10)
+
+			// the finished upload is accounted once, as a stored blob
+			_, _, err = imgStore.FullBlobUpload("team/b", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+
+			_, _, err = imgStore.FullBlobUpload("team/b", bytes.NewReader([]byte("a")), godigest.FromString("a"))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+		})
+
+		Convey("Linking deduped blobs is subject to the quota", func() {
+			store.SetQuota(config.StorageQuota{
+				Policies: []config.QuotaPolicy{
+					{
+						Repositories: []string{"small/**"},
+						HardLimit:    1,
+					},
+				},
+			})
+
+			err := imgStore.InitRepo("small/a")
+			So(err, ShouldBeNil)
+
+			found, _, err := imgStore.CheckBlob("small/a", image.Manifest.Layers[0].Digest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+			So(found, ShouldBeFalse)
+
+			found, _, err = imgStore.CheckBlob("other", image.Manifest.Layers[0].Digest)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeTrue)
+		})
+
+		Convey("Policies can be enforced per repository", func() {
+			store.SetQuota(config.StorageQuota{
+				Policies: []config.QuotaPolicy{
+					{
+						Repositories:  []string{"team/**"},
+						HardLimit:     imageSize,
+						PerRepository: true,
+					},
+				},
+			})
+
+			content := []byte("this blob goes over the quota of team/a")
+			digest := godigest.FromBytes(content)
+
+			_, _, err := imgStore.FullBlobUpload("team/a", bytes.NewReader(content), digest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			// other repositories matching the policy have their own quota
+			_, _, err = imgStore.FullBlobUpload("team/b", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+
+			usage, err := imgStore.GetQuotaUsage()
+			So(err, ShouldBeNil)
+			So(usage, ShouldHaveLength, 2)
+			So(usage[0].Repository, ShouldEqual, "team/a")
+			So(usage[0].Used, ShouldEqual, imageSize)
+			So(usage[1].Repository, ShouldEqual, "team/b")
+			So(usage[1].Used, ShouldEqual, len(content))
+			So(usage[1].HardLimit, ShouldEqual, imageSize)
+
+			err = imgStore.DeleteImageManifest("team/a", image.DigestStr(), false)
+			So(err, ShouldBeNil)
+
+			usage, err = imgStore.GetQuotaUsage()
+			So(err, ShouldBeNil)
+			So(usage[0].Used, ShouldEqual, imageSize-image.ManifestDescriptor.Size)
+		})
+
+		Convey("Deleting content releases quota", func() {
+			err := imgStore.DeleteImageManifest("team/a", image.DigestStr(), false)
+			So(err, ShouldBeNil)
+
+			usage, err := imgStore.GetQuotaUsage()
+			So(err, ShouldBeNil)
+			So(usage[0].Used, ShouldEqual, imageSize-image.ManifestDescriptor.Size)
+		})
+	})
+}
+
 func TestPullRange(t *testing.T) {
 	Convey("Repo layout", t, func(c C) {
 		dir := t.TempDir()
@@ -0,0 +1,35 @@
+package objectstore
+
+import (
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+
+	"zotregistry.dev/zot/pkg/compat"
+	"zotregistry.dev/zot/pkg/extensions/events"
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	zlog "zotregistry.dev/zot/pkg/log"
+	common "zotregistry.dev/zot/pkg/storage/common"
+	"zotregistry.dev/zot/pkg/storage/imagestore"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+)
+
+// NewImageStore returns a new image store backed by the cloud storage driver named name.
+// see https://distribution.github.io/distribution/storage-drivers/
+// Use the last argument to properly set a cache database, or it will default to boltDB local storage.
+func NewImageStore(name string, rootDir string, cacheDir string, dedupe, commit bool, log zlog.Logger,
+	metrics monitoring.MetricServer, linter common.Lint, store driver.StorageDriver,
+	cacheDriver storageTypes.Cache, compat []compat.MediaCompatibility, recorder events.Recorder,
+) storageTypes.ImageStore {
+	return imagestore.NewImageStore(
+		rootDir,
+		cacheDir,
+		dedupe,
+		commit,
+		log,
+		metrics,
+		linter,
+		New(name, store),
+		cacheDriver,
+		compat,
+		recorder,
+	)
+}
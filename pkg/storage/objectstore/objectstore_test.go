@@ -0,0 +1,252 @@
+package objectstore_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+	"github.com/distribution/distribution/v3/registry/storage/driver/factory"
+	_ "github.com/distribution/distribution/v3/registry/storage/driver/inmemory"
+	guuid "github.com/gofrs/uuid"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/rs/zerolog"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/api/option"
+
+	"zotregistry.dev/zot/pkg/extensions/monitoring"
+	"zotregistry.dev/zot/pkg/log"
+	zstorage "zotregistry.dev/zot/pkg/storage"
+	"zotregistry.dev/zot/pkg/storage/cache"
+	storageConstants "zotregistry.dev/zot/pkg/storage/constants"
+	"zotregistry.dev/zot/pkg/storage/objectstore"
+	storageTypes "zotregistry.dev/zot/pkg/storage/types"
+	. "zotregistry.dev/zot/pkg/test/image-utils"
+	tskip "zotregistry.dev/zot/pkg/test/skip"
+)
+
+// well known credentials of the azurite emulator.
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+func createImageStore(t *testing.T, name string, store driver.StorageDriver, dedupe bool) storageTypes.ImageStore {
+	t.Helper()
+
+	log := log.Logger{Logger: zerolog.New(os.Stdout)}
+	metrics := monitoring.NewMetricsServer(false, log)
+
+	cacheDir := t.TempDir()
+	cacheDriver, _ := zstorage.Create("boltdb", cache.BoltDBDriverParameters{
+		RootDir:     cacheDir,
+		Name:        "cache",
+		UseRelPaths: false,
+	}, log)
+
+	return objectstore.NewImageStore(name, "/zot", cacheDir, dedupe, false, log, metrics, nil, store,
+		cacheDriver, nil, nil)
+}
+
+// testImageStore checks the image store operations which rely on the object storage semantics.
+func testImageStore(t *testing.T, name string, store driver.StorageDriver) {
+	t.Helper()
+
+	for _, dedupe := range []bool{true, false} {
+		Convey(fmt.Sprintf("Push, pull and delete images with dedupe %v", dedupe), t, func() {
+			imgStore := createImageStore(t, name, store, dedupe)
+			So(imgStore.Name(), ShouldEqual, name)
+
+			image := CreateRandomImage()
+			storeController := zstorage.StoreController{DefaultStore: imgStore}
+
+			err := WriteImageToFileSystem(image, "repo1", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			err = WriteImageToFileSystem(image, "repo2", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			repos, err := imgStore.GetRepositories()
+			So(err, ShouldBeNil)
+			So(repos, ShouldContain, "repo1")
+			So(repos, ShouldContain, "repo2")
+
+			for _, layer := range image.Layers {
+				digest := godigest.FromBytes(layer)
+
+				ok, size, err := imgStore.CheckBlob("repo2", digest)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(size, ShouldEqual, len(layer))
+
+				buf, err := imgStore.GetBlobContent("repo2", digest)
+				So(err, ShouldBeNil)
+				So(buf, ShouldResemble, layer)
+
+				fileInfo, err := store.Stat(context.Background(), imgStore.BlobPath("repo2", digest))
+				So(err, ShouldBeNil)
+
+				if dedupe {
+					// deduped blobs are stored as empty placeholders
+					So(fileInfo.Size(), ShouldEqual, 0)
+				} else {
+					So(fileInfo.Size(), ShouldEqual, len(layer))
+				}
+			}
+
+			manifestBlob, manifestDigest, mediaType, err := imgStore.GetImageManifest("repo1", "1.0")
+			So(err, ShouldBeNil)
+			So(manifestDigest, ShouldEqual, image.ManifestDescriptor.Digest)
+			So(mediaType, ShouldEqual, ispec.MediaTypeImageManifest)
+			So(manifestBlob, ShouldNotBeEmpty)
+
+			tags, err := imgStore.GetImageTags("repo2")
+			So(err, ShouldBeNil)
+			So(tags, ShouldResemble, []string{"1.0"})
+
+			err = imgStore.DeleteImageManifest("repo1", image.DigestStr(), false)
+			So(err, ShouldBeNil)
+
+			_, _, _, err = imgStore.GetImageManifest("repo1", "1.0")
+			So(err, ShouldNotBeNil)
+
+			// the blobs of the second repo are still readable after deleting the ones of the first repo
+			for _, layer := range image.Layers {
+				digest := godigest.FromBytes(layer)
+
+				err = imgStore.DeleteBlob("repo1", digest)
+				So(err, ShouldBeNil)
+
+				buf, err := imgStore.GetBlobContent("repo2", digest)
+				So(err, ShouldBeNil)
+				So(buf, ShouldResemble, layer)
+			}
+		})
+	}
+}
+
+func TestObjectStoreDriver(t *testing.T) {
+	Convey("Object storage drivers are named after the storage they use", t, func() {
+		// the object storage semantics are the same for every driver, use an in memory one
+		store, err := factory.Create(context.Background(), "inmemory", nil)
+		So(err, ShouldBeNil)
+
+		for _, name := range []string{
+			storageConstants.S3StorageDriverName,
+			storageConstants.GCSStorageDriverName,
+			storageConstants.AzureStorageDriverName,
+		} {
+			So(objectstore.New(name, store).Name(), ShouldEqual, name)
+		}
+	})
+
+	store, err := factory.Create(context.Background(), "inmemory", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	testImageStore(t, storageConstants.GCSStorageDriverName, store)
+}
+
+func TestGCSImageStore(t *testing.T) {
+	tskip.SkipGCS(t)
+
+	endpoint := os.Getenv("GCSMOCK_ENDPOINT")
+	bucket := "zot-storage-test"
+	rootDir := path.Join("/oci-repo-test", guuid.Must(guuid.NewV4()).String())
+
+	// the gcs client connects to the emulator instead of the google apis
+	t.Setenv("STORAGE_EMULATOR_HOST", endpoint)
+
+	client, err := storage.NewClient(context.Background(), option.WithoutAuthentication())
+	if err != nil {
+		panic(err)
+	}
+
+	defer client.Close()
+
+	err = client.Bucket(bucket).Create(context.Background(), "zot", nil)
+	if err != nil {
+		// the bucket might be created by a previous run
+		if _, attrsErr := client.Bucket(bucket).Attrs(context.Background()); attrsErr != nil {
+			panic(err)
+		}
+	}
+
+	// the driver requires service account credentials, the emulator doesn't check them
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	store, err := factory.Create(context.Background(), storageConstants.GCSStorageDriverName,
+		map[string]interface{}{
+			"bucket":        bucket,
+			"rootdirectory": rootDir,
+			"credentials": map[interface{}]interface{}{
+				"type":         "service_account",
+				"client_email": "zot@zot.iam.gserviceaccount.com",
+				"private_key": string(pem.EncodeToMemory(&pem.Block{
+					Type:  "RSA PRIVATE KEY",
+					Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+				})),
+			},
+		})
+	if err != nil {
+		panic(err)
+	}
+
+	defer func() { _ = store.Delete(context.Background(), "/") }()
+
+	testImageStore(t, storageConstants.GCSStorageDriverName, store)
+}
+
+func TestAzureImageStore(t *testing.T) {
+	tskip.SkipAzure(t)
+
+	serviceURL := fmt.Sprintf("http://%s/%s", os.Getenv("AZURITE_ENDPOINT"), azuriteAccountName)
+	container := "zot-storage-test"
+	rootDir := path.Join("/oci-repo-test", guuid.Must(guuid.NewV4()).String())
+
+	credential, err := azblob.NewSharedKeyCredential(azuriteAccountName, azuriteAccountKey)
+	if err != nil {
+		panic(err)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// the container might be created by a previous run
+	_, _ = client.CreateContainer(context.Background(), container, nil)
+
+	store, err := factory.Create(context.Background(), storageConstants.AzureStorageDriverName,
+		map[string]interface{}{
+			"accountname":   azuriteAccountName,
+			"accountkey":    azuriteAccountKey,
+			"container":     container,
+			"serviceurl":    serviceURL,
+			"rootdirectory": rootDir,
+			"credentials": map[string]interface{}{
+				"type": "shared_key",
+			},
+		})
+	if err != nil {
+		panic(err)
+	}
+
+	defer func() { _ = store.Delete(context.Background(), "/") }()
+
+	testImageStore(t, storageConstants.AzureStorageDriverName, store)
+}
@@ -2774,6 +2774,68 @@ func TestRebuildDedupeMockStoreDriver(t *testing.T) {
 	})
 }
 
+func TestS3MountBlob(t *testing.T) {
+	tskip.SkipS3(t)
+
+	for _, dedupe := range []bool{true, false} {
+		Convey(fmt.Sprintf("Mount blobs across repositories, dedupe: %t", dedupe), t, func() {
+			uuid, err := guuid.NewV4()
+			if err != nil {
+				panic(err)
+			}
+
+			testDir := path.Join("/oci-repo-test", uuid.String())
+
+			storeDriver, imgStore, _ := createObjectsStore(testDir, t.TempDir(), dedupe)
+			defer cleanupStorage(storeDriver, testDir)
+
+			content := []byte("blob to mount")
+			digest := godigest.FromBytes(content)
+
+			_, _, err = imgStore.FullBlobUpload("src", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+
+			size, err := imgStore.MountBlob("dst", "src", digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			blob, err := imgStore.GetBlobContent("dst", digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+
+			dstInfo, err := storeDriver.Stat(context.Background(), imgStore.BlobPath("dst", digest))
+			So(err, ShouldBeNil)
+
+			// with dedupe the mounted blob is an empty file pointing to the original one
+			if dedupe {
+				So(dstInfo.Size(), ShouldEqual, 0)
+			} else {
+				So(dstInfo.Size(), ShouldEqual, len(content))
+			}
+
+			// mounting again is a no-op
+			size, err = imgStore.MountBlob("dst", "src", digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			// a mounted blob can be mounted in turn
+			size, err = imgStore.MountBlob("other", "dst", digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			blob, err = imgStore.GetBlobContent("other", digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+
+			_, err = imgStore.MountBlob("dst", "missing", digest)
+			So(err, ShouldEqual, zerr.ErrBlobNotFound)
+
+			_, err = imgStore.MountBlob("dst", "src", "sha256:")
+			So(err, ShouldNotBeNil)
+		})
+	}
+}
+
 func TestS3PullRange(t *testing.T) {
 	tskip.SkipS3(t)
 
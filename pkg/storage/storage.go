@@ -18,8 +18,9 @@ import (
 	"zotregistry.dev/zot/pkg/log"
 	common "zotregistry.dev/zot/pkg/storage/common"
 	"zotregistry.dev/zot/pkg/storage/constants"
+	"zotregistry.dev/zot/pkg/storage/imagestore"
 	"zotregistry.dev/zot/pkg/storage/local"
-	"zotregistry.dev/zot/pkg/storage/s3"
+	"zotregistry.dev/zot/pkg/storage/objectstore"
 	storageTypes "zotregistry.dev/zot/pkg/storage/types"
 )
 
@@ -62,40 +63,18 @@ func New(config *config.Config, linter common.Lint, metrics monitoring.MetricSer
 			config.Storage.Dedupe, config.Storage.Commit, log, metrics, linter, cacheDriver, config.HTTP.Compat, recorder,
 		)
 	} else {
-		storeName := fmt.Sprintf("%v", config.Storage.StorageDriver["name"])
-		if storeName != constants.S3StorageDriverName {
-			log.Error().Err(zerr.ErrBadConfig).Str("storageDriver", storeName).
-				Msg("unsupported storage driver")
+		var err error
 
-			return storeController, fmt.Errorf("storageDriver '%s' unsupported storage driver: %w", storeName, zerr.ErrBadConfig)
-		}
-
-		// Init a Storager from connection string.
-		store, err := factory.Create(context.Background(), storeName, config.Storage.StorageDriver)
-		if err != nil {
-			log.Error().Err(err).Str("rootDir", config.Storage.RootDirectory).Msg("failed to create s3 service")
-
-			return storeController, err
-		}
-
-		/* in the case of s3 config.Storage.RootDirectory is used for caching blobs locally and
-		config.Storage.StorageDriver["rootdirectory"] is the actual rootDir in s3 */
-		rootDir := "/"
-		if config.Storage.StorageDriver["rootdirectory"] != nil {
-			rootDir = fmt.Sprintf("%v", config.Storage.StorageDriver["rootdirectory"])
-		}
-
-		cacheDriver, err := CreateCacheDatabaseDriver(config.Storage.StorageConfig, log)
+		//nolint: contextcheck
+		defaultStore, err = newObjectStorageImageStore(config, config.Storage.StorageConfig,
+			objectStorageRootDir(config.Storage.StorageDriver), linter, metrics, log, recorder)
 		if err != nil {
 			return storeController, err
 		}
-
-		// false positive lint - linter does not implement Lint method
-		//nolint: typecheck,contextcheck
-		defaultStore = s3.NewImageStore(rootDir, config.Storage.RootDirectory,
-			config.Storage.Dedupe, config.Storage.Commit, log, metrics, linter, store, cacheDriver, config.HTTP.Compat, recorder)
 	}
 
+	setQuota(defaultStore, config.Storage.Quota)
+
 	storeController.DefaultStore = defaultStore
 
 	if config.Storage.SubPaths != nil {
@@ -174,51 +153,89 @@ func getSubStore(cfg *config.Config, subPaths map[string]config.StorageConfig,
 					storageConfig.Dedupe, storageConfig.Commit, log, metrics, linter, cacheDriver, cfg.HTTP.Compat, recorder,
 				)
 
+				setQuota(imgStoreMap[storageConfig.RootDirectory], storageConfig.Quota)
+
 				subImageStore[route] = imgStoreMap[storageConfig.RootDirectory]
 			}
 		} else {
-			storeName := fmt.Sprintf("%v", storageConfig.StorageDriver["name"])
-			if storeName != constants.S3StorageDriverName {
-				log.Error().Err(zerr.ErrBadConfig).Str("storageDriver", storeName).
-					Msg("unsupported storage driver")
-
-				return nil, fmt.Errorf("storageDriver '%s' unsupported storage driver: %w", storeName, zerr.ErrBadConfig)
-			}
-
-			// Init a Storager from connection string.
-			store, err := factory.Create(context.Background(), storeName, storageConfig.StorageDriver)
+			// the image stores of the subpaths use the rootdirectory of the default storage driver
+			imgStore, err := newObjectStorageImageStore(cfg, storageConfig, objectStorageRootDir(cfg.Storage.StorageDriver),
+				linter, metrics, log, recorder)
 			if err != nil {
-				log.Error().Err(err).Str("rootDir", storageConfig.RootDirectory).Msg("failed to create s3 service")
-
 				return nil, err
 			}
 
-			/* in the case of s3 c.Config.Storage.RootDirectory is used for caching blobs locally and
-			c.Config.Storage.StorageDriver["rootdirectory"] is the actual rootDir in s3 */
-			rootDir := "/"
-			if cfg.Storage.StorageDriver["rootdirectory"] != nil {
-				rootDir = fmt.Sprintf("%v", cfg.Storage.StorageDriver["rootdirectory"])
-			}
-
-			cacheDriver, err := CreateCacheDatabaseDriver(storageConfig, log)
-			if err != nil {
-				log.Error().Err(err).Any("config", storageConfig).
-					Msg("failed to create storage driver")
-
-				return nil, err
-			}
+			subImageStore[route] = imgStore
 
-			// false positive lint - linter does not implement Lint method
-			//nolint: typecheck
-			subImageStore[route] = s3.NewImageStore(rootDir, storageConfig.RootDirectory,
-				storageConfig.Dedupe, storageConfig.Commit, log, metrics, linter, store, cacheDriver, cfg.HTTP.Compat, recorder,
-			)
+			setQuota(subImageStore[route], storageConfig.Quota)
 		}
 	}
 
 	return subImageStore, nil
 }
 
+// newObjectStorageImageStore creates an image store backed by one of the supported cloud storage drivers.
+func newObjectStorageImageStore(cfg *config.Config, storageConfig config.StorageConfig, rootDir string,
+	linter common.Lint, metrics monitoring.MetricServer, log log.Logger, recorder events.Recorder,
+) (storageTypes.ImageStore, error) {
+	storeName := fmt.Sprintf("%v", storageConfig.StorageDriver["name"])
+	if !IsObjectStorageDriver(storeName) {
+		log.Error().Err(zerr.ErrBadConfig).Str("storageDriver", storeName).
+			Msg("unsupported storage driver")
+
+		return nil, fmt.Errorf("storageDriver '%s' unsupported storage driver: %w", storeName, zerr.ErrBadConfig)
+	}
+
+	// Init a Storager from connection string.
+	store, err := factory.Create(context.Background(), storeName, storageConfig.StorageDriver)
+	if err != nil {
+		log.Error().Err(err).Str("rootDir", storageConfig.RootDirectory).Str("storageDriver", storeName).
+			Msg("failed to create storage driver")
+
+		return nil, err
+	}
+
+	cacheDriver, err := CreateCacheDatabaseDriver(storageConfig, log)
+	if err != nil {
+		log.Error().Err(err).Any("config", storageConfig).
+			Msg("failed to create cache driver")
+
+		return nil, err
+	}
+
+	// false positive lint - linter does not implement Lint method
+	//nolint: typecheck
+	return objectstore.NewImageStore(storeName, rootDir, storageConfig.RootDirectory, storageConfig.Dedupe,
+		storageConfig.Commit, log, metrics, linter, store, cacheDriver, cfg.HTTP.Compat, recorder), nil
+}
+
+// objectStorageRootDir returns the root directory of the image store in the bucket/container of a cloud storage,
+// in this case the RootDirectory of the storage config is used for caching blobs locally.
+func objectStorageRootDir(storageDriver map[string]interface{}) string {
+	if storageDriver["rootdirectory"] != nil {
+		return fmt.Sprintf("%v", storageDriver["rootdirectory"])
+	}
+
+	return "/"
+}
+
+// IsObjectStorageDriver returns true if name is one of the supported cloud storage drivers.
+func IsObjectStorageDriver(name string) bool {
+	switch name {
+	case constants.S3StorageDriverName, constants.GCSStorageDriverName, constants.AzureStorageDriverName:
+		return true
+	default:
+		return false
+	}
+}
+
+// setQuota configures the storage quota policies of an image store created by this package.
+func setQuota(imgStore storageTypes.ImageStore, quota config.StorageQuota) {
+	if store, ok := imgStore.(*imagestore.ImageStore); ok {
+		store.SetQuota(quota)
+	}
+}
+
 func compareImageStore(root1, root2 string) bool {
 	isSameFile, err := config.SameFile(root1, root2)
 	if err != nil {
@@ -51,6 +51,7 @@ type ImageStore interface { //nolint:interfacebloat
 	DeleteBlobUpload(repo, uuid string) error
 	BlobPath(repo string, digest godigest.Digest) string
 	CheckBlob(repo string, digest godigest.Digest) (bool, int64, error)
+	MountBlob(repo, fromRepo string, digest godigest.Digest) (int64, error)
 	StatBlob(repo string, digest godigest.Digest) (bool, int64, time.Time, error)
 	GetBlob(repo string, digest godigest.Digest, mediaType string) (io.ReadCloser, int64, error)
 	GetBlobPartial(repo string, digest godigest.Digest, mediaType string, from, to int64,
@@ -69,6 +70,19 @@ type ImageStore interface { //nolint:interfacebloat
 	PopulateStorageMetrics(interval time.Duration, sch *scheduler.Scheduler)
 	VerifyBlobDigestValue(repo string, digest godigest.Digest) error
 	GetAllDedupeReposCandidates(digest godigest.Digest) ([]string, error)
+	GetQuotaUsage() ([]QuotaUsage, error)
+	WithContext(ctx context.Context) ImageStore
+}
+
+// QuotaUsage describes the storage consumed by the repositories matched by a quota policy,
+// blobs shared between these repositories are only accounted once.
+// For policies enforced per repository there is one QuotaUsage for each matching repository.
+type QuotaUsage struct {
+	Repositories []string `json:"repositories"`
+	Repository   string   `json:"repository,omitempty"`
+	SoftLimit    int64    `json:"softLimit"`
+	HardLimit    int64    `json:"hardLimit"`
+	Used         int64    `json:"used"`
 }
 
 type Driver interface { //nolint:interfacebloat
@@ -77,8 +77,8 @@ func (cveInfo CveInfoMock) GetCVESummaryForImageMedia(ctx context.Context, repo,
 type CveScannerMock struct {
 	IsImageFormatScannableFn func(repo string, reference string) (bool, error)
 	IsImageMediaScannableFn  func(repo string, digest, mediaType string) (bool, error)
-	IsResultCachedFn         func(digest string) bool
-	GetCachedResultFn        func(digest string) map[string]cvemodel.CVE
+	IsResultCachedFn         func(repo, digest string) bool
+	GetCachedResultFn        func(repo, digest string) map[string]cvemodel.CVE
 	ScanImageFn              func(ctx context.Context, image string) (map[string]cvemodel.CVE, error)
 	UpdateDBFn               func(ctx context.Context) error
 }
@@ -99,17 +99,17 @@ func (scanner CveScannerMock) IsImageMediaScannable(repo string, digest, mediaTy
 	return true, nil
 }
 
-func (scanner CveScannerMock) IsResultCached(digest string) bool {
+func (scanner CveScannerMock) IsResultCached(repo, digest string) bool {
 	if scanner.IsResultCachedFn != nil {
-		return scanner.IsResultCachedFn(digest)
+		return scanner.IsResultCachedFn(repo, digest)
 	}
 
 	return false
 }
 
-func (scanner CveScannerMock) GetCachedResult(digest string) map[string]cvemodel.CVE {
+func (scanner CveScannerMock) GetCachedResult(repo, digest string) map[string]cvemodel.CVE {
 	if scanner.GetCachedResultFn != nil {
-		return scanner.GetCachedResultFn(digest)
+		return scanner.GetCachedResultFn(repo, digest)
 	}
 
 	return map[string]cvemodel.CVE{}
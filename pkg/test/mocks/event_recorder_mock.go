@@ -0,0 +1,104 @@
+package mocks
+
+type EventRecorderMock struct {
+	CloseFn                 func()
+	RepositoryCreatedFn     func(name string)
+	ImageUpdatedFn          func(name, reference, digest, mediaType, manifest string)
+	ImageDeletedFn          func(name, reference, digest, mediaType string)
+	ImageLintFailedFn       func(name, reference, digest, mediaType, manifest string, lintErr error)
+	ImagePulledFn           func(name, reference, digest, mediaType string)
+	ImageGarbageCollectedFn func(name, reference, digest, mediaType, rule string)
+	BlobPushedFn            func(name, digest string, size int64)
+	SignatureAddedFn        func(name, subjectDigest, signatureDigest, signatureType string)
+	SignatureDeletedFn      func(name, subjectDigest, signatureDigest, signatureType string)
+	ReferrerAddedFn         func(name, subjectDigest, referrerDigest, artifactType string)
+	SyncCompletedFn         func(name, reference string)
+	SyncFailedFn            func(name, reference string, err error)
+	CVEScanCompletedFn      func(name, digest, maxSeverity string, severityCounts map[string]int)
+}
+
+func (recorder EventRecorderMock) Close() {
+	if recorder.CloseFn != nil {
+		recorder.CloseFn()
+	}
+}
+
+func (recorder EventRecorderMock) RepositoryCreated(name string) {
+	if recorder.RepositoryCreatedFn != nil {
+		recorder.RepositoryCreatedFn(name)
+	}
+}
+
+func (recorder EventRecorderMock) ImageUpdated(name, reference, digest, mediaType, manifest string) {
+	if recorder.ImageUpdatedFn != nil {
+		recorder.ImageUpdatedFn(name, reference, digest, mediaType, manifest)
+	}
+}
+
+func (recorder EventRecorderMock) ImageDeleted(name, reference, digest, mediaType string) {
+	if recorder.ImageDeletedFn != nil {
+		recorder.ImageDeletedFn(name, reference, digest, mediaType)
+	}
+}
+
+func (recorder EventRecorderMock) ImageLintFailed(name, reference, digest, mediaType, manifest string,
+	lintErr error,
+) {
+	if recorder.ImageLintFailedFn != nil {
+		recorder.ImageLintFailedFn(name, reference, digest, mediaType, manifest, lintErr)
+	}
+}
+
+func (recorder EventRecorderMock) ImagePulled(name, reference, digest, mediaType string) {
+	if recorder.ImagePulledFn != nil {
+		recorder.ImagePulledFn(name, reference, digest, mediaType)
+	}
+}
+
+func (recorder EventRecorderMock) ImageGarbageCollected(name, reference, digest, mediaType, rule string) {
+	if recorder.ImageGarbageCollectedFn != nil {
+		recorder.ImageGarbageCollectedFn(name, reference, digest, mediaType, rule)
+	}
+}
+
+func (recorder EventRecorderMock) BlobPushed(name, digest string, size int64) {
+	if recorder.BlobPushedFn != nil {
+		recorder.BlobPushedFn(name, digest, size)
+	}
+}
+
+func (recorder EventRecorderMock) SignatureAdded(name, subjectDigest, signatureDigest, signatureType string) {
+	if recorder.SignatureAddedFn != nil {
+		recorder.SignatureAddedFn(name, subjectDigest, signatureDigest, signatureType)
+	}
+}
+
+func (recorder EventRecorderMock) SignatureDeleted(name, subjectDigest, signatureDigest, signatureType string) {
+	if recorder.SignatureDeletedFn != nil {
+		recorder.SignatureDeletedFn(name, subjectDigest, signatureDigest, signatureType)
+	}
+}
+
+func (recorder EventRecorderMock) ReferrerAdded(name, subjectDigest, referrerDigest, artifactType string) {
+	if recorder.ReferrerAddedFn != nil {
+		recorder.ReferrerAddedFn(name, subjectDigest, referrerDigest, artifactType)
+	}
+}
+
+func (recorder EventRecorderMock) SyncCompleted(name, reference string) {
+	if recorder.SyncCompletedFn != nil {
+		recorder.SyncCompletedFn(name, reference)
+	}
+}
+
+func (recorder EventRecorderMock) SyncFailed(name, reference string, err error) {
+	if recorder.SyncFailedFn != nil {
+		recorder.SyncFailedFn(name, reference, err)
+	}
+}
+
+func (recorder EventRecorderMock) CVEScanCompleted(name, digest, maxSeverity string, severityCounts map[string]int) {
+	if recorder.CVEScanCompletedFn != nil {
+		recorder.CVEScanCompletedFn(name, digest, maxSeverity, severityCounts)
+	}
+}
@@ -40,6 +40,7 @@ type MockedImageStore struct {
 	DeleteBlobUploadFn     func(repo string, uuid string) error
 	BlobPathFn             func(repo string, digest godigest.Digest) string
 	CheckBlobFn            func(repo string, digest godigest.Digest) (bool, int64, error)
+	MountBlobFn            func(repo, fromRepo string, digest godigest.Digest) (int64, error)
 	StatBlobFn             func(repo string, digest godigest.Digest) (bool, int64, time.Time, error)
 	GetBlobPartialFn       func(repo string, digest godigest.Digest, mediaType string, from, to int64,
 	) (io.ReadCloser, int64, int64, error)
@@ -62,6 +63,8 @@ type MockedImageStore struct {
 	StatIndexFn                   func(repo string) (bool, int64, time.Time, error)
 	VerifyBlobDigestValueFn       func(repo string, digest godigest.Digest) error
 	GetAllDedupeReposCandidatesFn func(digest godigest.Digest) ([]string, error)
+	GetQuotaUsageFn               func() ([]storageTypes.QuotaUsage, error)
+	WithContextFn                 func(ctx context.Context) storageTypes.ImageStore
 }
 
 func (is MockedImageStore) StatIndex(repo string) (bool, int64, time.Time, error) {
@@ -313,6 +316,14 @@ func (is MockedImageStore) CheckBlob(repo string, digest godigest.Digest) (bool,
 	return true, 0, nil
 }
 
+func (is MockedImageStore) MountBlob(repo, fromRepo string, digest godigest.Digest) (int64, error) {
+	if is.MountBlobFn != nil {
+		return is.MountBlobFn(repo, fromRepo, digest)
+	}
+
+	return 0, nil
+}
+
 func (is MockedImageStore) StatBlob(repo string, digest godigest.Digest) (bool, int64, time.Time, error) {
 	if is.StatBlobFn != nil {
 		return is.StatBlobFn(repo, digest)
@@ -458,3 +469,19 @@ func (is MockedImageStore) GetAllDedupeReposCandidates(digest godigest.Digest) (
 
 	return []string{}, nil
 }
+
+func (is MockedImageStore) GetQuotaUsage() ([]storageTypes.QuotaUsage, error) {
+	if is.GetQuotaUsageFn != nil {
+		return is.GetQuotaUsageFn()
+	}
+
+	return []storageTypes.QuotaUsage{}, nil
+}
+
+func (is MockedImageStore) WithContext(ctx context.Context) storageTypes.ImageStore {
+	if is.WithContextFn != nil {
+		return is.WithContextFn(ctx)
+	}
+
+	return is
+}
@@ -6,6 +6,7 @@ import (
 
 	godigest "github.com/opencontainers/go-digest"
 
+	zerr "zotregistry.dev/zot/errors"
 	mTypes "zotregistry.dev/zot/pkg/meta/types"
 )
 
@@ -44,6 +45,22 @@ type MetaDBMock struct {
 
 	DeleteUserAPIKeyFn func(ctx context.Context, id string) error
 
+	AddRobotAccountFn func(robot mTypes.RobotAccount) error
+
+	UpdateRobotAccountFn func(robot mTypes.RobotAccount) error
+
+	GetRobotAccountFn func(name string) (mTypes.RobotAccount, error)
+
+	GetRobotAccountsFn func() ([]mTypes.RobotAccount, error)
+
+	DeleteRobotAccountFn func(name string) error
+
+	AddAccessControlPoliciesFn func(policies mTypes.AccessControlPolicies) error
+
+	GetAccessControlPoliciesFn func(version int) (mTypes.AccessControlPolicies, error)
+
+	GetAccessControlPoliciesHistoryFn func() ([]mTypes.AccessControlPolicies, error)
+
 	PatchDBFn func() error
 
 	ImageTrustStoreFn func() mTypes.ImageTrustStore
@@ -69,6 +86,8 @@ type MetaDBMock struct {
 
 	DecrementRepoStarsFn func(repo string) error
 
+	SetRepoProxyCacheFn func(repo string, upstream string) error
+
 	SetRepoMetaFn func(repo string, repoMeta mTypes.RepoMeta) error
 
 	DeleteReferrerFn func(repo string, referredDigest godigest.Digest, referrerDigest godigest.Digest) error
@@ -85,6 +104,21 @@ type MetaDBMock struct {
 
 	DeleteSignatureFn func(repo string, signedManifestDigest godigest.Digest, sigMeta mTypes.SignatureMetadata) error
 
+	AddManifestSbomFn func(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+		packages []mTypes.SbomPackage) error
+
+	GetSbomPackagesFn func(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error)
+
+	GetSbomsWithPackageFn func(name string) ([]godigest.Digest, error)
+
+	SetCVEScanResultFn func(digest godigest.Digest, result mTypes.CVEScanResult) error
+
+	GetCVEScanResultFn func(digest godigest.Digest) (mTypes.CVEScanResult, error)
+
+	DeleteCVEScanResultFn func(digest godigest.Digest) error
+
+	DeleteCVEScanResultsFn func(isStale func(result mTypes.CVEScanResult) bool) error
+
 	SetImageMetaFn func(digest godigest.Digest, imageMeta mTypes.ImageMeta) error
 
 	FilterTagsFn func(ctx context.Context, filterRepoTag mTypes.FilterRepoTagFunc,
@@ -279,6 +313,70 @@ func (sdm MetaDBMock) DeleteUserAPIKey(ctx context.Context, id string) error {
 	return nil
 }
 
+func (sdm MetaDBMock) AddRobotAccount(robot mTypes.RobotAccount) error {
+	if sdm.AddRobotAccountFn != nil {
+		return sdm.AddRobotAccountFn(robot)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) UpdateRobotAccount(robot mTypes.RobotAccount) error {
+	if sdm.UpdateRobotAccountFn != nil {
+		return sdm.UpdateRobotAccountFn(robot)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetRobotAccount(name string) (mTypes.RobotAccount, error) {
+	if sdm.GetRobotAccountFn != nil {
+		return sdm.GetRobotAccountFn(name)
+	}
+
+	return mTypes.RobotAccount{}, zerr.ErrRobotAccountNotFound
+}
+
+func (sdm MetaDBMock) GetRobotAccounts() ([]mTypes.RobotAccount, error) {
+	if sdm.GetRobotAccountsFn != nil {
+		return sdm.GetRobotAccountsFn()
+	}
+
+	return []mTypes.RobotAccount{}, nil
+}
+
+func (sdm MetaDBMock) DeleteRobotAccount(name string) error {
+	if sdm.DeleteRobotAccountFn != nil {
+		return sdm.DeleteRobotAccountFn(name)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) AddAccessControlPolicies(policies mTypes.AccessControlPolicies) error {
+	if sdm.AddAccessControlPoliciesFn != nil {
+		return sdm.AddAccessControlPoliciesFn(policies)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetAccessControlPolicies(version int) (mTypes.AccessControlPolicies, error) {
+	if sdm.GetAccessControlPoliciesFn != nil {
+		return sdm.GetAccessControlPoliciesFn(version)
+	}
+
+	return mTypes.AccessControlPolicies{}, zerr.ErrAccessControlPoliciesNotFound
+}
+
+func (sdm MetaDBMock) GetAccessControlPoliciesHistory() ([]mTypes.AccessControlPolicies, error) {
+	if sdm.GetAccessControlPoliciesHistoryFn != nil {
+		return sdm.GetAccessControlPoliciesHistoryFn()
+	}
+
+	return []mTypes.AccessControlPolicies{}, nil
+}
+
 func (sdm MetaDBMock) SetImageMeta(digest godigest.Digest, imageMeta mTypes.ImageMeta) error {
 	if sdm.SetImageMetaFn != nil {
 		return sdm.SetImageMetaFn(digest, imageMeta)
@@ -374,6 +472,14 @@ func (sdm MetaDBMock) DecrementRepoStars(repo string) error {
 	return nil
 }
 
+func (sdm MetaDBMock) SetRepoProxyCache(repo string, upstream string) error {
+	if sdm.SetRepoProxyCacheFn != nil {
+		return sdm.SetRepoProxyCacheFn(repo, upstream)
+	}
+
+	return nil
+}
+
 func (sdm MetaDBMock) SetRepoMeta(repo string, repoMeta mTypes.RepoMeta) error {
 	if sdm.SetRepoMetaFn != nil {
 		return sdm.SetRepoMetaFn(repo, repoMeta)
@@ -418,6 +524,64 @@ func (sdm MetaDBMock) AddManifestSignature(repo string, signedManifestDigest god
 	return nil
 }
 
+func (sdm MetaDBMock) AddManifestSbom(repo string, subjectDigest godigest.Digest, sbom mTypes.SbomInfo,
+	packages []mTypes.SbomPackage,
+) error {
+	if sdm.AddManifestSbomFn != nil {
+		return sdm.AddManifestSbomFn(repo, subjectDigest, sbom, packages)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetSbomPackages(sbomDigest godigest.Digest) ([]mTypes.SbomPackage, error) {
+	if sdm.GetSbomPackagesFn != nil {
+		return sdm.GetSbomPackagesFn(sbomDigest)
+	}
+
+	return nil, zerr.ErrSbomPackagesNotFound
+}
+
+func (sdm MetaDBMock) GetSbomsWithPackage(name string) ([]godigest.Digest, error) {
+	if sdm.GetSbomsWithPackageFn != nil {
+		return sdm.GetSbomsWithPackageFn(name)
+	}
+
+	return []godigest.Digest{}, nil
+}
+
+func (sdm MetaDBMock) SetCVEScanResult(digest godigest.Digest, result mTypes.CVEScanResult) error {
+	if sdm.SetCVEScanResultFn != nil {
+		return sdm.SetCVEScanResultFn(digest, result)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetCVEScanResult(digest godigest.Digest) (mTypes.CVEScanResult, error) {
+	if sdm.GetCVEScanResultFn != nil {
+		return sdm.GetCVEScanResultFn(digest)
+	}
+
+	return mTypes.CVEScanResult{}, zerr.ErrCVEScanResultNotFound
+}
+
+func (sdm MetaDBMock) DeleteCVEScanResult(digest godigest.Digest) error {
+	if sdm.DeleteCVEScanResultFn != nil {
+		return sdm.DeleteCVEScanResultFn(digest)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) DeleteCVEScanResults(isStale func(result mTypes.CVEScanResult) bool) error {
+	if sdm.DeleteCVEScanResultsFn != nil {
+		return sdm.DeleteCVEScanResultsFn(isStale)
+	}
+
+	return nil
+}
+
 func (sdm MetaDBMock) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
 	sigMeta mTypes.SignatureMetadata,
 ) error {
@@ -20,3 +20,19 @@ func SkipDynamo(t *testing.T) {
 		t.Skip("Skipping testing without AWS DynamoDB mock server")
 	}
 }
+
+func SkipGCS(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("GCSMOCK_ENDPOINT") == "" {
+		t.Skip("Skipping testing without GCS mock server")
+	}
+}
+
+func SkipAzure(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("AZURITE_ENDPOINT") == "" {
+		t.Skip("Skipping testing without Azurite server")
+	}
+}
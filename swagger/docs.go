@@ -87,7 +87,7 @@ const docTemplate = `{
         },
         "/v2/_zot/ext/cosign": {
             "post": {
-                "description": "Upload cosign public keys for verifying signatures",
+                "description": "Upload cosign public keys, or Fulcio certificates and Rekor public keys for keyless signatures",
                 "consumes": [
                     "application/octet-stream"
                 ],
@@ -97,7 +97,13 @@ const docTemplate = `{
                 "summary": "Upload cosign public keys for verifying signatures",
                 "parameters": [
                     {
-                        "description": "Public key content",
+                        "type": "string",
+                        "description": "truststore type: fulcio or rekor, public key if not set",
+                        "name": "truststoreType",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Public key or certificate content",
                         "name": "requestBody",
                         "in": "body",
                         "required": true,
@@ -141,12 +147,20 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "enum": [
-                            "config"
+                            "config",
+                            "quota",
+                            "retention"
                         ],
                         "type": "string",
                         "description": "specify resource",
                         "name": "resource",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "repositories to preview retention for, defaults to **",
+                        "name": "repository",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -295,9 +309,33 @@ const docTemplate = `{
                         "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "digest of the blob to mount",
+                        "name": "mount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "repository to mount the blob from",
+                        "name": "from",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/v2/{name}/blobs/{digest}"
+                            }
+                        }
+                    },
                     "202": {
                         "description": "accepted",
                         "schema": {
@@ -320,6 +358,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -958,22 +1002,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/zot/auth/apikey": {
+        "/zot/admin/events/deadletters": {
             "get": {
-                "description": "Get list of all API keys for a logged in user",
-                "consumes": [
+                "description": "List the events which could not be delivered to a sink after all the attempts, oldest first.",
+                "produces": [
                     "application/json"
                 ],
+                "summary": "List dead-lettered events",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/extensions.DeadLetterList"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/events/deadletters/replay": {
+            "post": {
+                "description": "Schedule all the dead-lettered events for delivery, with their attempts count reset.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get list of API keys for the current user",
+                "summary": "Replay all dead-lettered events",
                 "responses": {
-                    "200": {
-                        "description": "ok",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/extensions.ReplayedDeadLetters"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/zot/admin/events/deadletters/{id}/replay": {
+            "post": {
+                "description": "Schedule a dead-lettered event for delivery, with its attempts count reset.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Replay a dead-lettered event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "dead letter id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/extensions.ReplayedDeadLetters"
+                        }
                     },
                     "401": {
                         "description": "unauthorized",
@@ -981,6 +1101,24 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -988,32 +1126,61 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/zot/admin/jobs": {
+            "get": {
+                "description": "List the queued, running and recently finished jobs.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.JobList"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
             },
             "post": {
-                "description": "Can create an api key for a logged in user, based on the provided label and scopes.",
+                "description": "Enqueue a gc, scrub or dedupe job for a repository, or for all the repositories if none is given.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Create an API key for the current user",
+                "summary": "Run gc, scrub or dedupe on demand",
                 "parameters": [
                     {
-                        "description": "api token id (UUID)",
-                        "name": "id",
+                        "description": "job type, repository and priority",
+                        "name": "job",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.APIKeyPayload"
+                            "$ref": "#/definitions/api.JobPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.Job"
                         }
                     },
                     "400": {
@@ -1028,44 +1195,84 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "internal server error",
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "service unavailable",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Revokes one current user API key based on given key ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/zot/admin/jobs/{id}": {
+            "get": {
+                "description": "Get the status and the results of a gc, scrub or dedupe job.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Revokes one current user API key",
+                "summary": "Get the status of a job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "api token id (UUID)",
+                        "description": "job id",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Job"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "bad request",
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/zot/admin/policies": {
+            "get": {
+                "description": "Get the current version of the access control policies stored in MetaDB.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the access control policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AccessControlPoliciesInfo"
+                        }
                     },
                     "401": {
                         "description": "unauthorized",
@@ -1073,6 +1280,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -1080,50 +1293,661 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/zot/auth/logout": {
-            "post": {
-                "description": "Logout by removing current session",
+            },
+            "put": {
+                "description": "Store a new version of the access control policies and apply it.\nThe other instances sharing MetaDB apply it when they reload the policies.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Logout by removing current session",
+                "summary": "Update the access control policies",
+                "parameters": [
+                    {
+                        "description": "repositories, adminPolicy and groups policies",
+                        "name": "policies",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AccessControlPoliciesPayload"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "ok\".",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AccessControlPoliciesInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "internal server error\".",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "api.APIKeyPayload": {
-            "type": "object",
-            "properties": {
-                "expirationDate": {
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/policies/history": {
+            "get": {
+                "description": "Get all the versions of the access control policies stored in MetaDB, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the history of the access control policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AccessControlPoliciesHistory"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/policies/{version}/rollback": {
+            "post": {
+                "description": "Store a copy of a previous version of the access control policies as a new version and apply it.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Roll back the access control policies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "version to roll back to",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.AccessControlPoliciesInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/robots": {
+            "get": {
+                "description": "List the robot accounts, their scope and the expiration date of their secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List robot accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RobotAccountList"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a robot account allowed the given actions on the given repositories.\nThe secret is only returned once, it's used as password along with the robot$\u003cname\u003e username.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create a robot account",
+                "parameters": [
+                    {
+                        "description": "robot account name, description and scope",
+                        "name": "robot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RobotAccountPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.RobotAccountInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/robots/{robot}": {
+            "delete": {
+                "description": "Delete a robot account, its secret and the tokens issued to it can't be used anymore.",
+                "summary": "Delete a robot account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "robot account name",
+                        "name": "robot",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/admin/robots/{robot}/rotate": {
+            "post": {
+                "description": "Replace the secret of a robot account, the previous secret can't be used anymore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Rotate the secret of a robot account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "robot account name",
+                        "name": "robot",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "expiration date of the new secret",
+                        "name": "secret",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.RobotSecretPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RobotAccountInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/auth/apikey": {
+            "get": {
+                "description": "Get list of all API keys for a logged in user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get list of API keys for the current user",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Can create an api key for a logged in user, based on the provided label and scopes.\nThe key can be restricted to some repositories and actions, otherwise it has all the permissions\nof the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create an API key for the current user",
+                "parameters": [
+                    {
+                        "description": "api token id (UUID)",
+                        "name": "id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.APIKeyPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revokes one current user API key based on given key ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Revokes one current user API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "api token id (UUID)",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/auth/logout": {
+            "post": {
+                "description": "Logout by removing current session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Logout by removing current session",
+                "responses": {
+                    "200": {
+                        "description": "ok\".",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error\".",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/zot/auth/token": {
+            "get": {
+                "description": "Issues a token granting the requested scopes which the access control policies allow to the\nauthenticated user, as specified by the distribution token authentication specification.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a bearer token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "service the token is requested for",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "requested scope, eg. repository:name:pull,push",
+                        "name": "scope",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIKeyPayload": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expirationDate": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "repositories": {
+                    "description": "restrict the key to some repositories (glob patterns) and actions (read, create, update, delete),\nthe key has all the permissions of its owner if both are empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.AccessControlPolicies": {
+            "type": "object",
+            "properties": {
+                "adminPolicy": {
+                    "$ref": "#/definitions/config.Policy"
+                },
+                "groups": {
+                    "$ref": "#/definitions/config.Groups"
+                },
+                "repositories": {
+                    "$ref": "#/definitions/config.Repositories"
+                }
+            }
+        },
+        "api.AccessControlPoliciesHistory": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "description": "oldest version first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AccessControlPoliciesInfo"
+                    }
+                }
+            }
+        },
+        "api.AccessControlPoliciesInfo": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "label": {
+                "createdBy": {
                     "type": "string"
                 },
-                "scopes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "policies": {
+                    "$ref": "#/definitions/api.AccessControlPolicies"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.AccessControlPoliciesPayload": {
+            "type": "object",
+            "properties": {
+                "baseVersion": {
+                    "description": "if set, the policies are only updated if it's still the current version, protecting from concurrent updates",
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "policies": {
+                    "$ref": "#/definitions/api.AccessControlPolicies"
                 }
             }
         },
@@ -1224,6 +2048,84 @@ const docTemplate = `{
                 }
             }
         },
+        "api.Job": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "digests": {
+                    "description": "number of digests processed by dedupe jobs",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "description": "unset until the job finishes",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "repositories": {
+                    "description": "repositories processed by gc and scrub jobs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "repository": {
+                    "type": "string"
+                },
+                "scrubResults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.ScrubImageResult"
+                    }
+                },
+                "startedAt": {
+                    "description": "unset until the job starts",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.JobList": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.Job"
+                    }
+                }
+            }
+        },
+        "api.JobPayload": {
+            "type": "object",
+            "properties": {
+                "priority": {
+                    "description": "low (default), medium or high",
+                    "type": "string"
+                },
+                "repository": {
+                    "description": "if empty the job runs on all the repositories of all the image stores",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "api.RepositoryList": {
             "type": "object",
             "properties": {
@@ -1235,6 +2137,118 @@ const docTemplate = `{
                 }
             }
         },
+        "api.RobotAccountInfo": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "isExpired": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "repositories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "only returned when the robot account is created or its secret is rotated",
+                    "type": "string"
+                },
+                "secretCreatedAt": {
+                    "type": "string"
+                },
+                "secretExpirationDate": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "the username used to authenticate, eg. robot$name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.RobotAccountList": {
+            "type": "object",
+            "properties": {
+                "robots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.RobotAccountInfo"
+                    }
+                }
+            }
+        },
+        "api.RobotAccountPayload": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "subset of read, create, update and delete",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expirationDate": {
+                    "description": "if empty the secret never expires",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "repositories": {
+                    "description": "glob patterns of the repositories the robot account can act on",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.RobotSecretPayload": {
+            "type": "object",
+            "properties": {
+                "expirationDate": {
+                    "description": "if empty the new secret never expires",
+                    "type": "string"
+                }
+            }
+        },
+        "api.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "common.ImageTags": {
             "type": "object",
             "properties": {
@@ -1249,6 +2263,108 @@ const docTemplate = `{
                 }
             }
         },
+        "config.Group": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.Groups": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/config.Group"
+            }
+        },
+        "config.Policy": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.PolicyGroup": {
+            "type": "object",
+            "properties": {
+                "anonymousPolicy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "defaultPolicy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.Policy"
+                    }
+                }
+            }
+        },
+        "config.Repositories": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/config.PolicyGroup"
+            }
+        },
+        "events.OutboxEntry": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deadLettered": {
+                    "type": "boolean"
+                },
+                "deadLetteredAt": {
+                    "description": "when the event was dead-lettered, it's removed from the outbox once the retention expired",
+                    "type": "string"
+                },
+                "event": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "nextAttempt": {
+                    "type": "string"
+                },
+                "sink": {
+                    "type": "string"
+                }
+            }
+        },
         "extensions.Auth": {
             "type": "object",
             "properties": {
@@ -1285,6 +2401,17 @@ const docTemplate = `{
                 }
             }
         },
+        "extensions.DeadLetterList": {
+            "type": "object",
+            "properties": {
+                "deadLetters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.OutboxEntry"
+                    }
+                }
+            }
+        },
         "extensions.Extension": {
             "type": "object",
             "properties": {
@@ -1332,6 +2459,14 @@ const docTemplate = `{
                 }
             }
         },
+        "extensions.ReplayedDeadLetters": {
+            "type": "object",
+            "properties": {
+                "replayed": {
+                    "type": "integer"
+                }
+            }
+        },
         "extensions.StrippedConfig": {
             "type": "object",
             "properties": {
@@ -1357,6 +2492,26 @@ const docTemplate = `{
                 }
             }
         },
+        "storage.ScrubImageResult": {
+            "type": "object",
+            "properties": {
+                "affectedBlob": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "imageName": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.Descriptor": {
             "type": "object",
             "properties": {